@@ -10,8 +10,10 @@ import (
 	"net/http"
 	_ "net/http/pprof"
 	"os"
+	"path/filepath"
 	"runtime"
 	"sync"
+	"time"
 
 	"github.com/btcsuite/btcwallet/chain"
 	"github.com/btcsuite/btcwallet/rpc/legacyrpc"
@@ -22,6 +24,10 @@ import (
 // 	//cfg *config
 // )
 
+// chainClientRetryDelay is the delay between failed attempts to connect to the
+// chain backend.
+const chainClientRetryDelay = 5 * time.Second
+
 func xxmain() {
 	// Use all processor cores.
 	runtime.GOMAXPROCS(runtime.NumCPU())
@@ -67,6 +73,7 @@ func walletMain() error {
 
 	dbDir := networkDir(cfg.AppDataDir.Value, activeNet.Params)
 	loader := wallet.NewLoader(activeNet.Params, dbDir)
+	loader.SetPassphraseOptions(passphraseOptions(cfg))
 
 	// Create and start HTTP server to serve wallet client connections.
 	// This will be updated with the wallet and chain server RPC client
@@ -78,16 +85,29 @@ func walletMain() error {
 	}
 
 	// Create and start chain RPC client so it's ready to connect to
-	// the wallet when loaded later.
-	if !cfg.NoInitialLoad {
+	// the wallets when loaded later.
+	if !cfg.NoInitialLoad || len(cfg.Wallets) != 0 {
 		go rpcClientConnectLoop(legacyRPCServer, loader)
 	}
 
 	loader.RunAfterLoad(func(w *wallet.Wallet) {
-		startWalletRPCServices(w, rpcs, legacyRPCServer)
+		w.SetFeePolicy(wallet.FeePolicy{
+			ConfTarget:  cfg.FeeConfTarget,
+			MinFeePerKb: cfg.MinFeeRate.Amount,
+			MaxFeePerKb: cfg.MaxFeeRate.Amount,
+		})
+		w.SetRecoveryWindow(cfg.RecoveryWindow)
 	})
 
-	if !cfg.NoInitialLoad {
+	if !cfg.NoInitialLoad && cfg.MemDB {
+		// Create the in-memory simulation wallet, which is lost when
+		// the process exits.
+		_, err = loadMemDBSimulationWallet(loader)
+		if err != nil {
+			log.Errorf("Unable to create wallet: %v", err)
+			return err
+		}
+	} else if !cfg.NoInitialLoad {
 		// Load the wallet database.  It must have been created already
 		// or this will return an appropriate error.
 		_, err = loader.OpenExistingWallet([]byte(cfg.WalletPass), true)
@@ -96,14 +116,22 @@ func walletMain() error {
 			return err
 		}
 	}
+	for _, name := range cfg.Wallets {
+		_, err = loader.OpenNamedWallet(name, []byte(cfg.WalletPass), true)
+		if err != nil {
+			log.Errorf("Unable to load wallet %q: %v", name, err)
+			loader.UnloadAllWallets()
+			return err
+		}
+	}
 
 	// Add interrupt handlers to shutdown the various process components
-	// before exiting.  Interrupt handlers run in LIFO order, so the wallet
-	// (which should be closed last) is added first.
+	// before exiting.  Interrupt handlers run in LIFO order, so the wallets
+	// (which should be closed last) are added first.
 	addInterruptHandler(func() {
-		err := loader.UnloadWallet()
-		if err != nil && err != wallet.ErrNotLoaded {
-			log.Errorf("Failed to close wallet: %v", err)
+		err := loader.UnloadAllWallets()
+		if err != nil {
+			log.Errorf("Failed to close wallets: %v", err)
 		}
 	})
 	if rpcs != nil {
@@ -132,22 +160,31 @@ func walletMain() error {
 	return nil
 }
 
-// rpcClientConnectLoop continuously attempts a connection to the consensus RPC
-// server.  When a connection is established, the client is used to sync the
-// loaded wallet, either immediately or when loaded at a later time.
+// rpcClientConnectLoop continuously attempts a connection to the configured
+// chain backend.  When a connection is established, the client is shared by
+// all loaded wallets, each syncing with its own client of a ClientMux, either
+// immediately or when loaded at a later time.
 //
-// The legacy RPC is optional.  If set, the connected RPC client will be
-// associated with the server for RPC passthrough and to enable additional
-// methods.
+// The legacy RPC is optional.  If set, the connected client will be associated
+// with the server to enable additional methods, and for RPC passthrough when
+// the backend is a consensus RPC server.
 func rpcClientConnectLoop(legacyRPCServer *legacyrpc.Server, loader *wallet.Loader) {
-	certs := readCAFile()
+	var certs []byte
+	if cfg.ChainBackend == "btcd" {
+		certs = readCAFile()
+	}
 
 	for {
-		chainClient, err := startChainRPC(certs)
+		chainClient, err := startChainClient(certs)
 		if err != nil {
-			log.Errorf("Unable to open connection to consensus RPC server: %v", err)
+			log.Errorf("Unable to open connection to chain backend: %v", err)
+			time.Sleep(chainClientRetryDelay)
 			continue
 		}
+		if legacyRPCServer != nil {
+			legacyRPCServer.SetChainServer(chainClient)
+		}
+		clientMux := chain.NewClientMux(chainClient)
 
 		// Rather than inlining this logic directly into the loader
 		// callback, a function variable is used to avoid running any of
@@ -156,9 +193,10 @@ func rpcClientConnectLoop(legacyRPCServer *legacyrpc.Server, loader *wallet.Load
 		// later time with a client that has already disconnected.  A
 		// mutex is used to make this concurrent safe.
 		associateRPCClient := func(w *wallet.Wallet) {
-			w.SynchronizeRPC(chainClient)
-			if legacyRPCServer != nil {
-				legacyRPCServer.SetChainServer(chainClient)
+			err := w.SynchronizeMux(clientMux)
+			if err != nil {
+				log.Errorf("Unable to sync wallet with chain "+
+					"backend: %v", err)
 			}
 		}
 		mu := new(sync.Mutex)
@@ -172,26 +210,26 @@ func rpcClientConnectLoop(legacyRPCServer *legacyrpc.Server, loader *wallet.Load
 		})
 
 		chainClient.WaitForShutdown()
+		clientMux.WaitForShutdown()
 
 		mu.Lock()
 		associateRPCClient = nil
 		mu.Unlock()
 
-		loadedWallet, ok := loader.LoadedWallet()
-		if ok {
+		for _, w := range loader.LoadedWallets() {
 			// Do not attempt a reconnect when the wallet was
 			// explicitly stopped.
-			if loadedWallet.ShuttingDown() {
+			if w.ShuttingDown() {
 				return
 			}
 
-			loadedWallet.SetChainSynced(false)
+			w.SetChainSynced(false)
 
 			// TODO: Rework the wallet so changing the RPC client
 			// does not require stopping and restarting everything.
-			loadedWallet.Stop()
-			loadedWallet.WaitForShutdown()
-			loadedWallet.Start()
+			w.Stop()
+			w.WaitForShutdown()
+			w.Start()
 		}
 	}
 }
@@ -215,6 +253,47 @@ func readCAFile() []byte {
 	return certs
 }
 
+// startChainClient opens a connection to the chain backend selected by the
+// global config.
+func startChainClient(certs []byte) (chain.Interface, error) {
+	var chainClient chain.Interface
+	switch cfg.ChainBackend {
+	case "bitcoind":
+		log.Infof("Attempting bitcoind RPC client connection to %v",
+			cfg.RPCConnect)
+		c, err := chain.NewBitcoindClient(activeNet.Params,
+			cfg.RPCConnect, cfg.BtcdUsername, cfg.BtcdPassword,
+			cfg.ZMQPubRawBlock, cfg.ZMQPubRawTx)
+		if err != nil {
+			return nil, err
+		}
+		chainClient = c
+	case "neutrino":
+		log.Infof("Attempting light client connection to %v",
+			cfg.NeutrinoConnect)
+		dataDir := filepath.Join(networkDir(cfg.AppDataDir.Value,
+			activeNet.Params), "neutrino")
+		c, err := chain.NewNeutrinoClient(activeNet.Params,
+			cfg.NeutrinoConnect, dataDir)
+		if err != nil {
+			return nil, err
+		}
+		chainClient = c
+	default:
+		rpcc, err := startChainRPC(certs)
+		if err != nil {
+			return nil, err
+		}
+		return rpcc, nil
+	}
+	err := chainClient.Start()
+	if err != nil {
+		chainClient.Stop()
+		return nil, err
+	}
+	return chainClient, nil
+}
+
 // startChainRPC opens a RPC client connection to a btcd server for blockchain
 // services.  This function uses the RPC options from the global config and
 // there is no recovery in case the server is not available or if there is an
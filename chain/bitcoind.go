@@ -0,0 +1,586 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chain
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	btcrpcclient "github.com/btcsuite/btcd/rpcclient"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+)
+
+// bitcoindDialTimeout is the timeout for connecting to bitcoind's ZMQ
+// publishers.
+const bitcoindDialTimeout = 10 * time.Second
+
+// bitcoindReconnectDelay is the delay between attempts to reconnect to a ZMQ
+// publisher after the connection is lost.
+const bitcoindReconnectDelay = 5 * time.Second
+
+// bitcoindRescanBatch is the number of blocks rescanned between each
+// RescanProgress notification.
+const bitcoindRescanBatch = 1000
+
+// The ZMQ topics of bitcoind's notifications.
+const (
+	zmqTopicRawBlock = "rawblock"
+	zmqTopicRawTx    = "rawtx"
+)
+
+// BitcoindClient is a chain backend for Bitcoin Core.  Chain data is queried
+// with bitcoind's JSON-RPC API over HTTP, and new blocks and transactions are
+// received from the rawblock and rawtx ZMQ notifications published by
+// bitcoind.  Since bitcoind does not match transactions against the
+// addresses of the wallet, the client matches the transactions of every block
+// and rescans the blockchain by fetching blocks.
+type BitcoindClient struct {
+	client       *btcrpcclient.Client
+	chainParams  *chaincfg.Params
+	zmqBlockAddr string
+	zmqTxAddr    string
+
+	// watched holds the scripts and outpoints watched for relevant
+	// transactions.
+	watched *watchList
+
+	notifyBlocksMtx sync.Mutex
+	notifyBlocks    bool
+
+	// syncMtx serializes the processing of new blocks and rescans, and
+	// protects the best block.
+	syncMtx      sync.Mutex
+	best         wtxmgr.BlockMeta
+	newBlocks    chan *wire.MsgBlock
+	syncRequests chan struct{}
+
+	zmqMtx  sync.Mutex
+	zmqSubs []*zmqSubscriber
+
+	enqueueNotification chan interface{}
+	dequeueNotification chan interface{}
+
+	quit    chan struct{}
+	wg      sync.WaitGroup
+	started bool
+	quitMtx sync.Mutex
+}
+
+// NewBitcoindClient creates a client for the bitcoind JSON-RPC server at the
+// host:port address connect, authenticated by user and pass.  zmqBlockAddr
+// and zmqTxAddr are the endpoints bitcoind publishes rawblock and rawtx
+// notifications to, as configured with its zmqpubrawblock and zmqpubrawtx
+// options, and may be the same endpoint.  The connections are not established
+// immediately, but must be done using the Start method.
+func NewBitcoindClient(chainParams *chaincfg.Params, connect, user, pass,
+	zmqBlockAddr, zmqTxAddr string) (*BitcoindClient, error) {
+
+	client, err := btcrpcclient.New(&btcrpcclient.ConnConfig{
+		Host:         connect,
+		User:         user,
+		Pass:         pass,
+		HTTPPostMode: true,
+		DisableTLS:   true,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &BitcoindClient{
+		client:              client,
+		chainParams:         chainParams,
+		zmqBlockAddr:        zmqBlockAddr,
+		zmqTxAddr:           zmqTxAddr,
+		watched:             newWatchList(),
+		newBlocks:           make(chan *wire.MsgBlock),
+		syncRequests:        make(chan struct{}, 1),
+		enqueueNotification: make(chan interface{}),
+		dequeueNotification: make(chan interface{}),
+		quit:                make(chan struct{}),
+	}, nil
+}
+
+// Start checks that bitcoind runs on the client's network, subscribes to the
+// ZMQ notifications, and starts the handlers which deliver notifications for
+// new blocks and transactions.
+func (c *BitcoindClient) Start() error {
+	genesisHash, err := c.client.GetBlockHash(0)
+	if err != nil {
+		return err
+	}
+	if *genesisHash != *c.chainParams.GenesisHash {
+		return errors.New("mismatched networks")
+	}
+	hash, height, err := c.GetBestBlock()
+	if err != nil {
+		return err
+	}
+	header, err := c.client.GetBlockHeader(hash)
+	if err != nil {
+		return err
+	}
+	c.best = wtxmgr.BlockMeta{
+		Block: wtxmgr.Block{Hash: *hash, Height: height},
+		Time:  header.Timestamp,
+	}
+
+	// Subscribe to both topics with a single connection when bitcoind
+	// publishes them to the same endpoint.
+	subscriptions := map[string][]string{
+		c.zmqBlockAddr: {zmqTopicRawBlock},
+	}
+	subscriptions[c.zmqTxAddr] = append(subscriptions[c.zmqTxAddr],
+		zmqTopicRawTx)
+	subs := make(map[string]*zmqSubscriber, len(subscriptions))
+	for addr, topics := range subscriptions {
+		sub, err := dialZMQ(addr, topics, bitcoindDialTimeout)
+		if err != nil {
+			for _, sub := range subs {
+				sub.close()
+			}
+			return err
+		}
+		subs[addr] = sub
+	}
+
+	c.quitMtx.Lock()
+	c.started = true
+	c.quitMtx.Unlock()
+
+	c.wg.Add(2 + len(subs))
+	go func() {
+		queueNotifications(c.enqueueNotification,
+			c.dequeueNotification, c.quit)
+		c.wg.Done()
+	}()
+	go c.blockHandler()
+	for addr, sub := range subs {
+		go c.zmqHandler(addr, subscriptions[addr], sub)
+	}
+
+	c.enqueue(ClientConnected{})
+	return nil
+}
+
+// Stop disconnects from bitcoind and signals the shutdown of all goroutines
+// started by Start.
+func (c *BitcoindClient) Stop() {
+	c.quitMtx.Lock()
+	select {
+	case <-c.quit:
+	default:
+		close(c.quit)
+		c.client.Shutdown()
+
+		c.zmqMtx.Lock()
+		for _, sub := range c.zmqSubs {
+			sub.close()
+		}
+		c.zmqMtx.Unlock()
+
+		if !c.started {
+			close(c.dequeueNotification)
+		}
+	}
+	c.quitMtx.Unlock()
+}
+
+// WaitForShutdown blocks until the client has disconnected and all goroutines
+// started by Start have exited.
+func (c *BitcoindClient) WaitForShutdown() {
+	c.client.WaitForShutdown()
+	c.wg.Wait()
+}
+
+// Notifications returns a channel of notifications for the blocks and
+// transactions found by the client.  This channel must be continually read
+// or the process may abort for running out memory, as unread notifications
+// are queued for later reads.
+func (c *BitcoindClient) Notifications() <-chan interface{} {
+	return c.dequeueNotification
+}
+
+// enqueue queues a notification to be read from the notifications channel.
+func (c *BitcoindClient) enqueue(n interface{}) {
+	select {
+	case c.enqueueNotification <- n:
+	case <-c.quit:
+	}
+}
+
+// GetBestBlock returns the hash and height of bitcoind's best block.
+func (c *BitcoindClient) GetBestBlock() (*chainhash.Hash, int32, error) {
+	height, err := c.client.GetBlockCount()
+	if err != nil {
+		return nil, 0, err
+	}
+	hash, err := c.client.GetBlockHash(height)
+	if err != nil {
+		return nil, 0, err
+	}
+	return hash, int32(height), nil
+}
+
+// GetBlock returns the block with the hash.
+func (c *BitcoindClient) GetBlock(hash *chainhash.Hash) (*wire.MsgBlock, error) {
+	return c.client.GetBlock(hash)
+}
+
+// GetBlockHash returns the hash of the main chain block at a height.
+func (c *BitcoindClient) GetBlockHash(height int64) (*chainhash.Hash, error) {
+	return c.client.GetBlockHash(height)
+}
+
+// GetBlockHeader returns the header of the block with the hash.
+func (c *BitcoindClient) GetBlockHeader(hash *chainhash.Hash) (*wire.BlockHeader, error) {
+	return c.client.GetBlockHeader(hash)
+}
+
+// GetBlockHeight returns the height of the block with the hash.
+func (c *BitcoindClient) GetBlockHeight(hash *chainhash.Hash) (int32, error) {
+	header, err := c.client.GetBlockHeaderVerbose(hash)
+	if err != nil {
+		return 0, err
+	}
+	return header.Height, nil
+}
+
+// BlockStamp returns the latest block notified by the client, or an error if
+// the client has been shut down.
+func (c *BitcoindClient) BlockStamp() (*waddrmgr.BlockStamp, error) {
+	select {
+	case <-c.quit:
+		return nil, errors.New("disconnected")
+	default:
+	}
+	c.syncMtx.Lock()
+	bs := &waddrmgr.BlockStamp{Hash: c.best.Hash, Height: c.best.Height}
+	c.syncMtx.Unlock()
+	return bs, nil
+}
+
+// SendRawTransaction submits a transaction to bitcoind to be relayed to the
+// network.
+func (c *BitcoindClient) SendRawTransaction(tx *wire.MsgTx,
+	allowHighFees bool) (*chainhash.Hash, error) {
+
+	return c.client.SendRawTransaction(tx, allowHighFees)
+}
+
+// EstimateFeePerKb returns the fee rate, per kB of serialized transaction
+// size, estimated by bitcoind for a transaction to confirm within confTarget
+// blocks.
+func (c *BitcoindClient) EstimateFeePerKb(confTarget uint32) (btcutil.Amount, error) {
+	return estimateFeePerKb(c.client, confTarget)
+}
+
+// NotifyReceived requests RelevantTx notifications for transactions paying
+// to addrs.
+func (c *BitcoindClient) NotifyReceived(addrs []btcutil.Address) error {
+	return c.watched.add(addrs, nil)
+}
+
+// NotifyBlocks requests BlockConnected and BlockDisconnected notifications
+// for changes to the main chain.
+func (c *BitcoindClient) NotifyBlocks() error {
+	c.notifyBlocksMtx.Lock()
+	c.notifyBlocks = true
+	c.notifyBlocksMtx.Unlock()
+	return nil
+}
+
+func (c *BitcoindClient) notifyingBlocks() bool {
+	c.notifyBlocksMtx.Lock()
+	notify := c.notifyBlocks
+	c.notifyBlocksMtx.Unlock()
+	return notify
+}
+
+// Rescan fetches the main chain blocks from the block startHash through the
+// best block notified by the client, queueing RelevantTx notifications for
+// the transactions paying to addrs or spending outPoints, and for
+// transactions spending outputs found by the rescan.  RescanProgress
+// notifications are sent after each batch of blocks is rescanned, followed by
+// a RescanFinished notification.
+func (c *BitcoindClient) Rescan(startHash *chainhash.Hash, addrs []btcutil.Address,
+	outPoints []*wire.OutPoint) error {
+
+	header, err := c.client.GetBlockHeaderVerbose(startHash)
+	if err != nil {
+		return err
+	}
+	err = c.watched.add(addrs, outPoints)
+	if err != nil {
+		return err
+	}
+
+	// New blocks are not processed during the rescan, so the rescan ends
+	// at the best block, after which blocks are matched against the
+	// rescanned addresses and outpoints as they are processed.
+	c.syncMtx.Lock()
+	defer c.syncMtx.Unlock()
+
+	var last *wtxmgr.BlockMeta
+	for height := header.Height; height <= c.best.Height; height++ {
+		hash, err := c.client.GetBlockHash(int64(height))
+		if err != nil {
+			return err
+		}
+		block, err := c.client.GetBlock(hash)
+		if err != nil {
+			return err
+		}
+		last = &wtxmgr.BlockMeta{
+			Block: wtxmgr.Block{Hash: *hash, Height: height},
+			Time:  block.Header.Timestamp,
+		}
+		c.notifyRelevantTxs(block, last)
+
+		if height == c.best.Height ||
+			(height-header.Height+1)%bitcoindRescanBatch == 0 {
+			c.enqueue(&RescanProgress{&last.Hash, last.Height,
+				last.Time})
+		}
+	}
+	if last == nil {
+		last = &c.best
+	}
+	c.enqueue(&RescanFinished{&last.Hash, last.Height, last.Time})
+	return nil
+}
+
+// notifyRelevantTxs queues RelevantTx notifications for the relevant
+// transactions of a block.
+func (c *BitcoindClient) notifyRelevantTxs(block *wire.MsgBlock, meta *wtxmgr.BlockMeta) {
+	for _, tx := range block.Transactions {
+		if c.watched.relevant(tx) {
+			c.notifyRelevantTx(tx, meta)
+		}
+	}
+}
+
+// notifyRelevantTx queues a RelevantTx notification for a transaction, which
+// is mined in block, or unmined if block is nil.
+func (c *BitcoindClient) notifyRelevantTx(tx *wire.MsgTx, block *wtxmgr.BlockMeta) {
+	rec, err := wtxmgr.NewTxRecordFromMsgTx(tx, time.Now())
+	if err != nil {
+		log.Errorf("Cannot create transaction record for relevant "+
+			"tx: %v", err)
+		return
+	}
+	c.enqueue(RelevantTx{rec, block})
+}
+
+// zmqHandler receives the notifications published to a ZMQ endpoint,
+// reconnecting to the endpoint when the connection is lost.
+func (c *BitcoindClient) zmqHandler(addr string, topics []string, sub *zmqSubscriber) {
+	defer c.wg.Done()
+
+	for {
+		c.zmqMtx.Lock()
+		c.zmqSubs = append(c.zmqSubs, sub)
+		c.zmqMtx.Unlock()
+
+		// Closing the subscriber when the client is stopped is
+		// racy with adding it above, so check again.
+		select {
+		case <-c.quit:
+			sub.close()
+			return
+		default:
+		}
+
+		err := c.receiveZMQ(sub)
+		sub.close()
+		c.zmqMtx.Lock()
+		for i, s := range c.zmqSubs {
+			if s == sub {
+				c.zmqSubs = append(c.zmqSubs[:i], c.zmqSubs[i+1:]...)
+				break
+			}
+		}
+		c.zmqMtx.Unlock()
+
+		select {
+		case <-c.quit:
+			return
+		default:
+		}
+		log.Warnf("Lost connection to ZMQ publisher %s: %v", addr, err)
+
+		for {
+			select {
+			case <-time.After(bitcoindReconnectDelay):
+			case <-c.quit:
+				return
+			}
+			sub, err = dialZMQ(addr, topics, bitcoindDialTimeout)
+			if err == nil {
+				break
+			}
+			log.Warnf("Unable to reconnect to ZMQ publisher %s: %v",
+				addr, err)
+		}
+		log.Infof("Reconnected to ZMQ publisher %s", addr)
+
+		// Blocks published while disconnected were missed.
+		c.requestSync()
+	}
+}
+
+// receiveZMQ handles the messages received by a subscriber until the
+// connection fails.
+func (c *BitcoindClient) receiveZMQ(sub *zmqSubscriber) error {
+	for {
+		msg, err := sub.receive()
+		if err != nil {
+			return err
+		}
+		if len(msg) < 2 {
+			continue
+		}
+
+		switch string(msg[0]) {
+		case zmqTopicRawBlock:
+			var block wire.MsgBlock
+			err := block.Deserialize(bytes.NewReader(msg[1]))
+			if err != nil {
+				log.Errorf("Unable to deserialize block: %v",
+					err)
+				continue
+			}
+			select {
+			case c.newBlocks <- &block:
+			case <-c.quit:
+				return nil
+			}
+
+		case zmqTopicRawTx:
+			var tx wire.MsgTx
+			err := tx.Deserialize(bytes.NewReader(msg[1]))
+			if err != nil {
+				log.Errorf("Unable to deserialize transaction: "+
+					"%v", err)
+				continue
+			}
+			if c.watched.relevant(&tx) {
+				c.notifyRelevantTx(&tx, nil)
+			}
+		}
+	}
+}
+
+// requestSync signals the block handler to catch up with bitcoind's best
+// block.
+func (c *BitcoindClient) requestSync() {
+	select {
+	case c.syncRequests <- struct{}{}:
+	default:
+	}
+}
+
+// blockHandler processes new blocks.  Blocks extending the client's best
+// block are connected directly, while other blocks cause the client to sync
+// with bitcoind's main chain, handling reorganizations and missed blocks.
+func (c *BitcoindClient) blockHandler() {
+	defer c.wg.Done()
+
+	for {
+		var block *wire.MsgBlock
+		select {
+		case block = <-c.newBlocks:
+		case <-c.syncRequests:
+		case <-c.quit:
+			return
+		}
+
+		c.syncMtx.Lock()
+		var err error
+		if block != nil && block.Header.PrevBlock == c.best.Hash {
+			c.connectBlock(block, c.best.Height+1)
+		} else {
+			err = c.syncBlocks()
+		}
+		c.syncMtx.Unlock()
+		if err != nil {
+			log.Errorf("Unable to sync blocks with bitcoind: %v", err)
+		}
+	}
+}
+
+// connectBlock makes a block at a height the best block, queueing
+// notifications for its relevant transactions and the connected block.  It
+// must be called with syncMtx held.
+func (c *BitcoindClient) connectBlock(block *wire.MsgBlock, height int32) {
+	meta := wtxmgr.BlockMeta{
+		Block: wtxmgr.Block{Hash: block.BlockHash(), Height: height},
+		Time:  block.Header.Timestamp,
+	}
+	c.notifyRelevantTxs(block, &meta)
+	c.best = meta
+	if c.notifyingBlocks() {
+		c.enqueue(BlockConnected(meta))
+	}
+}
+
+// syncBlocks disconnects the blocks notified by the client which are no
+// longer in bitcoind's main chain, and connects the main chain blocks after
+// them.  It must be called with syncMtx held.
+func (c *BitcoindClient) syncBlocks() error {
+	_, bestHeight, err := c.GetBestBlock()
+	if err != nil {
+		return err
+	}
+
+	for c.best.Height > 0 {
+		if c.best.Height <= bestHeight {
+			hash, err := c.client.GetBlockHash(int64(c.best.Height))
+			if err != nil {
+				return err
+			}
+			if *hash == c.best.Hash {
+				break
+			}
+		}
+
+		header, err := c.client.GetBlockHeader(&c.best.Hash)
+		if err != nil {
+			return err
+		}
+		prevHeader, err := c.client.GetBlockHeader(&header.PrevBlock)
+		if err != nil {
+			return err
+		}
+		if c.notifyingBlocks() {
+			c.enqueue(BlockDisconnected(c.best))
+		}
+		c.best = wtxmgr.BlockMeta{
+			Block: wtxmgr.Block{
+				Hash:   header.PrevBlock,
+				Height: c.best.Height - 1,
+			},
+			Time: prevHeader.Timestamp,
+		}
+	}
+
+	for height := c.best.Height + 1; height <= bestHeight; height++ {
+		hash, err := c.client.GetBlockHash(int64(height))
+		if err != nil {
+			return err
+		}
+		block, err := c.client.GetBlock(hash)
+		if err != nil {
+			return err
+		}
+		c.connectBlock(block, height)
+	}
+	return nil
+}
@@ -0,0 +1,404 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chain
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+)
+
+// testBitcoind serves the JSON-RPC methods used by BitcoindClient for a
+// fixture chain, and publishes ZMQ notifications for its blocks and
+// transactions.
+type testBitcoind struct {
+	t      *testing.T
+	params *chaincfg.Params
+	server *httptest.Server
+
+	mtx     sync.Mutex
+	chain   []*wire.MsgBlock
+	blocks  map[chainhash.Hash]*wire.MsgBlock
+	heights map[chainhash.Hash]int32
+
+	zmqListener net.Listener
+	zmqConn     net.Conn
+	subscribed  chan struct{}
+}
+
+func newTestBitcoind(t *testing.T, params *chaincfg.Params) *testBitcoind {
+	zmqListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tb := &testBitcoind{
+		t:           t,
+		params:      params,
+		blocks:      make(map[chainhash.Hash]*wire.MsgBlock),
+		heights:     make(map[chainhash.Hash]int32),
+		zmqListener: zmqListener,
+		subscribed:  make(chan struct{}),
+	}
+	tb.connectBlock(params.GenesisBlock)
+	tb.server = httptest.NewServer(http.HandlerFunc(tb.serveRPC))
+	go tb.serveZMQ()
+	return tb
+}
+
+func (tb *testBitcoind) close() {
+	tb.server.Close()
+	tb.zmqListener.Close()
+	tb.mtx.Lock()
+	if tb.zmqConn != nil {
+		tb.zmqConn.Close()
+	}
+	tb.mtx.Unlock()
+}
+
+// connectBlock adds a block to the main chain.
+func (tb *testBitcoind) connectBlock(block *wire.MsgBlock) {
+	tb.mtx.Lock()
+	defer tb.mtx.Unlock()
+	hash := block.BlockHash()
+	tb.blocks[hash] = block
+	tb.heights[hash] = int32(len(tb.chain))
+	tb.chain = append(tb.chain, block)
+}
+
+// disconnectBlocks removes the main chain blocks after a height.  The
+// disconnected blocks can still be queried by hash.
+func (tb *testBitcoind) disconnectBlocks(height int32) {
+	tb.mtx.Lock()
+	tb.chain = tb.chain[:height+1]
+	tb.mtx.Unlock()
+}
+
+// mineBlock creates a block extending prev with a coinbase paying to
+// payScript, followed by txs, and adds it to the main chain.
+func (tb *testBitcoind) mineBlock(prev *wire.MsgBlock, payScript []byte,
+	txs ...*wire.MsgTx) *wire.MsgBlock {
+
+	tb.mtx.Lock()
+	height := tb.heights[prev.BlockHash()] + 1
+	tb.mtx.Unlock()
+
+	block := newTestBlock(tb.t, tb.params, prev, height, payScript, txs...)
+	tb.connectBlock(block)
+	return block
+}
+
+// serveRPC responds to a JSON-RPC request.
+func (tb *testBitcoind) serveRPC(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+		ID     interface{}       `json:"id"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	result, rpcErr := tb.handle(req.Method, req.Params)
+	resp := struct {
+		Result interface{}       `json:"result"`
+		Error  *btcjson.RPCError `json:"error"`
+		ID     interface{}       `json:"id"`
+	}{result, rpcErr, req.ID}
+	json.NewEncoder(w).Encode(&resp)
+}
+
+func (tb *testBitcoind) handle(method string, params []json.RawMessage) (interface{}, *btcjson.RPCError) {
+	tb.mtx.Lock()
+	defer tb.mtx.Unlock()
+
+	blockParam := func() (*wire.MsgBlock, *btcjson.RPCError) {
+		var hashStr string
+		if len(params) == 0 || json.Unmarshal(params[0], &hashStr) != nil {
+			return nil, btcjson.NewRPCError(btcjson.ErrRPCInvalidParameter,
+				"invalid block hash")
+		}
+		hash, err := chainhash.NewHashFromStr(hashStr)
+		if err != nil {
+			return nil, btcjson.NewRPCError(btcjson.ErrRPCInvalidParameter,
+				err.Error())
+		}
+		block, ok := tb.blocks[*hash]
+		if !ok {
+			return nil, btcjson.NewRPCError(btcjson.ErrRPCBlockNotFound,
+				"block not found")
+		}
+		return block, nil
+	}
+	verbose := func() bool {
+		var v bool
+		if len(params) > 1 {
+			json.Unmarshal(params[1], &v)
+		}
+		return v
+	}
+
+	switch method {
+	case "getblockcount":
+		return len(tb.chain) - 1, nil
+
+	case "getblockhash":
+		var height int
+		if len(params) == 0 || json.Unmarshal(params[0], &height) != nil ||
+			height < 0 || height >= len(tb.chain) {
+			return nil, btcjson.NewRPCError(btcjson.ErrRPCOutOfRange,
+				"block height out of range")
+		}
+		return tb.chain[height].BlockHash().String(), nil
+
+	case "getblock":
+		block, rpcErr := blockParam()
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+		var buf bytes.Buffer
+		block.Serialize(&buf)
+		return hex.EncodeToString(buf.Bytes()), nil
+
+	case "getblockheader":
+		block, rpcErr := blockParam()
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+		if verbose() {
+			return &btcjson.GetBlockHeaderVerboseResult{
+				Hash:   block.BlockHash().String(),
+				Height: tb.heights[block.BlockHash()],
+			}, nil
+		}
+		var buf bytes.Buffer
+		block.Header.Serialize(&buf)
+		return hex.EncodeToString(buf.Bytes()), nil
+	}
+
+	return nil, btcjson.NewRPCError(btcjson.ErrRPCMethodNotFound.Code,
+		"Method not found")
+}
+
+// serveZMQ accepts a single subscriber, performs the ZMTP handshake and reads
+// its two subscriptions.
+func (tb *testBitcoind) serveZMQ() {
+	conn, err := tb.zmqListener.Accept()
+	if err != nil {
+		return
+	}
+	tb.mtx.Lock()
+	tb.zmqConn = conn
+	tb.mtx.Unlock()
+
+	r := bufio.NewReader(conn)
+	greeting := make([]byte, zmqGreetingLen)
+	_, err = io.ReadFull(r, greeting)
+	if err != nil {
+		tb.t.Errorf("read greeting: %v", err)
+		return
+	}
+	if err := checkZMQGreeting(greeting); err != nil {
+		tb.t.Errorf("subscriber greeting: %v", err)
+		return
+	}
+	conn.Write(zmqGreeting(true))
+
+	flags, body, err := readZMQFrame(r)
+	if err != nil || flags&zmqFlagCommand == 0 ||
+		!bytes.Equal(body, zmqReadyCommand("SUB")) {
+		tb.t.Errorf("expected READY command, got %x (%v)", body, err)
+		return
+	}
+	writeZMQFrame(conn, zmqFlagCommand, zmqReadyCommand("PUB"))
+
+	var topics []string
+	for len(topics) < 2 {
+		_, body, err := readZMQFrame(r)
+		if err != nil || len(body) == 0 || body[0] != 1 {
+			tb.t.Errorf("expected subscription, got %x (%v)", body,
+				err)
+			return
+		}
+		topics = append(topics, string(body[1:]))
+	}
+	if strings.Join(topics, ",") != "rawblock,rawtx" &&
+		strings.Join(topics, ",") != "rawtx,rawblock" {
+		tb.t.Errorf("unexpected subscriptions %v", topics)
+		return
+	}
+	close(tb.subscribed)
+}
+
+// publish publishes a notification, with the sequence number frame sent by
+// bitcoind.
+func (tb *testBitcoind) publish(topic string, msg interface {
+	Serialize(io.Writer) error
+}, seq uint32) {
+
+	<-tb.subscribed
+	var body bytes.Buffer
+	msg.Serialize(&body)
+	var seqBytes [4]byte
+	binary.LittleEndian.PutUint32(seqBytes[:], seq)
+
+	tb.mtx.Lock()
+	defer tb.mtx.Unlock()
+	writeZMQFrame(tb.zmqConn, zmqFlagMore, []byte(topic))
+	writeZMQFrame(tb.zmqConn, zmqFlagMore, body.Bytes())
+	writeZMQFrame(tb.zmqConn, 0, seqBytes[:])
+}
+
+func TestBitcoindClient(t *testing.T) {
+	params := &chaincfg.RegressionNetParams
+	addr, err := btcutil.NewAddressPubKeyHash(make([]byte, 20), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addrScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherScript := []byte{txscript.OP_TRUE}
+
+	tb := newTestBitcoind(t, params)
+	defer tb.close()
+
+	// Mine a chain with a coinbase paying to the watched address in block
+	// 3, which is spent in block 6.
+	block := params.GenesisBlock
+	blocks := []*wire.MsgBlock{block}
+	var spend *wire.MsgTx
+	for height := 1; height <= 10; height++ {
+		var txs []*wire.MsgTx
+		payScript := otherScript
+		switch height {
+		case 3:
+			payScript = addrScript
+		case 6:
+			spend = wire.NewMsgTx(wire.TxVersion)
+			spend.AddTxIn(wire.NewTxIn(&wire.OutPoint{
+				Hash: blocks[3].Transactions[0].TxHash(),
+			}, nil, nil))
+			spend.AddTxOut(wire.NewTxOut(49e8, otherScript))
+			txs = append(txs, spend)
+		}
+		block = tb.mineBlock(block, payScript, txs...)
+		blocks = append(blocks, block)
+	}
+
+	zmqAddr := "tcp://" + tb.zmqListener.Addr().String()
+	c, err := NewBitcoindClient(params,
+		strings.TrimPrefix(tb.server.URL, "http://"), "user", "pass",
+		zmqAddr, zmqAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.Start()
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer func() {
+		c.Stop()
+		c.WaitForShutdown()
+	}()
+	if _, ok := nextNotification(t, c).(ClientConnected); !ok {
+		t.Fatal("expected ClientConnected notification")
+	}
+
+	hash, height, err := c.GetBestBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *hash != blocks[10].BlockHash() || height != 10 {
+		t.Fatalf("best block %v (height %d), want %v (height 10)",
+			hash, height, blocks[10].BlockHash())
+	}
+
+	// Rescan the chain, finding the coinbase paying to the address and
+	// the transaction spending it.
+	err = c.NotifyBlocks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesisHash := params.GenesisBlock.BlockHash()
+	err = c.Rescan(&genesisHash, []btcutil.Address{addr}, nil)
+	if err != nil {
+		t.Fatalf("Rescan: %v", err)
+	}
+	checkRelevantTx(t, nextNotification(t, c),
+		blocks[3].Transactions[0].TxHash(), blocks[3], 3)
+	checkRelevantTx(t, nextNotification(t, c), spend.TxHash(), blocks[6], 6)
+	progress, ok := nextNotification(t, c).(*RescanProgress)
+	if !ok || progress.Height != 10 {
+		t.Fatalf("expected RescanProgress notification at height 10, "+
+			"got %v", progress)
+	}
+	finished, ok := nextNotification(t, c).(*RescanFinished)
+	if !ok || *finished.Hash != blocks[10].BlockHash() {
+		t.Fatalf("expected RescanFinished notification for block %v, "+
+			"got %v", blocks[10].BlockHash(), finished)
+	}
+
+	// Publish an unmined transaction paying to the address.
+	unmined := wire.NewMsgTx(wire.TxVersion)
+	unmined.AddTxIn(wire.NewTxIn(&wire.OutPoint{
+		Hash: blocks[1].Transactions[0].TxHash(),
+	}, nil, nil))
+	unmined.AddTxOut(wire.NewTxOut(49e8, addrScript))
+	tb.publish(zmqTopicRawTx, unmined, 0)
+	n := nextNotification(t, c)
+	relevant, ok := n.(RelevantTx)
+	if !ok || relevant.TxRecord.Hash != unmined.TxHash() ||
+		relevant.Block != nil {
+		t.Fatalf("expected unmined RelevantTx notification for %v, "+
+			"got %v", unmined.TxHash(), n)
+	}
+
+	// Publish a new block paying to the address.
+	block11 := tb.mineBlock(blocks[10], addrScript)
+	tb.publish(zmqTopicRawBlock, block11, 0)
+	checkRelevantTx(t, nextNotification(t, c),
+		block11.Transactions[0].TxHash(), block11, 11)
+	checkBlockNotification(t, nextNotification(t, c), true, block11, 11)
+
+	// Reorganize the new block out of the chain, replacing it with two
+	// blocks, the second of which pays to the address.  Only the second
+	// block is published, so the client must fetch the first.
+	tb.disconnectBlocks(10)
+	fork11 := tb.mineBlock(blocks[10], otherScript)
+	fork12 := tb.mineBlock(fork11, addrScript)
+	tb.publish(zmqTopicRawBlock, fork12, 1)
+	checkBlockNotification(t, nextNotification(t, c), false, block11, 11)
+	checkBlockNotification(t, nextNotification(t, c), true, fork11, 11)
+	checkRelevantTx(t, nextNotification(t, c),
+		fork12.Transactions[0].TxHash(), fork12, 12)
+	checkBlockNotification(t, nextNotification(t, c), true, fork12, 12)
+
+	bs, err := c.BlockStamp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bs.Hash != fork12.BlockHash() || bs.Height != 12 {
+		t.Fatalf("block stamp %v (height %d), want %v (height 12)",
+			bs.Hash, bs.Height, fork12.BlockHash())
+	}
+}
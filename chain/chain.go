@@ -5,7 +5,10 @@
 package chain
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -199,6 +202,71 @@ func (c *RPCClient) BlockStamp() (*waddrmgr.BlockStamp, error) {
 	}
 }
 
+// GetBlockHeight returns the height of the block with the hash.
+func (c *RPCClient) GetBlockHeight(hash *chainhash.Hash) (int32, error) {
+	header, err := c.GetBlockHeaderVerbose(hash)
+	if err != nil {
+		return 0, err
+	}
+	return header.Height, nil
+}
+
+// estimateSmartFeeResult models the data returned by the estimatesmartfee
+// JSON-RPC method.
+type estimateSmartFeeResult struct {
+	FeeRate *float64 `json:"feerate"`
+	Errors  []string `json:"errors"`
+	Blocks  int64    `json:"blocks"`
+}
+
+// EstimateFeePerKb returns the fee rate, per kB of serialized transaction
+// size, estimated by the chain server for a transaction to confirm within
+// confTarget blocks.  The estimatesmartfee method is preferred, and servers
+// which do not implement it are queried with estimatefee instead.  An error is
+// returned if the server does not have enough data to provide an estimate.
+func (c *RPCClient) EstimateFeePerKb(confTarget uint32) (btcutil.Amount, error) {
+	return estimateFeePerKb(c.Client, confTarget)
+}
+
+// estimateFeePerKb queries a btcd or bitcoind RPC server for a fee rate
+// estimate.  See RPCClient.EstimateFeePerKb for details.
+func estimateFeePerKb(c *btcrpcclient.Client, confTarget uint32) (btcutil.Amount, error) {
+	target, err := json.Marshal(confTarget)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := c.RawRequest("estimatesmartfee", []json.RawMessage{target})
+	if rpcErr, ok := err.(*btcjson.RPCError); ok &&
+		rpcErr.Code == btcjson.ErrRPCMethodNotFound.Code {
+
+		feeRate, err := c.EstimateFee(int64(confTarget))
+		if err != nil {
+			return 0, err
+		}
+		if feeRate <= 0 {
+			return 0, errors.New("no fee estimate available")
+		}
+		return btcutil.NewAmount(feeRate)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var result estimateSmartFeeResult
+	err = json.Unmarshal(resp, &result)
+	if err != nil {
+		return 0, err
+	}
+	if result.FeeRate == nil || *result.FeeRate <= 0 {
+		if len(result.Errors) != 0 {
+			return 0, fmt.Errorf("no fee estimate available: %s",
+				strings.Join(result.Errors, "; "))
+		}
+		return 0, errors.New("no fee estimate available")
+	}
+	return btcutil.NewAmount(*result.FeeRate)
+}
+
 // parseBlock parses a btcws definition of the block a tx is mined it to the
 // Block structure of the wtxmgr package, and the block index.  This is done
 // here since btcrpcclient doesn't parse this nicely for us.
@@ -0,0 +1,602 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+)
+
+// errWeakerFork describes headers which fork from the best chain, but do not
+// have more proof of work than the blocks they would replace.
+var errWeakerFork = errors.New("headers fork from the best chain with " +
+	"less proof of work")
+
+const (
+	// headersFileName and filterHashesFileName are the names of the files
+	// persisting the block headers and the filter hashes of a header chain.
+	headersFileName      = "headers.bin"
+	filterHashesFileName = "filterhashes.bin"
+
+	// headerRecordSize and filterHashRecordSize are the sizes of the records
+	// of the headers and filter hashes files.  The record of a block is at
+	// the offset of its height times the record size.
+	headerRecordSize     = wire.MaxBlockHeaderPayload
+	filterHashRecordSize = chainhash.HashSize
+)
+
+// headerChain is the chain of block headers followed by the light client,
+// indexed by height from the genesis block, and the compact filter headers of
+// its blocks.
+type headerChain struct {
+	params      *chaincfg.Params
+	checkpoints map[int32]*chainhash.Hash
+
+	// blocksPerRetarget is the number of blocks between difficulty
+	// retargets, and the retarget timespans, in seconds, limit the
+	// adjustment of the difficulty at each retarget.
+	blocksPerRetarget   int32
+	minRetargetTimespan int64
+	maxRetargetTimespan int64
+
+	mtx     sync.RWMutex
+	headers []wire.BlockHeader
+	hashes  []chainhash.Hash
+	heights map[chainhash.Hash]int32
+
+	// work is the cumulative proof of work of the chain through each
+	// block.
+	work []*big.Int
+
+	// filterHeaders and filterHashes hold the filter header and filter
+	// hash of the blocks whose filter headers have been downloaded, which
+	// may lag behind the block headers.
+	filterHeaders []chainhash.Hash
+	filterHashes  []chainhash.Hash
+
+	// headersFile and filterHashesFile persist the block headers and the
+	// filter hashes, from which the filter headers are computed, when the
+	// chain is opened from a directory.  They are nil for chains held
+	// only in memory.
+	headersFile      *os.File
+	filterHashesFile *os.File
+}
+
+// newHeaderChain returns a header chain holding only the genesis block of the
+// network.  The chain is held only in memory.
+func newHeaderChain(params *chaincfg.Params) *headerChain {
+	genesis := params.GenesisBlock.Header
+	hash := genesis.BlockHash()
+	checkpoints := make(map[int32]*chainhash.Hash, len(params.Checkpoints))
+	for _, checkpoint := range params.Checkpoints {
+		checkpoints[checkpoint.Height] = checkpoint.Hash
+	}
+	targetTimespan := int64(params.TargetTimespan / time.Second)
+	adjustmentFactor := params.RetargetAdjustmentFactor
+	return &headerChain{
+		params:      params,
+		checkpoints: checkpoints,
+		blocksPerRetarget: int32(params.TargetTimespan /
+			params.TargetTimePerBlock),
+		minRetargetTimespan: targetTimespan / adjustmentFactor,
+		maxRetargetTimespan: targetTimespan * adjustmentFactor,
+		headers:             []wire.BlockHeader{genesis},
+		hashes:              []chainhash.Hash{hash},
+		heights:             map[chainhash.Hash]int32{hash: 0},
+		work:                []*big.Int{blockchain.CalcWork(genesis.Bits)},
+	}
+}
+
+// openHeaderChain returns a header chain persisted in a directory, which is
+// created if it does not exist.  The headers and filter headers recorded in
+// the directory are validated again, and records which are incomplete, such
+// as those of writes interrupted by a crash, are discarded.  If the recorded
+// headers are invalid, for example because a checkpoint was added which they
+// do not match, the chain is reset to the genesis block.
+func openHeaderChain(params *chaincfg.Params, dir string) (*headerChain, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+	headersFile, err := os.OpenFile(filepath.Join(dir, headersFileName),
+		os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	filterHashesFile, err := os.OpenFile(filepath.Join(dir,
+		filterHashesFileName), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		headersFile.Close()
+		return nil, err
+	}
+
+	c := newHeaderChain(params)
+	err = c.load(headersFile, filterHashesFile)
+	if err != nil {
+		headersFile.Close()
+		filterHashesFile.Close()
+		return nil, err
+	}
+	c.headersFile = headersFile
+	c.filterHashesFile = filterHashesFile
+	return c, nil
+}
+
+// load connects the headers and filter hashes recorded in the files to the
+// chain, which must only hold the genesis block, and truncates the files to the
+// records which were connected.
+func (c *headerChain) load(headersFile, filterHashesFile *os.File) error {
+	serialized, err := ioutil.ReadAll(headersFile)
+	if err != nil {
+		return err
+	}
+	numHeaders := len(serialized) / headerRecordSize
+	headers := make([]*wire.BlockHeader, 0, numHeaders)
+	for i := 0; i < numHeaders; i++ {
+		record := serialized[i*headerRecordSize : (i+1)*headerRecordSize]
+		header := new(wire.BlockHeader)
+		err := header.Deserialize(bytes.NewReader(record))
+		if err != nil {
+			return err
+		}
+		headers = append(headers, header)
+	}
+	if len(headers) != 0 && headers[0].BlockHash() != c.hashes[0] {
+		return fmt.Errorf("headers file %s does not begin with the "+
+			"genesis block of network %s", headersFile.Name(),
+			c.params.Name)
+	}
+	if len(headers) > 1 {
+		_, err = c.connect(headers[1:])
+		if err != nil {
+			log.Warnf("Discarding recorded block headers: %v", err)
+		}
+	}
+
+	serialized, err = ioutil.ReadAll(filterHashesFile)
+	if err != nil {
+		return err
+	}
+	numFilterHashes := len(serialized) / filterHashRecordSize
+	if numFilterHashes > len(c.hashes) {
+		numFilterHashes = len(c.hashes)
+	}
+	filterHashes := make([]*chainhash.Hash, numFilterHashes)
+	for i := range filterHashes {
+		record := serialized[i*filterHashRecordSize : (i+1)*filterHashRecordSize]
+		filterHashes[i], err = chainhash.NewHash(record)
+		if err != nil {
+			return err
+		}
+	}
+	if numFilterHashes != 0 {
+		stopHash := c.hashes[numFilterHashes-1]
+		err = c.connectFilterHeaders(chainhash.Hash{}, stopHash,
+			filterHashes)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Discard the records which were not connected, and record the
+	// genesis block in a new headers file.
+	err = headersFile.Truncate(int64(len(c.hashes) * headerRecordSize))
+	if err != nil {
+		return err
+	}
+	if numHeaders == 0 {
+		err = c.writeHeaders(headersFile, 0, c.headers[:1])
+		if err != nil {
+			return err
+		}
+	}
+	return filterHashesFile.Truncate(int64(len(c.filterHashes) *
+		filterHashRecordSize))
+}
+
+// writeHeaders writes the records of headers to the headers file, beginning
+// with the record of the block at a height.
+func (c *headerChain) writeHeaders(f *os.File, height int32,
+	headers []wire.BlockHeader) error {
+
+	var buf bytes.Buffer
+	buf.Grow(len(headers) * headerRecordSize)
+	for i := range headers {
+		err := headers[i].Serialize(&buf)
+		if err != nil {
+			return err
+		}
+	}
+	_, err := f.WriteAt(buf.Bytes(), int64(height)*headerRecordSize)
+	return err
+}
+
+// close closes the files persisting the chain.  The chain must not be
+// modified after it is closed.
+func (c *headerChain) close() error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.headersFile == nil {
+		return nil
+	}
+	err := c.headersFile.Close()
+	ferr := c.filterHashesFile.Close()
+	c.headersFile = nil
+	c.filterHashesFile = nil
+	if err != nil {
+		return err
+	}
+	return ferr
+}
+
+// tip returns the hash and height of the last block of the chain.
+func (c *headerChain) tip() (chainhash.Hash, int32) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	height := len(c.hashes) - 1
+	return c.hashes[height], int32(height)
+}
+
+// blockHash returns the hash of the block at a height.
+func (c *headerChain) blockHash(height int32) (*chainhash.Hash, error) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	if height < 0 || int(height) >= len(c.hashes) {
+		return nil, fmt.Errorf("no block at height %d", height)
+	}
+	hash := c.hashes[height]
+	return &hash, nil
+}
+
+// header returns the header and height of the block with the hash.
+func (c *headerChain) header(hash *chainhash.Hash) (*wire.BlockHeader, int32, error) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	height, ok := c.heights[*hash]
+	if !ok {
+		return nil, 0, fmt.Errorf("block %v is not in the main chain",
+			hash)
+	}
+	header := c.headers[height]
+	return &header, height, nil
+}
+
+// blockMeta returns the hash, height and time of the block at a height.
+func (c *headerChain) blockMeta(height int32) (*wtxmgr.BlockMeta, error) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	if height < 0 || int(height) >= len(c.hashes) {
+		return nil, fmt.Errorf("no block at height %d", height)
+	}
+	return &wtxmgr.BlockMeta{
+		Block: wtxmgr.Block{Hash: c.hashes[height], Height: height},
+		Time:  c.headers[height].Timestamp,
+	}, nil
+}
+
+// locator returns a block locator for the tip of the chain, listing the hashes
+// of the last ten blocks, followed by blocks at exponentially increasing
+// distances, and ending with the genesis block.
+func (c *headerChain) locator() blockchain.BlockLocator {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	var locator blockchain.BlockLocator
+	step := 1
+	for height := len(c.hashes) - 1; height > 0; height -= step {
+		hash := c.hashes[height]
+		locator = append(locator, &hash)
+		if len(locator) > 10 {
+			step *= 2
+		}
+	}
+	genesis := c.hashes[0]
+	return append(locator, &genesis)
+}
+
+// requiredBits returns the difficulty bits required of the block at a height
+// with a timestamp, following the retarget rules of the network.  headerAt
+// returns the header of each previous block by its height.
+func (c *headerChain) requiredBits(height int32, timestamp time.Time,
+	headerAt func(int32) *wire.BlockHeader) uint32 {
+
+	params := c.params
+	last := headerAt(height - 1)
+
+	// The difficulty of the previous block is kept between retargets,
+	// except on networks which allow blocks of the minimum difficulty once
+	// too much time has passed since the previous block.
+	if height%c.blocksPerRetarget != 0 {
+		if !params.ReduceMinDifficulty {
+			return last.Bits
+		}
+		reductionTime := int64(params.MinDiffReductionTime / time.Second)
+		if timestamp.Unix() > last.Timestamp.Unix()+reductionTime {
+			return params.PowLimitBits
+		}
+
+		// The difficulty is that of the last block which was not
+		// allowed the minimum difficulty.
+		h := height - 1
+		for h%c.blocksPerRetarget != 0 &&
+			headerAt(h).Bits == params.PowLimitBits {
+			h--
+		}
+		return headerAt(h).Bits
+	}
+
+	// The adjustment of the target is limited by the retarget factor.
+	first := headerAt(height - c.blocksPerRetarget)
+	timespan := last.Timestamp.Unix() - first.Timestamp.Unix()
+	if timespan < c.minRetargetTimespan {
+		timespan = c.minRetargetTimespan
+	} else if timespan > c.maxRetargetTimespan {
+		timespan = c.maxRetargetTimespan
+	}
+	target := blockchain.CompactToBig(last.Bits)
+	target.Mul(target, big.NewInt(timespan))
+	target.Div(target, big.NewInt(int64(params.TargetTimespan/time.Second)))
+	if target.Cmp(params.PowLimit) > 0 {
+		target.Set(params.PowLimit)
+	}
+	return blockchain.BigToCompact(target)
+}
+
+// checkHeader checks that the header of the block at a height has the
+// difficulty required by the network's retarget rules and meets its proof of
+// work target, and that it matches the checkpoint at its height, if any.
+// headerAt returns the header of each previous block by its height.
+func (c *headerChain) checkHeader(header *wire.BlockHeader, height int32,
+	headerAt func(int32) *wire.BlockHeader) error {
+
+	hash := header.BlockHash()
+	if checkpoint, ok := c.checkpoints[height]; ok && *checkpoint != hash {
+		return fmt.Errorf("block %v at height %d does not match "+
+			"checkpoint %v", hash, height, checkpoint)
+	}
+	required := c.requiredBits(height, header.Timestamp, headerAt)
+	if header.Bits != required {
+		return fmt.Errorf("block %v has difficulty bits %08x, "+
+			"expected %08x", hash, header.Bits, required)
+	}
+	target := blockchain.CompactToBig(header.Bits)
+	if target.Sign() <= 0 || target.Cmp(c.params.PowLimit) > 0 {
+		return fmt.Errorf("block %v has an invalid target", hash)
+	}
+	if blockchain.HashToBig(&hash).Cmp(target) > 0 {
+		return fmt.Errorf("block %v does not meet its proof of work "+
+			"target", hash)
+	}
+	return nil
+}
+
+// lastCheckpointHeight returns the height of the last checkpoint at or below a
+// height, or -1 if there is none.
+func (c *headerChain) lastCheckpointHeight(height int32) int32 {
+	last := int32(-1)
+	for checkpointHeight := range c.checkpoints {
+		if checkpointHeight <= height && checkpointHeight > last {
+			last = checkpointHeight
+		}
+	}
+	return last
+}
+
+// connect adds headers, each of which must extend the previous one, to the
+// chain.  When the first header extends a block before the tip, the headers
+// replace the blocks after that block if they have more proof of work, and
+// errWeakerFork is returned otherwise.  Forks from before the last checkpoint
+// of the chain are rejected.  Headers already in the chain are skipped.  The
+// replaced blocks are returned, from the tip down.  The headers are recorded
+// before the chain is modified when the chain is persisted.
+func (c *headerChain) connect(headers []*wire.BlockHeader) ([]wtxmgr.BlockMeta, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	// Skip headers of blocks which are already in the chain, such as the
+	// blocks following the fork point of the peer's locator.
+	for len(headers) != 0 {
+		if _, ok := c.heights[headers[0].BlockHash()]; !ok {
+			break
+		}
+		headers = headers[1:]
+	}
+	if len(headers) == 0 {
+		return nil, nil
+	}
+
+	forkHeight, ok := c.heights[headers[0].PrevBlock]
+	if !ok {
+		return nil, fmt.Errorf("block %v does not connect to the chain",
+			headers[0].BlockHash())
+	}
+	tipHeight := int32(len(c.hashes) - 1)
+	if forkHeight < c.lastCheckpointHeight(tipHeight) {
+		return nil, fmt.Errorf("block %v forks from the chain before "+
+			"its last checkpoint", headers[0].BlockHash())
+	}
+
+	headerAt := func(height int32) *wire.BlockHeader {
+		if height <= forkHeight {
+			return &c.headers[height]
+		}
+		return headers[height-forkHeight-1]
+	}
+	work := new(big.Int).Set(c.work[forkHeight])
+	prev := c.hashes[forkHeight]
+	works := make([]*big.Int, len(headers))
+	for i, header := range headers {
+		if header.PrevBlock != prev {
+			return nil, fmt.Errorf("block %v does not connect to "+
+				"the previous header", header.BlockHash())
+		}
+		err := c.checkHeader(header, forkHeight+1+int32(i), headerAt)
+		if err != nil {
+			return nil, err
+		}
+		work = new(big.Int).Add(work, blockchain.CalcWork(header.Bits))
+		works[i] = work
+		prev = header.BlockHash()
+	}
+
+	if forkHeight != tipHeight && work.Cmp(c.work[tipHeight]) <= 0 {
+		return nil, errWeakerFork
+	}
+
+	if c.headersFile != nil {
+		err := c.record(forkHeight, headers)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var disconnected []wtxmgr.BlockMeta
+	if forkHeight != tipHeight {
+		for height := tipHeight; height > forkHeight; height-- {
+			hash := c.hashes[height]
+			disconnected = append(disconnected, wtxmgr.BlockMeta{
+				Block: wtxmgr.Block{Hash: hash, Height: height},
+				Time:  c.headers[height].Timestamp,
+			})
+			delete(c.heights, hash)
+		}
+		c.headers = c.headers[:forkHeight+1]
+		c.hashes = c.hashes[:forkHeight+1]
+		c.work = c.work[:forkHeight+1]
+		if len(c.filterHeaders) > int(forkHeight)+1 {
+			c.filterHeaders = c.filterHeaders[:forkHeight+1]
+			c.filterHashes = c.filterHashes[:forkHeight+1]
+		}
+	}
+
+	for i, header := range headers {
+		hash := header.BlockHash()
+		c.heights[hash] = int32(len(c.hashes))
+		c.headers = append(c.headers, *header)
+		c.hashes = append(c.hashes, hash)
+		c.work = append(c.work, works[i])
+	}
+	return disconnected, nil
+}
+
+// record writes the headers connected to the block at forkHeight to the headers
+// file, discarding the records of the blocks they replace and of the filter
+// hashes of those blocks.
+func (c *headerChain) record(forkHeight int32, headers []*wire.BlockHeader) error {
+	tipHeight := int32(len(c.hashes) - 1)
+	if forkHeight != tipHeight {
+		err := c.headersFile.Truncate(int64(forkHeight+1) *
+			headerRecordSize)
+		if err != nil {
+			return err
+		}
+		if len(c.filterHashes) > int(forkHeight)+1 {
+			err := c.filterHashesFile.Truncate(int64(forkHeight+1) *
+				filterHashRecordSize)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	values := make([]wire.BlockHeader, len(headers))
+	for i, header := range headers {
+		values[i] = *header
+	}
+	return c.writeHeaders(c.headersFile, forkHeight+1, values)
+}
+
+// filterHeaderCount returns the number of blocks, from the genesis block,
+// whose filter headers are known.
+func (c *headerChain) filterHeaderCount() int32 {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return int32(len(c.filterHeaders))
+}
+
+// connectFilterHeaders adds the filter headers committing to filterHashes,
+// which must be the hashes of the filters of the blocks following the last
+// block with a known filter header, ending with the block stopHash.
+// prevHeader must match the last known filter header.  The filter hashes are
+// recorded before the chain is modified when the chain is persisted.
+func (c *headerChain) connectFilterHeaders(prevHeader, stopHash chainhash.Hash,
+	filterHashes []*chainhash.Hash) error {
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	var lastHeader chainhash.Hash
+	if len(c.filterHeaders) != 0 {
+		lastHeader = c.filterHeaders[len(c.filterHeaders)-1]
+	}
+	if prevHeader != lastHeader {
+		return errors.New("filter headers do not connect to the " +
+			"previous filter header")
+	}
+	stopHeight := len(c.filterHeaders) + len(filterHashes) - 1
+	if stopHeight >= len(c.hashes) || c.hashes[stopHeight] != stopHash {
+		return fmt.Errorf("filter headers do not end with block %v",
+			stopHash)
+	}
+
+	if c.filterHashesFile != nil {
+		buf := make([]byte, 0, len(filterHashes)*filterHashRecordSize)
+		for _, filterHash := range filterHashes {
+			buf = append(buf, filterHash[:]...)
+		}
+		_, err := c.filterHashesFile.WriteAt(buf,
+			int64(len(c.filterHashes))*filterHashRecordSize)
+		if err != nil {
+			return err
+		}
+	}
+
+	header := lastHeader
+	for _, filterHash := range filterHashes {
+		var buf [2 * chainhash.HashSize]byte
+		copy(buf[:], filterHash[:])
+		copy(buf[chainhash.HashSize:], header[:])
+		header = chainhash.DoubleHashH(buf[:])
+		c.filterHeaders = append(c.filterHeaders, header)
+		c.filterHashes = append(c.filterHashes, *filterHash)
+	}
+	return nil
+}
+
+// checkFilter checks that the filter of the block at a height hashes to the
+// filter hash committed to by the block's filter header.
+func (c *headerChain) checkFilter(height int32, blockHash *chainhash.Hash,
+	filterData []byte) error {
+
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	if int(height) >= len(c.filterHashes) || c.hashes[height] != *blockHash {
+		return fmt.Errorf("no filter header for block %v", blockHash)
+	}
+	if chainhash.DoubleHashH(filterData) != c.filterHashes[height] {
+		return fmt.Errorf("filter for block %v does not match its "+
+			"filter header", blockHash)
+	}
+	return nil
+}
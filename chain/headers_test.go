@@ -0,0 +1,290 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chain
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// solveHeader chooses the nonce of a header to meet its proof of work target.
+func solveHeader(header *wire.BlockHeader) {
+	target := blockchain.CompactToBig(header.Bits)
+	for {
+		hash := header.BlockHash()
+		if blockchain.HashToBig(&hash).Cmp(target) <= 0 {
+			return
+		}
+		header.Nonce++
+	}
+}
+
+// newTestHeaders returns n headers extending prev, with blocks a minute apart
+// and the difficulty bits, solving the proof of work.  The merkle roots are
+// set to the branch number, so that branches from the same block differ.
+func newTestHeaders(prev *wire.BlockHeader, n int, bits uint32,
+	branch byte) []*wire.BlockHeader {
+
+	headers := make([]*wire.BlockHeader, n)
+	for i := range headers {
+		header := &wire.BlockHeader{
+			Version:    1,
+			PrevBlock:  prev.BlockHash(),
+			MerkleRoot: chainhash.Hash{branch},
+			Timestamp:  prev.Timestamp.Add(time.Minute),
+			Bits:       bits,
+		}
+		solveHeader(header)
+		headers[i] = header
+		prev = header
+	}
+	return headers
+}
+
+// TestHeaderChainRetarget ensures headers must have the difficulty of the
+// network's retarget rules.
+func TestHeaderChainRetarget(t *testing.T) {
+	// Retarget every ten blocks, without the minimum difficulty rules.
+	params := chaincfg.RegressionNetParams
+	params.ReduceMinDifficulty = false
+	params.TargetTimespan = 10 * params.TargetTimePerBlock
+	c := newHeaderChain(&params)
+
+	headers := newTestHeaders(&params.GenesisBlock.Header, 9,
+		params.PowLimitBits, 0)
+	_, err := c.connect(headers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := headers[len(headers)-1]
+
+	// Blocks were found much faster than targeted, so the target of the
+	// retarget block is reduced by the retarget adjustment factor.
+	target := new(big.Int).Div(params.PowLimit,
+		big.NewInt(params.RetargetAdjustmentFactor))
+	wantBits := blockchain.BigToCompact(target)
+	_, err = c.connect(newTestHeaders(last, 1, params.PowLimitBits, 0))
+	if err == nil {
+		t.Fatal("retarget block without the retargeted difficulty " +
+			"was connected")
+	}
+	_, err = c.connect(newTestHeaders(last, 1, wantBits, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, height := c.tip(); height != 10 {
+		t.Fatalf("tip height is %d, expected 10", height)
+	}
+
+	// Blocks between retargets keep the difficulty.
+	tipHash, _ := c.tip()
+	tip, _, err := c.header(&tipHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.connect(newTestHeaders(tip, 1, params.PowLimitBits, 0))
+	if err == nil {
+		t.Fatal("block changing the difficulty between retargets was " +
+			"connected")
+	}
+	_, err = c.connect(newTestHeaders(tip, 1, wantBits, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestHeaderChainMinDifficulty ensures blocks of the minimum difficulty are
+// only allowed on networks with the minimum difficulty rules once too much
+// time has passed since the previous block.
+func TestHeaderChainMinDifficulty(t *testing.T) {
+	params := chaincfg.RegressionNetParams
+	params.TargetTimespan = 10 * params.TargetTimePerBlock
+	c := newHeaderChain(&params)
+
+	headers := newTestHeaders(&params.GenesisBlock.Header, 9,
+		params.PowLimitBits, 0)
+	target := new(big.Int).Div(params.PowLimit,
+		big.NewInt(params.RetargetAdjustmentFactor))
+	bits := blockchain.BigToCompact(target)
+	headers = append(headers, newTestHeaders(headers[8], 1, bits, 0)...)
+	_, err := c.connect(headers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	late := &wire.BlockHeader{
+		Version:   1,
+		PrevBlock: headers[9].BlockHash(),
+		Timestamp: headers[9].Timestamp.Add(params.MinDiffReductionTime +
+			time.Minute),
+		Bits: params.PowLimitBits,
+	}
+	solveHeader(late)
+
+	// A minimum difficulty block in time is rejected, and one after the
+	// reduction time is connected.
+	_, err = c.connect(newTestHeaders(headers[9], 1, params.PowLimitBits, 0))
+	if err == nil {
+		t.Fatal("minimum difficulty block in time was connected")
+	}
+	_, err = c.connect([]*wire.BlockHeader{late})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The following block in time requires the difficulty of the last
+	// block without the minimum difficulty.
+	_, err = c.connect(newTestHeaders(late, 1, params.PowLimitBits, 0))
+	if err == nil {
+		t.Fatal("minimum difficulty block in time was connected")
+	}
+	_, err = c.connect(newTestHeaders(late, 1, bits, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestHeaderChainCheckpoints ensures headers must match the network's
+// checkpoints, and forks from before the last checkpoint are rejected.
+func TestHeaderChainCheckpoints(t *testing.T) {
+	params := chaincfg.RegressionNetParams
+	genesis := &params.GenesisBlock.Header
+	headers := newTestHeaders(genesis, 3, params.PowLimitBits, 0)
+	checkpointHash := headers[1].BlockHash()
+	params.Checkpoints = []chaincfg.Checkpoint{
+		{Height: 2, Hash: &checkpointHash},
+	}
+
+	c := newHeaderChain(&params)
+	_, err := c.connect(newTestHeaders(genesis, 3, params.PowLimitBits, 1))
+	if err == nil {
+		t.Fatal("headers not matching a checkpoint were connected")
+	}
+	_, err = c.connect(headers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A longer fork from the genesis block is rejected, but a fork after
+	// the checkpoint replaces the tip.
+	_, err = c.connect(newTestHeaders(genesis, 5, params.PowLimitBits, 1))
+	if err == nil {
+		t.Fatal("fork before the last checkpoint was connected")
+	}
+	fork := newTestHeaders(headers[1], 2, params.PowLimitBits, 1)
+	disconnected, err := c.connect(fork)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(disconnected) != 1 || disconnected[0].Hash != headers[2].BlockHash() {
+		t.Fatalf("unexpected disconnected blocks %v", disconnected)
+	}
+}
+
+// TestHeaderChainPersistence ensures the headers and filter headers of a
+// chain opened from a directory are recorded, including the blocks replaced
+// by reorgs, and incomplete records are discarded.
+func TestHeaderChainPersistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "headerchain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	params := &chaincfg.RegressionNetParams
+	c, err := openHeaderChain(params, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	headers := newTestHeaders(&params.GenesisBlock.Header, 5,
+		params.PowLimitBits, 0)
+	_, err = c.connect(headers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	filterHashes := make([]*chainhash.Hash, 5)
+	for i := range filterHashes {
+		filterHashes[i] = &chainhash.Hash{byte(i + 1)}
+	}
+	err = c.connectFilterHeaders(chainhash.Hash{}, headers[3].BlockHash(),
+		filterHashes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Replace the blocks after height 2, discarding the filter headers of
+	// the replaced blocks.
+	_, err = c.connect(newTestHeaders(headers[1], 4, params.PowLimitBits, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := c.filterHeaderCount(); n != 3 {
+		t.Fatalf("%d filter headers after reorg, expected 3", n)
+	}
+	err = c.close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Append incomplete records, as written by an interrupted write.
+	for _, name := range []string{headersFileName, filterHashesFileName} {
+		f, err := os.OpenFile(filepath.Join(dir, name),
+			os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = f.Write(make([]byte, 10))
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reopened, err := openHeaderChain(params, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.close()
+	if !reflect.DeepEqual(reopened.hashes, c.hashes) {
+		t.Errorf("reopened chain has blocks %v, expected %v",
+			reopened.hashes, c.hashes)
+	}
+	if !reflect.DeepEqual(reopened.filterHeaders, c.filterHeaders) {
+		t.Errorf("reopened chain has filter headers %v, expected %v",
+			reopened.filterHeaders, c.filterHeaders)
+	}
+	if !reflect.DeepEqual(reopened.work, c.work) {
+		t.Error("reopened chain has different work")
+	}
+
+	// Headers connected after reopening are recorded after the existing
+	// records.
+	tipHash, _ := reopened.tip()
+	tip, _, err := reopened.header(&tipHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = reopened.connect(newTestHeaders(tip, 1, params.PowLimitBits, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(filepath.Join(dir, headersFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := int64(len(reopened.hashes)) * headerRecordSize; fi.Size() != want {
+		t.Errorf("headers file has size %d, expected %d", fi.Size(), want)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chain
+
+import (
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+)
+
+// Interface describes a chain backend the wallet can be synchronized with,
+// such as a btcd RPC server or a light client following the chain over the
+// peer-to-peer network.  It contains the methods used by the wallet to sync
+// with the backend, rescan the blockchain and handle chain notifications.
+type Interface interface {
+	// Start connects to the backend and starts delivering notifications.
+	Start() error
+
+	// Stop disconnects from the backend and signals the shutdown of all
+	// goroutines started by Start.
+	Stop()
+
+	// WaitForShutdown blocks until the client has disconnected and all
+	// goroutines started by Start have exited.
+	WaitForShutdown()
+
+	// GetBestBlock returns the hash and height of the best block known by
+	// the backend.
+	GetBestBlock() (*chainhash.Hash, int32, error)
+
+	// GetBlock returns the block with the hash.
+	GetBlock(hash *chainhash.Hash) (*wire.MsgBlock, error)
+
+	// GetBlockHash returns the hash of the main chain block at a height.
+	GetBlockHash(height int64) (*chainhash.Hash, error)
+
+	// GetBlockHeader returns the header of the block with the hash.
+	GetBlockHeader(hash *chainhash.Hash) (*wire.BlockHeader, error)
+
+	// GetBlockHeight returns the height of the block with the hash.
+	GetBlockHeight(hash *chainhash.Hash) (int32, error)
+
+	// BlockStamp returns the latest block notified by the client.
+	BlockStamp() (*waddrmgr.BlockStamp, error)
+
+	// SendRawTransaction publishes a transaction to the network.
+	SendRawTransaction(tx *wire.MsgTx, allowHighFees bool) (*chainhash.Hash, error)
+
+	// Rescan searches the main chain, from the start block through the
+	// best block, for transactions paying to addrs or spending outpoints.
+	// RelevantTx notifications are sent for found transactions, and
+	// further transactions paying to found outputs or spending them are
+	// searched for as well.  Progress is reported by RescanProgress
+	// notifications, and a RescanFinished notification is sent when the
+	// rescan completes.  The addresses and outpoints remain watched for
+	// transactions after the rescan.
+	Rescan(startHash *chainhash.Hash, addrs []btcutil.Address,
+		outPoints []*wire.OutPoint) error
+
+	// NotifyReceived requests RelevantTx notifications for transactions
+	// paying to addrs.
+	NotifyReceived(addrs []btcutil.Address) error
+
+	// NotifyBlocks requests BlockConnected and BlockDisconnected
+	// notifications for changes to the main chain.
+	NotifyBlocks() error
+
+	// Notifications returns the channel of notifications sent by the
+	// client.  The channel is closed when the client is stopped.
+	Notifications() <-chan interface{}
+
+	// EstimateFeePerKb returns the fee rate, per kB of serialized
+	// transaction size, estimated for a transaction to confirm within
+	// confTarget blocks.
+	EstimateFeePerKb(confTarget uint32) (btcutil.Amount, error)
+}
+
+// The chain backends implemented by this package.
+var (
+	_ Interface = (*RPCClient)(nil)
+	_ Interface = (*NeutrinoClient)(nil)
+	_ Interface = (*BitcoindClient)(nil)
+	_ Interface = (*MockClient)(nil)
+)
@@ -0,0 +1,371 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chain
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+)
+
+// MockClient is an in-memory chain backend for testing code which syncs with
+// an Interface, such as the wallet, without running a chain server.  Tests
+// build its chain with ConnectBlock and DisconnectBlocks, and publish unmined
+// transactions with AddUnminedTx.  Once the client is started, these changes
+// are notified the same way as by the other backends, matching transactions
+// against the addresses and outpoints requested by NotifyReceived and Rescan.
+type MockClient struct {
+	chainParams *chaincfg.Params
+	watched     *watchList
+
+	mtx          sync.Mutex
+	chain        []*wire.MsgBlock
+	heights      map[chainhash.Hash]int32
+	blocks       map[chainhash.Hash]*wire.MsgBlock
+	notifyBlocks bool
+	sent         []*wire.MsgTx
+	feeRate      btcutil.Amount
+
+	enqueueNotification chan interface{}
+	dequeueNotification chan interface{}
+
+	quit    chan struct{}
+	wg      sync.WaitGroup
+	started bool
+	quitMtx sync.Mutex
+}
+
+// NewMockClient creates a mock client with a chain holding the genesis block
+// of the network.
+func NewMockClient(chainParams *chaincfg.Params) *MockClient {
+	genesisHash := chainParams.GenesisBlock.BlockHash()
+	return &MockClient{
+		chainParams: chainParams,
+		watched:     newWatchList(),
+		chain:       []*wire.MsgBlock{chainParams.GenesisBlock},
+		heights: map[chainhash.Hash]int32{
+			genesisHash: 0,
+		},
+		blocks: map[chainhash.Hash]*wire.MsgBlock{
+			genesisHash: chainParams.GenesisBlock,
+		},
+		enqueueNotification: make(chan interface{}),
+		dequeueNotification: make(chan interface{}),
+		quit:                make(chan struct{}),
+	}
+}
+
+// Start starts delivering notifications, beginning with ClientConnected.
+func (c *MockClient) Start() error {
+	c.quitMtx.Lock()
+	defer c.quitMtx.Unlock()
+	select {
+	case <-c.quit:
+		return errors.New("client stopped")
+	default:
+	}
+	if c.started {
+		return errors.New("client already started")
+	}
+	c.started = true
+
+	c.wg.Add(1)
+	go func() {
+		queueNotifications(c.enqueueNotification,
+			c.dequeueNotification, c.quit)
+		c.wg.Done()
+	}()
+	c.enqueue(ClientConnected{})
+	return nil
+}
+
+// Stop signals the shutdown of the notification queue.
+func (c *MockClient) Stop() {
+	c.quitMtx.Lock()
+	select {
+	case <-c.quit:
+	default:
+		close(c.quit)
+		if !c.started {
+			close(c.dequeueNotification)
+		}
+	}
+	c.quitMtx.Unlock()
+}
+
+// WaitForShutdown blocks until the notification queue has been shut down.
+func (c *MockClient) WaitForShutdown() {
+	c.wg.Wait()
+}
+
+// Notifications returns a channel of notifications for the blocks and
+// transactions added to the mock chain.
+func (c *MockClient) Notifications() <-chan interface{} {
+	return c.dequeueNotification
+}
+
+// enqueue queues a notification to be read from the notifications channel.
+func (c *MockClient) enqueue(n interface{}) {
+	select {
+	case c.enqueueNotification <- n:
+	case <-c.quit:
+	}
+}
+
+// notify queues a notification when the client has been started.
+// Notifications for changes made before Start are dropped.
+func (c *MockClient) notify(n interface{}) {
+	c.quitMtx.Lock()
+	started := c.started
+	c.quitMtx.Unlock()
+	if started {
+		c.enqueue(n)
+	}
+}
+
+// blockMeta returns the hash, height and time of a main chain block.  It
+// must be called with mtx held.
+func (c *MockClient) blockMeta(height int32) *wtxmgr.BlockMeta {
+	block := c.chain[height]
+	return &wtxmgr.BlockMeta{
+		Block: wtxmgr.Block{Hash: block.BlockHash(), Height: height},
+		Time:  block.Header.Timestamp,
+	}
+}
+
+// ConnectBlock adds a block extending the tip of the mock chain, notifying
+// its relevant transactions and the connected block.
+func (c *MockClient) ConnectBlock(block *wire.MsgBlock) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	tip := c.chain[len(c.chain)-1].BlockHash()
+	if block.Header.PrevBlock != tip {
+		return fmt.Errorf("block %v does not extend the tip %v",
+			block.BlockHash(), tip)
+	}
+	hash := block.BlockHash()
+	height := int32(len(c.chain))
+	c.chain = append(c.chain, block)
+	c.heights[hash] = height
+	c.blocks[hash] = block
+
+	meta := c.blockMeta(height)
+	c.notifyRelevantTxs(block, meta)
+	if c.notifyBlocks {
+		c.notify(BlockConnected(*meta))
+	}
+	return nil
+}
+
+// DisconnectBlocks removes the blocks after a height from the mock chain,
+// notifying each disconnected block from the tip down.  The disconnected
+// blocks can still be fetched by their hashes.
+func (c *MockClient) DisconnectBlocks(height int32) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if height < 0 || int(height) >= len(c.chain) {
+		return fmt.Errorf("no block at height %d", height)
+	}
+	for h := int32(len(c.chain)) - 1; h > height; h-- {
+		meta := c.blockMeta(h)
+		delete(c.heights, meta.Hash)
+		c.chain = c.chain[:h]
+		if c.notifyBlocks {
+			c.notify(BlockDisconnected(*meta))
+		}
+	}
+	return nil
+}
+
+// AddUnminedTx notifies an unmined transaction if it is relevant, as if it
+// had been accepted to the mempool of a chain server.
+func (c *MockClient) AddUnminedTx(tx *wire.MsgTx) {
+	if c.watched.relevant(tx) {
+		c.notifyRelevantTx(tx, nil)
+	}
+}
+
+// SentTransactions returns the transactions passed to SendRawTransaction.
+func (c *MockClient) SentTransactions() []*wire.MsgTx {
+	c.mtx.Lock()
+	sent := make([]*wire.MsgTx, len(c.sent))
+	copy(sent, c.sent)
+	c.mtx.Unlock()
+	return sent
+}
+
+// SetFeeRate sets the fee rate returned by EstimateFeePerKb.  A zero fee rate
+// causes estimates to fail, as when a server does not have enough data to
+// provide one.
+func (c *MockClient) SetFeeRate(feeRate btcutil.Amount) {
+	c.mtx.Lock()
+	c.feeRate = feeRate
+	c.mtx.Unlock()
+}
+
+// notifyRelevantTxs queues RelevantTx notifications for the relevant
+// transactions of a block.
+func (c *MockClient) notifyRelevantTxs(block *wire.MsgBlock, meta *wtxmgr.BlockMeta) {
+	for _, tx := range block.Transactions {
+		if c.watched.relevant(tx) {
+			c.notifyRelevantTx(tx, meta)
+		}
+	}
+}
+
+// notifyRelevantTx queues a RelevantTx notification for a transaction, which
+// is mined in block, or unmined if block is nil.
+func (c *MockClient) notifyRelevantTx(tx *wire.MsgTx, block *wtxmgr.BlockMeta) {
+	rec, err := wtxmgr.NewTxRecordFromMsgTx(tx, time.Now())
+	if err != nil {
+		log.Errorf("Cannot create transaction record for relevant "+
+			"tx: %v", err)
+		return
+	}
+	c.notify(RelevantTx{rec, block})
+}
+
+// GetBestBlock returns the hash and height of the tip of the mock chain.
+func (c *MockClient) GetBestBlock() (*chainhash.Hash, int32, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	meta := c.blockMeta(int32(len(c.chain)) - 1)
+	return &meta.Hash, meta.Height, nil
+}
+
+// GetBlock returns the block with the hash, which may have been disconnected
+// from the mock chain.
+func (c *MockClient) GetBlock(hash *chainhash.Hash) (*wire.MsgBlock, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	block, ok := c.blocks[*hash]
+	if !ok {
+		return nil, fmt.Errorf("block %v not found", hash)
+	}
+	return block, nil
+}
+
+// GetBlockHash returns the hash of the main chain block at a height.
+func (c *MockClient) GetBlockHash(height int64) (*chainhash.Hash, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if height < 0 || height >= int64(len(c.chain)) {
+		return nil, fmt.Errorf("no block at height %d", height)
+	}
+	hash := c.chain[height].BlockHash()
+	return &hash, nil
+}
+
+// GetBlockHeader returns the header of the block with the hash.
+func (c *MockClient) GetBlockHeader(hash *chainhash.Hash) (*wire.BlockHeader, error) {
+	block, err := c.GetBlock(hash)
+	if err != nil {
+		return nil, err
+	}
+	return &block.Header, nil
+}
+
+// GetBlockHeight returns the height of the main chain block with the hash.
+func (c *MockClient) GetBlockHeight(hash *chainhash.Hash) (int32, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	height, ok := c.heights[*hash]
+	if !ok {
+		return 0, fmt.Errorf("block %v is not in the main chain", hash)
+	}
+	return height, nil
+}
+
+// BlockStamp returns the tip of the mock chain, or an error if the client has
+// been stopped.
+func (c *MockClient) BlockStamp() (*waddrmgr.BlockStamp, error) {
+	select {
+	case <-c.quit:
+		return nil, errors.New("disconnected")
+	default:
+	}
+	hash, height, err := c.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+	return &waddrmgr.BlockStamp{Hash: *hash, Height: height}, nil
+}
+
+// SendRawTransaction records the transaction, which can be retrieved with
+// SentTransactions, and notifies it as an unmined transaction if relevant.
+func (c *MockClient) SendRawTransaction(tx *wire.MsgTx,
+	allowHighFees bool) (*chainhash.Hash, error) {
+
+	c.mtx.Lock()
+	c.sent = append(c.sent, tx)
+	c.mtx.Unlock()
+
+	c.AddUnminedTx(tx)
+	txHash := tx.TxHash()
+	return &txHash, nil
+}
+
+// EstimateFeePerKb returns the fee rate set by SetFeeRate.
+func (c *MockClient) EstimateFeePerKb(confTarget uint32) (btcutil.Amount, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.feeRate == 0 {
+		return 0, errors.New("no fee estimate available")
+	}
+	return c.feeRate, nil
+}
+
+// NotifyReceived requests RelevantTx notifications for transactions paying
+// to addrs.
+func (c *MockClient) NotifyReceived(addrs []btcutil.Address) error {
+	return c.watched.add(addrs, nil)
+}
+
+// NotifyBlocks requests BlockConnected and BlockDisconnected notifications
+// for changes to the mock chain.
+func (c *MockClient) NotifyBlocks() error {
+	c.mtx.Lock()
+	c.notifyBlocks = true
+	c.mtx.Unlock()
+	return nil
+}
+
+// Rescan queues RelevantTx notifications for the transactions of the main
+// chain blocks from the block startHash through the tip which pay to addrs,
+// spend outPoints, or spend outputs found by the rescan, followed by a
+// RescanProgress and a RescanFinished notification for the tip.
+func (c *MockClient) Rescan(startHash *chainhash.Hash, addrs []btcutil.Address,
+	outPoints []*wire.OutPoint) error {
+
+	err := c.watched.add(addrs, outPoints)
+	if err != nil {
+		return err
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	start, ok := c.heights[*startHash]
+	if !ok {
+		return fmt.Errorf("block %v is not in the main chain", startHash)
+	}
+	for height := start; int(height) < len(c.chain); height++ {
+		c.notifyRelevantTxs(c.chain[height], c.blockMeta(height))
+	}
+	tip := c.blockMeta(int32(len(c.chain)) - 1)
+	c.notify(&RescanProgress{&tip.Hash, tip.Height, tip.Time})
+	c.notify(&RescanFinished{&tip.Hash, tip.Height, tip.Time})
+	return nil
+}
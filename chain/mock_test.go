@@ -0,0 +1,129 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chain
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+)
+
+func TestMockClient(t *testing.T) {
+	params := &chaincfg.RegressionNetParams
+	addr, err := btcutil.NewAddressPubKeyHash(make([]byte, 20), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addrScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherScript := []byte{txscript.OP_TRUE}
+
+	c := NewMockClient(params)
+
+	// Build a chain before starting the client, paying to the address in
+	// block 2.  No notifications are sent for it.
+	blocks := []*wire.MsgBlock{params.GenesisBlock}
+	for height := int32(1); height <= 3; height++ {
+		payScript := otherScript
+		if height == 2 {
+			payScript = addrScript
+		}
+		block := newTestBlock(t, params, blocks[height-1], height,
+			payScript)
+		err := c.ConnectBlock(block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		blocks = append(blocks, block)
+	}
+
+	err = c.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		c.Stop()
+		c.WaitForShutdown()
+	}()
+	if _, ok := nextNotification(t, c).(ClientConnected); !ok {
+		t.Fatal("expected ClientConnected notification")
+	}
+
+	err = c.NotifyBlocks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesisHash := params.GenesisBlock.BlockHash()
+	err = c.Rescan(&genesisHash, []btcutil.Address{addr}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRelevantTx(t, nextNotification(t, c),
+		blocks[2].Transactions[0].TxHash(), blocks[2], 2)
+	if _, ok := nextNotification(t, c).(*RescanProgress); !ok {
+		t.Fatal("expected RescanProgress notification")
+	}
+	finished, ok := nextNotification(t, c).(*RescanFinished)
+	if !ok || finished.Height != 3 {
+		t.Fatalf("expected RescanFinished notification at height 3, "+
+			"got %v", finished)
+	}
+
+	// Sending a transaction spending the output found by the rescan
+	// notifies it as unmined.
+	spend := wire.NewMsgTx(wire.TxVersion)
+	spend.AddTxIn(wire.NewTxIn(&wire.OutPoint{
+		Hash: blocks[2].Transactions[0].TxHash(),
+	}, nil, nil))
+	spend.AddTxOut(wire.NewTxOut(49e8, otherScript))
+	_, err = c.SendRawTransaction(spend, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := nextNotification(t, c)
+	if relevant, ok := n.(RelevantTx); !ok ||
+		relevant.TxRecord.Hash != spend.TxHash() || relevant.Block != nil {
+		t.Fatalf("expected unmined RelevantTx notification, got %v", n)
+	}
+	if sent := c.SentTransactions(); len(sent) != 1 || sent[0] != spend {
+		t.Fatalf("sent transactions %v, want the spend", sent)
+	}
+
+	// Reorganize block 3 out of the chain, mining the spend in its
+	// replacement.
+	err = c.DisconnectBlocks(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fork3 := newTestBlock(t, params, blocks[2], 3, otherScript, spend)
+	err = c.ConnectBlock(fork3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBlockNotification(t, nextNotification(t, c), false, blocks[3], 3)
+	checkRelevantTx(t, nextNotification(t, c), spend.TxHash(), fork3, 3)
+	checkBlockNotification(t, nextNotification(t, c), true, fork3, 3)
+
+	hash := blocks[3].BlockHash()
+	if _, err := c.GetBlockHeight(&hash); err == nil {
+		t.Fatal("disconnected block is reported in the main chain")
+	}
+	if _, err := c.GetBlock(&hash); err != nil {
+		t.Fatalf("disconnected block cannot be fetched: %v", err)
+	}
+	bs, err := c.BlockStamp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bs.Hash != fork3.BlockHash() || bs.Height != 3 {
+		t.Fatalf("block stamp %v (height %d), want %v (height 3)",
+			bs.Hash, bs.Height, fork3.BlockHash())
+	}
+}
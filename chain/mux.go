@@ -0,0 +1,380 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chain
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+)
+
+// ClientMux shares a single chain backend between multiple wallets.  Each
+// wallet is synced with its own MuxClient, which receives the block
+// notifications of the backend, and the RelevantTx notifications for the
+// transactions paying to the addresses or spending the outpoints the client
+// watches.  Rescans are serialized, and the rescan progress notifications are
+// only delivered to the client which requested the rescan.  Transactions found
+// by a rescan are delivered to every client watching them, which may include
+// clients that already know of them.
+type ClientMux struct {
+	backend Interface
+
+	mtx        sync.Mutex
+	clients    map[*MuxClient]struct{}
+	connected  bool
+	rescanning *MuxClient
+
+	// rescanSlot is sent to before a rescan is started, and read from
+	// once it has finished, so that only one rescan is performed by the
+	// backend at a time.
+	rescanSlot chan struct{}
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewClientMux creates a multiplexer sharing a started chain backend.  The
+// notifications of the backend are delivered to the clients created by
+// NewClient until the backend is stopped, at which point all clients are
+// stopped as well.
+func NewClientMux(backend Interface) *ClientMux {
+	m := &ClientMux{
+		backend:    backend,
+		clients:    make(map[*MuxClient]struct{}),
+		rescanSlot: make(chan struct{}, 1),
+		quit:       make(chan struct{}),
+	}
+	m.wg.Add(1)
+	go m.notificationHandler()
+	return m
+}
+
+// Backend returns the chain backend shared by the multiplexer.
+func (m *ClientMux) Backend() Interface {
+	return m.backend
+}
+
+// NewClient creates a client of the shared backend.  The client must be
+// started before it delivers any notifications.
+func (m *ClientMux) NewClient() *MuxClient {
+	return &MuxClient{
+		mux:                 m,
+		watched:             newWatchList(),
+		enqueueNotification: make(chan interface{}),
+		dequeueNotification: make(chan interface{}),
+		quit:                make(chan struct{}),
+	}
+}
+
+// WaitForShutdown blocks until the backend has been stopped and all clients
+// have been stopped.
+func (m *ClientMux) WaitForShutdown() {
+	m.wg.Wait()
+}
+
+// notificationHandler delivers the notifications of the backend to the
+// clients until the backend's notification channel is closed.
+func (m *ClientMux) notificationHandler() {
+	for n := range m.backend.Notifications() {
+		switch n := n.(type) {
+		case ClientConnected:
+			// The clients started from now on are notified of the
+			// connection by Start.
+			m.mtx.Lock()
+			m.connected = true
+			clients := m.clientList()
+			m.mtx.Unlock()
+			for _, c := range clients {
+				c.enqueue(n)
+			}
+
+		case BlockConnected, BlockDisconnected:
+			m.broadcast(n, func(c *MuxClient) bool {
+				return c.notifyingBlocks()
+			})
+
+		case RelevantTx:
+			tx := &n.TxRecord.MsgTx
+			m.broadcast(n, func(c *MuxClient) bool {
+				return c.watched.relevant(tx)
+			})
+
+		case *RescanProgress:
+			m.mtx.Lock()
+			c := m.rescanning
+			m.mtx.Unlock()
+			if c != nil {
+				c.enqueue(n)
+			}
+
+		case *RescanFinished:
+			m.mtx.Lock()
+			c := m.rescanning
+			m.rescanning = nil
+			m.mtx.Unlock()
+			if c != nil {
+				c.enqueue(n)
+				<-m.rescanSlot
+			}
+		}
+	}
+
+	close(m.quit)
+	m.mtx.Lock()
+	clients := m.clientList()
+	m.mtx.Unlock()
+	for _, c := range clients {
+		c.Stop()
+		c.WaitForShutdown()
+	}
+	m.wg.Done()
+}
+
+// clientList returns the started clients.  It must be called with mtx held.
+func (m *ClientMux) clientList() []*MuxClient {
+	clients := make([]*MuxClient, 0, len(m.clients))
+	for c := range m.clients {
+		clients = append(clients, c)
+	}
+	return clients
+}
+
+// broadcast delivers a notification to every started client for which deliver
+// returns true.
+func (m *ClientMux) broadcast(n interface{}, deliver func(*MuxClient) bool) {
+	m.mtx.Lock()
+	clients := m.clientList()
+	m.mtx.Unlock()
+
+	for _, c := range clients {
+		if deliver(c) {
+			c.enqueue(n)
+		}
+	}
+}
+
+// MuxClient is a client of a chain backend shared by a ClientMux.  It
+// implements Interface, so a wallet can be synced with it as with the backend
+// itself.  Stopping the client detaches it from the backend without stopping
+// the backend.
+type MuxClient struct {
+	mux     *ClientMux
+	watched *watchList
+
+	mtx          sync.Mutex
+	notifyBlocks bool
+
+	enqueueNotification chan interface{}
+	dequeueNotification chan interface{}
+
+	quit    chan struct{}
+	wg      sync.WaitGroup
+	started bool
+	quitMtx sync.Mutex
+}
+
+// Enforce MuxClient satisfies the Interface.
+var _ Interface = (*MuxClient)(nil)
+
+// Backend returns the chain backend shared by the client.
+func (c *MuxClient) Backend() Interface {
+	return c.mux.backend
+}
+
+// Start attaches the client to the shared backend and starts delivering
+// notifications.  When the backend is already connected, a ClientConnected
+// notification is delivered first.
+func (c *MuxClient) Start() error {
+	c.quitMtx.Lock()
+	defer c.quitMtx.Unlock()
+	select {
+	case <-c.quit:
+		return errors.New("client stopped")
+	case <-c.mux.quit:
+		return errors.New("chain backend stopped")
+	default:
+	}
+	if c.started {
+		return errors.New("client already started")
+	}
+	c.started = true
+
+	c.wg.Add(1)
+	go func() {
+		queueNotifications(c.enqueueNotification,
+			c.dequeueNotification, c.quit)
+		c.wg.Done()
+	}()
+
+	// The connection is notified before the client is visible to the
+	// notification handler so it is always the first notification.
+	m := c.mux
+	m.mtx.Lock()
+	if m.connected {
+		c.enqueue(ClientConnected{})
+	}
+	m.clients[c] = struct{}{}
+	m.mtx.Unlock()
+	return nil
+}
+
+// Stop detaches the client from the shared backend and signals the shutdown
+// of its notification queue.
+func (c *MuxClient) Stop() {
+	c.quitMtx.Lock()
+	select {
+	case <-c.quit:
+	default:
+		close(c.quit)
+		if !c.started {
+			close(c.dequeueNotification)
+		}
+	}
+	c.quitMtx.Unlock()
+
+	m := c.mux
+	m.mtx.Lock()
+	delete(m.clients, c)
+	m.mtx.Unlock()
+}
+
+// WaitForShutdown blocks until the client's notification queue has been shut
+// down.
+func (c *MuxClient) WaitForShutdown() {
+	c.wg.Wait()
+}
+
+// Notifications returns the channel of notifications delivered to the client.
+// The channel is closed when the client is stopped.
+func (c *MuxClient) Notifications() <-chan interface{} {
+	return c.dequeueNotification
+}
+
+// enqueue queues a notification to be read from the notifications channel.
+func (c *MuxClient) enqueue(n interface{}) {
+	select {
+	case c.enqueueNotification <- n:
+	case <-c.quit:
+	}
+}
+
+// notifyingBlocks returns whether the client has requested block
+// notifications.
+func (c *MuxClient) notifyingBlocks() bool {
+	c.mtx.Lock()
+	notify := c.notifyBlocks
+	c.mtx.Unlock()
+	return notify
+}
+
+// GetBestBlock returns the hash and height of the best block known by the
+// backend.
+func (c *MuxClient) GetBestBlock() (*chainhash.Hash, int32, error) {
+	return c.mux.backend.GetBestBlock()
+}
+
+// GetBlock returns the block with the hash.
+func (c *MuxClient) GetBlock(hash *chainhash.Hash) (*wire.MsgBlock, error) {
+	return c.mux.backend.GetBlock(hash)
+}
+
+// GetBlockHash returns the hash of the main chain block at a height.
+func (c *MuxClient) GetBlockHash(height int64) (*chainhash.Hash, error) {
+	return c.mux.backend.GetBlockHash(height)
+}
+
+// GetBlockHeader returns the header of the block with the hash.
+func (c *MuxClient) GetBlockHeader(hash *chainhash.Hash) (*wire.BlockHeader, error) {
+	return c.mux.backend.GetBlockHeader(hash)
+}
+
+// GetBlockHeight returns the height of the block with the hash.
+func (c *MuxClient) GetBlockHeight(hash *chainhash.Hash) (int32, error) {
+	return c.mux.backend.GetBlockHeight(hash)
+}
+
+// BlockStamp returns the latest block notified by the backend.
+func (c *MuxClient) BlockStamp() (*waddrmgr.BlockStamp, error) {
+	return c.mux.backend.BlockStamp()
+}
+
+// SendRawTransaction publishes a transaction to the network with the backend.
+func (c *MuxClient) SendRawTransaction(tx *wire.MsgTx,
+	allowHighFees bool) (*chainhash.Hash, error) {
+
+	return c.mux.backend.SendRawTransaction(tx, allowHighFees)
+}
+
+// EstimateFeePerKb returns the fee rate estimated by the backend.
+func (c *MuxClient) EstimateFeePerKb(confTarget uint32) (btcutil.Amount, error) {
+	return c.mux.backend.EstimateFeePerKb(confTarget)
+}
+
+// NotifyReceived requests RelevantTx notifications for transactions paying
+// to addrs.
+func (c *MuxClient) NotifyReceived(addrs []btcutil.Address) error {
+	err := c.watched.add(addrs, nil)
+	if err != nil {
+		return err
+	}
+	return c.mux.backend.NotifyReceived(addrs)
+}
+
+// NotifyBlocks requests BlockConnected and BlockDisconnected notifications.
+func (c *MuxClient) NotifyBlocks() error {
+	err := c.mux.backend.NotifyBlocks()
+	if err != nil {
+		return err
+	}
+	c.mtx.Lock()
+	c.notifyBlocks = true
+	c.mtx.Unlock()
+	return nil
+}
+
+// Rescan rescans the main chain with the backend once any rescan requested by
+// another client of the backend has finished.  The RescanProgress and
+// RescanFinished notifications of the rescan are only delivered to this
+// client.
+func (c *MuxClient) Rescan(startHash *chainhash.Hash, addrs []btcutil.Address,
+	outPoints []*wire.OutPoint) error {
+
+	err := c.watched.add(addrs, outPoints)
+	if err != nil {
+		return err
+	}
+
+	m := c.mux
+	select {
+	case m.rescanSlot <- struct{}{}:
+	case <-c.quit:
+		return errors.New("client stopped")
+	case <-m.quit:
+		return errors.New("chain backend stopped")
+	}
+	m.mtx.Lock()
+	m.rescanning = c
+	m.mtx.Unlock()
+
+	// The slot is released by the notification handler once the rescan
+	// finished notification is delivered, unless the rescan fails.
+	err = m.backend.Rescan(startHash, addrs, outPoints)
+	if err != nil {
+		m.mtx.Lock()
+		released := m.rescanning == c
+		if released {
+			m.rescanning = nil
+		}
+		m.mtx.Unlock()
+		if released {
+			<-m.rescanSlot
+		}
+	}
+	return err
+}
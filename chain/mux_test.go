@@ -0,0 +1,169 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chain
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+)
+
+func TestClientMux(t *testing.T) {
+	params := &chaincfg.RegressionNetParams
+	var addrs []btcutil.Address
+	var scripts [][]byte
+	for i := byte(0); i < 2; i++ {
+		addr, err := btcutil.NewAddressPubKeyHash(
+			append(make([]byte, 19), i+1), params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		script, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addrs = append(addrs, addr)
+		scripts = append(scripts, script)
+	}
+	otherScript := []byte{txscript.OP_TRUE}
+
+	backend := NewMockClient(params)
+	err := backend.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := NewClientMux(backend)
+
+	// Each client watches one of the addresses.
+	clients := []*MuxClient{mux.NewClient(), mux.NewClient()}
+	for i, c := range clients {
+		err := c.Start()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := nextNotification(t, c).(ClientConnected); !ok {
+			t.Fatal("expected ClientConnected notification")
+		}
+		err = c.NotifyBlocks()
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = c.NotifyReceived(addrs[i : i+1])
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Blocks are notified to both clients, but transactions only to the
+	// client watching the address they pay to.
+	blocks := []*wire.MsgBlock{params.GenesisBlock}
+	for height := int32(1); height <= 2; height++ {
+		block := newTestBlock(t, params, blocks[height-1], height,
+			scripts[height-1])
+		err := backend.ConnectBlock(block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		blocks = append(blocks, block)
+
+		for i, c := range clients {
+			if int32(i) == height-1 {
+				checkRelevantTx(t, nextNotification(t, c),
+					block.Transactions[0].TxHash(), block,
+					height)
+			}
+			checkBlockNotification(t, nextNotification(t, c), true,
+				block, height)
+		}
+	}
+
+	// Rescan progress is only delivered to the rescanning client, while the
+	// transactions found are delivered to every client watching them.
+	genesisHash := params.GenesisBlock.BlockHash()
+	err = clients[0].Rescan(&genesisHash, addrs[:1], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRelevantTx(t, nextNotification(t, clients[0]),
+		blocks[1].Transactions[0].TxHash(), blocks[1], 1)
+	if _, ok := nextNotification(t, clients[0]).(*RescanProgress); !ok {
+		t.Fatal("expected RescanProgress notification")
+	}
+	finished, ok := nextNotification(t, clients[0]).(*RescanFinished)
+	if !ok || finished.Height != 2 {
+		t.Fatalf("expected RescanFinished notification at height 2, "+
+			"got %v", finished)
+	}
+	checkRelevantTx(t, nextNotification(t, clients[1]),
+		blocks[2].Transactions[0].TxHash(), blocks[2], 2)
+
+	block := newTestBlock(t, params, blocks[2], 3, otherScript)
+	err = backend.ConnectBlock(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocks = append(blocks, block)
+	for _, c := range clients {
+		checkBlockNotification(t, nextNotification(t, c), true, block, 3)
+	}
+
+	// A second rescan can be performed once the first has finished.
+	err = clients[1].Rescan(&genesisHash, addrs[1:], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRelevantTx(t, nextNotification(t, clients[1]),
+		blocks[2].Transactions[0].TxHash(), blocks[2], 2)
+	if _, ok := nextNotification(t, clients[1]).(*RescanProgress); !ok {
+		t.Fatal("expected RescanProgress notification")
+	}
+	if _, ok := nextNotification(t, clients[1]).(*RescanFinished); !ok {
+		t.Fatal("expected RescanFinished notification")
+	}
+	checkRelevantTx(t, nextNotification(t, clients[0]),
+		blocks[1].Transactions[0].TxHash(), blocks[1], 1)
+
+	// Stopping a client detaches it without stopping the backend.
+	clients[0].Stop()
+	clients[0].WaitForShutdown()
+	if _, ok := <-clients[0].Notifications(); ok {
+		t.Fatal("notifications channel of stopped client not closed")
+	}
+	block = newTestBlock(t, params, blocks[3], 4, scripts[0])
+	err = backend.ConnectBlock(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBlockNotification(t, nextNotification(t, clients[1]), true,
+		block, 4)
+
+	// A client started after the backend connected is notified of the
+	// connection.
+	late := mux.NewClient()
+	err = late.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := nextNotification(t, late).(ClientConnected); !ok {
+		t.Fatal("expected ClientConnected notification")
+	}
+
+	// Stopping the backend stops all clients.
+	backend.Stop()
+	backend.WaitForShutdown()
+	mux.WaitForShutdown()
+	for _, c := range []*MuxClient{clients[1], late} {
+		if _, ok := <-c.Notifications(); ok {
+			t.Fatal("notifications channel of client not closed " +
+				"after backend shutdown")
+		}
+	}
+	if err := mux.NewClient().Start(); err == nil {
+		t.Fatal("client started after backend shutdown")
+	}
+}
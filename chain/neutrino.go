@@ -0,0 +1,824 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chain
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/peer"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/gcs"
+	"github.com/btcsuite/btcutil/gcs/builder"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+)
+
+// neutrinoQueryTimeout is the duration the light client waits for its peer to
+// respond to a request before failing the request.
+const neutrinoQueryTimeout = 30 * time.Second
+
+// errNoFeeEstimates is returned by light clients, which are unable to
+// estimate fees.
+var errNoFeeEstimates = errors.New("no fee estimate available from the " +
+	"light client")
+
+// NeutrinoClient is a light client chain backend which follows the chain of
+// block headers served by a peer on the bitcoin network, and downloads the
+// BIP 157 compact filters of the blocks.  The filters are matched locally
+// against the scripts watched by the wallet, and only blocks with matching
+// filters are downloaded, so the peer does not learn which addresses belong
+// to the wallet.
+//
+// The client trusts its peer to serve the filters of the blocks, since the
+// filter headers are not checked against other peers.  Block headers are
+// checked to connect to each other, to have the difficulty required by the
+// network's retarget rules, to meet their proof of work targets and to match
+// the network's checkpoints.
+type NeutrinoClient struct {
+	chainParams *chaincfg.Params
+	connect     string
+	chain       *headerChain
+
+	peer *peer.Peer
+
+	// Responses to requests made to the peer.  Only a single request is
+	// outstanding at any time, which is ensured by queryMtx.
+	queryMtx  sync.Mutex
+	verAck    chan struct{}
+	headers   chan *wire.MsgHeaders
+	cfheaders chan *wire.MsgCFHeaders
+	cfilters  chan *wire.MsgCFilter
+	blocks    chan *wire.MsgBlock
+	notFound  chan *wire.MsgNotFound
+
+	// watched holds the scripts and outpoints watched for relevant
+	// transactions.
+	watched *watchList
+
+	notifyBlocksMtx sync.Mutex
+	notifyBlocks    bool
+
+	// syncMtx prevents new blocks from being processed while the filters
+	// of the main chain are being rescanned.
+	syncMtx        sync.Mutex
+	blockAnnounced chan struct{}
+
+	bestMtx sync.Mutex
+	best    waddrmgr.BlockStamp
+
+	enqueueNotification chan interface{}
+	dequeueNotification chan interface{}
+
+	quit    chan struct{}
+	wg      sync.WaitGroup
+	started bool
+	quitMtx sync.Mutex
+}
+
+// NewNeutrinoClient creates a light client which connects to the peer at the
+// host:port address connect.  The peer must serve compact filters.  The block
+// headers and filter headers are persisted in the directory dataDir, so they
+// are not downloaded again when the client is restarted, or are only held in
+// memory if dataDir is empty.  The connection is not established immediately,
+// but must be done using the Start method.
+func NewNeutrinoClient(chainParams *chaincfg.Params, connect,
+	dataDir string) (*NeutrinoClient, error) {
+
+	headers := newHeaderChain(chainParams)
+	if dataDir != "" {
+		var err error
+		headers, err = openHeaderChain(chainParams, dataDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &NeutrinoClient{
+		chainParams:         chainParams,
+		connect:             connect,
+		chain:               headers,
+		verAck:              make(chan struct{}, 1),
+		headers:             make(chan *wire.MsgHeaders, 1),
+		cfheaders:           make(chan *wire.MsgCFHeaders, 1),
+		cfilters:            make(chan *wire.MsgCFilter, wire.MaxGetCFiltersReqRange),
+		blocks:              make(chan *wire.MsgBlock, 1),
+		notFound:            make(chan *wire.MsgNotFound, 1),
+		watched:             newWatchList(),
+		blockAnnounced:      make(chan struct{}, 1),
+		enqueueNotification: make(chan interface{}),
+		dequeueNotification: make(chan interface{}),
+		quit:                make(chan struct{}),
+	}, nil
+}
+
+// peerConfig returns the configuration of the client's peer connection.
+func (c *NeutrinoClient) peerConfig() *peer.Config {
+	return &peer.Config{
+		NewestBlock: func() (*chainhash.Hash, int32, error) {
+			hash, height := c.chain.tip()
+			return &hash, height, nil
+		},
+		HostToNetAddress: func(host string, port uint16,
+			services wire.ServiceFlag) (*wire.NetAddress, error) {
+
+			ips, err := net.LookupIP(host)
+			if err != nil {
+				return nil, err
+			}
+			return wire.NewNetAddressIPPort(ips[0], port, services), nil
+		},
+		UserAgentName:    "btcwallet",
+		UserAgentVersion: "0.7.0",
+		ChainParams:      c.chainParams,
+		Listeners: peer.MessageListeners{
+			OnVerAck:    c.onVerAck,
+			OnHeaders:   c.onHeaders,
+			OnCFHeaders: c.onCFHeaders,
+			OnCFilter:   c.onCFilter,
+			OnBlock:     c.onBlock,
+			OnNotFound:  c.onNotFound,
+			OnInv:       c.onInv,
+			OnTx:        c.onTx,
+		},
+	}
+}
+
+// Start connects to the peer and synchronizes the chain of block headers and
+// filter headers with the peer, before starting the handler which delivers
+// notifications for new blocks.  The client is stopped when the peer
+// disconnects.
+func (c *NeutrinoClient) Start() error {
+	// Notifications for unmined transactions may be queued as soon as the
+	// peer is connected, so the queue is started first.
+	c.quitMtx.Lock()
+	c.started = true
+	c.quitMtx.Unlock()
+	c.wg.Add(1)
+	go func() {
+		queueNotifications(c.enqueueNotification,
+			c.dequeueNotification, c.quit)
+		c.wg.Done()
+	}()
+
+	err := c.connectPeer()
+	if err != nil {
+		c.Stop()
+		return err
+	}
+	_, err = c.syncHeaders()
+	if err != nil {
+		c.Stop()
+		return err
+	}
+	hash, height := c.chain.tip()
+	c.setBest(hash, height)
+	log.Infof("Synchronized headers with peer %v through block %v "+
+		"(height %d)", c.peer, hash, height)
+
+	c.wg.Add(2)
+	go c.syncHandler()
+	go func() {
+		c.peer.WaitForDisconnect()
+		c.Stop()
+		c.wg.Done()
+	}()
+
+	c.enqueue(ClientConnected{})
+	return nil
+}
+
+// connectPeer connects to the peer and waits for the handshake to complete.
+func (c *NeutrinoClient) connectPeer() error {
+	conn, err := net.DialTimeout("tcp", c.connect, neutrinoQueryTimeout)
+	if err != nil {
+		return err
+	}
+	p, err := peer.NewOutboundPeer(c.peerConfig(), c.connect)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	c.quitMtx.Lock()
+	c.peer = p
+	c.quitMtx.Unlock()
+	p.AssociateConnection(conn)
+
+	disconnected := make(chan struct{})
+	go func() {
+		p.WaitForDisconnect()
+		close(disconnected)
+	}()
+	select {
+	case <-c.verAck:
+	case <-disconnected:
+		return fmt.Errorf("peer %v disconnected during handshake", p)
+	case <-time.After(neutrinoQueryTimeout):
+		return errors.New("timeout waiting for peer handshake")
+	case <-c.quit:
+		return errors.New("disconnected")
+	}
+	if p.Services()&wire.SFNodeCF != wire.SFNodeCF {
+		return fmt.Errorf("peer %v does not serve compact filters", p)
+	}
+	return nil
+}
+
+// Stop disconnects from the peer and signals the shutdown of all goroutines
+// started by Start.
+func (c *NeutrinoClient) Stop() {
+	c.quitMtx.Lock()
+	select {
+	case <-c.quit:
+	default:
+		close(c.quit)
+		if c.peer != nil {
+			c.peer.Disconnect()
+		}
+
+		if !c.started {
+			close(c.dequeueNotification)
+		}
+	}
+	c.quitMtx.Unlock()
+}
+
+// WaitForShutdown blocks until the peer has disconnected and all goroutines
+// started by Start have exited, and closes the files persisting the headers.
+func (c *NeutrinoClient) WaitForShutdown() {
+	c.quitMtx.Lock()
+	p := c.peer
+	c.quitMtx.Unlock()
+	if p != nil {
+		p.WaitForDisconnect()
+	}
+	c.wg.Wait()
+	err := c.chain.close()
+	if err != nil {
+		log.Errorf("Failed to close block headers: %v", err)
+	}
+}
+
+// Notifications returns a channel of notifications for the blocks and
+// transactions found by the client.  This channel must be continually read
+// or the process may abort for running out memory, as unread notifications
+// are queued for later reads.
+func (c *NeutrinoClient) Notifications() <-chan interface{} {
+	return c.dequeueNotification
+}
+
+// enqueue queues a notification to be read from the notifications channel.
+func (c *NeutrinoClient) enqueue(n interface{}) {
+	select {
+	case c.enqueueNotification <- n:
+	case <-c.quit:
+	}
+}
+
+// GetBestBlock returns the hash and height of the last block of the header
+// chain.
+func (c *NeutrinoClient) GetBestBlock() (*chainhash.Hash, int32, error) {
+	hash, height := c.chain.tip()
+	return &hash, height, nil
+}
+
+// GetBlockHash returns the hash of the main chain block at a height.
+func (c *NeutrinoClient) GetBlockHash(height int64) (*chainhash.Hash, error) {
+	return c.chain.blockHash(int32(height))
+}
+
+// GetBlockHeader returns the header of the main chain block with the hash.
+func (c *NeutrinoClient) GetBlockHeader(hash *chainhash.Hash) (*wire.BlockHeader, error) {
+	header, _, err := c.chain.header(hash)
+	return header, err
+}
+
+// GetBlockHeight returns the height of the block with the hash.
+func (c *NeutrinoClient) GetBlockHeight(hash *chainhash.Hash) (int32, error) {
+	_, height, err := c.chain.header(hash)
+	return height, err
+}
+
+// BlockStamp returns the latest block notified by the client, or an error if
+// the client has been shut down.
+func (c *NeutrinoClient) BlockStamp() (*waddrmgr.BlockStamp, error) {
+	select {
+	case <-c.quit:
+		return nil, errors.New("disconnected")
+	default:
+	}
+	c.bestMtx.Lock()
+	bs := c.best
+	c.bestMtx.Unlock()
+	return &bs, nil
+}
+
+func (c *NeutrinoClient) setBest(hash chainhash.Hash, height int32) {
+	c.bestMtx.Lock()
+	c.best = waddrmgr.BlockStamp{Hash: hash, Height: height}
+	c.bestMtx.Unlock()
+}
+
+// EstimateFeePerKb always returns an error, since fee rates can not be
+// estimated from the data downloaded by the light client.
+func (c *NeutrinoClient) EstimateFeePerKb(confTarget uint32) (btcutil.Amount, error) {
+	return 0, errNoFeeEstimates
+}
+
+// SendRawTransaction relays a transaction to the peer.
+func (c *NeutrinoClient) SendRawTransaction(tx *wire.MsgTx,
+	allowHighFees bool) (*chainhash.Hash, error) {
+
+	select {
+	case <-c.quit:
+		return nil, errors.New("disconnected")
+	default:
+	}
+	c.peer.QueueMessage(tx, nil)
+	hash := tx.TxHash()
+	return &hash, nil
+}
+
+// NotifyReceived requests RelevantTx notifications for transactions paying
+// to addrs.
+func (c *NeutrinoClient) NotifyReceived(addrs []btcutil.Address) error {
+	return c.watched.add(addrs, nil)
+}
+
+// NotifyBlocks requests BlockConnected and BlockDisconnected notifications
+// for changes to the main chain.
+func (c *NeutrinoClient) NotifyBlocks() error {
+	c.notifyBlocksMtx.Lock()
+	c.notifyBlocks = true
+	c.notifyBlocksMtx.Unlock()
+	return nil
+}
+
+// notifyRelevantTx queues a RelevantTx notification for a transaction, which
+// is mined in block, or unmined if block is nil.
+func (c *NeutrinoClient) notifyRelevantTx(tx *wire.MsgTx, block *wtxmgr.BlockMeta) {
+	rec, err := wtxmgr.NewTxRecordFromMsgTx(tx, time.Now())
+	if err != nil {
+		log.Errorf("Cannot create transaction record for relevant "+
+			"tx: %v", err)
+		return
+	}
+	c.enqueue(RelevantTx{rec, block})
+}
+
+// Rescan searches the main chain, from the block startHash through the tip of
+// the header chain, for transactions paying to addrs or spending outPoints.
+// The filters of the blocks are matched against the watched scripts, and only
+// matching blocks are downloaded.  Spends of outpoints are found by matching
+// the scripts of the spent outputs, so the addresses paid by outPoints must be
+// watched as well.  RescanProgress notifications are sent after each batch of
+// filters is scanned, followed by a RescanFinished notification.
+func (c *NeutrinoClient) Rescan(startHash *chainhash.Hash, addrs []btcutil.Address,
+	outPoints []*wire.OutPoint) error {
+
+	_, height, err := c.chain.header(startHash)
+	if err != nil {
+		return err
+	}
+	err = c.watched.add(addrs, outPoints)
+	if err != nil {
+		return err
+	}
+
+	// New blocks are not processed during the rescan, so the rescan
+	// must scan through the tip of the header chain, even as blocks are
+	// added.
+	c.syncMtx.Lock()
+	defer c.syncMtx.Unlock()
+
+	var last *wtxmgr.BlockMeta
+	for {
+		_, tipHeight := c.chain.tip()
+		if height > tipHeight {
+			break
+		}
+		stop := height + wire.MaxGetCFiltersReqRange - 1
+		if stop > tipHeight {
+			stop = tipHeight
+		}
+		err := c.scanBlocks(height, stop)
+		if err != nil {
+			return err
+		}
+		last, err = c.chain.blockMeta(stop)
+		if err != nil {
+			return err
+		}
+		c.enqueue(&RescanProgress{&last.Hash, last.Height, last.Time})
+		height = stop + 1
+	}
+	if last == nil {
+		last, err = c.chain.blockMeta(height - 1)
+		if err != nil {
+			return err
+		}
+	}
+	c.enqueue(&RescanFinished{&last.Hash, last.Height, last.Time})
+	return nil
+}
+
+// scanBlocks matches the filters of the blocks from height start through stop
+// against the watched scripts, and queues RelevantTx notifications for the
+// relevant transactions of the matching blocks.
+func (c *NeutrinoClient) scanBlocks(start, stop int32) error {
+	scripts := c.watched.scriptList()
+	if len(scripts) == 0 {
+		return nil
+	}
+	filters, err := c.getFilters(start, stop)
+	if err != nil {
+		return err
+	}
+	for i, filter := range filters {
+		height := start + int32(i)
+		meta, err := c.chain.blockMeta(height)
+		if err != nil {
+			return err
+		}
+		if filter.N() == 0 {
+			continue
+		}
+		key := builder.DeriveKey(&meta.Hash)
+		matched, err := filter.MatchAny(key, scripts)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			continue
+		}
+
+		block, err := c.GetBlock(&meta.Hash)
+		if err != nil {
+			return err
+		}
+		for _, tx := range block.Transactions {
+			if c.watched.relevant(tx) {
+				c.notifyRelevantTx(tx, meta)
+			}
+		}
+	}
+	return nil
+}
+
+// syncHandler processes the blocks announced by the peer.
+func (c *NeutrinoClient) syncHandler() {
+	defer c.wg.Done()
+
+	for {
+		select {
+		case <-c.blockAnnounced:
+		case <-c.quit:
+			return
+		}
+
+		err := c.processNewBlocks()
+		if err != nil {
+			log.Errorf("Unable to process new blocks from peer %v: %v",
+				c.peer, err)
+		}
+	}
+}
+
+// processNewBlocks synchronizes the header chain with the peer, scans the
+// filters of the new blocks, and queues notifications for the disconnected
+// blocks, the relevant transactions of the new blocks, and the new blocks.
+func (c *NeutrinoClient) processNewBlocks() error {
+	c.syncMtx.Lock()
+	defer c.syncMtx.Unlock()
+
+	_, prevTip := c.chain.tip()
+	disconnected, err := c.syncHeaders()
+	if err != nil {
+		return err
+	}
+	_, tipHeight := c.chain.tip()
+
+	c.notifyBlocksMtx.Lock()
+	notifyBlocks := c.notifyBlocks
+	c.notifyBlocksMtx.Unlock()
+
+	start := prevTip + 1
+	for _, b := range disconnected {
+		if notifyBlocks {
+			c.enqueue(BlockDisconnected(b))
+		}
+		start = b.Height
+	}
+	for height := start; height <= tipHeight; height++ {
+		err := c.scanBlocks(height, height)
+		if err != nil {
+			return err
+		}
+		meta, err := c.chain.blockMeta(height)
+		if err != nil {
+			return err
+		}
+		c.setBest(meta.Hash, meta.Height)
+		if notifyBlocks {
+			c.enqueue(BlockConnected(*meta))
+		}
+	}
+	return nil
+}
+
+// syncHeaders downloads the block headers and filter headers following the
+// tip of the header chain from the peer.  The blocks disconnected by a
+// reorganization are returned, from the old tip down.
+func (c *NeutrinoClient) syncHeaders() ([]wtxmgr.BlockMeta, error) {
+	var disconnected []wtxmgr.BlockMeta
+	for {
+		msg := wire.NewMsgGetHeaders()
+		for _, hash := range c.chain.locator() {
+			err := msg.AddBlockLocatorHash(hash)
+			if err != nil {
+				return nil, err
+			}
+		}
+		c.queryMtx.Lock()
+		c.drainResponses()
+		c.peer.QueueMessage(msg, nil)
+		var resp *wire.MsgHeaders
+		select {
+		case resp = <-c.headers:
+		case <-time.After(neutrinoQueryTimeout):
+		case <-c.quit:
+		}
+		c.queryMtx.Unlock()
+		if resp == nil {
+			return nil, errors.New("no headers received from peer")
+		}
+		if len(resp.Headers) == 0 {
+			break
+		}
+
+		d, err := c.chain.connect(resp.Headers)
+		if err == errWeakerFork {
+			log.Warnf("Ignoring headers from peer %v: %v", c.peer, err)
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		disconnected = append(disconnected, d...)
+		if len(resp.Headers) < wire.MaxBlockHeadersPerMsg {
+			break
+		}
+	}
+
+	err := c.syncFilterHeaders()
+	if err != nil {
+		return nil, err
+	}
+	return disconnected, nil
+}
+
+// syncFilterHeaders downloads the filter headers of the blocks of the header
+// chain with unknown filter headers.
+func (c *NeutrinoClient) syncFilterHeaders() error {
+	for {
+		start := c.chain.filterHeaderCount()
+		_, tipHeight := c.chain.tip()
+		if start > tipHeight {
+			return nil
+		}
+		stop := start + wire.MaxCFHeadersPerMsg - 1
+		if stop > tipHeight {
+			stop = tipHeight
+		}
+		stopHash, err := c.chain.blockHash(stop)
+		if err != nil {
+			return err
+		}
+
+		msg := wire.NewMsgGetCFHeaders(wire.GCSFilterRegular,
+			uint32(start), stopHash)
+		c.queryMtx.Lock()
+		c.drainResponses()
+		c.peer.QueueMessage(msg, nil)
+		var resp *wire.MsgCFHeaders
+		select {
+		case resp = <-c.cfheaders:
+		case <-time.After(neutrinoQueryTimeout):
+		case <-c.quit:
+		}
+		c.queryMtx.Unlock()
+		if resp == nil {
+			return errors.New("no filter headers received from peer")
+		}
+		if resp.StopHash != *stopHash ||
+			len(resp.FilterHashes) != int(stop-start+1) {
+			return errors.New("peer sent filter headers for the " +
+				"wrong blocks")
+		}
+
+		err = c.chain.connectFilterHeaders(resp.PrevFilterHeader,
+			resp.StopHash, resp.FilterHashes)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// getFilters downloads the filters of the blocks from height start through
+// stop, and checks them against the filter headers.
+func (c *NeutrinoClient) getFilters(start, stop int32) ([]*gcs.Filter, error) {
+	stopHash, err := c.chain.blockHash(stop)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := wire.NewMsgGetCFilters(wire.GCSFilterRegular, uint32(start),
+		stopHash)
+	c.queryMtx.Lock()
+	defer c.queryMtx.Unlock()
+	c.drainResponses()
+	c.peer.QueueMessage(msg, nil)
+
+	filters := make([]*gcs.Filter, 0, stop-start+1)
+	timeout := time.After(neutrinoQueryTimeout)
+	for height := start; height <= stop; height++ {
+		var resp *wire.MsgCFilter
+		select {
+		case resp = <-c.cfilters:
+		case <-timeout:
+			return nil, errors.New("timeout waiting for filters")
+		case <-c.quit:
+			return nil, errors.New("disconnected")
+		}
+
+		err := c.chain.checkFilter(height, &resp.BlockHash, resp.Data)
+		if err != nil {
+			return nil, err
+		}
+		filter, err := gcs.FromNBytes(builder.DefaultP, builder.DefaultM,
+			resp.Data)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
+
+// GetBlock downloads the block with the hash from the peer, and checks that
+// the block's transactions match its header.
+func (c *NeutrinoClient) GetBlock(hash *chainhash.Hash) (*wire.MsgBlock, error) {
+	invType := wire.InvTypeBlock
+	if c.peer.IsWitnessEnabled() {
+		invType = wire.InvTypeWitnessBlock
+	}
+	msg := wire.NewMsgGetData()
+	err := msg.AddInvVect(wire.NewInvVect(invType, hash))
+	if err != nil {
+		return nil, err
+	}
+
+	c.queryMtx.Lock()
+	defer c.queryMtx.Unlock()
+	c.drainResponses()
+	c.peer.QueueMessage(msg, nil)
+
+	timeout := time.After(neutrinoQueryTimeout)
+	for {
+		select {
+		case block := <-c.blocks:
+			if block.BlockHash() != *hash {
+				continue
+			}
+			if !merkleRootMatches(block) {
+				return nil, fmt.Errorf("block %v has an "+
+					"invalid merkle root", hash)
+			}
+			return block, nil
+		case <-c.notFound:
+			return nil, fmt.Errorf("block %v not found", hash)
+		case <-timeout:
+			return nil, fmt.Errorf("timeout waiting for block %v",
+				hash)
+		case <-c.quit:
+			return nil, errors.New("disconnected")
+		}
+	}
+}
+
+// merkleRootMatches returns whether the merkle root of a block's transactions
+// matches the merkle root of its header.
+func merkleRootMatches(block *wire.MsgBlock) bool {
+	txs := make([]*btcutil.Tx, len(block.Transactions))
+	for i, tx := range block.Transactions {
+		txs[i] = btcutil.NewTx(tx)
+	}
+	merkles := blockchain.BuildMerkleTreeStore(txs, false)
+	return *merkles[len(merkles)-1] == block.Header.MerkleRoot
+}
+
+// drainResponses discards responses to previous requests which arrived after
+// the requests timed out.  It must be called with queryMtx held.
+func (c *NeutrinoClient) drainResponses() {
+	for {
+		select {
+		case <-c.headers:
+		case <-c.cfheaders:
+		case <-c.cfilters:
+		case <-c.blocks:
+		case <-c.notFound:
+		default:
+			return
+		}
+	}
+}
+
+func (c *NeutrinoClient) onVerAck(p *peer.Peer, msg *wire.MsgVerAck) {
+	select {
+	case c.verAck <- struct{}{}:
+	default:
+	}
+}
+
+func (c *NeutrinoClient) onHeaders(p *peer.Peer, msg *wire.MsgHeaders) {
+	select {
+	case c.headers <- msg:
+	default:
+		// Headers not requested by the client announce new blocks.
+		c.announceBlock()
+	}
+}
+
+func (c *NeutrinoClient) onCFHeaders(p *peer.Peer, msg *wire.MsgCFHeaders) {
+	select {
+	case c.cfheaders <- msg:
+	default:
+	}
+}
+
+func (c *NeutrinoClient) onCFilter(p *peer.Peer, msg *wire.MsgCFilter) {
+	select {
+	case c.cfilters <- msg:
+	default:
+	}
+}
+
+func (c *NeutrinoClient) onBlock(p *peer.Peer, msg *wire.MsgBlock, buf []byte) {
+	select {
+	case c.blocks <- msg:
+	default:
+	}
+}
+
+func (c *NeutrinoClient) onNotFound(p *peer.Peer, msg *wire.MsgNotFound) {
+	select {
+	case c.notFound <- msg:
+	default:
+	}
+}
+
+// onInv requests the transactions announced by the peer, and signals the
+// sync handler when blocks are announced.
+func (c *NeutrinoClient) onInv(p *peer.Peer, msg *wire.MsgInv) {
+	getData := wire.NewMsgGetData()
+	for _, iv := range msg.InvList {
+		switch iv.Type {
+		case wire.InvTypeBlock, wire.InvTypeWitnessBlock:
+			c.announceBlock()
+		case wire.InvTypeTx, wire.InvTypeWitnessTx:
+			invType := wire.InvTypeTx
+			if p.IsWitnessEnabled() {
+				invType = wire.InvTypeWitnessTx
+			}
+			getData.AddInvVect(wire.NewInvVect(invType, &iv.Hash))
+		}
+	}
+	if len(getData.InvList) != 0 {
+		p.QueueMessage(getData, nil)
+	}
+}
+
+// onTx queues notifications for relevant unmined transactions relayed by the
+// peer.
+func (c *NeutrinoClient) onTx(p *peer.Peer, msg *wire.MsgTx) {
+	if c.watched.relevant(msg) {
+		c.notifyRelevantTx(msg, nil)
+	}
+}
+
+// announceBlock signals the sync handler to process new blocks.
+func (c *NeutrinoClient) announceBlock() {
+	select {
+	case c.blockAnnounced <- struct{}{}:
+	default:
+	}
+}
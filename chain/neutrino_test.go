@@ -0,0 +1,517 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chain
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/gcs/builder"
+)
+
+// testPeer is an in-process peer serving the headers, compact filters and
+// blocks of a fixture chain to a light client.  It implements the peer side of
+// the protocol directly on the connection, since the peer package refuses
+// connections between peers of the same process.
+type testPeer struct {
+	t        *testing.T
+	params   *chaincfg.Params
+	listener net.Listener
+
+	mtx           sync.Mutex
+	blocks        []*wire.MsgBlock
+	filters       [][]byte
+	filterHeaders []chainhash.Hash
+	heights       map[chainhash.Hash]int32
+	prevScripts   map[wire.OutPoint][]byte
+	conn          net.Conn
+	connected     chan struct{}
+}
+
+func newTestPeer(t *testing.T, params *chaincfg.Params) *testPeer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tp := &testPeer{
+		t:           t,
+		params:      params,
+		listener:    listener,
+		heights:     make(map[chainhash.Hash]int32),
+		prevScripts: make(map[wire.OutPoint][]byte),
+		connected:   make(chan struct{}),
+	}
+	tp.connectBlock(params.GenesisBlock)
+	go tp.serve()
+	return tp
+}
+
+// serve accepts a single connection, performs the version handshake and
+// responds to the requests of the client until the connection is closed.
+func (tp *testPeer) serve() {
+	conn, err := tp.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	tp.mtx.Lock()
+	tp.conn = conn
+	tp.mtx.Unlock()
+
+	msg, err := tp.read()
+	if err != nil {
+		return
+	}
+	if _, ok := msg.(*wire.MsgVersion); !ok {
+		return
+	}
+	tcpAddr := conn.LocalAddr().(*net.TCPAddr)
+	services := wire.SFNodeNetwork | wire.SFNodeWitness | wire.SFNodeCF
+	me := wire.NewNetAddress(tcpAddr, services)
+	you := wire.NewNetAddress(conn.RemoteAddr().(*net.TCPAddr), 0)
+	version := wire.NewMsgVersion(me, you, 1, int32(len(tp.blocks)-1))
+	version.Services = services
+	tp.write(version)
+	tp.write(wire.NewMsgVerAck())
+	close(tp.connected)
+
+	for {
+		msg, err := tp.read()
+		if err != nil {
+			return
+		}
+		switch msg := msg.(type) {
+		case *wire.MsgGetHeaders:
+			tp.onGetHeaders(msg)
+		case *wire.MsgGetCFHeaders:
+			tp.onGetCFHeaders(msg)
+		case *wire.MsgGetCFilters:
+			tp.onGetCFilters(msg)
+		case *wire.MsgGetData:
+			tp.onGetData(msg)
+		case *wire.MsgPing:
+			tp.write(wire.NewMsgPong(msg.Nonce))
+		}
+	}
+}
+
+func (tp *testPeer) read() (wire.Message, error) {
+	msg, _, err := wire.ReadMessage(tp.conn, wire.ProtocolVersion,
+		tp.params.Net)
+	return msg, err
+}
+
+// write sends a message to the client.  Write errors are ignored, since the
+// client may disconnect at any time.
+func (tp *testPeer) write(msg wire.Message) {
+	wire.WriteMessage(tp.conn, msg, wire.ProtocolVersion, tp.params.Net)
+}
+
+func (tp *testPeer) close() {
+	tp.listener.Close()
+	tp.mtx.Lock()
+	conn := tp.conn
+	tp.mtx.Unlock()
+	if conn != nil {
+		conn.Close()
+	}
+}
+
+// connectBlock adds a block and its filter to the served chain.
+func (tp *testPeer) connectBlock(block *wire.MsgBlock) {
+	tp.mtx.Lock()
+	defer tp.mtx.Unlock()
+
+	var prevOutScripts [][]byte
+	for _, tx := range block.Transactions {
+		for _, in := range tx.TxIn {
+			if script, ok := tp.prevScripts[in.PreviousOutPoint]; ok {
+				prevOutScripts = append(prevOutScripts, script)
+			}
+		}
+		for i, out := range tx.TxOut {
+			op := wire.OutPoint{Hash: tx.TxHash(), Index: uint32(i)}
+			tp.prevScripts[op] = out.PkScript
+		}
+	}
+	filter, err := builder.BuildBasicFilter(block, prevOutScripts)
+	if err != nil {
+		tp.t.Fatal(err)
+	}
+	filterData, err := filter.NBytes()
+	if err != nil {
+		tp.t.Fatal(err)
+	}
+	var prevHeader chainhash.Hash
+	if len(tp.filterHeaders) != 0 {
+		prevHeader = tp.filterHeaders[len(tp.filterHeaders)-1]
+	}
+	filterHeader, err := builder.MakeHeaderForFilter(filter, prevHeader)
+	if err != nil {
+		tp.t.Fatal(err)
+	}
+
+	tp.heights[block.BlockHash()] = int32(len(tp.blocks))
+	tp.blocks = append(tp.blocks, block)
+	tp.filters = append(tp.filters, filterData)
+	tp.filterHeaders = append(tp.filterHeaders, filterHeader)
+}
+
+// disconnectBlocks removes the blocks after height from the served chain.
+func (tp *testPeer) disconnectBlocks(height int32) {
+	tp.mtx.Lock()
+	defer tp.mtx.Unlock()
+
+	for _, block := range tp.blocks[height+1:] {
+		delete(tp.heights, block.BlockHash())
+	}
+	tp.blocks = tp.blocks[:height+1]
+	tp.filters = tp.filters[:height+1]
+	tp.filterHeaders = tp.filterHeaders[:height+1]
+}
+
+// tip returns the last block of the served chain.
+func (tp *testPeer) tip() *wire.MsgBlock {
+	tp.mtx.Lock()
+	defer tp.mtx.Unlock()
+	return tp.blocks[len(tp.blocks)-1]
+}
+
+// mineBlock creates a block extending prev with a coinbase paying to
+// payScript, followed by txs, and adds it to the served chain.
+func (tp *testPeer) mineBlock(prev *wire.MsgBlock, payScript []byte,
+	txs ...*wire.MsgTx) *wire.MsgBlock {
+
+	tp.mtx.Lock()
+	height := tp.heights[prev.BlockHash()] + 1
+	tp.mtx.Unlock()
+
+	block := newTestBlock(tp.t, tp.params, prev, height, payScript, txs...)
+	tp.connectBlock(block)
+	return block
+}
+
+// newTestBlock creates a block at a height extending prev with a coinbase
+// paying to payScript, followed by txs, solving the proof of work.
+func newTestBlock(t *testing.T, params *chaincfg.Params, prev *wire.MsgBlock,
+	height int32, payScript []byte, txs ...*wire.MsgTx) *wire.MsgBlock {
+
+	sigScript, err := txscript.NewScriptBuilder().AddInt64(int64(height)).
+		AddData(payScript).Script()
+	if err != nil {
+		t.Fatal(err)
+	}
+	coinbase := wire.NewMsgTx(wire.TxVersion)
+	coinbase.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Index: wire.MaxPrevOutIndex},
+		SignatureScript:  sigScript,
+		Sequence:         wire.MaxTxInSequenceNum,
+	})
+	coinbase.AddTxOut(wire.NewTxOut(50e8, payScript))
+
+	block := &wire.MsgBlock{
+		Header: wire.BlockHeader{
+			Version:   1,
+			PrevBlock: prev.BlockHash(),
+			Timestamp: prev.Header.Timestamp.Add(time.Minute),
+			Bits:      params.PowLimitBits,
+		},
+		Transactions: append([]*wire.MsgTx{coinbase}, txs...),
+	}
+	utilTxs := make([]*btcutil.Tx, len(block.Transactions))
+	for i, tx := range block.Transactions {
+		utilTxs[i] = btcutil.NewTx(tx)
+	}
+	merkles := blockchain.BuildMerkleTreeStore(utilTxs, false)
+	block.Header.MerkleRoot = *merkles[len(merkles)-1]
+
+	target := blockchain.CompactToBig(block.Header.Bits)
+	for {
+		hash := block.BlockHash()
+		if blockchain.HashToBig(&hash).Cmp(target) <= 0 {
+			break
+		}
+		block.Header.Nonce++
+	}
+	return block
+}
+
+// announce announces the tip of the served chain to the client.
+func (tp *testPeer) announce() {
+	<-tp.connected
+	hash := tp.tip().BlockHash()
+	inv := wire.NewMsgInv()
+	inv.AddInvVect(wire.NewInvVect(wire.InvTypeBlock, &hash))
+
+	tp.mtx.Lock()
+	defer tp.mtx.Unlock()
+	tp.write(inv)
+}
+
+func (tp *testPeer) onGetHeaders(msg *wire.MsgGetHeaders) {
+	tp.mtx.Lock()
+	defer tp.mtx.Unlock()
+
+	var forkHeight int32
+	for _, hash := range msg.BlockLocatorHashes {
+		if height, ok := tp.heights[*hash]; ok {
+			forkHeight = height
+			break
+		}
+	}
+	headers := wire.NewMsgHeaders()
+	for _, block := range tp.blocks[forkHeight+1:] {
+		header := block.Header
+		headers.AddBlockHeader(&header)
+	}
+	tp.write(headers)
+}
+
+func (tp *testPeer) onGetCFHeaders(msg *wire.MsgGetCFHeaders) {
+	tp.mtx.Lock()
+	defer tp.mtx.Unlock()
+
+	stop, ok := tp.heights[msg.StopHash]
+	if !ok {
+		return
+	}
+	resp := wire.NewMsgCFHeaders()
+	resp.FilterType = msg.FilterType
+	resp.StopHash = msg.StopHash
+	if msg.StartHeight > 0 {
+		resp.PrevFilterHeader = tp.filterHeaders[msg.StartHeight-1]
+	}
+	for _, filter := range tp.filters[msg.StartHeight : stop+1] {
+		filterHash := chainhash.DoubleHashH(filter)
+		resp.AddCFHash(&filterHash)
+	}
+	tp.write(resp)
+}
+
+func (tp *testPeer) onGetCFilters(msg *wire.MsgGetCFilters) {
+	tp.mtx.Lock()
+	defer tp.mtx.Unlock()
+
+	stop, ok := tp.heights[msg.StopHash]
+	if !ok {
+		return
+	}
+	for height := int32(msg.StartHeight); height <= stop; height++ {
+		hash := tp.blocks[height].BlockHash()
+		tp.write(wire.NewMsgCFilter(msg.FilterType, &hash,
+			tp.filters[height]))
+	}
+}
+
+func (tp *testPeer) onGetData(msg *wire.MsgGetData) {
+	tp.mtx.Lock()
+	defer tp.mtx.Unlock()
+
+	for _, iv := range msg.InvList {
+		height, ok := tp.heights[iv.Hash]
+		if !ok {
+			notFound := wire.NewMsgNotFound()
+			notFound.AddInvVect(iv)
+			tp.write(notFound)
+			continue
+		}
+		tp.write(tp.blocks[height])
+	}
+}
+
+// nextNotification returns the next notification sent by the client.
+func nextNotification(t *testing.T, c Interface) interface{} {
+	t.Helper()
+	select {
+	case n, ok := <-c.Notifications():
+		if !ok {
+			t.Fatal("notifications channel closed")
+		}
+		return n
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for notification")
+	}
+	return nil
+}
+
+func checkRelevantTx(t *testing.T, n interface{}, txHash chainhash.Hash,
+	block *wire.MsgBlock, height int32) {
+
+	t.Helper()
+	relevant, ok := n.(RelevantTx)
+	if !ok {
+		t.Fatalf("expected RelevantTx notification, got %T", n)
+	}
+	if relevant.TxRecord.Hash != txHash {
+		t.Fatalf("notified tx %v, want %v", relevant.TxRecord.Hash,
+			txHash)
+	}
+	if relevant.Block == nil || relevant.Block.Hash != block.BlockHash() ||
+		relevant.Block.Height != height {
+		t.Fatalf("tx notified in block %v, want block %v (height %d)",
+			relevant.Block, block.BlockHash(), height)
+	}
+}
+
+func checkBlockNotification(t *testing.T, n interface{}, connected bool,
+	block *wire.MsgBlock, height int32) {
+
+	t.Helper()
+	var hash chainhash.Hash
+	var notifiedHeight int32
+	switch n := n.(type) {
+	case BlockConnected:
+		if !connected {
+			t.Fatalf("expected BlockDisconnected notification, "+
+				"got %T", n)
+		}
+		hash, notifiedHeight = n.Hash, n.Height
+	case BlockDisconnected:
+		if connected {
+			t.Fatalf("expected BlockConnected notification, got %T",
+				n)
+		}
+		hash, notifiedHeight = n.Hash, n.Height
+	default:
+		t.Fatalf("expected block notification, got %T", n)
+	}
+	if hash != block.BlockHash() || notifiedHeight != height {
+		t.Fatalf("notified block %v (height %d), want %v (height %d)",
+			hash, notifiedHeight, block.BlockHash(), height)
+	}
+}
+
+func TestNeutrinoClient(t *testing.T) {
+	params := &chaincfg.RegressionNetParams
+	addr, err := btcutil.NewAddressPubKeyHash(make([]byte, 20), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addrScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherScript := []byte{txscript.OP_TRUE}
+
+	tp := newTestPeer(t, params)
+	defer tp.close()
+
+	// Mine a chain with a coinbase paying to the watched address in block
+	// 3, which is spent in block 6.
+	block := params.GenesisBlock
+	blocks := []*wire.MsgBlock{block}
+	var spend *wire.MsgTx
+	for height := 1; height <= 10; height++ {
+		var txs []*wire.MsgTx
+		payScript := otherScript
+		switch height {
+		case 3:
+			payScript = addrScript
+		case 6:
+			spend = wire.NewMsgTx(wire.TxVersion)
+			spend.AddTxIn(wire.NewTxIn(&wire.OutPoint{
+				Hash: blocks[3].Transactions[0].TxHash(),
+			}, nil, nil))
+			spend.AddTxOut(wire.NewTxOut(49e8, otherScript))
+			txs = append(txs, spend)
+		}
+		block = tp.mineBlock(block, payScript, txs...)
+		blocks = append(blocks, block)
+	}
+
+	c, err := NewNeutrinoClient(params, tp.listener.Addr().String(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.Start()
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer func() {
+		c.Stop()
+		c.WaitForShutdown()
+	}()
+	if _, ok := nextNotification(t, c).(ClientConnected); !ok {
+		t.Fatal("expected ClientConnected notification")
+	}
+
+	hash, height, err := c.GetBestBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *hash != blocks[10].BlockHash() || height != 10 {
+		t.Fatalf("best block %v (height %d), want %v (height 10)",
+			hash, height, blocks[10].BlockHash())
+	}
+	hash, err = c.GetBlockHash(6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *hash != blocks[6].BlockHash() {
+		t.Fatalf("block hash at height 6 is %v, want %v", hash,
+			blocks[6].BlockHash())
+	}
+
+	// Rescan the chain, finding the coinbase paying to the address and
+	// the transaction spending it.
+	err = c.NotifyBlocks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesisHash := params.GenesisBlock.BlockHash()
+	err = c.Rescan(&genesisHash, []btcutil.Address{addr}, nil)
+	if err != nil {
+		t.Fatalf("Rescan: %v", err)
+	}
+	checkRelevantTx(t, nextNotification(t, c),
+		blocks[3].Transactions[0].TxHash(), blocks[3], 3)
+	checkRelevantTx(t, nextNotification(t, c), spend.TxHash(), blocks[6], 6)
+	progress, ok := nextNotification(t, c).(*RescanProgress)
+	if !ok || progress.Height != 10 {
+		t.Fatalf("expected RescanProgress notification at height 10, "+
+			"got %v", progress)
+	}
+	finished, ok := nextNotification(t, c).(*RescanFinished)
+	if !ok || *finished.Hash != blocks[10].BlockHash() {
+		t.Fatalf("expected RescanFinished notification for block %v, "+
+			"got %v", blocks[10].BlockHash(), finished)
+	}
+
+	// Announce a new block paying to the address.
+	block11 := tp.mineBlock(blocks[10], addrScript)
+	tp.announce()
+	checkRelevantTx(t, nextNotification(t, c),
+		block11.Transactions[0].TxHash(), block11, 11)
+	checkBlockNotification(t, nextNotification(t, c), true, block11, 11)
+
+	// Reorganize the new block out of the chain, replacing it with two
+	// blocks, the second of which pays to the address.
+	tp.disconnectBlocks(10)
+	fork11 := tp.mineBlock(blocks[10], otherScript)
+	fork12 := tp.mineBlock(fork11, addrScript)
+	tp.announce()
+	checkBlockNotification(t, nextNotification(t, c), false, block11, 11)
+	checkBlockNotification(t, nextNotification(t, c), true, fork11, 11)
+	checkRelevantTx(t, nextNotification(t, c),
+		fork12.Transactions[0].TxHash(), fork12, 12)
+	checkBlockNotification(t, nextNotification(t, c), true, fork12, 12)
+
+	bs, err := c.BlockStamp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bs.Hash != fork12.BlockHash() || bs.Height != 12 {
+		t.Fatalf("block stamp %v (height %d), want %v (height 12)",
+			bs.Hash, bs.Height, fork12.BlockHash())
+	}
+}
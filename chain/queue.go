@@ -0,0 +1,54 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chain
+
+// queueNotifications forwards the notifications sent to enqueue to dequeue,
+// in order, queueing them while dequeue is not being read so that senders
+// never block on the reader.  The dequeue channel is closed when quit is
+// closed, or when enqueue is closed and all queued notifications have been
+// read.
+func queueNotifications(enqueue <-chan interface{}, dequeue chan<- interface{},
+	quit <-chan struct{}) {
+
+	var notifications []interface{}
+	var out chan<- interface{}
+	var next interface{}
+out:
+	for {
+		select {
+		case n, ok := <-enqueue:
+			if !ok {
+				if len(notifications) == 0 {
+					break out
+				}
+				// nil channel so no more reads can occur.
+				enqueue = nil
+				continue
+			}
+			if len(notifications) == 0 {
+				next = n
+				out = dequeue
+			}
+			notifications = append(notifications, n)
+
+		case out <- next:
+			notifications[0] = nil
+			notifications = notifications[1:]
+			if len(notifications) != 0 {
+				next = notifications[0]
+				continue
+			}
+			if enqueue == nil {
+				break out
+			}
+			next = nil
+			out = nil
+
+		case <-quit:
+			break out
+		}
+	}
+	close(dequeue)
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chain
+
+import (
+	"sync"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+)
+
+// watchList records the output scripts and outpoints watched for relevant
+// transactions by chain backends which match transactions on the client side.
+type watchList struct {
+	mtx       sync.Mutex
+	scripts   map[string]struct{}
+	outPoints map[wire.OutPoint]struct{}
+}
+
+func newWatchList() *watchList {
+	return &watchList{
+		scripts:   make(map[string]struct{}),
+		outPoints: make(map[wire.OutPoint]struct{}),
+	}
+}
+
+// add watches the output scripts of addrs and the outpoints.
+func (w *watchList) add(addrs []btcutil.Address, outPoints []*wire.OutPoint) error {
+	scripts := make([][]byte, len(addrs))
+	for i, addr := range addrs {
+		script, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			return err
+		}
+		scripts[i] = script
+	}
+
+	w.mtx.Lock()
+	for _, script := range scripts {
+		w.scripts[string(script)] = struct{}{}
+	}
+	for _, op := range outPoints {
+		w.outPoints[*op] = struct{}{}
+	}
+	w.mtx.Unlock()
+	return nil
+}
+
+// scriptList returns the watched scripts.
+func (w *watchList) scriptList() [][]byte {
+	w.mtx.Lock()
+	scripts := make([][]byte, 0, len(w.scripts))
+	for script := range w.scripts {
+		scripts = append(scripts, []byte(script))
+	}
+	w.mtx.Unlock()
+	return scripts
+}
+
+// relevant returns whether a transaction pays to a watched script or spends a
+// watched outpoint.  The outputs paying to watched scripts are watched for
+// spends from then on.
+func (w *watchList) relevant(tx *wire.MsgTx) bool {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	relevant := false
+	for _, in := range tx.TxIn {
+		if _, ok := w.outPoints[in.PreviousOutPoint]; ok {
+			relevant = true
+			break
+		}
+	}
+	txHash := tx.TxHash()
+	for i, out := range tx.TxOut {
+		if _, ok := w.scripts[string(out.PkScript)]; ok {
+			op := wire.OutPoint{Hash: txHash, Index: uint32(i)}
+			w.outPoints[op] = struct{}{}
+			relevant = true
+		}
+	}
+	return relevant
+}
@@ -0,0 +1,221 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chain
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+// ZMTP frame flags.
+const (
+	zmqFlagMore    = 0x01
+	zmqFlagLong    = 0x02
+	zmqFlagCommand = 0x04
+)
+
+// zmqGreetingLen is the length of the greeting exchanged by ZMTP 3.0 peers.
+const zmqGreetingLen = 64
+
+// zmqMaxFrameSize is the largest frame accepted from a publisher, which must
+// be able to hold a serialized block.
+const zmqMaxFrameSize = wire.MaxMessagePayload
+
+// zmqSubscriber is a ZeroMQ SUB socket connected to a single publisher, such
+// as the publishers of bitcoind's ZMQ notifications.  It implements the part
+// of version 3.0 of the ZeroMQ message transport protocol (ZMTP) needed to
+// subscribe to topics over TCP with the NULL security mechanism.
+type zmqSubscriber struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// zmqGreeting returns the ZMTP 3.0 greeting of a peer using the NULL security
+// mechanism.
+func zmqGreeting(asServer bool) []byte {
+	greeting := make([]byte, zmqGreetingLen)
+	greeting[0] = 0xff
+	greeting[9] = 0x7f
+	greeting[10] = 3 // Major version
+	greeting[11] = 0 // Minor version
+	copy(greeting[12:32], "NULL")
+	if asServer {
+		greeting[32] = 1
+	}
+	return greeting
+}
+
+// checkZMQGreeting checks that a greeting was sent by a ZMTP 3.x peer using
+// the NULL security mechanism.
+func checkZMQGreeting(greeting []byte) error {
+	if greeting[0] != 0xff || greeting[9]&0x01 != 0x01 {
+		return errors.New("invalid ZMTP greeting")
+	}
+	if greeting[10] < 3 {
+		return fmt.Errorf("unsupported ZMTP version %d.%d",
+			greeting[10], greeting[11])
+	}
+	mechanism := string(bytes.TrimRight(greeting[12:32], "\x00"))
+	if mechanism != "NULL" {
+		return fmt.Errorf("unsupported ZMTP security mechanism %q",
+			mechanism)
+	}
+	return nil
+}
+
+// zmqReadyCommand returns the body of a READY command announcing the socket
+// type.
+func zmqReadyCommand(socketType string) []byte {
+	var b bytes.Buffer
+	b.WriteByte(byte(len("READY")))
+	b.WriteString("READY")
+	b.WriteByte(byte(len("Socket-Type")))
+	b.WriteString("Socket-Type")
+	var valueLen [4]byte
+	binary.BigEndian.PutUint32(valueLen[:], uint32(len(socketType)))
+	b.Write(valueLen[:])
+	b.WriteString(socketType)
+	return b.Bytes()
+}
+
+// writeZMQFrame writes a single ZMTP frame.
+func writeZMQFrame(w io.Writer, flags byte, body []byte) error {
+	var header []byte
+	if len(body) > 0xff {
+		header = make([]byte, 9)
+		header[0] = flags | zmqFlagLong
+		binary.BigEndian.PutUint64(header[1:], uint64(len(body)))
+	} else {
+		header = []byte{flags, byte(len(body))}
+	}
+	_, err := w.Write(append(header, body...))
+	return err
+}
+
+// readZMQFrame reads a single ZMTP frame, returning its flags and body.
+func readZMQFrame(r *bufio.Reader) (byte, []byte, error) {
+	flags, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	var size uint64
+	if flags&zmqFlagLong != 0 {
+		var buf [8]byte
+		_, err = io.ReadFull(r, buf[:])
+		size = binary.BigEndian.Uint64(buf[:])
+	} else {
+		var b byte
+		b, err = r.ReadByte()
+		size = uint64(b)
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	if size > zmqMaxFrameSize {
+		return 0, nil, fmt.Errorf("ZMTP frame size %d exceeds the "+
+			"maximum of %d", size, zmqMaxFrameSize)
+	}
+	body := make([]byte, size)
+	_, err = io.ReadFull(r, body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return flags, body, nil
+}
+
+// dialZMQ connects to the ZeroMQ publisher at addr, which may be given as a
+// tcp:// endpoint like those configured with bitcoind's zmqpub* options, and
+// subscribes to the topics.
+func dialZMQ(addr string, topics []string, timeout time.Duration) (*zmqSubscriber, error) {
+	addr = strings.TrimPrefix(addr, "tcp://")
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return nil, err
+	}
+	s := &zmqSubscriber{conn: conn, r: bufio.NewReader(conn)}
+	err = s.handshake(topics, timeout)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// handshake exchanges greetings and READY commands with the publisher, and
+// subscribes to the topics.
+func (s *zmqSubscriber) handshake(topics []string, timeout time.Duration) error {
+	s.conn.SetDeadline(time.Now().Add(timeout))
+	defer s.conn.SetDeadline(time.Time{})
+
+	_, err := s.conn.Write(zmqGreeting(false))
+	if err != nil {
+		return err
+	}
+	greeting := make([]byte, zmqGreetingLen)
+	_, err = io.ReadFull(s.r, greeting)
+	if err != nil {
+		return err
+	}
+	err = checkZMQGreeting(greeting)
+	if err != nil {
+		return err
+	}
+
+	err = writeZMQFrame(s.conn, zmqFlagCommand, zmqReadyCommand("SUB"))
+	if err != nil {
+		return err
+	}
+	flags, body, err := readZMQFrame(s.r)
+	if err != nil {
+		return err
+	}
+	if flags&zmqFlagCommand == 0 || len(body) < 6 ||
+		string(body[1:6]) != "READY" {
+		return errors.New("expected ZMTP READY command")
+	}
+
+	// ZMTP 3.0 subscriptions are messages holding the topic prefixed by a
+	// byte of value one.
+	for _, topic := range topics {
+		err := writeZMQFrame(s.conn, 0, append([]byte{1}, topic...))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// receive returns the frames of the next message sent by the publisher.
+func (s *zmqSubscriber) receive() ([][]byte, error) {
+	var frames [][]byte
+	for {
+		flags, body, err := readZMQFrame(s.r)
+		if err != nil {
+			return nil, err
+		}
+		if flags&zmqFlagCommand != 0 {
+			continue
+		}
+		frames = append(frames, body)
+		if flags&zmqFlagMore == 0 {
+			return frames, nil
+		}
+	}
+}
+
+// close closes the connection to the publisher, causing pending receives to
+// fail.
+func (s *zmqSubscriber) close() error {
+	return s.conn.Close()
+}
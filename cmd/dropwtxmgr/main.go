@@ -13,20 +13,27 @@ import (
 	"github.com/btcsuite/btcutil"
 	"github.com/btcsuite/btcwallet/walletdb"
 	_ "github.com/btcsuite/btcwallet/walletdb/bdb"
+	"github.com/btcsuite/btcwallet/walletdb/encrypted"
 	"github.com/jessevdk/go-flags"
 )
 
 const defaultNet = "mainnet"
 
+// defaultPubPassphrase is the public passphrase of wallets created without
+// one.
+const defaultPubPassphrase = "public"
+
 var datadir = btcutil.AppDataDir("btcwallet", false)
 
 // Flags.
 var opts = struct {
-	Force  bool   `short:"f" description:"Force removal without prompt"`
-	DbPath string `long:"db" description:"Path to wallet database"`
+	Force      bool   `short:"f" description:"Force removal without prompt"`
+	DbPath     string `long:"db" description:"Path to wallet database"`
+	WalletPass string `long:"walletpass" default-mask:"-" description:"The public wallet password of an encrypted database -- Only required if the wallet was created with one"`
 }{
-	Force:  false,
-	DbPath: filepath.Join(datadir, defaultNet, "wallet.db"),
+	Force:      false,
+	DbPath:     filepath.Join(datadir, defaultNet, "wallet.db"),
+	WalletPass: defaultPubPassphrase,
 }
 
 func init() {
@@ -96,7 +103,11 @@ func mainInt() int {
 		fmt.Println("Enter yes or no.")
 	}
 
-	db, err := walletdb.Open("bdb", opts.DbPath)
+	db, err := walletdb.Open("encrypted", "bdb", []byte(opts.WalletPass),
+		opts.DbPath)
+	if err == encrypted.ErrNotEncrypted {
+		db, err = walletdb.Open("bdb", opts.DbPath)
+	}
 	if err != nil {
 		fmt.Println("Failed to open database:", err)
 		return 1
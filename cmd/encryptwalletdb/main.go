@@ -0,0 +1,186 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/walletdb"
+	_ "github.com/btcsuite/btcwallet/walletdb/bdb"
+	"github.com/btcsuite/btcwallet/walletdb/encrypted"
+	"github.com/jessevdk/go-flags"
+)
+
+const defaultNet = "mainnet"
+
+// defaultPubPassphrase is the public passphrase of wallets created without
+// one.
+const defaultPubPassphrase = "public"
+
+var datadir = btcutil.AppDataDir("btcwallet", false)
+
+// Flags.
+var opts = struct {
+	Force      bool   `short:"f" description:"Force encryption without prompt"`
+	DbPath     string `long:"db" description:"Path to wallet database"`
+	WalletPass string `long:"walletpass" default-mask:"-" description:"The public wallet password -- Only required if the wallet was created with one"`
+}{
+	Force:      false,
+	DbPath:     filepath.Join(datadir, defaultNet, "wallet.db"),
+	WalletPass: defaultPubPassphrase,
+}
+
+func init() {
+	_, err := flags.Parse(&opts)
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+// Namespace keys.
+var (
+	waddrmgrNamespace = []byte("waddrmgr")
+	wtxmgrNamespace   = []byte("wtxmgr")
+)
+
+func yes(s string) bool {
+	switch s {
+	case "y", "Y", "yes", "Yes":
+		return true
+	default:
+		return false
+	}
+}
+
+func no(s string) bool {
+	switch s {
+	case "n", "N", "no", "No":
+		return true
+	default:
+		return false
+	}
+}
+
+func main() {
+	os.Exit(mainInt())
+}
+
+func mainInt() int {
+	fmt.Println("Database path:", opts.DbPath)
+	_, err := os.Stat(opts.DbPath)
+	if os.IsNotExist(err) {
+		fmt.Println("Database file does not exist")
+		return 1
+	}
+
+	for !opts.Force {
+		fmt.Print("Encrypt the wallet database with the public passphrase? [y/N] ")
+
+		scanner := bufio.NewScanner(bufio.NewReader(os.Stdin))
+		if !scanner.Scan() {
+			// Exit on EOF.
+			return 0
+		}
+		err := scanner.Err()
+		if err != nil {
+			fmt.Println()
+			fmt.Println(err)
+			return 1
+		}
+		resp := scanner.Text()
+		if yes(resp) {
+			break
+		}
+		if no(resp) || resp == "" {
+			return 0
+		}
+
+		fmt.Println("Enter yes or no.")
+	}
+
+	pubPass := []byte(opts.WalletPass)
+	db, err := walletdb.Open("encrypted", "bdb", pubPass, opts.DbPath)
+	switch err {
+	case nil:
+		db.Close()
+		fmt.Println("Database is already encrypted")
+		return 0
+	case encrypted.ErrNotEncrypted:
+	default:
+		fmt.Println("Failed to open database:", err)
+		return 1
+	}
+
+	db, err = walletdb.Open("bdb", opts.DbPath)
+	if err != nil {
+		fmt.Println("Failed to open database:", err)
+		return 1
+	}
+	defer db.Close()
+
+	// The loader opens the encrypted database and the address manager with
+	// the same public passphrase, so a passphrase which does not open the
+	// address manager would leave the wallet impossible to open.  The
+	// network parameters are only used to encode addresses, so those of
+	// any network will do.
+	ns, err := db.Namespace(waddrmgrNamespace)
+	if err != nil {
+		fmt.Println("Failed to open address manager namespace:", err)
+		return 1
+	}
+	mgr, err := waddrmgr.Open(ns, pubPass, &chaincfg.MainNetParams, nil)
+	if waddrmgr.IsError(err, waddrmgr.ErrWrongPassphrase) {
+		fmt.Println("The public passphrase does not open the wallet.  " +
+			"Set it with --walletpass.")
+		return 1
+	}
+	if err != nil {
+		fmt.Println("Failed to open address manager:", err)
+		return 1
+	}
+	mgr.Close()
+
+	// The encrypted database is written next to the database and only
+	// replaces it once every namespace has been copied.
+	encPath := opts.DbPath + ".encrypted"
+	_, err = os.Stat(encPath)
+	if !os.IsNotExist(err) {
+		fmt.Println("Temporary database", encPath, "already exists")
+		return 1
+	}
+	encDB, err := walletdb.Create("encrypted", "bdb", pubPass, encPath)
+	if err != nil {
+		fmt.Println("Failed to create encrypted database:", err)
+		return 1
+	}
+	fmt.Println("Encrypting namespaces")
+	err = encrypted.Migrate(encDB, db, waddrmgrNamespace, wtxmgrNamespace)
+	if cerr := encDB.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(encPath)
+		fmt.Println("Failed to encrypt database:", err)
+		return 1
+	}
+
+	db.Close()
+	err = os.Rename(encPath, opts.DbPath)
+	if err != nil {
+		os.Remove(encPath)
+		fmt.Println("Failed to replace database:", err)
+		return 1
+	}
+	fmt.Println("The wallet database has been encrypted.  Backups made " +
+		"before encryption remain unencrypted.")
+
+	return 0
+}
@@ -5,6 +5,7 @@
 package main
 
 import (
+	"encoding/hex"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -134,13 +135,16 @@ func (noInputValue) Error() string { return "no input value" }
 
 // makeInputSource creates an InputSource that creates inputs for every unspent
 // output with non-zero output values.  The target amount is ignored since every
-// output is consumed.  The InputSource does not return any previous output
-// scripts as they are not needed for creating the unsinged transaction and are
-// looked up again by the wallet during the call to signrawtransaction.
+// output is consumed.  The previous output scripts and any redeem scripts are
+// only returned to estimate the transaction size, and are looked up again by
+// the wallet during the call to signrawtransaction.
 func makeInputSource(outputs []btcjson.ListUnspentResult) txauthor.InputSource {
 	var (
 		totalInputValue btcutil.Amount
 		inputs          = make([]*wire.TxIn, 0, len(outputs))
+		inputValues     = make([]btcutil.Amount, 0, len(outputs))
+		scripts         = make([][]byte, 0, len(outputs))
+		redeemScripts   = make([][]byte, 0, len(outputs))
 		sourceErr       error
 	)
 	for _, output := range outputs {
@@ -170,15 +174,37 @@ func makeInputSource(outputs []btcjson.ListUnspentResult) txauthor.InputSource {
 			break
 		}
 
-		inputs = append(inputs, wire.NewTxIn(&previousOutPoint, nil))
+		pkScript, err := hex.DecodeString(output.ScriptPubKey)
+		if err != nil {
+			sourceErr = fmt.Errorf(
+				"invalid script in listunspent result: %v",
+				err)
+			break
+		}
+
+		var redeemScript []byte
+		if output.RedeemScript != "" {
+			redeemScript, err = hex.DecodeString(output.RedeemScript)
+			if err != nil {
+				sourceErr = fmt.Errorf(
+					"invalid redeem script in listunspent result: %v",
+					err)
+				break
+			}
+		}
+
+		inputs = append(inputs, wire.NewTxIn(&previousOutPoint, nil, nil))
+		inputValues = append(inputValues, outputAmount)
+		scripts = append(scripts, pkScript)
+		redeemScripts = append(redeemScripts, redeemScript)
 	}
 
 	if sourceErr == nil && totalInputValue == 0 {
 		sourceErr = noInputValue{}
 	}
 
-	return func(btcutil.Amount) (btcutil.Amount, []*wire.TxIn, [][]byte, error) {
-		return totalInputValue, inputs, nil, sourceErr
+	return func(btcutil.Amount) (btcutil.Amount, []*wire.TxIn, []btcutil.Amount, [][]byte, [][]byte, error) {
+		return totalInputValue, inputs, inputValues, scripts, redeemScripts, sourceErr
 	}
 }
 
@@ -19,6 +19,7 @@ import (
 	"github.com/btcsuite/btcwallet/internal/legacy/keystore"
 	"github.com/btcsuite/btcwallet/netparams"
 	"github.com/btcsuite/btcwallet/wallet"
+	"github.com/btcsuite/btcwallet/wallet/txrules"
 	flags "github.com/jessevdk/go-flags"
 )
 
@@ -30,6 +31,7 @@ const (
 	defaultLogFilename      = "btcwallet.log"
 	defaultRPCMaxClients    = 10
 	defaultRPCMaxWebsockets = 25
+	defaultMaxFeeRate       = 1e6 // satoshis per kB
 
 	walletDbName = "wallet.db"
 )
@@ -49,6 +51,7 @@ type config struct {
 	ShowVersion   bool                    `short:"V" long:"version" description:"Display version information and exit"`
 	Create        bool                    `long:"create" description:"Create the wallet if it does not exist"`
 	CreateTemp    bool                    `long:"createtemp" description:"Create a temporary simulation wallet (pass=password) in the data directory indicated; must call with --datadir"`
+	MemDB         bool                    `long:"memdb" description:"Keep the temporary simulation wallet in memory instead of the data directory; requires --createtemp"`
 	AppDataDir    *cfgutil.ExplicitString `short:"A" long:"appdata" description:"Application data directory for wallet config, databases and logs"`
 	TestNet3      bool                    `long:"testnet" description:"Use the test Bitcoin network (version 3) (default mainnet)"`
 	SimNet        bool                    `long:"simnet" description:"Use the simulation test network (default mainnet)"`
@@ -58,7 +61,13 @@ type config struct {
 	Profile       string                  `long:"profile" description:"Enable HTTP profiling on given port -- NOTE port must be between 1024 and 65536"`
 
 	// Wallet options
-	WalletPass string `long:"walletpass" default-mask:"-" description:"The public wallet password -- Only required if the wallet was created with one"`
+	WalletPass     string              `long:"walletpass" default-mask:"-" description:"The public wallet password -- Only required if the wallet was created with one"`
+	FeeConfTarget  uint32              `long:"feeconftarget" description:"Number of blocks within which sent transactions are estimated to confirm"`
+	MinFeeRate     *cfgutil.AmountFlag `long:"minfeerate" description:"Fee per kilobyte used when no fee estimate is available, and the lowest estimated fee per kilobyte"`
+	MaxFeeRate     *cfgutil.AmountFlag `long:"maxfeerate" description:"Highest estimated fee per kilobyte -- 0 does not limit estimates"`
+	RecoveryWindow uint32              `long:"recoverywindow" description:"Number of unused addresses derived after the last used address of each account branch when discovering the addresses and accounts of a wallet restored from its seed, and after the last used address of each branch of watch-only accounts -- 0 disables discovery and uses a gap limit of 20 for watch-only accounts"`
+	Wallets        []string            `long:"wallet" description:"Name of a wallet in the wallets directory to load at startup in addition to the default wallet -- May be repeated"`
+	Argon2id       bool                `long:"argon2id" description:"Derive the keys of new wallets and changed passphrases from the passphrases with Argon2id instead of scrypt -- Wallets using scrypt are migrated when their passphrases are changed"`
 
 	// RPC client options
 	RPCConnect       string                  `short:"c" long:"rpcconnect" description:"Hostname/IP and port of btcd RPC server to connect to (default localhost:8334, testnet: localhost:18334, simnet: localhost:18556)"`
@@ -70,6 +79,12 @@ type config struct {
 	ProxyUser        string                  `long:"proxyuser" description:"Username for proxy server"`
 	ProxyPass        string                  `long:"proxypass" default-mask:"-" description:"Password for proxy server"`
 
+	// Chain backend options
+	ChainBackend    string `long:"chainbackend" description:"Chain backend used to sync the wallet {btcd, bitcoind, neutrino} -- bitcoind is connected to with the rpcconnect, btcdusername and btcdpassword options"`
+	NeutrinoConnect string `long:"neutrinoconnect" description:"Hostname/IP and port of a full node serving compact block filters to the neutrino backend (default port: 8333, testnet: 18333, simnet: 18555)"`
+	ZMQPubRawBlock  string `long:"zmqpubrawblock" description:"ZMQ endpoint bitcoind publishes rawblock notifications to (eg. tcp://127.0.0.1:28332)"`
+	ZMQPubRawTx     string `long:"zmqpubrawtx" description:"ZMQ endpoint bitcoind publishes rawtx notifications to (default: the zmqpubrawblock endpoint)"`
+
 	// RPC server options
 	//
 	// The legacy server is still enabled by default (and eventually will be
@@ -251,6 +266,10 @@ func loadConfig() (*config, []string, error) {
 		AppDataDir:             cfgutil.NewExplicitString(defaultAppDataDir),
 		LogDir:                 defaultLogDir,
 		WalletPass:             wallet.InsecurePubPassphrase,
+		FeeConfTarget:          wallet.DefaultFeeConfTarget,
+		MinFeeRate:             cfgutil.NewAmountFlag(txrules.DefaultRelayFeePerKb),
+		MaxFeeRate:             cfgutil.NewAmountFlag(defaultMaxFeeRate),
+		ChainBackend:           "btcd",
 		CAFile:                 cfgutil.NewExplicitString(""),
 		RPCKey:                 cfgutil.NewExplicitString(defaultRPCKeyFile),
 		RPCCert:                cfgutil.NewExplicitString(defaultRPCCertFile),
@@ -381,9 +400,21 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// An in-memory wallet is only supported for simulation wallets, which
+	// are created when the wallet is loaded at startup.
+	if cfg.MemDB && (!cfg.CreateTemp || cfg.NoInitialLoad) {
+		err := fmt.Errorf("%s: the --memdb option requires --createtemp "+
+			"and may not be used with --noinitialload", funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
 	// Exit if you try to use a simulation wallet with a standard
-	// data directory.
-	if !(cfg.AppDataDir.ExplicitlySet() || cfg.DataDir.ExplicitlySet()) && cfg.CreateTemp {
+	// data directory.  In-memory simulation wallets are never written
+	// to the data directory, so they may use the standard one.
+	if !(cfg.AppDataDir.ExplicitlySet() || cfg.DataDir.ExplicitlySet()) &&
+		cfg.CreateTemp && !cfg.MemDB {
 		fmt.Fprintln(os.Stderr, "Tried to create a temporary simulation "+
 			"wallet, but failed to specify data directory!")
 		os.Exit(0)
@@ -397,6 +428,24 @@ func loadConfig() (*config, []string, error) {
 		os.Exit(0)
 	}
 
+	// Ensure the fee estimation options are sane.
+	if cfg.FeeConfTarget == 0 {
+		str := "%s: the --feeconftarget option must be positive"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+	if cfg.MinFeeRate.Amount < 0 || cfg.MaxFeeRate.Amount < 0 ||
+		(cfg.MaxFeeRate.Amount != 0 && cfg.MinFeeRate.Amount > cfg.MaxFeeRate.Amount) {
+		str := "%s: the --minfeerate and --maxfeerate options must not " +
+			"be negative, and --minfeerate may not exceed --maxfeerate"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
 	// Ensure the wallet exists or create it when the create flag is set.
 	netDir := networkDir(cfg.AppDataDir.Value, activeNet.Params)
 	dbPath := filepath.Join(netDir, walletDbName)
@@ -414,7 +463,9 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
-	if cfg.CreateTemp {
+	// In-memory simulation wallets are created when the wallet is loaded,
+	// since they do not outlive the process.
+	if cfg.CreateTemp && !cfg.MemDB {
 		tempWalletExists := false
 
 		if dbFileExists {
@@ -461,7 +512,7 @@ func loadConfig() (*config, []string, error) {
 
 		// Created successfully, so exit now with success.
 		os.Exit(0)
-	} else if !dbFileExists && !cfg.NoInitialLoad {
+	} else if !dbFileExists && !cfg.NoInitialLoad && !cfg.CreateTemp {
 		keystorePath := filepath.Join(netDir, keystore.Filename)
 		keystoreExists, err := cfgutil.FileExists(keystorePath)
 		if err != nil {
@@ -479,6 +530,47 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// Check the options of the chain backend.  The RPC client options
+	// default to connecting to a local btcd, so bitcoind's address must be
+	// set explicitly.
+	switch cfg.ChainBackend {
+	case "btcd":
+	case "bitcoind":
+		if cfg.RPCConnect == "" || cfg.ZMQPubRawBlock == "" {
+			str := "%s: the bitcoind chain backend requires the " +
+				"--rpcconnect and --zmqpubrawblock options"
+			err := fmt.Errorf(str, funcName)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+		if cfg.ZMQPubRawTx == "" {
+			cfg.ZMQPubRawTx = cfg.ZMQPubRawBlock
+		}
+	case "neutrino":
+		if cfg.NeutrinoConnect == "" {
+			str := "%s: the neutrino chain backend requires the " +
+				"--neutrinoconnect option"
+			err := fmt.Errorf(str, funcName)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+		cfg.NeutrinoConnect, err = cfgutil.NormalizeAddress(
+			cfg.NeutrinoConnect, activeNet.Params.DefaultPort)
+		if err != nil {
+			fmt.Fprintf(os.Stderr,
+				"Invalid neutrinoconnect network address: %v\n", err)
+			return nil, nil, err
+		}
+	default:
+		str := "%s: unknown chain backend %q"
+		err := fmt.Errorf(str, funcName, cfg.ChainBackend)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
 	if cfg.RPCConnect == "" {
 		cfg.RPCConnect = net.JoinHostPort("localhost", activeNet.RPCClientPort)
 	}
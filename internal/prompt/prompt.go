@@ -14,34 +14,14 @@ import (
 
 	"github.com/btcsuite/btcutil/hdkeychain"
 	"github.com/btcsuite/btcwallet/internal/legacy/keystore"
+	"github.com/btcsuite/btcwallet/wallet/bip39"
 	"github.com/btcsuite/golangcrypto/ssh/terminal"
 )
 
 // ProvideSeed is used to prompt for the wallet seed which maybe required during
 // upgrades.
 func ProvideSeed() ([]byte, error) {
-	reader := bufio.NewReader(os.Stdin)
-	for {
-		fmt.Print("Enter existing wallet seed: ")
-		seedStr, err := reader.ReadString('\n')
-		if err != nil {
-			return nil, err
-		}
-		seedStr = strings.TrimSpace(strings.ToLower(seedStr))
-
-		seed, err := hex.DecodeString(seedStr)
-		if err != nil || len(seed) < hdkeychain.MinSeedBytes ||
-			len(seed) > hdkeychain.MaxSeedBytes {
-
-			fmt.Printf("Invalid seed specified.  Must be a "+
-				"hexadecimal value that is at least %d bits and "+
-				"at most %d bits\n", hdkeychain.MinSeedBytes*8,
-				hdkeychain.MaxSeedBytes*8)
-			continue
-		}
-
-		return seed, nil
-	}
+	return existingSeed(bufio.NewReader(os.Stdin))
 }
 
 // ProvidePrivPassphrase is used to prompt for the private passphrase which
@@ -256,10 +236,11 @@ func PublicPass(reader *bufio.Reader, privPass []byte,
 }
 
 // Seed prompts the user whether they want to use an existing wallet generation
-// seed.  When the user answers no, a seed will be generated and displayed to
-// the user along with prompting them for confirmation.  When the user answers
-// yes, a the user is prompted for it.  All prompts are repeated until the user
-// enters a valid response.
+// seed.  When the user answers no, a BIP0039 mnemonic will be generated and
+// displayed to the user along with prompting them for an optional mnemonic
+// passphrase and confirmation.  When the user answers yes, the user is
+// prompted for either a hex seed or a mnemonic.  All prompts are repeated
+// until the user enters a valid response.
 func Seed(reader *bufio.Reader) ([]byte, error) {
 	// Ascertain the wallet generation seed.
 	useUserSeed, err := promptListBool(reader, "Do you have an "+
@@ -267,57 +248,110 @@ func Seed(reader *bufio.Reader) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	if !useUserSeed {
-		seed, err := hdkeychain.GenerateSeed(hdkeychain.RecommendedSeedLen)
+	if useUserSeed {
+		return existingSeed(reader)
+	}
+
+	entropy, err := bip39.NewEntropy(bip39.RecommendedEntropyBits)
+	if err != nil {
+		return nil, err
+	}
+	mnemonic, err := bip39.NewMnemonic(entropy)
+	if err != nil {
+		return nil, err
+	}
+	passphrase, err := mnemonicPassphrase(reader, "Do you want to "+
+		"protect the mnemonic with an additional passphrase?")
+	if err != nil {
+		return nil, err
+	}
+	seed, err := bip39.Seed(mnemonic, string(passphrase))
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Println("Your wallet generation mnemonic is:")
+	fmt.Println(mnemonic)
+	fmt.Println("IMPORTANT: Keep the mnemonic in a safe place as you\n" +
+		"will NOT be able to restore your wallet without it.")
+	if len(passphrase) != 0 {
+		fmt.Println("The mnemonic passphrase is also required to\n" +
+			"restore your wallet.  Keep it separately from the\n" +
+			"mnemonic.")
+	}
+	fmt.Println("Please keep in mind that anyone who has access\n" +
+		"to the mnemonic can also restore your wallet thereby\n" +
+		"giving them access to all your funds, so it is\n" +
+		"imperative that you keep it in a secure location.")
+
+	for {
+		fmt.Print(`Once you have stored the mnemonic in a safe ` +
+			`and secure location, enter "OK" to continue: `)
+		confirmSeed, err := reader.ReadString('\n')
 		if err != nil {
 			return nil, err
 		}
-
-		fmt.Println("Your wallet generation seed is:")
-		fmt.Printf("%x\n", seed)
-		fmt.Println("IMPORTANT: Keep the seed in a safe place as you\n" +
-			"will NOT be able to restore your wallet without it.")
-		fmt.Println("Please keep in mind that anyone who has access\n" +
-			"to the seed can also restore your wallet thereby\n" +
-			"giving them access to all your funds, so it is\n" +
-			"imperative that you keep it in a secure location.")
-
-		for {
-			fmt.Print(`Once you have stored the seed in a safe ` +
-				`and secure location, enter "OK" to continue: `)
-			confirmSeed, err := reader.ReadString('\n')
-			if err != nil {
-				return nil, err
-			}
-			confirmSeed = strings.TrimSpace(confirmSeed)
-			confirmSeed = strings.Trim(confirmSeed, `"`)
-			if confirmSeed == "OK" {
-				break
-			}
+		confirmSeed = strings.TrimSpace(confirmSeed)
+		confirmSeed = strings.Trim(confirmSeed, `"`)
+		if confirmSeed == "OK" {
+			break
 		}
-
-		return seed, nil
 	}
 
+	return seed, nil
+}
+
+// existingSeed prompts the user for an existing wallet seed, which may be
+// entered as either a hexadecimal seed or a BIP0039 mnemonic.  The checksum of
+// a mnemonic is validated, and the user is prompted for the mnemonic
+// passphrase, if any.  The prompt is repeated until a valid seed is entered.
+func existingSeed(reader *bufio.Reader) ([]byte, error) {
 	for {
-		fmt.Print("Enter existing wallet seed: ")
+		fmt.Print("Enter existing wallet seed or mnemonic: ")
 		seedStr, err := reader.ReadString('\n')
 		if err != nil {
 			return nil, err
 		}
 		seedStr = strings.TrimSpace(strings.ToLower(seedStr))
 
+		// A seed with several words is a mnemonic.
+		if len(strings.Fields(seedStr)) > 1 {
+			_, err := bip39.EntropyFromMnemonic(seedStr)
+			if err != nil {
+				fmt.Printf("Invalid mnemonic specified: %v\n", err)
+				continue
+			}
+			passphrase, err := mnemonicPassphrase(reader, "Does the "+
+				"mnemonic have an additional passphrase?")
+			if err != nil {
+				return nil, err
+			}
+			return bip39.Seed(seedStr, string(passphrase))
+		}
+
 		seed, err := hex.DecodeString(seedStr)
 		if err != nil || len(seed) < hdkeychain.MinSeedBytes ||
 			len(seed) > hdkeychain.MaxSeedBytes {
 
 			fmt.Printf("Invalid seed specified.  Must be a "+
 				"hexadecimal value that is at least %d bits and "+
-				"at most %d bits\n", hdkeychain.MinSeedBytes*8,
-				hdkeychain.MaxSeedBytes*8)
+				"at most %d bits, or a BIP0039 mnemonic\n",
+				hdkeychain.MinSeedBytes*8, hdkeychain.MaxSeedBytes*8)
 			continue
 		}
 
 		return seed, nil
 	}
 }
+
+// mnemonicPassphrase prompts the user whether a mnemonic has a passphrase,
+// sometimes called the 25th word, and prompts for it when the user answers
+// yes.  Since every passphrase derives a valid seed, the passphrase must be
+// confirmed.  A nil passphrase is returned when the user answers no.
+func mnemonicPassphrase(reader *bufio.Reader, question string) ([]byte, error) {
+	usePassphrase, err := promptListBool(reader, question, "no")
+	if err != nil || !usePassphrase {
+		return nil, err
+	}
+	return promptPass(reader, "Enter the mnemonic passphrase", true)
+}
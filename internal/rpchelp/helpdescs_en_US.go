@@ -2,7 +2,8 @@
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
-//+build !generate
+//go:build !generate
+// +build !generate
 
 package rpchelp
 
@@ -14,6 +15,23 @@ var helpDescsEnUS = map[string]string{
 	"addmultisigaddress-nrequired": "The number of signatures required to redeem outputs paid to this address",
 	"addmultisigaddress--result0":  "The imported pay-to-script-hash address",
 
+	// BackupWalletCmd help.
+	"backupwallet--synopsis": "Writes a copy of the wallet database to a file.\n" +
+		"The copy is consistent while the wallet is running and may be restored with 'restorewallet'.",
+	"backupwallet-destination": "The file to write the backup to, or a directory to write a file named wallet.db to",
+	"backupwallet-passphrase":  "Passphrase to encrypt the backup with (default is to write the database unencrypted)",
+
+	// BumpFeeCmd help.
+	"bumpfee--synopsis": "Replaces an unconfirmed wallet transaction which signals replaceability (BIP0125) with one paying a higher fee.\n" +
+		"The fee is paid by decreasing the change output, and additional inputs are added when the change is insufficient.",
+	"bumpfee-txid":    "The hash of the transaction to replace",
+	"bumpfee-feerate": "The fee rate of the replacement in bitcoin per kilobyte (default is the wallet's estimated fee rate)",
+
+	// BumpFeeResult help.
+	"bumpfeeresult-txid":    "The hash of the replacement transaction",
+	"bumpfeeresult-origfee": "The fee of the replaced transaction in bitcoin",
+	"bumpfeeresult-fee":     "The fee of the replacement transaction in bitcoin",
+
 	// CreateMultisigCmd help.
 	"createmultisig--synopsis": "Generate a multisig address and redeem script.",
 	"createmultisig-keys":      "Pubkeys and/or pay-to-pubkey-hash addresses to partially control the multisig address",
@@ -28,6 +46,25 @@ var helpDescsEnUS = map[string]string{
 	"dumpprivkey-address":   "The address to return a private key for",
 	"dumpprivkey--result0":  "The WIF-encoded private key",
 
+	// DumpWalletCmd help.
+	"dumpwallet--synopsis": "Writes the private keys and scripts of all wallet addresses to a new file in a human readable format.\n" +
+		"Each key is written with its account and, for keys derived from the wallet seed, its HD key path.  Requires the wallet to be unlocked.",
+	"dumpwallet-filename": "The file to write the dump to, which must not already exist",
+
+	// DumpWalletResult help.
+	"dumpwalletresult-filename": "The absolute path of the written file",
+
+	// FinalizePsbtCmd help.
+	"finalizepsbt--synopsis": "Finalizes the inputs of a base64 encoded partially signed transaction (BIP0174) which have all required signatures.\n" +
+		"When every input is finalized and extract is true, the signed transaction is returned instead of the PSBT.",
+	"finalizepsbt-psbt":    "The base64 encoded PSBT",
+	"finalizepsbt-extract": "Return the signed transaction if the PSBT is complete",
+
+	// FinalizePsbtResult help.
+	"finalizepsbtresult-psbt":     "The base64 encoded PSBT, unless the signed transaction was extracted",
+	"finalizepsbtresult-hex":      "The extracted signed transaction encoded as a hexadecimal string",
+	"finalizepsbtresult-complete": "Whether every input is finalized",
+
 	// GetAccountCmd help.
 	"getaccount--synopsis": "DEPRECATED -- Lookup the account name that some wallet address belongs to.",
 	"getaccount-address":   "The address to query the account for",
@@ -83,9 +120,11 @@ var helpDescsEnUS = map[string]string{
 	"infowalletresult-keypoololdest":   "Unset",
 
 	// GetNewAddressCmd help.
-	"getnewaddress--synopsis": "Generates and returns a new payment address.",
-	"getnewaddress-account":   "DEPRECATED -- Account name the new address will belong to (default=\"default\")",
-	"getnewaddress--result0":  "The payment address",
+	"getnewaddress--synopsis": "Generates and returns a new payment address.\n" +
+		"An optional second parameter names the expected address type (\"legacy\", \"p2sh-segwit\" or \"bech32\"), which must be the address type of the account since accounts derive addresses of a single type.\n" +
+		"By default, the address type of the account is used.",
+	"getnewaddress-account":  "DEPRECATED -- Account name the new address will belong to (default=\"default\")",
+	"getnewaddress--result0": "The payment address",
 
 	// GetRawChangeAddressCmd help.
 	"getrawchangeaddress--synopsis": "Generates and returns a new internal payment address for use as a change address in raw transactions.",
@@ -140,12 +179,40 @@ var helpDescsEnUS = map[string]string{
 	"gettransactiondetailsresult-vout":              "The transaction output index",
 	"gettransactiondetailsresult-involveswatchonly": "Unset",
 
+	// GetWalletInfoCmd help.
+	"getwalletinfo--synopsis": "Returns a JSON object containing the balances, transaction count, key pools, and lock state of the wallet.",
+
+	// GetWalletInfoResult help.
+	"getwalletinforesult-walletname":              "The name of the wallet",
+	"getwalletinforesult-walletversion":           "The version of the address manager database",
+	"getwalletinforesult-balance":                 "The spendable balance of all accounts calculated with one block confirmation",
+	"getwalletinforesult-unconfirmed_balance":     "The value of all unmined unspent outputs",
+	"getwalletinforesult-immature_balance":        "The value of all immature coinbase outputs",
+	"getwalletinforesult-txcount":                 "The number of transactions recorded by the wallet",
+	"getwalletinforesult-keypoolsize":             "The number of derived external addresses of all accounts which have not been used",
+	"getwalletinforesult-keypoolsize_hd_internal": "The number of derived internal (change) addresses of all accounts which have not been used",
+	"getwalletinforesult-unlocked_until":          "The Unix time the wallet will be locked at, or 0 if the wallet is locked (omitted when unlocked without a time limit)",
+	"getwalletinforesult-paytxfee":                "The increment used each time more fee is required for an authored transaction",
+	"getwalletinforesult-relayfee":                "The minimum relay fee for non-free transactions in BTC/KB",
+	"getwalletinforesult-accounts":                "The key pool of each account",
+
+	// GetWalletInfoAccountResult help.
+	"getwalletinfoaccountresult-account":                 "The name of the account",
+	"getwalletinfoaccountresult-keypoolsize":             "The number of derived external addresses of the account which have not been used",
+	"getwalletinfoaccountresult-keypoolsize_hd_internal": "The number of derived internal (change) addresses of the account which have not been used",
+
 	// ImportPrivKeyCmd help.
 	"importprivkey--synopsis": "Imports a WIF-encoded private key to the 'imported' account.",
 	"importprivkey-privkey":   "The WIF-encoded private key",
 	"importprivkey-label":     "Unused (must be unset or 'imported')",
 	"importprivkey-rescan":    "Rescan the blockchain (since the genesis block) for outputs controlled by the imported key",
 
+	// ImportWalletCmd help.
+	"importwallet--synopsis": "Imports the private keys and scripts of a file written by 'dumpwallet'.\n" +
+		"Keys with the HD key path of a wallet account are recovered by deriving the account's addresses, while other keys are imported to the 'imported' account.\n" +
+		"The blockchain is rescanned for the new addresses in the background.  Requires the wallet to be unlocked.",
+	"importwallet-filename": "The file to import",
+
 	// KeypoolRefillCmd help.
 	"keypoolrefill--synopsis": "DEPRECATED -- This request does nothing since no keypool is maintained.",
 	"keypoolrefill-newsize":   "Unused",
@@ -157,8 +224,19 @@ var helpDescsEnUS = map[string]string{
 	"listaccounts--result0--key":   "The account name",
 	"listaccounts--result0--value": "The account balance valued in bitcoin",
 
+	// ListAddressGroupingsCmd help.
+	"listaddressgroupings--synopsis": "Returns a JSON array of address groupings.\n" +
+		"Addresses spent together as inputs of a transaction, and the change outputs of such transactions, are assumed to share an owner and are placed in the same grouping.",
+	"listaddressgroupings--result0": "An array of groupings, each an array of addresses described by an array of the address, its unspent balance valued in bitcoin, and its account name",
+
 	// ListLockUnspentCmd help.
-	"listlockunspent--synopsis": "Returns a JSON array of outpoints marked as locked (with lockunspent) for this wallet session.",
+	"listlockunspent--synopsis": "Returns a JSON array of outpoints marked as locked (with lockunspent).",
+
+	// ListLockUnspentResult help.
+	"listlockunspentresult-txid":       "The transaction hash of the locked output",
+	"listlockunspentresult-vout":       "The output index of the locked output",
+	"listlockunspentresult-lockid":     "The lock ID recorded when the output was locked (omitted if empty)",
+	"listlockunspentresult-expiration": "The Unix time at which the lock expires (omitted if the lock does not expire)",
 
 	// TransactionInput help.
 	"transactioninput-txid": "The transaction hash of the referenced output",
@@ -220,7 +298,7 @@ var helpDescsEnUS = map[string]string{
 	"listtransactionsresult-time":               "The earliest Unix time this transaction was known to exist",
 	"listtransactionsresult-timereceived":       "The earliest Unix time this transaction was known to exist",
 	"listtransactionsresult-involveswatchonly":  "Unset",
-	"listtransactionsresult-comment":            "Unset",
+	"listtransactionsresult-comment":            "The label of the output, or the label of the transaction if the output is not labeled",
 	"listtransactionsresult-otheraccount":       "Unset",
 	"listtransactionsresult-trusted":            "Unset",
 	"listtransactionsresult-bip125-replaceable": "Unset",
@@ -250,15 +328,36 @@ var helpDescsEnUS = map[string]string{
 	"listunspentresult-confirmations": "The number of block confirmations of the transaction",
 	"listunspentresult-spendable":     "Whether the output is entirely controlled by wallet keys/scripts (false for partially controlled multisig outputs or outputs to watch-only addresses)",
 
+	// ListWalletsCmd help.
+	"listwallets--synopsis": "Returns the names of the loaded wallets.\n" +
+		"The default wallet is named by the empty string.  Requests for a wallet are made to the /wallet/<name> URL path.",
+	"listwallets--result0": "The names of the loaded wallets",
+
+	// LoadWalletCmd help.
+	"loadwallet--synopsis": "Loads a wallet from the wallets directory of the application data directory.\n" +
+		"The wallet is opened with the public passphrase of the server and synced with the chain backend shared by all loaded wallets.",
+	"loadwallet-filename": "The name of the wallet",
+
+	// LoadWalletResult help.
+	"loadwalletresult-name":    "The name of the loaded wallet",
+	"loadwalletresult-warning": "Warning message if the wallet was not loaded cleanly",
+
 	// LockUnspentCmd help.
 	"lockunspent--synopsis": "Locks or unlocks an unspent output.\n" +
 		"Locked outputs are not chosen for transaction inputs of authored transactions and are not included in 'listunspent' results.\n" +
-		"Locked outputs are volatile and are not saved across wallet restarts.\n" +
-		"If unlock is true and no transaction outputs are specified, all locked outputs are marked unlocked.",
+		"Locked outputs are saved in the wallet database and remain locked across wallet restarts.\n" +
+		"If unlock is true and no transaction outputs are specified, all locked outputs are marked unlocked.\n" +
+		"When locking, an optional third parameter records a free-form lock ID for the locked outputs, and an optional fourth parameter sets the number of seconds after which the locks expire (default is 0, never expiring).",
 	"lockunspent-unlock":       "True to unlock outputs, false to lock",
 	"lockunspent-transactions": "Transaction outputs to lock or unlock",
 	"lockunspent--result0":     "The boolean 'true'",
 
+	// RestoreWalletCmd help.
+	"restorewallet--synopsis":  "Creates a named wallet in the wallets directory from a file written by 'backupwallet', and loads it.",
+	"restorewallet-walletname": "The name of the new wallet",
+	"restorewallet-backupfile": "The backup file to restore",
+	"restorewallet-passphrase": "Passphrase of an encrypted backup",
+
 	// SendFromCmd help.
 	"sendfrom--synopsis": "DEPRECATED -- Authors, signs, and sends a transaction that outputs some amount to a payment address.\n" +
 		"A change output is automatically included to send extra output value back to the original account.",
@@ -266,20 +365,22 @@ var helpDescsEnUS = map[string]string{
 	"sendfrom-toaddress":   "Address to pay",
 	"sendfrom-amount":      "Amount to send to the payment address valued in bitcoin",
 	"sendfrom-minconf":     "Minimum number of block confirmations required before a transaction output is eligible to be spent",
-	"sendfrom-comment":     "Unused",
-	"sendfrom-commentto":   "Unused",
+	"sendfrom-comment":     "A comment saved as the transaction's label",
+	"sendfrom-commentto":   "A comment describing the recipient, saved as the label of the output paying the recipient",
 	"sendfrom--result0":    "The transaction hash of the sent transaction",
 
 	// SendManyCmd help.
 	"sendmany--synopsis": "Authors, signs, and sends a transaction that outputs to many payment addresses.\n" +
-		"A change output is automatically included to send extra output value back to the original account.",
+		"A change output is automatically included to send extra output value back to the original account.\n" +
+		"An optional fifth parameter sets the fee rate in bitcoin per kilobyte, overriding the fee rate estimated by the wallet.\n" +
+		"An optional sixth parameter selects the coin selection strategy (\"largest-first\", \"branch-and-bound\", \"oldest-first\" or \"random-improve\"), defaulting to \"largest-first\".",
 	"sendmany-fromaccount":    "DEPRECATED -- Account to pick unspent outputs from",
 	"sendmany-amounts":        "Pairs of payment addresses and the output amount to pay each",
 	"sendmany-amounts--desc":  "JSON object using payment addresses as keys and output amounts valued in bitcoin to send to each address",
 	"sendmany-amounts--key":   "Address to pay",
 	"sendmany-amounts--value": "Amount to send to the payment address valued in bitcoin",
 	"sendmany-minconf":        "Minimum number of block confirmations required before a transaction output is eligible to be spent",
-	"sendmany-comment":        "Unused",
+	"sendmany-comment":        "A comment saved as the transaction's label",
 	"sendmany--result0":       "The transaction hash of the sent transaction",
 
 	// SendToAddressCmd help.
@@ -288,8 +389,8 @@ var helpDescsEnUS = map[string]string{
 		"A change output is automatically included to send extra output value back to the original account.",
 	"sendtoaddress-address":   "Address to pay",
 	"sendtoaddress-amount":    "Amount to send to the payment address valued in bitcoin",
-	"sendtoaddress-comment":   "Unused",
-	"sendtoaddress-commentto": "Unused",
+	"sendtoaddress-comment":   "A comment saved as the transaction's label",
+	"sendtoaddress-commentto": "A comment describing the recipient, saved as the label of the output paying the recipient",
 	"sendtoaddress--result0":  "The transaction hash of the sent transaction",
 
 	// SetTxFeeCmd help.
@@ -298,10 +399,11 @@ var helpDescsEnUS = map[string]string{
 	"settxfee--result0":  "The boolean 'true'",
 
 	// SignMessageCmd help.
-	"signmessage--synopsis": "Signs a message using the private key of a payment address.",
-	"signmessage-address":   "Payment address of private key used to sign the message with",
-	"signmessage-message":   "Message to sign",
-	"signmessage--result0":  "The signed message encoded as a base64 string",
+	"signmessage--synopsis": "Signs a message using the private key of a payment address.\n" +
+		"Messages of P2PKH addresses are signed in the legacy format, and messages of all other addresses with BIP0322.",
+	"signmessage-address":  "Payment address of private key used to sign the message with",
+	"signmessage-message":  "Message to sign",
+	"signmessage--result0": "The signed message encoded as a base64 string",
 
 	// SignRawTransactionCmd help.
 	"signrawtransaction--synopsis": "Signs transaction inputs using private keys from this wallet and request.\n" +
@@ -323,6 +425,10 @@ var helpDescsEnUS = map[string]string{
 	"signrawtransactionerror-txid":      "The transaction hash of the referenced previous output",
 	"signrawtransactionerror-vout":      "The output index of the referenced previous output",
 
+	// UnloadWalletCmd help.
+	"unloadwallet--synopsis":  "Unloads a wallet, stopping it and closing its database.",
+	"unloadwallet-walletname": "The name of the wallet to unload (default is the wallet selected by the request's URL path)",
+
 	// ValidateAddressCmd help.
 	"validateaddress--synopsis": "Verify that an address is valid.\n" +
 		"Extra details are returned if the address is controlled by this wallet.\n" +
@@ -347,12 +453,35 @@ var helpDescsEnUS = map[string]string{
 	"validateaddresswalletresult-sigsrequired": "The number of required signatures to redeem outputs to the multisig address",
 
 	// VerifyMessageCmd help.
-	"verifymessage--synopsis": "Verify a message was signed with the associated private key of some address.",
+	"verifymessage--synopsis": "Verify a message was signed with the associated private key of some address.\n" +
+		"Legacy signatures are accepted for P2PKH addresses, and BIP0322 signatures for all addresses.",
 	"verifymessage-address":   "Address used to sign message",
-	"verifymessage-signature": "The signature to verify",
+	"verifymessage-signature": "The base64-encoded signature to verify",
 	"verifymessage-message":   "The message to verify",
 	"verifymessage--result0":  "Whether the message was signed with the private key of 'address'",
 
+	// WalletCreateFundedPsbtCmd help.
+	"walletcreatefundedpsbt--synopsis": "Creates a partially signed transaction (BIP0174) paying the outputs, funded by inputs from a wallet account.\n" +
+		"Inputs are added to the requested inputs until the outputs and fee are paid, and change is paid to a new change address when necessary.\n" +
+		"The inputs of the PSBT are locked for a short duration so they are not spent by other transactions.\n" +
+		"The valid coinselection options are largest-first, branch-and-bound, oldest-first and random-improve.",
+	"walletcreatefundedpsbt-inputs":            "Wallet outputs which must be spent by the transaction",
+	"walletcreatefundedpsbt-outputs":           "Pairs of payment addresses and the output amount to pay each",
+	"walletcreatefundedpsbt-outputs--desc":     "JSON object using payment addresses as keys and output amounts valued in bitcoin to send to each address",
+	"walletcreatefundedpsbt-outputs--key":      "Address to pay",
+	"walletcreatefundedpsbt-outputs--value":    "Amount to send to the payment address valued in bitcoin",
+	"walletcreatefundedpsbt-locktime":          "The transaction lock time",
+	"walletcreatefundedpsbt-options":           "Funding options",
+	"walletcreatefundedpsbtopts-account":       "The account to fund the transaction from (default is the default account)",
+	"walletcreatefundedpsbtopts-feerate":       "The fee rate in bitcoin per kilobyte (default is the wallet's estimated fee rate)",
+	"walletcreatefundedpsbtopts-minconf":       "Only spend outputs with at least this many confirmations (default is 1)",
+	"walletcreatefundedpsbtopts-coinselection": "The coin selection strategy used to choose inputs (default is largest-first)",
+
+	// WalletCreateFundedPsbtResult help.
+	"walletcreatefundedpsbtresult-psbt":      "The base64 encoded PSBT",
+	"walletcreatefundedpsbtresult-fee":       "The fee of the transaction in bitcoin",
+	"walletcreatefundedpsbtresult-changepos": "The index of the change output, or -1 if there is no change",
+
 	// WalletLockCmd help.
 	"walletlock--synopsis": "Lock the wallet.",
 
@@ -366,6 +495,17 @@ var helpDescsEnUS = map[string]string{
 	"walletpassphrasechange-oldpassphrase": "The old wallet passphrase",
 	"walletpassphrasechange-newpassphrase": "The new wallet passphrase",
 
+	// WalletProcessPsbtCmd help.
+	"walletprocesspsbt--synopsis": "Signs the inputs of a base64 encoded partially signed transaction (BIP0174) which spend wallet outputs, and finalizes every input which is completely signed.\n" +
+		"The valid sighashtype options are ALL, NONE, SINGLE, ALL|ANYONECANPAY, NONE|ANYONECANPAY, and SINGLE|ANYONECANPAY.",
+	"walletprocesspsbt-psbt":        "The base64 encoded PSBT",
+	"walletprocesspsbt-sign":        "Sign the inputs spending wallet outputs",
+	"walletprocesspsbt-sighashtype": "The sighash type to sign with",
+
+	// WalletProcessPsbtResult help.
+	"walletprocesspsbtresult-psbt":     "The base64 encoded PSBT",
+	"walletprocesspsbtresult-complete": "Whether every input is finalized",
+
 	// CreateNewAccountCmd help.
 	"createnewaccount--synopsis": "Creates a new account.\n" +
 		"The wallet must be unlocked for this request to succeed.",
@@ -389,6 +529,14 @@ var helpDescsEnUS = map[string]string{
 	"getunconfirmedbalance-account":   "The account to query the unconfirmed balance for (default=\"default\")",
 	"getunconfirmedbalance--result0":  "Total amount of all unmined unspent outputs of the account valued in bitcoin.",
 
+	// ImportAccountCmd help.
+	"importaccount--synopsis": "Imports a watch-only account from an account-level extended public key, such as the key of an account kept in cold storage.\n" +
+		"Addresses of the account are watched up to a gap limit of unused addresses, and unsigned transactions spending from the account may be created with walletcreatefundedpsbt.",
+	"importaccount-account":     "Name of the new account",
+	"importaccount-xpub":        "The extended public key of the account",
+	"importaccount-addresstype": "The type of addresses derived by the account: \"legacy\", \"p2sh-segwit\" or \"bech32\"",
+	"importaccount-rescan":      "Rescan the blockchain for transactions of the account's addresses",
+
 	// ListAddressTransactionsCmd help.
 	"listaddresstransactions--synopsis": "Returns a JSON array of objects containing verbose details for wallet transactions pertaining some addresses.",
 	"listaddresstransactions-addresses": "Addresses to filter transaction results by",
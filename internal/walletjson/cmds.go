@@ -0,0 +1,230 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package walletjson defines the JSON-RPC commands and results of btcwallet's
+// legacy RPC server which are not described by the btcjson package.  The
+// commands are registered with btcjson, so requests for them can be
+// unmarshaled and help can be generated with the btcjson API.
+package walletjson
+
+import (
+	"github.com/btcsuite/btcd/btcjson"
+)
+
+// BackupWalletCmd defines the backupwallet JSON-RPC command.  The passphrase
+// is an extension of the reference client's command, and encrypts the backup
+// when it is not empty.
+type BackupWalletCmd struct {
+	Destination string
+	Passphrase  *string
+}
+
+// NewBackupWalletCmd returns a new instance which can be used to issue a
+// backupwallet JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewBackupWalletCmd(destination string, passphrase *string) *BackupWalletCmd {
+	return &BackupWalletCmd{
+		Destination: destination,
+		Passphrase:  passphrase,
+	}
+}
+
+// BumpFeeCmd defines the bumpfee JSON-RPC command.
+type BumpFeeCmd struct {
+	TxID    string
+	FeeRate *float64
+}
+
+// NewBumpFeeCmd returns a new instance which can be used to issue a bumpfee
+// JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewBumpFeeCmd(txID string, feeRate *float64) *BumpFeeCmd {
+	return &BumpFeeCmd{
+		TxID:    txID,
+		FeeRate: feeRate,
+	}
+}
+
+// FinalizePsbtCmd defines the finalizepsbt JSON-RPC command.
+type FinalizePsbtCmd struct {
+	Psbt    string
+	Extract *bool `jsonrpcdefault:"true"`
+}
+
+// NewFinalizePsbtCmd returns a new instance which can be used to issue a
+// finalizepsbt JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewFinalizePsbtCmd(psbt string, extract *bool) *FinalizePsbtCmd {
+	return &FinalizePsbtCmd{
+		Psbt:    psbt,
+		Extract: extract,
+	}
+}
+
+// ImportAccountCmd defines the importaccount JSON-RPC command.
+type ImportAccountCmd struct {
+	Account     string
+	XPub        string
+	AddressType *string `jsonrpcdefault:"\"legacy\""`
+	Rescan      *bool   `jsonrpcdefault:"true"`
+}
+
+// NewImportAccountCmd returns a new instance which can be used to issue an
+// importaccount JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewImportAccountCmd(account, xpub string, addressType *string,
+	rescan *bool) *ImportAccountCmd {
+
+	return &ImportAccountCmd{
+		Account:     account,
+		XPub:        xpub,
+		AddressType: addressType,
+		Rescan:      rescan,
+	}
+}
+
+// ListWalletsCmd defines the listwallets JSON-RPC command.
+type ListWalletsCmd struct{}
+
+// NewListWalletsCmd returns a new instance which can be used to issue a
+// listwallets JSON-RPC command.
+func NewListWalletsCmd() *ListWalletsCmd {
+	return &ListWalletsCmd{}
+}
+
+// LoadWalletCmd defines the loadwallet JSON-RPC command.
+type LoadWalletCmd struct {
+	Filename string
+}
+
+// NewLoadWalletCmd returns a new instance which can be used to issue a
+// loadwallet JSON-RPC command.
+func NewLoadWalletCmd(filename string) *LoadWalletCmd {
+	return &LoadWalletCmd{
+		Filename: filename,
+	}
+}
+
+// RestoreWalletCmd defines the restorewallet JSON-RPC command.  The passphrase
+// is an extension of the reference client's command, and decrypts encrypted
+// backups.
+type RestoreWalletCmd struct {
+	WalletName string
+	BackupFile string
+	Passphrase *string
+}
+
+// NewRestoreWalletCmd returns a new instance which can be used to issue a
+// restorewallet JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewRestoreWalletCmd(walletName, backupFile string,
+	passphrase *string) *RestoreWalletCmd {
+
+	return &RestoreWalletCmd{
+		WalletName: walletName,
+		BackupFile: backupFile,
+		Passphrase: passphrase,
+	}
+}
+
+// UnloadWalletCmd defines the unloadwallet JSON-RPC command.
+type UnloadWalletCmd struct {
+	WalletName *string
+}
+
+// NewUnloadWalletCmd returns a new instance which can be used to issue an
+// unloadwallet JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewUnloadWalletCmd(walletName *string) *UnloadWalletCmd {
+	return &UnloadWalletCmd{
+		WalletName: walletName,
+	}
+}
+
+// WalletCreateFundedPsbtOpts describes the optional funding options of the
+// walletcreatefundedpsbt JSON-RPC command.
+type WalletCreateFundedPsbtOpts struct {
+	Account       *string  `json:"account,omitempty"`
+	FeeRate       *float64 `json:"feerate,omitempty"`
+	MinConf       *int     `json:"minconf,omitempty"`
+	CoinSelection *string  `json:"coinselection,omitempty"`
+}
+
+// WalletCreateFundedPsbtCmd defines the walletcreatefundedpsbt JSON-RPC
+// command.
+type WalletCreateFundedPsbtCmd struct {
+	Inputs   []btcjson.TransactionInput
+	Outputs  map[string]float64 `jsonrpcusage:"{\"address\":amount,...}"` // In BTC
+	LockTime *uint32
+	Options  *WalletCreateFundedPsbtOpts
+}
+
+// NewWalletCreateFundedPsbtCmd returns a new instance which can be used to
+// issue a walletcreatefundedpsbt JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewWalletCreateFundedPsbtCmd(inputs []btcjson.TransactionInput,
+	outputs map[string]float64, lockTime *uint32,
+	options *WalletCreateFundedPsbtOpts) *WalletCreateFundedPsbtCmd {
+
+	return &WalletCreateFundedPsbtCmd{
+		Inputs:   inputs,
+		Outputs:  outputs,
+		LockTime: lockTime,
+		Options:  options,
+	}
+}
+
+// WalletProcessPsbtCmd defines the walletprocesspsbt JSON-RPC command.
+type WalletProcessPsbtCmd struct {
+	Psbt        string
+	Sign        *bool   `jsonrpcdefault:"true"`
+	SighashType *string `jsonrpcdefault:"\"ALL\""`
+}
+
+// NewWalletProcessPsbtCmd returns a new instance which can be used to issue a
+// walletprocesspsbt JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewWalletProcessPsbtCmd(psbt string, sign *bool,
+	sighashType *string) *WalletProcessPsbtCmd {
+
+	return &WalletProcessPsbtCmd{
+		Psbt:        psbt,
+		Sign:        sign,
+		SighashType: sighashType,
+	}
+}
+
+func init() {
+	// The commands in this file are only usable with a wallet server.
+	flags := btcjson.UFWalletOnly
+
+	btcjson.MustRegisterCmd("backupwallet", (*BackupWalletCmd)(nil), flags)
+	btcjson.MustRegisterCmd("bumpfee", (*BumpFeeCmd)(nil), flags)
+	btcjson.MustRegisterCmd("finalizepsbt", (*FinalizePsbtCmd)(nil), flags)
+	btcjson.MustRegisterCmd("importaccount", (*ImportAccountCmd)(nil), flags)
+	btcjson.MustRegisterCmd("listwallets", (*ListWalletsCmd)(nil), flags)
+	btcjson.MustRegisterCmd("loadwallet", (*LoadWalletCmd)(nil), flags)
+	btcjson.MustRegisterCmd("restorewallet", (*RestoreWalletCmd)(nil), flags)
+	btcjson.MustRegisterCmd("unloadwallet", (*UnloadWalletCmd)(nil), flags)
+	btcjson.MustRegisterCmd("walletcreatefundedpsbt",
+		(*WalletCreateFundedPsbtCmd)(nil), flags)
+	btcjson.MustRegisterCmd("walletprocesspsbt", (*WalletProcessPsbtCmd)(nil),
+		flags)
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package walletjson
+
+// BumpFeeResult models the data returned from the bumpfee command.
+type BumpFeeResult struct {
+	TxID    string  `json:"txid"`
+	OrigFee float64 `json:"origfee"`
+	Fee     float64 `json:"fee"`
+}
+
+// DumpWalletResult models the data returned from the dumpwallet command.
+type DumpWalletResult struct {
+	Filename string `json:"filename"`
+}
+
+// FinalizePsbtResult models the data returned from the finalizepsbt command.
+type FinalizePsbtResult struct {
+	Psbt     string `json:"psbt,omitempty"`
+	Hex      string `json:"hex,omitempty"`
+	Complete bool   `json:"complete"`
+}
+
+// GetWalletInfoResult models the data returned from the getwalletinfo
+// command.
+type GetWalletInfoResult struct {
+	WalletName            string                       `json:"walletname"`
+	WalletVersion         int32                        `json:"walletversion"`
+	Balance               float64                      `json:"balance"`
+	UnconfirmedBalance    float64                      `json:"unconfirmed_balance"`
+	ImmatureBalance       float64                      `json:"immature_balance"`
+	TxCount               int                          `json:"txcount"`
+	KeyPoolSize           uint32                       `json:"keypoolsize"`
+	KeyPoolSizeHDInternal uint32                       `json:"keypoolsize_hd_internal"`
+	UnlockedUntil         *int64                       `json:"unlocked_until,omitempty"`
+	PayTxFee              float64                      `json:"paytxfee"`
+	RelayFee              float64                      `json:"relayfee"`
+	Accounts              []GetWalletInfoAccountResult `json:"accounts"`
+}
+
+// GetWalletInfoAccountResult models the key pool of a single account returned
+// as part of the getwalletinfo command result.
+type GetWalletInfoAccountResult struct {
+	Account               string `json:"account"`
+	KeyPoolSize           uint32 `json:"keypoolsize"`
+	KeyPoolSizeHDInternal uint32 `json:"keypoolsize_hd_internal"`
+}
+
+// ListLockUnspentResult models the data returned from the listlockunspent
+// command for each locked output.
+type ListLockUnspentResult struct {
+	TxID       string `json:"txid"`
+	Vout       uint32 `json:"vout"`
+	LockID     string `json:"lockid,omitempty"`
+	Expiration int64  `json:"expiration,omitempty"`
+}
+
+// LoadWalletResult models the data returned from the loadwallet command.
+type LoadWalletResult struct {
+	Name    string `json:"name"`
+	Warning string `json:"warning"`
+}
+
+// WalletCreateFundedPsbtResult models the data returned from the
+// walletcreatefundedpsbt command.
+type WalletCreateFundedPsbtResult struct {
+	Psbt      string  `json:"psbt"`
+	Fee       float64 `json:"fee"`
+	ChangePos int     `json:"changepos"`
+}
+
+// WalletProcessPsbtResult models the data returned from the walletprocesspsbt
+// command.
+type WalletProcessPsbtResult struct {
+	Psbt     string `json:"psbt"`
+	Complete bool   `json:"complete"`
+}
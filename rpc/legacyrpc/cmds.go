@@ -0,0 +1,111 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package legacyrpc
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcjson"
+)
+
+// GetNewAddressCmd defines the getnewaddress JSON-RPC command.  It extends the
+// btcjson command with an optional address type, which btcjson does not
+// describe.
+type GetNewAddressCmd struct {
+	btcjson.GetNewAddressCmd
+	AddressType *string
+}
+
+// LockUnspentCmd defines the lockunspent JSON-RPC command.  It extends the
+// btcjson command with an optional lock ID and the optional number of seconds
+// after which the locks expire.
+type LockUnspentCmd struct {
+	btcjson.LockUnspentCmd
+	LockID   *string
+	Duration *int64
+}
+
+// SendManyCmd defines the sendmany JSON-RPC command.  It extends the btcjson
+// command with an optional fee rate, in BTC per kB, which overrides the fee
+// rate estimated by the wallet, and an optional coin selection strategy.
+type SendManyCmd struct {
+	btcjson.SendManyCmd
+	FeeRate       *float64
+	CoinSelection *string
+}
+
+// unmarshalCmd unmarshals a JSON-RPC request into a command.  Requests for
+// commands which are extended by this package with parameters unknown to
+// btcjson are unmarshaled here, and all others are unmarshaled by btcjson.
+func unmarshalCmd(request *btcjson.Request) (interface{}, error) {
+	switch request.Method {
+	case "getnewaddress":
+		cmd := new(GetNewAddressCmd)
+		known, err := unmarshalExtendedCmd(request, 1, &cmd.AddressType)
+		if err != nil {
+			return nil, err
+		}
+		cmd.GetNewAddressCmd = *known.(*btcjson.GetNewAddressCmd)
+		return cmd, nil
+
+	case "lockunspent":
+		cmd := new(LockUnspentCmd)
+		known, err := unmarshalExtendedCmd(request, 2, &cmd.LockID,
+			&cmd.Duration)
+		if err != nil {
+			return nil, err
+		}
+		cmd.LockUnspentCmd = *known.(*btcjson.LockUnspentCmd)
+		return cmd, nil
+
+	case "sendmany":
+		cmd := new(SendManyCmd)
+		known, err := unmarshalExtendedCmd(request, 4, &cmd.FeeRate,
+			&cmd.CoinSelection)
+		if err != nil {
+			return nil, err
+		}
+		cmd.SendManyCmd = *known.(*btcjson.SendManyCmd)
+		return cmd, nil
+	}
+
+	return btcjson.UnmarshalCmd(request)
+}
+
+// unmarshalExtendedCmd unmarshals the first numKnown positional parameters of
+// a request with btcjson, which also applies the defaults of any optional
+// parameters it describes.  The remaining parameters are unmarshaled into the
+// passed pointers to optional fields, which are left unset when the request
+// does not provide them.
+func unmarshalExtendedCmd(request *btcjson.Request, numKnown int,
+	fields ...interface{}) (interface{}, error) {
+
+	params := request.Params
+	if len(params) > numKnown+len(fields) {
+		return nil, fmt.Errorf("wrong number of params (expected at most "+
+			"%d, received %d)", numKnown+len(fields), len(params))
+	}
+
+	knownRequest := *request
+	if len(params) > numKnown {
+		knownRequest.Params = params[:numKnown]
+		params = params[numKnown:]
+	} else {
+		params = nil
+	}
+	cmd, err := btcjson.UnmarshalCmd(&knownRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, param := range params {
+		if err := json.Unmarshal(param, fields[i]); err != nil {
+			return nil, fmt.Errorf("parameter #%d is invalid: %v",
+				numKnown+i+1, err)
+		}
+	}
+	return cmd, nil
+}
@@ -9,6 +9,10 @@ type Options struct {
 	Username string
 	Password string
 
+	// WalletPubPassphrase is the public passphrase used to open the
+	// wallets loaded by the loadwallet method.
+	WalletPubPassphrase []byte
+
 	MaxPOSTClients      int64
 	MaxWebsocketClients int64
 }
@@ -37,6 +37,13 @@ type (
 	}
 )
 
+// Error codes of the reference implementation for the selection of a wallet,
+// which are not defined by btcjson.
+const (
+	errRPCWalletNotFound     btcjson.RPCErrorCode = -18
+	errRPCWalletNotSpecified btcjson.RPCErrorCode = -19
+)
+
 // Errors variables that are defined once here to avoid duplication below.
 var (
 	ErrNeedPositiveAmount = InvalidParameterError{
@@ -62,6 +69,17 @@ var (
 		Message: "Request requires a wallet but wallet has not loaded yet",
 	}
 
+	ErrWalletNotFound = btcjson.RPCError{
+		Code:    errRPCWalletNotFound,
+		Message: "Requested wallet does not exist or is not loaded",
+	}
+
+	ErrWalletNotSpecified = btcjson.RPCError{
+		Code: errRPCWalletNotSpecified,
+		Message: "Wallet not specified (must request wallet RPC " +
+			"through /wallet/<name> URL path)",
+	}
+
 	ErrWalletUnlockNeeded = btcjson.RPCError{
 		Code:    btcjson.ErrRPCWalletUnlockNeeded,
 		Message: "Enter the wallet passphrase with walletpassphrase first",
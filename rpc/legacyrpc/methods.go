@@ -11,10 +11,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
-	"github.com/btcsuite/btcd/btcec"
 	"github.com/btcsuite/btcd/btcjson"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
@@ -22,9 +24,13 @@ import (
 	"github.com/btcsuite/btcd/wire"
 	btcrpcclient "github.com/btcsuite/btcd/rpcclient"
 	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/hdkeychain"
 	"github.com/btcsuite/btcwallet/chain"
+	"github.com/btcsuite/btcwallet/internal/walletjson"
 	"github.com/btcsuite/btcwallet/waddrmgr"
 	"github.com/btcsuite/btcwallet/wallet"
+	"github.com/btcsuite/btcwallet/wallet/psbt"
+	"github.com/btcsuite/btcwallet/wallet/txauthor"
 	"github.com/btcsuite/btcwallet/wallet/txrules"
 	"github.com/btcsuite/btcwallet/wtxmgr"
 )
@@ -62,11 +68,17 @@ func confirms(txHeight, curHeight int32) int32 {
 type requestHandler func(interface{}, *wallet.Wallet) (interface{}, error)
 
 // requestHandlerChain is a requestHandler that also takes a parameter for
-type requestHandlerChainRequired func(interface{}, *wallet.Wallet, *chain.RPCClient) (interface{}, error)
+type requestHandlerChainRequired func(interface{}, *wallet.Wallet, chain.Interface) (interface{}, error)
+
+// requestHandlerLoader is a handler for the methods which manage the wallets
+// loaded by the server.  It is passed the wallet selected by the request,
+// which may not be loaded.
+type requestHandlerLoader func(interface{}, *Server, walletSelection) (interface{}, error)
 
 var rpcHandlers = map[string]struct {
-	handler          requestHandler
-	handlerWithChain requestHandlerChainRequired
+	handler           requestHandler
+	handlerWithChain  requestHandlerChainRequired
+	handlerWithLoader requestHandlerLoader
 
 	// Function variables cannot be compared against anything but nil, so
 	// use a boolean to record whether help generation is necessary.  This
@@ -80,8 +92,12 @@ var rpcHandlers = map[string]struct {
 }{
 	// Reference implementation wallet methods (implemented)
 	"addmultisigaddress":     {handler: addMultiSigAddress},
+	"backupwallet":           {handlerWithLoader: backupWallet},
+	"bumpfee":                {handler: bumpFee},
 	"createmultisig":         {handler: createMultiSig},
 	"dumpprivkey":            {handler: dumpPrivKey},
+	"dumpwallet":             {handler: dumpWallet},
+	"finalizepsbt":           {handler: finalizePsbt},
 	"getaccount":             {handler: getAccount},
 	"getaccountaddress":      {handler: getAccountAddress},
 	"getaddressesbyaccount":  {handler: getAddressesByAccount},
@@ -94,35 +110,37 @@ var rpcHandlers = map[string]struct {
 	"getreceivedbyaccount":   {handler: getReceivedByAccount},
 	"getreceivedbyaddress":   {handler: getReceivedByAddress},
 	"gettransaction":         {handler: getTransaction},
+	"getwalletinfo":          {handlerWithLoader: getWalletInfo},
 	"help":                   {handler: helpNoChainRPC, handlerWithChain: helpWithChainRPC},
 	"importprivkey":          {handler: importPrivKey},
+	"importwallet":           {handler: importWallet},
 	"keypoolrefill":          {handler: keypoolRefill},
 	"listaccounts":           {handler: listAccounts},
+	"listaddressgroupings":   {handler: listAddressGroupings},
 	"listlockunspent":        {handler: listLockUnspent},
 	"listreceivedbyaccount":  {handler: listReceivedByAccount},
 	"listreceivedbyaddress":  {handler: listReceivedByAddress},
 	"listsinceblock":         {handlerWithChain: listSinceBlock},
 	"listtransactions":       {handler: listTransactions},
 	"listunspent":            {handler: listUnspent},
+	"listwallets":            {handlerWithLoader: listWallets},
+	"loadwallet":             {handlerWithLoader: loadWallet},
 	"lockunspent":            {handler: lockUnspent},
+	"restorewallet":          {handlerWithLoader: restoreWallet},
 	"sendfrom":               {handlerWithChain: sendFrom},
 	"sendmany":               {handler: sendMany},
 	"sendtoaddress":          {handler: sendToAddress},
 	"settxfee":               {handler: setTxFee},
 	"signmessage":            {handler: signMessage},
 	"signrawtransaction":     {handlerWithChain: signRawTransaction},
+	"unloadwallet":           {handlerWithLoader: unloadWallet},
 	"validateaddress":        {handler: validateAddress},
 	"verifymessage":          {handler: verifyMessage},
+	"walletcreatefundedpsbt": {handler: walletCreateFundedPsbt},
 	"walletlock":             {handler: walletLock},
 	"walletpassphrase":       {handler: walletPassphrase},
 	"walletpassphrasechange": {handler: walletPassphraseChange},
-
-	// Reference implementation methods (still unimplemented)
-	"backupwallet":         {handler: unimplemented, noHelp: true},
-	"dumpwallet":           {handler: unimplemented, noHelp: true},
-	"getwalletinfo":        {handler: unimplemented, noHelp: true},
-	"importwallet":         {handler: unimplemented, noHelp: true},
-	"listaddressgroupings": {handler: unimplemented, noHelp: true},
+	"walletprocesspsbt":      {handler: walletProcessPsbt},
 
 	// Reference methods which can't be implemented by btcwallet due to
 	// design decision differences
@@ -139,6 +157,7 @@ var rpcHandlers = map[string]struct {
 	// here because it hasn't been update to use the reference
 	// implemenation's API.
 	"getunconfirmedbalance":   {handler: getUnconfirmedBalance},
+	"importaccount":           {handler: importAccount},
 	"listaddresstransactions": {handler: listAddressTransactions},
 	"listalltransactions":     {handler: listAllTransactions},
 	"renameaccount":           {handler: renameAccount},
@@ -169,14 +188,38 @@ func unsupported(interface{}, *wallet.Wallet) (interface{}, error) {
 type lazyHandler func() (interface{}, *btcjson.RPCError)
 
 // lazyApplyHandler looks up the best request handler func for the method,
-// returning a closure that will execute it with the (required) wallet and
-// (optional) consensus RPC server.  If no handlers are found and the
-// chainClient is not nil, the returned handler performs RPC passthrough.
-func lazyApplyHandler(request *btcjson.Request, w *wallet.Wallet, chainClient *chain.RPCClient) lazyHandler {
+// returning a closure that will execute it with the (required) wallet selected
+// for the request and (optional) chain backend.  If no handlers are found and
+// the chainClient is a consensus RPC client, the returned handler performs RPC
+// passthrough.
+func lazyApplyHandler(request *btcjson.Request, s *Server, sel walletSelection,
+	chainClient chain.Interface) lazyHandler {
+
 	handlerData, ok := rpcHandlers[request.Method]
+	if ok && handlerData.handlerWithLoader != nil {
+		return func() (interface{}, *btcjson.RPCError) {
+			cmd, err := unmarshalCmd(request)
+			if err != nil {
+				return nil, btcjson.ErrRPCInvalidRequest
+			}
+			resp, err := handlerData.handlerWithLoader(cmd, s, sel)
+			if err != nil {
+				return nil, jsonError(err)
+			}
+			return resp, nil
+		}
+	}
+	if ok && sel.err != nil &&
+		(handlerData.handler != nil || handlerData.handlerWithChain != nil) {
+
+		return func() (interface{}, *btcjson.RPCError) {
+			return nil, sel.err
+		}
+	}
+	w := sel.wallet
 	if ok && handlerData.handlerWithChain != nil && w != nil && chainClient != nil {
 		return func() (interface{}, *btcjson.RPCError) {
-			cmd, err := btcjson.UnmarshalCmd(request)
+			cmd, err := unmarshalCmd(request)
 			if err != nil {
 				return nil, btcjson.ErrRPCInvalidRequest
 			}
@@ -189,7 +232,7 @@ func lazyApplyHandler(request *btcjson.Request, w *wallet.Wallet, chainClient *c
 	}
 	if ok && handlerData.handler != nil && w != nil {
 		return func() (interface{}, *btcjson.RPCError) {
-			cmd, err := btcjson.UnmarshalCmd(request)
+			cmd, err := unmarshalCmd(request)
 			if err != nil {
 				return nil, btcjson.ErrRPCInvalidRequest
 			}
@@ -209,7 +252,11 @@ func lazyApplyHandler(request *btcjson.Request, w *wallet.Wallet, chainClient *c
 				Message: "Chain RPC is inactive",
 			}
 		}
-		resp, err := chainClient.RawRequest(request.Method, request.Params)
+		rpcClient, ok := chainClient.(*chain.RPCClient)
+		if !ok {
+			return nil, btcjson.ErrRPCMethodNotFound
+		}
+		resp, err := rpcClient.RawRequest(request.Method, request.Params)
 		if err != nil {
 			return nil, jsonError(err)
 		}
@@ -266,6 +313,8 @@ func jsonError(err error) *btcjson.RPCError {
 		switch e.ErrorCode {
 		case waddrmgr.ErrWrongPassphrase:
 			code = btcjson.ErrRPCWalletPassphraseIncorrect
+		case waddrmgr.ErrUnsupportedAddressType:
+			code = btcjson.ErrRPCInvalidParameter
 		}
 	}
 	return &btcjson.RPCError{
@@ -317,6 +366,33 @@ func makeMultiSigScript(w *wallet.Wallet, keys []string, nRequired int) ([]byte,
 	return txscript.MultiSigScript(keysesPrecious, nRequired)
 }
 
+// backupWallet handles a backupwallet request by writing a copy of the wallet
+// database to a file, encrypting it when a passphrase is given.
+func backupWallet(icmd interface{}, s *Server, sel walletSelection) (interface{}, error) {
+	cmd := icmd.(*walletjson.BackupWalletCmd)
+
+	if sel.err != nil {
+		return nil, sel.err
+	}
+	if sel.wallet == nil {
+		return nil, &ErrUnloadedWallet
+	}
+
+	var passphrase []byte
+	if cmd.Passphrase != nil {
+		passphrase = []byte(*cmd.Passphrase)
+	}
+	err := s.walletLoader.BackupNamedWallet(sel.name, cmd.Destination,
+		passphrase)
+	switch err {
+	case wallet.ErrBackupOverwritesWallet:
+		return nil, InvalidParameterError{err}
+	case wallet.ErrNotLoaded:
+		return nil, &ErrWalletNotFound
+	}
+	return nil, err
+}
+
 // addMultiSigAddress handles an addmultisigaddress request by adding a
 // multisig address to the given wallet.
 func addMultiSigAddress(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
@@ -346,6 +422,54 @@ func addMultiSigAddress(icmd interface{}, w *wallet.Wallet) (interface{}, error)
 	return addr.Address().EncodeAddress(), nil
 }
 
+// bumpFee handles a bumpfee request by replacing an unmined wallet
+// transaction with one paying a higher fee.
+func bumpFee(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.BumpFeeCmd)
+
+	txHash, err := chainhash.NewHashFromStr(cmd.TxID)
+	if err != nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCDecodeHexString,
+			Message: "Transaction hash string decode failed: " + err.Error(),
+		}
+	}
+
+	var feeRate btcutil.Amount
+	if cmd.FeeRate != nil {
+		feeRate, err = btcutil.NewAmount(*cmd.FeeRate)
+		if err != nil {
+			return nil, err
+		}
+		if feeRate <= 0 {
+			return nil, InvalidParameterError{
+				errors.New("fee rate must be positive"),
+			}
+		}
+	}
+
+	result, err := w.BumpFee(txHash, feeRate)
+	switch err {
+	case nil:
+	case wallet.ErrTxNotFound:
+		return nil, &ErrNoTransactionInfo
+	case wallet.ErrTxMined, wallet.ErrTxNotReplaceable, wallet.ErrTxNotOwned,
+		wallet.ErrTxHasSpenders, wallet.ErrFeeRateTooLow:
+		return nil, InvalidParameterError{err}
+	default:
+		if waddrmgr.IsError(err, waddrmgr.ErrLocked) {
+			return nil, &ErrWalletUnlockNeeded
+		}
+		return nil, err
+	}
+
+	return &walletjson.BumpFeeResult{
+		TxID:    result.Tx.TxHash().String(),
+		OrigFee: result.OrigFee.ToBTC(),
+		Fee:     result.Fee.ToBTC(),
+	}, nil
+}
+
 // createMultiSig handles an createmultisig request by returning a
 // multisig address for the given inputs.
 func createMultiSig(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
@@ -388,16 +512,48 @@ func dumpPrivKey(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	return key, err
 }
 
-// dumpWallet handles a dumpwallet request by returning  all private
-// keys in a wallet, or an appropiate error if the wallet is locked.
-// TODO: finish this to match bitcoind by writing the dump to a file.
+// dumpWallet handles a dumpwallet request by writing all private keys and
+// scripts of a wallet to a new file, or an appropiate error if the wallet is
+// locked.
 func dumpWallet(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
-	keys, err := w.DumpPrivKeys()
-	if waddrmgr.IsError(err, waddrmgr.ErrLocked) {
+	cmd := icmd.(*btcjson.DumpWalletCmd)
+
+	filename, err := filepath.Abs(cmd.Filename)
+	if err != nil {
+		return nil, InvalidParameterError{err}
+	}
+	if w.Manager.IsLocked() {
 		return nil, &ErrWalletUnlockNeeded
 	}
 
-	return keys, err
+	// Existing files are never overwritten, as they may be an earlier
+	// dump which is the only copy of some keys.
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if os.IsExist(err) {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCWallet,
+			Message: fmt.Sprintf("%s already exists", filename),
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = w.DumpWallet(f)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(filename)
+		if waddrmgr.IsError(err, waddrmgr.ErrLocked) {
+			return nil, &ErrWalletUnlockNeeded
+		}
+		return nil, err
+	}
+
+	return &walletjson.DumpWalletResult{Filename: filename}, nil
 }
 
 // exportWatchingWallet handles an exportwatchingwallet request by exporting the
@@ -496,12 +652,25 @@ func getBlockCount(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 // getInfo handles a getinfo request by returning the a structure containing
 // information about the current state of btcwallet.
 // exist.
-func getInfo(icmd interface{}, w *wallet.Wallet, chainClient *chain.RPCClient) (interface{}, error) {
+func getInfo(icmd interface{}, w *wallet.Wallet, chainClient chain.Interface) (interface{}, error) {
 	// Call down to btcd for all of the information in this command known
-	// by them.
-	info, err := chainClient.GetInfo()
-	if err != nil {
-		return nil, err
+	// by them.  Only the block count is known for other chain backends.
+	var info *btcjson.InfoWalletResult
+	if rpcClient, ok := chainClient.(*chain.RPCClient); ok {
+		var err error
+		info, err = rpcClient.GetInfo()
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		_, height, err := chainClient.GetBestBlock()
+		if err != nil {
+			return nil, err
+		}
+		info = &btcjson.InfoWalletResult{
+			Blocks:  height,
+			TestNet: w.ChainParams().Net == wire.TestNet3,
+		}
 	}
 
 	bal, err := w.CalculateBalance(1)
@@ -578,7 +747,11 @@ func getAccountAddress(icmd interface{}, w *wallet.Wallet) (interface{}, error)
 	if err != nil {
 		return nil, err
 	}
-	addr, err := w.CurrentAddress(account)
+	props, err := w.Manager.AccountProperties(account)
+	if err != nil {
+		return nil, err
+	}
+	addr, err := w.CurrentAddress(account, props.AddressType)
 	if err != nil {
 		return nil, err
 	}
@@ -607,6 +780,69 @@ func getUnconfirmedBalance(icmd interface{}, w *wallet.Wallet) (interface{}, err
 	return (bals.Total - bals.Spendable).ToBTC(), nil
 }
 
+// getWalletInfo handles a getwalletinfo request by returning the balances,
+// transaction count, key pools, and lock state of the selected wallet.
+func getWalletInfo(icmd interface{}, s *Server, sel walletSelection) (interface{}, error) {
+	if sel.err != nil {
+		return nil, sel.err
+	}
+	w := sel.wallet
+	if w == nil {
+		return nil, &ErrUnloadedWallet
+	}
+
+	bals, err := w.CalculateWalletBalances(1)
+	if err != nil {
+		return nil, err
+	}
+	txCount, err := w.TxCount()
+	if err != nil {
+		return nil, err
+	}
+	pools, err := w.KeyPoolSizes()
+	if err != nil {
+		return nil, err
+	}
+
+	relayFee := w.RelayFee().ToBTC()
+	info := &walletjson.GetWalletInfoResult{
+		WalletName: sel.name,
+		// TODO(davec): This should probably have a database version as
+		// opposed to using the manager version.
+		WalletVersion:      int32(waddrmgr.LatestMgrVersion),
+		Balance:            bals.Spendable.ToBTC(),
+		UnconfirmedBalance: (bals.Total - bals.Spendable - bals.ImmatureReward).ToBTC(),
+		ImmatureBalance:    bals.ImmatureReward.ToBTC(),
+		TxCount:            txCount,
+		PayTxFee:           relayFee,
+		RelayFee:           relayFee,
+		Accounts:           make([]walletjson.GetWalletInfoAccountResult, 0, len(pools)),
+	}
+	for _, pool := range pools {
+		info.KeyPoolSize += pool.External
+		info.KeyPoolSizeHDInternal += pool.Internal
+		info.Accounts = append(info.Accounts, walletjson.GetWalletInfoAccountResult{
+			Account:               pool.AccountName,
+			KeyPoolSize:           pool.External,
+			KeyPoolSizeHDInternal: pool.Internal,
+		})
+	}
+
+	// The reference client reports zero for a locked wallet.  The field is
+	// omitted when the wallet was unlocked without a time limit.
+	var unlockedUntil int64
+	locked, until := w.LockState()
+	if !locked {
+		if until.IsZero() {
+			return info, nil
+		}
+		unlockedUntil = until.Unix()
+	}
+	info.UnlockedUntil = &unlockedUntil
+
+	return info, nil
+}
+
 // importPrivKey handles an importprivkey request by parsing
 // a WIF-encoded private key and adding it to an account.
 func importPrivKey(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
@@ -646,6 +882,24 @@ func importPrivKey(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	return nil, err
 }
 
+// importWallet handles an importwallet request by importing the keys and
+// scripts of a key dump file written by dumpwallet.
+func importWallet(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*btcjson.ImportWalletCmd)
+
+	f, err := os.Open(cmd.Filename)
+	if err != nil {
+		return nil, InvalidParameterError{err}
+	}
+	defer f.Close()
+
+	err = w.ImportWallet(f)
+	if waddrmgr.IsError(err, waddrmgr.ErrLocked) {
+		return nil, &ErrWalletUnlockNeeded
+	}
+	return nil, err
+}
+
 // keypoolRefill handles the keypoolrefill command. Since we handle the keypool
 // automatically this does nothing since refilling is never manually required.
 func keypoolRefill(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
@@ -680,7 +934,7 @@ func createNewAccount(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 		}
 	}
 
-	_, err = w.NextAccount(cmd.Account)
+	_, err = w.NextAccount(cmd.Account, waddrmgr.PubKeyHash)
 	if waddrmgr.IsError(err, waddrmgr.ErrLocked) {
 		return nil, &btcjson.RPCError{
 			Code: btcjson.ErrRPCWalletUnlockNeeded,
@@ -691,6 +945,41 @@ func createNewAccount(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	return nil, err
 }
 
+// importAccount handles an importaccount request by importing a watch-only
+// account from an account-level extended public key.
+func importAccount(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.ImportAccountCmd)
+
+	// The wildcard * is reserved by the rpc server with the special meaning
+	// of "all accounts", so disallow naming accounts to this string.
+	if cmd.Account == "*" {
+		return nil, &ErrReservedAccountName
+	}
+
+	acctKey, err := hdkeychain.NewKeyFromString(cmd.XPub)
+	if err != nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidAddressOrKey,
+			Message: "Extended key decode failed: " + err.Error(),
+		}
+	}
+	addrType, err := decodeAddressType(*cmd.AddressType)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = w.ImportAccount(cmd.Account, acctKey, addrType, nil,
+		*cmd.Rescan)
+	if waddrmgr.IsError(err, waddrmgr.ErrKeyChain) ||
+		waddrmgr.IsError(err, waddrmgr.ErrWrongNet) {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidAddressOrKey,
+			Message: err.Error(),
+		}
+	}
+	return nil, err
+}
+
 // renameAccount handles a renameaccount request by renaming an account.
 // If the account does not exist an appropiate error will be returned.
 func renameAccount(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
@@ -712,11 +1001,12 @@ func renameAccount(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 
 // getNewAddress handles a getnewaddress request by returning a new
 // address for an account.  If the account does not exist an appropiate
-// error is returned.
+// error is returned.  When an address type is requested, the account must
+// derive addresses of that type, otherwise the account's address type is used.
 // TODO: Follow BIP 0044 and warn if number of unused addresses exceeds
 // the gap limit.
 func getNewAddress(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
-	cmd := icmd.(*btcjson.GetNewAddressCmd)
+	cmd := icmd.(*GetNewAddressCmd)
 
 	acctName := "default"
 	if cmd.Account != nil {
@@ -726,7 +1016,18 @@ func getNewAddress(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
-	addr, err := w.NewAddress(account)
+	props, err := w.Manager.AccountProperties(account)
+	if err != nil {
+		return nil, err
+	}
+	addrType := props.AddressType
+	if cmd.AddressType != nil {
+		addrType, err = decodeAddressType(*cmd.AddressType)
+		if err != nil {
+			return nil, err
+		}
+	}
+	addr, err := w.NewAddress(account, addrType)
 	if err != nil {
 		return nil, err
 	}
@@ -735,6 +1036,23 @@ func getNewAddress(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	return addr.EncodeAddress(), nil
 }
 
+// decodeAddressType decodes an address type parameter using the names of the
+// reference implementation.
+func decodeAddressType(s string) (waddrmgr.AddressType, error) {
+	switch s {
+	case "legacy":
+		return waddrmgr.PubKeyHash, nil
+	case "p2sh-segwit":
+		return waddrmgr.NestedWitnessPubKey, nil
+	case "bech32":
+		return waddrmgr.WitnessPubKey, nil
+	default:
+		return 0, InvalidParameterError{
+			fmt.Errorf("unknown address type %q", s),
+		}
+	}
+}
+
 // getRawChangeAddress handles a getrawchangeaddress request by creating
 // and returning a new change address for an account.
 //
@@ -955,11 +1273,12 @@ var helpDescs map[string]string
 var helpDescsMu sync.Mutex // Help may execute concurrently, so synchronize access.
 
 // helpWithChainRPC handles the help request when the RPC server has been
-// associated with a consensus RPC client.  The additional RPC client is used to
-// include help messages for methods implemented by the consensus server via RPC
-// passthrough.
-func helpWithChainRPC(icmd interface{}, w *wallet.Wallet, chainClient *chain.RPCClient) (interface{}, error) {
-	return help(icmd, w, chainClient)
+// associated with a chain backend.  When the backend is a consensus RPC client,
+// it is used to include help messages for methods implemented by the consensus
+// server via RPC passthrough.
+func helpWithChainRPC(icmd interface{}, w *wallet.Wallet, chainClient chain.Interface) (interface{}, error) {
+	rpcClient, _ := chainClient.(*chain.RPCClient)
+	return help(icmd, w, rpcClient)
 }
 
 // helpNoChainRPC handles the help request when the RPC server has not been
@@ -1083,10 +1402,52 @@ func listAccounts(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	return accountBalances, nil
 }
 
+// listAddressGroupings handles a listaddressgroupings request by returning
+// the wallet's addresses clustered by common input ownership.  Each address is
+// described by an array of its encoding, balance, and account name.
+func listAddressGroupings(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	groupings, err := w.AddressGroupings()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([][][]interface{}, 0, len(groupings))
+	for _, grouping := range groupings {
+		group := make([][]interface{}, 0, len(grouping))
+		for _, ga := range grouping {
+			group = append(group, []interface{}{
+				ga.Address.EncodeAddress(),
+				ga.Balance.ToBTC(),
+				ga.Account,
+			})
+		}
+		result = append(result, group)
+	}
+	return result, nil
+}
+
 // listLockUnspent handles a listlockunspent request by returning an slice of
 // all locked outpoints.
 func listLockUnspent(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
-	return w.LockedOutpoints(), nil
+	locks, err := w.LockedOutpoints()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]walletjson.ListLockUnspentResult, 0, len(locks))
+	for i := range locks {
+		lock := &locks[i]
+		var expiration int64
+		if !lock.Expiration.IsZero() {
+			expiration = lock.Expiration.Unix()
+		}
+		result = append(result, walletjson.ListLockUnspentResult{
+			TxID:       lock.OutPoint.Hash.String(),
+			Vout:       lock.OutPoint.Index,
+			LockID:     lock.LockID,
+			Expiration: expiration,
+		})
+	}
+	return result, nil
 }
 
 // listReceivedByAccount handles a listreceivedbyaccount request by returning
@@ -1233,28 +1594,23 @@ func listReceivedByAddress(icmd interface{}, w *wallet.Wallet) (interface{}, err
 
 // listSinceBlock handles a listsinceblock request by returning an array of maps
 // with details of sent and received wallet transactions since the given block.
-func listSinceBlock(icmd interface{}, w *wallet.Wallet, chainClient *chain.RPCClient) (interface{}, error) {
+func listSinceBlock(icmd interface{}, w *wallet.Wallet, chainClient chain.Interface) (interface{}, error) {
 	cmd := icmd.(*btcjson.ListSinceBlockCmd)
 
 	syncBlock := w.Manager.SyncedTo()
 	targetConf := int64(*cmd.TargetConfirmations)
 
-	// For the result we need the block hash for the last block counted
-	// in the blockchain due to confirmations. We send this off now so that
-	// it can arrive asynchronously while we figure out the rest.
-	gbh := chainClient.GetBlockHashAsync(int64(syncBlock.Height) + 1 - targetConf)
-
 	var start int32
 	if cmd.BlockHash != nil {
 		hash, err := chainhash.NewHashFromStr(*cmd.BlockHash)
 		if err != nil {
 			return nil, DeserializationError{err}
 		}
-		block, err := chainClient.GetBlockVerbose(hash)
+		height, err := chainClient.GetBlockHeight(hash)
 		if err != nil {
 			return nil, err
 		}
-		start = int32(block.Height) + 1
+		start = height + 1
 	}
 
 	txInfoList, err := w.ListSinceBlock(start, -1, syncBlock.Height)
@@ -1262,8 +1618,9 @@ func listSinceBlock(icmd interface{}, w *wallet.Wallet, chainClient *chain.RPCCl
 		return nil, err
 	}
 
-	// Done with work, get the response.
-	blockHash, err := gbh.Receive()
+	// For the result we need the block hash for the last block counted
+	// in the blockchain due to confirmations.
+	blockHash, err := chainClient.GetBlockHash(int64(syncBlock.Height) + 1 - targetConf)
 	if err != nil {
 		return nil, err
 	}
@@ -1362,13 +1719,64 @@ func listUnspent(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	return w.ListUnspent(int32(*cmd.MinConf), int32(*cmd.MaxConf), addresses)
 }
 
+// listWallets handles the listwallets command.
+func listWallets(icmd interface{}, s *Server, _ walletSelection) (interface{}, error) {
+	return s.walletLoader.LoadedWalletNames(), nil
+}
+
+// loadWallet handles the loadwallet command by opening a named wallet with the
+// server's public passphrase.
+func loadWallet(icmd interface{}, s *Server, _ walletSelection) (interface{}, error) {
+	cmd := icmd.(*walletjson.LoadWalletCmd)
+
+	exists, err := s.walletLoader.NamedWalletExists(cmd.Filename)
+	if err == wallet.ErrInvalidWalletName {
+		return nil, InvalidParameterError{err}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, &btcjson.RPCError{
+			Code:    errRPCWalletNotFound,
+			Message: fmt.Sprintf("Wallet %q not found", cmd.Filename),
+		}
+	}
+
+	_, err = s.walletLoader.OpenNamedWallet(cmd.Filename, s.walletPubPass,
+		false)
+	if err != nil {
+		return nil, err
+	}
+	return &walletjson.LoadWalletResult{Name: cmd.Filename}, nil
+}
+
 // lockUnspent handles the lockunspent command.
 func lockUnspent(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
-	cmd := icmd.(*btcjson.LockUnspentCmd)
+	cmd := icmd.(*LockUnspentCmd)
+
+	var lockID string
+	if cmd.LockID != nil {
+		lockID = *cmd.LockID
+	}
+	var expiration time.Time
+	if cmd.Duration != nil {
+		switch {
+		case *cmd.Duration < 0:
+			return nil, InvalidParameterError{
+				errors.New("lock duration may not be negative"),
+			}
+		case *cmd.Duration > 0:
+			expiration = time.Now().Add(time.Duration(*cmd.Duration) * time.Second)
+		}
+	}
 
 	switch {
 	case cmd.Unlock && len(cmd.Transactions) == 0:
-		w.ResetLockedOutpoints()
+		err := w.ResetLockedOutpoints()
+		if err != nil {
+			return nil, err
+		}
 	default:
 		for _, input := range cmd.Transactions {
 			txHash, err := chainhash.NewHashFromStr(input.Txid)
@@ -1377,9 +1785,12 @@ func lockUnspent(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 			}
 			op := wire.OutPoint{Hash: *txHash, Index: input.Vout}
 			if cmd.Unlock {
-				w.UnlockOutpoint(op)
+				err = w.UnlockOutpoint(op)
 			} else {
-				w.LockOutpoint(op)
+				err = w.LockOutpoint(op, lockID, expiration)
+			}
+			if err != nil {
+				return nil, err
 			}
 		}
 	}
@@ -1411,13 +1822,17 @@ func makeOutputs(pairs map[string]btcutil.Amount, chainParams *chaincfg.Params)
 // sendPairs creates and sends payment transactions.
 // It returns the transaction hash in string format upon success
 // All errors are returned in btcjson.RPCError format
+// A zero feeRate uses the wallet's estimated fee rate, and a nil selector
+// uses the wallet's default coin selection strategy.
 func sendPairs(w *wallet.Wallet, amounts map[string]btcutil.Amount,
-	account uint32, minconf int32) (string, error) {
+	account uint32, minconf int32, feeRate btcutil.Amount,
+	selector txauthor.CoinSelector) (string, error) {
 	outputs, err := makeOutputs(amounts, w.ChainParams())
 	if err != nil {
 		return "", err
 	}
-	txHash, err := w.SendOutputs(outputs, account, minconf)
+	txHash, err := w.SendOutputs(outputs, account, minconf, feeRate,
+		selector)
 	if err != nil {
 		if err == txrules.ErrAmountNegative {
 			return "", ErrNeedPositiveAmount
@@ -1450,18 +1865,9 @@ func isNilOrEmpty(s *string) bool {
 // address.  Leftover inputs not sent to the payment address or a fee for
 // the miner are sent back to a new address in the wallet.  Upon success,
 // the TxID for the created transaction is returned.
-func sendFrom(icmd interface{}, w *wallet.Wallet, chainClient *chain.RPCClient) (interface{}, error) {
+func sendFrom(icmd interface{}, w *wallet.Wallet, chainClient chain.Interface) (interface{}, error) {
 	cmd := icmd.(*btcjson.SendFromCmd)
 
-	// Transaction comments are not yet supported.  Error instead of
-	// pretending to save them.
-	if !isNilOrEmpty(cmd.Comment) || !isNilOrEmpty(cmd.CommentTo) {
-		return nil, &btcjson.RPCError{
-			Code:    btcjson.ErrRPCUnimplemented,
-			Message: "Transaction comments are not yet supported",
-		}
-	}
-
 	account, err := w.Manager.LookupAccount(cmd.FromAccount)
 	if err != nil {
 		return nil, err
@@ -1484,7 +1890,12 @@ func sendFrom(icmd interface{}, w *wallet.Wallet, chainClient *chain.RPCClient)
 		cmd.ToAddress: amt,
 	}
 
-	return sendPairs(w, pairs, account, minConf)
+	txHashStr, err := sendPairs(w, pairs, account, minConf, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	labelSentTx(w, txHashStr, cmd.Comment, cmd.CommentTo, cmd.ToAddress)
+	return txHashStr, nil
 }
 
 // sendMany handles a sendmany RPC request by creating a new transaction
@@ -1493,16 +1904,7 @@ func sendFrom(icmd interface{}, w *wallet.Wallet, chainClient *chain.RPCClient)
 // or a fee for the miner are sent back to a new address in the wallet.
 // Upon success, the TxID for the created transaction is returned.
 func sendMany(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
-	cmd := icmd.(*btcjson.SendManyCmd)
-
-	// Transaction comments are not yet supported.  Error instead of
-	// pretending to save them.
-	if !isNilOrEmpty(cmd.Comment) {
-		return nil, &btcjson.RPCError{
-			Code:    btcjson.ErrRPCUnimplemented,
-			Message: "Transaction comments are not yet supported",
-		}
-	}
+	cmd := icmd.(*SendManyCmd)
 
 	account, err := w.Manager.LookupAccount(cmd.FromAccount)
 	if err != nil {
@@ -1525,7 +1927,55 @@ func sendMany(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 		pairs[k] = amt
 	}
 
-	return sendPairs(w, pairs, account, minConf)
+	// Use the requested fee rate, if any, instead of the estimated one.
+	var feeRate btcutil.Amount
+	if cmd.FeeRate != nil {
+		feeRate, err = btcutil.NewAmount(*cmd.FeeRate)
+		if err != nil {
+			return nil, err
+		}
+		if feeRate <= 0 {
+			return nil, InvalidParameterError{
+				errors.New("fee rate must be positive"),
+			}
+		}
+	}
+
+	var selector txauthor.CoinSelector
+	if cmd.CoinSelection != nil {
+		selector, err = decodeCoinSelection(*cmd.CoinSelection, w)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	txHashStr, err := sendPairs(w, pairs, account, minConf, feeRate, selector)
+	if err != nil {
+		return nil, err
+	}
+	labelSentTx(w, txHashStr, cmd.Comment, nil, "")
+	return txHashStr, nil
+}
+
+// decodeCoinSelection returns the coin selector for a coin selection strategy
+// name.
+func decodeCoinSelection(name string, w *wallet.Wallet) (txauthor.CoinSelector, error) {
+	switch name {
+	case "largest-first":
+		return txauthor.LargestFirst, nil
+	case "branch-and-bound":
+		return &txauthor.BranchAndBoundSelector{
+			RelayFeePerKb: w.RelayFee(),
+		}, nil
+	case "oldest-first":
+		return txauthor.OldestFirst, nil
+	case "random-improve":
+		return txauthor.RandomImprove, nil
+	default:
+		return nil, InvalidParameterError{
+			fmt.Errorf("unknown coin selection strategy %q", name),
+		}
+	}
 }
 
 // sendToAddress handles a sendtoaddress RPC request by creating a new
@@ -1536,15 +1986,6 @@ func sendMany(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 func sendToAddress(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	cmd := icmd.(*btcjson.SendToAddressCmd)
 
-	// Transaction comments are not yet supported.  Error instead of
-	// pretending to save them.
-	if !isNilOrEmpty(cmd.Comment) || !isNilOrEmpty(cmd.CommentTo) {
-		return nil, &btcjson.RPCError{
-			Code:    btcjson.ErrRPCUnimplemented,
-			Message: "Transaction comments are not yet supported",
-		}
-	}
-
 	amt, err := btcutil.NewAmount(cmd.Amount)
 	if err != nil {
 		return nil, err
@@ -1561,7 +2002,65 @@ func sendToAddress(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	}
 
 	// sendtoaddress always spends from the default account, this matches bitcoind
-	return sendPairs(w, pairs, waddrmgr.DefaultAccountNum, 1)
+	txHashStr, err := sendPairs(w, pairs, waddrmgr.DefaultAccountNum, 1, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	labelSentTx(w, txHashStr, cmd.Comment, cmd.CommentTo, cmd.Address)
+	return txHashStr, nil
+}
+
+// labelSentTx saves the comment of a sent transaction as the transaction's
+// label, and the commentTo describing the recipient as the label of the output
+// paying address.  The transaction has already been published, so failures
+// are logged rather than returned, to not hide the transaction hash from the
+// caller.
+func labelSentTx(w *wallet.Wallet, txHashStr string, comment, commentTo *string,
+	address string) {
+
+	txHash, err := chainhash.NewHashFromStr(txHashStr)
+	if err != nil {
+		log.Errorf("Cannot label transaction %v: %v", txHashStr, err)
+		return
+	}
+	if !isNilOrEmpty(comment) {
+		err := w.LabelTransaction(txHash, *comment)
+		if err != nil {
+			log.Errorf("Cannot label transaction %v: %v", txHash, err)
+		}
+	}
+	if isNilOrEmpty(commentTo) {
+		return
+	}
+	addr, err := decodeAddress(address, w.ChainParams())
+	if err != nil {
+		log.Errorf("Cannot label output of transaction %v: %v", txHash, err)
+		return
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		log.Errorf("Cannot label output of transaction %v: %v", txHash, err)
+		return
+	}
+	details, err := w.TxStore.TxDetails(txHash)
+	if err == nil && details == nil {
+		err = wallet.ErrTxNotFound
+	}
+	if err != nil {
+		log.Errorf("Cannot label output of transaction %v: %v", txHash, err)
+		return
+	}
+	for i, txOut := range details.MsgTx.TxOut {
+		if !bytes.Equal(txOut.PkScript, pkScript) {
+			continue
+		}
+		op := wire.OutPoint{Hash: *txHash, Index: uint32(i)}
+		err := w.LabelOutput(&op, *commentTo)
+		if err != nil {
+			log.Errorf("Cannot label output %v: %v", op, err)
+		}
+		return
+	}
 }
 
 // setTxFee sets the transaction fee per kilobyte added to transactions.
@@ -1584,7 +2083,7 @@ func setTxFee(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 }
 
 // signMessage signs the given message with the private key for the given
-// address
+// address, or the keys of the wallet which can spend from it
 func signMessage(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	cmd := icmd.(*btcjson.SignMessageCmd)
 
@@ -1593,38 +2092,62 @@ func signMessage(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 		return nil, err
 	}
 
-	ainfo, err := w.Manager.Address(addr)
-	if err != nil {
-		return nil, err
+	// Messages are signed in the legacy format of the reference client
+	// for P2PKH addresses, and with BIP0322 for all other addresses.
+	var sigbytes []byte
+	switch addr.(type) {
+	case *btcutil.AddressPubKeyHash, *btcutil.AddressPubKey:
+		sigbytes, err = w.SignMessage(addr, cmd.Message)
+	default:
+		var partial *psbt.Packet
+		sigbytes, partial, err = w.SignMessageBIP322(addr,
+			[]byte(cmd.Message), nil)
+		if err == nil && partial != nil {
+			return nil, &btcjson.RPCError{
+				Code: btcjson.ErrRPCWallet,
+				Message: fmt.Sprintf("Address '%s' requires "+
+					"signatures of keys not held by the wallet", addr),
+			}
+		}
 	}
-	pka, ok := ainfo.(waddrmgr.ManagedPubKeyAddress)
-	if !ok {
+	switch err {
+	case nil:
+	case wallet.ErrLegacyMessageAddress, wallet.ErrMessageNotSigned:
 		msg := fmt.Sprintf("Address '%s' does not have an associated private key", addr)
 		return nil, &btcjson.RPCError{
 			Code:    btcjson.ErrRPCInvalidAddressOrKey,
 			Message: msg,
 		}
-	}
-	privKey, err := pka.PrivKey()
-	if err != nil {
-		return nil, err
-	}
-
-	var buf bytes.Buffer
-	wire.WriteVarString(&buf, 0, "Bitcoin Signed Message:\n")
-	wire.WriteVarString(&buf, 0, cmd.Message)
-	messageHash := chainhash.DoubleHashB(buf.Bytes())
-	sigbytes, err := btcec.SignCompact(btcec.S256(), privKey,
-		messageHash, ainfo.Compressed())
-	if err != nil {
+	default:
 		return nil, err
 	}
 
 	return base64.StdEncoding.EncodeToString(sigbytes), nil
 }
 
+// decodeSigHashType returns the sighash type for a sighash flags parameter.
+func decodeSigHashType(flags string) (txscript.SigHashType, error) {
+	switch flags {
+	case "ALL":
+		return txscript.SigHashAll, nil
+	case "NONE":
+		return txscript.SigHashNone, nil
+	case "SINGLE":
+		return txscript.SigHashSingle, nil
+	case "ALL|ANYONECANPAY":
+		return txscript.SigHashAll | txscript.SigHashAnyOneCanPay, nil
+	case "NONE|ANYONECANPAY":
+		return txscript.SigHashNone | txscript.SigHashAnyOneCanPay, nil
+	case "SINGLE|ANYONECANPAY":
+		return txscript.SigHashSingle | txscript.SigHashAnyOneCanPay, nil
+	default:
+		e := errors.New("Invalid sighash parameter")
+		return 0, InvalidParameterError{e}
+	}
+}
+
 // signRawTransaction handles the signrawtransaction command.
-func signRawTransaction(icmd interface{}, w *wallet.Wallet, chainClient *chain.RPCClient) (interface{}, error) {
+func signRawTransaction(icmd interface{}, w *wallet.Wallet, chainClient chain.Interface) (interface{}, error) {
 	cmd := icmd.(*btcjson.SignRawTransactionCmd)
 
 	serializedTx, err := decodeHexStr(cmd.RawTx)
@@ -1638,23 +2161,9 @@ func signRawTransaction(icmd interface{}, w *wallet.Wallet, chainClient *chain.R
 		return nil, DeserializationError{e}
 	}
 
-	var hashType txscript.SigHashType
-	switch *cmd.Flags {
-	case "ALL":
-		hashType = txscript.SigHashAll
-	case "NONE":
-		hashType = txscript.SigHashNone
-	case "SINGLE":
-		hashType = txscript.SigHashSingle
-	case "ALL|ANYONECANPAY":
-		hashType = txscript.SigHashAll | txscript.SigHashAnyOneCanPay
-	case "NONE|ANYONECANPAY":
-		hashType = txscript.SigHashNone | txscript.SigHashAnyOneCanPay
-	case "SINGLE|ANYONECANPAY":
-		hashType = txscript.SigHashSingle | txscript.SigHashAnyOneCanPay
-	default:
-		e := errors.New("Invalid sighash parameter")
-		return nil, InvalidParameterError{e}
+	hashType, err := decodeSigHashType(*cmd.Flags)
+	if err != nil {
+		return nil, err
 	}
 
 	// TODO: really we probably should look these up with btcd anyway to
@@ -1702,18 +2211,21 @@ func signRawTransaction(icmd interface{}, w *wallet.Wallet, chainClient *chain.R
 	}
 
 	// Now we go and look for any inputs that we were not provided by
-	// querying btcd with getrawtransaction. We queue up a bunch of async
-	// requests and will wait for replies after we have checked the rest of
-	// the arguments.
+	// querying btcd with gettxout. We queue up a bunch of async requests
+	// and will wait for replies after we have checked the rest of the
+	// arguments.  Other chain backends can not look up outputs, so the
+	// remaining inputs must be outputs of the wallet.
 	requested := make(map[wire.OutPoint]btcrpcclient.FutureGetTxOutResult)
+	rpcClient, _ := chainClient.(*chain.RPCClient)
 	for _, txIn := range tx.TxIn {
-		// Did we get this outpoint from the arguments?
-		if _, ok := inputs[txIn.PreviousOutPoint]; ok {
+		// Did we get this outpoint from the arguments, or is there no
+		// server to request it from?
+		if _, ok := inputs[txIn.PreviousOutPoint]; ok || rpcClient == nil {
 			continue
 		}
 
 		// Asynchronously request the output script.
-		requested[txIn.PreviousOutPoint] = chainClient.GetTxOutAsync(
+		requested[txIn.PreviousOutPoint] = rpcClient.GetTxOutAsync(
 			&txIn.PreviousOutPoint.Hash, txIn.PreviousOutPoint.Index,
 			true)
 	}
@@ -1797,6 +2309,68 @@ func signRawTransaction(icmd interface{}, w *wallet.Wallet, chainClient *chain.R
 	}, nil
 }
 
+// restoreWallet handles a restorewallet request by creating a named wallet
+// from a backup file written by backupwallet, and loading it.
+func restoreWallet(icmd interface{}, s *Server, _ walletSelection) (interface{}, error) {
+	cmd := icmd.(*walletjson.RestoreWalletCmd)
+
+	f, err := os.Open(cmd.BackupFile)
+	if err != nil {
+		return nil, InvalidParameterError{err}
+	}
+	defer f.Close()
+
+	var passphrase []byte
+	if cmd.Passphrase != nil {
+		passphrase = []byte(*cmd.Passphrase)
+	}
+	err = s.walletLoader.RestoreNamedWallet(cmd.WalletName, f, passphrase)
+	switch err {
+	case nil:
+	case wallet.ErrInvalidWalletName, wallet.ErrMalformedBackup:
+		return nil, InvalidParameterError{err}
+	case wallet.ErrBackupPassphrase:
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCWalletPassphraseIncorrect,
+			Message: err.Error(),
+		}
+	case wallet.ErrExists, wallet.ErrLoaded:
+		return nil, &btcjson.RPCError{
+			Code: btcjson.ErrRPCWallet,
+			Message: fmt.Sprintf("Wallet %q already exists",
+				cmd.WalletName),
+		}
+	default:
+		return nil, err
+	}
+
+	_, err = s.walletLoader.OpenNamedWallet(cmd.WalletName, s.walletPubPass,
+		false)
+	if err != nil {
+		return nil, err
+	}
+	return &walletjson.LoadWalletResult{Name: cmd.WalletName}, nil
+}
+
+// unloadWallet handles the unloadwallet command.  The wallet selected by the
+// request is unloaded when the command does not name a wallet.
+func unloadWallet(icmd interface{}, s *Server, sel walletSelection) (interface{}, error) {
+	cmd := icmd.(*walletjson.UnloadWalletCmd)
+
+	name := sel.name
+	if cmd.WalletName != nil {
+		name = *cmd.WalletName
+	} else if sel.err != nil {
+		return nil, sel.err
+	}
+
+	err := s.walletLoader.UnloadNamedWallet(name)
+	if err == wallet.ErrNotLoaded {
+		return nil, &ErrWalletNotFound
+	}
+	return nil, err
+}
+
 // validateAddress handles the validateaddress command.
 func validateAddress(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	cmd := icmd.(*btcjson.ValidateAddressCmd)
@@ -1878,7 +2452,7 @@ func validateAddress(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 }
 
 // verifyMessage handles the verifymessage command by verifying the provided
-// compact signature for the given address and message.
+// legacy or BIP0322 signature for the given address and message.
 func verifyMessage(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	cmd := icmd.(*btcjson.VerifyMessageCmd)
 
@@ -1893,33 +2467,9 @@ func verifyMessage(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 		return nil, err
 	}
 
-	// Validate the signature - this just shows that it was valid at all.
-	// we will compare it with the key next.
-	var buf bytes.Buffer
-	wire.WriteVarString(&buf, 0, "Bitcoin Signed Message:\n")
-	wire.WriteVarString(&buf, 0, cmd.Message)
-	expectedMessageHash := chainhash.DoubleHashB(buf.Bytes())
-	pk, wasCompressed, err := btcec.RecoverCompact(btcec.S256(), sig,
-		expectedMessageHash)
-	if err != nil {
-		return nil, err
-	}
-
-	var serializedPubKey []byte
-	if wasCompressed {
-		serializedPubKey = pk.SerializeCompressed()
-	} else {
-		serializedPubKey = pk.SerializeUncompressed()
-	}
-	// Verify that the signed-by address matches the given address
-	switch checkAddr := addr.(type) {
-	case *btcutil.AddressPubKeyHash: // ok
-		return bytes.Equal(btcutil.Hash160(serializedPubKey), checkAddr.Hash160()[:]), nil
-	case *btcutil.AddressPubKey: // ok
-		return string(serializedPubKey) == checkAddr.String(), nil
-	default:
-		return nil, errors.New("address type not supported")
-	}
+	// Signatures of P2PKH addresses may be in the legacy format, and
+	// signatures of all addresses in either BIP0322 format.
+	return wallet.VerifyMessage(addr, cmd.Message, sig)
 }
 
 // walletIsLocked handles the walletislocked extension request by
@@ -1943,12 +2493,11 @@ func walletLock(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 func walletPassphrase(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
 	cmd := icmd.(*btcjson.WalletPassphraseCmd)
 
-	timeout := time.Second * time.Duration(cmd.Timeout)
-	var unlockAfter <-chan time.Time
-	if timeout != 0 {
-		unlockAfter = time.After(timeout)
+	var lockTime time.Time
+	if cmd.Timeout != 0 {
+		lockTime = time.Now().Add(time.Second * time.Duration(cmd.Timeout))
 	}
-	err := w.Unlock([]byte(cmd.Passphrase), unlockAfter)
+	err := w.UnlockUntil([]byte(cmd.Passphrase), lockTime)
 	return nil, err
 }
 
@@ -1973,6 +2522,190 @@ func walletPassphraseChange(icmd interface{}, w *wallet.Wallet) (interface{}, er
 	return nil, err
 }
 
+// walletCreateFundedPsbt handles the walletcreatefundedpsbt command by
+// creating a PSBT paying the requested outputs, funded by inputs and change
+// of the wallet.
+func walletCreateFundedPsbt(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.WalletCreateFundedPsbtCmd)
+
+	account := uint32(waddrmgr.DefaultAccountNum)
+	minConf := int32(1)
+	var feeRate btcutil.Amount
+	var selector txauthor.CoinSelector
+	var err error
+	if opts := cmd.Options; opts != nil {
+		if opts.Account != nil {
+			account, err = w.Manager.LookupAccount(*opts.Account)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if opts.MinConf != nil {
+			minConf = int32(*opts.MinConf)
+			if minConf < 0 {
+				return nil, ErrNeedPositiveMinconf
+			}
+		}
+		if opts.FeeRate != nil {
+			feeRate, err = btcutil.NewAmount(*opts.FeeRate)
+			if err != nil {
+				return nil, err
+			}
+			if feeRate <= 0 {
+				return nil, InvalidParameterError{
+					errors.New("fee rate must be positive"),
+				}
+			}
+		}
+		if opts.CoinSelection != nil {
+			selector, err = decodeCoinSelection(*opts.CoinSelection, w)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	tx := wire.NewMsgTx(wire.TxVersion)
+	for _, input := range cmd.Inputs {
+		txHash, err := chainhash.NewHashFromStr(input.Txid)
+		if err != nil {
+			return nil, DeserializationError{err}
+		}
+		txIn := wire.NewTxIn(wire.NewOutPoint(txHash, input.Vout), nil, nil)
+		txIn.Sequence = txrules.MaxRBFSequence
+		tx.AddTxIn(txIn)
+	}
+	pairs := make(map[string]btcutil.Amount, len(cmd.Outputs))
+	for addr, amount := range cmd.Outputs {
+		amt, err := btcutil.NewAmount(amount)
+		if err != nil {
+			return nil, err
+		}
+		if amt <= 0 {
+			return nil, ErrNeedPositiveAmount
+		}
+		pairs[addr] = amt
+	}
+	outputs, err := makeOutputs(pairs, w.ChainParams())
+	if err != nil {
+		return nil, InvalidParameterError{err}
+	}
+	tx.TxOut = outputs
+	if cmd.LockTime != nil {
+		tx.LockTime = *cmd.LockTime
+	}
+
+	packet, err := psbt.New(tx)
+	if err != nil {
+		return nil, err
+	}
+	changeIndex, fee, err := w.FundPsbt(packet, account, minConf, feeRate,
+		selector)
+	switch err {
+	case nil:
+	case wallet.ErrTxNotOwned:
+		return nil, InvalidParameterError{err}
+	default:
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInternal.Code,
+			Message: err.Error(),
+		}
+	}
+
+	b64, err := packet.B64Encode()
+	if err != nil {
+		return nil, err
+	}
+	return &walletjson.WalletCreateFundedPsbtResult{
+		Psbt:      b64,
+		Fee:       fee.ToBTC(),
+		ChangePos: changeIndex,
+	}, nil
+}
+
+// walletProcessPsbt handles the walletprocesspsbt command by signing the
+// inputs of a PSBT spending wallet outputs and finalizing every input which
+// is completely signed.
+func walletProcessPsbt(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.WalletProcessPsbtCmd)
+
+	packet, err := decodePsbt(cmd.Psbt)
+	if err != nil {
+		return nil, err
+	}
+	hashType, err := decodeSigHashType(*cmd.SighashType)
+	if err != nil {
+		return nil, err
+	}
+
+	if *cmd.Sign {
+		_, err := w.SignPsbt(packet, hashType)
+		switch {
+		case err == nil:
+		case err == wallet.ErrSighashMismatch:
+			return nil, InvalidParameterError{err}
+		case waddrmgr.IsError(err, waddrmgr.ErrLocked):
+			return nil, &ErrWalletUnlockNeeded
+		default:
+			return nil, err
+		}
+	}
+
+	// Inputs which can not be finalized yet are left for other signers.
+	_ = psbt.MaybeFinalizeAll(packet)
+
+	b64, err := packet.B64Encode()
+	if err != nil {
+		return nil, err
+	}
+	return &walletjson.WalletProcessPsbtResult{
+		Psbt:     b64,
+		Complete: packet.IsComplete(),
+	}, nil
+}
+
+// finalizePsbt handles the finalizepsbt command by finalizing every input of
+// a PSBT which is completely signed, and extracting the signed transaction
+// when all inputs are finalized.
+func finalizePsbt(icmd interface{}, w *wallet.Wallet) (interface{}, error) {
+	cmd := icmd.(*walletjson.FinalizePsbtCmd)
+
+	packet, err := decodePsbt(cmd.Psbt)
+	if err != nil {
+		return nil, err
+	}
+	_ = psbt.MaybeFinalizeAll(packet)
+
+	result := &walletjson.FinalizePsbtResult{Complete: packet.IsComplete()}
+	if result.Complete && *cmd.Extract {
+		tx, err := psbt.Extract(packet)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		buf.Grow(tx.SerializeSize())
+		if err := tx.Serialize(&buf); err != nil {
+			return nil, err
+		}
+		result.Hex = hex.EncodeToString(buf.Bytes())
+		return result, nil
+	}
+	result.Psbt, err = packet.B64Encode()
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// decodePsbt decodes a base64 encoded PSBT.
+func decodePsbt(b64 string) (*psbt.Packet, error) {
+	packet, err := psbt.NewFromRawBytes(strings.NewReader(b64), true)
+	if err != nil {
+		return nil, DeserializationError{err}
+	}
+	return packet, nil
+}
+
 // decodeHexStr decodes the hex encoding of a string, possibly prepending a
 // leading '0' character if there is an odd number of bytes in the hex string.
 // This is to prevent an error for an invalid hex string when using an odd
@@ -0,0 +1,110 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package legacyrpc
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcwallet/internal/walletjson"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wallet"
+	"github.com/btcsuite/btcwallet/walletdb"
+	_ "github.com/btcsuite/btcwallet/walletdb/memdb"
+)
+
+// TestGetWalletInfo ensures getwalletinfo reports the key pools of the
+// wallet's accounts, and the time the wallet is unlocked until only when the
+// wallet is locked or unlocked with a time limit.
+func TestGetWalletInfo(t *testing.T) {
+	pubPass := []byte("public")
+	privPass := []byte("private")
+	seed := make([]byte, 32)
+
+	db, err := walletdb.Create("memdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	err = wallet.CreateWithOptions(db, pubPass, privPass, seed,
+		&chaincfg.RegressionNetParams,
+		&waddrmgr.ScryptOptions{N: 16, R: 8, P: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := wallet.Open(db, pubPass, nil, &chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Start()
+	defer func() {
+		w.Stop()
+		w.WaitForShutdown()
+	}()
+
+	for i := 0; i < 2; i++ {
+		_, err := w.NewAddress(0, waddrmgr.PubKeyHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err = w.NewChangeAddress(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sel := walletSelection{name: "alice", wallet: w}
+	getInfo := func() *walletjson.GetWalletInfoResult {
+		t.Helper()
+		result, err := getWalletInfo(nil, nil, sel)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result.(*walletjson.GetWalletInfoResult)
+	}
+
+	info := getInfo()
+	relayFee := w.RelayFee().ToBTC()
+	locked := int64(0)
+	want := &walletjson.GetWalletInfoResult{
+		WalletName:            "alice",
+		WalletVersion:         int32(waddrmgr.LatestMgrVersion),
+		KeyPoolSize:           2,
+		KeyPoolSizeHDInternal: 1,
+		UnlockedUntil:         &locked,
+		PayTxFee:              relayFee,
+		RelayFee:              relayFee,
+		Accounts: []walletjson.GetWalletInfoAccountResult{{
+			Account:               "default",
+			KeyPoolSize:           2,
+			KeyPoolSizeHDInternal: 1,
+		}},
+	}
+	if !reflect.DeepEqual(info, want) {
+		t.Errorf("locked wallet info %+v, expected %+v", info, want)
+	}
+
+	err = w.Unlock(privPass, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info := getInfo(); info.UnlockedUntil != nil {
+		t.Errorf("wallet unlocked without a time limit is unlocked "+
+			"until %d", *info.UnlockedUntil)
+	}
+
+	lockTime := time.Now().Add(time.Hour)
+	err = w.UnlockUntil(privPass, lockTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info = getInfo()
+	if info.UnlockedUntil == nil || *info.UnlockedUntil != lockTime.Unix() {
+		t.Errorf("wallet is unlocked until %v, expected %d",
+			info.UnlockedUntil, lockTime.Unix())
+	}
+}
@@ -46,3 +46,28 @@ func TestThrottle(t *testing.T) {
 		t.Fatalf("status codes: want: %v, got: %v", want, got)
 	}
 }
+
+func TestRequestEndpoint(t *testing.T) {
+	tests := []struct {
+		path string
+		want walletEndpoint
+	}{
+		{"/", walletEndpoint{}},
+		{"/wallet", walletEndpoint{}},
+		{"/wallet/", walletEndpoint{name: "", named: true}},
+		{"/wallet/alice", walletEndpoint{name: "alice", named: true}},
+		{"/wallet/bob%20smith", walletEndpoint{name: "bob smith", named: true}},
+		{"/ws", walletEndpoint{}},
+		{"/ws/wallet", walletEndpoint{}},
+		{"/ws/wallet/alice", walletEndpoint{name: "alice", named: true}},
+	}
+
+	for _, test := range tests {
+		r := httptest.NewRequest("POST", test.path, nil)
+		got := requestEndpoint(r)
+		if got != test.want {
+			t.Errorf("path %q: want %+v, got %+v", test.path,
+				test.want, got)
+		}
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -29,17 +30,21 @@ type websocketClient struct {
 	conn          *websocket.Conn
 	authenticated bool
 	remoteAddr    string
+	endpoint      walletEndpoint
 	allRequests   chan []byte
 	responses     chan []byte
 	quit          chan struct{} // closed on disconnect
 	wg            sync.WaitGroup
 }
 
-func newWebsocketClient(c *websocket.Conn, authenticated bool, remoteAddr string) *websocketClient {
+func newWebsocketClient(c *websocket.Conn, authenticated bool, remoteAddr string,
+	endpoint walletEndpoint) *websocketClient {
+
 	return &websocketClient{
 		conn:          c,
 		authenticated: authenticated,
 		remoteAddr:    remoteAddr,
+		endpoint:      endpoint,
 		allRequests:   make(chan []byte),
 		responses:     make(chan []byte),
 		quit:          make(chan struct{}),
@@ -55,13 +60,56 @@ func (c *websocketClient) send(b []byte) error {
 	}
 }
 
+// walletPathPrefix is the URL path prefix of requests made for a named wallet,
+// which is followed by the name of the wallet.
+const walletPathPrefix = "/wallet/"
+
+// websocketPath is the URL path of websocket connections.  Websocket clients
+// of a named wallet connect to the wallet path under the websocket path, and
+// every request made over the connection is made for that wallet.
+const websocketPath = "/ws"
+
+// walletEndpoint describes the wallet requested by the URL path of a client
+// request.  Requests made to any path other than a wallet path do not name a
+// wallet.
+type walletEndpoint struct {
+	name  string
+	named bool
+}
+
+// requestEndpoint returns the wallet endpoint of an HTTP request or websocket
+// connection.
+func requestEndpoint(r *http.Request) walletEndpoint {
+	path := r.URL.Path
+	if strings.HasPrefix(path, websocketPath+walletPathPrefix) {
+		path = strings.TrimPrefix(path, websocketPath)
+	}
+	if !strings.HasPrefix(path, walletPathPrefix) {
+		return walletEndpoint{}
+	}
+	return walletEndpoint{
+		name:  strings.TrimPrefix(path, walletPathPrefix),
+		named: true,
+	}
+}
+
+// walletSelection is the wallet selected for a request.  When err is set, no
+// wallet could be selected and wallet methods must fail with the error.  The
+// wallet is nil without an error when no wallet is loaded and the request does
+// not name one.
+type walletSelection struct {
+	name   string
+	wallet *wallet.Wallet
+	err    *btcjson.RPCError
+}
+
 // Server holds the items the RPC server may need to access (auth,
 // config, shutdown, etc.)
 type Server struct {
 	httpServer    http.Server
-	wallet        *wallet.Wallet
 	walletLoader  *wallet.Loader
-	chainClient   *chain.RPCClient
+	walletPubPass []byte
+	chainClient   chain.Interface
 	handlerLookup func(string) (requestHandler, bool)
 	handlerMu     sync.Mutex
 
@@ -100,6 +148,7 @@ func NewServer(opts *Options, walletLoader *wallet.Loader, listeners []net.Liste
 			ReadTimeout: time.Second * rpcAuthTimeoutSeconds,
 		},
 		walletLoader:        walletLoader,
+		walletPubPass:       opts.WalletPubPassphrase,
 		maxPostClients:      opts.MaxPOSTClients,
 		maxWebsocketClients: opts.MaxWebsocketClients,
 		listeners:           listeners,
@@ -126,11 +175,11 @@ func NewServer(opts *Options, walletLoader *wallet.Loader, listeners []net.Liste
 				return
 			}
 			server.wg.Add(1)
-			server.postClientRPC(w, r)
+			server.postClientRPC(w, r, requestEndpoint(r))
 			server.wg.Done()
 		}))
 
-	serveMux.Handle("/ws", throttledFn(opts.MaxWebsocketClients,
+	wsHandler := throttledFn(opts.MaxWebsocketClients,
 		func(w http.ResponseWriter, r *http.Request) {
 			authenticated := false
 			switch server.checkAuthHeader(r) {
@@ -153,9 +202,12 @@ func NewServer(opts *Options, walletLoader *wallet.Loader, listeners []net.Liste
 					r.RemoteAddr, err)
 				return
 			}
-			wsc := newWebsocketClient(conn, authenticated, r.RemoteAddr)
+			wsc := newWebsocketClient(conn, authenticated,
+				r.RemoteAddr, requestEndpoint(r))
 			server.websocketClientRPC(wsc)
-		}))
+		})
+	serveMux.Handle(websocketPath, wsHandler)
+	serveMux.Handle(websocketPath+walletPathPrefix, wsHandler)
 
 	for _, lis := range listeners {
 		server.serve(lis)
@@ -196,17 +248,9 @@ func (s *Server) serve(lis net.Listener) {
 	}()
 }
 
-// RegisterWallet associates the legacy RPC server with the wallet.  This
-// function must be called before any wallet RPCs can be called by clients.
-func (s *Server) RegisterWallet(w *wallet.Wallet) {
-	s.handlerMu.Lock()
-	s.wallet = w
-	s.handlerMu.Unlock()
-}
-
 // Stop gracefully shuts down the rpc server by stopping and disconnecting all
-// clients, disconnecting the chain server connection, and closing the wallet's
-// account files.  This blocks until shutdown completes.
+// clients, disconnecting the chain server connection, and stopping the loaded
+// wallets.  This blocks until shutdown completes.
 func (s *Server) Stop() {
 	s.quitMtx.Lock()
 	select {
@@ -216,13 +260,13 @@ func (s *Server) Stop() {
 	default:
 	}
 
-	// Stop the connected wallet and chain server, if any.
+	// Stop the loaded wallets and the chain server, if any.
+	wallets := s.walletLoader.LoadedWallets()
 	s.handlerMu.Lock()
-	wallet := s.wallet
 	chainClient := s.chainClient
 	s.handlerMu.Unlock()
-	if wallet != nil {
-		wallet.Stop()
+	for _, w := range wallets {
+		w.Stop()
 	}
 	if chainClient != nil {
 		chainClient.Stop()
@@ -241,10 +285,10 @@ func (s *Server) Stop() {
 	close(s.quit)
 	s.quitMtx.Unlock()
 
-	// First wait for the wallet and chain server to stop, if they
+	// First wait for the wallets and chain server to stop, if they
 	// were ever set.
-	if wallet != nil {
-		wallet.WaitForShutdown()
+	for _, w := range wallets {
+		w.WaitForShutdown()
 	}
 	if chainClient != nil {
 		chainClient.WaitForShutdown()
@@ -256,9 +300,10 @@ func (s *Server) Stop() {
 
 // SetChainServer sets the chain server client component needed to run a fully
 // functional bitcoin wallet RPC server.  This can be called to enable RPC
-// passthrough even before a loaded wallet is set, but the wallet's RPC client
-// is preferred.
-func (s *Server) SetChainServer(chainClient *chain.RPCClient) {
+// passthrough even before a wallet is loaded.  When the chain server is shared
+// by several wallets, this must be the shared backend rather than the client
+// of any single wallet.
+func (s *Server) SetChainServer(chainClient chain.Interface) {
 	s.handlerMu.Lock()
 	s.chainClient = chainClient
 	s.handlerMu.Unlock()
@@ -271,18 +316,53 @@ func (s *Server) SetChainServer(chainClient *chain.RPCClient) {
 // NOTE: These handlers do not handle special cases, such as the authenticate
 // method.  Each of these must be checked beforehand (the method is already
 // known) and handled accordingly.
-func (s *Server) handlerClosure(request *btcjson.Request) lazyHandler {
+func (s *Server) handlerClosure(request *btcjson.Request, e walletEndpoint) lazyHandler {
+	sel := s.selectWallet(e)
+
 	s.handlerMu.Lock()
 	// With the lock held, make copies of these pointers for the closure.
-	wallet := s.wallet
 	chainClient := s.chainClient
-	if wallet != nil && chainClient == nil {
-		chainClient = wallet.ChainClient()
+	if sel.wallet != nil && chainClient == nil {
+		chainClient = sel.wallet.ChainClient()
+		if c, ok := chainClient.(*chain.MuxClient); ok {
+			chainClient = c.Backend()
+		}
 		s.chainClient = chainClient
 	}
 	s.handlerMu.Unlock()
 
-	return lazyApplyHandler(request, wallet, chainClient)
+	return lazyApplyHandler(request, s, sel, chainClient)
+}
+
+// selectWallet selects the loaded wallet for a request made to an endpoint.
+// Requests which do not name a wallet use the default wallet, or the only
+// loaded wallet when the default wallet is not loaded.
+func (s *Server) selectWallet(e walletEndpoint) walletSelection {
+	if e.named {
+		w, ok := s.walletLoader.NamedWallet(e.name)
+		if !ok {
+			return walletSelection{name: e.name, err: &ErrWalletNotFound}
+		}
+		return walletSelection{name: e.name, wallet: w}
+	}
+
+	if w, ok := s.walletLoader.LoadedWallet(); ok {
+		return walletSelection{name: wallet.DefaultWalletName, wallet: w}
+	}
+	names := s.walletLoader.LoadedWalletNames()
+	switch len(names) {
+	case 0:
+		return walletSelection{name: wallet.DefaultWalletName}
+	case 1:
+		w, ok := s.walletLoader.NamedWallet(names[0])
+		if !ok {
+			// Unloaded since listed.
+			return walletSelection{name: names[0], err: &ErrWalletNotFound}
+		}
+		return walletSelection{name: names[0], wallet: w}
+	default:
+		return walletSelection{err: &ErrWalletNotSpecified}
+	}
 }
 
 // ErrNoAuth represents an error where authentication could not succeed
@@ -477,7 +557,7 @@ out:
 
 			default:
 				req := req // Copy for the closure
-				f := s.handlerClosure(&req)
+				f := s.handlerClosure(&req, wsc.endpoint)
 				wsc.wg.Add(1)
 				go func() {
 					resp, jsonErr := f()
@@ -562,8 +642,9 @@ func (s *Server) websocketClientRPC(wsc *websocketClient) {
 // that may be read from a client.  This is currently limited to 4MB.
 const maxRequestSize = 1024 * 1024 * 4
 
-// postClientRPC processes and replies to a JSON-RPC client request.
-func (s *Server) postClientRPC(w http.ResponseWriter, r *http.Request) {
+// postClientRPC processes and replies to a JSON-RPC client request made to a
+// wallet endpoint.
+func (s *Server) postClientRPC(w http.ResponseWriter, r *http.Request, e walletEndpoint) {
 	body := http.MaxBytesReader(w, r.Body, maxRequestSize)
 	rpcRequest, err := ioutil.ReadAll(body)
 	if err != nil {
@@ -608,7 +689,7 @@ func (s *Server) postClientRPC(w http.ResponseWriter, r *http.Request) {
 		stop = true
 		res = "btcwallet stopping"
 	default:
-		res, jsonErr = s.handlerClosure(&req)()
+		res, jsonErr = s.handlerClosure(&req, e)()
 	}
 
 	// Marshal and send.
@@ -24,7 +24,9 @@ import (
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 
+	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
@@ -38,15 +40,20 @@ import (
 	pb "github.com/btcsuite/btcwallet/rpc/walletrpc"
 	"github.com/btcsuite/btcwallet/waddrmgr"
 	"github.com/btcsuite/btcwallet/wallet"
+	"github.com/btcsuite/btcwallet/wallet/bip322"
+	"github.com/btcsuite/btcwallet/wallet/bip39"
+	"github.com/btcsuite/btcwallet/wallet/psbt"
+	"github.com/btcsuite/btcwallet/wallet/txauthor"
 	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/btcsuite/btcwallet/wtxmgr"
 )
 
 // Public API version constants
 const (
-	semverString = "2.0.1"
+	semverString = "2.13.0"
 	semverMajor  = 2
-	semverMinor  = 0
-	semverPatch  = 1
+	semverMinor  = 13
+	semverPatch  = 0
 )
 
 // translateError creates a new gRPC error with an appropiate error code for
@@ -70,20 +77,32 @@ func errorCode(err error) codes.Code {
 		switch e.ErrorCode {
 		case waddrmgr.ErrWrongPassphrase: // public and private
 			return codes.InvalidArgument
-		case waddrmgr.ErrAccountNotFound:
+		case waddrmgr.ErrAccountNotFound, waddrmgr.ErrAddressNotFound:
 			return codes.NotFound
 		case waddrmgr.ErrInvalidAccount: // reserved account
 			return codes.InvalidArgument
 		case waddrmgr.ErrDuplicateAccount:
 			return codes.AlreadyExists
+		case waddrmgr.ErrUnsupportedAddressType:
+			return codes.InvalidArgument
+		case waddrmgr.ErrKeyChain, waddrmgr.ErrWrongNet:
+			return codes.InvalidArgument
+		case waddrmgr.ErrWatchingOnly:
+			return codes.FailedPrecondition
 		}
 
 		err = e.Err
 	}
 
+	if _, ok := err.(*bip39.UnknownWordError); ok {
+		return codes.InvalidArgument
+	}
+
 	switch err {
 	case wallet.ErrLoaded:
 		return codes.FailedPrecondition
+	case wallet.ErrInvalidWalletName:
+		return codes.InvalidArgument
 	case walletdb.ErrDbNotOpen:
 		return codes.Aborted
 	case walletdb.ErrDbExists:
@@ -92,6 +111,20 @@ func errorCode(err error) codes.Code {
 		return codes.NotFound
 	case hdkeychain.ErrInvalidSeedLen:
 		return codes.InvalidArgument
+	case wallet.ErrTxNotFound:
+		return codes.NotFound
+	case wallet.ErrTxMined, wallet.ErrTxNotReplaceable, wallet.ErrTxNotOwned,
+		wallet.ErrTxHasSpenders:
+		return codes.FailedPrecondition
+	case wallet.ErrFeeRateTooLow, wallet.ErrSighashMismatch:
+		return codes.InvalidArgument
+	case wallet.ErrLegacyMessageAddress, wallet.ErrMessageNotSigned,
+		bip322.ErrPacketMismatch, bip322.ErrMalformedSignature:
+		return codes.InvalidArgument
+	case bip39.ErrChecksum, bip39.ErrMnemonicLen:
+		return codes.InvalidArgument
+	case txauthor.ErrInsufficientFunds:
+		return codes.FailedPrecondition
 	default:
 		return codes.Unknown
 	}
@@ -102,13 +135,15 @@ func errorCode(err error) codes.Code {
 type versionServer struct {
 }
 
-// walletServer provides wallet services for RPC clients.
+// walletServer provides wallet services for RPC clients.  Each request is
+// served by the loaded wallet named by the request's metadata.
 type walletServer struct {
-	wallet *wallet.Wallet
+	loader *wallet.Loader
 }
 
 // loaderServer provides RPC clients with the ability to load and close wallets,
-// as well as establishing a RPC connection to a btcd consensus server.
+// as well as establishing a RPC connection to a btcd consensus server, which
+// is shared by all loaded wallets.
 type loaderServer struct {
 	loader    *wallet.Loader
 	activeNet *netparams.Params
@@ -131,13 +166,53 @@ func (*versionServer) Version(ctx context.Context, req *pb.VersionRequest) (*pb.
 	}, nil
 }
 
-// StartWalletService creates an implementation of the WalletService and
-// registers it with the gRPC server.
-func StartWalletService(server *grpc.Server, wallet *wallet.Wallet) {
-	service := &walletServer{wallet}
+// WalletNameMetadataKey is the gRPC metadata key naming the loaded wallet a
+// WalletService request is made for.  Requests without the key are served by
+// the default wallet, or by the only loaded wallet when the default wallet is
+// not loaded.
+const WalletNameMetadataKey = "wallet-name"
+
+// StartWalletService creates an implementation of the WalletService serving
+// the wallets loaded by the loader and registers it with the gRPC server.
+func StartWalletService(server *grpc.Server, loader *wallet.Loader) {
+	service := &walletServer{loader}
 	pb.RegisterWalletServiceServer(server, service)
 }
 
+// requestWallet returns the loaded wallet a request is made for.
+func (s *walletServer) requestWallet(ctx context.Context) (*wallet.Wallet, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if names := md.Get(WalletNameMetadataKey); len(names) != 0 {
+		w, ok := s.loader.NamedWallet(names[0])
+		if !ok {
+			return nil, grpc.Errorf(codes.NotFound,
+				"wallet %q is not loaded", names[0])
+		}
+		return w, nil
+	}
+
+	if w, ok := s.loader.LoadedWallet(); ok {
+		return w, nil
+	}
+	names := s.loader.LoadedWalletNames()
+	switch len(names) {
+	case 0:
+		return nil, grpc.Errorf(codes.FailedPrecondition,
+			"no wallet is loaded")
+	case 1:
+		w, ok := s.loader.NamedWallet(names[0])
+		if !ok {
+			return nil, grpc.Errorf(codes.NotFound,
+				"wallet %q is not loaded", names[0])
+		}
+		return w, nil
+	default:
+		return nil, grpc.Errorf(codes.InvalidArgument,
+			"wallet not specified (the %s metadata is required when "+
+				"several wallets are loaded)", WalletNameMetadataKey)
+	}
+}
+
 func (s *walletServer) Ping(ctx context.Context, req *pb.PingRequest) (*pb.PingResponse, error) {
 	return &pb.PingResponse{}, nil
 }
@@ -145,13 +220,23 @@ func (s *walletServer) Ping(ctx context.Context, req *pb.PingRequest) (*pb.PingR
 func (s *walletServer) Network(ctx context.Context, req *pb.NetworkRequest) (
 	*pb.NetworkResponse, error) {
 
-	return &pb.NetworkResponse{ActiveNetwork: uint32(s.wallet.ChainParams().Net)}, nil
+	w, err := s.requestWallet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.NetworkResponse{ActiveNetwork: uint32(w.ChainParams().Net)}, nil
 }
 
 func (s *walletServer) AccountNumber(ctx context.Context, req *pb.AccountNumberRequest) (
 	*pb.AccountNumberResponse, error) {
 
-	accountNum, err := s.wallet.Manager.LookupAccount(req.AccountName)
+	w, err := s.requestWallet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	accountNum, err := w.Manager.LookupAccount(req.AccountName)
 	if err != nil {
 		return nil, translateError(err)
 	}
@@ -162,7 +247,12 @@ func (s *walletServer) AccountNumber(ctx context.Context, req *pb.AccountNumberR
 func (s *walletServer) Accounts(ctx context.Context, req *pb.AccountsRequest) (
 	*pb.AccountsResponse, error) {
 
-	resp, err := s.wallet.Accounts()
+	w, err := s.requestWallet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := w.Accounts()
 	if err != nil {
 		return nil, translateError(err)
 	}
@@ -176,6 +266,8 @@ func (s *walletServer) Accounts(ctx context.Context, req *pb.AccountsRequest) (
 			ExternalKeyCount: a.ExternalKeyCount,
 			InternalKeyCount: a.InternalKeyCount,
 			ImportedKeyCount: a.ImportedKeyCount,
+			AddressType:      marshalAddressType(a.AddressType),
+			WatchOnly:        a.WatchOnly,
 		}
 	}
 	return &pb.AccountsResponse{
@@ -188,7 +280,12 @@ func (s *walletServer) Accounts(ctx context.Context, req *pb.AccountsRequest) (
 func (s *walletServer) RenameAccount(ctx context.Context, req *pb.RenameAccountRequest) (
 	*pb.RenameAccountResponse, error) {
 
-	err := s.wallet.RenameAccount(req.AccountNumber, req.NewName)
+	w, err := s.requestWallet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = w.RenameAccount(req.AccountNumber, req.NewName)
 	if err != nil {
 		return nil, translateError(err)
 	}
@@ -201,20 +298,29 @@ func (s *walletServer) NextAccount(ctx context.Context, req *pb.NextAccountReque
 
 	defer zero.Bytes(req.Passphrase)
 
+	w, err := s.requestWallet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	if req.AccountName == "" {
 		return nil, grpc.Errorf(codes.InvalidArgument, "account name may not be empty")
 	}
+	addrType, err := unmarshalAddressType(req.AddressType)
+	if err != nil {
+		return nil, err
+	}
 
 	lock := make(chan time.Time, 1)
 	defer func() {
 		lock <- time.Time{} // send matters, not the value
 	}()
-	err := s.wallet.Unlock(req.Passphrase, lock)
+	err = w.Unlock(req.Passphrase, lock)
 	if err != nil {
 		return nil, translateError(err)
 	}
 
-	account, err := s.wallet.NextAccount(req.AccountName)
+	account, err := w.NextAccount(req.AccountName, addrType)
 	if err != nil {
 		return nil, translateError(err)
 	}
@@ -222,18 +328,50 @@ func (s *walletServer) NextAccount(ctx context.Context, req *pb.NextAccountReque
 	return &pb.NextAccountResponse{AccountNumber: account}, nil
 }
 
+func marshalAddressType(t waddrmgr.AddressType) pb.AddressType {
+	switch t {
+	case waddrmgr.WitnessPubKey:
+		return pb.AddressType_WITNESS_PUBKEY_HASH
+	case waddrmgr.NestedWitnessPubKey:
+		return pb.AddressType_NESTED_WITNESS_PUBKEY_HASH
+	default:
+		return pb.AddressType_PUBKEY_HASH
+	}
+}
+
+func unmarshalAddressType(t pb.AddressType) (waddrmgr.AddressType, error) {
+	switch t {
+	case pb.AddressType_PUBKEY_HASH:
+		return waddrmgr.PubKeyHash, nil
+	case pb.AddressType_WITNESS_PUBKEY_HASH:
+		return waddrmgr.WitnessPubKey, nil
+	case pb.AddressType_NESTED_WITNESS_PUBKEY_HASH:
+		return waddrmgr.NestedWitnessPubKey, nil
+	default:
+		return 0, grpc.Errorf(codes.InvalidArgument,
+			"unknown address type %v", t)
+	}
+}
+
 func (s *walletServer) NextAddress(ctx context.Context, req *pb.NextAddressRequest) (
 	*pb.NextAddressResponse, error) {
 
-	var (
-		addr btcutil.Address
-		err  error
-	)
+	w, err := s.requestWallet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var addr btcutil.Address
 	switch req.Kind {
 	case pb.NextAddressRequest_BIP0044_EXTERNAL:
-		addr, err = s.wallet.NewAddress(req.Account)
+		var props *waddrmgr.AccountProperties
+		props, err = w.Manager.AccountProperties(req.Account)
+		if err != nil {
+			break
+		}
+		addr, err = w.NewAddress(req.Account, props.AddressType)
 	case pb.NextAddressRequest_BIP0044_INTERNAL:
-		addr, err = s.wallet.NewChangeAddress(req.Account)
+		addr, err = w.NewChangeAddress(req.Account)
 	default:
 		return nil, grpc.Errorf(codes.InvalidArgument, "kind=%v", req.Kind)
 	}
@@ -249,6 +387,11 @@ func (s *walletServer) ImportPrivateKey(ctx context.Context, req *pb.ImportPriva
 
 	defer zero.Bytes(req.Passphrase)
 
+	w, err := s.requestWallet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	wif, err := btcutil.DecodeWIF(req.PrivateKeyWif)
 	if err != nil {
 		return nil, grpc.Errorf(codes.InvalidArgument,
@@ -259,7 +402,7 @@ func (s *walletServer) ImportPrivateKey(ctx context.Context, req *pb.ImportPriva
 	defer func() {
 		lock <- time.Time{} // send matters, not the value
 	}()
-	err = s.wallet.Unlock(req.Passphrase, lock)
+	err = w.Unlock(req.Passphrase, lock)
 	if err != nil {
 		return nil, translateError(err)
 	}
@@ -271,7 +414,7 @@ func (s *walletServer) ImportPrivateKey(ctx context.Context, req *pb.ImportPriva
 			"Only the imported account accepts private key imports")
 	}
 
-	_, err = s.wallet.ImportPrivateKey(wif, nil, req.Rescan)
+	_, err = w.ImportPrivateKey(wif, nil, req.Rescan)
 	if err != nil {
 		return nil, translateError(err)
 	}
@@ -279,12 +422,47 @@ func (s *walletServer) ImportPrivateKey(ctx context.Context, req *pb.ImportPriva
 	return &pb.ImportPrivateKeyResponse{}, nil
 }
 
+func (s *walletServer) ImportAccount(ctx context.Context, req *pb.ImportAccountRequest) (
+	*pb.ImportAccountResponse, error) {
+
+	w, err := s.requestWallet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.AccountName == "" {
+		return nil, grpc.Errorf(codes.InvalidArgument, "account name may not be empty")
+	}
+	acctKey, err := hdkeychain.NewKeyFromString(req.ExtendedPublicKey)
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument,
+			"Invalid extended public key: %v", err)
+	}
+	addrType, err := unmarshalAddressType(req.AddressType)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := w.ImportAccount(req.AccountName, acctKey,
+		addrType, nil, req.Rescan)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &pb.ImportAccountResponse{AccountNumber: account}, nil
+}
+
 func (s *walletServer) Balance(ctx context.Context, req *pb.BalanceRequest) (
 	*pb.BalanceResponse, error) {
 
+	w, err := s.requestWallet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	account := req.AccountNumber
 	reqConfs := req.RequiredConfirmations
-	bals, err := s.wallet.CalculateAccountBalances(account, reqConfs)
+	bals, err := w.CalculateAccountBalances(account, reqConfs)
 	if err != nil {
 		return nil, translateError(err)
 	}
@@ -320,41 +498,66 @@ func confirms(txHeight, curHeight int32) int32 {
 func (s *walletServer) FundTransaction(ctx context.Context, req *pb.FundTransactionRequest) (
 	*pb.FundTransactionResponse, error) {
 
+	w, err := s.requestWallet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	// TODO: A predicate function for selecting outputs should be created
 	// and passed to a database view of just a particular account's utxos to
 	// prevent reading every unspent transaction output from every account
 	// into memory at once.
 
-	syncBlock := s.wallet.Manager.SyncedTo()
+	feeRate := btcutil.Amount(req.FeeRate)
+	switch {
+	case feeRate == 0:
+		feeRate = w.FeeRate()
+	case feeRate < w.RelayFee():
+		return nil, grpc.Errorf(codes.InvalidArgument,
+			"fee rate may not be negative or less than the relay fee")
+	}
+
+	syncBlock := w.Manager.SyncedTo()
 
-	outputs, err := s.wallet.TxStore.UnspentOutputs()
+	outputs, err := w.TxStore.UnspentOutputs()
 	if err != nil {
 		return nil, translateError(err)
 	}
+	locks, err := w.LockedOutpoints()
+	if err != nil {
+		return nil, translateError(err)
+	}
+	locked := make(map[wire.OutPoint]struct{}, len(locks))
+	for i := range locks {
+		locked[locks[i].OutPoint] = struct{}{}
+	}
 
-	selectedOutputs := make([]*pb.FundTransactionResponse_PreviousOutput, 0, len(outputs))
-	var totalAmount btcutil.Amount
+	eligible := make([]txauthor.Coin, 0, len(outputs))
+	eligibleOutputs := make(map[wire.OutPoint]*wtxmgr.Credit, len(outputs))
 	for i := range outputs {
 		output := &outputs[i]
 
 		if !confirmed(req.RequiredConfirmations, output.Height, syncBlock.Height) {
 			continue
 		}
-		target := int32(s.wallet.ChainParams().CoinbaseMaturity)
+		target := int32(w.ChainParams().CoinbaseMaturity)
 		if !req.IncludeImmatureCoinbases && output.FromCoinBase &&
 			!confirmed(target, output.Height, syncBlock.Height) {
 			continue
 		}
+		if _, ok := locked[output.OutPoint]; ok {
+			continue
+		}
 
 		_, addrs, _, err := txscript.ExtractPkScriptAddrs(
-			output.PkScript, s.wallet.ChainParams())
+			output.PkScript, w.ChainParams())
 		if err != nil || len(addrs) == 0 {
 			// Cannot determine which account this belongs to
 			// without a valid address.  Fix this by saving
 			// outputs per account (per-account wtxmgr).
 			continue
 		}
-		outputAcct, err := s.wallet.Manager.AddrAccount(addrs[0])
+		outputAcct, err := w.Manager.AddrAccount(addrs[0])
 		if err != nil {
 			return nil, translateError(err)
 		}
@@ -362,6 +565,57 @@ func (s *walletServer) FundTransaction(ctx context.Context, req *pb.FundTransact
 			continue
 		}
 
+		eligible = append(eligible, txauthor.Coin{
+			OutPoint:     output.OutPoint,
+			Value:        output.Amount,
+			PkScript:     output.PkScript,
+			RedeemScript: w.RedeemScript(output.PkScript),
+			Height:       output.Height,
+		})
+		eligibleOutputs[output.OutPoint] = output
+	}
+
+	// All eligible outputs are returned when there is no target amount.
+	// Otherwise, outputs are selected for the target and the fee to spend
+	// the previous selection until the selection pays for its own fee or
+	// there are no more outputs to select.
+	selected := eligible
+	var totalAmount, estimatedFee btcutil.Amount
+	if req.TargetAmount != 0 {
+		selector, err := s.coinSelector(w, req.CoinSelection)
+		if err != nil {
+			return nil, err
+		}
+		targetAmount := btcutil.Amount(req.TargetAmount)
+		required := targetAmount
+		for {
+			selected, err = selector.SelectCoins(required, eligible)
+			if err != nil {
+				return nil, translateError(err)
+			}
+			totalAmount, estimatedFee = estimateCoinsFee(selected,
+				req.IncludeChangeScript, feeRate)
+			if totalAmount >= targetAmount+estimatedFee ||
+				len(selected) == len(eligible) {
+				break
+			}
+
+			// Selections are not monotonic in the target amount,
+			// so selecting again for an amount which does not
+			// increase could alternate between selections forever.
+			if targetAmount+estimatedFee <= required {
+				return nil, translateError(txauthor.ErrInsufficientFunds)
+			}
+			required = targetAmount + estimatedFee
+		}
+	} else {
+		totalAmount, estimatedFee = estimateCoinsFee(selected,
+			req.IncludeChangeScript, feeRate)
+	}
+
+	selectedOutputs := make([]*pb.FundTransactionResponse_PreviousOutput, 0, len(selected))
+	for i := range selected {
+		output := eligibleOutputs[selected[i].OutPoint]
 		selectedOutputs = append(selectedOutputs, &pb.FundTransactionResponse_PreviousOutput{
 			TransactionHash: output.OutPoint.Hash[:],
 			OutputIndex:     output.Index,
@@ -370,17 +624,11 @@ func (s *walletServer) FundTransaction(ctx context.Context, req *pb.FundTransact
 			ReceiveTime:     output.Received.Unix(),
 			FromCoinbase:    output.FromCoinBase,
 		})
-		totalAmount += output.Amount
-
-		if req.TargetAmount != 0 && totalAmount > btcutil.Amount(req.TargetAmount) {
-			break
-		}
-
 	}
 
 	var changeScript []byte
-	if req.IncludeChangeScript && totalAmount > btcutil.Amount(req.TargetAmount) {
-		changeAddr, err := s.wallet.NewChangeAddress(req.Account)
+	if req.IncludeChangeScript && totalAmount > btcutil.Amount(req.TargetAmount)+estimatedFee {
+		changeAddr, err := w.NewChangeAddress(req.Account)
 		if err != nil {
 			return nil, translateError(err)
 		}
@@ -394,9 +642,49 @@ func (s *walletServer) FundTransaction(ctx context.Context, req *pb.FundTransact
 		SelectedOutputs: selectedOutputs,
 		TotalAmount:     int64(totalAmount),
 		ChangePkScript:  changeScript,
+		FeeRate:         int64(feeRate),
+		EstimatedFee:    int64(estimatedFee),
 	}, nil
 }
 
+// coinSelector returns the coin selector implementing a coin selection
+// strategy.
+func (s *walletServer) coinSelector(w *wallet.Wallet,
+	strategy pb.CoinSelection) (txauthor.CoinSelector, error) {
+
+	switch strategy {
+	case pb.CoinSelection_LARGEST_FIRST:
+		return txauthor.LargestFirst, nil
+	case pb.CoinSelection_BRANCH_AND_BOUND:
+		return &txauthor.BranchAndBoundSelector{
+			RelayFeePerKb: w.RelayFee(),
+		}, nil
+	case pb.CoinSelection_OLDEST_FIRST:
+		return txauthor.OldestFirst, nil
+	case pb.CoinSelection_RANDOM_IMPROVE:
+		return txauthor.RandomImprove, nil
+	default:
+		return nil, grpc.Errorf(codes.InvalidArgument,
+			"unknown coin selection strategy %v", strategy)
+	}
+}
+
+// estimateCoinsFee returns the total value of coins and the estimated fee
+// required to spend them, optionally with a change output.
+func estimateCoinsFee(coins []txauthor.Coin, change bool,
+	feeRate btcutil.Amount) (total, fee btcutil.Amount) {
+
+	scripts := make([][]byte, 0, len(coins))
+	redeemScripts := make([][]byte, 0, len(coins))
+	for i := range coins {
+		total += coins[i].Value
+		scripts = append(scripts, coins[i].PkScript)
+		redeemScripts = append(redeemScripts, coins[i].RedeemScript)
+	}
+	return total, txauthor.EstimateFee(scripts, redeemScripts, nil, change,
+		feeRate)
+}
+
 func marshalGetTransactionsResult(wresp *wallet.GetTransactionsResult) (
 	*pb.GetTransactionsResponse, error) {
 
@@ -413,6 +701,11 @@ func marshalGetTransactionsResult(wresp *wallet.GetTransactionsResult) (
 func (s *walletServer) GetTransactions(ctx context.Context, req *pb.GetTransactionsRequest) (
 	resp *pb.GetTransactionsResponse, err error) {
 
+	w, err := s.requestWallet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	var startBlock, endBlock *wallet.BlockIdentifier
 	if req.StartingBlockHash != nil && req.StartingBlockHeight != 0 {
 		return nil, errors.New(
@@ -456,13 +749,60 @@ func (s *walletServer) GetTransactions(ctx context.Context, req *pb.GetTransacti
 
 	_ = minRecentTxs
 
-	gtr, err := s.wallet.GetTransactions(startBlock, endBlock, ctx.Done())
+	gtr, err := w.GetTransactions(startBlock, endBlock, ctx.Done())
 	if err != nil {
 		return nil, translateError(err)
 	}
 	return marshalGetTransactionsResult(gtr)
 }
 
+// backupChunkSize is the maximum number of backup bytes sent in each
+// BackupWallet response.
+const backupChunkSize = 64 * 1024
+
+// backupStream writes a wallet backup to a BackupWallet response stream.
+type backupStream struct {
+	svr pb.WalletService_BackupWalletServer
+}
+
+func (b backupStream) Write(p []byte) (int, error) {
+	n := 0
+	for len(p) != 0 {
+		chunk := p
+		if len(chunk) > backupChunkSize {
+			chunk = chunk[:backupChunkSize]
+		}
+		err := b.svr.Send(&pb.BackupWalletResponse{Data: chunk})
+		if err != nil {
+			return n, err
+		}
+		n += len(chunk)
+		p = p[len(chunk):]
+	}
+	return n, nil
+}
+
+func (s *walletServer) BackupWallet(req *pb.BackupWalletRequest,
+	svr pb.WalletService_BackupWalletServer) error {
+
+	defer zero.Bytes(req.Passphrase)
+
+	w, err := s.requestWallet(svr.Context())
+	if err != nil {
+		return err
+	}
+
+	if len(req.Passphrase) != 0 {
+		err = w.BackupEncrypted(backupStream{svr}, req.Passphrase)
+	} else {
+		err = w.Backup(backupStream{svr})
+	}
+	if err != nil {
+		return translateError(err)
+	}
+	return nil
+}
+
 func (s *walletServer) ChangePassphrase(ctx context.Context, req *pb.ChangePassphraseRequest) (
 	*pb.ChangePassphraseResponse, error) {
 
@@ -471,8 +811,18 @@ func (s *walletServer) ChangePassphrase(ctx context.Context, req *pb.ChangePassp
 		zero.Bytes(req.NewPassphrase)
 	}()
 
-	err := s.wallet.Manager.ChangePassphrase(req.OldPassphrase, req.NewPassphrase,
-		req.Key != pb.ChangePassphraseRequest_PUBLIC, &waddrmgr.DefaultScryptOptions)
+	w, err := s.requestWallet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Key == pb.ChangePassphraseRequest_PUBLIC {
+		err = w.ChangePublicPassphrase(req.OldPassphrase,
+			req.NewPassphrase)
+	} else {
+		err = w.Manager.ChangePassphrase(req.OldPassphrase,
+			req.NewPassphrase, true, w.PassphraseOptions())
+	}
 	if err != nil {
 		return nil, translateError(err)
 	}
@@ -487,8 +837,13 @@ func (s *walletServer) SignTransaction(ctx context.Context, req *pb.SignTransact
 
 	defer zero.Bytes(req.Passphrase)
 
+	w, err := s.requestWallet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	var tx wire.MsgTx
-	err := tx.Deserialize(bytes.NewReader(req.SerializedTransaction))
+	err = tx.Deserialize(bytes.NewReader(req.SerializedTransaction))
 	if err != nil {
 		return nil, grpc.Errorf(codes.InvalidArgument,
 			"Bytes do not represent a valid raw transaction: %v", err)
@@ -498,12 +853,12 @@ func (s *walletServer) SignTransaction(ctx context.Context, req *pb.SignTransact
 	defer func() {
 		lock <- time.Time{} // send matters, not the value
 	}()
-	err = s.wallet.Unlock(req.Passphrase, lock)
+	err = w.Unlock(req.Passphrase, lock)
 	if err != nil {
 		return nil, translateError(err)
 	}
 
-	invalidSigs, err := s.wallet.SignTransaction(&tx, txscript.SigHashAll, nil, nil, nil)
+	invalidSigs, err := w.SignTransaction(&tx, txscript.SigHashAll, nil, nil, nil)
 	if err != nil {
 		return nil, translateError(err)
 	}
@@ -537,14 +892,19 @@ func (s *walletServer) SignTransaction(ctx context.Context, req *pb.SignTransact
 func (s *walletServer) PublishTransaction(ctx context.Context, req *pb.PublishTransactionRequest) (
 	*pb.PublishTransactionResponse, error) {
 
+	w, err := s.requestWallet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	var msgTx wire.MsgTx
-	err := msgTx.Deserialize(bytes.NewReader(req.SignedTransaction))
+	err = msgTx.Deserialize(bytes.NewReader(req.SignedTransaction))
 	if err != nil {
 		return nil, grpc.Errorf(codes.InvalidArgument,
 			"Bytes do not represent a valid raw transaction: %v", err)
 	}
 
-	err = s.wallet.PublishTransaction(&msgTx)
+	err = w.PublishTransaction(&msgTx)
 	if err != nil {
 		return nil, translateError(err)
 	}
@@ -552,6 +912,391 @@ func (s *walletServer) PublishTransaction(ctx context.Context, req *pb.PublishTr
 	return &pb.PublishTransactionResponse{}, nil
 }
 
+func (s *walletServer) BumpFee(ctx context.Context, req *pb.BumpFeeRequest) (
+	*pb.BumpFeeResponse, error) {
+
+	defer zero.Bytes(req.Passphrase)
+
+	w, err := s.requestWallet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	txHash, err := chainhash.NewHash(req.TransactionHash)
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument,
+			"transaction_hash has invalid length")
+	}
+	if req.FeeRate < 0 {
+		return nil, grpc.Errorf(codes.InvalidArgument,
+			"fee rate may not be negative")
+	}
+
+	lock := make(chan time.Time, 1)
+	defer func() {
+		lock <- time.Time{} // send matters, not the value
+	}()
+	err = w.Unlock(req.Passphrase, lock)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	result, err := w.BumpFee(txHash, btcutil.Amount(req.FeeRate))
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	var serializedTransaction bytes.Buffer
+	serializedTransaction.Grow(result.Tx.SerializeSize())
+	err = result.Tx.Serialize(&serializedTransaction)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	replacementHash := result.Tx.TxHash()
+	return &pb.BumpFeeResponse{
+		TransactionHash: replacementHash[:],
+		Transaction:     serializedTransaction.Bytes(),
+		OriginalFee:     int64(result.OrigFee),
+		Fee:             int64(result.Fee),
+	}, nil
+}
+
+func (s *walletServer) LockOutpoint(ctx context.Context, req *pb.LockOutpointRequest) (
+	*pb.LockOutpointResponse, error) {
+
+	w, err := s.requestWallet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	txHash, err := chainhash.NewHash(req.TransactionHash)
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument,
+			"transaction_hash has invalid length")
+	}
+	if req.Duration < 0 {
+		return nil, grpc.Errorf(codes.InvalidArgument,
+			"duration may not be negative")
+	}
+
+	var expiration time.Time
+	var expirationTime int64
+	if req.Duration > 0 {
+		expiration = time.Now().Add(time.Duration(req.Duration) * time.Second)
+		expirationTime = expiration.Unix()
+	}
+	op := wire.OutPoint{Hash: *txHash, Index: req.OutputIndex}
+	err = w.LockOutpoint(op, req.LockId, expiration)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &pb.LockOutpointResponse{ExpirationTime: expirationTime}, nil
+}
+
+func (s *walletServer) UnlockOutpoint(ctx context.Context, req *pb.UnlockOutpointRequest) (
+	*pb.UnlockOutpointResponse, error) {
+
+	w, err := s.requestWallet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	txHash, err := chainhash.NewHash(req.TransactionHash)
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument,
+			"transaction_hash has invalid length")
+	}
+
+	op := wire.OutPoint{Hash: *txHash, Index: req.OutputIndex}
+	err = w.UnlockOutpoint(op)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &pb.UnlockOutpointResponse{}, nil
+}
+
+func (s *walletServer) ListLockedOutpoints(ctx context.Context, req *pb.ListLockedOutpointsRequest) (
+	*pb.ListLockedOutpointsResponse, error) {
+
+	w, err := s.requestWallet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	locks, err := w.LockedOutpoints()
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	lockedOutpoints := make([]*pb.ListLockedOutpointsResponse_LockedOutpoint, 0, len(locks))
+	for i := range locks {
+		lock := &locks[i]
+		var expirationTime int64
+		if !lock.Expiration.IsZero() {
+			expirationTime = lock.Expiration.Unix()
+		}
+		lockedOutpoints = append(lockedOutpoints, &pb.ListLockedOutpointsResponse_LockedOutpoint{
+			TransactionHash: lock.OutPoint.Hash[:],
+			OutputIndex:     lock.OutPoint.Index,
+			LockId:          lock.LockID,
+			ExpirationTime:  expirationTime,
+		})
+	}
+
+	return &pb.ListLockedOutpointsResponse{LockedOutpoints: lockedOutpoints}, nil
+}
+
+func (s *walletServer) LabelTransaction(ctx context.Context, req *pb.LabelTransactionRequest) (
+	*pb.LabelTransactionResponse, error) {
+
+	w, err := s.requestWallet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	txHash, err := chainhash.NewHash(req.TransactionHash)
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument,
+			"transaction_hash has invalid length")
+	}
+
+	err = w.LabelTransaction(txHash, req.Label)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &pb.LabelTransactionResponse{}, nil
+}
+
+func (s *walletServer) FundPsbt(ctx context.Context, req *pb.FundPsbtRequest) (
+	*pb.FundPsbtResponse, error) {
+
+	w, err := s.requestWallet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	packet, err := psbt.NewFromRawBytes(bytes.NewReader(req.Psbt), false)
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument,
+			"Bytes do not represent a valid PSBT: %v", err)
+	}
+	if req.RequiredConfirmations < 0 {
+		return nil, grpc.Errorf(codes.InvalidArgument,
+			"required_confirmations must be non-negative")
+	}
+	if req.FeeRate < 0 {
+		return nil, grpc.Errorf(codes.InvalidArgument,
+			"fee rate may not be negative")
+	}
+	selector, err := s.coinSelector(w, req.CoinSelection)
+	if err != nil {
+		return nil, err
+	}
+
+	changeIndex, fee, err := w.FundPsbt(packet, req.Account,
+		req.RequiredConfirmations, btcutil.Amount(req.FeeRate), selector)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	var buf bytes.Buffer
+	err = packet.Serialize(&buf)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &pb.FundPsbtResponse{
+		Psbt:        buf.Bytes(),
+		ChangeIndex: int32(changeIndex),
+		Fee:         int64(fee),
+	}, nil
+}
+
+func (s *walletServer) SignPsbt(ctx context.Context, req *pb.SignPsbtRequest) (
+	*pb.SignPsbtResponse, error) {
+
+	defer zero.Bytes(req.Passphrase)
+
+	w, err := s.requestWallet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	packet, err := psbt.NewFromRawBytes(bytes.NewReader(req.Psbt), false)
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument,
+			"Bytes do not represent a valid PSBT: %v", err)
+	}
+
+	lock := make(chan time.Time, 1)
+	defer func() {
+		lock <- time.Time{} // send matters, not the value
+	}()
+	err = w.Unlock(req.Passphrase, lock)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	signed, err := w.SignPsbt(packet, txscript.SigHashAll)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	var buf bytes.Buffer
+	err = packet.Serialize(&buf)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	signedInputs := make([]uint32, len(signed))
+	for i, index := range signed {
+		signedInputs[i] = uint32(index)
+	}
+	return &pb.SignPsbtResponse{
+		Psbt:         buf.Bytes(),
+		SignedInputs: signedInputs,
+	}, nil
+}
+
+func (s *walletServer) FinalizePsbt(ctx context.Context, req *pb.FinalizePsbtRequest) (
+	*pb.FinalizePsbtResponse, error) {
+
+	packet, err := psbt.NewFromRawBytes(bytes.NewReader(req.Psbt), false)
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument,
+			"Bytes do not represent a valid PSBT: %v", err)
+	}
+
+	// Inputs which can not be finalized yet are left for other signers.
+	_ = psbt.MaybeFinalizeAll(packet)
+
+	var buf bytes.Buffer
+	err = packet.Serialize(&buf)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	resp := &pb.FinalizePsbtResponse{
+		Psbt:     buf.Bytes(),
+		Complete: packet.IsComplete(),
+	}
+	if resp.Complete {
+		tx, err := psbt.Extract(packet)
+		if err != nil {
+			return nil, translateError(err)
+		}
+		var serializedTransaction bytes.Buffer
+		serializedTransaction.Grow(tx.SerializeSize())
+		err = tx.Serialize(&serializedTransaction)
+		if err != nil {
+			return nil, translateError(err)
+		}
+		resp.Transaction = serializedTransaction.Bytes()
+	}
+	return resp, nil
+}
+
+func (s *walletServer) SignMessage(ctx context.Context, req *pb.SignMessageRequest) (
+	*pb.SignMessageResponse, error) {
+
+	defer zero.Bytes(req.Passphrase)
+
+	w, err := s.requestWallet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	addr, err := decodeAddress(req.Address, w.ChainParams())
+	if err != nil {
+		return nil, err
+	}
+	var packet *psbt.Packet
+	if len(req.Psbt) != 0 {
+		packet, err = psbt.NewFromRawBytes(bytes.NewReader(req.Psbt), false)
+		if err != nil {
+			return nil, grpc.Errorf(codes.InvalidArgument,
+				"Bytes do not represent a valid PSBT: %v", err)
+		}
+	}
+
+	lock := make(chan time.Time, 1)
+	defer func() {
+		lock <- time.Time{} // send matters, not the value
+	}()
+	err = w.Unlock(req.Passphrase, lock)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	// Messages for P2PKH addresses are signed in the legacy format unless
+	// a BIP0322 signature is requested.
+	legacy := !req.Bip322 && packet == nil
+	switch addr.(type) {
+	case *btcutil.AddressPubKeyHash, *btcutil.AddressPubKey:
+	default:
+		legacy = false
+	}
+	var sig []byte
+	if legacy {
+		sig, err = w.SignMessage(addr, req.Message)
+	} else {
+		sig, packet, err = w.SignMessageBIP322(addr, []byte(req.Message),
+			packet)
+	}
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	resp := &pb.SignMessageResponse{
+		Signature: sig,
+		Complete:  packet == nil,
+	}
+	if packet != nil {
+		var buf bytes.Buffer
+		err = packet.Serialize(&buf)
+		if err != nil {
+			return nil, translateError(err)
+		}
+		resp.Psbt = buf.Bytes()
+	}
+	return resp, nil
+}
+
+func (s *walletServer) VerifyMessage(ctx context.Context, req *pb.VerifyMessageRequest) (
+	*pb.VerifyMessageResponse, error) {
+
+	w, err := s.requestWallet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	addr, err := decodeAddress(req.Address, w.ChainParams())
+	if err != nil {
+		return nil, err
+	}
+	valid, err := wallet.VerifyMessage(addr, req.Message, req.Signature)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &pb.VerifyMessageResponse{Valid: valid}, nil
+}
+
+// decodeAddress decodes an address of a request for the network of the
+// wallet.
+func decodeAddress(a string, params *chaincfg.Params) (btcutil.Address, error) {
+	addr, err := btcutil.DecodeAddress(a, params)
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument,
+			"Invalid address %q: %v", a, err)
+	}
+	if !addr.IsForNet(params) {
+		return nil, grpc.Errorf(codes.InvalidArgument,
+			"Address %q is not intended for use on %s", a, params.Name)
+	}
+	return addr, nil
+}
+
 func marshalTransactionInputs(v []wallet.TransactionSummaryInput) []*pb.TransactionDetails_Input {
 	inputs := make([]*pb.TransactionDetails_Input, len(v))
 	for i := range v {
@@ -573,6 +1318,7 @@ func marshalTransactionOutputs(v []wallet.TransactionSummaryOutput) []*pb.Transa
 			Index:    output.Index,
 			Account:  output.Account,
 			Internal: output.Internal,
+			Label:    output.Label,
 		}
 	}
 	return outputs
@@ -589,6 +1335,7 @@ func marshalTransactionDetails(v []wallet.TransactionSummary) []*pb.TransactionD
 			Credits:     marshalTransactionOutputs(tx.MyOutputs),
 			Fee:         int64(tx.Fee),
 			Timestamp:   tx.Timestamp,
+			Label:       tx.Label,
 		}
 	}
 	return txs
@@ -631,7 +1378,12 @@ func marshalAccountBalances(v []wallet.AccountBalance) []*pb.AccountBalance {
 func (s *walletServer) TransactionNotifications(req *pb.TransactionNotificationsRequest,
 	svr pb.WalletService_TransactionNotificationsServer) error {
 
-	n := s.wallet.NtfnServer.TransactionNotifications()
+	w, err := s.requestWallet(svr.Context())
+	if err != nil {
+		return err
+	}
+
+	n := w.NtfnServer.TransactionNotifications()
 	defer n.Done()
 
 	ctxDone := svr.Context().Done()
@@ -658,12 +1410,17 @@ func (s *walletServer) TransactionNotifications(req *pb.TransactionNotifications
 func (s *walletServer) SpentnessNotifications(req *pb.SpentnessNotificationsRequest,
 	svr pb.WalletService_SpentnessNotificationsServer) error {
 
+	w, err := s.requestWallet(svr.Context())
+	if err != nil {
+		return err
+	}
+
 	if req.NoNotifyUnspent && req.NoNotifySpent {
 		return grpc.Errorf(codes.InvalidArgument,
 			"no_notify_unspent and no_notify_spent may not both be true")
 	}
 
-	n := s.wallet.NtfnServer.AccountSpentnessNotifications(req.Account)
+	n := w.NtfnServer.AccountSpentnessNotifications(req.Account)
 	defer n.Done()
 
 	ctxDone := svr.Context().Done()
@@ -699,7 +1456,12 @@ func (s *walletServer) SpentnessNotifications(req *pb.SpentnessNotificationsRequ
 func (s *walletServer) AccountNotifications(req *pb.AccountNotificationsRequest,
 	svr pb.WalletService_AccountNotificationsServer) error {
 
-	n := s.wallet.NtfnServer.AccountNotifications()
+	w, err := s.requestWallet(svr.Context())
+	if err != nil {
+		return err
+	}
+
+	n := w.NtfnServer.AccountNotifications()
 	defer n.Done()
 
 	ctxDone := svr.Context().Done()
@@ -739,25 +1501,33 @@ func (s *loaderServer) CreateWallet(ctx context.Context, req *pb.CreateWalletReq
 	defer func() {
 		zero.Bytes(req.PrivatePassphrase)
 		zero.Bytes(req.Seed)
+		zero.Bytes(req.MnemonicPassphrase)
 	}()
 
+	if req.Mnemonic != "" && len(req.Seed) != 0 {
+		return nil, grpc.Errorf(codes.InvalidArgument,
+			"seed and mnemonic are mutually exclusive")
+	}
+
 	// Use an insecure public passphrase when the request's is empty.
 	pubPassphrase := req.PublicPassphrase
 	if len(pubPassphrase) == 0 {
 		pubPassphrase = []byte(wallet.InsecurePubPassphrase)
 	}
 
-	wallet, err := s.loader.CreateNewWallet(pubPassphrase, req.PrivatePassphrase, req.Seed)
+	var err error
+	if req.Mnemonic != "" {
+		_, err = s.loader.CreateNamedWalletFromMnemonic(req.WalletName,
+			pubPassphrase, req.PrivatePassphrase, req.Mnemonic,
+			req.MnemonicPassphrase)
+	} else {
+		_, err = s.loader.CreateNamedWallet(req.WalletName, pubPassphrase,
+			req.PrivatePassphrase, req.Seed)
+	}
 	if err != nil {
 		return nil, translateError(err)
 	}
 
-	s.mu.Lock()
-	if s.rpcClient != nil {
-		wallet.SynchronizeRPC(s.rpcClient)
-	}
-	s.mu.Unlock()
-
 	return &pb.CreateWalletResponse{}, nil
 }
 
@@ -770,24 +1540,19 @@ func (s *loaderServer) OpenWallet(ctx context.Context, req *pb.OpenWalletRequest
 		pubPassphrase = []byte(wallet.InsecurePubPassphrase)
 	}
 
-	wallet, err := s.loader.OpenExistingWallet(pubPassphrase, false)
+	_, err := s.loader.OpenNamedWallet(req.WalletName, pubPassphrase,
+		false)
 	if err != nil {
 		return nil, translateError(err)
 	}
 
-	s.mu.Lock()
-	if s.rpcClient != nil {
-		wallet.SynchronizeRPC(s.rpcClient)
-	}
-	s.mu.Unlock()
-
 	return &pb.OpenWalletResponse{}, nil
 }
 
 func (s *loaderServer) WalletExists(ctx context.Context, req *pb.WalletExistsRequest) (
 	*pb.WalletExistsResponse, error) {
 
-	exists, err := s.loader.WalletExists()
+	exists, err := s.loader.NamedWalletExists(req.WalletName)
 	if err != nil {
 		return nil, translateError(err)
 	}
@@ -797,7 +1562,7 @@ func (s *loaderServer) WalletExists(ctx context.Context, req *pb.WalletExistsReq
 func (s *loaderServer) CloseWallet(ctx context.Context, req *pb.CloseWalletRequest) (
 	*pb.CloseWalletResponse, error) {
 
-	err := s.loader.UnloadWallet()
+	err := s.loader.UnloadNamedWallet(req.WalletName)
 	if err == wallet.ErrNotLoaded {
 		return nil, grpc.Errorf(codes.FailedPrecondition, "wallet is not loaded")
 	}
@@ -827,11 +1592,12 @@ func (s *loaderServer) StartConsensusRpc(ctx context.Context, req *pb.StartConse
 			"Network address is ill-formed: %v", err)
 	}
 
-	// Error if the wallet is already syncing with the network.
-	wallet, walletLoaded := s.loader.LoadedWallet()
-	if walletLoaded && wallet.SynchronizingToNetwork() {
-		return nil, grpc.Errorf(codes.FailedPrecondition,
-			"wallet is loaded and already synchronizing")
+	// Error if a wallet is already syncing with the network.
+	for _, w := range s.loader.LoadedWallets() {
+		if w.SynchronizingToNetwork() {
+			return nil, grpc.Errorf(codes.FailedPrecondition,
+				"wallet is loaded and already synchronizing")
+		}
 	}
 
 	rpcClient, err := chain.NewRPCClient(s.activeNet.Params, networkAddress, req.Username,
@@ -852,9 +1618,33 @@ func (s *loaderServer) StartConsensusRpc(ctx context.Context, req *pb.StartConse
 
 	s.rpcClient = rpcClient
 
-	if walletLoaded {
-		wallet.SynchronizeRPC(rpcClient)
-	}
+	// Every loaded wallet, and every wallet loaded later, is synced with
+	// its own client of the shared RPC connection.
+	clientMux := chain.NewClientMux(rpcClient)
+	s.loader.RunAfterLoad(func(w *wallet.Wallet) {
+		// Syncing only fails once the connection has been closed,
+		// which leaves the wallet unsynced as for any disconnect.
+		_ = w.SynchronizeMux(clientMux)
+	})
 
 	return &pb.StartConsensusRpcResponse{}, nil
 }
+
+func (s *loaderServer) ListWallets(ctx context.Context, req *pb.ListWalletsRequest) (
+	*pb.ListWalletsResponse, error) {
+
+	names, err := s.loader.WalletNames()
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	wallets := make([]*pb.ListWalletsResponse_Wallet, len(names))
+	for i, name := range names {
+		_, loaded := s.loader.NamedWallet(name)
+		wallets[i] = &pb.ListWalletsResponse_Wallet{
+			Name:   name,
+			Loaded: loaded,
+		}
+	}
+	return &pb.ListWalletsResponse{Wallets: wallets}, nil
+}
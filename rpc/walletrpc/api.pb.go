@@ -29,10 +29,14 @@ It has these top-level messages:
 	NextAddressResponse
 	ImportPrivateKeyRequest
 	ImportPrivateKeyResponse
+	ImportAccountRequest
+	ImportAccountResponse
 	BalanceRequest
 	BalanceResponse
 	GetTransactionsRequest
 	GetTransactionsResponse
+	BackupWalletRequest
+	BackupWalletResponse
 	ChangePassphraseRequest
 	ChangePassphraseResponse
 	FundTransactionRequest
@@ -41,6 +45,26 @@ It has these top-level messages:
 	SignTransactionResponse
 	PublishTransactionRequest
 	PublishTransactionResponse
+	BumpFeeRequest
+	BumpFeeResponse
+	LockOutpointRequest
+	LockOutpointResponse
+	UnlockOutpointRequest
+	UnlockOutpointResponse
+	ListLockedOutpointsRequest
+	ListLockedOutpointsResponse
+	LabelTransactionRequest
+	LabelTransactionResponse
+	FundPsbtRequest
+	FundPsbtResponse
+	SignPsbtRequest
+	SignPsbtResponse
+	FinalizePsbtRequest
+	FinalizePsbtResponse
+	SignMessageRequest
+	SignMessageResponse
+	VerifyMessageRequest
+	VerifyMessageResponse
 	TransactionNotificationsRequest
 	TransactionNotificationsResponse
 	SpentnessNotificationsRequest
@@ -55,6 +79,8 @@ It has these top-level messages:
 	CloseWalletResponse
 	WalletExistsRequest
 	WalletExistsResponse
+	ListWalletsRequest
+	ListWalletsResponse
 	StartConsensusRpcRequest
 	StartConsensusRpcResponse
 */
@@ -80,6 +106,57 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+type AddressType int32
+
+const (
+	AddressType_PUBKEY_HASH                AddressType = 0
+	AddressType_WITNESS_PUBKEY_HASH        AddressType = 1
+	AddressType_NESTED_WITNESS_PUBKEY_HASH AddressType = 2
+)
+
+var AddressType_name = map[int32]string{
+	0: "PUBKEY_HASH",
+	1: "WITNESS_PUBKEY_HASH",
+	2: "NESTED_WITNESS_PUBKEY_HASH",
+}
+var AddressType_value = map[string]int32{
+	"PUBKEY_HASH":                0,
+	"WITNESS_PUBKEY_HASH":        1,
+	"NESTED_WITNESS_PUBKEY_HASH": 2,
+}
+
+func (x AddressType) String() string {
+	return proto.EnumName(AddressType_name, int32(x))
+}
+func (AddressType) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+type CoinSelection int32
+
+const (
+	CoinSelection_LARGEST_FIRST    CoinSelection = 0
+	CoinSelection_BRANCH_AND_BOUND CoinSelection = 1
+	CoinSelection_OLDEST_FIRST     CoinSelection = 2
+	CoinSelection_RANDOM_IMPROVE   CoinSelection = 3
+)
+
+var CoinSelection_name = map[int32]string{
+	0: "LARGEST_FIRST",
+	1: "BRANCH_AND_BOUND",
+	2: "OLDEST_FIRST",
+	3: "RANDOM_IMPROVE",
+}
+var CoinSelection_value = map[string]int32{
+	"LARGEST_FIRST":    0,
+	"BRANCH_AND_BOUND": 1,
+	"OLDEST_FIRST":     2,
+	"RANDOM_IMPROVE":   3,
+}
+
+func (x CoinSelection) String() string {
+	return proto.EnumName(CoinSelection_name, int32(x))
+}
+func (CoinSelection) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
 type NextAddressRequest_Kind int32
 
 const (
@@ -121,7 +198,7 @@ func (x ChangePassphraseRequest_Key) String() string {
 	return proto.EnumName(ChangePassphraseRequest_Key_name, int32(x))
 }
 func (ChangePassphraseRequest_Key) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{25, 0}
+	return fileDescriptor0, []int{29, 0}
 }
 
 type VersionRequest struct {
@@ -195,6 +272,7 @@ type TransactionDetails struct {
 	Credits     []*TransactionDetails_Output `protobuf:"bytes,4,rep,name=credits" json:"credits,omitempty"`
 	Fee         int64                        `protobuf:"varint,5,opt,name=fee" json:"fee,omitempty"`
 	Timestamp   int64                        `protobuf:"varint,6,opt,name=timestamp" json:"timestamp,omitempty"`
+	Label       string                       `protobuf:"bytes,7,opt,name=label" json:"label,omitempty"`
 }
 
 func (m *TransactionDetails) Reset()                    { *m = TransactionDetails{} }
@@ -244,6 +322,13 @@ func (m *TransactionDetails) GetTimestamp() int64 {
 	return 0
 }
 
+func (m *TransactionDetails) GetLabel() string {
+	if m != nil {
+		return m.Label
+	}
+	return ""
+}
+
 type TransactionDetails_Input struct {
 	Index           uint32 `protobuf:"varint,1,opt,name=index" json:"index,omitempty"`
 	PreviousAccount uint32 `protobuf:"varint,2,opt,name=previous_account,json=previousAccount" json:"previous_account,omitempty"`
@@ -280,6 +365,7 @@ type TransactionDetails_Output struct {
 	Index    uint32 `protobuf:"varint,1,opt,name=index" json:"index,omitempty"`
 	Account  uint32 `protobuf:"varint,2,opt,name=account" json:"account,omitempty"`
 	Internal bool   `protobuf:"varint,3,opt,name=internal" json:"internal,omitempty"`
+	Label    string `protobuf:"bytes,4,opt,name=label" json:"label,omitempty"`
 }
 
 func (m *TransactionDetails_Output) Reset()                    { *m = TransactionDetails_Output{} }
@@ -308,6 +394,13 @@ func (m *TransactionDetails_Output) GetInternal() bool {
 	return false
 }
 
+func (m *TransactionDetails_Output) GetLabel() string {
+	if m != nil {
+		return m.Label
+	}
+	return ""
+}
+
 type BlockDetails struct {
 	Hash         []byte                `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
 	Height       int32                 `protobuf:"varint,2,opt,name=height" json:"height,omitempty"`
@@ -485,12 +578,14 @@ func (m *AccountsResponse) GetCurrentBlockHeight() int32 {
 }
 
 type AccountsResponse_Account struct {
-	AccountNumber    uint32 `protobuf:"varint,1,opt,name=account_number,json=accountNumber" json:"account_number,omitempty"`
-	AccountName      string `protobuf:"bytes,2,opt,name=account_name,json=accountName" json:"account_name,omitempty"`
-	TotalBalance     int64  `protobuf:"varint,3,opt,name=total_balance,json=totalBalance" json:"total_balance,omitempty"`
-	ExternalKeyCount uint32 `protobuf:"varint,4,opt,name=external_key_count,json=externalKeyCount" json:"external_key_count,omitempty"`
-	InternalKeyCount uint32 `protobuf:"varint,5,opt,name=internal_key_count,json=internalKeyCount" json:"internal_key_count,omitempty"`
-	ImportedKeyCount uint32 `protobuf:"varint,6,opt,name=imported_key_count,json=importedKeyCount" json:"imported_key_count,omitempty"`
+	AccountNumber    uint32      `protobuf:"varint,1,opt,name=account_number,json=accountNumber" json:"account_number,omitempty"`
+	AccountName      string      `protobuf:"bytes,2,opt,name=account_name,json=accountName" json:"account_name,omitempty"`
+	TotalBalance     int64       `protobuf:"varint,3,opt,name=total_balance,json=totalBalance" json:"total_balance,omitempty"`
+	ExternalKeyCount uint32      `protobuf:"varint,4,opt,name=external_key_count,json=externalKeyCount" json:"external_key_count,omitempty"`
+	InternalKeyCount uint32      `protobuf:"varint,5,opt,name=internal_key_count,json=internalKeyCount" json:"internal_key_count,omitempty"`
+	ImportedKeyCount uint32      `protobuf:"varint,6,opt,name=imported_key_count,json=importedKeyCount" json:"imported_key_count,omitempty"`
+	AddressType      AddressType `protobuf:"varint,7,opt,name=address_type,json=addressType,enum=walletrpc.AddressType" json:"address_type,omitempty"`
+	WatchOnly        bool        `protobuf:"varint,8,opt,name=watch_only,json=watchOnly" json:"watch_only,omitempty"`
 }
 
 func (m *AccountsResponse_Account) Reset()                    { *m = AccountsResponse_Account{} }
@@ -540,6 +635,20 @@ func (m *AccountsResponse_Account) GetImportedKeyCount() uint32 {
 	return 0
 }
 
+func (m *AccountsResponse_Account) GetAddressType() AddressType {
+	if m != nil {
+		return m.AddressType
+	}
+	return AddressType_PUBKEY_HASH
+}
+
+func (m *AccountsResponse_Account) GetWatchOnly() bool {
+	if m != nil {
+		return m.WatchOnly
+	}
+	return false
+}
+
 type RenameAccountRequest struct {
 	AccountNumber uint32 `protobuf:"varint,1,opt,name=account_number,json=accountNumber" json:"account_number,omitempty"`
 	NewName       string `protobuf:"bytes,2,opt,name=new_name,json=newName" json:"new_name,omitempty"`
@@ -573,8 +682,9 @@ func (*RenameAccountResponse) ProtoMessage()               {}
 func (*RenameAccountResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
 
 type NextAccountRequest struct {
-	Passphrase  []byte `protobuf:"bytes,1,opt,name=passphrase,proto3" json:"passphrase,omitempty"`
-	AccountName string `protobuf:"bytes,2,opt,name=account_name,json=accountName" json:"account_name,omitempty"`
+	Passphrase  []byte      `protobuf:"bytes,1,opt,name=passphrase,proto3" json:"passphrase,omitempty"`
+	AccountName string      `protobuf:"bytes,2,opt,name=account_name,json=accountName" json:"account_name,omitempty"`
+	AddressType AddressType `protobuf:"varint,3,opt,name=address_type,json=addressType,enum=walletrpc.AddressType" json:"address_type,omitempty"`
 }
 
 func (m *NextAccountRequest) Reset()                    { *m = NextAccountRequest{} }
@@ -596,6 +706,13 @@ func (m *NextAccountRequest) GetAccountName() string {
 	return ""
 }
 
+func (m *NextAccountRequest) GetAddressType() AddressType {
+	if m != nil {
+		return m.AddressType
+	}
+	return AddressType_PUBKEY_HASH
+}
+
 type NextAccountResponse struct {
 	AccountNumber uint32 `protobuf:"varint,1,opt,name=account_number,json=accountNumber" json:"account_number,omitempty"`
 }
@@ -700,6 +817,62 @@ func (m *ImportPrivateKeyResponse) String() string            { return proto.Com
 func (*ImportPrivateKeyResponse) ProtoMessage()               {}
 func (*ImportPrivateKeyResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
 
+type ImportAccountRequest struct {
+	AccountName       string      `protobuf:"bytes,1,opt,name=account_name,json=accountName" json:"account_name,omitempty"`
+	ExtendedPublicKey string      `protobuf:"bytes,2,opt,name=extended_public_key,json=extendedPublicKey" json:"extended_public_key,omitempty"`
+	AddressType       AddressType `protobuf:"varint,3,opt,name=address_type,json=addressType,enum=walletrpc.AddressType" json:"address_type,omitempty"`
+	Rescan            bool        `protobuf:"varint,4,opt,name=rescan" json:"rescan,omitempty"`
+}
+
+func (m *ImportAccountRequest) Reset()                    { *m = ImportAccountRequest{} }
+func (m *ImportAccountRequest) String() string            { return proto.CompactTextString(m) }
+func (*ImportAccountRequest) ProtoMessage()               {}
+func (*ImportAccountRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+
+func (m *ImportAccountRequest) GetAccountName() string {
+	if m != nil {
+		return m.AccountName
+	}
+	return ""
+}
+
+func (m *ImportAccountRequest) GetExtendedPublicKey() string {
+	if m != nil {
+		return m.ExtendedPublicKey
+	}
+	return ""
+}
+
+func (m *ImportAccountRequest) GetAddressType() AddressType {
+	if m != nil {
+		return m.AddressType
+	}
+	return AddressType_PUBKEY_HASH
+}
+
+func (m *ImportAccountRequest) GetRescan() bool {
+	if m != nil {
+		return m.Rescan
+	}
+	return false
+}
+
+type ImportAccountResponse struct {
+	AccountNumber uint32 `protobuf:"varint,1,opt,name=account_number,json=accountNumber" json:"account_number,omitempty"`
+}
+
+func (m *ImportAccountResponse) Reset()                    { *m = ImportAccountResponse{} }
+func (m *ImportAccountResponse) String() string            { return proto.CompactTextString(m) }
+func (*ImportAccountResponse) ProtoMessage()               {}
+func (*ImportAccountResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+
+func (m *ImportAccountResponse) GetAccountNumber() uint32 {
+	if m != nil {
+		return m.AccountNumber
+	}
+	return 0
+}
+
 type BalanceRequest struct {
 	AccountNumber         uint32 `protobuf:"varint,1,opt,name=account_number,json=accountNumber" json:"account_number,omitempty"`
 	RequiredConfirmations int32  `protobuf:"varint,2,opt,name=required_confirmations,json=requiredConfirmations" json:"required_confirmations,omitempty"`
@@ -708,7 +881,7 @@ type BalanceRequest struct {
 func (m *BalanceRequest) Reset()                    { *m = BalanceRequest{} }
 func (m *BalanceRequest) String() string            { return proto.CompactTextString(m) }
 func (*BalanceRequest) ProtoMessage()               {}
-func (*BalanceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+func (*BalanceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
 
 func (m *BalanceRequest) GetAccountNumber() uint32 {
 	if m != nil {
@@ -733,7 +906,7 @@ type BalanceResponse struct {
 func (m *BalanceResponse) Reset()                    { *m = BalanceResponse{} }
 func (m *BalanceResponse) String() string            { return proto.CompactTextString(m) }
 func (*BalanceResponse) ProtoMessage()               {}
-func (*BalanceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+func (*BalanceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
 
 func (m *BalanceResponse) GetTotal() int64 {
 	if m != nil {
@@ -782,7 +955,7 @@ type GetTransactionsRequest struct {
 func (m *GetTransactionsRequest) Reset()                    { *m = GetTransactionsRequest{} }
 func (m *GetTransactionsRequest) String() string            { return proto.CompactTextString(m) }
 func (*GetTransactionsRequest) ProtoMessage()               {}
-func (*GetTransactionsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+func (*GetTransactionsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
 
 func (m *GetTransactionsRequest) GetStartingBlockHash() []byte {
 	if m != nil {
@@ -827,7 +1000,7 @@ type GetTransactionsResponse struct {
 func (m *GetTransactionsResponse) Reset()                    { *m = GetTransactionsResponse{} }
 func (m *GetTransactionsResponse) String() string            { return proto.CompactTextString(m) }
 func (*GetTransactionsResponse) ProtoMessage()               {}
-func (*GetTransactionsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+func (*GetTransactionsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
 
 func (m *GetTransactionsResponse) GetMinedTransactions() []*BlockDetails {
 	if m != nil {
@@ -843,6 +1016,38 @@ func (m *GetTransactionsResponse) GetUnminedTransactions() []*TransactionDetails
 	return nil
 }
 
+type BackupWalletRequest struct {
+	Passphrase []byte `protobuf:"bytes,1,opt,name=passphrase,proto3" json:"passphrase,omitempty"`
+}
+
+func (m *BackupWalletRequest) Reset()                    { *m = BackupWalletRequest{} }
+func (m *BackupWalletRequest) String() string            { return proto.CompactTextString(m) }
+func (*BackupWalletRequest) ProtoMessage()               {}
+func (*BackupWalletRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+
+func (m *BackupWalletRequest) GetPassphrase() []byte {
+	if m != nil {
+		return m.Passphrase
+	}
+	return nil
+}
+
+type BackupWalletResponse struct {
+	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *BackupWalletResponse) Reset()                    { *m = BackupWalletResponse{} }
+func (m *BackupWalletResponse) String() string            { return proto.CompactTextString(m) }
+func (*BackupWalletResponse) ProtoMessage()               {}
+func (*BackupWalletResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
+
+func (m *BackupWalletResponse) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
 type ChangePassphraseRequest struct {
 	Key           ChangePassphraseRequest_Key `protobuf:"varint,1,opt,name=key,enum=walletrpc.ChangePassphraseRequest_Key" json:"key,omitempty"`
 	OldPassphrase []byte                      `protobuf:"bytes,2,opt,name=old_passphrase,json=oldPassphrase,proto3" json:"old_passphrase,omitempty"`
@@ -852,7 +1057,7 @@ type ChangePassphraseRequest struct {
 func (m *ChangePassphraseRequest) Reset()                    { *m = ChangePassphraseRequest{} }
 func (m *ChangePassphraseRequest) String() string            { return proto.CompactTextString(m) }
 func (*ChangePassphraseRequest) ProtoMessage()               {}
-func (*ChangePassphraseRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+func (*ChangePassphraseRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
 
 func (m *ChangePassphraseRequest) GetKey() ChangePassphraseRequest_Key {
 	if m != nil {
@@ -881,7 +1086,7 @@ type ChangePassphraseResponse struct {
 func (m *ChangePassphraseResponse) Reset()                    { *m = ChangePassphraseResponse{} }
 func (m *ChangePassphraseResponse) String() string            { return proto.CompactTextString(m) }
 func (*ChangePassphraseResponse) ProtoMessage()               {}
-func (*ChangePassphraseResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
+func (*ChangePassphraseResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
 
 type FundTransactionRequest struct {
 	Account                  uint32 `protobuf:"varint,1,opt,name=account" json:"account,omitempty"`
@@ -889,12 +1094,18 @@ type FundTransactionRequest struct {
 	RequiredConfirmations    int32  `protobuf:"varint,3,opt,name=required_confirmations,json=requiredConfirmations" json:"required_confirmations,omitempty"`
 	IncludeImmatureCoinbases bool   `protobuf:"varint,4,opt,name=include_immature_coinbases,json=includeImmatureCoinbases" json:"include_immature_coinbases,omitempty"`
 	IncludeChangeScript      bool   `protobuf:"varint,5,opt,name=include_change_script,json=includeChangeScript" json:"include_change_script,omitempty"`
+	// The fee rate, in satoshis per kilobyte, used to estimate the fee
+	// required to spend the selected outputs.  If zero, the wallet's
+	// estimated fee rate is used.
+	FeeRate int64 `protobuf:"varint,6,opt,name=fee_rate,json=feeRate" json:"fee_rate,omitempty"`
+	// The strategy used to select outputs paying the target amount.
+	CoinSelection CoinSelection `protobuf:"varint,7,opt,name=coin_selection,json=coinSelection,enum=walletrpc.CoinSelection" json:"coin_selection,omitempty"`
 }
 
 func (m *FundTransactionRequest) Reset()                    { *m = FundTransactionRequest{} }
 func (m *FundTransactionRequest) String() string            { return proto.CompactTextString(m) }
 func (*FundTransactionRequest) ProtoMessage()               {}
-func (*FundTransactionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+func (*FundTransactionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
 
 func (m *FundTransactionRequest) GetAccount() uint32 {
 	if m != nil {
@@ -931,16 +1142,32 @@ func (m *FundTransactionRequest) GetIncludeChangeScript() bool {
 	return false
 }
 
+func (m *FundTransactionRequest) GetFeeRate() int64 {
+	if m != nil {
+		return m.FeeRate
+	}
+	return 0
+}
+
+func (m *FundTransactionRequest) GetCoinSelection() CoinSelection {
+	if m != nil {
+		return m.CoinSelection
+	}
+	return CoinSelection_LARGEST_FIRST
+}
+
 type FundTransactionResponse struct {
 	SelectedOutputs []*FundTransactionResponse_PreviousOutput `protobuf:"bytes,1,rep,name=selected_outputs,json=selectedOutputs" json:"selected_outputs,omitempty"`
 	TotalAmount     int64                                     `protobuf:"varint,2,opt,name=total_amount,json=totalAmount" json:"total_amount,omitempty"`
 	ChangePkScript  []byte                                    `protobuf:"bytes,3,opt,name=change_pk_script,json=changePkScript,proto3" json:"change_pk_script,omitempty"`
+	FeeRate         int64                                     `protobuf:"varint,4,opt,name=fee_rate,json=feeRate" json:"fee_rate,omitempty"`
+	EstimatedFee    int64                                     `protobuf:"varint,5,opt,name=estimated_fee,json=estimatedFee" json:"estimated_fee,omitempty"`
 }
 
 func (m *FundTransactionResponse) Reset()                    { *m = FundTransactionResponse{} }
 func (m *FundTransactionResponse) String() string            { return proto.CompactTextString(m) }
 func (*FundTransactionResponse) ProtoMessage()               {}
-func (*FundTransactionResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
+func (*FundTransactionResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
 
 func (m *FundTransactionResponse) GetSelectedOutputs() []*FundTransactionResponse_PreviousOutput {
 	if m != nil {
@@ -963,6 +1190,20 @@ func (m *FundTransactionResponse) GetChangePkScript() []byte {
 	return nil
 }
 
+func (m *FundTransactionResponse) GetFeeRate() int64 {
+	if m != nil {
+		return m.FeeRate
+	}
+	return 0
+}
+
+func (m *FundTransactionResponse) GetEstimatedFee() int64 {
+	if m != nil {
+		return m.EstimatedFee
+	}
+	return 0
+}
+
 type FundTransactionResponse_PreviousOutput struct {
 	TransactionHash []byte `protobuf:"bytes,1,opt,name=transaction_hash,json=transactionHash,proto3" json:"transaction_hash,omitempty"`
 	OutputIndex     uint32 `protobuf:"varint,2,opt,name=output_index,json=outputIndex" json:"output_index,omitempty"`
@@ -978,7 +1219,7 @@ func (m *FundTransactionResponse_PreviousOutput) Reset() {
 func (m *FundTransactionResponse_PreviousOutput) String() string { return proto.CompactTextString(m) }
 func (*FundTransactionResponse_PreviousOutput) ProtoMessage()    {}
 func (*FundTransactionResponse_PreviousOutput) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{28, 0}
+	return fileDescriptor0, []int{32, 0}
 }
 
 func (m *FundTransactionResponse_PreviousOutput) GetTransactionHash() []byte {
@@ -1037,7 +1278,7 @@ type SignTransactionRequest struct {
 func (m *SignTransactionRequest) Reset()                    { *m = SignTransactionRequest{} }
 func (m *SignTransactionRequest) String() string            { return proto.CompactTextString(m) }
 func (*SignTransactionRequest) ProtoMessage()               {}
-func (*SignTransactionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+func (*SignTransactionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
 
 func (m *SignTransactionRequest) GetPassphrase() []byte {
 	if m != nil {
@@ -1068,7 +1309,7 @@ type SignTransactionResponse struct {
 func (m *SignTransactionResponse) Reset()                    { *m = SignTransactionResponse{} }
 func (m *SignTransactionResponse) String() string            { return proto.CompactTextString(m) }
 func (*SignTransactionResponse) ProtoMessage()               {}
-func (*SignTransactionResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
+func (*SignTransactionResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{34} }
 
 func (m *SignTransactionResponse) GetTransaction() []byte {
 	if m != nil {
@@ -1091,7 +1332,7 @@ type PublishTransactionRequest struct {
 func (m *PublishTransactionRequest) Reset()                    { *m = PublishTransactionRequest{} }
 func (m *PublishTransactionRequest) String() string            { return proto.CompactTextString(m) }
 func (*PublishTransactionRequest) ProtoMessage()               {}
-func (*PublishTransactionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
+func (*PublishTransactionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{35} }
 
 func (m *PublishTransactionRequest) GetSignedTransaction() []byte {
 	if m != nil {
@@ -1106,200 +1347,787 @@ type PublishTransactionResponse struct {
 func (m *PublishTransactionResponse) Reset()                    { *m = PublishTransactionResponse{} }
 func (m *PublishTransactionResponse) String() string            { return proto.CompactTextString(m) }
 func (*PublishTransactionResponse) ProtoMessage()               {}
-func (*PublishTransactionResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
-
-type TransactionNotificationsRequest struct {
-}
-
-func (m *TransactionNotificationsRequest) Reset()         { *m = TransactionNotificationsRequest{} }
-func (m *TransactionNotificationsRequest) String() string { return proto.CompactTextString(m) }
-func (*TransactionNotificationsRequest) ProtoMessage()    {}
-func (*TransactionNotificationsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{33}
-}
+func (*PublishTransactionResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{36} }
 
-type TransactionNotificationsResponse struct {
-	// Sorted by increasing height.  This is a repeated field so many new blocks
-	// in a new best chain can be notified at once during a reorganize.
-	AttachedBlocks []*BlockDetails `protobuf:"bytes,1,rep,name=attached_blocks,json=attachedBlocks" json:"attached_blocks,omitempty"`
-	// If there was a chain reorganize, there may have been blocks with wallet
-	// transactions that are no longer in the best chain.  These are those
-	// block's hashes.
-	DetachedBlocks [][]byte `protobuf:"bytes,2,rep,name=detached_blocks,json=detachedBlocks,proto3" json:"detached_blocks,omitempty"`
-	// Any new unmined transactions are included here.  These unmined transactions
-	// refer to the current best chain, so transactions from detached blocks may
-	// be moved to mempool and included here if they are not mined or double spent
-	// in the new chain.  Additonally, if no new blocks were attached but a relevant
-	// unmined transaction is seen by the wallet, it will be reported here.
-	UnminedTransactions []*TransactionDetails `protobuf:"bytes,3,rep,name=unmined_transactions,json=unminedTransactions" json:"unmined_transactions,omitempty"`
-	// Instead of notifying all of the removed unmined transactions,
-	// just send all of the current hashes.
-	UnminedTransactionHashes [][]byte `protobuf:"bytes,4,rep,name=unmined_transaction_hashes,json=unminedTransactionHashes,proto3" json:"unmined_transaction_hashes,omitempty"`
+type BumpFeeRequest struct {
+	Passphrase      []byte `protobuf:"bytes,1,opt,name=passphrase,proto3" json:"passphrase,omitempty"`
+	TransactionHash []byte `protobuf:"bytes,2,opt,name=transaction_hash,json=transactionHash,proto3" json:"transaction_hash,omitempty"`
+	// The fee rate of the replacement transaction, in satoshis per
+	// kilobyte.  If zero, the wallet's estimated fee rate is used.
+	FeeRate int64 `protobuf:"varint,3,opt,name=fee_rate,json=feeRate" json:"fee_rate,omitempty"`
 }
 
-func (m *TransactionNotificationsResponse) Reset()         { *m = TransactionNotificationsResponse{} }
-func (m *TransactionNotificationsResponse) String() string { return proto.CompactTextString(m) }
-func (*TransactionNotificationsResponse) ProtoMessage()    {}
-func (*TransactionNotificationsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{34}
-}
+func (m *BumpFeeRequest) Reset()                    { *m = BumpFeeRequest{} }
+func (m *BumpFeeRequest) String() string            { return proto.CompactTextString(m) }
+func (*BumpFeeRequest) ProtoMessage()               {}
+func (*BumpFeeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{37} }
 
-func (m *TransactionNotificationsResponse) GetAttachedBlocks() []*BlockDetails {
+func (m *BumpFeeRequest) GetPassphrase() []byte {
 	if m != nil {
-		return m.AttachedBlocks
+		return m.Passphrase
 	}
 	return nil
 }
 
-func (m *TransactionNotificationsResponse) GetDetachedBlocks() [][]byte {
+func (m *BumpFeeRequest) GetTransactionHash() []byte {
 	if m != nil {
-		return m.DetachedBlocks
+		return m.TransactionHash
 	}
 	return nil
 }
 
-func (m *TransactionNotificationsResponse) GetUnminedTransactions() []*TransactionDetails {
+func (m *BumpFeeRequest) GetFeeRate() int64 {
 	if m != nil {
-		return m.UnminedTransactions
+		return m.FeeRate
 	}
-	return nil
+	return 0
 }
 
-func (m *TransactionNotificationsResponse) GetUnminedTransactionHashes() [][]byte {
+type BumpFeeResponse struct {
+	TransactionHash []byte `protobuf:"bytes,1,opt,name=transaction_hash,json=transactionHash,proto3" json:"transaction_hash,omitempty"`
+	Transaction     []byte `protobuf:"bytes,2,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	OriginalFee     int64  `protobuf:"varint,3,opt,name=original_fee,json=originalFee" json:"original_fee,omitempty"`
+	Fee             int64  `protobuf:"varint,4,opt,name=fee" json:"fee,omitempty"`
+}
+
+func (m *BumpFeeResponse) Reset()                    { *m = BumpFeeResponse{} }
+func (m *BumpFeeResponse) String() string            { return proto.CompactTextString(m) }
+func (*BumpFeeResponse) ProtoMessage()               {}
+func (*BumpFeeResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{38} }
+
+func (m *BumpFeeResponse) GetTransactionHash() []byte {
 	if m != nil {
-		return m.UnminedTransactionHashes
+		return m.TransactionHash
 	}
 	return nil
 }
 
-type SpentnessNotificationsRequest struct {
-	Account         uint32 `protobuf:"varint,1,opt,name=account" json:"account,omitempty"`
-	NoNotifyUnspent bool   `protobuf:"varint,2,opt,name=no_notify_unspent,json=noNotifyUnspent" json:"no_notify_unspent,omitempty"`
-	NoNotifySpent   bool   `protobuf:"varint,3,opt,name=no_notify_spent,json=noNotifySpent" json:"no_notify_spent,omitempty"`
-}
-
-func (m *SpentnessNotificationsRequest) Reset()                    { *m = SpentnessNotificationsRequest{} }
-func (m *SpentnessNotificationsRequest) String() string            { return proto.CompactTextString(m) }
-func (*SpentnessNotificationsRequest) ProtoMessage()               {}
-func (*SpentnessNotificationsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{35} }
-
-func (m *SpentnessNotificationsRequest) GetAccount() uint32 {
+func (m *BumpFeeResponse) GetTransaction() []byte {
 	if m != nil {
-		return m.Account
+		return m.Transaction
 	}
-	return 0
+	return nil
 }
 
-func (m *SpentnessNotificationsRequest) GetNoNotifyUnspent() bool {
+func (m *BumpFeeResponse) GetOriginalFee() int64 {
 	if m != nil {
-		return m.NoNotifyUnspent
+		return m.OriginalFee
 	}
-	return false
+	return 0
 }
 
-func (m *SpentnessNotificationsRequest) GetNoNotifySpent() bool {
+func (m *BumpFeeResponse) GetFee() int64 {
 	if m != nil {
-		return m.NoNotifySpent
+		return m.Fee
 	}
-	return false
+	return 0
 }
 
-type SpentnessNotificationsResponse struct {
-	TransactionHash []byte                                  `protobuf:"bytes,1,opt,name=transaction_hash,json=transactionHash,proto3" json:"transaction_hash,omitempty"`
-	OutputIndex     uint32                                  `protobuf:"varint,2,opt,name=output_index,json=outputIndex" json:"output_index,omitempty"`
-	Spender         *SpentnessNotificationsResponse_Spender `protobuf:"bytes,3,opt,name=spender" json:"spender,omitempty"`
+type LockOutpointRequest struct {
+	TransactionHash []byte `protobuf:"bytes,1,opt,name=transaction_hash,json=transactionHash,proto3" json:"transaction_hash,omitempty"`
+	OutputIndex     uint32 `protobuf:"varint,2,opt,name=output_index,json=outputIndex" json:"output_index,omitempty"`
+	LockId          string `protobuf:"bytes,3,opt,name=lock_id,json=lockId" json:"lock_id,omitempty"`
+	// The number of seconds after which the lock expires.  If zero, the
+	// lock does not expire.
+	Duration int64 `protobuf:"varint,4,opt,name=duration" json:"duration,omitempty"`
 }
 
-func (m *SpentnessNotificationsResponse) Reset()                    { *m = SpentnessNotificationsResponse{} }
-func (m *SpentnessNotificationsResponse) String() string            { return proto.CompactTextString(m) }
-func (*SpentnessNotificationsResponse) ProtoMessage()               {}
-func (*SpentnessNotificationsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{36} }
+func (m *LockOutpointRequest) Reset()                    { *m = LockOutpointRequest{} }
+func (m *LockOutpointRequest) String() string            { return proto.CompactTextString(m) }
+func (*LockOutpointRequest) ProtoMessage()               {}
+func (*LockOutpointRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{39} }
 
-func (m *SpentnessNotificationsResponse) GetTransactionHash() []byte {
+func (m *LockOutpointRequest) GetTransactionHash() []byte {
 	if m != nil {
 		return m.TransactionHash
 	}
 	return nil
 }
 
-func (m *SpentnessNotificationsResponse) GetOutputIndex() uint32 {
+func (m *LockOutpointRequest) GetOutputIndex() uint32 {
 	if m != nil {
 		return m.OutputIndex
 	}
 	return 0
 }
 
-func (m *SpentnessNotificationsResponse) GetSpender() *SpentnessNotificationsResponse_Spender {
+func (m *LockOutpointRequest) GetLockId() string {
 	if m != nil {
-		return m.Spender
+		return m.LockId
 	}
-	return nil
+	return ""
 }
 
-type SpentnessNotificationsResponse_Spender struct {
-	TransactionHash []byte `protobuf:"bytes,1,opt,name=transaction_hash,json=transactionHash,proto3" json:"transaction_hash,omitempty"`
-	InputIndex      uint32 `protobuf:"varint,2,opt,name=input_index,json=inputIndex" json:"input_index,omitempty"`
+func (m *LockOutpointRequest) GetDuration() int64 {
+	if m != nil {
+		return m.Duration
+	}
+	return 0
 }
 
-func (m *SpentnessNotificationsResponse_Spender) Reset() {
-	*m = SpentnessNotificationsResponse_Spender{}
+type LockOutpointResponse struct {
+	ExpirationTime int64 `protobuf:"varint,1,opt,name=expiration_time,json=expirationTime" json:"expiration_time,omitempty"`
 }
-func (m *SpentnessNotificationsResponse_Spender) String() string { return proto.CompactTextString(m) }
-func (*SpentnessNotificationsResponse_Spender) ProtoMessage()    {}
-func (*SpentnessNotificationsResponse_Spender) Descriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{36, 0}
+
+func (m *LockOutpointResponse) Reset()                    { *m = LockOutpointResponse{} }
+func (m *LockOutpointResponse) String() string            { return proto.CompactTextString(m) }
+func (*LockOutpointResponse) ProtoMessage()               {}
+func (*LockOutpointResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{40} }
+
+func (m *LockOutpointResponse) GetExpirationTime() int64 {
+	if m != nil {
+		return m.ExpirationTime
+	}
+	return 0
 }
 
-func (m *SpentnessNotificationsResponse_Spender) GetTransactionHash() []byte {
+type UnlockOutpointRequest struct {
+	TransactionHash []byte `protobuf:"bytes,1,opt,name=transaction_hash,json=transactionHash,proto3" json:"transaction_hash,omitempty"`
+	OutputIndex     uint32 `protobuf:"varint,2,opt,name=output_index,json=outputIndex" json:"output_index,omitempty"`
+}
+
+func (m *UnlockOutpointRequest) Reset()                    { *m = UnlockOutpointRequest{} }
+func (m *UnlockOutpointRequest) String() string            { return proto.CompactTextString(m) }
+func (*UnlockOutpointRequest) ProtoMessage()               {}
+func (*UnlockOutpointRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{41} }
+
+func (m *UnlockOutpointRequest) GetTransactionHash() []byte {
 	if m != nil {
 		return m.TransactionHash
 	}
 	return nil
 }
 
-func (m *SpentnessNotificationsResponse_Spender) GetInputIndex() uint32 {
+func (m *UnlockOutpointRequest) GetOutputIndex() uint32 {
 	if m != nil {
-		return m.InputIndex
+		return m.OutputIndex
 	}
 	return 0
 }
 
-type AccountNotificationsRequest struct {
+type UnlockOutpointResponse struct {
 }
 
-func (m *AccountNotificationsRequest) Reset()                    { *m = AccountNotificationsRequest{} }
-func (m *AccountNotificationsRequest) String() string            { return proto.CompactTextString(m) }
-func (*AccountNotificationsRequest) ProtoMessage()               {}
-func (*AccountNotificationsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{37} }
+func (m *UnlockOutpointResponse) Reset()                    { *m = UnlockOutpointResponse{} }
+func (m *UnlockOutpointResponse) String() string            { return proto.CompactTextString(m) }
+func (*UnlockOutpointResponse) ProtoMessage()               {}
+func (*UnlockOutpointResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{42} }
 
-type AccountNotificationsResponse struct {
-	AccountNumber    uint32 `protobuf:"varint,1,opt,name=account_number,json=accountNumber" json:"account_number,omitempty"`
-	AccountName      string `protobuf:"bytes,2,opt,name=account_name,json=accountName" json:"account_name,omitempty"`
-	ExternalKeyCount uint32 `protobuf:"varint,3,opt,name=external_key_count,json=externalKeyCount" json:"external_key_count,omitempty"`
-	InternalKeyCount uint32 `protobuf:"varint,4,opt,name=internal_key_count,json=internalKeyCount" json:"internal_key_count,omitempty"`
-	ImportedKeyCount uint32 `protobuf:"varint,5,opt,name=imported_key_count,json=importedKeyCount" json:"imported_key_count,omitempty"`
+type ListLockedOutpointsRequest struct {
 }
 
-func (m *AccountNotificationsResponse) Reset()                    { *m = AccountNotificationsResponse{} }
-func (m *AccountNotificationsResponse) String() string            { return proto.CompactTextString(m) }
-func (*AccountNotificationsResponse) ProtoMessage()               {}
-func (*AccountNotificationsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{38} }
+func (m *ListLockedOutpointsRequest) Reset()                    { *m = ListLockedOutpointsRequest{} }
+func (m *ListLockedOutpointsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListLockedOutpointsRequest) ProtoMessage()               {}
+func (*ListLockedOutpointsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{43} }
 
-func (m *AccountNotificationsResponse) GetAccountNumber() uint32 {
+type ListLockedOutpointsResponse struct {
+	LockedOutpoints []*ListLockedOutpointsResponse_LockedOutpoint `protobuf:"bytes,1,rep,name=locked_outpoints,json=lockedOutpoints" json:"locked_outpoints,omitempty"`
+}
+
+func (m *ListLockedOutpointsResponse) Reset()                    { *m = ListLockedOutpointsResponse{} }
+func (m *ListLockedOutpointsResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListLockedOutpointsResponse) ProtoMessage()               {}
+func (*ListLockedOutpointsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{44} }
+
+func (m *ListLockedOutpointsResponse) GetLockedOutpoints() []*ListLockedOutpointsResponse_LockedOutpoint {
 	if m != nil {
-		return m.AccountNumber
+		return m.LockedOutpoints
 	}
-	return 0
+	return nil
 }
 
-func (m *AccountNotificationsResponse) GetAccountName() string {
+type ListLockedOutpointsResponse_LockedOutpoint struct {
+	TransactionHash []byte `protobuf:"bytes,1,opt,name=transaction_hash,json=transactionHash,proto3" json:"transaction_hash,omitempty"`
+	OutputIndex     uint32 `protobuf:"varint,2,opt,name=output_index,json=outputIndex" json:"output_index,omitempty"`
+	LockId          string `protobuf:"bytes,3,opt,name=lock_id,json=lockId" json:"lock_id,omitempty"`
+	ExpirationTime  int64  `protobuf:"varint,4,opt,name=expiration_time,json=expirationTime" json:"expiration_time,omitempty"`
+}
+
+func (m *ListLockedOutpointsResponse_LockedOutpoint) Reset() {
+	*m = ListLockedOutpointsResponse_LockedOutpoint{}
+}
+func (m *ListLockedOutpointsResponse_LockedOutpoint) String() string {
+	return proto.CompactTextString(m)
+}
+func (*ListLockedOutpointsResponse_LockedOutpoint) ProtoMessage() {}
+func (*ListLockedOutpointsResponse_LockedOutpoint) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{44, 0}
+}
+
+func (m *ListLockedOutpointsResponse_LockedOutpoint) GetTransactionHash() []byte {
 	if m != nil {
-		return m.AccountName
+		return m.TransactionHash
 	}
-	return ""
+	return nil
 }
 
-func (m *AccountNotificationsResponse) GetExternalKeyCount() uint32 {
+func (m *ListLockedOutpointsResponse_LockedOutpoint) GetOutputIndex() uint32 {
+	if m != nil {
+		return m.OutputIndex
+	}
+	return 0
+}
+
+func (m *ListLockedOutpointsResponse_LockedOutpoint) GetLockId() string {
+	if m != nil {
+		return m.LockId
+	}
+	return ""
+}
+
+func (m *ListLockedOutpointsResponse_LockedOutpoint) GetExpirationTime() int64 {
+	if m != nil {
+		return m.ExpirationTime
+	}
+	return 0
+}
+
+type LabelTransactionRequest struct {
+	TransactionHash []byte `protobuf:"bytes,1,opt,name=transaction_hash,json=transactionHash,proto3" json:"transaction_hash,omitempty"`
+	Label           string `protobuf:"bytes,2,opt,name=label" json:"label,omitempty"`
+}
+
+func (m *LabelTransactionRequest) Reset()                    { *m = LabelTransactionRequest{} }
+func (m *LabelTransactionRequest) String() string            { return proto.CompactTextString(m) }
+func (*LabelTransactionRequest) ProtoMessage()               {}
+func (*LabelTransactionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{45} }
+
+func (m *LabelTransactionRequest) GetTransactionHash() []byte {
+	if m != nil {
+		return m.TransactionHash
+	}
+	return nil
+}
+
+func (m *LabelTransactionRequest) GetLabel() string {
+	if m != nil {
+		return m.Label
+	}
+	return ""
+}
+
+type LabelTransactionResponse struct {
+}
+
+func (m *LabelTransactionResponse) Reset()                    { *m = LabelTransactionResponse{} }
+func (m *LabelTransactionResponse) String() string            { return proto.CompactTextString(m) }
+func (*LabelTransactionResponse) ProtoMessage()               {}
+func (*LabelTransactionResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{46} }
+
+type FundPsbtRequest struct {
+	// A serialized PSBT (BIP0174) with the outputs to pay and any inputs
+	// which must be spent.
+	Psbt                  []byte `protobuf:"bytes,1,opt,name=psbt,proto3" json:"psbt,omitempty"`
+	Account               uint32 `protobuf:"varint,2,opt,name=account" json:"account,omitempty"`
+	RequiredConfirmations int32  `protobuf:"varint,3,opt,name=required_confirmations,json=requiredConfirmations" json:"required_confirmations,omitempty"`
+	// The fee rate of the transaction, in satoshis per kilobyte.  If zero,
+	// the wallet's estimated fee rate is used.
+	FeeRate       int64         `protobuf:"varint,4,opt,name=fee_rate,json=feeRate" json:"fee_rate,omitempty"`
+	CoinSelection CoinSelection `protobuf:"varint,5,opt,name=coin_selection,json=coinSelection,enum=walletrpc.CoinSelection" json:"coin_selection,omitempty"`
+}
+
+func (m *FundPsbtRequest) Reset()                    { *m = FundPsbtRequest{} }
+func (m *FundPsbtRequest) String() string            { return proto.CompactTextString(m) }
+func (*FundPsbtRequest) ProtoMessage()               {}
+func (*FundPsbtRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{47} }
+
+func (m *FundPsbtRequest) GetPsbt() []byte {
+	if m != nil {
+		return m.Psbt
+	}
+	return nil
+}
+
+func (m *FundPsbtRequest) GetAccount() uint32 {
+	if m != nil {
+		return m.Account
+	}
+	return 0
+}
+
+func (m *FundPsbtRequest) GetRequiredConfirmations() int32 {
+	if m != nil {
+		return m.RequiredConfirmations
+	}
+	return 0
+}
+
+func (m *FundPsbtRequest) GetFeeRate() int64 {
+	if m != nil {
+		return m.FeeRate
+	}
+	return 0
+}
+
+func (m *FundPsbtRequest) GetCoinSelection() CoinSelection {
+	if m != nil {
+		return m.CoinSelection
+	}
+	return CoinSelection_LARGEST_FIRST
+}
+
+type FundPsbtResponse struct {
+	Psbt []byte `protobuf:"bytes,1,opt,name=psbt,proto3" json:"psbt,omitempty"`
+	// The index of the change output, or -1 if there is no change.
+	ChangeIndex int32 `protobuf:"varint,2,opt,name=change_index,json=changeIndex" json:"change_index,omitempty"`
+	Fee         int64 `protobuf:"varint,3,opt,name=fee" json:"fee,omitempty"`
+}
+
+func (m *FundPsbtResponse) Reset()                    { *m = FundPsbtResponse{} }
+func (m *FundPsbtResponse) String() string            { return proto.CompactTextString(m) }
+func (*FundPsbtResponse) ProtoMessage()               {}
+func (*FundPsbtResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{48} }
+
+func (m *FundPsbtResponse) GetPsbt() []byte {
+	if m != nil {
+		return m.Psbt
+	}
+	return nil
+}
+
+func (m *FundPsbtResponse) GetChangeIndex() int32 {
+	if m != nil {
+		return m.ChangeIndex
+	}
+	return 0
+}
+
+func (m *FundPsbtResponse) GetFee() int64 {
+	if m != nil {
+		return m.Fee
+	}
+	return 0
+}
+
+type SignPsbtRequest struct {
+	Passphrase []byte `protobuf:"bytes,1,opt,name=passphrase,proto3" json:"passphrase,omitempty"`
+	Psbt       []byte `protobuf:"bytes,2,opt,name=psbt,proto3" json:"psbt,omitempty"`
+}
+
+func (m *SignPsbtRequest) Reset()                    { *m = SignPsbtRequest{} }
+func (m *SignPsbtRequest) String() string            { return proto.CompactTextString(m) }
+func (*SignPsbtRequest) ProtoMessage()               {}
+func (*SignPsbtRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{49} }
+
+func (m *SignPsbtRequest) GetPassphrase() []byte {
+	if m != nil {
+		return m.Passphrase
+	}
+	return nil
+}
+
+func (m *SignPsbtRequest) GetPsbt() []byte {
+	if m != nil {
+		return m.Psbt
+	}
+	return nil
+}
+
+type SignPsbtResponse struct {
+	Psbt         []byte   `protobuf:"bytes,1,opt,name=psbt,proto3" json:"psbt,omitempty"`
+	SignedInputs []uint32 `protobuf:"varint,2,rep,packed,name=signed_inputs,json=signedInputs" json:"signed_inputs,omitempty"`
+}
+
+func (m *SignPsbtResponse) Reset()                    { *m = SignPsbtResponse{} }
+func (m *SignPsbtResponse) String() string            { return proto.CompactTextString(m) }
+func (*SignPsbtResponse) ProtoMessage()               {}
+func (*SignPsbtResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{50} }
+
+func (m *SignPsbtResponse) GetPsbt() []byte {
+	if m != nil {
+		return m.Psbt
+	}
+	return nil
+}
+
+func (m *SignPsbtResponse) GetSignedInputs() []uint32 {
+	if m != nil {
+		return m.SignedInputs
+	}
+	return nil
+}
+
+type FinalizePsbtRequest struct {
+	Psbt []byte `protobuf:"bytes,1,opt,name=psbt,proto3" json:"psbt,omitempty"`
+}
+
+func (m *FinalizePsbtRequest) Reset()                    { *m = FinalizePsbtRequest{} }
+func (m *FinalizePsbtRequest) String() string            { return proto.CompactTextString(m) }
+func (*FinalizePsbtRequest) ProtoMessage()               {}
+func (*FinalizePsbtRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{51} }
+
+func (m *FinalizePsbtRequest) GetPsbt() []byte {
+	if m != nil {
+		return m.Psbt
+	}
+	return nil
+}
+
+type FinalizePsbtResponse struct {
+	Psbt     []byte `protobuf:"bytes,1,opt,name=psbt,proto3" json:"psbt,omitempty"`
+	Complete bool   `protobuf:"varint,2,opt,name=complete" json:"complete,omitempty"`
+	// The signed transaction, set only when the PSBT is complete.
+	Transaction []byte `protobuf:"bytes,3,opt,name=transaction,proto3" json:"transaction,omitempty"`
+}
+
+func (m *FinalizePsbtResponse) Reset()                    { *m = FinalizePsbtResponse{} }
+func (m *FinalizePsbtResponse) String() string            { return proto.CompactTextString(m) }
+func (*FinalizePsbtResponse) ProtoMessage()               {}
+func (*FinalizePsbtResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{52} }
+
+func (m *FinalizePsbtResponse) GetPsbt() []byte {
+	if m != nil {
+		return m.Psbt
+	}
+	return nil
+}
+
+func (m *FinalizePsbtResponse) GetComplete() bool {
+	if m != nil {
+		return m.Complete
+	}
+	return false
+}
+
+func (m *FinalizePsbtResponse) GetTransaction() []byte {
+	if m != nil {
+		return m.Transaction
+	}
+	return nil
+}
+
+type SignMessageRequest struct {
+	Passphrase []byte `protobuf:"bytes,1,opt,name=passphrase,proto3" json:"passphrase,omitempty"`
+	Address    string `protobuf:"bytes,2,opt,name=address" json:"address,omitempty"`
+	Message    string `protobuf:"bytes,3,opt,name=message" json:"message,omitempty"`
+	// Messages of P2PKH addresses are signed in the legacy format unless
+	// bip322 is set.  Messages of all other addresses are always signed with
+	// BIP0322.
+	Bip322 bool `protobuf:"varint,4,opt,name=bip322" json:"bip322,omitempty"`
+	// The unfinished BIP0322 signature of the message returned by another
+	// signer of a multisig address, to which the wallet's signatures are
+	// added.
+	Psbt []byte `protobuf:"bytes,5,opt,name=psbt,proto3" json:"psbt,omitempty"`
+}
+
+func (m *SignMessageRequest) Reset()                    { *m = SignMessageRequest{} }
+func (m *SignMessageRequest) String() string            { return proto.CompactTextString(m) }
+func (*SignMessageRequest) ProtoMessage()               {}
+func (*SignMessageRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{53} }
+
+func (m *SignMessageRequest) GetPassphrase() []byte {
+	if m != nil {
+		return m.Passphrase
+	}
+	return nil
+}
+
+func (m *SignMessageRequest) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *SignMessageRequest) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *SignMessageRequest) GetBip322() bool {
+	if m != nil {
+		return m.Bip322
+	}
+	return false
+}
+
+func (m *SignMessageRequest) GetPsbt() []byte {
+	if m != nil {
+		return m.Psbt
+	}
+	return nil
+}
+
+type SignMessageResponse struct {
+	// The signature, set only when the message is fully signed.
+	Signature []byte `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
+	Complete  bool   `protobuf:"varint,2,opt,name=complete" json:"complete,omitempty"`
+	// The PSBT of the BIP0322 signature, set only when more signatures are
+	// required.
+	Psbt []byte `protobuf:"bytes,3,opt,name=psbt,proto3" json:"psbt,omitempty"`
+}
+
+func (m *SignMessageResponse) Reset()                    { *m = SignMessageResponse{} }
+func (m *SignMessageResponse) String() string            { return proto.CompactTextString(m) }
+func (*SignMessageResponse) ProtoMessage()               {}
+func (*SignMessageResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{54} }
+
+func (m *SignMessageResponse) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *SignMessageResponse) GetComplete() bool {
+	if m != nil {
+		return m.Complete
+	}
+	return false
+}
+
+func (m *SignMessageResponse) GetPsbt() []byte {
+	if m != nil {
+		return m.Psbt
+	}
+	return nil
+}
+
+type VerifyMessageRequest struct {
+	Address   string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+	Message   string `protobuf:"bytes,2,opt,name=message" json:"message,omitempty"`
+	Signature []byte `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *VerifyMessageRequest) Reset()                    { *m = VerifyMessageRequest{} }
+func (m *VerifyMessageRequest) String() string            { return proto.CompactTextString(m) }
+func (*VerifyMessageRequest) ProtoMessage()               {}
+func (*VerifyMessageRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{55} }
+
+func (m *VerifyMessageRequest) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *VerifyMessageRequest) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *VerifyMessageRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type VerifyMessageResponse struct {
+	Valid bool `protobuf:"varint,1,opt,name=valid" json:"valid,omitempty"`
+}
+
+func (m *VerifyMessageResponse) Reset()                    { *m = VerifyMessageResponse{} }
+func (m *VerifyMessageResponse) String() string            { return proto.CompactTextString(m) }
+func (*VerifyMessageResponse) ProtoMessage()               {}
+func (*VerifyMessageResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{56} }
+
+func (m *VerifyMessageResponse) GetValid() bool {
+	if m != nil {
+		return m.Valid
+	}
+	return false
+}
+
+type TransactionNotificationsRequest struct {
+}
+
+func (m *TransactionNotificationsRequest) Reset()         { *m = TransactionNotificationsRequest{} }
+func (m *TransactionNotificationsRequest) String() string { return proto.CompactTextString(m) }
+func (*TransactionNotificationsRequest) ProtoMessage()    {}
+func (*TransactionNotificationsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{57}
+}
+
+type TransactionNotificationsResponse struct {
+	// Sorted by increasing height.  This is a repeated field so many new blocks
+	// in a new best chain can be notified at once during a reorganize.
+	AttachedBlocks []*BlockDetails `protobuf:"bytes,1,rep,name=attached_blocks,json=attachedBlocks" json:"attached_blocks,omitempty"`
+	// If there was a chain reorganize, there may have been blocks with wallet
+	// transactions that are no longer in the best chain.  These are those
+	// block's hashes.
+	DetachedBlocks [][]byte `protobuf:"bytes,2,rep,name=detached_blocks,json=detachedBlocks,proto3" json:"detached_blocks,omitempty"`
+	// Any new unmined transactions are included here.  These unmined transactions
+	// refer to the current best chain, so transactions from detached blocks may
+	// be moved to mempool and included here if they are not mined or double spent
+	// in the new chain.  Additonally, if no new blocks were attached but a relevant
+	// unmined transaction is seen by the wallet, it will be reported here.
+	UnminedTransactions []*TransactionDetails `protobuf:"bytes,3,rep,name=unmined_transactions,json=unminedTransactions" json:"unmined_transactions,omitempty"`
+	// Instead of notifying all of the removed unmined transactions,
+	// just send all of the current hashes.
+	UnminedTransactionHashes [][]byte `protobuf:"bytes,4,rep,name=unmined_transaction_hashes,json=unminedTransactionHashes,proto3" json:"unmined_transaction_hashes,omitempty"`
+}
+
+func (m *TransactionNotificationsResponse) Reset()         { *m = TransactionNotificationsResponse{} }
+func (m *TransactionNotificationsResponse) String() string { return proto.CompactTextString(m) }
+func (*TransactionNotificationsResponse) ProtoMessage()    {}
+func (*TransactionNotificationsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{58}
+}
+
+func (m *TransactionNotificationsResponse) GetAttachedBlocks() []*BlockDetails {
+	if m != nil {
+		return m.AttachedBlocks
+	}
+	return nil
+}
+
+func (m *TransactionNotificationsResponse) GetDetachedBlocks() [][]byte {
+	if m != nil {
+		return m.DetachedBlocks
+	}
+	return nil
+}
+
+func (m *TransactionNotificationsResponse) GetUnminedTransactions() []*TransactionDetails {
+	if m != nil {
+		return m.UnminedTransactions
+	}
+	return nil
+}
+
+func (m *TransactionNotificationsResponse) GetUnminedTransactionHashes() [][]byte {
+	if m != nil {
+		return m.UnminedTransactionHashes
+	}
+	return nil
+}
+
+type SpentnessNotificationsRequest struct {
+	Account         uint32 `protobuf:"varint,1,opt,name=account" json:"account,omitempty"`
+	NoNotifyUnspent bool   `protobuf:"varint,2,opt,name=no_notify_unspent,json=noNotifyUnspent" json:"no_notify_unspent,omitempty"`
+	NoNotifySpent   bool   `protobuf:"varint,3,opt,name=no_notify_spent,json=noNotifySpent" json:"no_notify_spent,omitempty"`
+}
+
+func (m *SpentnessNotificationsRequest) Reset()                    { *m = SpentnessNotificationsRequest{} }
+func (m *SpentnessNotificationsRequest) String() string            { return proto.CompactTextString(m) }
+func (*SpentnessNotificationsRequest) ProtoMessage()               {}
+func (*SpentnessNotificationsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{59} }
+
+func (m *SpentnessNotificationsRequest) GetAccount() uint32 {
+	if m != nil {
+		return m.Account
+	}
+	return 0
+}
+
+func (m *SpentnessNotificationsRequest) GetNoNotifyUnspent() bool {
+	if m != nil {
+		return m.NoNotifyUnspent
+	}
+	return false
+}
+
+func (m *SpentnessNotificationsRequest) GetNoNotifySpent() bool {
+	if m != nil {
+		return m.NoNotifySpent
+	}
+	return false
+}
+
+type SpentnessNotificationsResponse struct {
+	TransactionHash []byte                                  `protobuf:"bytes,1,opt,name=transaction_hash,json=transactionHash,proto3" json:"transaction_hash,omitempty"`
+	OutputIndex     uint32                                  `protobuf:"varint,2,opt,name=output_index,json=outputIndex" json:"output_index,omitempty"`
+	Spender         *SpentnessNotificationsResponse_Spender `protobuf:"bytes,3,opt,name=spender" json:"spender,omitempty"`
+}
+
+func (m *SpentnessNotificationsResponse) Reset()         { *m = SpentnessNotificationsResponse{} }
+func (m *SpentnessNotificationsResponse) String() string { return proto.CompactTextString(m) }
+func (*SpentnessNotificationsResponse) ProtoMessage()    {}
+func (*SpentnessNotificationsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{60}
+}
+
+func (m *SpentnessNotificationsResponse) GetTransactionHash() []byte {
+	if m != nil {
+		return m.TransactionHash
+	}
+	return nil
+}
+
+func (m *SpentnessNotificationsResponse) GetOutputIndex() uint32 {
+	if m != nil {
+		return m.OutputIndex
+	}
+	return 0
+}
+
+func (m *SpentnessNotificationsResponse) GetSpender() *SpentnessNotificationsResponse_Spender {
+	if m != nil {
+		return m.Spender
+	}
+	return nil
+}
+
+type SpentnessNotificationsResponse_Spender struct {
+	TransactionHash []byte `protobuf:"bytes,1,opt,name=transaction_hash,json=transactionHash,proto3" json:"transaction_hash,omitempty"`
+	InputIndex      uint32 `protobuf:"varint,2,opt,name=input_index,json=inputIndex" json:"input_index,omitempty"`
+}
+
+func (m *SpentnessNotificationsResponse_Spender) Reset() {
+	*m = SpentnessNotificationsResponse_Spender{}
+}
+func (m *SpentnessNotificationsResponse_Spender) String() string { return proto.CompactTextString(m) }
+func (*SpentnessNotificationsResponse_Spender) ProtoMessage()    {}
+func (*SpentnessNotificationsResponse_Spender) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{60, 0}
+}
+
+func (m *SpentnessNotificationsResponse_Spender) GetTransactionHash() []byte {
+	if m != nil {
+		return m.TransactionHash
+	}
+	return nil
+}
+
+func (m *SpentnessNotificationsResponse_Spender) GetInputIndex() uint32 {
+	if m != nil {
+		return m.InputIndex
+	}
+	return 0
+}
+
+type AccountNotificationsRequest struct {
+}
+
+func (m *AccountNotificationsRequest) Reset()                    { *m = AccountNotificationsRequest{} }
+func (m *AccountNotificationsRequest) String() string            { return proto.CompactTextString(m) }
+func (*AccountNotificationsRequest) ProtoMessage()               {}
+func (*AccountNotificationsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{61} }
+
+type AccountNotificationsResponse struct {
+	AccountNumber    uint32 `protobuf:"varint,1,opt,name=account_number,json=accountNumber" json:"account_number,omitempty"`
+	AccountName      string `protobuf:"bytes,2,opt,name=account_name,json=accountName" json:"account_name,omitempty"`
+	ExternalKeyCount uint32 `protobuf:"varint,3,opt,name=external_key_count,json=externalKeyCount" json:"external_key_count,omitempty"`
+	InternalKeyCount uint32 `protobuf:"varint,4,opt,name=internal_key_count,json=internalKeyCount" json:"internal_key_count,omitempty"`
+	ImportedKeyCount uint32 `protobuf:"varint,5,opt,name=imported_key_count,json=importedKeyCount" json:"imported_key_count,omitempty"`
+}
+
+func (m *AccountNotificationsResponse) Reset()                    { *m = AccountNotificationsResponse{} }
+func (m *AccountNotificationsResponse) String() string            { return proto.CompactTextString(m) }
+func (*AccountNotificationsResponse) ProtoMessage()               {}
+func (*AccountNotificationsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{62} }
+
+func (m *AccountNotificationsResponse) GetAccountNumber() uint32 {
+	if m != nil {
+		return m.AccountNumber
+	}
+	return 0
+}
+
+func (m *AccountNotificationsResponse) GetAccountName() string {
+	if m != nil {
+		return m.AccountName
+	}
+	return ""
+}
+
+func (m *AccountNotificationsResponse) GetExternalKeyCount() uint32 {
 	if m != nil {
 		return m.ExternalKeyCount
 	}
@@ -1321,15 +2149,18 @@ func (m *AccountNotificationsResponse) GetImportedKeyCount() uint32 {
 }
 
 type CreateWalletRequest struct {
-	PublicPassphrase  []byte `protobuf:"bytes,1,opt,name=public_passphrase,json=publicPassphrase,proto3" json:"public_passphrase,omitempty"`
-	PrivatePassphrase []byte `protobuf:"bytes,2,opt,name=private_passphrase,json=privatePassphrase,proto3" json:"private_passphrase,omitempty"`
-	Seed              []byte `protobuf:"bytes,3,opt,name=seed,proto3" json:"seed,omitempty"`
+	PublicPassphrase   []byte `protobuf:"bytes,1,opt,name=public_passphrase,json=publicPassphrase,proto3" json:"public_passphrase,omitempty"`
+	PrivatePassphrase  []byte `protobuf:"bytes,2,opt,name=private_passphrase,json=privatePassphrase,proto3" json:"private_passphrase,omitempty"`
+	Seed               []byte `protobuf:"bytes,3,opt,name=seed,proto3" json:"seed,omitempty"`
+	WalletName         string `protobuf:"bytes,4,opt,name=wallet_name,json=walletName" json:"wallet_name,omitempty"`
+	Mnemonic           string `protobuf:"bytes,5,opt,name=mnemonic" json:"mnemonic,omitempty"`
+	MnemonicPassphrase []byte `protobuf:"bytes,6,opt,name=mnemonic_passphrase,json=mnemonicPassphrase,proto3" json:"mnemonic_passphrase,omitempty"`
 }
 
 func (m *CreateWalletRequest) Reset()                    { *m = CreateWalletRequest{} }
 func (m *CreateWalletRequest) String() string            { return proto.CompactTextString(m) }
 func (*CreateWalletRequest) ProtoMessage()               {}
-func (*CreateWalletRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{39} }
+func (*CreateWalletRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{63} }
 
 func (m *CreateWalletRequest) GetPublicPassphrase() []byte {
 	if m != nil {
@@ -1352,22 +2183,44 @@ func (m *CreateWalletRequest) GetSeed() []byte {
 	return nil
 }
 
+func (m *CreateWalletRequest) GetWalletName() string {
+	if m != nil {
+		return m.WalletName
+	}
+	return ""
+}
+
+func (m *CreateWalletRequest) GetMnemonic() string {
+	if m != nil {
+		return m.Mnemonic
+	}
+	return ""
+}
+
+func (m *CreateWalletRequest) GetMnemonicPassphrase() []byte {
+	if m != nil {
+		return m.MnemonicPassphrase
+	}
+	return nil
+}
+
 type CreateWalletResponse struct {
 }
 
 func (m *CreateWalletResponse) Reset()                    { *m = CreateWalletResponse{} }
 func (m *CreateWalletResponse) String() string            { return proto.CompactTextString(m) }
 func (*CreateWalletResponse) ProtoMessage()               {}
-func (*CreateWalletResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{40} }
+func (*CreateWalletResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{64} }
 
 type OpenWalletRequest struct {
 	PublicPassphrase []byte `protobuf:"bytes,1,opt,name=public_passphrase,json=publicPassphrase,proto3" json:"public_passphrase,omitempty"`
+	WalletName       string `protobuf:"bytes,2,opt,name=wallet_name,json=walletName" json:"wallet_name,omitempty"`
 }
 
 func (m *OpenWalletRequest) Reset()                    { *m = OpenWalletRequest{} }
 func (m *OpenWalletRequest) String() string            { return proto.CompactTextString(m) }
 func (*OpenWalletRequest) ProtoMessage()               {}
-func (*OpenWalletRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{41} }
+func (*OpenWalletRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{65} }
 
 func (m *OpenWalletRequest) GetPublicPassphrase() []byte {
 	if m != nil {
@@ -1376,21 +2229,36 @@ func (m *OpenWalletRequest) GetPublicPassphrase() []byte {
 	return nil
 }
 
+func (m *OpenWalletRequest) GetWalletName() string {
+	if m != nil {
+		return m.WalletName
+	}
+	return ""
+}
+
 type OpenWalletResponse struct {
 }
 
 func (m *OpenWalletResponse) Reset()                    { *m = OpenWalletResponse{} }
 func (m *OpenWalletResponse) String() string            { return proto.CompactTextString(m) }
 func (*OpenWalletResponse) ProtoMessage()               {}
-func (*OpenWalletResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{42} }
+func (*OpenWalletResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{66} }
 
 type CloseWalletRequest struct {
+	WalletName string `protobuf:"bytes,1,opt,name=wallet_name,json=walletName" json:"wallet_name,omitempty"`
 }
 
 func (m *CloseWalletRequest) Reset()                    { *m = CloseWalletRequest{} }
 func (m *CloseWalletRequest) String() string            { return proto.CompactTextString(m) }
 func (*CloseWalletRequest) ProtoMessage()               {}
-func (*CloseWalletRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{43} }
+func (*CloseWalletRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{67} }
+
+func (m *CloseWalletRequest) GetWalletName() string {
+	if m != nil {
+		return m.WalletName
+	}
+	return ""
+}
 
 type CloseWalletResponse struct {
 }
@@ -1398,15 +2266,23 @@ type CloseWalletResponse struct {
 func (m *CloseWalletResponse) Reset()                    { *m = CloseWalletResponse{} }
 func (m *CloseWalletResponse) String() string            { return proto.CompactTextString(m) }
 func (*CloseWalletResponse) ProtoMessage()               {}
-func (*CloseWalletResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{44} }
+func (*CloseWalletResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{68} }
 
 type WalletExistsRequest struct {
+	WalletName string `protobuf:"bytes,1,opt,name=wallet_name,json=walletName" json:"wallet_name,omitempty"`
 }
 
 func (m *WalletExistsRequest) Reset()                    { *m = WalletExistsRequest{} }
 func (m *WalletExistsRequest) String() string            { return proto.CompactTextString(m) }
 func (*WalletExistsRequest) ProtoMessage()               {}
-func (*WalletExistsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{45} }
+func (*WalletExistsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{69} }
+
+func (m *WalletExistsRequest) GetWalletName() string {
+	if m != nil {
+		return m.WalletName
+	}
+	return ""
+}
 
 type WalletExistsResponse struct {
 	Exists bool `protobuf:"varint,1,opt,name=exists" json:"exists,omitempty"`
@@ -1415,7 +2291,7 @@ type WalletExistsResponse struct {
 func (m *WalletExistsResponse) Reset()                    { *m = WalletExistsResponse{} }
 func (m *WalletExistsResponse) String() string            { return proto.CompactTextString(m) }
 func (*WalletExistsResponse) ProtoMessage()               {}
-func (*WalletExistsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{46} }
+func (*WalletExistsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{70} }
 
 func (m *WalletExistsResponse) GetExists() bool {
 	if m != nil {
@@ -1424,6 +2300,54 @@ func (m *WalletExistsResponse) GetExists() bool {
 	return false
 }
 
+type ListWalletsRequest struct {
+}
+
+func (m *ListWalletsRequest) Reset()                    { *m = ListWalletsRequest{} }
+func (m *ListWalletsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListWalletsRequest) ProtoMessage()               {}
+func (*ListWalletsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{71} }
+
+type ListWalletsResponse struct {
+	Wallets []*ListWalletsResponse_Wallet `protobuf:"bytes,1,rep,name=wallets" json:"wallets,omitempty"`
+}
+
+func (m *ListWalletsResponse) Reset()                    { *m = ListWalletsResponse{} }
+func (m *ListWalletsResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListWalletsResponse) ProtoMessage()               {}
+func (*ListWalletsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{72} }
+
+func (m *ListWalletsResponse) GetWallets() []*ListWalletsResponse_Wallet {
+	if m != nil {
+		return m.Wallets
+	}
+	return nil
+}
+
+type ListWalletsResponse_Wallet struct {
+	Name   string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Loaded bool   `protobuf:"varint,2,opt,name=loaded" json:"loaded,omitempty"`
+}
+
+func (m *ListWalletsResponse_Wallet) Reset()                    { *m = ListWalletsResponse_Wallet{} }
+func (m *ListWalletsResponse_Wallet) String() string            { return proto.CompactTextString(m) }
+func (*ListWalletsResponse_Wallet) ProtoMessage()               {}
+func (*ListWalletsResponse_Wallet) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{72, 0} }
+
+func (m *ListWalletsResponse_Wallet) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ListWalletsResponse_Wallet) GetLoaded() bool {
+	if m != nil {
+		return m.Loaded
+	}
+	return false
+}
+
 type StartConsensusRpcRequest struct {
 	NetworkAddress string `protobuf:"bytes,1,opt,name=network_address,json=networkAddress" json:"network_address,omitempty"`
 	Username       string `protobuf:"bytes,2,opt,name=username" json:"username,omitempty"`
@@ -1434,7 +2358,7 @@ type StartConsensusRpcRequest struct {
 func (m *StartConsensusRpcRequest) Reset()                    { *m = StartConsensusRpcRequest{} }
 func (m *StartConsensusRpcRequest) String() string            { return proto.CompactTextString(m) }
 func (*StartConsensusRpcRequest) ProtoMessage()               {}
-func (*StartConsensusRpcRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{47} }
+func (*StartConsensusRpcRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{73} }
 
 func (m *StartConsensusRpcRequest) GetNetworkAddress() string {
 	if m != nil {
@@ -1470,7 +2394,7 @@ type StartConsensusRpcResponse struct {
 func (m *StartConsensusRpcResponse) Reset()                    { *m = StartConsensusRpcResponse{} }
 func (m *StartConsensusRpcResponse) String() string            { return proto.CompactTextString(m) }
 func (*StartConsensusRpcResponse) ProtoMessage()               {}
-func (*StartConsensusRpcResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{48} }
+func (*StartConsensusRpcResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{74} }
 
 func init() {
 	proto.RegisterType((*VersionRequest)(nil), "walletrpc.VersionRequest")
@@ -1497,10 +2421,14 @@ func init() {
 	proto.RegisterType((*NextAddressResponse)(nil), "walletrpc.NextAddressResponse")
 	proto.RegisterType((*ImportPrivateKeyRequest)(nil), "walletrpc.ImportPrivateKeyRequest")
 	proto.RegisterType((*ImportPrivateKeyResponse)(nil), "walletrpc.ImportPrivateKeyResponse")
+	proto.RegisterType((*ImportAccountRequest)(nil), "walletrpc.ImportAccountRequest")
+	proto.RegisterType((*ImportAccountResponse)(nil), "walletrpc.ImportAccountResponse")
 	proto.RegisterType((*BalanceRequest)(nil), "walletrpc.BalanceRequest")
 	proto.RegisterType((*BalanceResponse)(nil), "walletrpc.BalanceResponse")
 	proto.RegisterType((*GetTransactionsRequest)(nil), "walletrpc.GetTransactionsRequest")
 	proto.RegisterType((*GetTransactionsResponse)(nil), "walletrpc.GetTransactionsResponse")
+	proto.RegisterType((*BackupWalletRequest)(nil), "walletrpc.BackupWalletRequest")
+	proto.RegisterType((*BackupWalletResponse)(nil), "walletrpc.BackupWalletResponse")
 	proto.RegisterType((*ChangePassphraseRequest)(nil), "walletrpc.ChangePassphraseRequest")
 	proto.RegisterType((*ChangePassphraseResponse)(nil), "walletrpc.ChangePassphraseResponse")
 	proto.RegisterType((*FundTransactionRequest)(nil), "walletrpc.FundTransactionRequest")
@@ -1510,6 +2438,27 @@ func init() {
 	proto.RegisterType((*SignTransactionResponse)(nil), "walletrpc.SignTransactionResponse")
 	proto.RegisterType((*PublishTransactionRequest)(nil), "walletrpc.PublishTransactionRequest")
 	proto.RegisterType((*PublishTransactionResponse)(nil), "walletrpc.PublishTransactionResponse")
+	proto.RegisterType((*BumpFeeRequest)(nil), "walletrpc.BumpFeeRequest")
+	proto.RegisterType((*BumpFeeResponse)(nil), "walletrpc.BumpFeeResponse")
+	proto.RegisterType((*LockOutpointRequest)(nil), "walletrpc.LockOutpointRequest")
+	proto.RegisterType((*LockOutpointResponse)(nil), "walletrpc.LockOutpointResponse")
+	proto.RegisterType((*UnlockOutpointRequest)(nil), "walletrpc.UnlockOutpointRequest")
+	proto.RegisterType((*UnlockOutpointResponse)(nil), "walletrpc.UnlockOutpointResponse")
+	proto.RegisterType((*ListLockedOutpointsRequest)(nil), "walletrpc.ListLockedOutpointsRequest")
+	proto.RegisterType((*ListLockedOutpointsResponse)(nil), "walletrpc.ListLockedOutpointsResponse")
+	proto.RegisterType((*ListLockedOutpointsResponse_LockedOutpoint)(nil), "walletrpc.ListLockedOutpointsResponse.LockedOutpoint")
+	proto.RegisterType((*LabelTransactionRequest)(nil), "walletrpc.LabelTransactionRequest")
+	proto.RegisterType((*LabelTransactionResponse)(nil), "walletrpc.LabelTransactionResponse")
+	proto.RegisterType((*FundPsbtRequest)(nil), "walletrpc.FundPsbtRequest")
+	proto.RegisterType((*FundPsbtResponse)(nil), "walletrpc.FundPsbtResponse")
+	proto.RegisterType((*SignPsbtRequest)(nil), "walletrpc.SignPsbtRequest")
+	proto.RegisterType((*SignPsbtResponse)(nil), "walletrpc.SignPsbtResponse")
+	proto.RegisterType((*FinalizePsbtRequest)(nil), "walletrpc.FinalizePsbtRequest")
+	proto.RegisterType((*FinalizePsbtResponse)(nil), "walletrpc.FinalizePsbtResponse")
+	proto.RegisterType((*SignMessageRequest)(nil), "walletrpc.SignMessageRequest")
+	proto.RegisterType((*SignMessageResponse)(nil), "walletrpc.SignMessageResponse")
+	proto.RegisterType((*VerifyMessageRequest)(nil), "walletrpc.VerifyMessageRequest")
+	proto.RegisterType((*VerifyMessageResponse)(nil), "walletrpc.VerifyMessageResponse")
 	proto.RegisterType((*TransactionNotificationsRequest)(nil), "walletrpc.TransactionNotificationsRequest")
 	proto.RegisterType((*TransactionNotificationsResponse)(nil), "walletrpc.TransactionNotificationsResponse")
 	proto.RegisterType((*SpentnessNotificationsRequest)(nil), "walletrpc.SpentnessNotificationsRequest")
@@ -1525,8 +2474,13 @@ func init() {
 	proto.RegisterType((*CloseWalletResponse)(nil), "walletrpc.CloseWalletResponse")
 	proto.RegisterType((*WalletExistsRequest)(nil), "walletrpc.WalletExistsRequest")
 	proto.RegisterType((*WalletExistsResponse)(nil), "walletrpc.WalletExistsResponse")
+	proto.RegisterType((*ListWalletsRequest)(nil), "walletrpc.ListWalletsRequest")
+	proto.RegisterType((*ListWalletsResponse)(nil), "walletrpc.ListWalletsResponse")
+	proto.RegisterType((*ListWalletsResponse_Wallet)(nil), "walletrpc.ListWalletsResponse.Wallet")
 	proto.RegisterType((*StartConsensusRpcRequest)(nil), "walletrpc.StartConsensusRpcRequest")
 	proto.RegisterType((*StartConsensusRpcResponse)(nil), "walletrpc.StartConsensusRpcResponse")
+	proto.RegisterEnum("walletrpc.AddressType", AddressType_name, AddressType_value)
+	proto.RegisterEnum("walletrpc.CoinSelection", CoinSelection_name, CoinSelection_value)
 	proto.RegisterEnum("walletrpc.NextAddressRequest_Kind", NextAddressRequest_Kind_name, NextAddressRequest_Kind_value)
 	proto.RegisterEnum("walletrpc.ChangePassphraseRequest_Key", ChangePassphraseRequest_Key_name, ChangePassphraseRequest_Key_value)
 }
@@ -1613,6 +2567,7 @@ type WalletServiceClient interface {
 	Accounts(ctx context.Context, in *AccountsRequest, opts ...grpc.CallOption) (*AccountsResponse, error)
 	Balance(ctx context.Context, in *BalanceRequest, opts ...grpc.CallOption) (*BalanceResponse, error)
 	GetTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (*GetTransactionsResponse, error)
+	BackupWallet(ctx context.Context, in *BackupWalletRequest, opts ...grpc.CallOption) (WalletService_BackupWalletClient, error)
 	// Notifications
 	TransactionNotifications(ctx context.Context, in *TransactionNotificationsRequest, opts ...grpc.CallOption) (WalletService_TransactionNotificationsClient, error)
 	SpentnessNotifications(ctx context.Context, in *SpentnessNotificationsRequest, opts ...grpc.CallOption) (WalletService_SpentnessNotificationsClient, error)
@@ -1623,9 +2578,20 @@ type WalletServiceClient interface {
 	NextAccount(ctx context.Context, in *NextAccountRequest, opts ...grpc.CallOption) (*NextAccountResponse, error)
 	NextAddress(ctx context.Context, in *NextAddressRequest, opts ...grpc.CallOption) (*NextAddressResponse, error)
 	ImportPrivateKey(ctx context.Context, in *ImportPrivateKeyRequest, opts ...grpc.CallOption) (*ImportPrivateKeyResponse, error)
+	ImportAccount(ctx context.Context, in *ImportAccountRequest, opts ...grpc.CallOption) (*ImportAccountResponse, error)
 	FundTransaction(ctx context.Context, in *FundTransactionRequest, opts ...grpc.CallOption) (*FundTransactionResponse, error)
 	SignTransaction(ctx context.Context, in *SignTransactionRequest, opts ...grpc.CallOption) (*SignTransactionResponse, error)
 	PublishTransaction(ctx context.Context, in *PublishTransactionRequest, opts ...grpc.CallOption) (*PublishTransactionResponse, error)
+	BumpFee(ctx context.Context, in *BumpFeeRequest, opts ...grpc.CallOption) (*BumpFeeResponse, error)
+	LockOutpoint(ctx context.Context, in *LockOutpointRequest, opts ...grpc.CallOption) (*LockOutpointResponse, error)
+	UnlockOutpoint(ctx context.Context, in *UnlockOutpointRequest, opts ...grpc.CallOption) (*UnlockOutpointResponse, error)
+	ListLockedOutpoints(ctx context.Context, in *ListLockedOutpointsRequest, opts ...grpc.CallOption) (*ListLockedOutpointsResponse, error)
+	LabelTransaction(ctx context.Context, in *LabelTransactionRequest, opts ...grpc.CallOption) (*LabelTransactionResponse, error)
+	FundPsbt(ctx context.Context, in *FundPsbtRequest, opts ...grpc.CallOption) (*FundPsbtResponse, error)
+	SignPsbt(ctx context.Context, in *SignPsbtRequest, opts ...grpc.CallOption) (*SignPsbtResponse, error)
+	FinalizePsbt(ctx context.Context, in *FinalizePsbtRequest, opts ...grpc.CallOption) (*FinalizePsbtResponse, error)
+	SignMessage(ctx context.Context, in *SignMessageRequest, opts ...grpc.CallOption) (*SignMessageResponse, error)
+	VerifyMessage(ctx context.Context, in *VerifyMessageRequest, opts ...grpc.CallOption) (*VerifyMessageResponse, error)
 }
 
 type walletServiceClient struct {
@@ -1690,8 +2656,40 @@ func (c *walletServiceClient) GetTransactions(ctx context.Context, in *GetTransa
 	return out, nil
 }
 
+func (c *walletServiceClient) BackupWallet(ctx context.Context, in *BackupWalletRequest, opts ...grpc.CallOption) (WalletService_BackupWalletClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_WalletService_serviceDesc.Streams[0], c.cc, "/walletrpc.WalletService/BackupWallet", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &walletServiceBackupWalletClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WalletService_BackupWalletClient interface {
+	Recv() (*BackupWalletResponse, error)
+	grpc.ClientStream
+}
+
+type walletServiceBackupWalletClient struct {
+	grpc.ClientStream
+}
+
+func (x *walletServiceBackupWalletClient) Recv() (*BackupWalletResponse, error) {
+	m := new(BackupWalletResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *walletServiceClient) TransactionNotifications(ctx context.Context, in *TransactionNotificationsRequest, opts ...grpc.CallOption) (WalletService_TransactionNotificationsClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_WalletService_serviceDesc.Streams[0], c.cc, "/walletrpc.WalletService/TransactionNotifications", opts...)
+	stream, err := grpc.NewClientStream(ctx, &_WalletService_serviceDesc.Streams[1], c.cc, "/walletrpc.WalletService/TransactionNotifications", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1723,7 +2721,7 @@ func (x *walletServiceTransactionNotificationsClient) Recv() (*TransactionNotifi
 }
 
 func (c *walletServiceClient) SpentnessNotifications(ctx context.Context, in *SpentnessNotificationsRequest, opts ...grpc.CallOption) (WalletService_SpentnessNotificationsClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_WalletService_serviceDesc.Streams[1], c.cc, "/walletrpc.WalletService/SpentnessNotifications", opts...)
+	stream, err := grpc.NewClientStream(ctx, &_WalletService_serviceDesc.Streams[2], c.cc, "/walletrpc.WalletService/SpentnessNotifications", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1751,107 +2749,206 @@ func (x *walletServiceSpentnessNotificationsClient) Recv() (*SpentnessNotificati
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
-	return m, nil
+	return m, nil
+}
+
+func (c *walletServiceClient) AccountNotifications(ctx context.Context, in *AccountNotificationsRequest, opts ...grpc.CallOption) (WalletService_AccountNotificationsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_WalletService_serviceDesc.Streams[3], c.cc, "/walletrpc.WalletService/AccountNotifications", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &walletServiceAccountNotificationsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WalletService_AccountNotificationsClient interface {
+	Recv() (*AccountNotificationsResponse, error)
+	grpc.ClientStream
+}
+
+type walletServiceAccountNotificationsClient struct {
+	grpc.ClientStream
+}
+
+func (x *walletServiceAccountNotificationsClient) Recv() (*AccountNotificationsResponse, error) {
+	m := new(AccountNotificationsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *walletServiceClient) ChangePassphrase(ctx context.Context, in *ChangePassphraseRequest, opts ...grpc.CallOption) (*ChangePassphraseResponse, error) {
+	out := new(ChangePassphraseResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/ChangePassphrase", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) RenameAccount(ctx context.Context, in *RenameAccountRequest, opts ...grpc.CallOption) (*RenameAccountResponse, error) {
+	out := new(RenameAccountResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/RenameAccount", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) NextAccount(ctx context.Context, in *NextAccountRequest, opts ...grpc.CallOption) (*NextAccountResponse, error) {
+	out := new(NextAccountResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/NextAccount", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) NextAddress(ctx context.Context, in *NextAddressRequest, opts ...grpc.CallOption) (*NextAddressResponse, error) {
+	out := new(NextAddressResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/NextAddress", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) ImportPrivateKey(ctx context.Context, in *ImportPrivateKeyRequest, opts ...grpc.CallOption) (*ImportPrivateKeyResponse, error) {
+	out := new(ImportPrivateKeyResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/ImportPrivateKey", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) ImportAccount(ctx context.Context, in *ImportAccountRequest, opts ...grpc.CallOption) (*ImportAccountResponse, error) {
+	out := new(ImportAccountResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/ImportAccount", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) FundTransaction(ctx context.Context, in *FundTransactionRequest, opts ...grpc.CallOption) (*FundTransactionResponse, error) {
+	out := new(FundTransactionResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/FundTransaction", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) SignTransaction(ctx context.Context, in *SignTransactionRequest, opts ...grpc.CallOption) (*SignTransactionResponse, error) {
+	out := new(SignTransactionResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/SignTransaction", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) PublishTransaction(ctx context.Context, in *PublishTransactionRequest, opts ...grpc.CallOption) (*PublishTransactionResponse, error) {
+	out := new(PublishTransactionResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/PublishTransaction", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (c *walletServiceClient) AccountNotifications(ctx context.Context, in *AccountNotificationsRequest, opts ...grpc.CallOption) (WalletService_AccountNotificationsClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_WalletService_serviceDesc.Streams[2], c.cc, "/walletrpc.WalletService/AccountNotifications", opts...)
+func (c *walletServiceClient) BumpFee(ctx context.Context, in *BumpFeeRequest, opts ...grpc.CallOption) (*BumpFeeResponse, error) {
+	out := new(BumpFeeResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/BumpFee", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &walletServiceAccountNotificationsClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
-	}
-	return x, nil
-}
-
-type WalletService_AccountNotificationsClient interface {
-	Recv() (*AccountNotificationsResponse, error)
-	grpc.ClientStream
-}
-
-type walletServiceAccountNotificationsClient struct {
-	grpc.ClientStream
+	return out, nil
 }
 
-func (x *walletServiceAccountNotificationsClient) Recv() (*AccountNotificationsResponse, error) {
-	m := new(AccountNotificationsResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
+func (c *walletServiceClient) LockOutpoint(ctx context.Context, in *LockOutpointRequest, opts ...grpc.CallOption) (*LockOutpointResponse, error) {
+	out := new(LockOutpointResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/LockOutpoint", in, out, c.cc, opts...)
+	if err != nil {
 		return nil, err
 	}
-	return m, nil
+	return out, nil
 }
 
-func (c *walletServiceClient) ChangePassphrase(ctx context.Context, in *ChangePassphraseRequest, opts ...grpc.CallOption) (*ChangePassphraseResponse, error) {
-	out := new(ChangePassphraseResponse)
-	err := grpc.Invoke(ctx, "/walletrpc.WalletService/ChangePassphrase", in, out, c.cc, opts...)
+func (c *walletServiceClient) UnlockOutpoint(ctx context.Context, in *UnlockOutpointRequest, opts ...grpc.CallOption) (*UnlockOutpointResponse, error) {
+	out := new(UnlockOutpointResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/UnlockOutpoint", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *walletServiceClient) RenameAccount(ctx context.Context, in *RenameAccountRequest, opts ...grpc.CallOption) (*RenameAccountResponse, error) {
-	out := new(RenameAccountResponse)
-	err := grpc.Invoke(ctx, "/walletrpc.WalletService/RenameAccount", in, out, c.cc, opts...)
+func (c *walletServiceClient) ListLockedOutpoints(ctx context.Context, in *ListLockedOutpointsRequest, opts ...grpc.CallOption) (*ListLockedOutpointsResponse, error) {
+	out := new(ListLockedOutpointsResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/ListLockedOutpoints", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *walletServiceClient) NextAccount(ctx context.Context, in *NextAccountRequest, opts ...grpc.CallOption) (*NextAccountResponse, error) {
-	out := new(NextAccountResponse)
-	err := grpc.Invoke(ctx, "/walletrpc.WalletService/NextAccount", in, out, c.cc, opts...)
+func (c *walletServiceClient) LabelTransaction(ctx context.Context, in *LabelTransactionRequest, opts ...grpc.CallOption) (*LabelTransactionResponse, error) {
+	out := new(LabelTransactionResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/LabelTransaction", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *walletServiceClient) NextAddress(ctx context.Context, in *NextAddressRequest, opts ...grpc.CallOption) (*NextAddressResponse, error) {
-	out := new(NextAddressResponse)
-	err := grpc.Invoke(ctx, "/walletrpc.WalletService/NextAddress", in, out, c.cc, opts...)
+func (c *walletServiceClient) FundPsbt(ctx context.Context, in *FundPsbtRequest, opts ...grpc.CallOption) (*FundPsbtResponse, error) {
+	out := new(FundPsbtResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/FundPsbt", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *walletServiceClient) ImportPrivateKey(ctx context.Context, in *ImportPrivateKeyRequest, opts ...grpc.CallOption) (*ImportPrivateKeyResponse, error) {
-	out := new(ImportPrivateKeyResponse)
-	err := grpc.Invoke(ctx, "/walletrpc.WalletService/ImportPrivateKey", in, out, c.cc, opts...)
+func (c *walletServiceClient) SignPsbt(ctx context.Context, in *SignPsbtRequest, opts ...grpc.CallOption) (*SignPsbtResponse, error) {
+	out := new(SignPsbtResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/SignPsbt", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *walletServiceClient) FundTransaction(ctx context.Context, in *FundTransactionRequest, opts ...grpc.CallOption) (*FundTransactionResponse, error) {
-	out := new(FundTransactionResponse)
-	err := grpc.Invoke(ctx, "/walletrpc.WalletService/FundTransaction", in, out, c.cc, opts...)
+func (c *walletServiceClient) FinalizePsbt(ctx context.Context, in *FinalizePsbtRequest, opts ...grpc.CallOption) (*FinalizePsbtResponse, error) {
+	out := new(FinalizePsbtResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/FinalizePsbt", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *walletServiceClient) SignTransaction(ctx context.Context, in *SignTransactionRequest, opts ...grpc.CallOption) (*SignTransactionResponse, error) {
-	out := new(SignTransactionResponse)
-	err := grpc.Invoke(ctx, "/walletrpc.WalletService/SignTransaction", in, out, c.cc, opts...)
+func (c *walletServiceClient) SignMessage(ctx context.Context, in *SignMessageRequest, opts ...grpc.CallOption) (*SignMessageResponse, error) {
+	out := new(SignMessageResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/SignMessage", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *walletServiceClient) PublishTransaction(ctx context.Context, in *PublishTransactionRequest, opts ...grpc.CallOption) (*PublishTransactionResponse, error) {
-	out := new(PublishTransactionResponse)
-	err := grpc.Invoke(ctx, "/walletrpc.WalletService/PublishTransaction", in, out, c.cc, opts...)
+func (c *walletServiceClient) VerifyMessage(ctx context.Context, in *VerifyMessageRequest, opts ...grpc.CallOption) (*VerifyMessageResponse, error) {
+	out := new(VerifyMessageResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletService/VerifyMessage", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1868,6 +2965,7 @@ type WalletServiceServer interface {
 	Accounts(context.Context, *AccountsRequest) (*AccountsResponse, error)
 	Balance(context.Context, *BalanceRequest) (*BalanceResponse, error)
 	GetTransactions(context.Context, *GetTransactionsRequest) (*GetTransactionsResponse, error)
+	BackupWallet(*BackupWalletRequest, WalletService_BackupWalletServer) error
 	// Notifications
 	TransactionNotifications(*TransactionNotificationsRequest, WalletService_TransactionNotificationsServer) error
 	SpentnessNotifications(*SpentnessNotificationsRequest, WalletService_SpentnessNotificationsServer) error
@@ -1878,9 +2976,20 @@ type WalletServiceServer interface {
 	NextAccount(context.Context, *NextAccountRequest) (*NextAccountResponse, error)
 	NextAddress(context.Context, *NextAddressRequest) (*NextAddressResponse, error)
 	ImportPrivateKey(context.Context, *ImportPrivateKeyRequest) (*ImportPrivateKeyResponse, error)
+	ImportAccount(context.Context, *ImportAccountRequest) (*ImportAccountResponse, error)
 	FundTransaction(context.Context, *FundTransactionRequest) (*FundTransactionResponse, error)
 	SignTransaction(context.Context, *SignTransactionRequest) (*SignTransactionResponse, error)
 	PublishTransaction(context.Context, *PublishTransactionRequest) (*PublishTransactionResponse, error)
+	BumpFee(context.Context, *BumpFeeRequest) (*BumpFeeResponse, error)
+	LockOutpoint(context.Context, *LockOutpointRequest) (*LockOutpointResponse, error)
+	UnlockOutpoint(context.Context, *UnlockOutpointRequest) (*UnlockOutpointResponse, error)
+	ListLockedOutpoints(context.Context, *ListLockedOutpointsRequest) (*ListLockedOutpointsResponse, error)
+	LabelTransaction(context.Context, *LabelTransactionRequest) (*LabelTransactionResponse, error)
+	FundPsbt(context.Context, *FundPsbtRequest) (*FundPsbtResponse, error)
+	SignPsbt(context.Context, *SignPsbtRequest) (*SignPsbtResponse, error)
+	FinalizePsbt(context.Context, *FinalizePsbtRequest) (*FinalizePsbtResponse, error)
+	SignMessage(context.Context, *SignMessageRequest) (*SignMessageResponse, error)
+	VerifyMessage(context.Context, *VerifyMessageRequest) (*VerifyMessageResponse, error)
 }
 
 func RegisterWalletServiceServer(s *grpc.Server, srv WalletServiceServer) {
@@ -1995,6 +3104,27 @@ func _WalletService_GetTransactions_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WalletService_BackupWallet_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BackupWalletRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WalletServiceServer).BackupWallet(m, &walletServiceBackupWalletServer{stream})
+}
+
+type WalletService_BackupWalletServer interface {
+	Send(*BackupWalletResponse) error
+	grpc.ServerStream
+}
+
+type walletServiceBackupWalletServer struct {
+	grpc.ServerStream
+}
+
+func (x *walletServiceBackupWalletServer) Send(m *BackupWalletResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _WalletService_TransactionNotifications_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(TransactionNotificationsRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -2148,6 +3278,24 @@ func _WalletService_ImportPrivateKey_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WalletService_ImportAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).ImportAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/ImportAccount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).ImportAccount(ctx, req.(*ImportAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _WalletService_FundTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(FundTransactionRequest)
 	if err := dec(in); err != nil {
@@ -2202,6 +3350,186 @@ func _WalletService_PublishTransaction_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WalletService_BumpFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BumpFeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).BumpFee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/BumpFee",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).BumpFee(ctx, req.(*BumpFeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_LockOutpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LockOutpointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).LockOutpoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/LockOutpoint",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).LockOutpoint(ctx, req.(*LockOutpointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_UnlockOutpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlockOutpointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).UnlockOutpoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/UnlockOutpoint",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).UnlockOutpoint(ctx, req.(*UnlockOutpointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_ListLockedOutpoints_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLockedOutpointsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).ListLockedOutpoints(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/ListLockedOutpoints",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).ListLockedOutpoints(ctx, req.(*ListLockedOutpointsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_LabelTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LabelTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).LabelTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/LabelTransaction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).LabelTransaction(ctx, req.(*LabelTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_FundPsbt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FundPsbtRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).FundPsbt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/FundPsbt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).FundPsbt(ctx, req.(*FundPsbtRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_SignPsbt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignPsbtRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).SignPsbt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/SignPsbt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).SignPsbt(ctx, req.(*SignPsbtRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_FinalizePsbt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FinalizePsbtRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).FinalizePsbt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/FinalizePsbt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).FinalizePsbt(ctx, req.(*FinalizePsbtRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_SignMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).SignMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/SignMessage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).SignMessage(ctx, req.(*SignMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_VerifyMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).VerifyMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletService/VerifyMessage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).VerifyMessage(ctx, req.(*VerifyMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _WalletService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "walletrpc.WalletService",
 	HandlerType: (*WalletServiceServer)(nil),
@@ -2250,6 +3578,10 @@ var _WalletService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ImportPrivateKey",
 			Handler:    _WalletService_ImportPrivateKey_Handler,
 		},
+		{
+			MethodName: "ImportAccount",
+			Handler:    _WalletService_ImportAccount_Handler,
+		},
 		{
 			MethodName: "FundTransaction",
 			Handler:    _WalletService_FundTransaction_Handler,
@@ -2262,8 +3594,53 @@ var _WalletService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "PublishTransaction",
 			Handler:    _WalletService_PublishTransaction_Handler,
 		},
+		{
+			MethodName: "BumpFee",
+			Handler:    _WalletService_BumpFee_Handler,
+		},
+		{
+			MethodName: "LockOutpoint",
+			Handler:    _WalletService_LockOutpoint_Handler,
+		},
+		{
+			MethodName: "UnlockOutpoint",
+			Handler:    _WalletService_UnlockOutpoint_Handler,
+		},
+		{
+			MethodName: "ListLockedOutpoints",
+			Handler:    _WalletService_ListLockedOutpoints_Handler,
+		},
+		{
+			MethodName: "LabelTransaction",
+			Handler:    _WalletService_LabelTransaction_Handler,
+		},
+		{
+			MethodName: "FundPsbt",
+			Handler:    _WalletService_FundPsbt_Handler,
+		},
+		{
+			MethodName: "SignPsbt",
+			Handler:    _WalletService_SignPsbt_Handler,
+		},
+		{
+			MethodName: "FinalizePsbt",
+			Handler:    _WalletService_FinalizePsbt_Handler,
+		},
+		{
+			MethodName: "SignMessage",
+			Handler:    _WalletService_SignMessage_Handler,
+		},
+		{
+			MethodName: "VerifyMessage",
+			Handler:    _WalletService_VerifyMessage_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "BackupWallet",
+			Handler:       _WalletService_BackupWallet_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "TransactionNotifications",
 			Handler:       _WalletService_TransactionNotifications_Handler,
@@ -2291,6 +3668,7 @@ type WalletLoaderServiceClient interface {
 	OpenWallet(ctx context.Context, in *OpenWalletRequest, opts ...grpc.CallOption) (*OpenWalletResponse, error)
 	CloseWallet(ctx context.Context, in *CloseWalletRequest, opts ...grpc.CallOption) (*CloseWalletResponse, error)
 	StartConsensusRpc(ctx context.Context, in *StartConsensusRpcRequest, opts ...grpc.CallOption) (*StartConsensusRpcResponse, error)
+	ListWallets(ctx context.Context, in *ListWalletsRequest, opts ...grpc.CallOption) (*ListWalletsResponse, error)
 }
 
 type walletLoaderServiceClient struct {
@@ -2346,6 +3724,15 @@ func (c *walletLoaderServiceClient) StartConsensusRpc(ctx context.Context, in *S
 	return out, nil
 }
 
+func (c *walletLoaderServiceClient) ListWallets(ctx context.Context, in *ListWalletsRequest, opts ...grpc.CallOption) (*ListWalletsResponse, error) {
+	out := new(ListWalletsResponse)
+	err := grpc.Invoke(ctx, "/walletrpc.WalletLoaderService/ListWallets", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for WalletLoaderService service
 
 type WalletLoaderServiceServer interface {
@@ -2354,6 +3741,7 @@ type WalletLoaderServiceServer interface {
 	OpenWallet(context.Context, *OpenWalletRequest) (*OpenWalletResponse, error)
 	CloseWallet(context.Context, *CloseWalletRequest) (*CloseWalletResponse, error)
 	StartConsensusRpc(context.Context, *StartConsensusRpcRequest) (*StartConsensusRpcResponse, error)
+	ListWallets(context.Context, *ListWalletsRequest) (*ListWalletsResponse, error)
 }
 
 func RegisterWalletLoaderServiceServer(s *grpc.Server, srv WalletLoaderServiceServer) {
@@ -2450,6 +3838,24 @@ func _WalletLoaderService_StartConsensusRpc_Handler(srv interface{}, ctx context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WalletLoaderService_ListWallets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWalletsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletLoaderServiceServer).ListWallets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletLoaderService/ListWallets",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletLoaderServiceServer).ListWallets(ctx, req.(*ListWalletsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _WalletLoaderService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "walletrpc.WalletLoaderService",
 	HandlerType: (*WalletLoaderServiceServer)(nil),
@@ -2474,6 +3880,10 @@ var _WalletLoaderService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "StartConsensusRpc",
 			Handler:    _WalletLoaderService_StartConsensusRpc_Handler,
 		},
+		{
+			MethodName: "ListWallets",
+			Handler:    _WalletLoaderService_ListWallets_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "api.proto",
@@ -2482,156 +3892,224 @@ var _WalletLoaderService_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("api.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 2402 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x19, 0x4b, 0x73, 0x1b, 0x49,
-	0x79, 0xc7, 0xe3, 0x87, 0xfc, 0xe9, 0xdd, 0x96, 0x65, 0x65, 0x12, 0x27, 0xce, 0xec, 0x23, 0xd9,
-	0xec, 0xae, 0x09, 0x26, 0x0b, 0x4b, 0xb1, 0x15, 0x36, 0x31, 0x59, 0x56, 0x24, 0x38, 0xaa, 0x71,
-	0xb2, 0x49, 0xd5, 0x52, 0x4c, 0x8d, 0x67, 0xda, 0x76, 0x63, 0xa9, 0x47, 0x99, 0x19, 0xc5, 0x31,
-	0x27, 0x8a, 0x2a, 0x8e, 0x5c, 0x80, 0x03, 0x05, 0xb5, 0x17, 0x7e, 0x01, 0x55, 0x5c, 0x38, 0xb2,
-	0xbf, 0x81, 0x23, 0xff, 0x82, 0x5f, 0x40, 0xf5, 0x4b, 0xea, 0xd6, 0x8c, 0x64, 0x7b, 0x8b, 0x9b,
-	0xe6, 0x7b, 0xf5, 0xd7, 0x5f, 0x7f, 0x6f, 0xc1, 0x6a, 0x30, 0x24, 0xdb, 0xc3, 0x24, 0xce, 0x62,
-	0xb4, 0x7a, 0x1a, 0xf4, 0xfb, 0x38, 0x4b, 0x86, 0xa1, 0xdb, 0x80, 0xda, 0x97, 0x38, 0x49, 0x49,
-	0x4c, 0x3d, 0xfc, 0x6a, 0x84, 0xd3, 0xcc, 0xfd, 0xc6, 0x82, 0xfa, 0x18, 0x94, 0x0e, 0x63, 0x9a,
-	0x62, 0xf4, 0x2e, 0xd4, 0x5e, 0x0b, 0x90, 0x9f, 0x66, 0x09, 0xa1, 0x47, 0x1d, 0x6b, 0xcb, 0xba,
-	0xbd, 0xea, 0x55, 0x25, 0x74, 0x9f, 0x03, 0x51, 0x0b, 0x96, 0x06, 0xc1, 0xaf, 0xe2, 0xa4, 0xb3,
-	0xb0, 0x65, 0xdd, 0xae, 0x7a, 0xe2, 0x83, 0x43, 0x09, 0x8d, 0x93, 0x8e, 0x2d, 0xa1, 0xec, 0x83,
-	0x41, 0x87, 0x41, 0x16, 0x1e, 0x77, 0x16, 0x05, 0x94, 0x7f, 0xa0, 0xeb, 0x00, 0xc3, 0x04, 0x27,
-	0xb8, 0x8f, 0x83, 0x14, 0x77, 0x96, 0xf8, 0x21, 0x1a, 0x84, 0x29, 0x72, 0x30, 0x22, 0xfd, 0xc8,
-	0x1f, 0xe0, 0x2c, 0x88, 0x82, 0x2c, 0xe8, 0x2c, 0x0b, 0x45, 0x38, 0xf4, 0xe7, 0x12, 0xe8, 0xfe,
-	0xcb, 0x06, 0xf4, 0x2c, 0x09, 0x68, 0x1a, 0x84, 0x19, 0x89, 0xe9, 0x4f, 0x70, 0x16, 0x90, 0x7e,
-	0x8a, 0x10, 0x2c, 0x1e, 0x07, 0xe9, 0x31, 0x57, 0xbe, 0xe2, 0xf1, 0xdf, 0x68, 0x0b, 0xca, 0xd9,
-	0x84, 0x92, 0x6b, 0x5e, 0xf1, 0x74, 0x10, 0xfa, 0x11, 0x2c, 0x47, 0xf8, 0x80, 0x64, 0x69, 0xc7,
-	0xde, 0xb2, 0x6f, 0x97, 0x77, 0xde, 0xde, 0x1e, 0x9b, 0x6f, 0x3b, 0x7f, 0xc8, 0x76, 0x97, 0x0e,
-	0x47, 0x99, 0x27, 0x59, 0xd0, 0x7d, 0x58, 0x09, 0x13, 0x1c, 0x31, 0xee, 0x45, 0xce, 0xfd, 0xce,
-	0x7c, 0xee, 0xa7, 0xa3, 0x8c, 0xb1, 0x2b, 0x26, 0xd4, 0x00, 0xfb, 0x10, 0x0b, 0x4b, 0xd8, 0x1e,
-	0xfb, 0x89, 0xae, 0xc1, 0x6a, 0x46, 0x06, 0x38, 0xcd, 0x82, 0xc1, 0x90, 0xdf, 0xde, 0xf6, 0x26,
-	0x00, 0xe7, 0x15, 0x2c, 0x71, 0x05, 0x98, 0x7d, 0x09, 0x8d, 0xf0, 0x1b, 0x7e, 0xd9, 0xaa, 0x27,
-	0x3e, 0xd0, 0xfb, 0xd0, 0x18, 0x26, 0xf8, 0x35, 0x89, 0x47, 0xa9, 0x1f, 0x84, 0x61, 0x3c, 0xa2,
-	0x99, 0x7c, 0xac, 0xba, 0x82, 0x3f, 0x10, 0x60, 0x74, 0x0b, 0xea, 0x13, 0xd2, 0x01, 0xa7, 0xb4,
-	0xf9, 0x69, 0xb5, 0x31, 0x25, 0x87, 0x3a, 0xcf, 0x60, 0x59, 0x68, 0x3d, 0xe3, 0xcc, 0x0e, 0xac,
-	0x98, 0x47, 0xa9, 0x4f, 0xe4, 0x40, 0x89, 0xd0, 0x0c, 0x27, 0x34, 0xe8, 0x73, 0xd9, 0x25, 0x6f,
-	0xfc, 0xed, 0xfe, 0xd5, 0x82, 0xca, 0xc3, 0x7e, 0x1c, 0x9e, 0xcc, 0x7b, 0xbc, 0x36, 0x2c, 0x1f,
-	0x63, 0x72, 0x74, 0x2c, 0x24, 0x2f, 0x79, 0xf2, 0xcb, 0xb4, 0x91, 0x3d, 0x65, 0x23, 0xf4, 0x00,
-	0x2a, 0xda, 0xfb, 0xaa, 0x87, 0xd9, 0x9c, 0xfb, 0x30, 0x9e, 0xc1, 0xe2, 0x3e, 0x85, 0x9a, 0xb4,
-	0xd3, 0xc3, 0xa0, 0x1f, 0xd0, 0x10, 0xeb, 0xb7, 0xb4, 0xcc, 0x5b, 0xbe, 0x0d, 0xd5, 0x2c, 0xce,
-	0x82, 0xbe, 0x7f, 0x20, 0x48, 0xb9, 0xae, 0xb6, 0x57, 0xe1, 0x40, 0xc9, 0xee, 0x56, 0xa1, 0xdc,
-	0x23, 0xf4, 0x48, 0x05, 0x61, 0x0d, 0x2a, 0xe2, 0x53, 0x04, 0x20, 0x0b, 0xd3, 0x3d, 0x9c, 0x9d,
-	0xc6, 0xc9, 0x89, 0xa2, 0xf8, 0x04, 0xea, 0x63, 0xc8, 0x24, 0x4a, 0x99, 0x7e, 0xaf, 0xb1, 0x4f,
-	0x05, 0x46, 0x6a, 0x52, 0x15, 0x50, 0x49, 0xee, 0xfe, 0x10, 0x5a, 0x52, 0xf7, 0xbd, 0xd1, 0xe0,
-	0x00, 0x27, 0x52, 0x22, 0xba, 0x09, 0x15, 0xa9, 0xb2, 0x4f, 0x83, 0x01, 0x96, 0x21, 0x5e, 0x96,
-	0xb0, 0xbd, 0x60, 0x80, 0xdd, 0xfb, 0xb0, 0x3e, 0xc5, 0xaa, 0x1f, 0x2d, 0x79, 0x39, 0x66, 0x72,
-	0xb4, 0x46, 0xee, 0x36, 0xa1, 0x2e, 0xf9, 0x53, 0x75, 0x8f, 0x7f, 0xda, 0xd0, 0x98, 0xc0, 0xa4,
-	0xb8, 0x1f, 0x43, 0x49, 0x32, 0xa6, 0x1d, 0x2b, 0x17, 0x74, 0xd3, 0xe4, 0x0a, 0xe0, 0x8d, 0x99,
-	0xd0, 0x87, 0x80, 0xc2, 0x51, 0x92, 0x60, 0x9a, 0xf9, 0x07, 0xcc, 0x89, 0x7c, 0xee, 0x3a, 0x22,
-	0xb8, 0x1b, 0x12, 0xc3, 0xbd, 0xeb, 0x0b, 0xe6, 0x46, 0x77, 0xa1, 0x35, 0x45, 0x2d, 0x9c, 0xca,
-	0xe6, 0x4e, 0x85, 0x0c, 0x7a, 0x8e, 0x71, 0x7e, 0xbb, 0x00, 0x2b, 0x2a, 0x50, 0x2e, 0x76, 0xf7,
-	0x9c, 0x79, 0x17, 0x72, 0xe6, 0xcd, 0x7b, 0x8a, 0x9d, 0xf7, 0x14, 0x76, 0x35, 0xfc, 0x46, 0x04,
-	0x89, 0x7f, 0x82, 0xcf, 0x7c, 0xe1, 0x73, 0x22, 0x8b, 0x36, 0x14, 0xe6, 0x31, 0x3e, 0xdb, 0xe5,
-	0xca, 0x7d, 0x08, 0x48, 0x85, 0x94, 0x46, 0xbd, 0x24, 0xa8, 0x15, 0xc6, 0xa0, 0x1e, 0x0c, 0xe3,
-	0x24, 0xc3, 0x91, 0x46, 0xbd, 0x2c, 0xa9, 0x25, 0x46, 0x51, 0xbb, 0x2f, 0xa1, 0xe5, 0x61, 0x76,
-	0x17, 0x65, 0x7f, 0xe9, 0x48, 0x17, 0x34, 0xc8, 0x15, 0x28, 0x51, 0x7c, 0xaa, 0x1b, 0x63, 0x85,
-	0xe2, 0x53, 0xee, 0x67, 0x1b, 0xb0, 0x3e, 0x25, 0x59, 0xc6, 0xc1, 0x0b, 0x40, 0x7b, 0xf8, 0x4d,
-	0x36, 0x75, 0x20, 0xab, 0x1a, 0x41, 0x9a, 0x0e, 0x8f, 0x13, 0x56, 0x35, 0x44, 0x82, 0xd0, 0x20,
-	0x17, 0x30, 0xbd, 0xfb, 0x29, 0xac, 0x19, 0x82, 0x2f, 0xe7, 0xd7, 0x7f, 0xb1, 0xa4, 0x5e, 0x51,
-	0x94, 0xe0, 0x54, 0xf9, 0xf6, 0x9c, 0x9c, 0xf0, 0x7d, 0x58, 0x3c, 0x21, 0x34, 0xe2, 0x9a, 0xd4,
-	0x76, 0x5c, 0xcd, 0xb9, 0xf3, 0x62, 0xb6, 0x1f, 0x13, 0x1a, 0x79, 0x9c, 0xde, 0xdd, 0x81, 0x45,
-	0xf6, 0x85, 0x5a, 0xd0, 0x78, 0xd8, 0xed, 0xdd, 0xbd, 0x7b, 0xef, 0x9e, 0xff, 0xe8, 0xe5, 0xb3,
-	0x47, 0xde, 0xde, 0x83, 0x27, 0x8d, 0xb7, 0x74, 0x68, 0x77, 0x4f, 0x42, 0x2d, 0xf7, 0x3b, 0xf2,
-	0x6a, 0x4a, 0xa8, 0xbc, 0x1a, 0x53, 0x4e, 0x80, 0x64, 0xa4, 0xab, 0x4f, 0xf7, 0x8f, 0x16, 0x6c,
-	0x74, 0xf9, 0x63, 0xf7, 0x12, 0xf2, 0x3a, 0xc8, 0xf0, 0x63, 0x7c, 0x76, 0x51, 0x53, 0xcf, 0x4e,
-	0xf6, 0xef, 0xb1, 0x7a, 0xc2, 0xc5, 0x71, 0xd7, 0x3a, 0x25, 0x87, 0xdc, 0xbd, 0x57, 0xbd, 0xea,
-	0x70, 0x7c, 0xca, 0x0b, 0x72, 0xc8, 0x72, 0x7a, 0x82, 0xd3, 0x30, 0xa0, 0xdc, 0xa7, 0x4b, 0x9e,
-	0xfc, 0x72, 0x1d, 0xe8, 0xe4, 0x95, 0x92, 0x6e, 0x41, 0xa1, 0x26, 0xc3, 0xe3, 0x92, 0x3e, 0xf8,
-	0x31, 0xb4, 0x13, 0xfc, 0x6a, 0x44, 0x12, 0x1c, 0xf9, 0x61, 0x4c, 0x0f, 0x49, 0x32, 0x08, 0x44,
-	0x51, 0x10, 0x05, 0x65, 0x5d, 0x61, 0x77, 0x75, 0xa4, 0x4b, 0xa1, 0x3e, 0x3e, 0x4f, 0x9a, 0xb3,
-	0x05, 0x4b, 0x3c, 0x4c, 0xf9, 0x39, 0xb6, 0x27, 0x3e, 0x58, 0x21, 0x4a, 0x87, 0x98, 0x46, 0xc1,
-	0x41, 0x5f, 0xe5, 0xfd, 0x09, 0x80, 0x95, 0x58, 0x32, 0x18, 0x04, 0xd9, 0x28, 0xc1, 0x7e, 0x82,
-	0x4f, 0x83, 0x24, 0x52, 0x25, 0x56, 0x81, 0x3d, 0x0e, 0x75, 0xff, 0xbc, 0x00, 0xed, 0x9f, 0xe2,
-	0x4c, 0x2b, 0x4b, 0x63, 0x1f, 0xdb, 0x86, 0xb5, 0x34, 0x0b, 0x92, 0x8c, 0xd0, 0x23, 0x3d, 0xd5,
-	0x89, 0x97, 0x69, 0x2a, 0xd4, 0x24, 0xd7, 0xed, 0xc0, 0xfa, 0x34, 0xfd, 0xa4, 0x82, 0x36, 0xbd,
-	0x35, 0x93, 0x43, 0x94, 0xd3, 0x3b, 0xd0, 0xc4, 0x34, 0x9a, 0x3a, 0xc1, 0xe6, 0x27, 0xd4, 0x05,
-	0x62, 0x22, 0x7f, 0x1b, 0xd6, 0x4c, 0x5a, 0x21, 0x7d, 0x91, 0x9b, 0xb3, 0xa9, 0x53, 0x0b, 0xd9,
-	0xf7, 0xe1, 0xea, 0x80, 0x50, 0x32, 0x18, 0x0d, 0xfc, 0x04, 0x87, 0x2c, 0x05, 0x1b, 0xb5, 0x79,
-	0x89, 0xf3, 0x5d, 0x91, 0x24, 0x1e, 0xa7, 0xd0, 0xcd, 0xe0, 0xfe, 0xc3, 0x82, 0x8d, 0x9c, 0x69,
-	0xe4, 0x9b, 0x7c, 0x0e, 0x68, 0x40, 0x28, 0x8e, 0x4c, 0x91, 0xa2, 0xa0, 0x6c, 0x68, 0x31, 0xa7,
-	0xf7, 0x19, 0x5e, 0x93, 0xb3, 0xe8, 0xf2, 0x50, 0x0f, 0x5a, 0x23, 0x5a, 0x20, 0x69, 0xe1, 0x22,
-	0x8d, 0xc3, 0x9a, 0x64, 0x35, 0xb4, 0xfe, 0xc6, 0x82, 0x8d, 0xdd, 0xe3, 0x80, 0x1e, 0xe1, 0xde,
-	0x38, 0x76, 0xd4, 0x8b, 0x7e, 0x02, 0xf6, 0x09, 0x3e, 0xe3, 0x2f, 0x58, 0xdb, 0x79, 0x4f, 0x13,
-	0x3e, 0x83, 0x61, 0x9b, 0x45, 0x02, 0x63, 0x61, 0x4e, 0x1f, 0xf7, 0x23, 0x5f, 0x0b, 0x50, 0x51,
-	0xf1, 0xaa, 0x71, 0x3f, 0x9a, 0xb0, 0x31, 0x32, 0x96, 0x78, 0x35, 0x32, 0xf1, 0x96, 0x55, 0x8a,
-	0x4f, 0x27, 0x64, 0xee, 0x75, 0xb0, 0x1f, 0xe3, 0x33, 0x54, 0x86, 0x95, 0x9e, 0xd7, 0xfd, 0xf2,
-	0xc1, 0xb3, 0x47, 0x8d, 0xb7, 0x10, 0xc0, 0x72, 0xef, 0xf9, 0xc3, 0x27, 0xdd, 0xdd, 0x86, 0xc5,
-	0x02, 0x32, 0xaf, 0x91, 0x0c, 0xc8, 0xdf, 0x2c, 0x40, 0xfb, 0xf3, 0x11, 0xd5, 0x2f, 0x7d, 0x7e,
-	0x52, 0x64, 0xe5, 0x2f, 0x48, 0x8e, 0x70, 0xa6, 0xfa, 0x4d, 0xd5, 0x28, 0x71, 0xa0, 0xe8, 0x36,
-	0xe7, 0x44, 0xac, 0x3d, 0x27, 0x62, 0xd1, 0xa7, 0xe0, 0x10, 0x1a, 0xf6, 0x47, 0x11, 0xf6, 0xc7,
-	0x21, 0x17, 0xc6, 0x84, 0x1e, 0x04, 0x29, 0x4e, 0x65, 0xa6, 0xe9, 0x48, 0x8a, 0xae, 0x24, 0xd8,
-	0x55, 0x78, 0x16, 0x34, 0x8a, 0x3b, 0xe4, 0x57, 0xf6, 0xd3, 0x30, 0x21, 0x43, 0x51, 0x48, 0x4b,
-	0xde, 0x9a, 0x44, 0x0a, 0x73, 0xec, 0x73, 0x94, 0xfb, 0x37, 0x1b, 0x36, 0x72, 0x26, 0x90, 0x8e,
-	0xf9, 0x0b, 0x68, 0xa4, 0xb8, 0x8f, 0x43, 0x56, 0x67, 0x63, 0xde, 0x3b, 0x2b, 0xb7, 0xfc, 0xae,
-	0xf6, 0xde, 0x33, 0xb8, 0xb7, 0x7b, 0xb2, 0xff, 0x96, 0xb3, 0x42, 0x5d, 0x89, 0x12, 0xdf, 0x29,
-	0x2b, 0x77, 0xa2, 0x8d, 0x30, 0xcc, 0x58, 0xe6, 0x30, 0x69, 0xc5, 0xdb, 0xd0, 0x90, 0x17, 0x19,
-	0x9e, 0xa8, 0xbb, 0x08, 0x27, 0xa8, 0x09, 0x78, 0xef, 0x44, 0x5c, 0xc3, 0xf9, 0x8f, 0x05, 0x35,
-	0xf3, 0x40, 0x36, 0x44, 0x68, 0x61, 0xa0, 0xe7, 0x9b, 0xba, 0x06, 0xe7, 0xd9, 0xe0, 0x26, 0x54,
-	0xc4, 0xfd, 0x7c, 0x31, 0x18, 0x88, 0x9a, 0x50, 0x16, 0xb0, 0x2e, 0x1f, 0x0f, 0xda, 0xb0, 0x6c,
-	0x8c, 0x17, 0xf2, 0x0b, 0x5d, 0x85, 0xd5, 0x89, 0x6e, 0x8b, 0x5c, 0x7c, 0x69, 0x28, 0xb5, 0x62,
-	0x72, 0x59, 0xb6, 0x60, 0xbd, 0x2e, 0xeb, 0xeb, 0xe5, 0x7c, 0x54, 0x96, 0xb0, 0x67, 0x44, 0x34,
-	0x53, 0x87, 0x49, 0x3c, 0x18, 0xbf, 0x32, 0x6f, 0x63, 0x4a, 0x5e, 0x85, 0x01, 0xd5, 0xcb, 0xba,
-	0x7f, 0xb2, 0xa0, 0xbd, 0x4f, 0x8e, 0x68, 0x81, 0x9f, 0x9e, 0x57, 0xe9, 0x3e, 0x86, 0x76, 0x8a,
-	0x13, 0x12, 0xf4, 0xc9, 0xaf, 0xcd, 0xbc, 0x20, 0x83, 0x6e, 0x7d, 0x82, 0xd5, 0xa4, 0x33, 0xb5,
-	0x08, 0x1d, 0x1b, 0x04, 0x8b, 0xa1, 0xb2, 0xea, 0x55, 0x38, 0xb0, 0x2b, 0x60, 0xee, 0x2b, 0xd8,
-	0xc8, 0x69, 0x25, 0x5d, 0x67, 0x6a, 0x5e, 0xb5, 0xf2, 0xf3, 0xea, 0x3d, 0x68, 0x8f, 0x68, 0x4a,
-	0x8e, 0x58, 0xba, 0x32, 0x8f, 0x5a, 0xe0, 0x47, 0xb5, 0x14, 0xb6, 0xab, 0x1f, 0xf9, 0x33, 0xb8,
-	0xd2, 0x1b, 0x1d, 0xf4, 0x49, 0x7a, 0x5c, 0x60, 0x8b, 0x8f, 0x00, 0x49, 0x81, 0xf9, 0xb3, 0x9b,
-	0x02, 0xa3, 0x71, 0xb9, 0xd7, 0xc0, 0x29, 0x92, 0x25, 0x73, 0xc3, 0x4d, 0xb8, 0xa1, 0x81, 0xf7,
-	0xe2, 0x8c, 0x1c, 0x92, 0x30, 0xd0, 0x8b, 0x9a, 0xfb, 0xf5, 0x02, 0x6c, 0xcd, 0xa6, 0x91, 0x96,
-	0xf8, 0x0c, 0xea, 0x41, 0x96, 0x05, 0xe1, 0x31, 0x8e, 0x44, 0xad, 0x39, 0x37, 0xb5, 0xd7, 0x14,
-	0x3d, 0x87, 0xa6, 0xac, 0xfe, 0x46, 0xd8, 0x94, 0xc0, 0x4c, 0x54, 0xf1, 0x6a, 0x0a, 0x2c, 0x09,
-	0x67, 0x15, 0x00, 0xfb, 0xdb, 0x16, 0x00, 0x96, 0x8f, 0x0a, 0x24, 0xf2, 0x58, 0xc2, 0x62, 0x22,
-	0xad, 0x78, 0x9d, 0x3c, 0xe3, 0x17, 0x1c, 0xef, 0xfe, 0xde, 0x82, 0xcd, 0xfd, 0x21, 0xa6, 0x19,
-	0xc5, 0x69, 0x5a, 0x64, 0xc1, 0x39, 0x59, 0xf6, 0x0e, 0x34, 0x69, 0xec, 0x53, 0xc6, 0x74, 0xe6,
-	0x8f, 0x28, 0xeb, 0x46, 0x44, 0x8a, 0x28, 0x79, 0x75, 0x1a, 0x73, 0x61, 0x67, 0xcf, 0x05, 0x98,
-	0xf5, 0x6c, 0x13, 0x5a, 0x41, 0x29, 0xe6, 0xf4, 0xaa, 0xa2, 0xe4, 0x5a, 0xb8, 0x7f, 0x58, 0x80,
-	0xeb, 0xb3, 0xf4, 0x91, 0xaf, 0xf5, 0xff, 0x4d, 0x1a, 0x8f, 0x61, 0x85, 0xb7, 0x51, 0x58, 0x6c,
-	0x95, 0xcc, 0xbc, 0x39, 0x5f, 0x13, 0x8e, 0x8e, 0x70, 0xe2, 0x29, 0x09, 0xce, 0x73, 0x58, 0x91,
-	0xb0, 0xcb, 0x68, 0x79, 0x03, 0xca, 0x5a, 0x74, 0x49, 0x25, 0x61, 0x12, 0xc6, 0xee, 0x26, 0x5c,
-	0x55, 0xc3, 0x72, 0x91, 0x8f, 0xff, 0xd7, 0x82, 0x6b, 0xc5, 0xf8, 0x4b, 0xcd, 0x1e, 0x17, 0x99,
-	0x2b, 0x8b, 0x47, 0x46, 0xfb, 0x52, 0x23, 0xe3, 0xe2, 0xa5, 0x46, 0xc6, 0xa5, 0x19, 0x23, 0xe3,
-	0xef, 0x2c, 0x58, 0xdb, 0x4d, 0x70, 0x90, 0xe1, 0x17, 0xfc, 0xb9, 0x94, 0xbb, 0x7e, 0x00, 0xcd,
-	0x21, 0xcb, 0x18, 0xa1, 0x9f, 0xcb, 0xb9, 0x0d, 0x81, 0xd0, 0xfa, 0x97, 0x8f, 0x00, 0xa9, 0x49,
-	0x22, 0xd7, 0xea, 0x34, 0x25, 0x46, 0x23, 0x47, 0xb0, 0x98, 0x62, 0x1c, 0xc9, 0xfa, 0xc6, 0x7f,
-	0xbb, 0x6d, 0x68, 0x99, 0x6a, 0xc8, 0xdc, 0xf4, 0x19, 0x34, 0x9f, 0x0e, 0x31, 0xfd, 0xf6, 0xca,
-	0xb9, 0x2d, 0x40, 0xba, 0x04, 0x29, 0xb7, 0x05, 0x68, 0xb7, 0x1f, 0xa7, 0xe6, 0xad, 0xdd, 0x75,
-	0x58, 0x33, 0xa0, 0x92, 0x78, 0x1d, 0xd6, 0x04, 0xe4, 0xd1, 0x1b, 0x92, 0x4e, 0x36, 0x25, 0xdb,
-	0xd0, 0x32, 0xc1, 0xd2, 0x4f, 0xda, 0xb0, 0x8c, 0x39, 0x84, 0xeb, 0x54, 0xf2, 0xe4, 0x97, 0xfb,
-	0xb5, 0x05, 0x9d, 0x7d, 0xd6, 0xcd, 0xef, 0x32, 0x32, 0x9a, 0x8e, 0x52, 0x6f, 0x18, 0xaa, 0x3b,
-	0xdd, 0x82, 0xba, 0x5c, 0x12, 0xf9, 0xe6, 0x14, 0x58, 0x93, 0x60, 0x39, 0x2e, 0x22, 0x07, 0x4a,
-	0xa3, 0x94, 0x3d, 0xf9, 0xd8, 0xb5, 0xc6, 0xdf, 0x0c, 0xc7, 0x2c, 0x72, 0x1a, 0x27, 0xca, 0xba,
-	0xe3, 0x6f, 0x56, 0xa7, 0x42, 0x9c, 0x48, 0xbf, 0xc6, 0xb2, 0x80, 0xeb, 0x20, 0xf7, 0x2a, 0x5c,
-	0x29, 0x50, 0x4f, 0x5c, 0x6a, 0xc7, 0x1b, 0xef, 0xa5, 0xf7, 0x71, 0xf2, 0x9a, 0x84, 0x2c, 0xdd,
-	0xaf, 0x48, 0x08, 0xba, 0xa2, 0x05, 0xbb, 0xb9, 0xbd, 0x76, 0x9c, 0x22, 0x94, 0x94, 0xf9, 0xef,
-	0x32, 0x54, 0x85, 0x05, 0x95, 0xcc, 0x1f, 0xc0, 0x62, 0x8f, 0xd0, 0x23, 0xd4, 0xd6, 0xb8, 0xb4,
-	0x35, 0x9c, 0xb3, 0x91, 0x83, 0x8f, 0x6b, 0xcf, 0x8a, 0x5c, 0xa7, 0x19, 0xca, 0x98, 0x3b, 0x3a,
-	0x43, 0x99, 0xe9, 0x65, 0x9d, 0x07, 0x55, 0x63, 0x95, 0x86, 0x6e, 0xe4, 0x37, 0x5c, 0xc6, 0x7e,
-	0xce, 0xd9, 0x9a, 0x4d, 0x20, 0x65, 0xee, 0x42, 0x49, 0xed, 0xc6, 0x90, 0x53, 0xb8, 0x30, 0x13,
-	0x92, 0xae, 0xce, 0x59, 0xa6, 0xb1, 0xab, 0xa9, 0x55, 0x93, 0x7e, 0x35, 0x73, 0xbe, 0x36, 0xae,
-	0x36, 0x3d, 0x0a, 0xbf, 0x84, 0xfa, 0xd4, 0x44, 0x86, 0x6e, 0x6a, 0xe4, 0xc5, 0x83, 0xac, 0xe3,
-	0xce, 0x23, 0x91, 0x92, 0x47, 0xd0, 0x99, 0xd5, 0x16, 0xa0, 0x3b, 0xc5, 0x55, 0xb8, 0x28, 0xf7,
-	0x3a, 0x1f, 0x5c, 0x88, 0x56, 0x1c, 0x7a, 0xd7, 0x42, 0x31, 0xb4, 0x8b, 0x6b, 0x0a, 0xba, 0x7d,
-	0x81, 0xb2, 0x23, 0x8e, 0x7c, 0xff, 0xc2, 0x05, 0xea, 0xae, 0x85, 0xc8, 0x64, 0x45, 0x6b, 0x1c,
-	0xf7, 0x5e, 0x81, 0x0b, 0x14, 0x1d, 0x76, 0xeb, 0x5c, 0xba, 0xf1, 0x51, 0x5f, 0x41, 0x63, 0x7a,
-	0x8a, 0x43, 0xee, 0xf9, 0x43, 0xa7, 0xf3, 0xf6, 0x5c, 0x9a, 0x89, 0x93, 0x1b, 0x7b, 0x3c, 0xc3,
-	0xc9, 0x8b, 0x76, 0x87, 0x86, 0x93, 0x17, 0xae, 0x00, 0xd1, 0x13, 0x28, 0x6b, 0x9b, 0x3a, 0xb4,
-	0x39, 0xbd, 0x3b, 0x33, 0xe5, 0x5d, 0x9f, 0x85, 0x9e, 0x92, 0x26, 0xb3, 0xdd, 0xe6, 0xdc, 0x4d,
-	0x5c, 0x5e, 0xda, 0xd4, 0x4e, 0xed, 0x2b, 0x68, 0x4c, 0xef, 0xa8, 0x0c, 0x63, 0xce, 0xd8, 0xaa,
-	0x19, 0xc6, 0x9c, 0xb5, 0xe4, 0x62, 0x61, 0x35, 0x35, 0x11, 0x1a, 0x61, 0x55, 0x3c, 0x6e, 0x1b,
-	0x61, 0x35, 0x6b, 0x1c, 0x7d, 0x09, 0xf5, 0xa9, 0x71, 0xc3, 0x90, 0x5c, 0x3c, 0x20, 0x19, 0x92,
-	0x67, 0x4d, 0x2b, 0x01, 0xa0, 0xfc, 0x24, 0x80, 0xf4, 0xff, 0xc0, 0x66, 0x0e, 0x1d, 0xce, 0xbb,
-	0xe7, 0x50, 0xc9, 0xac, 0xfe, 0x77, 0x5b, 0x95, 0xcb, 0x27, 0x71, 0x10, 0xe1, 0x44, 0xe5, 0xf6,
-	0xa7, 0x50, 0xd1, 0xcb, 0x25, 0xd2, 0xdf, 0xae, 0xa0, 0xbc, 0x3a, 0x37, 0x66, 0xe2, 0xe5, 0x5d,
-	0x9e, 0x42, 0x45, 0xef, 0x19, 0x0c, 0x81, 0x05, 0x3d, 0x8d, 0x21, 0xb0, 0xa8, 0xd9, 0x40, 0x5d,
-	0x80, 0x49, 0xab, 0x80, 0xae, 0x69, 0xe4, 0xb9, 0x1e, 0xc4, 0xd9, 0x9c, 0x81, 0x9d, 0xb8, 0xb1,
-	0xd6, 0x49, 0x18, 0x6e, 0x9c, 0xef, 0x3b, 0x0c, 0x37, 0x2e, 0x68, 0x40, 0xd0, 0x2f, 0xa1, 0x99,
-	0xab, 0xcc, 0x48, 0xf7, 0xd1, 0x59, 0x6d, 0x85, 0xf3, 0xce, 0x7c, 0x22, 0x21, 0xff, 0x60, 0x99,
-	0xff, 0x0d, 0xfd, 0xbd, 0xff, 0x05, 0x00, 0x00, 0xff, 0xff, 0x22, 0xfc, 0x70, 0xb3, 0x93, 0x1e,
-	0x00, 0x00,
+	// 3502 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x3a, 0x4b, 0x73, 0xdc, 0xc6,
+	0xd1, 0xde, 0x07, 0x1f, 0x6a, 0xee, 0x8b, 0xb3, 0x7c, 0xac, 0x40, 0x89, 0xa4, 0x20, 0xeb, 0x61,
+	0xd9, 0xa6, 0xf5, 0xd1, 0x92, 0x3f, 0xbb, 0x3e, 0x97, 0xfc, 0x91, 0x14, 0x65, 0xf1, 0x13, 0x45,
+	0xf2, 0x03, 0x29, 0xc9, 0x89, 0x53, 0x46, 0xc0, 0xc5, 0x90, 0x1c, 0x73, 0x17, 0x58, 0x01, 0x58,
+	0x51, 0xf4, 0x6f, 0x48, 0x2e, 0x4e, 0x0e, 0xae, 0xa4, 0x5c, 0x95, 0x4a, 0xe5, 0x1f, 0xe4, 0x9a,
+	0x4b, 0xfe, 0x42, 0xae, 0xf1, 0x29, 0xe7, 0x9c, 0x92, 0x7b, 0x2a, 0x35, 0x2f, 0x60, 0x06, 0xc0,
+	0x2e, 0x49, 0x3b, 0xc9, 0x0d, 0xd3, 0xdd, 0xd3, 0xd3, 0xdd, 0xd3, 0xd3, 0xd3, 0xdd, 0x03, 0xb8,
+	0xe4, 0xf4, 0xc8, 0x52, 0x2f, 0xf0, 0x23, 0x1f, 0x5d, 0x3a, 0x71, 0x3a, 0x1d, 0x1c, 0x05, 0xbd,
+	0xb6, 0xd9, 0x80, 0xda, 0x73, 0x1c, 0x84, 0xc4, 0xf7, 0x2c, 0xfc, 0xb2, 0x8f, 0xc3, 0xc8, 0xfc,
+	0x63, 0x01, 0xea, 0x31, 0x28, 0xec, 0xf9, 0x5e, 0x88, 0xd1, 0x0d, 0xa8, 0xbd, 0xe2, 0x20, 0x3b,
+	0x8c, 0x02, 0xe2, 0x1d, 0xb6, 0x0a, 0x8b, 0x85, 0xdb, 0x97, 0xac, 0xaa, 0x80, 0xee, 0x32, 0x20,
+	0x9a, 0x82, 0x91, 0xae, 0xf3, 0xa5, 0x1f, 0xb4, 0x8a, 0x8b, 0x85, 0xdb, 0x55, 0x8b, 0x0f, 0x18,
+	0x94, 0x78, 0x7e, 0xd0, 0x2a, 0x09, 0x28, 0xf1, 0x38, 0xb4, 0xe7, 0x44, 0xed, 0xa3, 0x56, 0x99,
+	0x43, 0xd9, 0x00, 0xcd, 0x03, 0xf4, 0x02, 0x1c, 0xe0, 0x0e, 0x76, 0x42, 0xdc, 0x1a, 0x61, 0x8b,
+	0x28, 0x10, 0x2a, 0xc8, 0x7e, 0x9f, 0x74, 0x5c, 0xbb, 0x8b, 0x23, 0xc7, 0x75, 0x22, 0xa7, 0x35,
+	0xca, 0x05, 0x61, 0xd0, 0xa7, 0x02, 0x68, 0xfe, 0xb5, 0x04, 0x68, 0x2f, 0x70, 0xbc, 0xd0, 0x69,
+	0x47, 0xc4, 0xf7, 0x1e, 0xe2, 0xc8, 0x21, 0x9d, 0x10, 0x21, 0x28, 0x1f, 0x39, 0xe1, 0x11, 0x13,
+	0xbe, 0x62, 0xb1, 0x6f, 0xb4, 0x08, 0x13, 0x51, 0x42, 0xc9, 0x24, 0xaf, 0x58, 0x2a, 0x08, 0xfd,
+	0x0f, 0x8c, 0xba, 0x78, 0x9f, 0x44, 0x61, 0xab, 0xb4, 0x58, 0xba, 0x3d, 0xb1, 0x7c, 0x7d, 0x29,
+	0x36, 0xdf, 0x52, 0x76, 0x91, 0xa5, 0x0d, 0xaf, 0xd7, 0x8f, 0x2c, 0x31, 0x05, 0x3d, 0x80, 0xb1,
+	0x76, 0x80, 0x5d, 0x3a, 0xbb, 0xcc, 0x66, 0xbf, 0x39, 0x7c, 0xf6, 0x76, 0x3f, 0xa2, 0xd3, 0xe5,
+	0x24, 0xd4, 0x80, 0xd2, 0x01, 0xe6, 0x96, 0x28, 0x59, 0xf4, 0x13, 0x5d, 0x81, 0x4b, 0x11, 0xe9,
+	0xe2, 0x30, 0x72, 0xba, 0x3d, 0xa6, 0x7d, 0xc9, 0x4a, 0x00, 0xd4, 0xac, 0x1d, 0x67, 0x1f, 0x77,
+	0x5a, 0x63, 0xcc, 0x2e, 0x7c, 0x60, 0xbc, 0x84, 0x11, 0x26, 0x16, 0x45, 0x13, 0xcf, 0xc5, 0xaf,
+	0x99, 0x09, 0xaa, 0x16, 0x1f, 0xa0, 0xb7, 0xa0, 0xd1, 0x0b, 0xf0, 0x2b, 0xe2, 0xf7, 0x43, 0xdb,
+	0x69, 0xb7, 0xfd, 0xbe, 0x17, 0x89, 0x2d, 0xac, 0x4b, 0xf8, 0x0a, 0x07, 0xa3, 0x5b, 0x50, 0x4f,
+	0x48, 0xbb, 0x8c, 0xb2, 0xc4, 0x64, 0xa8, 0xc5, 0x94, 0x0c, 0x6a, 0x7c, 0x09, 0xa3, 0x5c, 0x97,
+	0x01, 0x6b, 0xb6, 0x60, 0x4c, 0x5f, 0x4a, 0x0e, 0x91, 0x01, 0xe3, 0xc4, 0x8b, 0x70, 0xe0, 0x39,
+	0x1d, 0xc6, 0x7b, 0xdc, 0x8a, 0xc7, 0x89, 0x7a, 0x65, 0x45, 0x3d, 0xf3, 0xd7, 0x05, 0xa8, 0xac,
+	0x76, 0xfc, 0xf6, 0xf1, 0xb0, 0x8d, 0x9e, 0x81, 0xd1, 0x23, 0x4c, 0x0e, 0x8f, 0xf8, 0x7a, 0x23,
+	0x96, 0x18, 0xe9, 0xf6, 0x2c, 0xa5, 0xed, 0xb9, 0x02, 0x15, 0xc5, 0x17, 0xe4, 0x26, 0x5e, 0x1d,
+	0xba, 0x89, 0x96, 0x36, 0xc5, 0xdc, 0x86, 0x9a, 0xb0, 0xde, 0xaa, 0xd3, 0x71, 0xbc, 0x36, 0x56,
+	0x75, 0x2f, 0xe8, 0xba, 0x5f, 0x87, 0x6a, 0xe4, 0x47, 0x4e, 0xc7, 0xde, 0xe7, 0xa4, 0x4c, 0xd6,
+	0x92, 0x55, 0x61, 0x40, 0x31, 0xdd, 0xac, 0xc2, 0xc4, 0x0e, 0xf1, 0x0e, 0xe5, 0x81, 0xad, 0x41,
+	0x85, 0x0f, 0xf9, 0x61, 0xa5, 0x47, 0x7a, 0x0b, 0x47, 0x27, 0x7e, 0x70, 0x2c, 0x29, 0x3e, 0x84,
+	0x7a, 0x0c, 0x49, 0x4e, 0x34, 0x95, 0xef, 0x15, 0xb6, 0x3d, 0x8e, 0x11, 0x92, 0x54, 0x39, 0x54,
+	0x90, 0x9b, 0x1f, 0xc1, 0x94, 0x90, 0x7d, 0xab, 0xdf, 0xdd, 0xc7, 0x81, 0xe0, 0x88, 0xae, 0x41,
+	0x45, 0x88, 0x6c, 0x7b, 0x4e, 0x17, 0x8b, 0x70, 0x30, 0x21, 0x60, 0x5b, 0x4e, 0x17, 0x9b, 0x0f,
+	0x60, 0x3a, 0x35, 0x55, 0x5d, 0x5a, 0xcc, 0x65, 0x98, 0x64, 0x69, 0x85, 0xdc, 0x9c, 0x84, 0xba,
+	0x98, 0x1f, 0x4a, 0x3d, 0xbe, 0x2e, 0x43, 0x23, 0x81, 0x09, 0x76, 0x9f, 0xc0, 0xb8, 0x98, 0x18,
+	0xb6, 0x0a, 0x99, 0x03, 0x9a, 0x26, 0x97, 0x00, 0x2b, 0x9e, 0x84, 0xde, 0x01, 0xd4, 0xee, 0x07,
+	0x01, 0xf6, 0x22, 0x7b, 0x9f, 0x3a, 0x91, 0xcd, 0x5c, 0x87, 0x07, 0x82, 0x86, 0xc0, 0x30, 0xef,
+	0x7a, 0x4c, 0xdd, 0xe8, 0x2e, 0x4c, 0xa5, 0xa8, 0xb9, 0x53, 0x95, 0x98, 0x53, 0x21, 0x8d, 0x9e,
+	0x61, 0x8c, 0xef, 0x8a, 0x30, 0x26, 0x8f, 0xcf, 0xf9, 0x74, 0xcf, 0x98, 0xb7, 0x98, 0x31, 0x6f,
+	0xd6, 0x53, 0x4a, 0x59, 0x4f, 0xa1, 0xaa, 0xe1, 0xd7, 0xfc, 0xe8, 0xd8, 0xc7, 0xf8, 0xd4, 0xe6,
+	0x3e, 0xc7, 0x23, 0x6e, 0x43, 0x62, 0x9e, 0xe0, 0xd3, 0x35, 0x26, 0xdc, 0x3b, 0x80, 0x88, 0x97,
+	0xa1, 0x1e, 0xe1, 0xd4, 0xc4, 0xcb, 0xa1, 0xee, 0xf6, 0xfc, 0x20, 0xc2, 0xae, 0x42, 0x3d, 0x2a,
+	0xa8, 0x05, 0x26, 0xa6, 0xfe, 0x08, 0x2a, 0x8e, 0xeb, 0x06, 0x38, 0x0c, 0xed, 0xe8, 0xb4, 0x87,
+	0x59, 0x78, 0xaa, 0x2d, 0xcf, 0xa8, 0x3b, 0xc5, 0xd1, 0x7b, 0xa7, 0x3d, 0x6c, 0x4d, 0x38, 0xc9,
+	0x00, 0x5d, 0x05, 0x38, 0xa1, 0x97, 0x83, 0xed, 0x7b, 0x9d, 0xd3, 0xd6, 0x38, 0x8b, 0x08, 0x97,
+	0x18, 0x64, 0xdb, 0xeb, 0x9c, 0x9a, 0x9f, 0xc1, 0x94, 0x85, 0xa9, 0x95, 0xe4, 0xce, 0x0a, 0x17,
+	0x3d, 0xa7, 0xa9, 0x2f, 0xc3, 0xb8, 0x87, 0x4f, 0x54, 0x33, 0x8f, 0x79, 0xf8, 0x84, 0x79, 0xf0,
+	0x2c, 0x4c, 0xa7, 0x38, 0x8b, 0x13, 0xf6, 0x75, 0x01, 0xd0, 0x16, 0x7e, 0x1d, 0xa5, 0x56, 0xa4,
+	0x97, 0x97, 0x13, 0x86, 0xbd, 0xa3, 0x80, 0x5e, 0x5e, 0x3c, 0xf6, 0x28, 0x90, 0xf3, 0xec, 0x6a,
+	0xda, 0x4c, 0xa5, 0x73, 0x9b, 0xc9, 0xfc, 0x18, 0x9a, 0x9a, 0x4c, 0x17, 0x3b, 0x6d, 0xbf, 0x92,
+	0x2a, 0x71, 0x8e, 0x52, 0xa5, 0xc1, 0x91, 0xea, 0x03, 0x28, 0x1f, 0x13, 0xcf, 0x65, 0x4a, 0xd4,
+	0x96, 0x4d, 0x45, 0xc2, 0x2c, 0x9b, 0xa5, 0x27, 0xc4, 0x73, 0x2d, 0x46, 0x6f, 0x2e, 0x43, 0x99,
+	0x8e, 0xd0, 0x14, 0x34, 0x56, 0x37, 0x76, 0xee, 0xde, 0xbd, 0x77, 0xcf, 0x5e, 0xff, 0x6c, 0x6f,
+	0xdd, 0xda, 0x5a, 0xd9, 0x6c, 0xbc, 0xa1, 0x42, 0x37, 0xb6, 0x04, 0xb4, 0x60, 0xbe, 0x07, 0x4d,
+	0x8d, 0xa9, 0x50, 0x8d, 0x0a, 0xc7, 0x41, 0x22, 0xfe, 0xc8, 0xa1, 0xf9, 0x8b, 0x02, 0xcc, 0x6e,
+	0x30, 0x17, 0xdc, 0x09, 0xc8, 0x2b, 0x27, 0xc2, 0x4f, 0xf0, 0xe9, 0x79, 0x77, 0x69, 0xf0, 0xc5,
+	0x74, 0x93, 0xde, 0x7d, 0x8c, 0x1d, 0x73, 0xf8, 0x13, 0x72, 0xc0, 0xf6, 0xe7, 0x92, 0x55, 0xed,
+	0xc5, 0xab, 0xbc, 0x20, 0x07, 0xf4, 0xa6, 0x09, 0x70, 0xd8, 0x76, 0x3c, 0x76, 0xd2, 0xc6, 0x2d,
+	0x31, 0x32, 0x0d, 0x68, 0x65, 0x85, 0x12, 0x2e, 0xf5, 0x87, 0x02, 0x4c, 0x71, 0x64, 0xca, 0xa9,
+	0xce, 0x8e, 0xb4, 0x68, 0x09, 0x9a, 0xf4, 0x2c, 0x7b, 0x2e, 0x76, 0xed, 0x5e, 0x7f, 0xbf, 0x43,
+	0xda, 0x54, 0x3e, 0xe1, 0x5e, 0x93, 0x12, 0xb5, 0xc3, 0x30, 0x4f, 0xf0, 0xe9, 0x0f, 0x70, 0xb2,
+	0x81, 0xaa, 0x3d, 0x80, 0xe9, 0x94, 0xf4, 0x17, 0x73, 0x3f, 0x0f, 0x6a, 0x22, 0x66, 0x5d, 0xf0,
+	0xf8, 0xde, 0x87, 0x99, 0x00, 0xbf, 0xec, 0x93, 0x00, 0xbb, 0x76, 0xdb, 0xf7, 0x0e, 0x48, 0xd0,
+	0x75, 0xf8, 0x4d, 0xcd, 0x6f, 0xf9, 0x69, 0x89, 0x5d, 0x53, 0x91, 0xa6, 0x07, 0xf5, 0x78, 0x3d,
+	0x21, 0xe9, 0x14, 0x8c, 0xb0, 0xd8, 0xc9, 0xd6, 0x29, 0x59, 0x7c, 0x40, 0xb3, 0x83, 0xb0, 0x87,
+	0x3d, 0xd7, 0xd9, 0xef, 0xc8, 0xcb, 0x38, 0x01, 0xd0, 0x6c, 0x88, 0x74, 0xbb, 0x4e, 0xd4, 0x0f,
+	0xb0, 0x1d, 0xe0, 0x13, 0x27, 0x70, 0x65, 0x36, 0x24, 0xc1, 0x16, 0x83, 0x9a, 0xdf, 0x14, 0x61,
+	0xe6, 0x53, 0x1c, 0x29, 0xb9, 0x42, 0x7c, 0xc4, 0x96, 0xa0, 0x19, 0x46, 0x4e, 0x10, 0x11, 0xef,
+	0x50, 0xbd, 0x7f, 0xb8, 0x63, 0x4e, 0x4a, 0x54, 0x72, 0x01, 0x2d, 0xc3, 0x74, 0x9a, 0x3e, 0x49,
+	0x6b, 0x26, 0xad, 0xa6, 0x3e, 0x83, 0xa1, 0xd0, 0x1d, 0x98, 0xc4, 0x9e, 0x9b, 0x5a, 0xa1, 0xc4,
+	0x56, 0xa8, 0x73, 0x44, 0xc2, 0x9f, 0x7a, 0x93, 0x46, 0xcb, 0xb9, 0x97, 0x99, 0x39, 0x27, 0x55,
+	0x6a, 0xce, 0xfb, 0x01, 0xcc, 0x75, 0x89, 0x47, 0xba, 0xfd, 0xae, 0x1d, 0xe0, 0x36, 0xbd, 0x17,
+	0xb5, 0x84, 0x69, 0x84, 0xcd, 0xbb, 0x2c, 0x48, 0x2c, 0x46, 0xa1, 0x9a, 0xc1, 0xfc, 0x7d, 0x01,
+	0x66, 0x33, 0xa6, 0x11, 0x7b, 0xf2, 0x08, 0x50, 0x97, 0x78, 0xd8, 0xd5, 0x59, 0xf2, 0x5b, 0x7e,
+	0x56, 0xf1, 0x57, 0x35, 0xf9, 0xb3, 0x26, 0xd9, 0x14, 0x95, 0x1f, 0xda, 0x81, 0xa9, 0xbe, 0x97,
+	0xc3, 0xa9, 0x78, 0x9e, 0x6c, 0xae, 0x29, 0xa6, 0x6a, 0x52, 0xdf, 0x87, 0xe6, 0xaa, 0xd3, 0x3e,
+	0xee, 0xf7, 0x5e, 0xb0, 0xa9, 0xe7, 0x0c, 0x2e, 0xe6, 0x1d, 0x98, 0xd2, 0xa7, 0x09, 0x45, 0x11,
+	0x94, 0x59, 0x35, 0x23, 0x12, 0x56, 0xfa, 0x4d, 0x0b, 0xb1, 0xd9, 0xb5, 0x23, 0xc7, 0x3b, 0xc4,
+	0x3b, 0x31, 0x03, 0xb9, 0xce, 0x87, 0x50, 0xa2, 0x47, 0xbc, 0xc0, 0x4e, 0xee, 0x4d, 0x45, 0xfe,
+	0x01, 0x13, 0x96, 0x68, 0xac, 0xa1, 0x53, 0xe8, 0xb9, 0xf2, 0x3b, 0xae, 0xad, 0x48, 0xc9, 0x33,
+	0x9d, 0xaa, 0xdf, 0x71, 0x93, 0x69, 0x94, 0x8c, 0x5e, 0x8b, 0x0a, 0x19, 0x77, 0x97, 0xaa, 0x87,
+	0x4f, 0x12, 0x32, 0x73, 0x1e, 0x4a, 0x34, 0xa2, 0x4c, 0xc0, 0xd8, 0x8e, 0xb5, 0xf1, 0x7c, 0x65,
+	0x6f, 0xbd, 0xf1, 0x06, 0x02, 0x18, 0xdd, 0x79, 0xb6, 0xba, 0xb9, 0xb1, 0xd6, 0x28, 0xd0, 0x90,
+	0x97, 0x95, 0x48, 0x84, 0xbc, 0xef, 0x8a, 0x30, 0xf3, 0xa8, 0xef, 0xa9, 0x76, 0x3d, 0xfb, 0xda,
+	0xa1, 0x69, 0x8f, 0x13, 0x1c, 0xe2, 0x48, 0x56, 0x1f, 0x32, 0x41, 0x66, 0x40, 0x5e, 0x7b, 0x0c,
+	0x09, 0x0a, 0xa5, 0x21, 0x41, 0x01, 0x7d, 0x0c, 0x06, 0xf1, 0xda, 0x9d, 0xbe, 0x8b, 0xed, 0xf8,
+	0x54, 0xb7, 0x7d, 0xe2, 0xed, 0x3b, 0x21, 0x0e, 0x45, 0xc0, 0x6b, 0x09, 0x8a, 0x0d, 0x41, 0xb0,
+	0x26, 0xf1, 0xf4, 0x5c, 0xca, 0xd9, 0x6d, 0xa6, 0xb2, 0x1d, 0xb6, 0x03, 0xd2, 0xe3, 0x09, 0xd4,
+	0xb8, 0xd5, 0x14, 0x48, 0x6e, 0x8e, 0x5d, 0x86, 0xa2, 0xc9, 0xc7, 0x01, 0xc6, 0x76, 0xe0, 0x44,
+	0x58, 0x94, 0x72, 0x63, 0x07, 0x18, 0x5b, 0x4e, 0x44, 0xd3, 0xda, 0x1a, 0x5d, 0xdb, 0x0e, 0x71,
+	0x07, 0xf3, 0xd2, 0x94, 0xa7, 0x4c, 0x2d, 0x75, 0xb3, 0x7d, 0xe2, 0xed, 0x4a, 0xbc, 0x55, 0x6d,
+	0xab, 0x43, 0xf3, 0x2f, 0x25, 0x98, 0xcd, 0x98, 0x57, 0xb8, 0xdb, 0x4f, 0xa0, 0xc1, 0xf9, 0x62,
+	0xd7, 0xf6, 0x59, 0x95, 0x26, 0x4f, 0xd5, 0x7f, 0x29, 0xec, 0x07, 0xcc, 0x5e, 0xda, 0x11, 0x95,
+	0x9e, 0xa8, 0x55, 0xeb, 0x92, 0x15, 0x1f, 0x87, 0xf4, 0xca, 0xe2, 0xa9, 0xa9, 0xb6, 0x45, 0x13,
+	0x0c, 0x26, 0x76, 0xe8, 0x36, 0x34, 0x84, 0x91, 0x7a, 0xc7, 0xd2, 0x4e, 0xdc, 0xc1, 0x6a, 0x1c,
+	0xbe, 0x73, 0x9c, 0x63, 0xa2, 0xb2, 0x6e, 0xa2, 0xeb, 0x50, 0xc5, 0x61, 0x44, 0xba, 0x0e, 0x55,
+	0x23, 0xa9, 0x92, 0x2b, 0x31, 0xf0, 0x11, 0xc6, 0xc6, 0x9f, 0x0b, 0x50, 0xd3, 0x05, 0xa6, 0xe5,
+	0xae, 0x12, 0x05, 0xd4, 0x70, 0x5b, 0x57, 0xe0, 0x2c, 0x18, 0x5e, 0x83, 0x0a, 0xb7, 0x8f, 0xcd,
+	0x4b, 0x58, 0x9e, 0x11, 0x4c, 0x70, 0xd8, 0x06, 0x05, 0xd1, 0x2b, 0x51, 0x2b, 0x84, 0xc5, 0x08,
+	0xcd, 0xc1, 0xa5, 0x44, 0xb7, 0x32, 0x63, 0x3f, 0xde, 0x93, 0x5a, 0x5d, 0x83, 0x0a, 0x0d, 0x96,
+	0xb4, 0xfe, 0xa2, 0xb5, 0xa6, 0x90, 0x7c, 0x42, 0xc0, 0xf6, 0x08, 0x4f, 0xf0, 0x0f, 0x02, 0xbf,
+	0x1b, 0x7b, 0x20, 0x73, 0x90, 0x71, 0xab, 0x42, 0x81, 0xd2, 0xeb, 0xcc, 0x5f, 0x16, 0x60, 0x66,
+	0x97, 0x1c, 0x7a, 0x39, 0x67, 0xe8, 0xac, 0x3c, 0xe7, 0x3e, 0xcc, 0x84, 0x38, 0x20, 0x4e, 0x87,
+	0x7c, 0xa5, 0x87, 0x45, 0x11, 0x10, 0xa6, 0x13, 0xac, 0xc2, 0x9d, 0x8a, 0x45, 0xbc, 0xd8, 0x20,
+	0x98, 0x37, 0x45, 0xaa, 0x56, 0x85, 0x78, 0xd2, 0x22, 0x38, 0x34, 0x5f, 0xc2, 0x6c, 0x46, 0x2a,
+	0xe1, 0x7a, 0xa9, 0x7e, 0x4b, 0x21, 0xdb, 0x6f, 0xb9, 0x07, 0x33, 0x7d, 0x2f, 0x24, 0x87, 0x34,
+	0x5a, 0xeb, 0x4b, 0x15, 0xd9, 0x52, 0x53, 0x12, 0xbb, 0xa1, 0x2e, 0xf9, 0x7f, 0x70, 0x99, 0x65,
+	0x38, 0xe1, 0x51, 0x8e, 0x2d, 0xde, 0x05, 0x24, 0x18, 0x66, 0xd7, 0x9e, 0xe4, 0x18, 0x65, 0x96,
+	0x79, 0x05, 0x8c, 0x3c, 0x5e, 0x22, 0x6e, 0xbd, 0x82, 0xda, 0x6a, 0xbf, 0xdb, 0x7b, 0x84, 0xf1,
+	0x79, 0x4d, 0x9d, 0xe7, 0x70, 0xc5, 0x7c, 0x87, 0x53, 0xdd, 0xbd, 0xa4, 0xb9, 0x3b, 0x4d, 0x6a,
+	0xeb, 0xf1, 0xc2, 0xc2, 0x9a, 0x17, 0x70, 0xe5, 0xb3, 0x1b, 0x5d, 0xd4, 0xd9, 0x03, 0x72, 0x48,
+	0x68, 0xfd, 0x77, 0x80, 0xe5, 0xfa, 0x13, 0x12, 0xf6, 0x08, 0x63, 0xd9, 0x8e, 0x2a, 0xc7, 0xed,
+	0x28, 0xf3, 0x9b, 0x02, 0x34, 0x37, 0xfd, 0xf6, 0x31, 0x3d, 0x5b, 0x3e, 0x49, 0xf2, 0xd6, 0x7f,
+	0xed, 0x21, 0x9b, 0x85, 0x31, 0x96, 0x8c, 0x10, 0x57, 0xa4, 0xdc, 0xa3, 0x74, 0xb8, 0xe1, 0xd2,
+	0x66, 0x91, 0xdb, 0x0f, 0x58, 0x00, 0x17, 0x52, 0xc5, 0x63, 0xf3, 0x13, 0x98, 0xd2, 0x25, 0x13,
+	0x46, 0xbb, 0x05, 0x75, 0xfc, 0xba, 0x47, 0x38, 0x15, 0x3f, 0x7f, 0x3c, 0xe7, 0xab, 0x25, 0x60,
+	0x7a, 0x04, 0x4d, 0x0c, 0xd3, 0xcf, 0xbc, 0xce, 0xbf, 0x5b, 0x39, 0xb3, 0x05, 0x33, 0xe9, 0x65,
+	0x84, 0xab, 0x5d, 0x01, 0x63, 0x93, 0x84, 0x11, 0xd5, 0x02, 0xbb, 0x12, 0x1b, 0xb7, 0x43, 0x7e,
+	0x5b, 0x84, 0xb9, 0x5c, 0xb4, 0xd0, 0xf3, 0xa7, 0xd0, 0xe8, 0x30, 0x94, 0xed, 0x4b, 0x9c, 0x88,
+	0xf2, 0xf7, 0x95, 0x28, 0x3f, 0x84, 0xc3, 0x92, 0x0e, 0xb7, 0xea, 0x1d, 0x9d, 0xce, 0xf8, 0x4d,
+	0x01, 0x6a, 0x3a, 0xcd, 0x7f, 0x6a, 0xdf, 0x73, 0xf6, 0xb0, 0x9c, 0xbb, 0x87, 0x3f, 0x86, 0xd9,
+	0x4d, 0xda, 0x24, 0xcc, 0x89, 0x0a, 0x17, 0x10, 0x35, 0xee, 0x3b, 0x16, 0xd5, 0xbe, 0xa3, 0x01,
+	0xad, 0x2c, 0x6f, 0xb1, 0x75, 0x7f, 0x2a, 0x40, 0x9d, 0x5e, 0xa0, 0x3b, 0xe1, 0x7e, 0xec, 0x36,
+	0x08, 0xca, 0xbd, 0x70, 0x3f, 0x92, 0x59, 0x1e, 0xfd, 0x1e, 0x52, 0x6e, 0x7e, 0xcf, 0x2c, 0x66,
+	0xc8, 0x85, 0x99, 0xcd, 0x29, 0x46, 0x2e, 0x96, 0x53, 0x7c, 0x0e, 0x8d, 0x44, 0xa7, 0x24, 0x75,
+	0xcd, 0x28, 0x75, 0x0d, 0x2a, 0xe2, 0x7a, 0x4f, 0x76, 0x76, 0xc4, 0x9a, 0xe0, 0x30, 0xbe, 0xb3,
+	0x22, 0x92, 0x94, 0x92, 0x48, 0xb2, 0x0e, 0x75, 0x7a, 0x69, 0xa8, 0x06, 0x3b, 0x2b, 0xb0, 0xca,
+	0xb5, 0x8b, 0xc9, 0xda, 0xe6, 0x13, 0x68, 0x24, 0x6c, 0x86, 0xc8, 0x78, 0x1d, 0xaa, 0xea, 0x25,
+	0x23, 0x6f, 0x97, 0x8a, 0x72, 0xb7, 0x84, 0xe6, 0x5b, 0xd0, 0x7c, 0x44, 0x63, 0x1f, 0xf9, 0x0a,
+	0x9f, 0xb1, 0x91, 0xe6, 0x11, 0x4c, 0xe9, 0xa4, 0x43, 0xd6, 0x36, 0x60, 0xbc, 0xed, 0x77, 0x7b,
+	0x1d, 0x1c, 0xf1, 0xf4, 0x7b, 0xdc, 0x8a, 0xc7, 0xe9, 0x38, 0x5d, 0xca, 0xc4, 0x69, 0x7a, 0xe9,
+	0x23, 0xaa, 0xe2, 0x53, 0x1c, 0x86, 0xce, 0x21, 0xbe, 0x48, 0x63, 0x43, 0xb4, 0x4b, 0x8a, 0x5a,
+	0xbb, 0x84, 0x62, 0xba, 0x9c, 0x97, 0x38, 0x65, 0x72, 0x48, 0x93, 0x9b, 0x7d, 0xd2, 0x7b, 0x7f,
+	0x79, 0x59, 0xd6, 0xfb, 0x7c, 0x14, 0x2b, 0x35, 0xa2, 0x18, 0xa0, 0x0d, 0x4d, 0x4d, 0x2a, 0xa1,
+	0x3f, 0xad, 0xa0, 0xc9, 0xa1, 0xc7, 0xb2, 0x65, 0x21, 0x55, 0x02, 0x18, 0x6a, 0x09, 0xb9, 0x48,
+	0x49, 0xb7, 0xf2, 0x73, 0x1c, 0x90, 0x83, 0xd3, 0x94, 0xf2, 0x03, 0x7b, 0x41, 0xaa, 0x72, 0x45,
+	0x5d, 0x39, 0x4d, 0xb2, 0x52, 0x4a, 0x32, 0xf3, 0x5d, 0x98, 0x4e, 0xad, 0x94, 0x34, 0x0a, 0x5e,
+	0x39, 0x1d, 0xe2, 0xb2, 0x85, 0xc6, 0x2d, 0x3e, 0x30, 0xaf, 0xc1, 0x82, 0x12, 0x06, 0xb6, 0xfc,
+	0x88, 0x1c, 0x90, 0xb6, 0xa3, 0x56, 0xfa, 0xe6, 0xb7, 0x45, 0x58, 0x1c, 0x4c, 0x23, 0xb8, 0xff,
+	0x2f, 0xd4, 0x9d, 0x28, 0x72, 0xda, 0x47, 0xd8, 0xe5, 0x05, 0xf8, 0x99, 0xf5, 0x6e, 0x4d, 0xd2,
+	0x33, 0x68, 0x48, 0x43, 0xa3, 0x8b, 0x75, 0x0e, 0xd4, 0xb5, 0x2b, 0x56, 0xcd, 0xc5, 0x1a, 0xe1,
+	0xa0, 0xaa, 0xb8, 0xf4, 0x7d, 0xab, 0x62, 0x5a, 0x41, 0xe5, 0x70, 0x64, 0x91, 0x15, 0xf3, 0xb7,
+	0x93, 0x8a, 0xd5, 0xca, 0x4e, 0x7c, 0xcc, 0xf0, 0xe6, 0xcf, 0x0b, 0x70, 0x75, 0xb7, 0x87, 0xbd,
+	0xc8, 0xc3, 0x61, 0x98, 0x67, 0xc1, 0x21, 0x75, 0xe1, 0x1d, 0x98, 0xf4, 0x7c, 0xdb, 0xa3, 0x93,
+	0x4e, 0xed, 0xbe, 0x17, 0x52, 0x36, 0xc2, 0xa1, 0xea, 0x9e, 0xcf, 0x98, 0x9d, 0x3e, 0xe3, 0x60,
+	0xda, 0xc7, 0x4b, 0x68, 0x39, 0x25, 0x7f, 0x67, 0xaa, 0x4a, 0x4a, 0x26, 0x85, 0xf9, 0x75, 0x11,
+	0xe6, 0x07, 0xc9, 0x73, 0xf1, 0xfc, 0xeb, 0x1c, 0xb7, 0xdd, 0x13, 0x18, 0x63, 0xbd, 0x25, 0xcc,
+	0xdf, 0x4a, 0xf5, 0x6a, 0x6c, 0xb8, 0x24, 0x0c, 0xed, 0xe2, 0xc0, 0x92, 0x1c, 0x8c, 0x67, 0x30,
+	0x26, 0x60, 0x17, 0x91, 0x72, 0x01, 0x26, 0x88, 0x97, 0x16, 0x12, 0x92, 0xe4, 0xde, 0xbc, 0x0a,
+	0x73, 0xf2, 0x59, 0x27, 0xcf, 0xc7, 0xff, 0x56, 0x80, 0x2b, 0xf9, 0xf8, 0x0b, 0x35, 0x04, 0xcf,
+	0xd3, 0x2b, 0xcf, 0x7f, 0xdc, 0x28, 0x5d, 0xe8, 0x71, 0xa3, 0x7c, 0xa1, 0xc7, 0x8d, 0x91, 0xfc,
+	0xc7, 0x0d, 0xf3, 0xef, 0x05, 0x68, 0xae, 0x05, 0xd8, 0x89, 0xb0, 0xde, 0x0d, 0x7a, 0x1b, 0x26,
+	0x45, 0x3f, 0x36, 0x13, 0x98, 0x1b, 0x1c, 0xa1, 0x74, 0x5c, 0xde, 0x05, 0x24, 0xbb, 0xcb, 0x99,
+	0xe6, 0xcc, 0xa4, 0xc0, 0xec, 0x68, 0x57, 0x5f, 0x88, 0xb1, 0x2b, 0x83, 0x23, 0xfd, 0xa6, 0x9b,
+	0xc7, 0xfd, 0x85, 0xdb, 0x8c, 0xbf, 0x91, 0x02, 0x07, 0x31, 0x93, 0x19, 0x30, 0xde, 0xf5, 0x70,
+	0xd7, 0xf7, 0x48, 0x5b, 0x3c, 0xae, 0xc7, 0x63, 0xf4, 0x1e, 0x34, 0xe5, 0xb7, 0x2a, 0xc0, 0x28,
+	0xe3, 0x8f, 0x24, 0x4a, 0xe9, 0xfd, 0xcc, 0xc0, 0x94, 0xae, 0xb4, 0xc8, 0x7c, 0x1c, 0x98, 0xdc,
+	0xee, 0x61, 0xef, 0x07, 0x98, 0x22, 0xa5, 0x47, 0x31, 0xad, 0x87, 0x39, 0x05, 0x48, 0x5d, 0x42,
+	0x2c, 0x7c, 0x1f, 0xd0, 0x5a, 0xc7, 0x0f, 0x53, 0x9b, 0x90, 0x62, 0x56, 0xc8, 0x30, 0x9b, 0x86,
+	0xa6, 0x36, 0x4d, 0x70, 0xfb, 0x00, 0x9a, 0x1c, 0xb2, 0xfe, 0x9a, 0x84, 0x51, 0x78, 0x6e, 0x76,
+	0x4b, 0x30, 0xa5, 0xcf, 0x13, 0x8e, 0x3f, 0x03, 0xa3, 0x98, 0x41, 0xc4, 0xbd, 0x21, 0x46, 0x54,
+	0x17, 0x9a, 0x82, 0xf3, 0x39, 0xf1, 0x39, 0xfa, 0x19, 0x2d, 0xab, 0x54, 0x70, 0xfc, 0xda, 0x39,
+	0xc6, 0xd7, 0x92, 0xd7, 0xc2, 0x8d, 0x54, 0x2a, 0x9f, 0x9a, 0xb0, 0x24, 0xb4, 0x92, 0xb3, 0x8c,
+	0x7b, 0x30, 0xca, 0x41, 0xd4, 0x83, 0x14, 0x15, 0xd8, 0x37, 0x15, 0xb2, 0xe3, 0x3b, 0x2e, 0x76,
+	0x45, 0xec, 0x14, 0x23, 0xf3, 0xdb, 0x02, 0xb4, 0x76, 0x23, 0x27, 0x88, 0xd6, 0x28, 0x53, 0x2f,
+	0xec, 0x87, 0x56, 0xaf, 0x2d, 0x4d, 0x72, 0x0b, 0xea, 0xe2, 0x11, 0xd9, 0xd6, 0xef, 0xe0, 0x9a,
+	0x00, 0x8b, 0xe7, 0x04, 0xea, 0x7e, 0xfd, 0x10, 0x07, 0xca, 0xa6, 0xc6, 0x63, 0x8a, 0xa3, 0x9e,
+	0x71, 0xe2, 0x07, 0xd2, 0xa7, 0xe3, 0x31, 0x4d, 0x89, 0xda, 0x38, 0x10, 0xd1, 0x04, 0x8b, 0x66,
+	0x8a, 0x0a, 0x32, 0xe7, 0xe0, 0x72, 0x8e, 0x78, 0xdc, 0x04, 0x77, 0x5e, 0xc0, 0x84, 0xf2, 0xa0,
+	0x81, 0xea, 0x30, 0xb1, 0xf3, 0x6c, 0xf5, 0xc9, 0xfa, 0x8f, 0xec, 0xc7, 0x2b, 0xbb, 0x8f, 0x1b,
+	0x6f, 0xa0, 0x59, 0x68, 0xbe, 0xd8, 0xd8, 0xdb, 0x5a, 0xdf, 0xdd, 0xb5, 0x55, 0x44, 0x01, 0xcd,
+	0x83, 0xb1, 0xb5, 0xbe, 0xbb, 0xb7, 0xfe, 0xd0, 0xce, 0xc3, 0x17, 0xef, 0x7c, 0x01, 0x55, 0x2d,
+	0x5d, 0x46, 0x93, 0x50, 0xdd, 0x5c, 0xb1, 0x3e, 0x5d, 0xdf, 0xdd, 0xb3, 0x1f, 0x6d, 0x58, 0xbb,
+	0x7b, 0xe2, 0x45, 0xcb, 0x5a, 0xd9, 0x5a, 0x7b, 0x6c, 0xaf, 0x6c, 0x3d, 0xb4, 0x57, 0xb7, 0x9f,
+	0x6d, 0x3d, 0x6c, 0x14, 0x50, 0x03, 0x2a, 0xdb, 0x9b, 0x0f, 0x13, 0xba, 0x22, 0x42, 0x50, 0xb3,
+	0x56, 0xb6, 0x1e, 0x6e, 0x3f, 0xb5, 0x37, 0x9e, 0xee, 0x58, 0xdb, 0xcf, 0xd7, 0x1b, 0xa5, 0x65,
+	0x2b, 0xfe, 0x39, 0x67, 0x17, 0x07, 0xaf, 0x48, 0x9b, 0x66, 0x07, 0x63, 0x02, 0x82, 0x2e, 0x2b,
+	0x1b, 0xaf, 0xff, 0xc2, 0x63, 0x18, 0x79, 0x28, 0x6e, 0x8c, 0xe5, 0xdf, 0x35, 0xa1, 0xca, 0x1d,
+	0x40, 0xf2, 0xfc, 0x6f, 0x28, 0xd3, 0xff, 0x07, 0x90, 0xfa, 0x00, 0xa4, 0xfc, 0x5f, 0x60, 0xcc,
+	0x66, 0xe0, 0x71, 0xaa, 0x32, 0x26, 0xfe, 0x13, 0xd0, 0x84, 0xd1, 0x7f, 0x3e, 0x30, 0x8c, 0x3c,
+	0x94, 0xe0, 0x60, 0x41, 0x55, 0xfb, 0x47, 0x00, 0x2d, 0x64, 0x9f, 0xee, 0xb5, 0x1f, 0x0f, 0x8c,
+	0xc5, 0xc1, 0x04, 0x82, 0xe7, 0x1a, 0x8c, 0xaf, 0xc8, 0xa7, 0x7d, 0x23, 0xf7, 0x4f, 0x00, 0xce,
+	0x69, 0x6e, 0xc8, 0x5f, 0x02, 0x54, 0x35, 0xf9, 0x86, 0xae, 0xaa, 0xa6, 0xbf, 0x51, 0x19, 0x46,
+	0x1e, 0x4a, 0x70, 0xf8, 0x0c, 0xea, 0xa9, 0x57, 0x0d, 0x74, 0x4d, 0x21, 0xcf, 0x7f, 0x0c, 0x32,
+	0xcc, 0x61, 0x24, 0x82, 0xf3, 0xff, 0x43, 0x45, 0x7d, 0x43, 0x40, 0xf3, 0x9a, 0x14, 0x99, 0x37,
+	0x09, 0x63, 0x61, 0x20, 0x9e, 0x33, 0xbc, 0x5b, 0x40, 0x7d, 0x68, 0x0d, 0x4a, 0x4c, 0xd1, 0x9d,
+	0xfc, 0x3c, 0x30, 0xef, 0xf6, 0x37, 0xde, 0x3e, 0x17, 0x6d, 0xbc, 0xac, 0x0f, 0x33, 0xf9, 0x59,
+	0x0d, 0xba, 0x7d, 0x8e, 0xc4, 0x87, 0x2f, 0xf9, 0xd6, 0xb9, 0x53, 0xa4, 0xbb, 0x05, 0x44, 0x92,
+	0xdf, 0x59, 0xb4, 0xe5, 0x6e, 0xe6, 0x78, 0x55, 0xde, 0x62, 0xb7, 0xce, 0xa4, 0x8b, 0x97, 0xfa,
+	0x1c, 0x1a, 0xe9, 0x97, 0x0f, 0x64, 0x9e, 0xfd, 0x50, 0x63, 0x5c, 0x1f, 0x4a, 0x93, 0x9c, 0x1b,
+	0xed, 0xcf, 0x04, 0xed, 0xdc, 0xe4, 0xfd, 0x0d, 0x61, 0x2c, 0x0e, 0x26, 0x10, 0x3c, 0x37, 0x61,
+	0x42, 0xf9, 0x7f, 0x00, 0x5d, 0x4d, 0xbf, 0xe8, 0xeb, 0xfc, 0xe6, 0x07, 0xa1, 0x53, 0xdc, 0x44,
+	0xe4, 0xbf, 0x3a, 0xf4, 0xff, 0x00, 0x63, 0x7e, 0x10, 0x5a, 0x70, 0xfb, 0x1c, 0x1a, 0xe9, 0x97,
+	0x73, 0xcd, 0x98, 0x03, 0xde, 0xfa, 0x8d, 0xeb, 0x43, 0x69, 0x12, 0x63, 0x6a, 0x6f, 0xd7, 0x9a,
+	0x31, 0xf3, 0xde, 0xe4, 0x8d, 0xc5, 0xc1, 0x04, 0xc9, 0xe9, 0x4f, 0xbd, 0x9e, 0x68, 0xa7, 0x3f,
+	0xff, 0xd9, 0xcb, 0x30, 0x87, 0x91, 0x24, 0x9c, 0x53, 0xad, 0x75, 0x8d, 0x73, 0xfe, 0x63, 0x80,
+	0x61, 0x0e, 0x23, 0x11, 0x9c, 0x1d, 0x40, 0xd9, 0xae, 0x37, 0x52, 0xff, 0x57, 0x1c, 0xd8, 0x60,
+	0x37, 0x6e, 0x9c, 0x41, 0xa5, 0x84, 0x55, 0xde, 0xc1, 0xd6, 0xc3, 0xaa, 0xd6, 0x4e, 0x37, 0x8c,
+	0x3c, 0x94, 0xe0, 0xb0, 0x0d, 0x15, 0xb5, 0xa7, 0xab, 0x05, 0xbf, 0x9c, 0x36, 0xb4, 0xb1, 0x30,
+	0x10, 0x2f, 0x18, 0x3e, 0x83, 0x9a, 0xde, 0x7c, 0x45, 0xea, 0xee, 0xe6, 0xb6, 0x7f, 0x8d, 0x6b,
+	0x43, 0x28, 0x04, 0x5b, 0x97, 0xa7, 0x6f, 0xa9, 0xc6, 0x2a, 0xba, 0x71, 0x56, 0xe3, 0x95, 0x2f,
+	0x70, 0xf3, 0x7c, 0xfd, 0x59, 0x7a, 0x2e, 0xd2, 0x0d, 0x48, 0xed, 0x5c, 0x0c, 0xe8, 0x7c, 0x1a,
+	0xd7, 0x87, 0xd2, 0x24, 0x17, 0xa9, 0x6c, 0xf6, 0x69, 0x17, 0x69, 0xaa, 0xab, 0x69, 0xcc, 0xe5,
+	0xe2, 0x12, 0x26, 0xb2, 0x1b, 0xa7, 0x31, 0x49, 0x75, 0xfa, 0x8c, 0xb9, 0x5c, 0x5c, 0xb2, 0xe9,
+	0x6a, 0x6b, 0x4d, 0xdb, 0xf4, 0x9c, 0xf6, 0x9c, 0xb1, 0x30, 0x10, 0x9f, 0x44, 0x27, 0xa5, 0x55,
+	0xa5, 0x45, 0xa7, 0x6c, 0x63, 0xcd, 0x98, 0x1f, 0x84, 0x4e, 0x02, 0x88, 0xd6, 0x29, 0xd2, 0x02,
+	0x48, 0x5e, 0xb7, 0xca, 0x58, 0x1c, 0x4c, 0x20, 0xd2, 0xb4, 0x7f, 0x94, 0x64, 0xf9, 0xb1, 0x49,
+	0x33, 0xf0, 0x40, 0x26, 0x6b, 0xdb, 0x50, 0x51, 0xab, 0x0b, 0xcd, 0x14, 0x39, 0xe5, 0x8a, 0xb1,
+	0x30, 0x10, 0x9f, 0xd8, 0x56, 0xad, 0xe2, 0x34, 0x86, 0x39, 0x35, 0xad, 0xb1, 0x30, 0x10, 0x2f,
+	0x18, 0x6e, 0x00, 0x24, 0xb5, 0x19, 0xba, 0xa2, 0x90, 0x67, 0xaa, 0x42, 0xe3, 0xea, 0x00, 0x6c,
+	0xb2, 0x4d, 0x4a, 0x65, 0xa6, 0x6d, 0x53, 0xb6, 0xd0, 0x33, 0xe6, 0x07, 0xa1, 0x05, 0xb7, 0x2f,
+	0x60, 0x32, 0x53, 0x23, 0x20, 0xf5, 0x24, 0x0c, 0x2a, 0x70, 0x8c, 0x37, 0x87, 0x13, 0x25, 0xd2,
+	0x2a, 0x05, 0x98, 0x26, 0x6d, 0xb6, 0xc0, 0x33, 0xe6, 0x07, 0xa1, 0x39, 0xb7, 0xfd, 0x51, 0xf6,
+	0xab, 0xfe, 0xfb, 0xff, 0x1c, 0x00, 0xfa, 0x2d, 0xa8, 0xc3, 0xb7, 0x2f, 0x00, 0x00,
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/btcsuite/btcwallet/internal/zero"
 	"github.com/btcsuite/golangcrypto/nacl/secretbox"
 	"github.com/btcsuite/golangcrypto/scrypt"
+	"golang.org/x/crypto/argon2"
 )
 
 var (
@@ -27,6 +28,7 @@ var (
 	ErrInvalidPassword = errors.New("invalid password")
 	ErrMalformed       = errors.New("malformed data")
 	ErrDecryptFailed   = errors.New("unable to decrypt")
+	ErrUnknownKDF      = errors.New("unknown key derivation function")
 )
 
 // Various constants needed for encryption scheme.
@@ -38,8 +40,35 @@ const (
 	DefaultN  = 16384 // 2^14
 	DefaultR  = 8
 	DefaultP  = 1
+
+	// Default Argon2id parameters.  The memory is in KiB.
+	DefaultArgon2idTime    = 3
+	DefaultArgon2idMemory  = 64 * 1024
+	DefaultArgon2idThreads = 4
 )
 
+// KDF identifies the key derivation function used to derive a secret key from
+// a passphrase.
+type KDF uint8
+
+// Supported key derivation functions.
+const (
+	KDFScrypt KDF = iota
+	KDFArgon2id
+)
+
+// String returns the name of the key derivation function.
+func (kdf KDF) String() string {
+	switch kdf {
+	case KDFScrypt:
+		return "scrypt"
+	case KDFArgon2id:
+		return "argon2id"
+	default:
+		return "unknown"
+	}
+}
+
 // CryptoKey represents a secret key which can be used to encrypt and decrypt
 // data.
 type CryptoKey [KeySize]byte
@@ -93,15 +122,30 @@ func GenerateCryptoKey() (*CryptoKey, error) {
 	return &key, nil
 }
 
-// Parameters are not secret and can be stored in plain text.
+// Parameters are not secret and can be stored in plain text.  N, R and P are
+// the scrypt parameters, while Time, Memory and Threads are the Argon2id
+// parameters.  Only the parameters of the KDF are used.
 type Parameters struct {
-	Salt   [KeySize]byte
-	Digest [sha256.Size]byte
-	N      int
-	R      int
-	P      int
+	Salt    [KeySize]byte
+	Digest  [sha256.Size]byte
+	KDF     KDF
+	N       int
+	R       int
+	P       int
+	Time    uint32
+	Memory  uint32
+	Threads uint8
 }
 
+// Sizes of the marshalled parameters.  Scrypt parameters are marshalled in the
+// original format without a KDF field, so keys derived with scrypt remain
+// readable by older versions, while the other KDFs are marshalled with a KDF
+// field following the digest.
+const (
+	scryptParamsSize   = KeySize + sha256.Size + 24
+	argon2idParamsSize = KeySize + sha256.Size + 1 + 9
+)
+
 // SecretKey houses a crypto key and the parameters needed to derive it from a
 // passphrase.  It should only be used in memory.
 type SecretKey struct {
@@ -111,23 +155,36 @@ type SecretKey struct {
 
 // deriveKey fills out the Key field.
 func (sk *SecretKey) deriveKey(password *[]byte) error {
-	key, err := scrypt.Key(*password, sk.Parameters.Salt[:],
-		sk.Parameters.N,
-		sk.Parameters.R,
-		sk.Parameters.P,
-		len(sk.Key))
-	if err != nil {
-		return err
+	params := &sk.Parameters
+	var key []byte
+	switch params.KDF {
+	case KDFScrypt:
+		var err error
+		key, err = scrypt.Key(*password, params.Salt[:], params.N,
+			params.R, params.P, len(sk.Key))
+		if err != nil {
+			return err
+		}
+	case KDFArgon2id:
+		// argon2 panics on invalid parameters instead of returning an
+		// error.
+		if params.Time == 0 || params.Threads == 0 {
+			return ErrMalformed
+		}
+		key = argon2.IDKey(*password, params.Salt[:], params.Time,
+			params.Memory, params.Threads, uint32(len(sk.Key)))
+	default:
+		return ErrUnknownKDF
 	}
 	copy(sk.Key[:], key)
 	zero.Bytes(key)
 
-	// I'm not a fan of forced garbage collections, but scrypt allocates a
-	// ton of memory and calling it back to back without a GC cycle in
-	// between means you end up needing twice the amount of memory.  For
-	// example, if your scrypt parameters are such that you require 1GB and
-	// you call it twice in a row, without this you end up allocating 2GB
-	// since the first GB probably hasn't been released yet.
+	// I'm not a fan of forced garbage collections, but scrypt and argon2
+	// allocate a ton of memory and calling them back to back without a GC
+	// cycle in between means you end up needing twice the amount of
+	// memory.  For example, if your scrypt parameters are such that you
+	// require 1GB and you call it twice in a row, without this you end up
+	// allocating 2GB since the first GB probably hasn't been released yet.
 	debug.FreeOSMemory()
 
 	return nil
@@ -138,11 +195,35 @@ func (sk *SecretKey) deriveKey(password *[]byte) error {
 func (sk *SecretKey) Marshal() []byte {
 	params := &sk.Parameters
 
-	// The marshalled format for the the params is as follows:
+	if params.KDF == KDFArgon2id {
+		// The marshalled format for Argon2id params is as follows:
+		//   <salt><digest><KDF><time><memory><threads>
+		//
+		// KeySize + sha256.Size + KDF (1 byte) + time (4 bytes) +
+		// memory (4 bytes) + threads (1 byte)
+		marshalled := make([]byte, argon2idParamsSize)
+
+		b := marshalled
+		copy(b[:KeySize], params.Salt[:])
+		b = b[KeySize:]
+		copy(b[:sha256.Size], params.Digest[:])
+		b = b[sha256.Size:]
+		b[0] = byte(params.KDF)
+		b = b[1:]
+		binary.LittleEndian.PutUint32(b[:4], params.Time)
+		b = b[4:]
+		binary.LittleEndian.PutUint32(b[:4], params.Memory)
+		b = b[4:]
+		b[0] = params.Threads
+
+		return marshalled
+	}
+
+	// The marshalled format for the the scrypt params is as follows:
 	//   <salt><digest><N><R><P>
 	//
 	// KeySize + sha256.Size + N (8 bytes) + R (8 bytes) + P (8 bytes)
-	marshalled := make([]byte, KeySize+sha256.Size+24)
+	marshalled := make([]byte, scryptParamsSize)
 
 	b := marshalled
 	copy(b[:KeySize], params.Salt[:])
@@ -165,11 +246,10 @@ func (sk *SecretKey) Unmarshal(marshalled []byte) error {
 		sk.Key = (*CryptoKey)(&[KeySize]byte{})
 	}
 
-	// The marshalled format for the the params is as follows:
-	//   <salt><digest><N><R><P>
-	//
-	// KeySize + sha256.Size + N (8 bytes) + R (8 bytes) + P (8 bytes)
-	if len(marshalled) != KeySize+sha256.Size+24 {
+	// The marshalled formats are described in Marshal.  Scrypt params
+	// have no KDF field and are identified by their length.
+	if len(marshalled) != scryptParamsSize &&
+		len(marshalled) <= KeySize+sha256.Size {
 		return ErrMalformed
 	}
 
@@ -178,6 +258,28 @@ func (sk *SecretKey) Unmarshal(marshalled []byte) error {
 	marshalled = marshalled[KeySize:]
 	copy(params.Digest[:], marshalled[:sha256.Size])
 	marshalled = marshalled[sha256.Size:]
+
+	if len(marshalled) != 24 {
+		params.KDF = KDF(marshalled[0])
+		marshalled = marshalled[1:]
+		if params.KDF != KDFArgon2id {
+			return ErrUnknownKDF
+		}
+		if len(marshalled) != 9 {
+			return ErrMalformed
+		}
+		params.Time = binary.LittleEndian.Uint32(marshalled[:4])
+		marshalled = marshalled[4:]
+		params.Memory = binary.LittleEndian.Uint32(marshalled[:4])
+		marshalled = marshalled[4:]
+		params.Threads = marshalled[0]
+		if params.Time == 0 || params.Threads == 0 {
+			return ErrMalformed
+		}
+		return nil
+	}
+
+	params.KDF = KDFScrypt
 	params.N = int(binary.LittleEndian.Uint64(marshalled[:8]))
 	marshalled = marshalled[8:]
 	params.R = int(binary.LittleEndian.Uint64(marshalled[:8]))
@@ -221,15 +323,34 @@ func (sk *SecretKey) Decrypt(in []byte) ([]byte, error) {
 	return sk.Key.Decrypt(in)
 }
 
-// NewSecretKey returns a SecretKey structure based on the passed parameters.
+// NewSecretKey returns a SecretKey structure derived with scrypt using the
+// passed parameters.
 func NewSecretKey(password *[]byte, N, r, p int) (*SecretKey, error) {
+	return newSecretKey(password, Parameters{KDF: KDFScrypt, N: N, R: r, P: p})
+}
+
+// NewArgon2idSecretKey returns a SecretKey structure derived with Argon2id
+// using the passed parameters.  The memory is in KiB.
+func NewArgon2idSecretKey(password *[]byte, time, memory uint32, threads uint8) (*SecretKey, error) {
+	if time == 0 || threads == 0 {
+		return nil, errors.New("argon2id time and threads must be " +
+			"non-zero")
+	}
+	return newSecretKey(password, Parameters{
+		KDF:     KDFArgon2id,
+		Time:    time,
+		Memory:  memory,
+		Threads: threads,
+	})
+}
+
+// newSecretKey returns a SecretKey structure derived using the KDF and its
+// parameters in params, with a new random salt.
+func newSecretKey(password *[]byte, params Parameters) (*SecretKey, error) {
 	sk := SecretKey{
-		Key: (*CryptoKey)(&[KeySize]byte{}),
+		Key:        (*CryptoKey)(&[KeySize]byte{}),
+		Parameters: params,
 	}
-	// setup parameters
-	sk.Parameters.N = N
-	sk.Parameters.R = r
-	sk.Parameters.P = p
 	_, err := io.ReadFull(prng, sk.Parameters.Salt[:])
 	if err != nil {
 		return nil, err
@@ -114,3 +114,56 @@ func TestDeriveKeyInvalid(t *testing.T) {
 		t.Errorf("unexpected DeriveKey key failure: %v", err)
 	}
 }
+
+func TestArgon2idSecretKey(t *testing.T) {
+	argonKey, err := NewArgon2idSecretKey(&password, 1, 64, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	marshalled := argonKey.Marshal()
+	scryptKey, err := NewSecretKey(&password, 16, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scryptParams := scryptKey.Marshal()
+	if len(marshalled) == len(scryptParams) {
+		t.Fatalf("argon2id params marshalled with the scrypt length")
+	}
+
+	var sk SecretKey
+	if err := sk.Unmarshal(marshalled); err != nil {
+		t.Fatalf("unexpected unmarshal error: %v", err)
+	}
+	if sk.Parameters != argonKey.Parameters {
+		t.Errorf("unmarshalled params %+v, want %+v", sk.Parameters,
+			argonKey.Parameters)
+	}
+	if err := sk.DeriveKey(&password); err != nil {
+		t.Fatalf("unexpected DeriveKey error: %v", err)
+	}
+	if !bytes.Equal(sk.Key[:], argonKey.Key[:]) {
+		t.Errorf("keys not equal")
+	}
+	bogusPass := []byte("bogus")
+	if err := sk.DeriveKey(&bogusPass); err != ErrInvalidPassword {
+		t.Errorf("wrong password didn't fail")
+	}
+
+	// Scrypt params keep their original format without a KDF field.
+	if err := sk.Unmarshal(scryptParams); err != nil {
+		t.Fatalf("unexpected unmarshal error: %v", err)
+	}
+	if sk.Parameters.KDF != KDFScrypt {
+		t.Errorf("scrypt params unmarshalled with KDF %v",
+			sk.Parameters.KDF)
+	}
+
+	unknown := append([]byte(nil), marshalled...)
+	unknown[KeySize+32] = 0xff
+	if err := sk.Unmarshal(unknown); err != ErrUnknownKDF {
+		t.Errorf("unknown KDF unmarshalled with error %v", err)
+	}
+	if err := sk.Unmarshal(marshalled[:len(marshalled)-1]); err != ErrMalformed {
+		t.Errorf("truncated params unmarshalled with error %v", err)
+	}
+}
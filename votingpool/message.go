@@ -0,0 +1,139 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package votingpool
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/wallet/bip322"
+	"github.com/btcsuite/btcwallet/wallet/psbt"
+)
+
+// SignDepositMessage signs a message for the deposit address of the given
+// series, branch and index using the generic signed message format of BIP0322.
+// The message is signed with every private key of the series which has been
+// loaded with EmpowerSeries.  When packet is nil a new signature is started.
+// Otherwise, packet must be the unfinished signature of the message returned
+// by another signer, and this pool's signatures are added to it.
+//
+// The encoded signature is returned once the series' required number of
+// signatures has been reached.  Until then, the PSBT of the message is
+// returned instead so it can be passed on to the remaining signers.
+func (p *Pool) SignDepositMessage(seriesID uint32, branch Branch, index Index,
+	message []byte, packet *psbt.Packet) (sig []byte, partial *psbt.Packet, err error) {
+
+	script, err := p.DepositScript(seriesID, branch, index)
+	if err != nil {
+		return nil, nil, err
+	}
+	addr, err := p.addressFor(script)
+	if err != nil {
+		return nil, nil, newError(ErrKeyChain, "failed to create deposit address", err)
+	}
+	challenge, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return nil, nil, newError(ErrKeyChain, "failed to create deposit pkScript", err)
+	}
+	if packet == nil {
+		packet, err = bip322.NewPacket(message, challenge)
+	} else {
+		err = bip322.CheckPacket(packet, message, challenge)
+	}
+	if err != nil {
+		return nil, nil, newError(ErrTxSigning, "invalid message PSBT", err)
+	}
+	packet.Inputs[0].RedeemScript = script
+
+	secrets, err := p.depositSecrets(seriesID, branch, index)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(secrets.keys) == 0 {
+		str := fmt.Sprintf("series #%d is not empowered", seriesID)
+		return nil, nil, newError(ErrTxSigning, str, nil)
+	}
+	_, err = psbt.Sign(packet, secrets)
+	if err != nil {
+		return nil, nil, newError(ErrRawSigning, "failed to sign message", err)
+	}
+
+	sig, err = bip322.Finalize(packet)
+	switch err {
+	case nil:
+		return sig, nil, nil
+	case psbt.ErrNotFinalizable:
+		return nil, packet, nil
+	default:
+		return nil, nil, newError(ErrTxSigning, "failed to finalize message signature", err)
+	}
+}
+
+// depositSecrets returns the child private keys of the series used by the
+// deposit script of the given branch and index, for the series' private keys
+// which have been loaded.
+func (p *Pool) depositSecrets(seriesID uint32, branch Branch, index Index) (*depositSecrets, error) {
+	series := p.Series(seriesID)
+	if series == nil {
+		str := fmt.Sprintf("series #%d does not exist", seriesID)
+		return nil, newError(ErrSeriesNotExists, str, nil)
+	}
+	pubKeys, err := branchOrder(series.publicKeys, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := &depositSecrets{
+		keys:   make(map[string]*btcec.PrivateKey),
+		params: p.manager.ChainParams(),
+	}
+	for _, pubKey := range pubKeys {
+		privKey, err := series.getPrivKeyFor(pubKey)
+		if err != nil {
+			return nil, err
+		}
+		if privKey == nil {
+			continue
+		}
+		childKey, err := privKey.Child(uint32(index))
+		if err != nil {
+			return nil, newError(ErrKeyChain, "failed to derive private key", err)
+		}
+		ecPrivKey, err := childKey.ECPrivKey()
+		if err != nil {
+			return nil, newError(ErrKeyChain, "failed to obtain ECPrivKey", err)
+		}
+		pkh := btcutil.Hash160(ecPrivKey.PubKey().SerializeCompressed())
+		secrets.keys[string(pkh)] = ecPrivKey
+	}
+	return secrets, nil
+}
+
+// depositSecrets is a txauthor.SecretsSource of the private keys of a deposit
+// script, keyed by the hash160 of their compressed public keys.  The redeem
+// script is always recorded by the PSBT, so no scripts are looked up.
+type depositSecrets struct {
+	keys   map[string]*btcec.PrivateKey
+	params *chaincfg.Params
+}
+
+func (s *depositSecrets) GetKey(addr btcutil.Address) (*btcec.PrivateKey, bool, error) {
+	privKey, ok := s.keys[string(addr.ScriptAddress())]
+	if !ok {
+		return nil, false, newError(ErrUnknownPubKey, "unknown public key", nil)
+	}
+	return privKey, true, nil
+}
+
+func (s *depositSecrets) GetScript(addr btcutil.Address) ([]byte, error) {
+	return nil, newError(ErrTxSigning, "no redeem script for address", nil)
+}
+
+func (s *depositSecrets) ChainParams() *chaincfg.Params {
+	return s.params
+}
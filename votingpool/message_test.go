@@ -0,0 +1,77 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package votingpool_test
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/txscript"
+	vp "github.com/btcsuite/btcwallet/votingpool"
+	"github.com/btcsuite/btcwallet/wallet/bip322"
+)
+
+func TestSignDepositMessage(t *testing.T) {
+	tearDown, mgr, pool := vp.TstCreatePool(t)
+	defer tearDown()
+
+	seriesID := uint32(1)
+	if err := pool.CreateSeries(1, seriesID, 2, vp.TstPubKeys[0:3]); err != nil {
+		t.Fatalf("Failed to create series: %v", err)
+	}
+	branch, index := vp.Branch(1), vp.Index(5)
+	addr, err := pool.DepositScriptAddress(seriesID, branch, index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	challenge, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := []byte("deposit message")
+
+	// A series without private keys can not sign.
+	_, _, err = pool.SignDepositMessage(seriesID, branch, index, message, nil)
+	vp.TstCheckError(t, "SignDepositMessage", err, vp.ErrTxSigning)
+
+	// With one of the two required keys, the signature is incomplete.
+	vp.TstRunWithManagerUnlocked(t, mgr, func() {
+		if err := pool.EmpowerSeries(seriesID, vp.TstPrivKeys[0]); err != nil {
+			t.Fatalf("Failed to empower series: %v", err)
+		}
+	})
+	sig, partial, err := pool.SignDepositMessage(seriesID, branch, index, message, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sig != nil || partial == nil {
+		t.Fatalf("Signed with one of two required keys")
+	}
+
+	// The partial signature is completed with the second key.
+	vp.TstRunWithManagerUnlocked(t, mgr, func() {
+		if err := pool.EmpowerSeries(seriesID, vp.TstPrivKeys[1]); err != nil {
+			t.Fatalf("Failed to empower series: %v", err)
+		}
+	})
+	sig, partial, err = pool.SignDepositMessage(seriesID, branch, index, message, partial)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sig == nil || partial != nil {
+		t.Fatalf("Signature incomplete with two required keys")
+	}
+	valid, err := bip322.Verify(sig, message, challenge)
+	if err != nil || !valid {
+		t.Errorf("Signature not valid (err %v)", err)
+	}
+
+	// A PSBT of another message is rejected.
+	other, err := bip322.NewPacket([]byte("other message"), challenge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = pool.SignDepositMessage(seriesID, branch, index, message, other)
+	vp.TstCheckError(t, "SignDepositMessage", err, vp.ErrTxSigning)
+}
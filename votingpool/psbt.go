@@ -0,0 +1,83 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package votingpool
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wallet/psbt"
+)
+
+// AddPsbtSigs adds the raw signatures of the pool's signers to the PSBT of a
+// withdrawal transaction as partial signatures, so the PSBT can be combined
+// with those of other signers and finalized.  Like SignTx, the redeem script
+// of each input is looked up on the address manager if the PSBT does not
+// record it, and it is added to the PSBT.  Empty signatures, for pubkeys
+// whose private keys are not known, are skipped.
+func AddPsbtSigs(p *psbt.Packet, sigs TxSigs, mgr *waddrmgr.Manager) error {
+	if len(sigs) != len(p.Inputs) {
+		str := fmt.Sprintf("got signatures for %d inputs, but PSBT has %d",
+			len(sigs), len(p.Inputs))
+		return newError(ErrTxSigning, str, nil)
+	}
+
+	for idx := range p.Inputs {
+		in := &p.Inputs[idx]
+		if in.RedeemScript == nil {
+			prevOut, err := p.PrevOutput(idx)
+			if err != nil {
+				return newError(ErrTxSigning, "invalid PSBT input", err)
+			}
+			if prevOut == nil {
+				str := fmt.Sprintf("PSBT input %d has no UTXO", idx)
+				return newError(ErrTxSigning, str, nil)
+			}
+			class, addresses, _, err := txscript.ExtractPkScriptAddrs(
+				prevOut.PkScript, mgr.ChainParams())
+			if err != nil {
+				return newError(ErrTxSigning, "unparseable pkScript", err)
+			}
+			if class != txscript.ScriptHashTy {
+				str := fmt.Sprintf("pkScript is not P2SH: %s", class)
+				return newError(ErrTxSigning, str, nil)
+			}
+			redeemScript, err := getRedeemScript(mgr,
+				addresses[0].(*btcutil.AddressScriptHash))
+			if err != nil {
+				return newError(ErrTxSigning, "unable to retrieve redeem script", err)
+			}
+			in.RedeemScript = redeemScript
+		}
+
+		// Raw signatures are ordered like the public keys of the
+		// redeem script.
+		if txscript.GetScriptClass(in.RedeemScript) != txscript.MultiSigTy {
+			return newError(ErrTxSigning, "redeem script is not multi-sig", nil)
+		}
+		pubKeys, err := txscript.PushedData(in.RedeemScript)
+		if err != nil {
+			return newError(ErrTxSigning, "unparseable redeem script", err)
+		}
+		if len(sigs[idx]) != len(pubKeys) {
+			str := fmt.Sprintf("got %d signatures for input %d, but "+
+				"redeem script has %d pubkeys", len(sigs[idx]), idx,
+				len(pubKeys))
+			return newError(ErrTxSigning, str, nil)
+		}
+		for i, sig := range sigs[idx] {
+			if len(sig) == 0 {
+				continue
+			}
+			in.AddPartialSig(&psbt.PartialSig{
+				PubKey:    pubKeys[i],
+				Signature: sig,
+			})
+		}
+	}
+	return nil
+}
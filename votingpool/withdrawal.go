@@ -368,7 +368,7 @@ func (tx *withdrawalTx) toMsgTx() *wire.MsgTx {
 	}
 
 	for _, i := range tx.inputs {
-		msgtx.AddTxIn(wire.NewTxIn(&i.OutPoint, []byte{}))
+		msgtx.AddTxIn(wire.NewTxIn(&i.OutPoint, []byte{}, nil))
 	}
 	return msgtx
 }
@@ -991,10 +991,11 @@ func signMultiSigUTXO(mgr *waddrmgr.Manager, tx *wire.MsgTx, idx int, pkScript [
 }
 
 // validateSigScripts executes the signature script of the tx input with the
-// given index, returning an error if it fails.
+// given index, returning an error if it fails.  The inputs are P2SH multi-sig,
+// which are not witness programs, so the input amount is not required.
 func validateSigScript(msgtx *wire.MsgTx, idx int, pkScript []byte) error {
 	vm, err := txscript.NewEngine(pkScript, msgtx, idx,
-		txscript.StandardVerifyFlags, nil)
+		txscript.StandardVerifyFlags, nil, txscript.NewTxSigHashes(msgtx), 0)
 	if err != nil {
 		return newError(ErrTxSigning, "cannot create script engine", err)
 	}
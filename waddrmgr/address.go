@@ -15,6 +15,45 @@ import (
 	"github.com/btcsuite/btcwallet/internal/zero"
 )
 
+// AddressType represents the various address types waddrmgr is currently able
+// to generate, and maintain.
+//
+// NOTE: These MUST be stable as they are stored with each account in the
+// database.
+type AddressType uint8
+
+const (
+	// PubKeyHash is a regular p2pkh address.
+	PubKeyHash AddressType = 0 // not iota as they need to be stable for db
+
+	// Script represents a raw pay-to-script-hash address.
+	Script AddressType = 1
+
+	// WitnessPubKey represents a p2wkh (pay-to-witness-key-hash) address
+	// type.
+	WitnessPubKey AddressType = 2
+
+	// NestedWitnessPubKey represents a p2wkh output nested within a p2sh
+	// output.  Accounts of this type derive their keys from the BIP0049
+	// purpose rather than the BIP0044 purpose.
+	NestedWitnessPubKey AddressType = 3
+)
+
+// String returns the AddressType as a human-readable name.
+func (t AddressType) String() string {
+	switch t {
+	case PubKeyHash:
+		return "p2pkh"
+	case Script:
+		return "p2sh"
+	case WitnessPubKey:
+		return "p2wpkh"
+	case NestedWitnessPubKey:
+		return "p2sh-p2wpkh"
+	}
+	return fmt.Sprintf("unknown address type (%d)", uint8(t))
+}
+
 // ManagedAddress is an interface that provides acces to information regarding
 // an address managed by an address manager. Concrete implementations of this
 // type may provide further fields to provide information specific to that type
@@ -42,6 +81,11 @@ type ManagedAddress interface {
 
 	// Used returns true if the backing address has been used in a transaction.
 	Used() (bool, error)
+
+	// AddrType returns the address type of the managed address.  This can
+	// be used to quickly discern the address type without further
+	// processing.
+	AddrType() AddressType
 }
 
 // ManagedPubKeyAddress extends ManagedAddress and additionally provides the
@@ -81,7 +125,8 @@ type ManagedScriptAddress interface {
 type managedAddress struct {
 	manager          *Manager
 	account          uint32
-	address          *btcutil.AddressPubKeyHash
+	address          btcutil.Address
+	addrType         AddressType
 	imported         bool
 	internal         bool
 	compressed       bool
@@ -139,7 +184,8 @@ func (a *managedAddress) Account() uint32 {
 }
 
 // Address returns the btcutil.Address which represents the managed address.
-// This will be a pay-to-pubkey-hash address.
+// This will be a pay-to-pubkey-hash or a pay-to-witness-pubkey-hash address
+// depending on the address type.
 //
 // This is part of the ManagedAddress interface implementation.
 func (a *managedAddress) Address() btcutil.Address {
@@ -150,7 +196,14 @@ func (a *managedAddress) Address() btcutil.Address {
 //
 // This is part of the ManagedAddress interface implementation.
 func (a *managedAddress) AddrHash() []byte {
-	return a.address.Hash160()[:]
+	return a.address.ScriptAddress()
+}
+
+// AddrType returns the address type of the managed address.
+//
+// This is part of the ManagedAddress interface implementation.
+func (a *managedAddress) AddrType() AddressType {
+	return a.addrType
 }
 
 // Imported returns true if the address was imported instead of being part of an
@@ -225,6 +278,14 @@ func (a *managedAddress) PrivKey() (*btcec.PrivateKey, error) {
 		return nil, managerError(ErrLocked, errLocked, nil)
 	}
 
+	// Addresses of watch-only accounts have no private keys, even once
+	// the manager is unlocked.
+	if len(a.privKeyEncrypted) == 0 {
+		str := fmt.Sprintf("address %s belongs to a watch-only account",
+			a.address)
+		return nil, managerError(ErrWatchingOnly, str, nil)
+	}
+
 	// Decrypt the key as needed.  Also, make sure it's a copy since the
 	// private key stored in memory can be cleared at any time.  Otherwise
 	// the returned private key could be invalidated from under the caller.
@@ -252,9 +313,11 @@ func (a *managedAddress) ExportPrivKey() (*btcutil.WIF, error) {
 }
 
 // newManagedAddressWithoutPrivKey returns a new managed address based on the
-// passed account, public key, and whether or not the public key should be
-// compressed.
-func newManagedAddressWithoutPrivKey(m *Manager, account uint32, pubKey *btcec.PublicKey, compressed bool) (*managedAddress, error) {
+// passed account, public key, whether or not the public key should be
+// compressed, and the type of address to create from the public key.
+func newManagedAddressWithoutPrivKey(m *Manager, account uint32, pubKey *btcec.PublicKey,
+	compressed bool, addrType AddressType) (*managedAddress, error) {
+
 	// Create a pay-to-pubkey-hash address from the public key.
 	var pubKeyHash []byte
 	if compressed {
@@ -262,7 +325,43 @@ func newManagedAddressWithoutPrivKey(m *Manager, account uint32, pubKey *btcec.P
 	} else {
 		pubKeyHash = btcutil.Hash160(pubKey.SerializeUncompressed())
 	}
-	address, err := btcutil.NewAddressPubKeyHash(pubKeyHash, m.chainParams)
+
+	var address btcutil.Address
+	var err error
+	switch addrType {
+	case PubKeyHash:
+		address, err = btcutil.NewAddressPubKeyHash(pubKeyHash,
+			m.chainParams)
+
+	case WitnessPubKey:
+		// Witness programs are only standard when committing to a
+		// compressed public key.
+		if !compressed {
+			str := "witness addresses require a compressed public key"
+			return nil, managerError(ErrUnsupportedAddressType, str, nil)
+		}
+		address, err = btcutil.NewAddressWitnessPubKeyHash(pubKeyHash,
+			m.chainParams)
+
+	case NestedWitnessPubKey:
+		if !compressed {
+			str := "witness addresses require a compressed public key"
+			return nil, managerError(ErrUnsupportedAddressType, str, nil)
+		}
+
+		// The address commits to the version 0 witness program which
+		// pays to the public key hash.
+		witnessProgram := make([]byte, 0, 22)
+		witnessProgram = append(witnessProgram, 0x00, 0x14)
+		witnessProgram = append(witnessProgram, pubKeyHash...)
+		address, err = btcutil.NewAddressScriptHash(witnessProgram,
+			m.chainParams)
+
+	default:
+		str := fmt.Sprintf("unable to create managed address of type %v",
+			addrType)
+		return nil, managerError(ErrUnsupportedAddressType, str, nil)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -270,6 +369,7 @@ func newManagedAddressWithoutPrivKey(m *Manager, account uint32, pubKey *btcec.P
 	return &managedAddress{
 		manager:          m,
 		address:          address,
+		addrType:         addrType,
 		account:          account,
 		imported:         false,
 		internal:         false,
@@ -281,9 +381,11 @@ func newManagedAddressWithoutPrivKey(m *Manager, account uint32, pubKey *btcec.P
 }
 
 // newManagedAddress returns a new managed address based on the passed account,
-// private key, and whether or not the public key is compressed.  The managed
-// address will have access to the private and public keys.
-func newManagedAddress(m *Manager, account uint32, privKey *btcec.PrivateKey, compressed bool) (*managedAddress, error) {
+// private key, whether or not the public key is compressed, and the address
+// type.  The managed address will have access to the private and public keys.
+func newManagedAddress(m *Manager, account uint32, privKey *btcec.PrivateKey,
+	compressed bool, addrType AddressType) (*managedAddress, error) {
+
 	// Encrypt the private key.
 	//
 	// NOTE: The privKeyBytes here are set into the managed address which
@@ -299,7 +401,7 @@ func newManagedAddress(m *Manager, account uint32, privKey *btcec.PrivateKey, co
 	// and then add the private key to it.
 	ecPubKey := (*btcec.PublicKey)(&privKey.PublicKey)
 	managedAddr, err := newManagedAddressWithoutPrivKey(m, account,
-		ecPubKey, compressed)
+		ecPubKey, compressed, addrType)
 	if err != nil {
 		return nil, err
 	}
@@ -309,11 +411,14 @@ func newManagedAddress(m *Manager, account uint32, privKey *btcec.PrivateKey, co
 	return managedAddr, nil
 }
 
-// newManagedAddressFromExtKey returns a new managed address based on the passed
-// account and extended key.  The managed address will have access to the
-// private and public keys if the provided extended key is private, otherwise it
-// will only have access to the public key.
-func newManagedAddressFromExtKey(m *Manager, account uint32, key *hdkeychain.ExtendedKey) (*managedAddress, error) {
+// newManagedAddressFromExtKey returns a new managed address of the passed
+// address type based on the passed account and extended key.  The managed
+// address will have access to the private and public keys if the provided
+// extended key is private, otherwise it will only have access to the public
+// key.
+func newManagedAddressFromExtKey(m *Manager, account uint32,
+	key *hdkeychain.ExtendedKey, addrType AddressType) (*managedAddress, error) {
+
 	// Create a new managed address based on the public or private key
 	// depending on whether the generated key is private.
 	var managedAddr *managedAddress
@@ -324,7 +429,8 @@ func newManagedAddressFromExtKey(m *Manager, account uint32, key *hdkeychain.Ext
 		}
 
 		// Ensure the temp private key big integer is cleared after use.
-		managedAddr, err = newManagedAddress(m, account, privKey, true)
+		managedAddr, err = newManagedAddress(m, account, privKey, true,
+			addrType)
 		zero.BigInt(privKey.D)
 		if err != nil {
 			return nil, err
@@ -336,7 +442,7 @@ func newManagedAddressFromExtKey(m *Manager, account uint32, key *hdkeychain.Ext
 		}
 
 		managedAddr, err = newManagedAddressWithoutPrivKey(m, account,
-			pubKey, true)
+			pubKey, true, addrType)
 		if err != nil {
 			return nil, err
 		}
@@ -448,6 +554,13 @@ func (a *scriptAddress) Used() (bool, error) {
 	return a.manager.fetchUsed(a.AddrHash())
 }
 
+// AddrType returns the address type of the managed address.
+//
+// This is part of the ManagedAddress interface implementation.
+func (a *scriptAddress) AddrType() AddressType {
+	return Script
+}
+
 // Script returns the script associated with the address.
 //
 // This implements the ScriptAddress interface.
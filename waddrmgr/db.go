@@ -19,7 +19,7 @@ import (
 
 const (
 	// LatestMgrVersion is the most recent manager version.
-	LatestMgrVersion = 4
+	LatestMgrVersion = 5
 )
 
 var (
@@ -93,6 +93,7 @@ type dbBIP0044AccountRow struct {
 	nextExternalIndex uint32
 	nextInternalIndex uint32
 	name              string
+	addrType          AddressType
 }
 
 // dbAddressRow houses common information stored about an address in the
@@ -167,6 +168,13 @@ var (
 	// meta is used to store meta-data about the address manager
 	// e.g. last account number
 	metaBucketName = []byte("meta")
+
+	// blockHashBucketName is used to store the hash of every block the
+	// manager has been synced through, keyed by block height.  Unlike the
+	// recent blocks in the sync bucket, this covers the entire sync range
+	// so the point at which the synced chain forks from the best chain can
+	// be found no matter how deep a reorganization is.
+	blockHashBucketName = []byte("blockhashes")
 	// lastAccountName is used to store the metadata - last account
 	// in the manager
 	lastAccountName = []byte("lastaccount")
@@ -188,10 +196,16 @@ var (
 	coinTypePubKeyName  = []byte("ctpub")
 	watchingOnlyName    = []byte("watchonly")
 
+	// BIP0049 cointype key names (main bucket).  These are optional since
+	// managers created before BIP0049 support only hold BIP0044 keys.
+	bip0049CoinTypePrivKeyName = []byte("ctpriv49")
+	bip0049CoinTypePubKeyName  = []byte("ctpub49")
+
 	// Sync related key names (sync bucket).
-	syncedToName     = []byte("syncedto")
-	startBlockName   = []byte("startblock")
-	recentBlocksName = []byte("recentblocks")
+	syncedToName        = []byte("syncedto")
+	startBlockName      = []byte("startblock")
+	recentBlocksName    = []byte("recentblocks")
+	pendingRollbackName = []byte("pendingrollback")
 
 	// Account related key names (account bucket).
 	acctNumAcctsName = []byte("numaccts")
@@ -308,18 +322,29 @@ func putMasterKeyParams(tx walletdb.Tx, pubParams, privParams []byte) error {
 	return nil
 }
 
-// fetchCoinTypeKeys loads the encrypted cointype keys which are in turn used to
-// derive the extended keys for all accounts.
-func fetchCoinTypeKeys(tx walletdb.Tx) ([]byte, []byte, error) {
+// coinTypeKeyNames returns the key names of the public and private cointype
+// keys for the passed purpose.
+func coinTypeKeyNames(purpose uint32) (pubKeyName, privKeyName []byte) {
+	if purpose == bip0049Purpose {
+		return bip0049CoinTypePubKeyName, bip0049CoinTypePrivKeyName
+	}
+	return coinTypePubKeyName, coinTypePrivKeyName
+}
+
+// fetchCoinTypeKeys loads the encrypted cointype keys for the passed purpose
+// which are in turn used to derive the extended keys for all accounts of that
+// purpose.
+func fetchCoinTypeKeys(tx walletdb.Tx, purpose uint32) ([]byte, []byte, error) {
 	bucket := tx.RootBucket().Bucket(mainBucketName)
+	pubKeyName, privKeyName := coinTypeKeyNames(purpose)
 
-	coinTypePubKeyEnc := bucket.Get(coinTypePubKeyName)
+	coinTypePubKeyEnc := bucket.Get(pubKeyName)
 	if coinTypePubKeyEnc == nil {
 		str := "required encrypted cointype public key not stored in database"
 		return nil, nil, managerError(ErrDatabase, str, nil)
 	}
 
-	coinTypePrivKeyEnc := bucket.Get(coinTypePrivKeyName)
+	coinTypePrivKeyEnc := bucket.Get(privKeyName)
 	if coinTypePrivKeyEnc == nil {
 		str := "required encrypted cointype private key not stored in database"
 		return nil, nil, managerError(ErrDatabase, str, nil)
@@ -327,14 +352,26 @@ func fetchCoinTypeKeys(tx walletdb.Tx) ([]byte, []byte, error) {
 	return coinTypePubKeyEnc, coinTypePrivKeyEnc, nil
 }
 
-// putCoinTypeKeys stores the encrypted cointype keys which are in turn used to
-// derive the extended keys for all accounts.  Either parameter can be nil in which
-// case no value is written for the parameter.
-func putCoinTypeKeys(tx walletdb.Tx, coinTypePubKeyEnc []byte, coinTypePrivKeyEnc []byte) error {
+// existsCoinTypeKeys returns whether the public cointype key for the passed
+// purpose is stored in the database.
+func existsCoinTypeKeys(tx walletdb.Tx, purpose uint32) bool {
+	bucket := tx.RootBucket().Bucket(mainBucketName)
+	pubKeyName, _ := coinTypeKeyNames(purpose)
+	return bucket.Get(pubKeyName) != nil
+}
+
+// putCoinTypeKeys stores the encrypted cointype keys for the passed purpose
+// which are in turn used to derive the extended keys for all accounts of that
+// purpose.  Either parameter can be nil in which case no value is written for
+// the parameter.
+func putCoinTypeKeys(tx walletdb.Tx, purpose uint32, coinTypePubKeyEnc []byte,
+	coinTypePrivKeyEnc []byte) error {
+
 	bucket := tx.RootBucket().Bucket(mainBucketName)
+	pubKeyName, privKeyName := coinTypeKeyNames(purpose)
 
 	if coinTypePubKeyEnc != nil {
-		err := bucket.Put(coinTypePubKeyName, coinTypePubKeyEnc)
+		err := bucket.Put(pubKeyName, coinTypePubKeyEnc)
 		if err != nil {
 			str := "failed to store encrypted cointype public key"
 			return managerError(ErrDatabase, str, err)
@@ -342,7 +379,7 @@ func putCoinTypeKeys(tx walletdb.Tx, coinTypePubKeyEnc []byte, coinTypePrivKeyEn
 	}
 
 	if coinTypePrivKeyEnc != nil {
-		err := bucket.Put(coinTypePrivKeyName, coinTypePrivKeyEnc)
+		err := bucket.Put(privKeyName, coinTypePrivKeyEnc)
 		if err != nil {
 			str := "failed to store encrypted cointype private key"
 			return managerError(ErrDatabase, str, err)
@@ -494,11 +531,16 @@ func serializeAccountRow(row *dbAccountRow) []byte {
 func deserializeBIP0044AccountRow(accountID []byte, row *dbAccountRow) (*dbBIP0044AccountRow, error) {
 	// The serialized BIP0044 account raw data format is:
 	//   <encpubkeylen><encpubkey><encprivkeylen><encprivkey><nextextidx>
-	//   <nextintidx><namelen><name>
+	//   <nextintidx><namelen><name>[<addrtype>]
 	//
 	// 4 bytes encrypted pubkey len + encrypted pubkey + 4 bytes encrypted
 	// privkey len + encrypted privkey + 4 bytes next external index +
-	// 4 bytes next internal index + 4 bytes name len + name
+	// 4 bytes next internal index + 4 bytes name len + name + optional
+	// 1 byte address type
+	//
+	// Accounts written before address types were introduced do not
+	// contain the trailing address type and are pay-to-pubkey-hash
+	// accounts.
 
 	// Given the above, the length of the entry must be at a minimum
 	// the constant value sizes.
@@ -528,6 +570,11 @@ func deserializeBIP0044AccountRow(accountID []byte, row *dbAccountRow) (*dbBIP00
 	nameLen := binary.LittleEndian.Uint32(row.rawData[offset : offset+4])
 	offset += 4
 	retRow.name = string(row.rawData[offset : offset+nameLen])
+	offset += nameLen
+	retRow.addrType = PubKeyHash
+	if uint32(len(row.rawData)) > offset {
+		retRow.addrType = AddressType(row.rawData[offset])
+	}
 
 	return &retRow, nil
 }
@@ -536,18 +583,19 @@ func deserializeBIP0044AccountRow(accountID []byte, row *dbAccountRow) (*dbBIP00
 // for a BIP0044 account.
 func serializeBIP0044AccountRow(encryptedPubKey,
 	encryptedPrivKey []byte, nextExternalIndex, nextInternalIndex uint32,
-	name string) []byte {
+	name string, addrType AddressType) []byte {
 	// The serialized BIP0044 account raw data format is:
 	//   <encpubkeylen><encpubkey><encprivkeylen><encprivkey><nextextidx>
-	//   <nextintidx><namelen><name>
+	//   <nextintidx><namelen><name><addrtype>
 	//
 	// 4 bytes encrypted pubkey len + encrypted pubkey + 4 bytes encrypted
 	// privkey len + encrypted privkey + 4 bytes next external index +
-	// 4 bytes next internal index + 4 bytes name len + name
+	// 4 bytes next internal index + 4 bytes name len + name + 1 byte
+	// address type
 	pubLen := uint32(len(encryptedPubKey))
 	privLen := uint32(len(encryptedPrivKey))
 	nameLen := uint32(len(name))
-	rawData := make([]byte, 20+pubLen+privLen+nameLen)
+	rawData := make([]byte, 21+pubLen+privLen+nameLen)
 	binary.LittleEndian.PutUint32(rawData[0:4], pubLen)
 	copy(rawData[4:4+pubLen], encryptedPubKey)
 	offset := 4 + pubLen
@@ -562,6 +610,8 @@ func serializeBIP0044AccountRow(encryptedPubKey,
 	binary.LittleEndian.PutUint32(rawData[offset:offset+4], nameLen)
 	offset += 4
 	copy(rawData[offset:offset+nameLen], name)
+	offset += nameLen
+	rawData[offset] = byte(addrType)
 	return rawData
 }
 
@@ -742,10 +792,10 @@ func putAccountRow(tx walletdb.Tx, account uint32, row *dbAccountRow) error {
 // putAccountInfo stores the provided account information to the database.
 func putAccountInfo(tx walletdb.Tx, account uint32, encryptedPubKey,
 	encryptedPrivKey []byte, nextExternalIndex, nextInternalIndex uint32,
-	name string) error {
+	name string, addrType AddressType) error {
 
 	rawData := serializeBIP0044AccountRow(encryptedPubKey, encryptedPrivKey,
-		nextExternalIndex, nextInternalIndex, name)
+		nextExternalIndex, nextInternalIndex, name, addrType)
 
 	acctRow := dbAccountRow{
 		acctType: actBIP0044,
@@ -1101,7 +1151,7 @@ func putChainedAddress(tx walletdb.Tx, addressID []byte, account uint32,
 	// Reserialize the account with the updated index and store it.
 	row.rawData = serializeBIP0044AccountRow(arow.pubKeyEncrypted,
 		arow.privKeyEncrypted, nextExternalIndex, nextInternalIndex,
-		arow.name)
+		arow.name, arow.addrType)
 	err = bucket.Put(accountID, serializeAccountRow(row))
 	if err != nil {
 		str := fmt.Sprintf("failed to update next index for "+
@@ -1265,6 +1315,10 @@ func deletePrivateKeys(tx walletdb.Tx) error {
 		str := "failed to delete cointype private key"
 		return managerError(ErrDatabase, str, err)
 	}
+	if err := bucket.Delete(bip0049CoinTypePrivKeyName); err != nil {
+		str := "failed to delete BIP0049 cointype private key"
+		return managerError(ErrDatabase, str, err)
+	}
 
 	// Delete the account extended private key for all accounts.
 	bucket = tx.RootBucket().Bucket(acctBucketName)
@@ -1292,7 +1346,7 @@ func deletePrivateKeys(tx walletdb.Tx) error {
 			row.rawData = serializeBIP0044AccountRow(
 				arow.pubKeyEncrypted, nil,
 				arow.nextExternalIndex, arow.nextInternalIndex,
-				arow.name)
+				arow.name, arow.addrType)
 			err = bucket.Put(k, serializeAccountRow(row))
 			if err != nil {
 				str := "failed to delete account private key"
@@ -1504,6 +1558,127 @@ func putRecentBlocks(tx walletdb.Tx, recentHeight int32, recentHashes []chainhas
 	return nil
 }
 
+// blockHashKey returns the key used to store the hash of the block at the
+// provided height.  Heights are serialized big endian so the keys sort in
+// height order.
+func blockHashKey(height int32) []byte {
+	var key [4]byte
+	binary.BigEndian.PutUint32(key[:], uint32(height))
+	return key[:]
+}
+
+// fetchBlockHash loads the hash of the synced block at the provided height
+// from the database.  It returns nil when no hash is stored for the height.
+func fetchBlockHash(tx walletdb.Tx, height int32) (*chainhash.Hash, error) {
+	bucket := tx.RootBucket().Bucket(blockHashBucketName)
+
+	buf := bucket.Get(blockHashKey(height))
+	if buf == nil {
+		return nil, nil
+	}
+	if len(buf) != chainhash.HashSize {
+		str := fmt.Sprintf("malformed block hash stored in database "+
+			"for height %d", height)
+		return nil, managerError(ErrDatabase, str, nil)
+	}
+
+	var hash chainhash.Hash
+	copy(hash[:], buf)
+	return &hash, nil
+}
+
+// putBlockHash stores the hash of the synced block described by the provided
+// block stamp to the database.
+func putBlockHash(tx walletdb.Tx, bs *BlockStamp) error {
+	bucket := tx.RootBucket().Bucket(blockHashBucketName)
+
+	err := bucket.Put(blockHashKey(bs.Height), bs.Hash[:])
+	if err != nil {
+		str := fmt.Sprintf("failed to store block hash %v", bs.Hash)
+		return managerError(ErrDatabase, str, err)
+	}
+	return nil
+}
+
+// deleteBlockHashes removes the hashes of the synced blocks from height start
+// through end from the database.  Hashes are only recorded for blocks up to
+// the block the manager is synced to, so callers pass the previously synced
+// height as end.  The hashes are deleted by height rather than found with a
+// cursor, since seeking a cursor is expensive for a large bucket of an
+// encrypted database, while removing them only costs a delete per block rolled
+// back.
+func deleteBlockHashes(tx walletdb.Tx, start, end int32) error {
+	bucket := tx.RootBucket().Bucket(blockHashBucketName)
+
+	for height := start; height <= end; height++ {
+		err := bucket.Delete(blockHashKey(height))
+		if err != nil {
+			str := fmt.Sprintf("failed to delete block hash for "+
+				"height %d", height)
+			return managerError(ErrDatabase, str, err)
+		}
+	}
+	return nil
+}
+
+// fetchPendingRollback loads the block stamp of a rollback which has been
+// started but not finished from the database.  It returns nil when there is
+// no pending rollback.
+func fetchPendingRollback(tx walletdb.Tx) (*BlockStamp, error) {
+	bucket := tx.RootBucket().Bucket(syncBucketName)
+
+	// The serialized pending rollback format is:
+	//   <blockheight><blockhash>
+	//
+	// 4 bytes block height + 32 bytes hash length
+	buf := bucket.Get(pendingRollbackName)
+	if buf == nil {
+		return nil, nil
+	}
+	if len(buf) != 36 {
+		str := "malformed pending rollback stored in database"
+		return nil, managerError(ErrDatabase, str, nil)
+	}
+
+	var bs BlockStamp
+	bs.Height = int32(binary.LittleEndian.Uint32(buf[0:4]))
+	copy(bs.Hash[:], buf[4:36])
+	return &bs, nil
+}
+
+// putPendingRollback stores the block stamp of a started rollback to the
+// database.
+func putPendingRollback(tx walletdb.Tx, bs *BlockStamp) error {
+	bucket := tx.RootBucket().Bucket(syncBucketName)
+
+	// The serialized pending rollback format is:
+	//   <blockheight><blockhash>
+	//
+	// 4 bytes block height + 32 bytes hash length
+	buf := make([]byte, 36)
+	binary.LittleEndian.PutUint32(buf[0:4], uint32(bs.Height))
+	copy(buf[4:36], bs.Hash[0:32])
+
+	err := bucket.Put(pendingRollbackName, buf)
+	if err != nil {
+		str := fmt.Sprintf("failed to store pending rollback %v", bs.Hash)
+		return managerError(ErrDatabase, str, err)
+	}
+	return nil
+}
+
+// deletePendingRollback removes the pending rollback from the database.
+func deletePendingRollback(tx walletdb.Tx) error {
+	bucket := tx.RootBucket().Bucket(syncBucketName)
+
+	err := bucket.Delete(pendingRollbackName)
+	if err != nil {
+		str := "failed to delete pending rollback"
+		return managerError(ErrDatabase, str, err)
+	}
+	return nil
+}
+
 // managerExists returns whether or not the manager has already been created
 // in the given database namespace.
 func managerExists(namespace walletdb.Namespace) (bool, error) {
@@ -1581,6 +1756,12 @@ func createManagerNS(namespace walletdb.Namespace) error {
 			return managerError(ErrDatabase, str, err)
 		}
 
+		_, err = rootBucket.CreateBucket(blockHashBucketName)
+		if err != nil {
+			str := "failed to create block hash bucket"
+			return managerError(ErrDatabase, str, err)
+		}
+
 		if err := putLastAccount(tx, DefaultAccountNum); err != nil {
 			return err
 		}
@@ -1720,6 +1901,15 @@ func upgradeManager(namespace walletdb.Namespace, pubPassPhrase []byte, chainPar
 		version = 4
 	}
 
+	if version < 5 {
+		if err := upgradeToVersion5(namespace); err != nil {
+			return err
+		}
+
+		// The manager is now at version 5.
+		version = 5
+	}
+
 	// Ensure the manager is upraded to the latest version.  This check is
 	// to intentionally cause a failure if the manager version is updated
 	// without writing code to handle the upgrade.
@@ -1762,7 +1952,8 @@ func upgradeToVersion3(namespace walletdb.Namespace, seed, privPassPhrase, pubPa
 		}
 
 		// Derive the cointype key according to BIP0044.
-		coinTypeKeyPriv, err := deriveCoinTypeKey(root, chainParams.HDCoinType)
+		coinTypeKeyPriv, err := deriveCoinTypeKey(root, bip0044Purpose,
+			chainParams.HDCoinType)
 		if err != nil {
 			str := "failed to derive cointype extended key"
 			return managerError(ErrKeyChain, str, err)
@@ -1788,7 +1979,8 @@ func upgradeToVersion3(namespace walletdb.Namespace, seed, privPassPhrase, pubPa
 		}
 
 		// Save the encrypted cointype keys to the database.
-		err = putCoinTypeKeys(tx, coinTypePubEnc, coinTypePrivEnc)
+		err = putCoinTypeKeys(tx, bip0044Purpose, coinTypePubEnc,
+			coinTypePrivEnc)
 		if err != nil {
 			return err
 		}
@@ -1943,3 +2135,62 @@ func upgradeToVersion4(namespace walletdb.Namespace, pubPassPhrase []byte) error
 	}
 	return nil
 }
+
+// upgradeToVersion5 upgrades the database from version 4 to version 5.  The
+// blockHashBucketName bucket is introduced to store the hashes of all synced
+// blocks.  It is seeded with the start block, the recent blocks and the block
+// the manager is synced to, and older hashes are filled in as the wallet
+// syncs.
+func upgradeToVersion5(namespace walletdb.Namespace) error {
+	err := namespace.Update(func(tx walletdb.Tx) error {
+		_, err := tx.RootBucket().CreateBucket(blockHashBucketName)
+		if err != nil {
+			str := "failed to create block hash bucket"
+			return managerError(ErrUpgrade, str, err)
+		}
+
+		startBlock, err := fetchStartBlock(tx)
+		if err != nil {
+			return err
+		}
+		err = putBlockHash(tx, startBlock)
+		if err != nil {
+			return err
+		}
+
+		recentHeight, recentHashes, err := fetchRecentBlocks(tx)
+		if err != nil {
+			return err
+		}
+		for i := range recentHashes {
+			bs := BlockStamp{
+				Height: recentHeight - int32(len(recentHashes)-1-i),
+				Hash:   recentHashes[i],
+			}
+			err := putBlockHash(tx, &bs)
+			if err != nil {
+				return err
+			}
+		}
+
+		syncedTo, err := fetchSyncedTo(tx)
+		if err != nil {
+			return err
+		}
+		err = putBlockHash(tx, syncedTo)
+		if err != nil {
+			return err
+		}
+		err = deleteBlockHashes(tx, syncedTo.Height+1, recentHeight)
+		if err != nil {
+			return err
+		}
+
+		// Write new manager version.
+		return putManagerVersion(tx, 5)
+	})
+	if err != nil {
+		return maybeConvertDbError(err)
+	}
+	return nil
+}
@@ -132,31 +132,42 @@ const (
 	// ErrEmptyPassphrase indicates that the private passphrase was refused
 	// due to being empty.
 	ErrEmptyPassphrase
+
+	// ErrUnsupportedAddressType indicates that an address type was
+	// requested which the address manager is unable to derive or
+	// otherwise manage for the account.
+	ErrUnsupportedAddressType
+
+	// ErrBlockNotFound indicates that the hash of a block at the requested
+	// height is not known to the address manager.
+	ErrBlockNotFound
 )
 
 // Map of ErrorCode values back to their constant names for pretty printing.
 var errorCodeStrings = map[ErrorCode]string{
-	ErrDatabase:          "ErrDatabase",
-	ErrUpgrade:           "ErrUpgrade",
-	ErrKeyChain:          "ErrKeyChain",
-	ErrCrypto:            "ErrCrypto",
-	ErrInvalidKeyType:    "ErrInvalidKeyType",
-	ErrNoExist:           "ErrNoExist",
-	ErrAlreadyExists:     "ErrAlreadyExists",
-	ErrCoinTypeTooHigh:   "ErrCoinTypeTooHigh",
-	ErrAccountNumTooHigh: "ErrAccountNumTooHigh",
-	ErrLocked:            "ErrLocked",
-	ErrWatchingOnly:      "ErrWatchingOnly",
-	ErrInvalidAccount:    "ErrInvalidAccount",
-	ErrAddressNotFound:   "ErrAddressNotFound",
-	ErrAccountNotFound:   "ErrAccountNotFound",
-	ErrDuplicateAddress:  "ErrDuplicateAddress",
-	ErrDuplicateAccount:  "ErrDuplicateAccount",
-	ErrTooManyAddresses:  "ErrTooManyAddresses",
-	ErrWrongPassphrase:   "ErrWrongPassphrase",
-	ErrWrongNet:          "ErrWrongNet",
-	ErrCallBackBreak:     "ErrCallBackBreak",
-	ErrEmptyPassphrase:   "ErrEmptyPassphrase",
+	ErrDatabase:               "ErrDatabase",
+	ErrUpgrade:                "ErrUpgrade",
+	ErrKeyChain:               "ErrKeyChain",
+	ErrCrypto:                 "ErrCrypto",
+	ErrInvalidKeyType:         "ErrInvalidKeyType",
+	ErrNoExist:                "ErrNoExist",
+	ErrAlreadyExists:          "ErrAlreadyExists",
+	ErrCoinTypeTooHigh:        "ErrCoinTypeTooHigh",
+	ErrAccountNumTooHigh:      "ErrAccountNumTooHigh",
+	ErrLocked:                 "ErrLocked",
+	ErrWatchingOnly:           "ErrWatchingOnly",
+	ErrInvalidAccount:         "ErrInvalidAccount",
+	ErrAddressNotFound:        "ErrAddressNotFound",
+	ErrAccountNotFound:        "ErrAccountNotFound",
+	ErrDuplicateAddress:       "ErrDuplicateAddress",
+	ErrDuplicateAccount:       "ErrDuplicateAccount",
+	ErrTooManyAddresses:       "ErrTooManyAddresses",
+	ErrWrongPassphrase:        "ErrWrongPassphrase",
+	ErrWrongNet:               "ErrWrongNet",
+	ErrCallBackBreak:          "ErrCallBackBreak",
+	ErrEmptyPassphrase:        "ErrEmptyPassphrase",
+	ErrUnsupportedAddressType: "ErrUnsupportedAddressType",
+	ErrBlockNotFound:          "ErrBlockNotFound",
 }
 
 // String returns the ErrorCode as a human-readable name.
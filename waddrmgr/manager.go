@@ -60,6 +60,18 @@ const (
 	//  m/44'/<coin type>'/<account>'/<branch>/<address index>
 	//
 	// The branch is 0 for external addresses and 1 for internal addresses.
+	//
+	// BIP0049 reuses the same hierarchy under its own purpose for accounts
+	// deriving p2wkh addresses nested within p2sh outputs:
+	//  m/49'/<coin type>'/<account>'/<branch>/<address index>
+
+	// bip0044Purpose is the purpose used by accounts deriving p2pkh and
+	// p2wkh addresses.
+	bip0044Purpose uint32 = 44
+
+	// bip0049Purpose is the purpose used by accounts deriving p2wkh
+	// addresses nested within p2sh outputs.
+	bip0049Purpose uint32 = 49
 
 	// maxCoinType is the maximum allowed coin type used when structuring
 	// the BIP0044 multi-account hierarchy.  This value is based on the
@@ -67,6 +79,10 @@ const (
 	// derivation.
 	maxCoinType = hdkeychain.HardenedKeyStart - 1
 
+	// accountKeyDepth is the depth of account extended keys in the
+	// BIP0044 hierarchy, m/<purpose>'/<coin type>'/<account>'.
+	accountKeyDepth = 3
+
 	// externalBranch is the child number to use when performing BIP0044
 	// style hierarchical deterministic key derivation for the external
 	// branch.
@@ -96,9 +112,20 @@ func isReservedAccountNum(acct uint32) bool {
 }
 
 // ScryptOptions is used to hold the scrypt parameters needed when deriving new
-// passphrase keys.
+// passphrase keys.  When Argon2id is set, new passphrase keys are instead
+// derived with Argon2id using its parameters.
 type ScryptOptions struct {
 	N, R, P int
+
+	Argon2id *Argon2idOptions
+}
+
+// Argon2idOptions is used to hold the Argon2id parameters needed when deriving
+// new passphrase keys.  The memory is in KiB.
+type Argon2idOptions struct {
+	Time    uint32
+	Memory  uint32
+	Threads uint8
 }
 
 // OpenCallbacks houses caller-provided callbacks that may be called when
@@ -123,6 +150,13 @@ var DefaultScryptOptions = ScryptOptions{
 	P: 1,
 }
 
+// DefaultArgon2idOptions is the default options used with Argon2id.
+var DefaultArgon2idOptions = Argon2idOptions{
+	Time:    snacl.DefaultArgon2idTime,
+	Memory:  snacl.DefaultArgon2idMemory,
+	Threads: snacl.DefaultArgon2idThreads,
+}
+
 // addrKey is used to uniquely identify an address even when those addresses
 // would end up being the same bitcoin address (as is the case for pay-to-pubkey
 // and pay-to-pubkey-hash style of addresses).
@@ -136,9 +170,15 @@ type addrKey string
 type accountInfo struct {
 	acctName string
 
+	// addrType is the type of address derived for both the internal and
+	// external branches of the account.
+	addrType AddressType
+
 	// The account key is used to derive the branches which in turn derive
 	// the internal and external addresses.
 	// The accountKeyPriv will be nil when the address manager is locked.
+	// Watch-only accounts imported from an extended public key have no
+	// encrypted private key, and their accountKeyPriv is always nil.
 	acctKeyEncrypted []byte
 	acctKeyPriv      *hdkeychain.ExtendedKey
 	acctKeyPub       *hdkeychain.ExtendedKey
@@ -154,6 +194,12 @@ type accountInfo struct {
 	lastInternalAddr  ManagedAddress
 }
 
+// watchOnly returns whether the account was imported from an extended public
+// key, and so has no private keys.
+func (a *accountInfo) watchOnly() bool {
+	return len(a.acctKeyEncrypted) == 0
+}
+
 // AccountProperties contains properties associated with each account, such as
 // the account name, number, and the nubmer of derived and imported keys.
 type AccountProperties struct {
@@ -162,6 +208,26 @@ type AccountProperties struct {
 	ExternalKeyCount uint32
 	InternalKeyCount uint32
 	ImportedKeyCount uint32
+	AddressType      AddressType
+	WatchOnly        bool
+}
+
+// DerivationPath describes the BIP0044 path from the master key of the manager
+// to a key derived by one of its accounts:
+//  m/<purpose>'/<coin type>'/<account>'/<branch>/<address index>
+type DerivationPath struct {
+	Purpose  uint32
+	CoinType uint32
+	Account  uint32
+	Branch   uint32
+	Index    uint32
+}
+
+// String returns the path in the notation of BIP0032, with hardened children
+// marked by an apostrophe.
+func (p DerivationPath) String() string {
+	return fmt.Sprintf("m/%d'/%d'/%d'/%d/%d", p.Purpose, p.CoinType,
+		p.Account, p.Branch, p.Index)
 }
 
 // unlockDeriveInfo houses the information needed to derive a private key for a
@@ -175,6 +241,10 @@ type unlockDeriveInfo struct {
 
 // defaultNewSecretKey returns a new secret key.  See newSecretKey.
 func defaultNewSecretKey(passphrase *[]byte, config *ScryptOptions) (*snacl.SecretKey, error) {
+	if a := config.Argon2id; a != nil {
+		return snacl.NewArgon2idSecretKey(passphrase, a.Time, a.Memory,
+			a.Threads)
+	}
 	return snacl.NewSecretKey(passphrase, config.N, config.R, config.P)
 }
 
@@ -382,16 +452,19 @@ func (m *Manager) Close() {
 }
 
 // keyToManaged returns a new managed address for the provided derived key and
-// its derivation path which consists of the account, branch, and index.
+// its derivation path which consists of the account, branch, and index.  The
+// address type of the returned address is determined by addrType.
 //
 // The passed derivedKey is zeroed after the new address is created.
 //
 // This function MUST be called with the manager lock held for writes.
-func (m *Manager) keyToManaged(derivedKey *hdkeychain.ExtendedKey, account, branch, index uint32) (ManagedAddress, error) {
+func (m *Manager) keyToManaged(derivedKey *hdkeychain.ExtendedKey, account,
+	branch, index uint32, addrType AddressType) (ManagedAddress, error) {
+
 	// Create a new managed address based on the public or private key
 	// depending on whether the passed key is private.  Also, zero the
 	// key after creating the managed address from it.
-	ma, err := newManagedAddressFromExtKey(m, account, derivedKey)
+	ma, err := newManagedAddressFromExtKey(m, account, derivedKey, addrType)
 	defer derivedKey.Zero()
 	if err != nil {
 		return nil, err
@@ -420,8 +493,9 @@ func (m *Manager) deriveKey(acctInfo *accountInfo, branch, index uint32, private
 	// Choose the public or private extended key based on whether or not
 	// the private flag was specified.  This, in turn, allows for public or
 	// private child derivation.
+	// Watch-only accounts only allow public derivation.
 	acctKey := acctInfo.acctKeyPub
-	if private {
+	if private && !acctInfo.watchOnly() {
 		acctKey = acctInfo.acctKeyPriv
 	}
 
@@ -491,13 +565,14 @@ func (m *Manager) loadAccountInfo(account uint32) (*accountInfo, error) {
 	// of the fields are filled out below.
 	acctInfo := &accountInfo{
 		acctName:          row.name,
+		addrType:          row.addrType,
 		acctKeyEncrypted:  row.privKeyEncrypted,
 		acctKeyPub:        acctKeyPub,
 		nextExternalIndex: row.nextExternalIndex,
 		nextInternalIndex: row.nextInternalIndex,
 	}
 
-	if !m.locked {
+	if !m.locked && !acctInfo.watchOnly() {
 		// Use the crypto private key to decrypt the account private
 		// extended keys.
 		decrypted, err := m.cryptoKeyPriv.Decrypt(acctInfo.acctKeyEncrypted)
@@ -525,7 +600,8 @@ func (m *Manager) loadAccountInfo(account uint32) (*accountInfo, error) {
 	if err != nil {
 		return nil, err
 	}
-	lastExtAddr, err := m.keyToManaged(lastExtKey, account, branch, index)
+	lastExtAddr, err := m.keyToManaged(lastExtKey, account, branch, index,
+		acctInfo.addrType)
 	if err != nil {
 		return nil, err
 	}
@@ -540,7 +616,8 @@ func (m *Manager) loadAccountInfo(account uint32) (*accountInfo, error) {
 	if err != nil {
 		return nil, err
 	}
-	lastIntAddr, err := m.keyToManaged(lastIntKey, account, branch, index)
+	lastIntAddr, err := m.keyToManaged(lastIntKey, account, branch, index,
+		acctInfo.addrType)
 	if err != nil {
 		return nil, err
 	}
@@ -583,8 +660,11 @@ func (m *Manager) AccountProperties(account uint32) (*AccountProperties, error)
 		props.AccountName = acctInfo.acctName
 		props.ExternalKeyCount = acctInfo.nextExternalIndex
 		props.InternalKeyCount = acctInfo.nextInternalIndex
+		props.AddressType = acctInfo.addrType
+		props.WatchOnly = acctInfo.watchOnly()
 	} else {
 		props.AccountName = ImportedAddrAccountName // reserved, nonchangable
+		props.AddressType = PubKeyHash
 
 		// Could be more efficient if this was tracked by the db.
 		var importedKeyCount uint32
@@ -624,13 +704,21 @@ func (m *Manager) deriveKeyFromPath(account, branch, index uint32, private bool)
 //
 // This function MUST be called with the manager lock held for writes.
 func (m *Manager) chainAddressRowToManaged(row *dbChainAddressRow) (ManagedAddress, error) {
-	addressKey, err := m.deriveKeyFromPath(row.account, row.branch,
-		row.index, !m.locked)
+	// Look up the account key information which also determines the type
+	// of address to create.
+	acctInfo, err := m.loadAccountInfo(row.account)
+	if err != nil {
+		return nil, err
+	}
+
+	addressKey, err := m.deriveKey(acctInfo, row.branch, row.index,
+		!m.locked)
 	if err != nil {
 		return nil, err
 	}
 
-	return m.keyToManaged(addressKey, row.account, row.branch, row.index)
+	return m.keyToManaged(addressKey, row.account, row.branch, row.index,
+		acctInfo.addrType)
 }
 
 // importedAddressRowToManaged returns a new managed address based on imported
@@ -651,7 +739,7 @@ func (m *Manager) importedAddressRowToManaged(row *dbImportedAddressRow) (Manage
 
 	compressed := len(pubBytes) == btcec.PubKeyBytesLenCompressed
 	ma, err := newManagedAddressWithoutPrivKey(m, row.account, pubKey,
-		compressed)
+		compressed, PubKeyHash)
 	if err != nil {
 		return nil, err
 	}
@@ -777,12 +865,74 @@ func (m *Manager) AddrAccount(address btcutil.Address) (uint32, error) {
 	return account, nil
 }
 
+// AddrBranchIndex returns the branch and index from which the given chained
+// address is derived by its account.  ErrInvalidAccount is returned for
+// addresses which are not derived from an account key, such as imported
+// addresses.
+func (m *Manager) AddrBranchIndex(address btcutil.Address) (uint32, uint32, error) {
+	var rowInterface interface{}
+	err := m.namespace.View(func(tx walletdb.Tx) error {
+		var err error
+		rowInterface, err = fetchAddress(tx, address.ScriptAddress())
+		return err
+	})
+	if err != nil {
+		return 0, 0, maybeConvertDbError(err)
+	}
+
+	row, ok := rowInterface.(*dbChainAddressRow)
+	if !ok {
+		str := fmt.Sprintf("address %s is not derived from an account "+
+			"key", address)
+		return 0, 0, managerError(ErrInvalidAccount, str, nil)
+	}
+	return row.branch, row.index, nil
+}
+
+// AddrDerivationPath returns the path from the master key of the manager to
+// the key of the given chained address.  ErrInvalidAccount is returned for
+// addresses which are not derived from an account key, such as imported
+// addresses, and ErrWatchingOnly for addresses of watch-only accounts, since
+// their account keys are not derived from the master key.
+func (m *Manager) AddrDerivationPath(address btcutil.Address) (*DerivationPath, error) {
+	branch, index, err := m.AddrBranchIndex(address)
+	if err != nil {
+		return nil, err
+	}
+	account, err := m.AddrAccount(address)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	acctInfo, err := m.loadAccountInfo(account)
+	if err != nil {
+		return nil, err
+	}
+	if acctInfo.watchOnly() {
+		str := fmt.Sprintf("address %s belongs to a watch-only account",
+			address)
+		return nil, managerError(ErrWatchingOnly, str, nil)
+	}
+
+	return &DerivationPath{
+		Purpose:  AccountPurpose(acctInfo.addrType),
+		CoinType: m.chainParams.HDCoinType,
+		Account:  account,
+		Branch:   branch,
+		Index:    index,
+	}, nil
+}
+
 // ChangePassphrase changes either the public or private passphrase to the
 // provided value depending on the private flag.  In order to change the private
 // password, the address manager must not be watching-only.  The new passphrase
-// keys are derived using the scrypt parameters in the options, so changing the
-// passphrase may be used to bump the computational difficulty needed to brute
-// force the passphrase.
+// keys are derived using the scrypt or Argon2id parameters in the options, so
+// changing the passphrase may be used to bump the computational difficulty
+// needed to brute force the passphrase, or to migrate the master key to
+// another key derivation function.
 func (m *Manager) ChangePassphrase(oldPassphrase, newPassphrase []byte, private bool, config *ScryptOptions) error {
 	// No private passphrase to change for a watching-only address manager.
 	if private && m.watchingOnly {
@@ -1145,11 +1295,11 @@ func (m *Manager) ImportPrivateKey(wif *btcutil.WIF, bs *BlockStamp) (ManagedPub
 	var managedAddr *managedAddress
 	if !m.watchingOnly {
 		managedAddr, err = newManagedAddress(m, ImportedAddrAccount,
-			wif.PrivKey, wif.CompressPubKey)
+			wif.PrivKey, wif.CompressPubKey, PubKeyHash)
 	} else {
 		pubKey := (*btcec.PublicKey)(&wif.PrivKey.PublicKey)
 		managedAddr, err = newManagedAddressWithoutPrivKey(m,
-			ImportedAddrAccount, pubKey, wif.CompressPubKey)
+			ImportedAddrAccount, pubKey, wif.CompressPubKey, PubKeyHash)
 	}
 	if err != nil {
 		return nil, err
@@ -1278,6 +1428,20 @@ func (m *Manager) IsLocked() bool {
 	return m.locked
 }
 
+// PassphraseKDF returns the key derivation function used to derive the public
+// or private master key, depending on the private flag, from its passphrase.
+// Master keys are migrated to the KDF of the options passed to
+// ChangePassphrase.
+func (m *Manager) PassphraseKDF(private bool) snacl.KDF {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	if private {
+		return m.masterKeyPriv.Parameters.KDF
+	}
+	return m.masterKeyPub.Parameters.KDF
+}
+
 // Lock performs a best try effort to remove and zero all secret keys associated
 // with the address manager.
 //
@@ -1381,6 +1545,9 @@ func (m *Manager) Unlock(passphrase []byte) error {
 	// Use the crypto private key to decrypt all of the account private
 	// extended keys.
 	for account, acctInfo := range m.acctInfo {
+		if acctInfo.watchOnly() {
+			continue
+		}
 		decrypted, err := m.cryptoKeyPriv.Decrypt(acctInfo.acctKeyEncrypted)
 		if err != nil {
 			m.lock()
@@ -1403,8 +1570,22 @@ func (m *Manager) Unlock(passphrase []byte) error {
 	// Derive any private keys that are pending due to them being created
 	// while the address manager was locked.
 	for _, info := range m.deriveOnUnlock {
-		addressKey, err := m.deriveKeyFromPath(info.managedAddr.account,
-			info.branch, info.index, true)
+		acctInfo, err := m.loadAccountInfo(info.managedAddr.account)
+		if err != nil {
+			m.lock()
+			return err
+		}
+
+		// There are no private keys to derive for the addresses of
+		// watch-only accounts.
+		if acctInfo.watchOnly() {
+			m.deriveOnUnlock[0] = nil
+			m.deriveOnUnlock = m.deriveOnUnlock[1:]
+			continue
+		}
+
+		addressKey, err := m.deriveKey(acctInfo, info.branch,
+			info.index, true)
 		if err != nil {
 			m.lock()
 			return err
@@ -1487,9 +1668,9 @@ func (m *Manager) nextAddresses(account uint32, numAddresses uint32, internal bo
 	}
 
 	// Choose the account key to used based on whether the address manager
-	// is locked.
+	// is locked.  Watch-only accounts always use the public key.
 	acctKey := acctInfo.acctKeyPub
-	if !m.locked {
+	if !m.locked && !acctInfo.watchOnly() {
 		acctKey = acctInfo.acctKeyPriv
 	}
 
@@ -1552,7 +1733,8 @@ func (m *Manager) nextAddresses(account uint32, numAddresses uint32, internal bo
 		// Create a new managed address based on the public or private
 		// key depending on whether the generated key is private.  Also,
 		// zero the next key after creating the managed address from it.
-		managedAddr, err := newManagedAddressFromExtKey(m, account, nextKey)
+		managedAddr, err := newManagedAddressFromExtKey(m, account,
+			nextKey, acctInfo.addrType)
 		nextKey.Zero()
 		if err != nil {
 			return nil, err
@@ -1598,7 +1780,7 @@ func (m *Manager) nextAddresses(account uint32, numAddresses uint32, internal bo
 		// Add the new managed address to the list of addresses that
 		// need their private keys derived when the address manager is
 		// next unlocked.
-		if m.locked && !m.watchingOnly {
+		if m.locked && !m.watchingOnly && !acctInfo.watchOnly() {
 			m.deriveOnUnlock = append(m.deriveOnUnlock, info)
 		}
 
@@ -1730,7 +1912,38 @@ func ValidateAccountName(name string) error {
 // ErrDuplicateAccount will be returned.  Since creating a new account requires
 // access to the cointype keys (from which extended account keys are derived),
 // it requires the manager to be unlocked.
+//
+// The addresses of the new account are pay-to-pubkey-hash addresses.  Use
+// NewAccountWithType to create accounts handing out other address types.
 func (m *Manager) NewAccount(name string) (uint32, error) {
+	return m.NewAccountWithType(name, PubKeyHash)
+}
+
+// isChainedAddressType returns whether addresses of the passed type may be
+// derived from the branches of a BIP0044 account.
+func isChainedAddressType(addrType AddressType) bool {
+	switch addrType {
+	case PubKeyHash, WitnessPubKey, NestedWitnessPubKey:
+		return true
+	}
+	return false
+}
+
+// AccountPurpose returns the BIP0043 purpose under which the keys of an account
+// deriving addresses of the passed type are derived.
+func AccountPurpose(addrType AddressType) uint32 {
+	if addrType == NestedWitnessPubKey {
+		return bip0049Purpose
+	}
+	return bip0044Purpose
+}
+
+// NewAccountWithType creates and returns a new account stored in the manager
+// based on the given account name, deriving all external and internal
+// addresses of the account as the passed address type.  Accounts deriving
+// nested witness addresses use the BIP0049 purpose, while all others use the
+// BIP0044 purpose.  All other behavior matches NewAccount.
+func (m *Manager) NewAccountWithType(name string, addrType AddressType) (uint32, error) {
 	if m.watchingOnly {
 		return 0, managerError(ErrWatchingOnly, errWatchingOnly, nil)
 	}
@@ -1747,6 +1960,14 @@ func (m *Manager) NewAccount(name string) (uint32, error) {
 		return 0, err
 	}
 
+	// Only address types which can be derived from the account branches
+	// may be used.
+	if !isChainedAddressType(addrType) {
+		str := fmt.Sprintf("address type %v is not supported for "+
+			"accounts", addrType)
+		return 0, managerError(ErrUnsupportedAddressType, str, nil)
+	}
+
 	// Check that account with the same name does not exist
 	_, err := m.lookupAccount(name)
 	if err == nil {
@@ -1768,8 +1989,16 @@ func (m *Manager) NewAccount(name string) (uint32, error) {
 		fmt.Printf("1768:account:%d\n",account)
 		account++
 		// Fetch the cointype key which will be used to derive the next account
-		// extended keys
-		_, coinTypePrivEnc, err = fetchCoinTypeKeys(tx)
+		// extended keys.  Managers created before BIP0049 support do not
+		// hold the BIP0049 cointype keys and cannot create such accounts.
+		purpose := AccountPurpose(addrType)
+		if !existsCoinTypeKeys(tx, purpose) {
+			str := fmt.Sprintf("address type %v requires cointype "+
+				"keys for purpose %d which are not stored in "+
+				"the database", addrType, purpose)
+			return managerError(ErrUnsupportedAddressType, str, nil)
+		}
+		_, coinTypePrivEnc, err = fetchCoinTypeKeys(tx, purpose)
 		if err != nil {
 			return err
 		}
@@ -1824,7 +2053,8 @@ func (m *Manager) NewAccount(name string) (uint32, error) {
 
 		// We have the encrypted account extended keys, so save them to the
 		// database
-		err = putAccountInfo(tx, account, acctPubEnc, acctPrivEnc, 0, 0, name)
+		err = putAccountInfo(tx, account, acctPubEnc, acctPrivEnc, 0, 0,
+			name, addrType)
 		if err != nil {
 			return err
 		}
@@ -1838,6 +2068,99 @@ func (m *Manager) NewAccount(name string) (uint32, error) {
 	return account, err
 }
 
+// ImportAccount creates and returns a new watch-only account stored in the
+// manager, deriving the external and internal addresses of the account as the
+// passed address type from an external account-level extended public key,
+// such as the key of an account kept in cold storage.  Addresses of the
+// account may be derived and watched, but there are no private keys for the
+// account, and ErrWatchingOnly is returned when accessing them.  The manager
+// need not be unlocked to import an account.
+//
+// The extended key must be public, for the manager's network, and at the
+// depth of BIP0044 account keys.  If an account with the same name already
+// exists, ErrDuplicateAccount will be returned.
+func (m *Manager) ImportAccount(name string, acctKeyPub *hdkeychain.ExtendedKey,
+	addrType AddressType) (uint32, error) {
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	// Validate account name
+	if err := ValidateAccountName(name); err != nil {
+		return 0, err
+	}
+
+	// Only address types which can be derived from the account branches
+	// may be used.
+	if !isChainedAddressType(addrType) {
+		str := fmt.Sprintf("address type %v is not supported for "+
+			"accounts", addrType)
+		return 0, managerError(ErrUnsupportedAddressType, str, nil)
+	}
+
+	// Ensure the key is an account public key for the manager's network
+	// from which both branches can be derived.
+	if acctKeyPub.IsPrivate() {
+		str := "imported account key must be an extended public key"
+		return 0, managerError(ErrKeyChain, str, nil)
+	}
+	if acctKeyPub.Depth() != accountKeyDepth {
+		str := fmt.Sprintf("imported account key has depth %d, "+
+			"expected account key depth %d", acctKeyPub.Depth(),
+			accountKeyDepth)
+		return 0, managerError(ErrKeyChain, str, nil)
+	}
+	if !acctKeyPub.IsForNet(m.chainParams) {
+		str := fmt.Sprintf("imported account key is not for %s",
+			m.chainParams.Name)
+		return 0, managerError(ErrWrongNet, str, nil)
+	}
+	if err := checkBranchKeys(acctKeyPub); err != nil {
+		str := "failed to derive branches of imported account key"
+		return 0, managerError(ErrKeyChain, str, err)
+	}
+
+	// Check that account with the same name does not exist
+	_, err := m.lookupAccount(name)
+	if err == nil {
+		str := fmt.Sprintf("account with the same name already exists")
+		return 0, managerError(ErrDuplicateAccount, str, err)
+	}
+
+	acctPubEnc, err := m.cryptoKeyPub.Encrypt([]byte(acctKeyPub.String()))
+	if err != nil {
+		str := "failed to  encrypt public key for account"
+		return 0, managerError(ErrCrypto, str, err)
+	}
+
+	var account uint32
+	err = m.namespace.Update(func(tx walletdb.Tx) error {
+		var err error
+		account, err = fetchLastAccount(tx)
+		if err != nil {
+			return err
+		}
+		account++
+		if account > MaxAccountNum {
+			return managerError(ErrAccountNumTooHigh,
+				errAcctTooHigh, nil)
+		}
+
+		// The account is saved without an encrypted private key,
+		// which marks it watch-only.
+		err = putAccountInfo(tx, account, acctPubEnc, nil, 0, 0,
+			name, addrType)
+		if err != nil {
+			return err
+		}
+		return putLastAccount(tx, account)
+	})
+	if err != nil {
+		return 0, maybeConvertDbError(err)
+	}
+	return account, nil
+}
+
 // RenameAccount renames an account stored in the manager based on the
 // given account number with the given name.  If an account with the same name
 // already exists, ErrDuplicateAccount will be returned.
@@ -1884,7 +2207,8 @@ func (m *Manager) RenameAccount(account uint32, name string) error {
 			return err
 		}
 		err = putAccountInfo(tx, account, row.pubKeyEncrypted,
-			row.privKeyEncrypted, row.nextExternalIndex, row.nextInternalIndex, name)
+			row.privKeyEncrypted, row.nextExternalIndex, row.nextInternalIndex,
+			name, row.addrType)
 		return err
 	})
 
@@ -2082,12 +2406,13 @@ func newManager(namespace walletdb.Namespace, chainParams *chaincfg.Params,
 
 // deriveCoinTypeKey derives the cointype key which can be used to derive the
 // extended key for an account according to the hierarchy described by BIP0044
-// given the coin type key.
+// given the coin type key.  The purpose selects between the BIP0044 and
+// BIP0049 hierarchies.
 //
 // In particular this is the hierarchical deterministic extended key path:
-// m/44'/<coin type>'
+// m/<purpose>'/<coin type>'
 func deriveCoinTypeKey(masterNode *hdkeychain.ExtendedKey,
-	coinType uint32) (*hdkeychain.ExtendedKey, error) {
+	purpose, coinType uint32) (*hdkeychain.ExtendedKey, error) {
 	// Enforce maximum coin type.
 	if coinType > maxCoinType {
 		err := managerError(ErrCoinTypeTooHigh, errCoinTypeTooHigh, nil)
@@ -2102,13 +2427,13 @@ func deriveCoinTypeKey(masterNode *hdkeychain.ExtendedKey,
 	// The branch is 0 for external addresses and 1 for internal addresses.
 
 	// Derive the purpose key as a child of the master node.
-	purpose, err := masterNode.Child(44 + hdkeychain.HardenedKeyStart)
+	purposeKey, err := masterNode.Child(purpose + hdkeychain.HardenedKeyStart)
 	if err != nil {
 		return nil, err
 	}
 
 	// Derive the coin type key as a child of the purpose key.
-	coinTypeKey, err := purpose.Child(coinType + hdkeychain.HardenedKeyStart)
+	coinTypeKey, err := purposeKey.Child(coinType + hdkeychain.HardenedKeyStart)
 	if err != nil {
 		return nil, err
 	}
@@ -2342,13 +2667,24 @@ func Create(namespace walletdb.Namespace, seed, pubPassphrase, privPassphrase []
 	}
 
 	// Derive the cointype key according to BIP0044.
-	coinTypeKeyPriv, err := deriveCoinTypeKey(root, chainParams.HDCoinType)
+	coinTypeKeyPriv, err := deriveCoinTypeKey(root, bip0044Purpose,
+		chainParams.HDCoinType)
 	if err != nil {
 		str := "failed to derive cointype extended key"
 		return managerError(ErrKeyChain, str, err)
 	}
 	defer coinTypeKeyPriv.Zero()
 
+	// Derive the cointype key according to BIP0049 for accounts deriving
+	// nested witness addresses.
+	bip0049CoinTypeKeyPriv, err := deriveCoinTypeKey(root, bip0049Purpose,
+		chainParams.HDCoinType)
+	if err != nil {
+		str := "failed to derive BIP0049 cointype extended key"
+		return managerError(ErrKeyChain, str, err)
+	}
+	defer bip0049CoinTypeKeyPriv.Zero()
+
 	// Derive the account key for the first account according to BIP0044.
 	acctKeyPriv, err := deriveAccountKey(coinTypeKeyPriv, 0)
 	if err != nil {
@@ -2462,6 +2798,23 @@ func Create(namespace walletdb.Namespace, seed, pubPassphrase, privPassphrase []
 		str := "failed to encrypt cointype private key"
 		return managerError(ErrCrypto, str, err)
 	}
+	bip0049CoinTypeKeyPub, err := bip0049CoinTypeKeyPriv.Neuter()
+	if err != nil {
+		str := "failed to convert BIP0049 cointype private key"
+		return managerError(ErrKeyChain, str, err)
+	}
+	bip0049CoinTypePubEnc, err := cryptoKeyPub.Encrypt(
+		[]byte(bip0049CoinTypeKeyPub.String()))
+	if err != nil {
+		str := "failed to encrypt BIP0049 cointype public key"
+		return managerError(ErrCrypto, str, err)
+	}
+	bip0049CoinTypePrivEnc, err := cryptoKeyPriv.Encrypt(
+		[]byte(bip0049CoinTypeKeyPriv.String()))
+	if err != nil {
+		str := "failed to encrypt BIP0049 cointype private key"
+		return managerError(ErrCrypto, str, err)
+	}
 
 	// Encrypt the default account keys with the associated crypto keys.
 	acctPubEnc, err := cryptoKeyPub.Encrypt([]byte(acctKeyPub.String()))
@@ -2502,7 +2855,13 @@ func Create(namespace walletdb.Namespace, seed, pubPassphrase, privPassphrase []
 		}
 
 		// Save the encrypted cointype keys to the database.
-		err = putCoinTypeKeys(tx, coinTypePubEnc, coinTypePrivEnc)
+		err = putCoinTypeKeys(tx, bip0044Purpose, coinTypePubEnc,
+			coinTypePrivEnc)
+		if err != nil {
+			return err
+		}
+		err = putCoinTypeKeys(tx, bip0049Purpose, bip0049CoinTypePubEnc,
+			bip0049CoinTypePrivEnc)
 		if err != nil {
 			return err
 		}
@@ -2529,17 +2888,21 @@ func Create(namespace walletdb.Namespace, seed, pubPassphrase, privPassphrase []
 		if err != nil {
 			return err
 		}
+		err = putBlockHash(tx, &syncInfo.syncedTo)
+		if err != nil {
+			return err
+		}
 
 		// Save the information for the imported account to the database.
 		err = putAccountInfo(tx, ImportedAddrAccount, nil,
-			nil, 0, 0, ImportedAddrAccountName)
+			nil, 0, 0, ImportedAddrAccountName, PubKeyHash)
 		if err != nil {
 			return err
 		}
 
 		// Save the information for the default account to the database.
 		err = putAccountInfo(tx, DefaultAccountNum, acctPubEnc,
-			acctPrivEnc, 0, 0, defaultAccountName)
+			acctPrivEnc, 0, 0, defaultAccountName, PubKeyHash)
 		return err
 	})
 	if err != nil {
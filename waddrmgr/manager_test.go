@@ -14,6 +14,8 @@ import (
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/btcsuite/btcwallet/snacl"
 	"github.com/btcsuite/btcwallet/waddrmgr"
 	"github.com/btcsuite/btcwallet/walletdb"
 )
@@ -1592,6 +1594,26 @@ func testSync(tc *testContext) bool {
 		}
 	}
 
+	// Ensure the hashes of all synced blocks are recorded, including those
+	// no longer in the recent history.
+	for height := int32(0); height <= int32(len(tests)); height++ {
+		wantHash := chaincfg.MainNetParams.GenesisHash
+		if height > 0 {
+			wantHash = tests[height-1].hash
+		}
+		gotHash, err := tc.manager.BlockHash(height)
+		if err != nil {
+			tc.t.Errorf("BlockHash unexpected err for height %d: %v",
+				height, err)
+			return false
+		}
+		if *gotHash != *wantHash {
+			tc.t.Errorf("BlockHash unexpected hash for height %d -- "+
+				"got %v, want %v", height, gotHash, wantHash)
+			return false
+		}
+	}
+
 	// Ensure rollback to block in recent history works as expected.
 	blockStamp := waddrmgr.BlockStamp{
 		Height: 10,
@@ -1608,6 +1630,11 @@ func testSync(tc *testContext) bool {
 			"got %v, want %v", gotBlockStamp, blockStamp)
 		return false
 	}
+	_, err := tc.manager.BlockHash(11)
+	if !checkManagerError(tc.t, "BlockHash after rollback", err,
+		waddrmgr.ErrBlockNotFound) {
+		return false
+	}
 
 	// Ensure syncing to a block that is in the future as compared to the
 	// current  block stamp clears the old recent blocks.
@@ -1631,6 +1658,85 @@ func testSync(tc *testContext) bool {
 		return false
 	}
 
+	// Ensure the hashes of blocks skipped over by the future block stamp
+	// are unknown until they are provided.  Hashes of blocks after the
+	// synced block are ignored.
+	_, err = tc.manager.BlockHash(50)
+	if !checkManagerError(tc.t, "BlockHash for skipped block", err,
+		waddrmgr.ErrBlockNotFound) {
+		return false
+	}
+	fillHash := newHash("00000000c7f50b6dfac8b8a59e584f7a1b1bcfe29ca1a2f9f2f8dbc8a1d6ae71")
+	err = tc.manager.PutBlockHashes([]waddrmgr.BlockStamp{
+		{Height: 50, Hash: *fillHash},
+		{Height: 200, Hash: *fillHash},
+	})
+	if err != nil {
+		tc.t.Errorf("PutBlockHashes unexpected err: %v", err)
+		return false
+	}
+	gotHash, err := tc.manager.BlockHash(50)
+	if err != nil {
+		tc.t.Errorf("BlockHash unexpected err for filled height: %v",
+			err)
+		return false
+	}
+	if *gotHash != *fillHash {
+		tc.t.Errorf("BlockHash unexpected hash for filled height -- "+
+			"got %v, want %v", gotHash, fillHash)
+		return false
+	}
+	_, err = tc.manager.BlockHash(200)
+	if !checkManagerError(tc.t, "BlockHash after synced block", err,
+		waddrmgr.ErrBlockNotFound) {
+		return false
+	}
+
+	// Ensure a rollback remains pending until it is finished.
+	blockStamp = waddrmgr.BlockStamp{
+		Height: 10,
+		Hash:   *tests[9].hash,
+	}
+	if err := tc.manager.BeginRollback(&blockStamp); err != nil {
+		tc.t.Errorf("BeginRollback unexpected err: %v", err)
+		return false
+	}
+	gotBlockStamp = tc.manager.SyncedTo()
+	if gotBlockStamp != blockStamp {
+		tc.t.Errorf("SyncedTo unexpected block stamp on BeginRollback "+
+			"-- got %v, want %v", gotBlockStamp, blockStamp)
+		return false
+	}
+	_, err = tc.manager.BlockHash(50)
+	if !checkManagerError(tc.t, "BlockHash after BeginRollback", err,
+		waddrmgr.ErrBlockNotFound) {
+		return false
+	}
+	pending, err := tc.manager.PendingRollback()
+	if err != nil {
+		tc.t.Errorf("PendingRollback unexpected err: %v", err)
+		return false
+	}
+	if pending == nil || *pending != blockStamp {
+		tc.t.Errorf("PendingRollback unexpected block stamp -- got "+
+			"%v, want %v", pending, blockStamp)
+		return false
+	}
+	if err := tc.manager.FinishRollback(); err != nil {
+		tc.t.Errorf("FinishRollback unexpected err: %v", err)
+		return false
+	}
+	pending, err = tc.manager.PendingRollback()
+	if err != nil {
+		tc.t.Errorf("PendingRollback unexpected err: %v", err)
+		return false
+	}
+	if pending != nil {
+		tc.t.Errorf("PendingRollback unexpected block stamp after "+
+			"FinishRollback -- got %v, want nil", pending)
+		return false
+	}
+
 	// Rollback to a block that is not in the recent block history and
 	// ensure it results in only that block.
 	blockStamp = waddrmgr.BlockStamp{
@@ -1870,3 +1976,418 @@ func TestEncryptDecrypt(t *testing.T) {
 		}
 	}
 }
+
+// TestWitnessAccount ensures accounts created with the witness pubkey hash
+// address type derive native segwit addresses on both branches and that
+// unsupported account address types are rejected.
+func TestWitnessAccount(t *testing.T) {
+	teardown, mgr := setupManager(t)
+	defer teardown()
+
+	if err := mgr.Unlock(privPassphrase); err != nil {
+		t.Fatalf("Unlock: unexpected error: %v", err)
+	}
+
+	_, err := mgr.NewAccountWithType("script", waddrmgr.Script)
+	if !checkManagerError(t, "Unsupported address type", err,
+		waddrmgr.ErrUnsupportedAddressType) {
+		return
+	}
+
+	account, err := mgr.NewAccountWithType("witness", waddrmgr.WitnessPubKey)
+	if err != nil {
+		t.Fatalf("NewAccountWithType: unexpected error: %v", err)
+	}
+	props, err := mgr.AccountProperties(account)
+	if err != nil {
+		t.Fatalf("AccountProperties: unexpected error: %v", err)
+	}
+	if props.AddressType != waddrmgr.WitnessPubKey {
+		t.Fatalf("AccountProperties: address type mismatch -- got %v, "+
+			"want %v", props.AddressType, waddrmgr.WitnessPubKey)
+	}
+
+	external, err := mgr.NextExternalAddresses(account, 2)
+	if err != nil {
+		t.Fatalf("NextExternalAddresses: unexpected error: %v", err)
+	}
+	internal, err := mgr.NextInternalAddresses(account, 2)
+	if err != nil {
+		t.Fatalf("NextInternalAddresses: unexpected error: %v", err)
+	}
+	for _, ma := range append(external, internal...) {
+		if ma.AddrType() != waddrmgr.WitnessPubKey {
+			t.Errorf("%v: address type mismatch -- got %v, want %v",
+				ma.Address(), ma.AddrType(), waddrmgr.WitnessPubKey)
+		}
+		if _, ok := ma.Address().(*btcutil.AddressWitnessPubKeyHash); !ok {
+			t.Errorf("%v: unexpected address type %T", ma.Address(),
+				ma.Address())
+		}
+
+		// Ensure the address can be looked up by its encoding.
+		addr, err := btcutil.DecodeAddress(ma.Address().EncodeAddress(),
+			&chaincfg.MainNetParams)
+		if err != nil {
+			t.Fatalf("DecodeAddress: unexpected error: %v", err)
+		}
+		got, err := mgr.Address(addr)
+		if err != nil {
+			t.Fatalf("Address: unexpected error: %v", err)
+		}
+		if got.Account() != account || got.AddrType() != waddrmgr.WitnessPubKey {
+			t.Errorf("Address: got account %d type %v, want account "+
+				"%d type %v", got.Account(), got.AddrType(), account,
+				waddrmgr.WitnessPubKey)
+		}
+	}
+}
+
+// TestNestedWitnessAccount ensures accounts created with the nested witness
+// pubkey hash address type derive p2sh-wrapped witness addresses from the
+// BIP0049 purpose.
+func TestNestedWitnessAccount(t *testing.T) {
+	teardown, mgr := setupManager(t)
+	defer teardown()
+
+	if err := mgr.Unlock(privPassphrase); err != nil {
+		t.Fatalf("Unlock: unexpected error: %v", err)
+	}
+
+	account, err := mgr.NewAccountWithType("nested",
+		waddrmgr.NestedWitnessPubKey)
+	if err != nil {
+		t.Fatalf("NewAccountWithType: unexpected error: %v", err)
+	}
+	addrs, err := mgr.NextExternalAddresses(account, 1)
+	if err != nil {
+		t.Fatalf("NextExternalAddresses: unexpected error: %v", err)
+	}
+	ma := addrs[0]
+	if ma.AddrType() != waddrmgr.NestedWitnessPubKey {
+		t.Fatalf("address type mismatch -- got %v, want %v",
+			ma.AddrType(), waddrmgr.NestedWitnessPubKey)
+	}
+
+	// Derive the expected address for m/49'/0'/<account>'/0/0 directly
+	// from the seed.
+	key, err := hdkeychain.NewMaster(seed, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := []uint32{
+		49 + hdkeychain.HardenedKeyStart,
+		chaincfg.MainNetParams.HDCoinType + hdkeychain.HardenedKeyStart,
+		account + hdkeychain.HardenedKeyStart,
+		0, 0,
+	}
+	for _, i := range path {
+		key, err = key.Child(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	pubKey, err := key.ECPubKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	witnessProgram := append([]byte{0x00, 0x14},
+		btcutil.Hash160(pubKey.SerializeCompressed())...)
+	wantAddr, err := btcutil.NewAddressScriptHash(witnessProgram,
+		&chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ma.Address().String() != wantAddr.String() {
+		t.Fatalf("address mismatch -- got %v, want %v", ma.Address(),
+			wantAddr)
+	}
+
+	// Ensure the address can be looked up by its script hash.
+	got, err := mgr.Address(wantAddr)
+	if err != nil {
+		t.Fatalf("Address: unexpected error: %v", err)
+	}
+	if got.Account() != account {
+		t.Fatalf("Address: account mismatch -- got %d, want %d",
+			got.Account(), account)
+	}
+}
+
+// TestAddrDerivationPath ensures the derivation paths of chained addresses
+// describe the BIP0044 and BIP0049 hierarchies, and that no path is returned
+// for imported addresses.
+func TestAddrDerivationPath(t *testing.T) {
+	teardown, mgr := setupManager(t)
+	defer teardown()
+
+	if err := mgr.Unlock(privPassphrase); err != nil {
+		t.Fatalf("Unlock: unexpected error: %v", err)
+	}
+
+	account, err := mgr.NewAccountWithType("nested",
+		waddrmgr.NestedWitnessPubKey)
+	if err != nil {
+		t.Fatalf("NewAccountWithType: unexpected error: %v", err)
+	}
+	nested, err := mgr.NextInternalAddresses(account, 2)
+	if err != nil {
+		t.Fatalf("NextInternalAddresses: unexpected error: %v", err)
+	}
+	external, err := mgr.NextExternalAddresses(waddrmgr.DefaultAccountNum, 1)
+	if err != nil {
+		t.Fatalf("NextExternalAddresses: unexpected error: %v", err)
+	}
+
+	coinType := chaincfg.MainNetParams.HDCoinType
+	tests := []struct {
+		addr btcutil.Address
+		want waddrmgr.DerivationPath
+	}{
+		{
+			addr: nested[1].Address(),
+			want: waddrmgr.DerivationPath{
+				Purpose:  49,
+				CoinType: coinType,
+				Account:  account,
+				Branch:   1,
+				Index:    1,
+			},
+		},
+		{
+			addr: external[0].Address(),
+			want: waddrmgr.DerivationPath{
+				Purpose:  44,
+				CoinType: coinType,
+				Account:  waddrmgr.DefaultAccountNum,
+				Branch:   0,
+				Index:    0,
+			},
+		},
+	}
+	for _, test := range tests {
+		path, err := mgr.AddrDerivationPath(test.addr)
+		if err != nil {
+			t.Fatalf("AddrDerivationPath: unexpected error: %v", err)
+		}
+		if *path != test.want {
+			t.Errorf("AddrDerivationPath: path mismatch -- got %v, "+
+				"want %v", path, test.want)
+		}
+	}
+	wantStr := fmt.Sprintf("m/49'/%d'/%d'/1/1", coinType, account)
+	if tests[0].want.String() != wantStr {
+		t.Errorf("String: got %v, want %v", tests[0].want.String(),
+			wantStr)
+	}
+
+	script, err := mgr.ImportScript([]byte{0x51}, &waddrmgr.BlockStamp{})
+	if err != nil {
+		t.Fatalf("ImportScript: unexpected error: %v", err)
+	}
+	_, err = mgr.AddrDerivationPath(script.Address())
+	checkManagerError(t, "Imported address", err, waddrmgr.ErrInvalidAccount)
+}
+
+// TestImportAccount ensures watch-only accounts imported from an extended
+// public key derive the addresses of the external account, can be used while
+// the manager is locked or unlocked, and never return private keys.
+func TestImportAccount(t *testing.T) {
+	teardown, mgr := setupManager(t)
+	defer teardown()
+
+	// Derive the account key m/44'/0'/0' of a seed unrelated to the
+	// manager's seed.
+	coldSeed := make([]byte, len(seed))
+	copy(coldSeed, seed)
+	coldSeed[0] ^= 0xff
+	acctKey, err := hdkeychain.NewMaster(coldSeed, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := []uint32{
+		44 + hdkeychain.HardenedKeyStart,
+		chaincfg.MainNetParams.HDCoinType + hdkeychain.HardenedKeyStart,
+		hdkeychain.HardenedKeyStart,
+	}
+	for _, i := range path {
+		acctKey, err = acctKey.Child(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	acctKeyPub, err := acctKey.Neuter()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Private, non-account and other network keys are rejected.
+	_, err = mgr.ImportAccount("cold", acctKey, waddrmgr.PubKeyHash)
+	if !checkManagerError(t, "Private key", err, waddrmgr.ErrKeyChain) {
+		return
+	}
+	branchKey, err := acctKeyPub.Child(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = mgr.ImportAccount("cold", branchKey, waddrmgr.PubKeyHash)
+	if !checkManagerError(t, "Branch key", err, waddrmgr.ErrKeyChain) {
+		return
+	}
+	testNetKey, err := hdkeychain.NewKeyFromString(acctKeyPub.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	testNetKey.SetNet(&chaincfg.TestNet3Params)
+	_, err = mgr.ImportAccount("cold", testNetKey, waddrmgr.PubKeyHash)
+	if !checkManagerError(t, "Wrong net", err, waddrmgr.ErrWrongNet) {
+		return
+	}
+
+	// The account is imported while the manager is locked.
+	account, err := mgr.ImportAccount("cold", acctKeyPub, waddrmgr.PubKeyHash)
+	if err != nil {
+		t.Fatalf("ImportAccount: unexpected error: %v", err)
+	}
+	_, err = mgr.ImportAccount("cold", acctKeyPub, waddrmgr.PubKeyHash)
+	if !checkManagerError(t, "Duplicate name", err,
+		waddrmgr.ErrDuplicateAccount) {
+		return
+	}
+	props, err := mgr.AccountProperties(account)
+	if err != nil {
+		t.Fatalf("AccountProperties: unexpected error: %v", err)
+	}
+	if !props.WatchOnly {
+		t.Fatal("AccountProperties: imported account is not watch-only")
+	}
+
+	wantAddr := func(branch, index uint32) string {
+		key, err := acctKeyPub.Child(branch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key, err = key.Child(index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr, err := key.Address(&chaincfg.MainNetParams)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return addr.EncodeAddress()
+	}
+
+	external, err := mgr.NextExternalAddresses(account, 2)
+	if err != nil {
+		t.Fatalf("NextExternalAddresses: unexpected error: %v", err)
+	}
+	if err := mgr.Unlock(privPassphrase); err != nil {
+		t.Fatalf("Unlock: unexpected error: %v", err)
+	}
+	internal, err := mgr.NextInternalAddresses(account, 1)
+	if err != nil {
+		t.Fatalf("NextInternalAddresses: unexpected error: %v", err)
+	}
+
+	for i, ma := range external {
+		want := wantAddr(0, uint32(i))
+		if ma.Address().EncodeAddress() != want {
+			t.Errorf("external address %d mismatch -- got %v, want %v",
+				i, ma.Address(), want)
+		}
+	}
+	if internal[0].Address().EncodeAddress() != wantAddr(1, 0) {
+		t.Errorf("internal address mismatch -- got %v, want %v",
+			internal[0].Address(), wantAddr(1, 0))
+	}
+	branch, index, err := mgr.AddrBranchIndex(external[1].Address())
+	if err != nil {
+		t.Fatalf("AddrBranchIndex: unexpected error: %v", err)
+	}
+	if branch != 0 || index != 1 {
+		t.Errorf("AddrBranchIndex: got branch %d index %d, want branch "+
+			"0 index 1", branch, index)
+	}
+
+	for _, ma := range append(external, internal...) {
+		_, err := ma.(waddrmgr.ManagedPubKeyAddress).PrivKey()
+		if !checkManagerError(t, "PrivKey", err,
+			waddrmgr.ErrWatchingOnly) {
+			return
+		}
+	}
+}
+
+// TestArgon2idMigration ensures changing a passphrase with Argon2id options
+// migrates a master key derived with scrypt, and that the migrated keys are
+// used after reopening the manager.
+func TestArgon2idMigration(t *testing.T) {
+	t.Parallel()
+
+	dbName := "mgrtestargon2id.bin"
+	_ = os.Remove(dbName)
+	db, mgrNamespace, err := createDbNamespace(dbName)
+	if err != nil {
+		t.Fatalf("createDbNamespace: unexpected error: %v", err)
+	}
+	defer os.Remove(dbName)
+	defer db.Close()
+
+	err = waddrmgr.Create(mgrNamespace, seed, pubPassphrase,
+		privPassphrase, &chaincfg.MainNetParams, fastScrypt)
+	if err != nil {
+		t.Fatalf("Create: unexpected error: %v", err)
+	}
+	mgr, err := waddrmgr.Open(mgrNamespace, pubPassphrase,
+		&chaincfg.MainNetParams, nil)
+	if err != nil {
+		t.Fatalf("Open: unexpected error: %v", err)
+	}
+	if kdf := mgr.PassphraseKDF(true); kdf != snacl.KDFScrypt {
+		t.Errorf("private master key KDF %v, want scrypt", kdf)
+	}
+
+	fastArgon2id := &waddrmgr.ScryptOptions{
+		Argon2id: &waddrmgr.Argon2idOptions{
+			Time:    1,
+			Memory:  64,
+			Threads: 1,
+		},
+	}
+	err = mgr.ChangePassphrase(privPassphrase, privPassphrase2, true,
+		fastArgon2id)
+	if err != nil {
+		t.Fatalf("ChangePassphrase (private): unexpected error: %v", err)
+	}
+	err = mgr.ChangePassphrase(pubPassphrase, pubPassphrase2, false,
+		fastArgon2id)
+	if err != nil {
+		t.Fatalf("ChangePassphrase (public): unexpected error: %v", err)
+	}
+	if kdf := mgr.PassphraseKDF(true); kdf != snacl.KDFArgon2id {
+		t.Errorf("private master key KDF %v, want argon2id", kdf)
+	}
+	if kdf := mgr.PassphraseKDF(false); kdf != snacl.KDFArgon2id {
+		t.Errorf("public master key KDF %v, want argon2id", kdf)
+	}
+	mgr.Close()
+
+	mgr, err = waddrmgr.Open(mgrNamespace, pubPassphrase2,
+		&chaincfg.MainNetParams, nil)
+	if err != nil {
+		t.Fatalf("Open: unexpected error: %v", err)
+	}
+	defer mgr.Close()
+	if kdf := mgr.PassphraseKDF(true); kdf != snacl.KDFArgon2id {
+		t.Errorf("reopened private master key KDF %v, want argon2id", kdf)
+	}
+	err = mgr.Unlock(privPassphrase)
+	if !checkManagerError(t, "Unlock with old passphrase", err,
+		waddrmgr.ErrWrongPassphrase) {
+		return
+	}
+	if err := mgr.Unlock(privPassphrase2); err != nil {
+		t.Fatalf("Unlock: unexpected error: %v", err)
+	}
+}
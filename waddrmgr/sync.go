@@ -5,6 +5,7 @@
 package waddrmgr
 
 import (
+	"fmt"
 	"sync"
 
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
@@ -137,10 +138,24 @@ func (m *Manager) NewIterateRecentBlocks() *BlockIterator {
 // imported addresses will be used.  This effectively allows the manager to be
 // marked as unsynced back to the oldest known point any of the addresses have
 // appeared in the block chain.
+//
+// The hash of the block is also recorded in the full history of synced block
+// hashes, replacing the hashes of any later blocks, which are no longer
+// synced.
 func (m *Manager) SetSyncedTo(bs *BlockStamp) error {
 	m.mtx.Lock()
 	defer m.mtx.Unlock()
 
+	return m.setSyncedTo(bs, nil)
+}
+
+// setSyncedTo marks the address manager to be in sync with the block described
+// by the blockstamp.  When update is not nil, it is called within the same
+// database transaction so that any additional changes are applied atomically
+// with the new sync state.
+//
+// This function MUST be called with the manager lock held for writes.
+func (m *Manager) setSyncedTo(bs *BlockStamp, update func(walletdb.Tx) error) error {
 	// Update the recent history.
 	//
 	// NOTE: The values in the memory sync state aren't directly modified
@@ -148,6 +163,7 @@ func (m *Manager) SetSyncedTo(bs *BlockStamp) error {
 	// is updated with these values as needed after the db updates.
 	recentHeight := m.syncState.recentHeight
 	recentHashes := m.syncState.recentHashes
+	prevHeight := m.syncState.syncedTo.Height
 	if bs == nil {
 		// Use the stored start blockstamp and reset recent hashes and
 		// height when the provided blockstamp is nil.
@@ -205,7 +221,24 @@ func (m *Manager) SetSyncedTo(bs *BlockStamp) error {
 			return err
 		}
 
-		return putRecentBlocks(tx, recentHeight, recentHashes)
+		err = putRecentBlocks(tx, recentHeight, recentHashes)
+		if err != nil {
+			return err
+		}
+
+		err = putBlockHash(tx, bs)
+		if err != nil {
+			return err
+		}
+		err = deleteBlockHashes(tx, bs.Height+1, prevHeight)
+		if err != nil {
+			return err
+		}
+
+		if update != nil {
+			return update(tx)
+		}
+		return nil
 	})
 	if err != nil {
 		return err
@@ -228,3 +261,104 @@ func (m *Manager) SyncedTo() BlockStamp {
 
 	return m.syncState.syncedTo
 }
+
+// BlockHash returns the hash of the synced block at the provided height.  An
+// error with the code ErrBlockNotFound is returned when the hash of the block
+// is not known, either because the height is after the block the manager is
+// synced to, or because the manager was never marked synced to the block and
+// its hash has not been recorded by PutBlockHashes.
+func (m *Manager) BlockHash(height int32) (*chainhash.Hash, error) {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	var hash *chainhash.Hash
+	if height <= m.syncState.syncedTo.Height {
+		err := m.namespace.View(func(tx walletdb.Tx) error {
+			var err error
+			hash, err = fetchBlockHash(tx, height)
+			return err
+		})
+		if err != nil {
+			return nil, maybeConvertDbError(err)
+		}
+	}
+	if hash == nil {
+		str := fmt.Sprintf("no synced block at height %d", height)
+		return nil, managerError(ErrBlockNotFound, str, nil)
+	}
+	return hash, nil
+}
+
+// PutBlockHashes records the hashes of synced blocks in the history of synced
+// block hashes.  This is used to fill in the hashes of blocks the manager was
+// synced through without being marked synced to each of them, such as blocks
+// covered by a rescan, which only reports its progress periodically.  Block
+// stamps after the block the manager is synced to are ignored since those
+// blocks have not been synced.
+func (m *Manager) PutBlockHashes(stamps []BlockStamp) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	err := m.namespace.Update(func(tx walletdb.Tx) error {
+		for i := range stamps {
+			if stamps[i].Height > m.syncState.syncedTo.Height {
+				continue
+			}
+			err := putBlockHash(tx, &stamps[i])
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return maybeConvertDbError(err)
+	}
+	return nil
+}
+
+// BeginRollback marks the address manager to be synced to the block described
+// by the blockstamp, which must be an earlier block of the synced chain, and
+// records the rollback as pending in the same database transaction.  The
+// pending rollback is returned by PendingRollback until FinishRollback is
+// called, allowing callers which must roll back other data to the same block
+// to complete the rollback after a crash or shutdown.
+func (m *Manager) BeginRollback(bs *BlockStamp) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return m.setSyncedTo(bs, func(tx walletdb.Tx) error {
+		return putPendingRollback(tx, bs)
+	})
+}
+
+// PendingRollback returns the block stamp passed to BeginRollback when the
+// rollback has not been finished with FinishRollback, or nil when there is no
+// pending rollback.
+func (m *Manager) PendingRollback() (*BlockStamp, error) {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	var bs *BlockStamp
+	err := m.namespace.View(func(tx walletdb.Tx) error {
+		var err error
+		bs, err = fetchPendingRollback(tx)
+		return err
+	})
+	if err != nil {
+		return nil, maybeConvertDbError(err)
+	}
+	return bs, nil
+}
+
+// FinishRollback removes the pending rollback recorded by BeginRollback.
+func (m *Manager) FinishRollback() error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	err := m.namespace.Update(deletePendingRollback)
+	if err != nil {
+		return maybeConvertDbError(err)
+	}
+	return nil
+}
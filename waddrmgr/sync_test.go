@@ -0,0 +1,88 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package waddrmgr_test
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/walletdb"
+	_ "github.com/btcsuite/btcwallet/walletdb/encrypted"
+)
+
+// benchmarkHash returns a made up block hash for a height.
+func benchmarkHash(height int32) chainhash.Hash {
+	var hash chainhash.Hash
+	binary.LittleEndian.PutUint32(hash[:], uint32(height))
+	return hash
+}
+
+// BenchmarkSetSyncedTo measures marking a manager of an encrypted database
+// synced to the next block once the hashes of many synced blocks have been
+// recorded.
+func BenchmarkSetSyncedTo(b *testing.B) {
+	const syncedBlocks = 100000
+
+	dirName, err := ioutil.TempDir("", "mgrbench")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer os.RemoveAll(dirName)
+	db, err := walletdb.Create("encrypted", "bdb", pubPassphrase,
+		filepath.Join(dirName, "mgrbench.db"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer db.Close()
+	namespace, err := db.Namespace(waddrmgrNamespaceKey)
+	if err != nil {
+		b.Fatal(err)
+	}
+	err = waddrmgr.Create(namespace, seed, pubPassphrase, privPassphrase,
+		&chaincfg.MainNetParams, fastScrypt)
+	if err != nil {
+		b.Fatal(err)
+	}
+	mgr, err := waddrmgr.Open(namespace, pubPassphrase,
+		&chaincfg.MainNetParams, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer mgr.Close()
+
+	bs := waddrmgr.BlockStamp{
+		Hash:   benchmarkHash(syncedBlocks),
+		Height: syncedBlocks,
+	}
+	err = mgr.SetSyncedTo(&bs)
+	if err != nil {
+		b.Fatal(err)
+	}
+	stamps := make([]waddrmgr.BlockStamp, syncedBlocks)
+	for i := range stamps {
+		stamps[i].Height = int32(i)
+		stamps[i].Hash = benchmarkHash(int32(i))
+	}
+	err = mgr.PutBlockHashes(stamps)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bs.Height++
+		bs.Hash = benchmarkHash(bs.Height)
+		err := mgr.SetSyncedTo(&bs)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,441 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/btcsuite/btcwallet/snacl"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+// The encrypted backup format wraps a copy of the wallet database:
+//
+//	<magic><version><snacl parameters><chunk>...
+//
+// The snacl parameters describe the scrypt derivation of the backup key from
+// the backup passphrase.  Each chunk is a big endian uint32 length followed by
+// the sealed chunk, which holds a big endian uint64 sequence number, a byte
+// marking the final chunk, and up to backupChunkSize bytes of the database.
+// The sequence numbers and final marker prevent chunks from being reordered,
+// duplicated, dropped, or truncated without detection, and no data may follow
+// the final chunk.
+const (
+	encryptedBackupVersion = 1
+
+	// backupChunkSize is the maximum number of database bytes sealed in a
+	// single chunk of an encrypted backup.
+	backupChunkSize = 64 * 1024
+
+	// chunkHeaderSize is the size of the sequence number and final marker
+	// sealed with the data of each chunk.
+	chunkHeaderSize = 9
+
+	// maxSealedChunkSize is the maximum size of a sealed chunk, which adds
+	// the nonce and authenticator of snacl to the chunk plaintext.
+	maxSealedChunkSize = backupChunkSize + chunkHeaderSize + 24 + 16
+)
+
+// encryptedBackupMagic begins every encrypted backup.  It can never begin a
+// wallet database, which allows backups to be restored without knowing
+// whether they are encrypted.
+var encryptedBackupMagic = []byte("btcwbkup")
+
+var (
+	// ErrBackupPassphrase describes the error condition of restoring an
+	// encrypted backup without its passphrase, or with an incorrect one.
+	ErrBackupPassphrase = errors.New("missing or incorrect passphrase " +
+		"for encrypted backup")
+
+	// ErrBackupOverwritesWallet describes the error condition of writing a
+	// backup to the database file of a wallet.
+	ErrBackupOverwritesWallet = errors.New("backup destination is a " +
+		"wallet database")
+
+	// ErrMalformedBackup describes the error condition of restoring a
+	// backup which is empty, or an encrypted backup which is corrupt,
+	// truncated, or of an unknown version.
+	ErrMalformedBackup = errors.New("malformed wallet backup")
+)
+
+// Backup writes a copy of the wallet database to out.  The copy is made in a
+// single read transaction, so it is consistent even while the wallet is
+// running.  The copy may be opened as a wallet database as is.
+func (w *Wallet) Backup(out io.Writer) error {
+	return w.db.Copy(out)
+}
+
+// BackupEncrypted writes a copy of the wallet database to out in the
+// encrypted backup format, using a key derived from the passphrase.  The copy
+// is made as by Backup and can be restored with NewBackupReader.
+func (w *Wallet) BackupEncrypted(out io.Writer, passphrase []byte) error {
+	ew, err := NewEncryptedBackupWriter(out, passphrase)
+	if err != nil {
+		return err
+	}
+	err = w.Backup(ew)
+	if err != nil {
+		return err
+	}
+	return ew.Close()
+}
+
+// backupFile writes a backup of the wallet database to the file at dest,
+// encrypting it when the passphrase is not empty.  The backup is written to a
+// temporary file which replaces dest once it is complete, so an existing
+// backup is never left half overwritten.
+func (w *Wallet) backupFile(dest string, passphrase []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(dest), "backup")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if len(passphrase) != 0 {
+		err = w.BackupEncrypted(tmp, passphrase)
+	} else {
+		err = w.Backup(tmp)
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dest)
+}
+
+// encryptedBackupWriter seals the data written to it into the chunks of an
+// encrypted backup.
+type encryptedBackupWriter struct {
+	out    io.Writer
+	key    *snacl.SecretKey
+	buf    []byte
+	seq    uint64
+	closed bool
+}
+
+// NewEncryptedBackupWriter returns a writer which encrypts a wallet database
+// written to it into out, in the encrypted backup format, using a key derived
+// from the passphrase.  The writer must be closed to write the final chunk of
+// the backup.  Closing the writer does not close out.
+func NewEncryptedBackupWriter(out io.Writer, passphrase []byte) (io.WriteCloser, error) {
+	if len(passphrase) == 0 {
+		return nil, ErrBackupPassphrase
+	}
+
+	opts := &waddrmgr.DefaultScryptOptions
+	key, err := snacl.NewSecretKey(&passphrase, opts.N, opts.R, opts.P)
+	if err != nil {
+		return nil, err
+	}
+
+	var header bytes.Buffer
+	header.Write(encryptedBackupMagic)
+	header.WriteByte(encryptedBackupVersion)
+	header.Write(key.Marshal())
+	_, err = out.Write(header.Bytes())
+	if err != nil {
+		key.Zero()
+		return nil, err
+	}
+
+	return &encryptedBackupWriter{
+		out: out,
+		key: key,
+		buf: make([]byte, 0, backupChunkSize),
+	}, nil
+}
+
+// Write buffers p, sealing each full chunk once more data is written.  A full
+// chunk is only sealed when it is known not to be the final chunk.
+func (ew *encryptedBackupWriter) Write(p []byte) (int, error) {
+	if ew.closed {
+		return 0, errors.New("write to closed backup writer")
+	}
+
+	n := 0
+	for len(p) != 0 {
+		if len(ew.buf) == backupChunkSize {
+			err := ew.seal(false)
+			if err != nil {
+				return n, err
+			}
+		}
+		c := copy(ew.buf[len(ew.buf):backupChunkSize], p)
+		ew.buf = ew.buf[:len(ew.buf)+c]
+		p = p[c:]
+		n += c
+	}
+	return n, nil
+}
+
+// Close seals the buffered data as the final chunk of the backup and zeros the
+// backup key.
+func (ew *encryptedBackupWriter) Close() error {
+	if ew.closed {
+		return nil
+	}
+	ew.closed = true
+	defer ew.key.Zero()
+	return ew.seal(true)
+}
+
+// seal writes the buffered data as the next chunk of the backup.
+func (ew *encryptedBackupWriter) seal(final bool) error {
+	plaintext := make([]byte, chunkHeaderSize, chunkHeaderSize+len(ew.buf))
+	binary.BigEndian.PutUint64(plaintext, ew.seq)
+	if final {
+		plaintext[8] = 1
+	}
+	plaintext = append(plaintext, ew.buf...)
+
+	sealed, err := ew.key.Encrypt(plaintext)
+	if err != nil {
+		return err
+	}
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(sealed)))
+	_, err = ew.out.Write(length[:])
+	if err != nil {
+		return err
+	}
+	_, err = ew.out.Write(sealed)
+	if err != nil {
+		return err
+	}
+
+	ew.seq++
+	ew.buf = ew.buf[:0]
+	return nil
+}
+
+// encryptedBackupReader opens the chunks of an encrypted backup.
+type encryptedBackupReader struct {
+	in    io.Reader
+	key   *snacl.SecretKey
+	buf   []byte
+	seq   uint64
+	final bool
+}
+
+// NewBackupReader returns a reader of the wallet database held by a backup
+// written by Backup or BackupEncrypted.  Unencrypted backups are read as is,
+// while encrypted backups are decrypted with a key derived from the
+// passphrase.  ErrBackupPassphrase is returned when the backup is encrypted
+// and the passphrase is missing or incorrect.
+func NewBackupReader(in io.Reader, passphrase []byte) (io.Reader, error) {
+	br := bufio.NewReader(in)
+	magic, err := br.Peek(len(encryptedBackupMagic))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if !bytes.Equal(magic, encryptedBackupMagic) {
+		return br, nil
+	}
+	if len(passphrase) == 0 {
+		return nil, ErrBackupPassphrase
+	}
+
+	header := make([]byte, len(encryptedBackupMagic)+1+
+		len((&snacl.SecretKey{}).Marshal()))
+	_, err = io.ReadFull(br, header)
+	if err != nil {
+		return nil, ErrMalformedBackup
+	}
+	header = header[len(encryptedBackupMagic):]
+	if header[0] != encryptedBackupVersion {
+		return nil, ErrMalformedBackup
+	}
+
+	var key snacl.SecretKey
+	err = key.Unmarshal(header[1:])
+	if err != nil {
+		return nil, ErrMalformedBackup
+	}
+	err = key.DeriveKey(&passphrase)
+	if err == snacl.ErrInvalidPassword {
+		return nil, ErrBackupPassphrase
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &encryptedBackupReader{in: br, key: &key}, nil
+}
+
+// Read reads the decrypted database, opening the next chunk once the
+// previous chunk has been read.  The key is zeroed once the final chunk has
+// been opened.
+func (er *encryptedBackupReader) Read(p []byte) (int, error) {
+	for len(er.buf) == 0 {
+		if er.final {
+			return 0, io.EOF
+		}
+		err := er.open()
+		if err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, er.buf)
+	er.buf = er.buf[n:]
+	return n, nil
+}
+
+// open reads and opens the next chunk of the backup.
+func (er *encryptedBackupReader) open() error {
+	var length [4]byte
+	_, err := io.ReadFull(er.in, length[:])
+	if err != nil {
+		return ErrMalformedBackup
+	}
+	size := binary.BigEndian.Uint32(length[:])
+	if size > maxSealedChunkSize {
+		return ErrMalformedBackup
+	}
+	sealed := make([]byte, size)
+	_, err = io.ReadFull(er.in, sealed)
+	if err != nil {
+		return ErrMalformedBackup
+	}
+
+	plaintext, err := er.key.Decrypt(sealed)
+	if err != nil || len(plaintext) < chunkHeaderSize {
+		return ErrMalformedBackup
+	}
+	if binary.BigEndian.Uint64(plaintext) != er.seq {
+		return ErrMalformedBackup
+	}
+	er.seq++
+	er.final = plaintext[8] == 1
+	if er.final {
+		er.key.Zero()
+
+		// Ensure nothing, such as a repeated final chunk, follows the
+		// final chunk.
+		_, err := io.ReadFull(er.in, make([]byte, 1))
+		if err != io.EOF {
+			return ErrMalformedBackup
+		}
+	}
+	er.buf = plaintext[chunkHeaderSize:]
+	return nil
+}
+
+// BackupNamedWallet writes a backup of the loaded wallet with a name to the
+// file at dest, or to a file named after the wallet database in dest when it
+// is a directory.  The backup is encrypted when the passphrase is not empty.
+// ErrBackupOverwritesWallet is returned when dest is the database of any
+// wallet in the loader's database directory.
+func (l *Loader) BackupNamedWallet(name, dest string, passphrase []byte) error {
+	w, ok := l.NamedWallet(name)
+	if !ok {
+		return ErrNotLoaded
+	}
+
+	fi, err := os.Stat(dest)
+	if err == nil && fi.IsDir() {
+		dest = filepath.Join(dest, walletDbName)
+		fi, err = os.Stat(dest)
+	}
+	if err == nil {
+		names, err := l.WalletNames()
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			dbDir, err := l.walletDir(name)
+			if err != nil {
+				return err
+			}
+			dbInfo, err := os.Stat(filepath.Join(dbDir, walletDbName))
+			if err != nil {
+				return err
+			}
+			if os.SameFile(fi, dbInfo) {
+				return ErrBackupOverwritesWallet
+			}
+		}
+	}
+
+	return w.backupFile(dest, passphrase)
+}
+
+// RestoreNamedWallet creates the database of the wallet with a name from a
+// backup written by Backup or BackupEncrypted, decrypting it with the
+// passphrase if it is encrypted.  The wallet must not already exist, and is
+// not loaded by restoring it.
+func (l *Loader) RestoreNamedWallet(name string, backup io.Reader,
+	passphrase []byte) error {
+
+	defer l.mu.Unlock()
+	l.mu.Lock()
+
+	if _, ok := l.wallets[name]; ok {
+		return ErrLoaded
+	}
+	dbDir, err := l.walletDir(name)
+	if err != nil {
+		return err
+	}
+	dbPath := filepath.Join(dbDir, walletDbName)
+	exists, err := fileExists(dbPath)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrExists
+	}
+
+	r, err := NewBackupReader(backup, passphrase)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(dbDir, 0700)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(dbDir, "restore")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	n, err := io.Copy(tmp, r)
+	if err == nil && n == 0 {
+		err = ErrMalformedBackup
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	// Ensure the backup holds a database before putting it in place.
+	db, err := walletdb.Open("bdb", tmp.Name())
+	if err != nil {
+		return err
+	}
+	err = db.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dbPath)
+}
@@ -0,0 +1,207 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/btcsuite/btcwallet/snacl"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	_ "github.com/btcsuite/btcwallet/walletdb/bdb"
+)
+
+// testBackupPass is the passphrase of the encrypted backups of the tests.
+var testBackupPass = []byte("backup")
+
+// TestBackupRestore ensures wallets restored from unencrypted and encrypted
+// backups hold the state of the backed up wallet, and backups which can not
+// be decrypted are not restored.
+func TestBackupRestore(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	loader := NewLoader(testParams, dir)
+	loader.SetPassphraseOptions(fastScrypt)
+	defer loader.UnloadAllWallets()
+	w, err := loader.CreateNamedWallet("original", testPubPass,
+		testPrivPass, testSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := w.NewAddress(0, waddrmgr.PubKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		passphrase []byte
+	}{
+		{"unencrypted", nil},
+		{"encrypted", testBackupPass},
+	}
+	for _, test := range tests {
+		dest := filepath.Join(dir, test.name)
+		err := loader.BackupNamedWallet("original", dest, test.passphrase)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		backup, err := os.Open(dest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = loader.RestoreNamedWallet(test.name, backup, test.passphrase)
+		backup.Close()
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		restored, err := loader.OpenNamedWallet(test.name, testPubPass,
+			false)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		_, err = restored.Manager.Address(addr)
+		if err != nil {
+			t.Errorf("%s: address %v was not restored: %v",
+				test.name, addr, err)
+		}
+	}
+
+	// An encrypted backup is not restored with an incorrect passphrase,
+	// nor when it was truncated.
+	encrypted, err := ioutil.ReadFile(filepath.Join(dir, "encrypted"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	failures := []struct {
+		name       string
+		backup     []byte
+		passphrase []byte
+		err        error
+	}{
+		{"missing passphrase", encrypted, nil, ErrBackupPassphrase},
+		{"wrong passphrase", encrypted, []byte("wrong"), ErrBackupPassphrase},
+		{"truncated", encrypted[:len(encrypted)-1], testBackupPass,
+			ErrMalformedBackup},
+	}
+	for _, test := range failures {
+		err := loader.RestoreNamedWallet(test.name,
+			bytes.NewReader(test.backup), test.passphrase)
+		if err != test.err {
+			t.Errorf("%s: restore failed with error %v, expected %v",
+				test.name, err, test.err)
+		}
+		exists, err := loader.NamedWalletExists(test.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exists {
+			t.Errorf("%s: wallet was restored", test.name)
+		}
+	}
+}
+
+// splitBackupChunks splits an encrypted backup into its header and chunks.
+func splitBackupChunks(t *testing.T, backup []byte) ([]byte, [][]byte) {
+	headerSize := len(encryptedBackupMagic) + 1 +
+		len((&snacl.SecretKey{}).Marshal())
+	header, rest := backup[:headerSize], backup[headerSize:]
+	var chunks [][]byte
+	for len(rest) != 0 {
+		if len(rest) < 4 {
+			t.Fatal("backup ends within a chunk length")
+		}
+		size := 4 + int(binary.BigEndian.Uint32(rest))
+		if len(rest) < size {
+			t.Fatal("backup ends within a chunk")
+		}
+		chunks = append(chunks, rest[:size])
+		rest = rest[size:]
+	}
+	return header, chunks
+}
+
+// TestEncryptedBackupChunks ensures the chunks of an encrypted backup are only
+// read when all of them are present once and in order, through the final
+// chunk.
+func TestEncryptedBackupChunks(t *testing.T) {
+	t.Parallel()
+
+	data := make([]byte, 2*backupChunkSize+1000)
+	rand.New(rand.NewSource(1)).Read(data)
+	var backup bytes.Buffer
+	ew, err := NewEncryptedBackupWriter(&backup, testBackupPass)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ew.Write(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ew.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, chunks := splitBackupChunks(t, backup.Bytes())
+	if len(chunks) != 3 {
+		t.Fatalf("backup has %d chunks, expected 3", len(chunks))
+	}
+
+	// Derive the key from the header once, and open the chunks of each
+	// test with a copy of it, since the key is zeroed once the final chunk
+	// is opened.
+	r, err := NewBackupReader(bytes.NewReader(header), testBackupPass)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := r.(*encryptedBackupReader).key
+
+	c0, c1, c2 := chunks[0], chunks[1], chunks[2]
+	tests := []struct {
+		name   string
+		chunks [][]byte
+		err    error
+	}{
+		{"complete", [][]byte{c0, c1, c2}, nil},
+		{"missing final chunk", [][]byte{c0, c1}, ErrMalformedBackup},
+		{"missing chunk", [][]byte{c0, c2}, ErrMalformedBackup},
+		{"truncated chunk", [][]byte{c0, c1, c2[:len(c2)-1]},
+			ErrMalformedBackup},
+		{"reordered chunks", [][]byte{c1, c0, c2}, ErrMalformedBackup},
+		{"duplicated chunk", [][]byte{c0, c0, c1, c2}, ErrMalformedBackup},
+		{"duplicated final chunk", [][]byte{c0, c1, c2, c2},
+			ErrMalformedBackup},
+	}
+	for _, test := range tests {
+		cryptoKey := *key.Key
+		testKey := *key
+		testKey.Key = &cryptoKey
+		er := &encryptedBackupReader{
+			in:  bytes.NewReader(bytes.Join(test.chunks, nil)),
+			key: &testKey,
+		}
+		read, err := ioutil.ReadAll(er)
+		if err != test.err {
+			t.Errorf("%s: read failed with error %v, expected %v",
+				test.name, err, test.err)
+			continue
+		}
+		if err == nil && !bytes.Equal(read, data) {
+			t.Errorf("%s: read data differs from the backed up data",
+				test.name)
+		}
+	}
+}
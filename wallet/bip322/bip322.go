@@ -0,0 +1,240 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package bip322 implements the generic signed message format of BIP0322.
+// A message is signed for an address by spending an output paying to the
+// address's script, the message challenge, with a virtual transaction that
+// commits to the message.  Any script a wallet is able to spend can therefore
+// sign messages, including multisig and witness scripts.
+//
+// Signatures of witness scripts spent without a signature script are encoded
+// in the simple format, which is only the witness of the virtual transaction.
+// All other signatures are encoded in the full format, the entire virtual
+// transaction.  Proofs of funds, which spend additional inputs, are not
+// supported.
+//
+// Unsigned messages are passed between signers as PSBTs of the virtual
+// transaction, so the signatures of a multisig script may be created by
+// several wallets.
+package bip322
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/wallet/psbt"
+)
+
+// tag is the tag of the BIP0340 tagged hash of messages.
+const tag = "BIP0322-signed-message"
+
+// maxWitnessItemSize is the maximum size of a witness item read from a
+// signature in the simple format.
+const maxWitnessItemSize = wire.MaxMessagePayload
+
+var (
+	// ErrMalformedSignature describes an error where a signature is in
+	// neither the simple nor the full format, or the full format
+	// transaction does not spend the message challenge of the message.
+	ErrMalformedSignature = errors.New("malformed BIP0322 signature")
+
+	// ErrPacketMismatch describes an error where a PSBT does not sign the
+	// message for the message challenge it was given with.
+	ErrPacketMismatch = errors.New("PSBT does not sign the message")
+)
+
+// verifyFlags are the script flags used to validate signatures.
+const verifyFlags = txscript.StandardVerifyFlags
+
+// MessageHash returns the tagged hash of a message which is committed to by
+// the virtual transactions.
+func MessageHash(message []byte) [32]byte {
+	tagHash := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	h.Write(message)
+	var hash [32]byte
+	copy(hash[:], h.Sum(nil))
+	return hash
+}
+
+// ToSpend returns the virtual to_spend transaction for a message.  Its only
+// output pays to the message challenge, the output script of the address the
+// message is signed for.
+func ToSpend(message, challenge []byte) *wire.MsgTx {
+	hash := MessageHash(message)
+	sigScript, _ := txscript.NewScriptBuilder().AddOp(txscript.OP_0).
+		AddData(hash[:]).Script()
+
+	tx := wire.NewMsgTx(0)
+	prevOut := wire.NewOutPoint(&chainhash.Hash{}, wire.MaxPrevOutIndex)
+	txIn := wire.NewTxIn(prevOut, sigScript, nil)
+	txIn.Sequence = 0
+	tx.AddTxIn(txIn)
+	tx.AddTxOut(wire.NewTxOut(0, challenge))
+	return tx
+}
+
+// ToSign returns the unsigned virtual to_sign transaction which spends the
+// output of a to_spend transaction.  The signature of a message is the script
+// which spends this input.
+func ToSign(toSpend *wire.MsgTx) *wire.MsgTx {
+	tx := wire.NewMsgTx(0)
+	toSpendHash := toSpend.TxHash()
+	prevOut := wire.NewOutPoint(&toSpendHash, 0)
+	txIn := wire.NewTxIn(prevOut, nil, nil)
+	txIn.Sequence = 0
+	tx.AddTxIn(txIn)
+	tx.AddTxOut(wire.NewTxOut(0, []byte{txscript.OP_RETURN}))
+	return tx
+}
+
+// NewPacket returns a PSBT of the to_sign transaction of a message, with the
+// to_spend transaction recorded as the UTXO of its input.
+func NewPacket(message, challenge []byte) (*psbt.Packet, error) {
+	toSpend := ToSpend(message, challenge)
+	p, err := psbt.New(ToSign(toSpend))
+	if err != nil {
+		return nil, err
+	}
+	setUtxo(&p.Inputs[0], toSpend)
+	return p, nil
+}
+
+// CheckPacket returns ErrPacketMismatch if a PSBT is not of the to_sign
+// transaction of the message and message challenge.  The UTXO of the input is
+// replaced by the to_spend transaction, so a PSBT received from another signer
+// can not commit to a different previous output.
+func CheckPacket(p *psbt.Packet, message, challenge []byte) error {
+	toSpend := ToSpend(message, challenge)
+	if p.UnsignedTx.TxHash() != ToSign(toSpend).TxHash() {
+		return ErrPacketMismatch
+	}
+	setUtxo(&p.Inputs[0], toSpend)
+	return nil
+}
+
+// setUtxo records the output of the to_spend transaction as the UTXO of the
+// to_sign input.
+func setUtxo(in *psbt.PInput, toSpend *wire.MsgTx) {
+	in.NonWitnessUtxo = toSpend
+	in.WitnessUtxo = nil
+	if txscript.IsWitnessProgram(toSpend.TxOut[0].PkScript) {
+		in.WitnessUtxo = toSpend.TxOut[0]
+	}
+}
+
+// Finalize finalizes the input of a signed PSBT of a message and returns the
+// encoded signature.  psbt.ErrNotFinalizable is returned if the PSBT is still
+// missing signatures.
+func Finalize(p *psbt.Packet) ([]byte, error) {
+	err := psbt.Finalize(p, 0)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := psbt.Extract(p)
+	if err != nil {
+		return nil, err
+	}
+	return Encode(tx)
+}
+
+// Encode encodes the signature of a signed to_sign transaction.  The simple
+// format is used when the input is spent by its witness alone.
+func Encode(toSign *wire.MsgTx) ([]byte, error) {
+	var buf bytes.Buffer
+	txIn := toSign.TxIn[0]
+	if len(txIn.SignatureScript) == 0 && len(txIn.Witness) != 0 {
+		err := writeWitness(&buf, txIn.Witness)
+		return buf.Bytes(), err
+	}
+	err := toSign.Serialize(&buf)
+	return buf.Bytes(), err
+}
+
+// Decode returns the signed to_sign transaction encoded by a signature of the
+// message.  ErrMalformedSignature is returned if the signature can not be
+// decoded or does not sign the message for the message challenge.
+func Decode(sig, message, challenge []byte) (*wire.MsgTx, error) {
+	toSign := ToSign(ToSpend(message, challenge))
+
+	// A full format signature is only accepted if it is exactly the
+	// to_sign transaction with input scripts added.
+	var full wire.MsgTx
+	r := bytes.NewReader(sig)
+	if full.Deserialize(r) == nil && r.Len() == 0 {
+		unsigned := full.Copy()
+		for _, txIn := range unsigned.TxIn {
+			txIn.SignatureScript = nil
+			txIn.Witness = nil
+		}
+		if unsigned.TxHash() != toSign.TxHash() {
+			return nil, ErrMalformedSignature
+		}
+		return &full, nil
+	}
+
+	witness, err := readWitness(sig)
+	if err != nil {
+		return nil, err
+	}
+	toSign.TxIn[0].Witness = witness
+	return toSign, nil
+}
+
+// Verify returns whether a signature of a message is valid for the message
+// challenge.  An error is only returned if the signature can not be decoded.
+func Verify(sig, message, challenge []byte) (bool, error) {
+	toSign, err := Decode(sig, message, challenge)
+	if err != nil {
+		return false, err
+	}
+	vm, err := txscript.NewEngine(challenge, toSign, 0, verifyFlags, nil,
+		txscript.NewTxSigHashes(toSign), 0)
+	if err != nil {
+		return false, nil
+	}
+	return vm.Execute() == nil, nil
+}
+
+// readWitness reads a signature in the simple format.
+func readWitness(b []byte) (wire.TxWitness, error) {
+	r := bytes.NewReader(b)
+	n, err := wire.ReadVarInt(r, 0)
+	if err != nil || n == 0 || n > uint64(len(b)) {
+		return nil, ErrMalformedSignature
+	}
+	witness := make(wire.TxWitness, n)
+	for i := range witness {
+		witness[i], err = wire.ReadVarBytes(r, 0, maxWitnessItemSize,
+			"witness item")
+		if err != nil {
+			return nil, ErrMalformedSignature
+		}
+	}
+	if r.Len() != 0 {
+		return nil, ErrMalformedSignature
+	}
+	return witness, nil
+}
+
+// writeWitness writes a signature in the simple format.
+func writeWitness(w *bytes.Buffer, witness wire.TxWitness) error {
+	err := wire.WriteVarInt(w, 0, uint64(len(witness)))
+	if err != nil {
+		return err
+	}
+	for _, item := range witness {
+		err = wire.WriteVarBytes(w, 0, item)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,282 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package bip322_test
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil"
+	. "github.com/btcsuite/btcwallet/wallet/bip322"
+	"github.com/btcsuite/btcwallet/wallet/psbt"
+)
+
+var params = &chaincfg.MainNetParams
+
+// The test vectors of BIP0322 sign messages for this P2WPKH address.
+const (
+	vectorAddress = "bc1q9vza2e8x573nczrlzms0wvx3gsqjx7vavgkx0l"
+	vectorWIF     = "L3VFeEujGtevx9w18HD1fhRbCH67Az2dpCymeRE1SoPK6XQtaN2k"
+)
+
+// testSecrets is a SecretsSource holding keys and redeem scripts looked up by
+// their encoded addresses.
+type testSecrets struct {
+	keys    map[string]*btcec.PrivateKey
+	scripts map[string][]byte
+}
+
+func newTestSecrets() *testSecrets {
+	return &testSecrets{
+		keys:    make(map[string]*btcec.PrivateKey),
+		scripts: make(map[string][]byte),
+	}
+}
+
+func (s *testSecrets) addKey(t *testing.T, privKey *btcec.PrivateKey) {
+	pubKeyHash := btcutil.Hash160(privKey.PubKey().SerializeCompressed())
+	pkh, err := btcutil.NewAddressPubKeyHash(pubKeyHash, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wpkh, err := btcutil.NewAddressWitnessPubKeyHash(pubKeyHash, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.keys[pkh.EncodeAddress()] = privKey
+	s.keys[wpkh.EncodeAddress()] = privKey
+}
+
+func (s *testSecrets) addScript(t *testing.T, script []byte) {
+	addr, err := btcutil.NewAddressScriptHash(script, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.scripts[addr.EncodeAddress()] = script
+}
+
+func (s *testSecrets) GetKey(addr btcutil.Address) (*btcec.PrivateKey, bool, error) {
+	privKey, ok := s.keys[addr.EncodeAddress()]
+	if !ok {
+		return nil, false, errors.New("unknown key")
+	}
+	return privKey, true, nil
+}
+
+func (s *testSecrets) GetScript(addr btcutil.Address) ([]byte, error) {
+	script, ok := s.scripts[addr.EncodeAddress()]
+	if !ok {
+		return nil, errors.New("unknown script")
+	}
+	return script, nil
+}
+
+func (s *testSecrets) ChainParams() *chaincfg.Params {
+	return params
+}
+
+func vectorChallenge(t *testing.T) []byte {
+	addr, err := btcutil.DecodeAddress(vectorAddress, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	challenge, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return challenge
+}
+
+func TestVectors(t *testing.T) {
+	challenge := vectorChallenge(t)
+	tests := []struct {
+		message   string
+		hash      string
+		toSpendID string
+		toSignID  string
+		signature string
+	}{
+		{
+			message:   "",
+			hash:      "c90c269c4f8fcbe6880f72a721ddfbf1914268a794cbb21cfafee13770ae19f1",
+			toSpendID: "c5680aa69bb8d860bf82d4e9cd3504b55dde018de765a91bb566283c545a99a7",
+			toSignID:  "1e9654e951a5ba44c8604c4de6c67fd78a27e81dcadcfe1edf638ba3aaebaed6",
+			signature: "AkcwRAIgM2gBAQqvZX15ZiysmKmQpDrG83avLIT492QBzLnQIxYCIBaTpOaD20qRlEylyxFSeEA2ba9YOixpX8z46TSDtS40ASECx/EgAxlkQpQ9hYjgGu6EBCPMVPwVIVJqO4XCsMvViHI=",
+		},
+		{
+			message:   "Hello World",
+			hash:      "f0eb03b1a75ac6d9847f55c624a99169b5dccba2a31f5b23bea77ba270de0a7a",
+			toSpendID: "b79d196740ad5217771c1098fc4a4b51e0535c32236c71f1ea4d61a2d603352b",
+			toSignID:  "88737ae86f2077145f93cc4b153ae9a1cb8d56afa511988c149c5c8c9d93bddf",
+			signature: "AkcwRAIgZRfIY3p7/DoVTty6YZbWS71bc5Vct9p9Fia83eRmw2QCICK/ENGfwLtptFluMGs2KsqoNSk89pO7F29zJLUx9a/sASECx/EgAxlkQpQ9hYjgGu6EBCPMVPwVIVJqO4XCsMvViHI=",
+		},
+	}
+	for _, test := range tests {
+		message := []byte(test.message)
+		hash := MessageHash(message)
+		if hex.EncodeToString(hash[:]) != test.hash {
+			t.Errorf("%q: message hash %x, want %s", test.message,
+				hash, test.hash)
+		}
+		toSpend := ToSpend(message, challenge)
+		if toSpend.TxHash().String() != test.toSpendID {
+			t.Errorf("%q: to_spend txid %v, want %s", test.message,
+				toSpend.TxHash(), test.toSpendID)
+		}
+		toSign := ToSign(toSpend)
+		if toSign.TxHash().String() != test.toSignID {
+			t.Errorf("%q: to_sign txid %v, want %s", test.message,
+				toSign.TxHash(), test.toSignID)
+		}
+
+		sig, err := base64.StdEncoding.DecodeString(test.signature)
+		if err != nil {
+			t.Fatal(err)
+		}
+		valid, err := Verify(sig, message, challenge)
+		if err != nil || !valid {
+			t.Errorf("%q: signature not valid (err %v)", test.message, err)
+		}
+		valid, err = Verify(sig, []byte("other message"), challenge)
+		if err != nil || valid {
+			t.Errorf("%q: signature valid for another message (err %v)",
+				test.message, err)
+		}
+	}
+}
+
+func TestSignWitnessPubKeyHash(t *testing.T) {
+	wif, err := btcutil.DecodeWIF(vectorWIF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secrets := newTestSecrets()
+	secrets.addKey(t, wif.PrivKey)
+	challenge := vectorChallenge(t)
+	message := []byte("Hello World")
+
+	p, err := NewPacket(message, challenge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, err := psbt.Sign(p, secrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signed) != 1 {
+		t.Fatalf("signed inputs %v, want [0]", signed)
+	}
+	sig, err := Finalize(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Signatures of P2WPKH addresses are encoded in the simple format:
+	// a witness of a signature and a pubkey.
+	if sig[0] != 2 {
+		t.Errorf("signature is not in the simple format: %x", sig)
+	}
+	valid, err := Verify(sig, message, challenge)
+	if err != nil || !valid {
+		t.Errorf("signature not valid (err %v)", err)
+	}
+}
+
+func TestSignMultiSig(t *testing.T) {
+	var keys [3]*btcec.PrivateKey
+	var pubKeys [3]*btcutil.AddressPubKey
+	for i := range keys {
+		var err error
+		keys[i], err = btcec.NewPrivateKey(btcec.S256())
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubKeys[i], err = btcutil.NewAddressPubKey(
+			keys[i].PubKey().SerializeCompressed(), params)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	redeemScript, err := txscript.MultiSigScript(pubKeys[:], 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := btcutil.NewAddressScriptHash(redeemScript, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	challenge, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := []byte("multisig message")
+
+	// Each signer holds one key of the 2-of-3 multisig script, and signs
+	// the PSBT passed on by the previous signer.
+	p, err := NewPacket(message, challenge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, key := range keys[:2] {
+		secrets := newTestSecrets()
+		secrets.addKey(t, key)
+		secrets.addScript(t, redeemScript)
+
+		err = CheckPacket(p, message, challenge)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = psbt.Sign(p, secrets)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := Finalize(p)
+		if i == 0 {
+			if err != psbt.ErrNotFinalizable {
+				t.Fatalf("finalized with one of two signatures: %v", err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		valid, err := Verify(sig, message, challenge)
+		if err != nil || !valid {
+			t.Errorf("signature not valid (err %v)", err)
+		}
+		_, err = Verify(sig, []byte("other message"), challenge)
+		if err != ErrMalformedSignature {
+			t.Errorf("full signature of another message: got error %v, "+
+				"want %v", err, ErrMalformedSignature)
+		}
+	}
+
+	other, err := NewPacket([]byte("other message"), challenge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = CheckPacket(other, message, challenge)
+	if err != ErrPacketMismatch {
+		t.Errorf("CheckPacket of another message: got error %v, want %v",
+			err, ErrPacketMismatch)
+	}
+}
+
+func TestVerifyMalformed(t *testing.T) {
+	challenge := vectorChallenge(t)
+	for _, sig := range [][]byte{nil, {0x00}, {0x01, 0x05, 0x00}, {0x01, 0x00, 0x00}} {
+		_, err := Verify(sig, nil, challenge)
+		if err != ErrMalformedSignature {
+			t.Errorf("signature %x: got error %v, want %v", sig, err,
+				ErrMalformedSignature)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package bip39 implements the mnemonic codes of BIP0039, which encode the
+// entropy of a wallet seed as a sequence of words from the English wordlist.
+// The final word includes a checksum of the entropy, so a mistyped or
+// misordered word is detected before a seed is derived.
+//
+// The seed of a mnemonic is derived with an optional passphrase, sometimes
+// called the 25th word.  Every passphrase derives a valid but different seed,
+// so a mistyped passphrase can not be detected.
+package bip39
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/btcsuite/golangcrypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// MinEntropyBits and MaxEntropyBits are the bounds of the entropy size
+	// of a mnemonic, which must also be a multiple of 32 bits.
+	MinEntropyBits = 128
+	MaxEntropyBits = 256
+
+	// RecommendedEntropyBits is the entropy size of generated mnemonics,
+	// which are encoded as 24 words.
+	RecommendedEntropyBits = 256
+
+	// SeedLen is the length of the seed derived from a mnemonic.
+	SeedLen = 64
+
+	// seedIterations is the number of PBKDF2 iterations used to derive the
+	// seed.
+	seedIterations = 2048
+
+	// bitsPerWord is the number of bits encoded by each word.
+	bitsPerWord = 11
+)
+
+var (
+	// ErrEntropyLen describes an error where the entropy of a mnemonic is
+	// not a multiple of 32 bits between MinEntropyBits and MaxEntropyBits.
+	ErrEntropyLen = fmt.Errorf("entropy must be a multiple of 32 bits "+
+		"between %d and %d bits", MinEntropyBits, MaxEntropyBits)
+
+	// ErrMnemonicLen describes an error where a mnemonic does not have 12,
+	// 15, 18, 21 or 24 words.
+	ErrMnemonicLen = errors.New("mnemonic must have 12, 15, 18, 21 or " +
+		"24 words")
+
+	// ErrChecksum describes an error where the checksum of a mnemonic does
+	// not match its entropy, usually because a word was mistyped.
+	ErrChecksum = errors.New("mnemonic checksum mismatch")
+)
+
+// UnknownWordError describes an error where a mnemonic contains a word which
+// is not in the wordlist.
+type UnknownWordError struct {
+	Word     string
+	Position int // One-based position of the word in the mnemonic.
+}
+
+// Error implements the error interface.
+func (e *UnknownWordError) Error() string {
+	return fmt.Sprintf("word %d of the mnemonic (%q) is not in the BIP0039 "+
+		"wordlist", e.Position, e.Word)
+}
+
+// NewEntropy returns random entropy of the given number of bits.
+func NewEntropy(bits int) ([]byte, error) {
+	if !validEntropyBits(bits) {
+		return nil, ErrEntropyLen
+	}
+	entropy := make([]byte, bits/8)
+	_, err := rand.Read(entropy)
+	if err != nil {
+		return nil, err
+	}
+	return entropy, nil
+}
+
+// validEntropyBits returns whether entropy of the given number of bits may be
+// encoded as a mnemonic.
+func validEntropyBits(bits int) bool {
+	return bits%32 == 0 && bits >= MinEntropyBits && bits <= MaxEntropyBits
+}
+
+// checksum returns the checksum bits of entropy, which are the first
+// len(entropy)/4 bits of its sha256 hash, in the high bits of a byte.
+func checksum(entropy []byte) byte {
+	hash := sha256.Sum256(entropy)
+	n := uint(len(entropy) / 4)
+	return hash[0] >> (8 - n) << (8 - n)
+}
+
+// NewMnemonic encodes entropy as a mnemonic of space-separated words.
+func NewMnemonic(entropy []byte) (string, error) {
+	if !validEntropyBits(len(entropy) * 8) {
+		return "", ErrEntropyLen
+	}
+
+	// The words encode the entropy followed by the checksum bits.
+	data := make([]byte, len(entropy)+1)
+	copy(data, entropy)
+	data[len(entropy)] = checksum(entropy)
+
+	numWords := (len(entropy)*8 + len(entropy)/4) / bitsPerWord
+	words := make([]string, numWords)
+	for i := range words {
+		var index int
+		for b := i * bitsPerWord; b < (i+1)*bitsPerWord; b++ {
+			bit := data[b/8] >> (7 - uint(b%8)) & 1
+			index = index<<1 | int(bit)
+		}
+		words[i] = englishWords[index]
+	}
+	return strings.Join(words, " "), nil
+}
+
+// wordIndex returns the index of a word in the wordlist.
+func wordIndex(word string) (int, bool) {
+	i := sort.SearchStrings(englishWords[:], word)
+	return i, i < len(englishWords) && englishWords[i] == word
+}
+
+// EntropyFromMnemonic decodes the entropy of a mnemonic and validates its
+// checksum.  Words may be separated by any whitespace and are not case
+// sensitive.
+func EntropyFromMnemonic(mnemonic string) ([]byte, error) {
+	words := strings.Fields(strings.ToLower(mnemonic))
+	switch len(words) {
+	case 12, 15, 18, 21, 24:
+	default:
+		return nil, ErrMnemonicLen
+	}
+
+	numBits := len(words) * bitsPerWord
+	data := make([]byte, (numBits+7)/8)
+	for i, word := range words {
+		index, ok := wordIndex(word)
+		if !ok {
+			return nil, &UnknownWordError{Word: word, Position: i + 1}
+		}
+		for b := 0; b < bitsPerWord; b++ {
+			if index>>(bitsPerWord-1-uint(b))&1 == 1 {
+				pos := i*bitsPerWord + b
+				data[pos/8] |= 1 << (7 - uint(pos%8))
+			}
+		}
+	}
+
+	// The checksum is one bit for every 32 bits of entropy.
+	entropyLen := numBits * 32 / 33 / 8
+	entropy := data[:entropyLen]
+	if data[entropyLen] != checksum(entropy) {
+		return nil, ErrChecksum
+	}
+	return entropy, nil
+}
+
+// normalize returns the NFKD normalization of s, which is applied to the
+// mnemonic and passphrase before deriving the seed.
+func normalize(s string) string {
+	return norm.NFKD.String(s)
+}
+
+// Seed validates a mnemonic and derives the wallet seed from it and an
+// optional passphrase.  An error is returned if the mnemonic has an unknown
+// word or an invalid checksum.
+func Seed(mnemonic, passphrase string) ([]byte, error) {
+	_, err := EntropyFromMnemonic(mnemonic)
+	if err != nil {
+		return nil, err
+	}
+	words := strings.Fields(strings.ToLower(mnemonic))
+	password := normalize(strings.Join(words, " "))
+	salt := normalize("mnemonic" + passphrase)
+	return pbkdf2.Key([]byte(password), []byte(salt), seedIterations,
+		SeedLen, sha512.New), nil
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package bip39_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	. "github.com/btcsuite/btcwallet/wallet/bip39"
+)
+
+// TestVectors checks the test vectors of the reference implementation, whose
+// seeds are derived with the passphrase "TREZOR".
+func TestVectors(t *testing.T) {
+	tests := []struct {
+		entropy  string
+		mnemonic string
+		seed     string
+	}{
+		{
+			entropy:  "00000000000000000000000000000000",
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			seed:     "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+		},
+		{
+			entropy:  "ffffffffffffffffffffffffffffffff",
+			mnemonic: "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+			seed:     "ac27495480225222079d7be181583751e86f571027b0497b5b5d11218e0a8a13332572917f0f8e5a589620c6f15b11c61dee327651a14c34e18231052e48c069",
+		},
+		{
+			entropy:  "7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+			mnemonic: "legal winner thank year wave sausage worth useful legal winner thank year wave sausage worth useful legal will",
+			seed:     "f2b94508732bcbacbcc020faefecfc89feafa6649a5491b8c952cede496c214a0c7b3c392d168748f2d4a612bada0753b52a1c7ac53c1e93abd5c6320b9e95dd",
+		},
+		{
+			entropy:  "0000000000000000000000000000000000000000000000000000000000000000",
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art",
+			seed:     "bda85446c68413707090a52022edd26a1c9462295029f2e60cd7c4f2bbd3097170af7a4d73245cafa9c3cca8d561a7c3de6f5d4a10be8ed2a5e608d68f92fcc8",
+		},
+		{
+			entropy:  "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+			mnemonic: "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo vote",
+			seed:     "dd48c104698c30cfe2b6142103248622fb7bb0ff692eebb00089b32d22484e1613912f0a5b694407be899ffd31ed3992c456cdf60f5d4564b8ba3f05a69890ad",
+		},
+		{
+			entropy:  "77c2b00716cec7213839159e404db50d",
+			mnemonic: "jelly better achieve collect unaware mountain thought cargo oxygen act hood bridge",
+			seed:     "b5b6d0127db1a9d2226af0c3346031d77af31e918dba64287a1b44b8ebf63cdd52676f672a290aae502472cf2d602c051f3e6f18055e84e4c43897fc4e51a6ff",
+		},
+		{
+			entropy:  "2c85efc7f24ee4573d2b81a6ec66cee209b2dcbd09d8eddc51e0215b0b68e416",
+			mnemonic: "clutch control vehicle tonight unusual clog visa ice plunge glimpse recipe series open hour vintage deposit universe tip job dress radar refuse motion taste",
+			seed:     "fe908f96f46668b2d5b37d82f558c77ed0d69dd0e7e043a5b0511c48c2f1064694a956f86360c93dd04052a8899497ce9e985ebe0c8c52b955e6ae86d4ff4449",
+		},
+		{
+			entropy:  "15da872c95a13dd738fbf50e427583ad61f18fd99f628c417a61cf8343c90419",
+			mnemonic: "beyond stage sleep clip because twist token leaf atom beauty genius food business side grid unable middle armed observe pair crouch tonight away coconut",
+			seed:     "b15509eaa2d09d3efd3e006ef42151b30367dc6e3aa5e44caba3fe4d3e352e65101fbdb86a96776b91946ff06f8eac594dc6ee1d3e82a42dfe1b40fef6bcc3fd",
+		},
+	}
+
+	for _, test := range tests {
+		entropy, err := hex.DecodeString(test.entropy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mnemonic, err := NewMnemonic(entropy)
+		if err != nil {
+			t.Errorf("NewMnemonic(%s): %v", test.entropy, err)
+			continue
+		}
+		if mnemonic != test.mnemonic {
+			t.Errorf("NewMnemonic(%s) = %q, want %q", test.entropy,
+				mnemonic, test.mnemonic)
+		}
+
+		decoded, err := EntropyFromMnemonic(test.mnemonic)
+		if err != nil {
+			t.Errorf("EntropyFromMnemonic(%q): %v", test.mnemonic, err)
+			continue
+		}
+		if !bytes.Equal(decoded, entropy) {
+			t.Errorf("EntropyFromMnemonic(%q) = %x, want %s",
+				test.mnemonic, decoded, test.entropy)
+		}
+
+		seed, err := Seed(test.mnemonic, "TREZOR")
+		if err != nil {
+			t.Errorf("Seed(%q): %v", test.mnemonic, err)
+			continue
+		}
+		if hex.EncodeToString(seed) != test.seed {
+			t.Errorf("Seed(%q) = %x, want %s", test.mnemonic, seed,
+				test.seed)
+		}
+	}
+}
+
+func TestInvalidMnemonics(t *testing.T) {
+	tests := []struct {
+		mnemonic string
+		err      error
+	}{
+		{
+			// 11 words.
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+			err:      ErrMnemonicLen,
+		},
+		{
+			// Last word changed.
+			mnemonic: "legal winner thank year wave sausage worth useful legal winner thank year",
+			err:      ErrChecksum,
+		},
+		{
+			// Two words swapped.
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about abandon",
+			err:      ErrChecksum,
+		},
+	}
+	for _, test := range tests {
+		_, err := Seed(test.mnemonic, "")
+		if err != test.err {
+			t.Errorf("Seed(%q): got error %v, want %v", test.mnemonic,
+				err, test.err)
+		}
+	}
+
+	mnemonic := "letter advice cage absurd amount doctor acoustic avoid letter advice caged above"
+	_, err := EntropyFromMnemonic(mnemonic)
+	e, ok := err.(*UnknownWordError)
+	if !ok || e.Word != "caged" || e.Position != 11 {
+		t.Errorf("EntropyFromMnemonic(%q): got error %v, want unknown "+
+			"word 11", mnemonic, err)
+	}
+}
+
+func TestNewEntropy(t *testing.T) {
+	for _, bits := range []int{0, 96, 136, 288} {
+		if _, err := NewEntropy(bits); err != ErrEntropyLen {
+			t.Errorf("NewEntropy(%d): got error %v, want %v", bits,
+				err, ErrEntropyLen)
+		}
+	}
+
+	entropy, err := NewEntropy(RecommendedEntropyBits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mnemonic, err := NewMnemonic(entropy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Whitespace and case are not significant.
+	decoded, err := EntropyFromMnemonic("  " + mnemonic + "\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decoded, entropy) {
+		t.Errorf("round trip of %q decoded %x, want %x", mnemonic,
+			decoded, entropy)
+	}
+}
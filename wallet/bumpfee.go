@@ -0,0 +1,393 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wallet/txauthor"
+	"github.com/btcsuite/btcwallet/wallet/txrules"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+)
+
+// Errors returned when a transaction's fee can not be bumped.
+var (
+	// ErrTxNotFound describes an error where a transaction is not recorded
+	// by the wallet.
+	ErrTxNotFound = errors.New("transaction not found")
+
+	// ErrTxMined describes an error where a transaction can not be
+	// replaced because it has already been mined.
+	ErrTxMined = errors.New("transaction has already been mined")
+
+	// ErrTxNotReplaceable describes an error where a transaction does not
+	// signal that it may be replaced as described by BIP0125.
+	ErrTxNotReplaceable = errors.New("transaction does not signal " +
+		"replaceability")
+
+	// ErrTxNotOwned describes an error where a transaction spends outputs
+	// which are not controlled by the wallet, and so can not be signed.
+	ErrTxNotOwned = errors.New("transaction spends outputs not " +
+		"controlled by the wallet")
+
+	// ErrTxHasSpenders describes an error where a transaction can not be
+	// replaced because other unmined transactions spend its outputs.
+	ErrTxHasSpenders = errors.New("transaction outputs are spent by " +
+		"unmined transactions")
+
+	// ErrFeeRateTooLow describes an error where the requested fee rate is
+	// too low for the replacement to be relayed.
+	ErrFeeRateTooLow = errors.New("fee rate is too low to replace the " +
+		"transaction")
+)
+
+type (
+	bumpFeeRequest struct {
+		txHash  chainhash.Hash
+		feeRate btcutil.Amount
+		resp    chan bumpFeeResponse
+	}
+	bumpFeeResponse struct {
+		result *BumpFeeResult
+		err    error
+	}
+)
+
+// BumpFeeResult describes a transaction created to replace another, paying a
+// higher fee.
+type BumpFeeResult struct {
+	Tx      *wire.MsgTx
+	OrigFee btcutil.Amount
+	Fee     btcutil.Amount
+}
+
+// BumpFee replaces an unmined transaction created by the wallet with another
+// paying the same outputs with a higher fee, as described by BIP0125.  The fee
+// of the replacement pays feeRate per kB of serialized size, or the wallet's
+// estimated fee rate when feeRate is zero.  The fee is paid by decreasing the
+// change output of the original transaction, and additional inputs from the
+// same account are added when the change is insufficient.  The replacement is
+// published and recorded in place of the original transaction.
+//
+// Only transactions which signal replaceability, spend outputs controlled by
+// the wallet, and do not have unmined spenders may be replaced.  Replacement
+// is serialized with all other transaction creation, and the wallet must be
+// unlocked to sign the replacement.
+func (w *Wallet) BumpFee(txHash *chainhash.Hash, feeRate btcutil.Amount) (*BumpFeeResult, error) {
+	req := bumpFeeRequest{
+		txHash:  *txHash,
+		feeRate: feeRate,
+		resp:    make(chan bumpFeeResponse),
+	}
+	w.bumpFeeRequests <- req
+	resp := <-req.resp
+	return resp.result, resp.err
+}
+
+// bumpFee implements BumpFee.  It must only be called by the txCreator
+// goroutine.
+func (w *Wallet) bumpFee(txHash *chainhash.Hash, feeRate btcutil.Amount) (*BumpFeeResult, error) {
+	heldUnlock, err := w.HoldUnlock()
+	if err != nil {
+		return nil, err
+	}
+	defer heldUnlock.Release()
+
+	chainClient, err := w.requireChainClient()
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := w.TxStore.TxDetails(txHash)
+	if err != nil {
+		return nil, err
+	}
+	if details == nil {
+		return nil, ErrTxNotFound
+	}
+	if details.Block.Height != -1 {
+		return nil, ErrTxMined
+	}
+	origTx := &details.MsgTx
+	if !txrules.SignalsReplacement(origTx) {
+		return nil, ErrTxNotReplaceable
+	}
+	err = w.checkNoUnminedSpenders(txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every input must be signed again, so each must spend a wallet
+	// output.
+	prevScripts, err := w.TxStore.PreviousPkScripts(&details.TxRecord, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(details.Debits) != len(origTx.TxIn) ||
+		len(prevScripts) != len(origTx.TxIn) {
+		return nil, ErrTxNotOwned
+	}
+	var origInput, origOutput btcutil.Amount
+	origInputValues := make([]btcutil.Amount, len(origTx.TxIn))
+	for _, debit := range details.Debits {
+		origInputValues[debit.Index] = debit.Amount
+		origInput += debit.Amount
+	}
+	for _, txOut := range origTx.TxOut {
+		origOutput += btcutil.Amount(txOut.Value)
+	}
+	origFee := origInput - origOutput
+
+	// The replacement must pay a higher fee rate than the original, and
+	// must additionally pay for its own relay.
+	relayFee := w.RelayFee()
+	origFeeRate := origFee * 1000 / btcutil.Amount(txrules.VirtualSize(origTx))
+	minFeeRate := origFeeRate + relayFee
+	switch {
+	case feeRate == 0:
+		feeRate = w.FeeRate()
+		if feeRate < minFeeRate {
+			feeRate = minFeeRate
+		}
+	case feeRate < minFeeRate:
+		return nil, ErrFeeRateTooLow
+	}
+
+	// Additional inputs and any new change output are taken from the
+	// account of the original inputs.  As in txToOutputs, change for spends
+	// from the imported account is paid to the default account.
+	account, err := w.scriptAccount(prevScripts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	// All outputs other than change are paid again by the replacement.
+	changeIndex := -1
+	for _, credit := range details.Credits {
+		if credit.Change {
+			changeIndex = int(credit.Index)
+			break
+		}
+	}
+	outputs := make([]*wire.TxOut, 0, len(origTx.TxOut))
+	var changeScript []byte
+	for i, txOut := range origTx.TxOut {
+		if i == changeIndex {
+			changeScript = txOut.PkScript
+			continue
+		}
+		outputs = append(outputs, txOut)
+	}
+	changeSource := func() ([]byte, error) {
+		if changeScript != nil {
+			return changeScript, nil
+		}
+		changeAccount := account
+		if account == waddrmgr.ImportedAddrAccount {
+			changeAccount = 0
+		}
+		changeAddr, err := w.NewChangeAddress(changeAccount)
+		if err != nil {
+			return nil, err
+		}
+		return txscript.PayToAddrScript(changeAddr)
+	}
+
+	bs, err := chainClient.BlockStamp()
+	if err != nil {
+		return nil, err
+	}
+	eligible, err := w.findEligibleOutputs(account, 1, bs)
+	if err != nil {
+		return nil, err
+	}
+	inputSource := makeFixedInputSource(origTx.TxIn, origInputValues,
+		prevScripts, w.redeemScripts(prevScripts),
+		w.makeInputSource(eligible, nil))
+
+	tx, err := txauthor.NewUnsignedTransaction(outputs, feeRate,
+		inputSource, changeSource)
+	if err != nil {
+		return nil, err
+	}
+	for _, txIn := range tx.Tx.TxIn {
+		txIn.Sequence = txrules.MaxRBFSequence
+	}
+	err = txauthor.SetTimeLocks(tx.Tx, tx.PrevScripts, secretSource{w.Manager})
+	if err != nil {
+		return nil, err
+	}
+	if tx.ChangeIndex >= 0 {
+		tx.RandomizeChangePosition()
+	}
+	err = tx.AddAllInputScripts(secretSource{w.Manager})
+	if err != nil {
+		return nil, err
+	}
+	err = validateMsgTx(tx.Tx, tx.PrevScripts, tx.PrevInputValues)
+	if err != nil {
+		return nil, err
+	}
+
+	var output btcutil.Amount
+	for _, txOut := range tx.Tx.TxOut {
+		output += btcutil.Amount(txOut.Value)
+	}
+	fee := tx.TotalInput - output
+	minFee := origFee + txrules.FeeForSerializeSize(relayFee,
+		txrules.VirtualSize(tx.Tx))
+	if fee < minFee {
+		return nil, ErrFeeRateTooLow
+	}
+
+	// Publish the replacement before modifying the transaction store, so
+	// the original remains recorded if the replacement is rejected.
+	_, err = chainClient.SendRawTransaction(tx.Tx, false)
+	if err != nil {
+		return nil, err
+	}
+	err = w.replaceUnminedTx(details, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BumpFeeResult{Tx: tx.Tx, OrigFee: origFee, Fee: fee}, nil
+}
+
+// checkNoUnminedSpenders returns ErrTxHasSpenders if any unmined transaction
+// spends an output of the transaction with hash txHash.
+func (w *Wallet) checkNoUnminedSpenders(txHash *chainhash.Hash) error {
+	unmined, err := w.TxStore.UnminedTxs()
+	if err != nil {
+		return err
+	}
+	for _, tx := range unmined {
+		for _, txIn := range tx.TxIn {
+			if txIn.PreviousOutPoint.Hash == *txHash {
+				return ErrTxHasSpenders
+			}
+		}
+	}
+	return nil
+}
+
+// scriptAccount returns the account of the address paid by an output script.
+func (w *Wallet) scriptAccount(pkScript []byte) (uint32, error) {
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(pkScript, w.chainParams)
+	if err != nil {
+		return 0, err
+	}
+	if len(addrs) != 1 {
+		return 0, ErrTxNotOwned
+	}
+	return w.Manager.AddrAccount(addrs[0])
+}
+
+// makeFixedInputSource creates an input source which always includes the
+// fixed inputs, such as every input of a transaction being replaced, and adds
+// inputs from extra when their value does not meet the target.  The sequence
+// numbers of the fixed inputs are kept.
+func makeFixedInputSource(fixedInputs []*wire.TxIn, fixedValues []btcutil.Amount,
+	fixedScripts, fixedRedeemScripts [][]byte,
+	extra txauthor.InputSource) txauthor.InputSource {
+
+	var fixedTotal btcutil.Amount
+	for _, v := range fixedValues {
+		fixedTotal += v
+	}
+
+	return func(target btcutil.Amount) (btcutil.Amount, []*wire.TxIn,
+		[]btcutil.Amount, [][]byte, [][]byte, error) {
+
+		inputs := make([]*wire.TxIn, 0, len(fixedInputs))
+		for _, txIn := range fixedInputs {
+			input := wire.NewTxIn(&txIn.PreviousOutPoint, nil, nil)
+			input.Sequence = txIn.Sequence
+			inputs = append(inputs, input)
+		}
+		inputValues := append([]btcutil.Amount(nil), fixedValues...)
+		scripts := append([][]byte(nil), fixedScripts...)
+		redeemScripts := make([][]byte, len(fixedInputs))
+		copy(redeemScripts, fixedRedeemScripts)
+		if target <= fixedTotal {
+			return fixedTotal, inputs, inputValues, scripts,
+				redeemScripts, nil
+		}
+
+		total, extraInputs, extraValues, extraScripts, extraRedeemScripts,
+			err := extra(target - fixedTotal)
+		if err != nil {
+			return 0, nil, nil, nil, nil, err
+		}
+		inputs = append(inputs, extraInputs...)
+		inputValues = append(inputValues, extraValues...)
+		scripts = append(scripts, extraScripts...)
+		for i := range extraInputs {
+			var redeemScript []byte
+			if i < len(extraRedeemScripts) {
+				redeemScript = extraRedeemScripts[i]
+			}
+			redeemScripts = append(redeemScripts, redeemScript)
+		}
+		return fixedTotal + total, inputs, inputValues, scripts,
+			redeemScripts, nil
+	}
+}
+
+// replaceUnminedTx replaces the record of an unmined transaction with the
+// record of the transaction replacing it.  Credits of the original transaction
+// which are paid again by the replacement are recorded for the replacement,
+// as is its change output, and the labels of the original transaction and its
+// outputs are copied to the replacement.  The store is updated atomically.
+func (w *Wallet) replaceUnminedTx(orig *wtxmgr.TxDetails, tx *txauthor.AuthoredTx) error {
+	rec, err := wtxmgr.NewTxRecordFromMsgTx(tx.Tx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	credits := make(map[uint32]bool)
+	if tx.ChangeIndex >= 0 {
+		credits[uint32(tx.ChangeIndex)] = true
+	}
+	for _, credit := range orig.Credits {
+		if credit.Change {
+			continue
+		}
+		origOut := orig.MsgTx.TxOut[credit.Index]
+		for i, txOut := range tx.Tx.TxOut {
+			if _, ok := credits[uint32(i)]; ok ||
+				txOut.Value != origOut.Value ||
+				!bytes.Equal(txOut.PkScript, origOut.PkScript) {
+				continue
+			}
+			credits[uint32(i)] = false
+			break
+		}
+	}
+
+	// Labels are copied to the replacement and the outputs paying the
+	// same scripts.
+	outputLabels := make(map[uint32]string)
+	for index, label := range orig.OutputLabels {
+		origOut := orig.MsgTx.TxOut[index]
+		for i, txOut := range tx.Tx.TxOut {
+			if !bytes.Equal(txOut.PkScript, origOut.PkScript) {
+				continue
+			}
+			outputLabels[uint32(i)] = label
+			break
+		}
+	}
+
+	return w.TxStore.ReplaceUnminedTx(&orig.TxRecord, rec, credits,
+		orig.Label, outputLabels)
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/wallet/txrules"
+)
+
+// waitForTx waits until the transaction is recorded by the wallet.
+func waitForTx(t *testing.T, w *Wallet, txHash chainhash.Hash) {
+	waitFor(t, "transaction "+txHash.String(), func() bool {
+		details, err := w.TxStore.TxDetails(&txHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return details != nil
+	})
+}
+
+// TestBumpFee ensures only unmined, replaceable transactions without unmined
+// spenders are replaced, the replacement pays at least the original fee and
+// the relay fee of its own size, and the fee is paid by reducing the change of
+// the original transaction.
+func TestBumpFee(t *testing.T) {
+	t.Parallel()
+
+	w, client, teardown := newTestWallet(t)
+	defer teardown()
+	err := w.Unlock(testPrivPass, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addrs := newTestAddresses(t, w, 3)
+	syncTestWallet(t, w, client)
+	funding := client.payTx(t, addrs[0], 2e8)
+	client.mineBlock(t, funding)
+	other := client.payTx(t, addrs[1], 1e8)
+	client.mineBlock(t, other)
+	waitForSync(t, w, client)
+	fundingHash := funding.TxHash()
+	otherHash := other.TxHash()
+	waitForUnspent(t, w, *wire.NewOutPoint(&fundingHash, 0))
+	waitForUnspent(t, w, *wire.NewOutPoint(&otherHash, 0))
+
+	_, err = w.BumpFee(&chainhash.Hash{}, 0)
+	if err != ErrTxNotFound {
+		t.Errorf("bumping an unknown transaction failed with error %v, "+
+			"expected %v", err, ErrTxNotFound)
+	}
+
+	// A transaction with final input sequence numbers does not signal
+	// replaceability.
+	final := wire.NewMsgTx(wire.TxVersion)
+	final.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&otherHash, 0), nil, nil))
+	final.AddTxOut(wire.NewTxOut(0.99e8, []byte{txscript.OP_TRUE}))
+	client.AddUnminedTx(final)
+	finalHash := final.TxHash()
+	waitForTx(t, w, finalHash)
+	_, err = w.BumpFee(&finalHash, 0)
+	if err != ErrTxNotReplaceable {
+		t.Errorf("bumping a final transaction failed with error %v, "+
+			"expected %v", err, ErrTxNotReplaceable)
+	}
+
+	// The wallet pays another key, and the fee rate of the payment is the
+	// rate the replacement must exceed by the relay fee.
+	_, payeeAddrs := dumpTestKey(t, 1)
+	payee, err := btcutil.DecodeAddress(payeeAddrs[0], testParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payeeScript, err := txscript.PayToAddrScript(payee)
+	if err != nil {
+		t.Fatal(err)
+	}
+	origHash, err := w.SendOutputs([]*wire.TxOut{
+		wire.NewTxOut(5e7, payeeScript),
+	}, 0, 1, 1e4, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	details, err := w.TxStore.TxDetails(origHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := &details.MsgTx
+	if len(orig.TxIn) != 1 || len(orig.TxOut) != 2 {
+		t.Fatalf("payment has %d inputs and %d outputs, expected 1 "+
+			"input and 2 outputs", len(orig.TxIn), len(orig.TxOut))
+	}
+	var changeScript []byte
+	var origChange btcutil.Amount
+	for _, credit := range details.Credits {
+		if credit.Change {
+			changeScript = orig.TxOut[credit.Index].PkScript
+			origChange = credit.Amount
+		}
+	}
+	if changeScript == nil {
+		t.Fatal("payment has no change")
+	}
+	origFee := 2e8 - 5e7 - origChange
+	relayFee := w.RelayFee()
+	minFeeRate := origFee*1000/btcutil.Amount(txrules.VirtualSize(orig)) +
+		relayFee
+	_, err = w.BumpFee(origHash, minFeeRate-1)
+	if err != ErrFeeRateTooLow {
+		t.Errorf("bumping at a fee rate below %v failed with error %v, "+
+			"expected %v", minFeeRate, err, ErrFeeRateTooLow)
+	}
+
+	// The replacement spends the same inputs, pays the payee again, and
+	// pays the increased fee from the change.
+	result, err := w.BumpFee(origHash, 3e4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repl := result.Tx
+	replHash := repl.TxHash()
+	if result.OrigFee != origFee {
+		t.Errorf("original fee %v, expected %v", result.OrigFee, origFee)
+	}
+	minFee := origFee + txrules.FeeForSerializeSize(relayFee,
+		txrules.VirtualSize(repl))
+	if result.Fee < minFee {
+		t.Errorf("replacement fee %v is less than the minimum fee %v",
+			result.Fee, minFee)
+	}
+	if len(repl.TxIn) != 1 {
+		t.Fatalf("replacement has %d inputs, expected 1", len(repl.TxIn))
+	}
+	txIn := repl.TxIn[0]
+	if txIn.PreviousOutPoint != orig.TxIn[0].PreviousOutPoint {
+		t.Errorf("replacement spends %v, expected %v",
+			txIn.PreviousOutPoint, orig.TxIn[0].PreviousOutPoint)
+	}
+	if txIn.Sequence != txrules.MaxRBFSequence {
+		t.Errorf("replacement input has sequence %d, expected %d",
+			txIn.Sequence, txrules.MaxRBFSequence)
+	}
+	if len(repl.TxOut) != 2 {
+		t.Fatalf("replacement has %d outputs, expected 2",
+			len(repl.TxOut))
+	}
+	changeIndex := -1
+	for i, txOut := range repl.TxOut {
+		switch {
+		case bytes.Equal(txOut.PkScript, payeeScript):
+			if txOut.Value != 5e7 {
+				t.Errorf("replacement pays %v to the payee, "+
+					"expected %v", btcutil.Amount(txOut.Value),
+					btcutil.Amount(5e7))
+			}
+		case bytes.Equal(txOut.PkScript, changeScript):
+			changeIndex = i
+			wantChange := origChange - (result.Fee - origFee)
+			if btcutil.Amount(txOut.Value) != wantChange {
+				t.Errorf("replacement change %v, expected %v",
+					btcutil.Amount(txOut.Value), wantChange)
+			}
+		default:
+			t.Errorf("replacement pays unexpected output script %x",
+				txOut.PkScript)
+		}
+	}
+	if changeIndex == -1 {
+		t.Fatal("replacement has no change")
+	}
+
+	// The replacement is published and recorded in place of the original.
+	sent := client.SentTransactions()
+	if len(sent) == 0 || sent[len(sent)-1].TxHash() != replHash {
+		t.Error("replacement was not published")
+	}
+	details, err = w.TxStore.TxDetails(origHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if details != nil {
+		t.Error("original transaction is still recorded")
+	}
+	replChange := *wire.NewOutPoint(&replHash, uint32(changeIndex))
+	waitForUnspent(t, w, replChange)
+
+	// A replacement with an unmined spender may not be replaced.
+	childScript, err := txscript.PayToAddrScript(addrs[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	child := wire.NewMsgTx(wire.TxVersion)
+	child.AddTxIn(wire.NewTxIn(&replChange, nil, nil))
+	child.TxIn[0].Sequence = txrules.MaxRBFSequence
+	child.AddTxOut(wire.NewTxOut(repl.TxOut[changeIndex].Value-1e5,
+		childScript))
+	client.AddUnminedTx(child)
+	childHash := child.TxHash()
+	waitForUnspent(t, w, *wire.NewOutPoint(&childHash, 0))
+	_, err = w.BumpFee(&replHash, 0)
+	if err != ErrTxHasSpenders {
+		t.Errorf("bumping a transaction with unmined spenders failed "+
+			"with error %v, expected %v", err, ErrTxHasSpenders)
+	}
+
+	// A mined transaction may not be replaced.
+	client.mineBlock(t, repl, child)
+	waitForTxHeight(t, w, replHash, 3)
+	_, err = w.BumpFee(&replHash, 0)
+	if err != ErrTxMined {
+		t.Errorf("bumping a mined transaction failed with error %v, "+
+			"expected %v", err, ErrTxMined)
+	}
+}
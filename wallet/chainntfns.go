@@ -19,33 +19,27 @@ func (w *Wallet) handleChainNotifications() {
 		return
 	}
 
-	sync := func(w *Wallet) {
-		// At the moment there is no recourse if the rescan fails for
-		// some reason, however, the wallet will not be marked synced
-		// and many methods will error early since the wallet is known
-		// to be out of date.
-		err := w.syncWithChain()
-		if err != nil && !w.ShuttingDown() {
-			log.Warnf("Unable to synchronize wallet to chain: %v", err)
-		}
-	}
-
 	for n := range chainClient.Notifications() {
 		var err error
 		switch n := n.(type) {
 		case chain.ClientConnected:
-			go sync(w)
+			go w.resync()
 		case chain.BlockConnected:
-			w.connectBlock(wtxmgr.BlockMeta(n))
+			err = w.connectBlock(wtxmgr.BlockMeta(n))
 		case chain.BlockDisconnected:
 			err = w.disconnectBlock(wtxmgr.BlockMeta(n))
 		case chain.RelevantTx:
 			err = w.addRelevantTx(n.TxRecord, n.Block)
 
 		// The following are handled by the wallet's rescan
-		// goroutines, so just pass them there.
+		// goroutines, so just pass them there.  The rescan goroutines
+		// exit when the wallet is stopped, in which case the
+		// notification is dropped.
 		case *chain.RescanProgress, *chain.RescanFinished:
-			w.rescanNotifications <- n
+			select {
+			case w.rescanNotifications <- n:
+			case <-w.quitChan():
+			}
 		}
 		if err != nil {
 			log.Errorf("Cannot handle chain server "+
@@ -55,10 +49,56 @@ func (w *Wallet) handleChainNotifications() {
 	w.wg.Done()
 }
 
+// resync synchronizes the wallet with the chain server, logging any failure.
+func (w *Wallet) resync() {
+	// At the moment there is no recourse if the rescan fails for some
+	// reason, however, the wallet will not be marked synced and many
+	// methods will error early since the wallet is known to be out of
+	// date.
+	err := w.syncWithChain()
+	if err != nil && !w.ShuttingDown() {
+		log.Warnf("Unable to synchronize wallet to chain: %v", err)
+	}
+}
+
 // connectBlock handles a chain server notification by marking a wallet
 // that's currently in-sync with the chain server as being synced up to
 // the passed block.
-func (w *Wallet) connectBlock(b wtxmgr.BlockMeta) {
+//
+// A block at or before the synced block is either already synced, in which
+// case it is ignored, or replaces a synced block whose disconnection was never
+// handled.  In the latter case a wallet in-sync with the chain server is
+// rolled back to the fork point and synced with the chain server again, since
+// the transactions of the blocks since the fork point must be rescanned.
+func (w *Wallet) connectBlock(b wtxmgr.BlockMeta) error {
+	if b.Height <= w.Manager.SyncedTo().Height {
+		hash, err := w.Manager.BlockHash(b.Height)
+		switch {
+		case err == nil && *hash == b.Hash:
+			return nil
+
+		case err == nil && w.ChainSynced():
+			chainClient, err := w.requireChainClient()
+			if err != nil {
+				return err
+			}
+			forkPoint, err := w.findForkPoint(chainClient)
+			if err != nil {
+				return err
+			}
+			err = w.rollback(forkPoint)
+			if err != nil {
+				return err
+			}
+			w.SetChainSynced(false)
+			go w.resync()
+			return nil
+
+		case err != nil && !waddrmgr.IsError(err, waddrmgr.ErrBlockNotFound):
+			return err
+		}
+	}
+
 	bs := waddrmgr.BlockStamp{
 		Height: b.Height,
 		Hash:   b.Hash,
@@ -71,6 +111,8 @@ func (w *Wallet) connectBlock(b wtxmgr.BlockMeta) {
 
 	// Notify interested clients of the connected block.
 	w.NtfnServer.notifyAttachedBlock(&b)
+
+	return nil
 }
 
 // disconnectBlock handles a chain server reorganize by rolling back all
@@ -81,29 +123,46 @@ func (w *Wallet) disconnectBlock(b wtxmgr.BlockMeta) error {
 		return nil
 	}
 
-	// Disconnect the last seen block from the manager if it matches the
-	// removed block.
-	iter := w.Manager.NewIterateRecentBlocks()
-	if iter != nil && iter.BlockStamp().Hash == b.Hash {
-		if iter.Prev() {
-			prev := iter.BlockStamp()
-			w.Manager.SetSyncedTo(&prev)
-			err := w.TxStore.Rollback(prev.Height + 1)
+	// Disconnect the block from the manager if it matches the removed
+	// block, rolling back to its parent.  The parent's hash is unknown when
+	// the wallet was synced through it by a rescan which did not record its
+	// hash, in which case the wallet is rolled back to the latest synced
+	// block which remains in the main chain, and synced with the chain
+	// server again to rescan the main chain blocks after it.
+	hash, err := w.Manager.BlockHash(b.Height)
+	if err != nil && !waddrmgr.IsError(err, waddrmgr.ErrBlockNotFound) {
+		return err
+	}
+	if err == nil && *hash == b.Hash {
+		prev := &waddrmgr.BlockStamp{Height: b.Height - 1}
+		resync := false
+		prevHash, err := w.Manager.BlockHash(prev.Height)
+		switch {
+		case err == nil:
+			prev.Hash = *prevHash
+
+		case waddrmgr.IsError(err, waddrmgr.ErrBlockNotFound):
+			chainClient, err := w.requireChainClient()
 			if err != nil {
 				return err
 			}
-		} else {
-			// The reorg is farther back than the recently-seen list
-			// of blocks has recorded, so set it to unsynced which
-			// will in turn lead to a rescan from either the
-			// earliest blockstamp the addresses in the manager are
-			// known to have been created.
-			w.Manager.SetSyncedTo(nil)
-			// Rollback everything but the genesis block.
-			err := w.TxStore.Rollback(1)
+			prev, err = w.findForkPoint(chainClient)
 			if err != nil {
 				return err
 			}
+			resync = true
+
+		default:
+			return err
+		}
+
+		err = w.rollback(prev)
+		if err != nil {
+			return err
+		}
+		if resync {
+			w.SetChainSynced(false)
+			go w.resync()
 		}
 	}
 
@@ -181,6 +240,10 @@ func (w *Wallet) addRelevantTx(rec *wtxmgr.TxRecord, block *wtxmgr.BlockMeta) er
 					return err
 				}
 				log.Debugf("Marked address %v used", addr)
+				err = w.extendWatchOnlyAccount(ma, block)
+				if err != nil {
+					return err
+				}
 				continue
 			}
 
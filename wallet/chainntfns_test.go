@@ -0,0 +1,210 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+)
+
+// waitForTxHeight waits until the transaction is recorded by the wallet as
+// mined at the height, or as unmined when the height is -1.
+func waitForTxHeight(t *testing.T, w *Wallet, txHash chainhash.Hash,
+	height int32) {
+
+	desc := fmt.Sprintf("transaction %v at height %d", txHash, height)
+	waitFor(t, desc, func() bool {
+		details, err := w.TxStore.TxDetails(&txHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return details != nil && details.Block.Height == height
+	})
+}
+
+// checkBlockHashes checks the wallet has recorded the hash of every main
+// chain block of the mock chain through the block it is synced to.
+func checkBlockHashes(t *testing.T, w *Wallet, client *testClient) {
+	synced := w.Manager.SyncedTo()
+	for height := int32(0); height <= synced.Height; height++ {
+		hash, err := w.Manager.BlockHash(height)
+		if err != nil {
+			t.Fatalf("no hash recorded for height %d: %v", height, err)
+		}
+		mainHash, err := client.GetBlockHash(int64(height))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *hash != *mainHash {
+			t.Errorf("recorded hash %v for height %d, expected %v",
+				hash, height, mainHash)
+		}
+	}
+}
+
+// TestSyncWithChain ensures a wallet syncing with a chain server rescans the
+// blocks attached while it was offline, recording the hashes of the rescanned
+// blocks.
+func TestSyncWithChain(t *testing.T) {
+	t.Parallel()
+
+	w, client, teardown := newTestWallet(t)
+	defer teardown()
+
+	addr, err := w.NewAddress(0, waddrmgr.PubKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.mineBlocks(t, 1)
+	payment := client.payTx(t, addr, 1e6)
+	client.mineBlock(t, payment)
+	client.mineBlocks(t, 2)
+
+	syncTestWallet(t, w, client)
+	paymentHash := payment.TxHash()
+	waitForUnspent(t, w, *wire.NewOutPoint(&paymentHash, 0))
+	waitForTxHeight(t, w, paymentHash, 2)
+	checkBlockHashes(t, w, client)
+}
+
+// TestConnectDisconnectBlocks ensures blocks attached to and detached from
+// the main chain after the wallet is synced are connected and disconnected,
+// moving the transactions of disconnected blocks back to the unconfirmed
+// pool.
+func TestConnectDisconnectBlocks(t *testing.T) {
+	t.Parallel()
+
+	w, client, teardown := newTestWallet(t)
+	defer teardown()
+
+	addr, err := w.NewAddress(0, waddrmgr.PubKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	syncTestWallet(t, w, client)
+
+	client.mineBlocks(t, 2)
+	payment := client.payTx(t, addr, 1e6)
+	client.mineBlock(t, payment)
+	waitForSync(t, w, client)
+	paymentHash := payment.TxHash()
+	waitForTxHeight(t, w, paymentHash, 3)
+	checkBlockHashes(t, w, client)
+
+	// Disconnecting the block of the payment rolls the wallet back to its
+	// parent, keeping the payment as unmined.
+	err = client.DisconnectBlocks(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForSync(t, w, client)
+	waitForTxHeight(t, w, paymentHash, -1)
+	checkUnspentCount(t, w, 1)
+
+	// The payment is mined again by a block replacing the disconnected
+	// one.
+	client.mineBlock(t, payment)
+	waitForSync(t, w, client)
+	waitForTxHeight(t, w, paymentHash, 3)
+	checkBlockHashes(t, w, client)
+}
+
+// TestConnectReplacedBlock ensures a connected block replacing a synced block
+// whose disconnection was never handled rolls the wallet back to the fork
+// point and resyncs it with the chain server.
+func TestConnectReplacedBlock(t *testing.T) {
+	t.Parallel()
+
+	w, client, teardown := newTestWallet(t)
+	defer teardown()
+
+	addr, err := w.NewAddress(0, waddrmgr.PubKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.mineBlocks(t, 2)
+	syncTestWallet(t, w, client)
+
+	// Record a payment mined in a block at height 3 which the chain server
+	// has since replaced, as if the notification disconnecting it was
+	// missed.
+	payment := client.payTx(t, addr, 1e6)
+	rec, err := wtxmgr.NewTxRecordFromMsgTx(payment, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	replaced := wtxmgr.BlockMeta{
+		Block: wtxmgr.Block{Hash: chainhash.Hash{3}, Height: 3},
+		Time:  time.Now(),
+	}
+	err = w.addRelevantTx(rec, &replaced)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.Manager.SetSyncedTo(&waddrmgr.BlockStamp{
+		Hash:   replaced.Hash,
+		Height: replaced.Height,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client.mineBlock(t)
+	waitForSync(t, w, client)
+	waitForTxHeight(t, w, payment.TxHash(), -1)
+	checkBlockHashes(t, w, client)
+}
+
+// TestRescan ensures a rescan finds the transactions of the rescanned blocks
+// paying to addresses the chain server was not notifying the wallet of,
+// without changing the block the wallet is synced to.
+func TestRescan(t *testing.T) {
+	t.Parallel()
+
+	w, client, teardown := newTestWallet(t)
+	defer teardown()
+
+	_, err := w.NewAddress(0, waddrmgr.PubKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	syncTestWallet(t, w, client)
+
+	// Addresses derived directly from the address manager are not watched
+	// by the chain server until rescanned.
+	managed, err := w.Manager.NextExternalAddresses(0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := managed[0].Address()
+	payment := client.payTx(t, addr, 1e6)
+	block := client.mineBlock(t, payment)
+	client.mineBlocks(t, 2)
+	waitForSync(t, w, client)
+	checkUnspentCount(t, w, 0)
+
+	err = <-w.SubmitRescan(&RescanJob{
+		Addrs:      []btcutil.Address{addr},
+		BlockStamp: waddrmgr.BlockStamp{Hash: block.BlockHash(), Height: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	paymentHash := payment.TxHash()
+	waitForUnspent(t, w, *wire.NewOutPoint(&paymentHash, 0))
+	waitForTxHeight(t, w, paymentHash, 1)
+	waitForSync(t, w, client)
+	if synced := w.Manager.SyncedTo(); synced.Height != 3 {
+		t.Errorf("synced to height %d after rescan, expected 3",
+			synced.Height)
+	}
+}
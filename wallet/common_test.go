@@ -0,0 +1,240 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"encoding/binary"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/chain"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/walletdb"
+	_ "github.com/btcsuite/btcwallet/walletdb/memdb"
+)
+
+var (
+	// testSeed is the master seed of the test wallets.
+	testSeed = []byte{
+		0x2a, 0x64, 0xdf, 0x08, 0x5e, 0xef, 0xed, 0xd8, 0xbf,
+		0xdb, 0xb3, 0x31, 0x76, 0xb5, 0xba, 0x2e, 0x62, 0xe8,
+		0xbe, 0x8b, 0x56, 0xc8, 0x83, 0x77, 0x95, 0x59, 0x8b,
+		0xb6, 0xc4, 0x40, 0xc0, 0x64,
+	}
+
+	testPubPass  = []byte("public")
+	testPrivPass = []byte("private")
+
+	// fastScrypt are parameters used throughout the tests to speed up the
+	// scrypt operations.
+	fastScrypt = &waddrmgr.ScryptOptions{
+		N: 16,
+		R: 8,
+		P: 1,
+	}
+
+	testParams = &chaincfg.RegressionNetParams
+)
+
+// waitTimeout is the time tests wait for the wallet to handle notifications
+// of the mock chain.
+const waitTimeout = 10 * time.Second
+
+// testClient is a mock chain client which can be made to reject published
+// transactions.
+type testClient struct {
+	*chain.MockClient
+
+	mtx     sync.Mutex
+	sendErr error
+	nonce   uint32
+}
+
+// setSendError sets the error returned by SendRawTransaction.  A nil error
+// publishes transactions to the mock chain again.
+func (c *testClient) setSendError(err error) {
+	c.mtx.Lock()
+	c.sendErr = err
+	c.mtx.Unlock()
+}
+
+// SendRawTransaction publishes the transaction to the mock chain, unless an
+// error was set with setSendError.
+func (c *testClient) SendRawTransaction(tx *wire.MsgTx,
+	allowHighFees bool) (*chainhash.Hash, error) {
+
+	c.mtx.Lock()
+	err := c.sendErr
+	c.mtx.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return c.MockClient.SendRawTransaction(tx, allowHighFees)
+}
+
+// nextNonce returns a number which is unique for every call, used to create
+// distinct blocks and transactions.
+func (c *testClient) nextNonce() uint32 {
+	c.mtx.Lock()
+	c.nonce++
+	nonce := c.nonce
+	c.mtx.Unlock()
+	return nonce
+}
+
+// mineBlock connects a block with the transactions to the tip of the mock
+// chain.
+func (c *testClient) mineBlock(t *testing.T, txs ...*wire.MsgTx) *wire.MsgBlock {
+	tipHash, height, err := c.GetBestBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce := c.nextNonce()
+	coinbaseScript := make([]byte, 8)
+	binary.LittleEndian.PutUint32(coinbaseScript, uint32(height+1))
+	binary.LittleEndian.PutUint32(coinbaseScript[4:], nonce)
+	coinbase := wire.NewMsgTx(wire.TxVersion)
+	coinbase.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Index: wire.MaxPrevOutIndex},
+		SignatureScript:  coinbaseScript,
+		Sequence:         wire.MaxTxInSequenceNum,
+	})
+	coinbase.AddTxOut(wire.NewTxOut(50e8, []byte{txscript.OP_TRUE}))
+
+	block := &wire.MsgBlock{
+		Header: wire.BlockHeader{
+			Version:   1,
+			PrevBlock: *tipHash,
+			Timestamp: time.Unix(1500000000+int64(height+1)*600, 0),
+			Bits:      testParams.PowLimitBits,
+			Nonce:     nonce,
+		},
+		Transactions: append([]*wire.MsgTx{coinbase}, txs...),
+	}
+	err = c.ConnectBlock(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return block
+}
+
+// mineBlocks connects n empty blocks to the tip of the mock chain.
+func (c *testClient) mineBlocks(t *testing.T, n int) {
+	for i := 0; i < n; i++ {
+		c.mineBlock(t)
+	}
+}
+
+// payTx returns a transaction paying each amount to the address.  It spends a
+// made up output which is unknown to the wallet.
+func (c *testClient) payTx(t *testing.T, addr btcutil.Address,
+	amounts ...btcutil.Amount) *wire.MsgTx {
+
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var prevHash chainhash.Hash
+	binary.LittleEndian.PutUint32(prevHash[:], c.nextNonce())
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&prevHash, 0), nil, nil))
+	for _, amount := range amounts {
+		tx.AddTxOut(wire.NewTxOut(int64(amount), pkScript))
+	}
+	return tx
+}
+
+// newTestWallet creates and opens a wallet of the test seed in a memory
+// database, and a mock chain client holding only the genesis block.  The
+// wallet is started, but does not sync with the client until syncTestWallet is
+// called.
+func newTestWallet(t *testing.T) (*Wallet, *testClient, func()) {
+	db, err := walletdb.Create("memdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = CreateWithOptions(db, testPubPass, testPrivPass, testSeed,
+		testParams, fastScrypt)
+	if err != nil {
+		db.Close()
+		t.Fatal(err)
+	}
+	w, err := Open(db, testPubPass, nil, testParams)
+	if err != nil {
+		db.Close()
+		t.Fatal(err)
+	}
+	w.Start()
+
+	client := &testClient{MockClient: chain.NewMockClient(testParams)}
+	teardown := func() {
+		w.Stop()
+		w.WaitForShutdown()
+		client.Stop()
+		client.WaitForShutdown()
+		db.Close()
+	}
+	return w, client, teardown
+}
+
+// syncTestWallet syncs the wallet with the client, and waits until the
+// wallet is synced to the tip of the mock chain.  The wallet must have
+// addresses, since the wallet is only marked synced by a rescan.
+func syncTestWallet(t *testing.T, w *Wallet, client *testClient) {
+	w.SynchronizeRPC(client)
+	err := client.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForSync(t, w, client)
+}
+
+// waitFor waits until cond returns true, failing the test after waitTimeout.
+func waitFor(t *testing.T, desc string, cond func() bool) {
+	deadline := time.Now().Add(waitTimeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", desc)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// waitForSync waits until the wallet is marked synced to the tip of the mock
+// chain.
+func waitForSync(t *testing.T, w *Wallet, client *testClient) {
+	waitFor(t, "the wallet to sync", func() bool {
+		hash, height, err := client.GetBestBlock()
+		if err != nil {
+			t.Fatal(err)
+		}
+		synced := w.Manager.SyncedTo()
+		return w.ChainSynced() && synced.Height == height &&
+			synced.Hash == *hash
+	})
+}
+
+// waitForUnspent waits until the output is recorded as an unspent wallet
+// output.
+func waitForUnspent(t *testing.T, w *Wallet, op wire.OutPoint) {
+	waitFor(t, "unspent output "+op.String(), func() bool {
+		unspent, err := w.TxStore.UnspentOutputs()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range unspent {
+			if unspent[i].OutPoint == op {
+				return true
+			}
+		}
+		return false
+	})
+}
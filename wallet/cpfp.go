@@ -0,0 +1,271 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"errors"
+	"time"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/chain"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wallet/internal/txsizes"
+	"github.com/btcsuite/btcwallet/wallet/txauthor"
+	"github.com/btcsuite/btcwallet/wallet/txrules"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+)
+
+var (
+	// ErrOutputNotEligible describes an error where an output can not be
+	// spent by a child transaction because it is not an unspent and
+	// unlocked output controlled by the wallet.
+	ErrOutputNotEligible = errors.New("output is not an unspent and " +
+		"unlocked wallet output")
+
+	// ErrOutputTooSmall describes an error where the value of an output is
+	// too small to pay the fee of a child transaction.
+	ErrOutputTooSmall = errors.New("output value is too small to pay " +
+		"the fee")
+)
+
+type (
+	cpfpRequest struct {
+		outPoint wire.OutPoint
+		feeRate  btcutil.Amount
+		resp     chan cpfpResponse
+	}
+	cpfpResponse struct {
+		result *CPFPResult
+		err    error
+	}
+)
+
+// CPFPResult describes a child transaction created to pay for an unmined
+// parent transaction.
+type CPFPResult struct {
+	Tx        *wire.MsgTx
+	Fee       btcutil.Amount
+	ParentFee btcutil.Amount
+}
+
+// CPFP creates and publishes a child transaction spending the unmined wallet
+// output outPoint to a new internal address of the same account, paying a fee
+// which raises the combined fee rate of the child and its parent to feeRate
+// per kB of virtual size, or the wallet's estimated fee rate when feeRate is
+// zero.  This is used to speed up the confirmation of unmined transactions
+// paying the wallet which can not be replaced by the wallet.
+//
+// When the fee of the parent can not be determined, because the values of the
+// outputs it spends are unknown to both the wallet and the chain server, the
+// parent is assumed to pay no fee.  Child transactions are created serialized
+// with all other transaction creation, and the wallet must be unlocked to sign
+// the child.
+func (w *Wallet) CPFP(outPoint wire.OutPoint, feeRate btcutil.Amount) (*CPFPResult, error) {
+	req := cpfpRequest{
+		outPoint: outPoint,
+		feeRate:  feeRate,
+		resp:     make(chan cpfpResponse),
+	}
+	w.cpfpRequests <- req
+	resp := <-req.resp
+	return resp.result, resp.err
+}
+
+// cpfp implements CPFP.  It must only be called by the txCreator goroutine.
+func (w *Wallet) cpfp(outPoint *wire.OutPoint, feeRate btcutil.Amount) (*CPFPResult, error) {
+	heldUnlock, err := w.HoldUnlock()
+	if err != nil {
+		return nil, err
+	}
+	defer heldUnlock.Release()
+
+	chainClient, err := w.requireChainClient()
+	if err != nil {
+		return nil, err
+	}
+
+	parent, err := w.TxStore.TxDetails(&outPoint.Hash)
+	if err != nil {
+		return nil, err
+	}
+	if parent == nil {
+		return nil, ErrTxNotFound
+	}
+	if parent.Block.Height != -1 {
+		return nil, ErrTxMined
+	}
+	if outPoint.Index >= uint32(len(parent.MsgTx.TxOut)) {
+		return nil, ErrOutputNotEligible
+	}
+
+	// Unmined credits are not normally eligible to be spent, so they are
+	// explicitly opted in by requiring no confirmations.  This still
+	// excludes locked outputs and outputs of other accounts.
+	account, err := w.scriptAccount(parent.MsgTx.TxOut[outPoint.Index].PkScript)
+	if err != nil {
+		return nil, ErrOutputNotEligible
+	}
+	bs, err := chainClient.BlockStamp()
+	if err != nil {
+		return nil, err
+	}
+	eligible, err := w.findEligibleOutputs(account, 0, bs)
+	if err != nil {
+		return nil, err
+	}
+	var credit *wtxmgr.Credit
+	for i := range eligible {
+		if eligible[i].OutPoint == *outPoint {
+			credit = &eligible[i]
+			break
+		}
+	}
+	if credit == nil {
+		return nil, ErrOutputNotEligible
+	}
+
+	if feeRate == 0 {
+		feeRate = w.FeeRate()
+	}
+	parentFee := w.parentFee(parent)
+
+	childSize := txauthor.EstimateVirtualSize([][]byte{credit.PkScript},
+		[][]byte{w.RedeemScript(credit.PkScript)}, nil, true)
+	fee := cpfpFee(feeRate, txrules.VirtualSize(&parent.MsgTx), childSize,
+		parentFee)
+	outputAmount := credit.Amount - fee
+	if outputAmount <= 0 || txrules.IsDustAmount(outputAmount,
+		txsizes.P2PKHPkScriptSize, w.RelayFee()) {
+		return nil, ErrOutputTooSmall
+	}
+
+	// As in txToOutputs, outputs of the imported account are paid to the
+	// default account.
+	changeAccount := account
+	if account == waddrmgr.ImportedAddrAccount {
+		changeAccount = 0
+	}
+	changeAddr, err := w.NewChangeAddress(changeAccount)
+	if err != nil {
+		return nil, err
+	}
+	changeScript, err := txscript.PayToAddrScript(changeAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	txIn := wire.NewTxIn(outPoint, nil, nil)
+	txIn.Sequence = txrules.MaxRBFSequence
+	tx := &txauthor.AuthoredTx{
+		Tx: &wire.MsgTx{
+			Version: wire.TxVersion,
+			TxIn:    []*wire.TxIn{txIn},
+			TxOut: []*wire.TxOut{
+				wire.NewTxOut(int64(outputAmount), changeScript),
+			},
+		},
+		PrevScripts:     [][]byte{credit.PkScript},
+		PrevInputValues: []btcutil.Amount{credit.Amount},
+		TotalInput:      credit.Amount,
+		ChangeIndex:     0,
+	}
+	err = txauthor.SetTimeLocks(tx.Tx, tx.PrevScripts, secretSource{w.Manager})
+	if err != nil {
+		return nil, err
+	}
+	err = tx.AddAllInputScripts(secretSource{w.Manager})
+	if err != nil {
+		return nil, err
+	}
+	err = validateMsgTx(tx.Tx, tx.PrevScripts, tx.PrevInputValues)
+	if err != nil {
+		return nil, err
+	}
+
+	// Publish the child before recording it, as bumpFee does with
+	// replacements, so a rejected child does not leave the parent output
+	// looking spent.
+	_, err = chainClient.SendRawTransaction(tx.Tx, false)
+	if err != nil {
+		return nil, err
+	}
+	rec, err := wtxmgr.NewTxRecordFromMsgTx(tx.Tx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	err = w.TxStore.InsertUnminedTx(rec, map[uint32]bool{0: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return &CPFPResult{Tx: tx.Tx, Fee: fee, ParentFee: parentFee}, nil
+}
+
+// cpfpFee returns the fee of a child transaction of childSize virtual bytes
+// which raises the fee rate of the package of the child and a parent of
+// parentSize virtual bytes, paying parentFee, to feeRate per kB.  The child
+// pays for the virtual size of both transactions less the fee already paid by
+// the parent, and at least feeRate for its own size.
+func cpfpFee(feeRate btcutil.Amount, parentSize, childSize int,
+	parentFee btcutil.Amount) btcutil.Amount {
+
+	fee := txrules.FeeForSerializeSize(feeRate, parentSize+childSize) -
+		parentFee
+	if minFee := txrules.FeeForSerializeSize(feeRate, childSize); fee < minFee {
+		fee = minFee
+	}
+	return fee
+}
+
+// parentFee returns the fee paid by an unmined transaction.  The values of
+// the outputs it spends are looked up in the wallet's transaction history, or
+// requested from a consensus RPC server for outputs unknown to the wallet.
+// Zero is returned when the fee can not be determined.
+func (w *Wallet) parentFee(parent *wtxmgr.TxDetails) btcutil.Amount {
+	// Other chain backends are unable to look up arbitrary transactions.
+	backend := w.ChainClient()
+	if c, ok := backend.(*chain.MuxClient); ok {
+		backend = c.Backend()
+	}
+	chainClient, _ := backend.(*chain.RPCClient)
+
+	var input btcutil.Amount
+	for _, txIn := range parent.MsgTx.TxIn {
+		prevOut := &txIn.PreviousOutPoint
+		var prevTx *wire.MsgTx
+		details, err := w.TxStore.TxDetails(&prevOut.Hash)
+		switch {
+		case err != nil:
+			return 0
+		case details != nil:
+			prevTx = &details.MsgTx
+		case chainClient == nil:
+			return 0
+		default:
+			tx, err := chainClient.GetRawTransaction(&prevOut.Hash)
+			if err != nil {
+				log.Debugf("Unable to determine fee of %v: %v",
+					parent.Hash, err)
+				return 0
+			}
+			prevTx = tx.MsgTx()
+		}
+		if prevOut.Index >= uint32(len(prevTx.TxOut)) {
+			return 0
+		}
+		input += btcutil.Amount(prevTx.TxOut[prevOut.Index].Value)
+	}
+
+	var output btcutil.Amount
+	for _, txOut := range parent.MsgTx.TxOut {
+		output += btcutil.Amount(txOut.Value)
+	}
+	if input < output {
+		return 0
+	}
+	return input - output
+}
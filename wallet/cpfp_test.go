@@ -0,0 +1,186 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wallet/txrules"
+)
+
+func TestCPFPFee(t *testing.T) {
+	const feeRate = 1e5
+	tests := []struct {
+		parentSize, childSize int
+		parentFee             btcutil.Amount
+		fee                   btcutil.Amount
+	}{
+		// A parent paying no fee is paid for entirely by the child.
+		0: {200, 110, 0, 31000},
+
+		// The fee of the parent is subtracted from the package fee.
+		1: {200, 110, 5000, 26000},
+		2: {225, 110, 10000, 23500},
+
+		// The child pays at least the fee rate for its own size when
+		// the parent already pays for the package.
+		3: {200, 110, 31000, 11000},
+		4: {200, 110, 1e6, 11000},
+	}
+	for i, test := range tests {
+		fee := cpfpFee(feeRate, test.parentSize, test.childSize,
+			test.parentFee)
+		if fee != test.fee {
+			t.Errorf("Test %d: Got fee %v, expected %v", i, fee,
+				test.fee)
+		}
+	}
+}
+
+// cpfpTestParent funds the wallet with a mined output and returns an unmined
+// parent transaction spending it, which pays 5e7 to another script, 4.99e7 to
+// the wallet, and a fee of 1e5.
+func cpfpTestParent(t *testing.T, w *Wallet, client *testClient) *wire.MsgTx {
+	addr, err := w.NewAddress(0, waddrmgr.PubKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changeAddr, err := w.NewAddress(0, waddrmgr.PubKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	syncTestWallet(t, w, client)
+
+	funding := client.payTx(t, addr, 1e8)
+	client.mineBlock(t, funding)
+	waitForSync(t, w, client)
+
+	changeScript, err := txscript.PayToAddrScript(changeAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fundingHash := funding.TxHash()
+	parent := wire.NewMsgTx(wire.TxVersion)
+	parent.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&fundingHash, 0), nil, nil))
+	parent.AddTxOut(wire.NewTxOut(5e7, []byte{txscript.OP_TRUE}))
+	parent.AddTxOut(wire.NewTxOut(4.99e7, changeScript))
+	client.AddUnminedTx(parent)
+	parentHash := parent.TxHash()
+	waitForUnspent(t, w, *wire.NewOutPoint(&parentHash, 1))
+	return parent
+}
+
+func TestParentFee(t *testing.T) {
+	w, client, teardown := newTestWallet(t)
+	defer teardown()
+
+	parent := cpfpTestParent(t, w, client)
+	parentHash := parent.TxHash()
+	details, err := w.TxStore.TxDetails(&parentHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee := w.parentFee(details); fee != 1e5 {
+		t.Errorf("Got parent fee %v, expected %v", fee, btcutil.Amount(1e5))
+	}
+
+	// The fee of a parent spending outputs unknown to the wallet can not
+	// be looked up from the mock chain client, and is assumed to be zero.
+	addr, err := w.NewAddress(0, waddrmgr.PubKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unknown := client.payTx(t, addr, 1e7)
+	client.AddUnminedTx(unknown)
+	unknownHash := unknown.TxHash()
+	waitForUnspent(t, w, *wire.NewOutPoint(&unknownHash, 0))
+	details, err = w.TxStore.TxDetails(&unknownHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee := w.parentFee(details); fee != 0 {
+		t.Errorf("Got parent fee %v for unknown inputs, expected 0", fee)
+	}
+}
+
+func TestCPFP(t *testing.T) {
+	w, client, teardown := newTestWallet(t)
+	defer teardown()
+
+	parent := cpfpTestParent(t, w, client)
+	parentHash := parent.TxHash()
+	parentOutPoint := *wire.NewOutPoint(&parentHash, 1)
+	err := w.Unlock(testPrivPass, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A child rejected by the chain server must not be recorded, leaving
+	// the parent output unspent.
+	client.setSendError(errors.New("rejected"))
+	_, err = w.CPFP(parentOutPoint, 1e5)
+	if err == nil {
+		t.Fatal("CPFP succeeded with a rejected child")
+	}
+	hashes, err := w.TxStore.UnminedTxHashes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hashes) != 1 || *hashes[0] != parentHash {
+		t.Fatalf("Expected only the parent to be unmined, found %v",
+			hashes)
+	}
+	waitForUnspent(t, w, parentOutPoint)
+	client.setSendError(nil)
+
+	const feeRate = 1e5
+	result, err := w.CPFP(parentOutPoint, feeRate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ParentFee != 1e5 {
+		t.Errorf("Got parent fee %v, expected %v", result.ParentFee,
+			btcutil.Amount(1e5))
+	}
+	sent := client.SentTransactions()
+	if len(sent) != 1 || sent[0].TxHash() != result.Tx.TxHash() {
+		t.Fatalf("Child transaction was not published")
+	}
+	child := result.Tx
+	if len(child.TxIn) != 1 || child.TxIn[0].PreviousOutPoint != parentOutPoint ||
+		len(child.TxOut) != 1 {
+		t.Fatalf("Unexpected child transaction %v", child)
+	}
+	if fee := 4.99e7 - btcutil.Amount(child.TxOut[0].Value); fee != result.Fee {
+		t.Errorf("Got child fee %v, expected %v", result.Fee, fee)
+	}
+
+	// The package of the parent and the signed child must pay at least
+	// the fee rate, and the child at least the fee rate for its own size.
+	childSize := txrules.VirtualSize(child)
+	packageSize := txrules.VirtualSize(parent) + childSize
+	if minFee := txrules.FeeForSerializeSize(feeRate, packageSize); result.Fee+result.ParentFee < minFee {
+		t.Errorf("Package fee %v is below %v", result.Fee+result.ParentFee,
+			minFee)
+	}
+	if minFee := txrules.FeeForSerializeSize(feeRate, childSize); result.Fee < minFee {
+		t.Errorf("Child fee %v is below %v", result.Fee, minFee)
+	}
+
+	// The child and its change are recorded.
+	childHash := child.TxHash()
+	details, err := w.TxStore.TxDetails(&childHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if details == nil || len(details.Credits) != 1 || !details.Credits[0].Change {
+		t.Fatalf("Child transaction and its change were not recorded")
+	}
+}
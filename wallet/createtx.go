@@ -6,47 +6,63 @@ package wallet
 
 import (
 	"fmt"
-	"sort"
 
 	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcutil"
 	"github.com/btcsuite/btcwallet/waddrmgr"
 	"github.com/btcsuite/btcwallet/wallet/txauthor"
+	"github.com/btcsuite/btcwallet/wallet/txrules"
 	"github.com/btcsuite/btcwallet/wtxmgr"
 )
 
-// byAmount defines the methods needed to satisify sort.Interface to
-// sort credits by their output amount.
-type byAmount []wtxmgr.Credit
-
-func (s byAmount) Len() int           { return len(s) }
-func (s byAmount) Less(i, j int) bool { return s[i].Amount < s[j].Amount }
-func (s byAmount) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
-
-func makeInputSource(eligible []wtxmgr.Credit) txauthor.InputSource {
-	// Pick largest outputs first.  This is only done for compatibility with
-	// previous tx creation code, not because it's a good idea.
-	sort.Sort(sort.Reverse(byAmount(eligible)))
-
-	// Current inputs and their total value.  These are closed over by the
-	// returned input source and reused across multiple calls.
-	currentTotal := btcutil.Amount(0)
-	currentInputs := make([]*wire.TxIn, 0, len(eligible))
-	currentScripts := make([][]byte, 0, len(eligible))
-
-	return func(target btcutil.Amount) (btcutil.Amount, []*wire.TxIn, [][]byte, error) {
-		for currentTotal < target && len(eligible) != 0 {
-			nextCredit := &eligible[0]
-			eligible = eligible[1:]
-			nextInput := wire.NewTxIn(&nextCredit.OutPoint, nil,nil)
-			currentTotal += nextCredit.Amount
-			currentInputs = append(currentInputs, nextInput)
-			currentScripts = append(currentScripts, nextCredit.PkScript)
+// makeInputSource creates an InputSource which selects inputs from eligible
+// credits using selector, or the txauthor.LargestFirst strategy if selector is
+// nil.
+func (w *Wallet) makeInputSource(eligible []wtxmgr.Credit, selector txauthor.CoinSelector) txauthor.InputSource {
+	coins := make([]txauthor.Coin, len(eligible))
+	for i := range eligible {
+		credit := &eligible[i]
+		coins[i] = txauthor.Coin{
+			OutPoint:     credit.OutPoint,
+			Value:        credit.Amount,
+			PkScript:     credit.PkScript,
+			RedeemScript: w.RedeemScript(credit.PkScript),
+			Height:       credit.Height,
 		}
-		return currentTotal, currentInputs, currentScripts, nil
 	}
+	return txauthor.MakeInputSource(coins, selector)
+}
+
+// RedeemScript returns the redeem script of a P2SH output script paying to a
+// wallet address, which is used to estimate the size of the input spending
+// it.  Nil is returned for other output scripts, and when the script can not
+// be looked up, such as for imported scripts while the wallet is locked.
+func (w *Wallet) RedeemScript(pkScript []byte) []byte {
+	if !txscript.IsPayToScriptHash(pkScript) {
+		return nil
+	}
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(pkScript, w.chainParams)
+	if err != nil || len(addrs) != 1 {
+		return nil
+	}
+	script, err := secretSource{w.Manager}.GetScript(addrs[0])
+	if err != nil {
+		return nil
+	}
+	return script
+}
+
+// redeemScripts returns the redeem script of each output script as returned
+// by RedeemScript.
+func (w *Wallet) redeemScripts(pkScripts [][]byte) [][]byte {
+	scripts := make([][]byte, len(pkScripts))
+	for i, pkScript := range pkScripts {
+		scripts[i] = w.RedeemScript(pkScript)
+	}
+	return scripts
 }
 
 // secretSource is an implementation of txauthor.SecretSource for the wallet's
@@ -78,6 +94,9 @@ func (s secretSource) GetScript(addr btcutil.Address) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	if ma.AddrType() == waddrmgr.NestedWitnessPubKey {
+		return nestedWitnessProgram(ma, s.ChainParams())
+	}
 	msa, ok := ma.(waddrmgr.ManagedScriptAddress)
 	if !ok {
 		e := fmt.Errorf("managed address type for %v is `%T` but "+
@@ -87,12 +106,39 @@ func (s secretSource) GetScript(addr btcutil.Address) ([]byte, error) {
 	return msa.Script()
 }
 
+// nestedWitnessProgram returns the p2wkh witness program which is the redeem
+// script of a managed address of the nested witness pubkey hash type.
+func nestedWitnessProgram(ma waddrmgr.ManagedAddress,
+	chainParams *chaincfg.Params) ([]byte, error) {
+
+	mpka, ok := ma.(waddrmgr.ManagedPubKeyAddress)
+	if !ok || ma.AddrType() != waddrmgr.NestedWitnessPubKey {
+		return nil, fmt.Errorf("managed address %v does not nest a "+
+			"witness program", ma.Address())
+	}
+	pubKeyHash := btcutil.Hash160(mpka.PubKey().SerializeCompressed())
+	witnessAddr, err := btcutil.NewAddressWitnessPubKeyHash(pubKeyHash,
+		chainParams)
+	if err != nil {
+		return nil, err
+	}
+	return txscript.PayToAddrScript(witnessAddr)
+}
+
 // txToOutputs creates a signed transaction which includes each output from
 // outputs.  Previous outputs to reedeem are chosen from the passed account's
 // UTXO set and minconf policy. An additional output may be added to return
-// change to the wallet.  An appropriate fee is included based on the wallet's
-// current relay fee.  The wallet must be unlocked to create the transaction.
-func (w *Wallet) txToOutputs(outputs []*wire.TxOut, account uint32, minconf int32) (*txauthor.AuthoredTx, error) {
+// change to the wallet.  An appropriate fee is included based on feeRate, or
+// the wallet's estimated fee rate if feeRate is zero.  Inputs are chosen by
+// selector, or by the largest-first strategy if selector is nil.  The wallet
+// must be unlocked to create the transaction.
+func (w *Wallet) txToOutputs(outputs []*wire.TxOut, account uint32, minconf int32,
+	feeRate btcutil.Amount, selector txauthor.CoinSelector) (*txauthor.AuthoredTx, error) {
+	// Transactions spending from watch-only accounts can not be signed.
+	if err := w.checkAccountSigns(account); err != nil {
+		return nil, err
+	}
+
 	// Address manager must be unlocked to compose transaction.  Grab
 	// the unlock if possible (to prevent future unlocks), or return the
 	// error if already locked.
@@ -118,7 +164,7 @@ func (w *Wallet) txToOutputs(outputs []*wire.TxOut, account uint32, minconf int3
 		return nil, err
 	}
 
-	inputSource := makeInputSource(eligible)
+	inputSource := w.makeInputSource(eligible, selector)
 	changeSource := func() ([]byte, error) {
 		// Derive the change output script.  As a hack to allow spending from
 		// the imported account, change addresses are created from account 0.
@@ -133,12 +179,25 @@ func (w *Wallet) txToOutputs(outputs []*wire.TxOut, account uint32, minconf int3
 		}
 		return txscript.PayToAddrScript(changeAddr)
 	}
-	tx, err := txauthor.NewUnsignedTransaction(outputs, w.RelayFee(),
+	tx, err := txauthor.NewUnsignedTransaction(outputs, w.txFeeRate(feeRate),
 		inputSource, changeSource)
 	if err != nil {
 		return nil, err
 	}
 
+	// Signal that the transaction may be replaced, so its fee can later be
+	// bumped if it is slow to confirm.
+	for _, txIn := range tx.Tx.TxIn {
+		txIn.Sequence = txrules.MaxRBFSequence
+	}
+
+	// Set the lock time and sequence numbers required by inputs spending
+	// time-locked scripts.
+	err = txauthor.SetTimeLocks(tx.Tx, tx.PrevScripts, secretSource{w.Manager})
+	if err != nil {
+		return nil, err
+	}
+
 	// Randomize change position, if change exists, before signing.  This
 	// doesn't affect the serialize size, so the change amount will still be
 	// valid.
@@ -151,7 +210,7 @@ func (w *Wallet) txToOutputs(outputs []*wire.TxOut, account uint32, minconf int3
 		return nil, err
 	}
 
-	err = validateMsgTx(tx.Tx, tx.PrevScripts)
+	err = validateMsgTx(tx.Tx, tx.PrevScripts, tx.PrevInputValues)
 	if err != nil {
 		return nil, err
 	}
@@ -170,6 +229,11 @@ func (w *Wallet) findEligibleOutputs(account uint32, minconf int32, bs *waddrmgr
 	if err != nil {
 		return nil, err
 	}
+	locked, err := w.lockedOutpointSet()
+	if err != nil {
+		return nil, err
+	}
+	mtp := w.medianTimePast()
 
 	// TODO: Eventually all of these filters (except perhaps output locking)
 	// should be handled by the call to UnspentOutputs (or similar).
@@ -194,7 +258,7 @@ func (w *Wallet) findEligibleOutputs(account uint32, minconf int32, bs *waddrmgr
 		}
 
 		// Locked unspent outputs are skipped.
-		if w.LockedOutpoint(output.OutPoint) {
+		if _, ok := locked[output.OutPoint]; ok {
 			continue
 		}
 
@@ -213,18 +277,33 @@ func (w *Wallet) findEligibleOutputs(account uint32, minconf int32, bs *waddrmgr
 			continue
 		}
 
+		// Outputs of time-locked scripts are only included once the
+		// locks of the branch the wallet can sign have matured.
+		if lock, ok := w.creditTimeLock(output); ok {
+			matured, err := timeLockMatured(lock, output, bs, mtp)
+			if err != nil {
+				return nil, err
+			}
+			if !matured {
+				continue
+			}
+		}
+
 		eligible = append(eligible, *output)
 	}
 	return eligible, nil
 }
 
 // validateMsgTx verifies transaction input scripts for tx.  All previous output
-// scripts from outputs redeemed by the transaction, in the same order they are
-// spent, must be passed in the prevScripts slice.
-func validateMsgTx(tx *wire.MsgTx, prevScripts [][]byte) error {
+// scripts and values from outputs redeemed by the transaction, in the same
+// order they are spent, must be passed in the prevScripts and inputValues
+// slices.
+func validateMsgTx(tx *wire.MsgTx, prevScripts [][]byte, inputValues []btcutil.Amount) error {
+	hashCache := txscript.NewTxSigHashes(tx)
 	for i, prevScript := range prevScripts {
 		vm, err := txscript.NewEngine(prevScript, tx, i,
-			txscript.StandardVerifyFlags, nil,nil,0)
+			txscript.StandardVerifyFlags, nil, hashCache,
+			int64(inputValues[i]))
 		if err != nil {
 			return fmt.Errorf("cannot create script engine: %s", err)
 		}
@@ -0,0 +1,520 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+)
+
+// The key dump written by DumpWallet is a text file following the format of
+// the reference client.  Each line describes a private key or script:
+//
+//	<WIF key> <time> account=<name> [change=1] # addr=<address> hdkeypath=<path>
+//	<hex script> <time> account=<name> script=1 # addr=<address>
+//
+// Blank lines and lines beginning with # are comments.  Account names are
+// encoded by encodeDumpString.  The hdkeypath is only written for keys derived
+// from the wallet's seed, and change=1 marks keys of the internal branch.  The
+// reference client lists every address of a key in its addr attribute,
+// separated by commas.
+const (
+	// dumpKeyTime is written as the creation time of every key, since the
+	// wallet does not record when keys are created.  This is the time used
+	// by the reference client for keys of unknown age.
+	dumpKeyTime = "1970-01-01T00:00:01Z"
+
+	// dumpScriptTime is written as the creation time of scripts.
+	dumpScriptTime = "0"
+)
+
+// dumpEntry is a single line of a key dump.
+type dumpEntry struct {
+	key     string
+	time    string
+	account string
+	change  bool
+	script  bool
+	addrs   []string
+	path    *waddrmgr.DerivationPath
+}
+
+// line returns the entry formatted as a line of a key dump.
+func (e *dumpEntry) line() string {
+	attrs := []string{e.key, e.time, "account=" + encodeDumpString(e.account)}
+	if e.change {
+		attrs = append(attrs, "change=1")
+	}
+	if e.script {
+		attrs = append(attrs, "script=1")
+	}
+	attrs = append(attrs, "#", "addr="+strings.Join(e.addrs, ","))
+	if e.path != nil {
+		attrs = append(attrs, "hdkeypath="+e.path.String())
+	}
+	return strings.Join(attrs, " ")
+}
+
+// dumpEntryLess orders keys derived from the wallet's seed by account, branch
+// and index before imported keys and scripts.
+func dumpEntryLess(a, b *dumpEntry) bool {
+	switch {
+	case a.path != nil && b.path != nil:
+		if a.path.Account != b.path.Account {
+			return a.path.Account < b.path.Account
+		}
+		if a.path.Branch != b.path.Branch {
+			return a.path.Branch < b.path.Branch
+		}
+		return a.path.Index < b.path.Index
+	case a.path != nil || b.path != nil:
+		return a.path != nil
+	case a.script != b.script:
+		return !a.script
+	default:
+		return strings.Join(a.addrs, ",") < strings.Join(b.addrs, ",")
+	}
+}
+
+// DumpWallet writes the private keys and scripts of every address of the
+// wallet to out in a human readable format, along with the account of each
+// address, and the derivation path of each key derived from the wallet's
+// seed.  The addresses of watch-only accounts are not written since they have
+// no private keys.  The wallet must be unlocked.
+func (w *Wallet) DumpWallet(out io.Writer) error {
+	if w.Manager.IsLocked() {
+		return waddrmgr.ManagerError{
+			ErrorCode:   waddrmgr.ErrLocked,
+			Description: "wallet must be unlocked to dump keys",
+		}
+	}
+
+	accountNames := make(map[uint32]string)
+	accountName := func(account uint32) (string, error) {
+		name, ok := accountNames[account]
+		if ok {
+			return name, nil
+		}
+		name, err := w.Manager.AccountName(account)
+		if err != nil {
+			return "", err
+		}
+		accountNames[account] = name
+		return name, nil
+	}
+
+	// The addresses are collected before they are looked up, since the
+	// manager is locked while iterating over its addresses.
+	var addrs []btcutil.Address
+	err := w.Manager.ForEachActiveAddress(func(addr btcutil.Address) error {
+		addrs = append(addrs, addr)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	entries := make([]*dumpEntry, 0, len(addrs))
+	for _, addr := range addrs {
+		ma, err := w.Manager.Address(addr)
+		if err != nil {
+			return err
+		}
+		account, err := accountName(ma.Account())
+		if err != nil {
+			return err
+		}
+		entry := &dumpEntry{
+			account: account,
+			change:  ma.Internal(),
+			addrs:   []string{addr.EncodeAddress()},
+		}
+
+		switch ma := ma.(type) {
+		case waddrmgr.ManagedPubKeyAddress:
+			wif, err := ma.ExportPrivKey()
+			if waddrmgr.IsError(err, waddrmgr.ErrWatchingOnly) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			entry.key = wif.String()
+			entry.time = dumpKeyTime
+			if !ma.Imported() {
+				entry.path, err = w.Manager.AddrDerivationPath(addr)
+				if err != nil {
+					return err
+				}
+			}
+
+		case waddrmgr.ManagedScriptAddress:
+			script, err := ma.Script()
+			if err != nil {
+				return err
+			}
+			entry.key = hex.EncodeToString(script)
+			entry.time = dumpScriptTime
+			entry.script = true
+
+		default:
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return dumpEntryLess(entries[i], entries[j])
+	})
+
+	bw := bufio.NewWriter(out)
+	syncedTo := w.Manager.SyncedTo()
+	fmt.Fprintf(bw, "# Wallet dump created by btcwallet\n")
+	fmt.Fprintf(bw, "# * Created on %s\n",
+		time.Now().UTC().Format(time.RFC3339))
+	fmt.Fprintf(bw, "# * Network is %s\n", w.chainParams.Name)
+	fmt.Fprintf(bw, "# * Best block at time of backup was %d (%v)\n",
+		syncedTo.Height, syncedTo.Hash)
+	fmt.Fprintf(bw, "# * File contains %d keys and scripts\n", len(entries))
+	fmt.Fprintf(bw, "#\n")
+	fmt.Fprintf(bw, "# The wallet seed is not stored by the wallet and is not "+
+		"included.\n\n")
+	for _, e := range entries {
+		fmt.Fprintln(bw, e.line())
+	}
+	fmt.Fprintf(bw, "\n# End of dump\n")
+	return bw.Flush()
+}
+
+// parseDumpLine parses a line of a key dump, returning nil for comments.
+// Derivation paths which do not describe a key of an account, such as the
+// paths of the reference client's keys, are ignored.
+func parseDumpLine(line string) (*dumpEntry, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+		return nil, nil
+	}
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("missing key time")
+	}
+
+	entry := &dumpEntry{key: fields[0], time: fields[1]}
+	comment := false
+	for _, field := range fields[2:] {
+		if strings.HasPrefix(field, "#") {
+			comment = true
+			field = field[1:]
+		}
+		var err error
+		switch {
+		case comment && strings.HasPrefix(field, "addr="):
+			entry.addrs = strings.Split(field[len("addr="):], ",")
+		case comment && strings.HasPrefix(field, "hdkeypath="):
+			entry.path, _ = parseDerivationPath(
+				field[len("hdkeypath="):])
+		case comment:
+		case strings.HasPrefix(field, "account="):
+			entry.account, err = decodeDumpString(
+				field[len("account="):])
+		case field == "change=1":
+			entry.change = true
+		case field == "script=1":
+			entry.script = true
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return entry, nil
+}
+
+// ImportWallet imports the keys and scripts of a key dump written by
+// DumpWallet or by the reference client.  Keys and scripts already in the
+// wallet are skipped.  Keys with a derivation path of an account of the
+// wallet are recovered by deriving the account's addresses through the key,
+// which restores the keys of a wallet created from the same seed as HD keys
+// of their accounts.  All other keys are imported into the imported account
+// as P2PKH addresses, which fails for keys dumped with only segwit addresses.
+// When the wallet is synced with a chain server, the blockchain is rescanned
+// for the new addresses in the background.  The wallet must be unlocked.
+func (w *Wallet) ImportWallet(in io.Reader) error {
+	if w.Manager.IsLocked() {
+		return waddrmgr.ManagerError{
+			ErrorCode:   waddrmgr.ErrLocked,
+			Description: "wallet must be unlocked to import keys",
+		}
+	}
+
+	genesis := waddrmgr.BlockStamp{
+		Hash:   *w.chainParams.GenesisHash,
+		Height: 0,
+	}
+
+	var rescanAddrs []btcutil.Address
+	var imported int
+	scanner := bufio.NewScanner(in)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		entry, err := parseDumpLine(scanner.Text())
+		if err != nil {
+			return fmt.Errorf("line %d: %v", lineNum, err)
+		}
+		if entry == nil {
+			continue
+		}
+
+		addrs, err := w.importDumpEntry(entry, &genesis)
+		if err != nil {
+			return fmt.Errorf("line %d: %v", lineNum, err)
+		}
+		if len(addrs) != 0 {
+			imported++
+		}
+		rescanAddrs = append(rescanAddrs, addrs...)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	log.Infof("Imported %d keys and scripts from key dump", imported)
+	if len(rescanAddrs) == 0 {
+		return nil
+	}
+
+	// Rescans are only handled while the wallet is synced with a chain
+	// server.  Do not block on finishing the rescan.  The rescan success
+	// or failure is logged elsewhere.
+	if w.ChainClient() != nil {
+		_ = w.SubmitRescan(&RescanJob{
+			Addrs:      rescanAddrs,
+			BlockStamp: genesis,
+		})
+	} else {
+		log.Warnf("Not rescanning for %d imported addresses without a "+
+			"chain server", len(rescanAddrs))
+	}
+
+	props, err := w.Manager.AccountProperties(waddrmgr.ImportedAddrAccount)
+	if err != nil {
+		log.Errorf("Cannot fetch account properties for imported "+
+			"account after importing key dump: %v", err)
+	} else {
+		w.NtfnServer.notifyAccountProperties(props)
+	}
+	return nil
+}
+
+// importDumpEntry adds the key or script of a key dump entry to the wallet,
+// returning the addresses which must be rescanned for.  No addresses are
+// returned when the wallet already holds the key or script.
+func (w *Wallet) importDumpEntry(entry *dumpEntry, bs *waddrmgr.BlockStamp) ([]btcutil.Address, error) {
+	if entry.script {
+		script, err := hex.DecodeString(entry.key)
+		if err != nil {
+			return nil, err
+		}
+		addr, err := w.Manager.ImportScript(script, bs)
+		if waddrmgr.IsError(err, waddrmgr.ErrDuplicateAddress) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return []btcutil.Address{addr.Address()}, nil
+	}
+
+	wif, err := btcutil.DecodeWIF(entry.key)
+	if err != nil {
+		return nil, err
+	}
+	if !wif.IsForNet(w.chainParams) {
+		return nil, fmt.Errorf("key is not for the %s network",
+			w.chainParams.Name)
+	}
+
+	// The addresses of the key are only known when they are recorded by
+	// the dump, since the key may be used with different address types.
+	addrs := make([]btcutil.Address, 0, len(entry.addrs))
+	for _, encoded := range entry.addrs {
+		addr, err := btcutil.DecodeAddress(encoded, w.chainParams)
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, addr)
+	}
+	if w.holdsAnyAddress(addrs) {
+		return nil, nil
+	}
+
+	if len(addrs) != 0 && entry.path != nil {
+		derived, err := w.deriveDumpPath(entry.path)
+		if err != nil {
+			return nil, err
+		}
+		if w.holdsAnyAddress(addrs) {
+			return derived, nil
+		}
+	}
+
+	// Imported keys are only watched as P2PKH addresses.  A key dumped
+	// without its P2PKH address is refused, since importing it would not
+	// watch the segwit addresses which the key was used with.
+	if len(addrs) != 0 {
+		pkh, err := btcutil.NewAddressPubKeyHash(
+			btcutil.Hash160(wif.SerializePubKey()), w.chainParams)
+		if err != nil {
+			return nil, err
+		}
+		found := false
+		for _, addr := range addrs {
+			if addr.EncodeAddress() == pkh.EncodeAddress() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("key of address %s can only be "+
+				"imported as P2PKH address %s", entry.addrs[0],
+				pkh.EncodeAddress())
+		}
+		if len(addrs) > 1 {
+			log.Warnf("Only watching P2PKH address %s of key "+
+				"dumped with addresses %s", pkh.EncodeAddress(),
+				strings.Join(entry.addrs, ","))
+		}
+	}
+
+	ma, err := w.Manager.ImportPrivateKey(wif, bs)
+	if waddrmgr.IsError(err, waddrmgr.ErrDuplicateAddress) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []btcutil.Address{ma.Address()}, nil
+}
+
+// holdsAnyAddress returns whether any of the addresses is an address of the
+// wallet.
+func (w *Wallet) holdsAnyAddress(addrs []btcutil.Address) bool {
+	for _, addr := range addrs {
+		if _, err := w.Manager.Address(addr); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// deriveDumpPath derives the addresses of the account branch described by a
+// derivation path through the path's index, when the path describes a branch
+// of an account of the wallet deriving keys from the wallet's seed.  The newly
+// derived addresses are returned.
+func (w *Wallet) deriveDumpPath(path *waddrmgr.DerivationPath) ([]btcutil.Address, error) {
+	if path.CoinType != w.chainParams.HDCoinType || path.Branch > 1 {
+		return nil, nil
+	}
+	props, err := w.Manager.AccountProperties(path.Account)
+	if waddrmgr.IsError(err, waddrmgr.ErrAccountNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if props.WatchOnly ||
+		path.Purpose != waddrmgr.AccountPurpose(props.AddressType) {
+		return nil, nil
+	}
+
+	internal := path.Branch == 1
+	count := props.ExternalKeyCount
+	if internal {
+		count = props.InternalKeyCount
+	}
+	if path.Index < count {
+		return nil, nil
+	}
+	return w.deriveAccountAddresses(path.Account, path.Index+1-count,
+		internal)
+}
+
+// parseDerivationPath parses a derivation path in the notation of BIP0032, as
+// written by DerivationPath.String.  Hardened children may be marked by an
+// apostrophe or an h.
+func parseDerivationPath(s string) (*waddrmgr.DerivationPath, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 6 || parts[0] != "m" {
+		return nil, fmt.Errorf("invalid derivation path %q", s)
+	}
+
+	var children [5]uint32
+	for i, part := range parts[1:] {
+		hardened := strings.HasSuffix(part, "'") ||
+			strings.HasSuffix(part, "h")
+		if hardened != (i < 3) {
+			return nil, fmt.Errorf("invalid derivation path %q", s)
+		}
+		if hardened {
+			part = part[:len(part)-1]
+		}
+		child, err := strconv.ParseUint(part, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("invalid derivation path %q", s)
+		}
+		children[i] = uint32(child)
+	}
+
+	return &waddrmgr.DerivationPath{
+		Purpose:  children[0],
+		CoinType: children[1],
+		Account:  children[2],
+		Branch:   children[3],
+		Index:    children[4],
+	}, nil
+}
+
+// encodeDumpString percent-encodes the whitespace, control and non-ASCII
+// bytes of s, and the percent sign, as the reference client does for labels,
+// so that s can be written as a single field of a key dump line.
+func encodeDumpString(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c <= ' ' || c >= 0x80 || c == '%' {
+			fmt.Fprintf(&b, "%%%02x", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// decodeDumpString reverses encodeDumpString.
+func decodeDumpString(s string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i+2 >= len(s) {
+			return "", fmt.Errorf("invalid escape in %q", s)
+		}
+		c, err := strconv.ParseUint(s[i+1:i+3], 16, 8)
+		if err != nil {
+			return "", fmt.Errorf("invalid escape in %q", s)
+		}
+		b.WriteByte(byte(c))
+		i += 2
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,395 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+)
+
+func TestDumpStringEncoding(t *testing.T) {
+	tests := []struct {
+		s, encoded string
+	}{
+		{"", ""},
+		{"default", "default"},
+		{"savings account", "savings%20account"},
+		{"100%", "100%25"},
+		{"tab\tnewline\n", "tab%09newline%0a"},
+		{"café", "caf%c3%a9"},
+	}
+	for _, test := range tests {
+		encoded := encodeDumpString(test.s)
+		if encoded != test.encoded {
+			t.Errorf("%q encoded as %q, expected %q", test.s,
+				encoded, test.encoded)
+		}
+		s, err := decodeDumpString(encoded)
+		if err != nil {
+			t.Errorf("%q: %v", encoded, err)
+			continue
+		}
+		if s != test.s {
+			t.Errorf("%q decoded as %q, expected %q", encoded, s,
+				test.s)
+		}
+	}
+
+	for _, invalid := range []string{"%", "a%2", "%zz", "%+1"} {
+		_, err := decodeDumpString(invalid)
+		if err == nil {
+			t.Errorf("invalid escape in %q was decoded", invalid)
+		}
+	}
+}
+
+func TestParseDumpLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		entry *dumpEntry
+		err   bool
+	}{
+		{
+			name: "blank",
+			line: "  ",
+		},
+		{
+			name: "comment",
+			line: "# Wallet dump created by btcwallet",
+		},
+		{
+			name: "derived key",
+			line: "key 1970-01-01T00:00:01Z account=my%20account " +
+				"change=1 # addr=addr hdkeypath=m/44'/1'/2'/1/3",
+			entry: &dumpEntry{
+				key:     "key",
+				time:    "1970-01-01T00:00:01Z",
+				account: "my account",
+				change:  true,
+				addrs:   []string{"addr"},
+				path: &waddrmgr.DerivationPath{
+					Purpose:  44,
+					CoinType: 1,
+					Account:  2,
+					Branch:   1,
+					Index:    3,
+				},
+			},
+		},
+		{
+			name: "hardened children marked by h",
+			line: "key 0 # addr=addr hdkeypath=m/49h/0h/0h/0/7",
+			entry: &dumpEntry{
+				key:   "key",
+				time:  "0",
+				addrs: []string{"addr"},
+				path: &waddrmgr.DerivationPath{
+					Purpose: 49,
+					Index:   7,
+				},
+			},
+		},
+		{
+			name: "script",
+			line: "5121 0 script=1 # addr=addr",
+			entry: &dumpEntry{
+				key:    "5121",
+				time:   "0",
+				script: true,
+				addrs:  []string{"addr"},
+			},
+		},
+		{
+			name: "reference client key",
+			line: "key 2017-05-01T10:00:00Z reserve=1 " +
+				"# addr=a,b,c hdkeypath=m/0'/0'/5'",
+			entry: &dumpEntry{
+				key:   "key",
+				time:  "2017-05-01T10:00:00Z",
+				addrs: []string{"a", "b", "c"},
+			},
+		},
+		{
+			name: "reference client seed",
+			line: "key 2017-05-01T10:00:00Z hdseed=1 # addr=a,b,c " +
+				"hdkeypath=m",
+			entry: &dumpEntry{
+				key:   "key",
+				time:  "2017-05-01T10:00:00Z",
+				addrs: []string{"a", "b", "c"},
+			},
+		},
+		{
+			name: "unhardened account",
+			line: "key 0 # addr=addr hdkeypath=m/44'/0'/0/0/1",
+			entry: &dumpEntry{
+				key:   "key",
+				time:  "0",
+				addrs: []string{"addr"},
+			},
+		},
+		{
+			name: "missing time",
+			line: "key",
+			err:  true,
+		},
+		{
+			name: "invalid account escape",
+			line: "key 0 account=%zz # addr=addr",
+			err:  true,
+		},
+	}
+	for _, test := range tests {
+		entry, err := parseDumpLine(test.line)
+		if test.err {
+			if err == nil {
+				t.Errorf("%s: line was parsed", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(entry, test.entry) {
+			t.Errorf("%s: parsed %+v, expected %+v", test.name,
+				entry, test.entry)
+		}
+	}
+}
+
+// dumpTestKey returns the WIF of a key which is not derived from the test seed,
+// along with its P2PKH, nested P2WPKH and P2WPKH addresses.
+func dumpTestKey(t *testing.T, b byte) (*btcutil.WIF, [3]string) {
+	privKey, _ := btcec.PrivKeyFromBytes(btcec.S256(),
+		bytes.Repeat([]byte{b}, 32))
+	wif, err := btcutil.NewWIF(privKey, testParams, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := btcutil.Hash160(wif.SerializePubKey())
+	pkh, err := btcutil.NewAddressPubKeyHash(hash, testParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wpkh, err := btcutil.NewAddressWitnessPubKeyHash(hash, testParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	witnessProgram, err := txscript.PayToAddrScript(wpkh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nested, err := btcutil.NewAddressScriptHash(witnessProgram, testParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return wif, [3]string{pkh.EncodeAddress(), nested.EncodeAddress(),
+		wpkh.EncodeAddress()}
+}
+
+// checkDumpAddress checks the address is held by the wallet in the named
+// account.
+func checkDumpAddress(t *testing.T, w *Wallet, addr btcutil.Address,
+	account string) {
+
+	ma, err := w.Manager.Address(addr)
+	if err != nil {
+		t.Errorf("address %v was not imported: %v", addr, err)
+		return
+	}
+	name, err := w.Manager.AccountName(ma.Account())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != account {
+		t.Errorf("address %v imported into account %q, expected %q",
+			addr, name, account)
+	}
+}
+
+// TestDumpImportWallet ensures the keys and scripts of a key dump are imported
+// into a wallet of the same seed, recovering the keys derived from the seed in
+// their accounts.
+func TestDumpImportWallet(t *testing.T) {
+	t.Parallel()
+
+	w, _, teardown := newTestWallet(t)
+	defer teardown()
+	err := w.Unlock(testPrivPass, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Derive keys of a P2PKH and a nested P2WPKH account, and import a key
+	// and a script.
+	addrs := make(map[btcutil.Address]string)
+	for i := 0; i < 3; i++ {
+		addr, err := w.NewAddress(0, waddrmgr.PubKeyHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addrs[addr] = "default"
+	}
+	change, err := w.Manager.NextInternalAddresses(0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addrs[change[0].Address()] = "default"
+	nestedAccount, err := w.NextAccount("nested account",
+		waddrmgr.NestedWitnessPubKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		addr, err := w.NewAddress(nestedAccount,
+			waddrmgr.NestedWitnessPubKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addrs[addr] = "nested account"
+	}
+	wif, _ := dumpTestKey(t, 1)
+	bs := &waddrmgr.BlockStamp{Hash: *testParams.GenesisHash}
+	imported, err := w.Manager.ImportPrivateKey(wif, bs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addrs[imported.Address()] = "imported"
+	pubKey, err := btcutil.NewAddressPubKey(wif.SerializePubKey(),
+		testParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	script, err := txscript.MultiSigScript([]*btcutil.AddressPubKey{pubKey}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	importedScript, err := w.Manager.ImportScript(script, bs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addrs[importedScript.Address()] = "imported"
+
+	var dump bytes.Buffer
+	err = w.DumpWallet(&dump)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Keys of an account the wallet does not have can not be derived, and
+	// keys of nested P2WPKH addresses are not imported as plain keys.
+	w2, _, teardown2 := newTestWallet(t)
+	defer teardown2()
+	err = w2.Unlock(testPrivPass, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w2.ImportWallet(bytes.NewReader(dump.Bytes()))
+	if err == nil {
+		t.Fatal("nested P2WPKH key of a missing account was imported")
+	}
+
+	// Once the account exists, every key is imported, and the derived
+	// keys are recovered in their accounts.
+	_, err = w2.NextAccount("nested account", waddrmgr.NestedWitnessPubKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w2.ImportWallet(bytes.NewReader(dump.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for addr, account := range addrs {
+		checkDumpAddress(t, w2, addr, account)
+	}
+	checkKeyCounts(t, w2, 0, 3, 1)
+	checkKeyCounts(t, w2, nestedAccount, 2, 0)
+
+	// Importing the dump again imports nothing new.
+	err = w2.ImportWallet(bytes.NewReader(dump.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkKeyCounts(t, w2, 0, 3, 1)
+
+	// The wallet's dump matches the original dump, apart from the time it
+	// was created.
+	var dump2 bytes.Buffer
+	err = w2.DumpWallet(&dump2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dumpLines := func(dump string) []string {
+		var lines []string
+		for _, line := range strings.Split(dump, "\n") {
+			if !strings.HasPrefix(line, "# * Created on") {
+				lines = append(lines, line)
+			}
+		}
+		return lines
+	}
+	if !reflect.DeepEqual(dumpLines(dump.String()),
+		dumpLines(dump2.String())) {
+
+		t.Errorf("dump of imported wallet:\n%s\ndiffers from "+
+			"original dump:\n%s", dump2.String(), dump.String())
+	}
+}
+
+// TestImportReferenceDump ensures the keys of a key dump of the reference
+// client are imported as plain keys, and keys dumped with only segwit
+// addresses are refused.
+func TestImportReferenceDump(t *testing.T) {
+	t.Parallel()
+
+	w, _, teardown := newTestWallet(t)
+	defer teardown()
+	err := w.Unlock(testPrivPass, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seed, seedAddrs := dumpTestKey(t, 1)
+	key, keyAddrs := dumpTestKey(t, 2)
+	dump := "# Wallet dump created by Bitcoin v0.16.0\n" +
+		"\n" +
+		seed.String() + " 2018-03-01T10:00:00Z hdseed=1 # addr=" +
+		strings.Join(seedAddrs[:], ",") + " hdkeypath=m\n" +
+		key.String() + " 2018-03-01T10:00:00Z reserve=1 # addr=" +
+		strings.Join(keyAddrs[:], ",") + " hdkeypath=m/0'/0'/5'\n" +
+		"\n" +
+		"# End of dump\n"
+	err = w.ImportWallet(strings.NewReader(dump))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, encoded := range []string{seedAddrs[0], keyAddrs[0]} {
+		addr, err := btcutil.DecodeAddress(encoded, testParams)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkDumpAddress(t, w, addr, "imported")
+	}
+
+	for i := 1; i <= 2; i++ {
+		key, addrs := dumpTestKey(t, byte(2+i))
+		line := key.String() + " 2018-03-01T10:00:00Z # addr=" +
+			addrs[i] + "\n"
+		err := w.ImportWallet(strings.NewReader(line))
+		if err == nil {
+			t.Errorf("key of segwit address %s was imported",
+				addrs[i])
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"github.com/btcsuite/btcutil"
+)
+
+// DefaultFeeConfTarget is the default number of blocks within which a
+// transaction is targeted to confirm when estimating the fee rate.
+const DefaultFeeConfTarget = 6
+
+// FeeEstimator describes a source of fee rate estimates, such as the chain
+// backend the wallet is synchronized with.
+type FeeEstimator interface {
+	// EstimateFeePerKb returns the fee rate, per kB of serialized
+	// transaction size, required for a transaction to confirm within
+	// confTarget blocks.
+	EstimateFeePerKb(confTarget uint32) (btcutil.Amount, error)
+}
+
+// FeePolicy describes how fee rate estimates are applied to transactions
+// created by the wallet.
+type FeePolicy struct {
+	// ConfTarget is the number of blocks within which created transactions
+	// should confirm.
+	ConfTarget uint32
+
+	// MinFeePerKb is the fee rate used when no estimate is available, and
+	// the lowest fee rate an estimate may be lowered to.  The wallet's
+	// relay fee is used when this is lower.
+	MinFeePerKb btcutil.Amount
+
+	// MaxFeePerKb is the highest fee rate an estimate may be raised to.  A
+	// zero value does not limit estimates.
+	MaxFeePerKb btcutil.Amount
+}
+
+// DefaultFeePolicy returns the fee policy used by a wallet until another is
+// set with SetFeePolicy.
+func DefaultFeePolicy() FeePolicy {
+	return FeePolicy{ConfTarget: DefaultFeeConfTarget}
+}
+
+// clamp limits a fee rate estimate to the range allowed by the policy, using
+// relayFee as the lowest possible fee rate.
+func (p *FeePolicy) clamp(feeRate, relayFee btcutil.Amount) btcutil.Amount {
+	floor := p.MinFeePerKb
+	if floor < relayFee {
+		floor = relayFee
+	}
+	if p.MaxFeePerKb > 0 && feeRate > p.MaxFeePerKb {
+		feeRate = p.MaxFeePerKb
+	}
+	if feeRate < floor {
+		feeRate = floor
+	}
+	return feeRate
+}
+
+// FeePolicy returns the wallet's current fee policy.
+func (w *Wallet) FeePolicy() FeePolicy {
+	w.feeMu.Lock()
+	policy := w.feePolicy
+	w.feeMu.Unlock()
+	return policy
+}
+
+// SetFeePolicy sets the policy used to apply fee rate estimates to created
+// transactions.
+func (w *Wallet) SetFeePolicy(policy FeePolicy) {
+	w.feeMu.Lock()
+	w.feePolicy = policy
+	w.feeMu.Unlock()
+}
+
+// SetFeeEstimator sets the source of fee rate estimates for created
+// transactions.  A nil estimator causes transactions to pay the policy's
+// minimum fee rate.  The wallet uses its chain client as the estimator when
+// it is synchronized with one.
+func (w *Wallet) SetFeeEstimator(estimator FeeEstimator) {
+	w.feeMu.Lock()
+	w.feeEstimator = estimator
+	w.feeMu.Unlock()
+}
+
+// FeeRate returns the fee rate, per kB of serialized transaction size, used
+// for transactions created by the wallet.  The rate is estimated using the
+// wallet's fee estimator and policy.  If estimation fails, the policy's
+// minimum fee rate or the relay fee, whichever is higher, is used instead.
+func (w *Wallet) FeeRate() btcutil.Amount {
+	w.feeMu.Lock()
+	estimator := w.feeEstimator
+	policy := w.feePolicy
+	w.feeMu.Unlock()
+
+	relayFee := w.RelayFee()
+	if estimator == nil {
+		return policy.clamp(0, relayFee)
+	}
+	feeRate, err := estimator.EstimateFeePerKb(policy.ConfTarget)
+	if err != nil {
+		log.Debugf("Unable to estimate fee rate: %v", err)
+		feeRate = 0
+	}
+	return policy.clamp(feeRate, relayFee)
+}
+
+// txFeeRate returns the fee rate to use for a created transaction.  A positive
+// override is used as is, unless it is below the relay fee.  Otherwise, the
+// wallet's estimated fee rate is used.
+func (w *Wallet) txFeeRate(override btcutil.Amount) btcutil.Amount {
+	if override <= 0 {
+		return w.FeeRate()
+	}
+	if relayFee := w.RelayFee(); override < relayFee {
+		return relayFee
+	}
+	return override
+}
@@ -5,6 +5,7 @@
 package txsizes
 
 import (
+	"github.com/btcsuite/btcd/blockchain"
 	"github.com/btcsuite/btcd/wire"
 
 	h "github.com/btcsuite/btcwallet/internal/helpers"
@@ -51,8 +52,114 @@ const (
 	//   - 1 byte compact int encoding value 25
 	//   - 25 bytes P2PKH output script
 	P2PKHOutputSize = 8 + 1 + P2PKHPkScriptSize
+
+	// P2WPKHPkScriptSize is the size of a transaction output script that
+	// pays to a witness pubkey hash.  It is calculated as:
+	//
+	//   - OP_0
+	//   - OP_DATA_20
+	//   - 20 bytes pubkey hash
+	P2WPKHPkScriptSize = 1 + 1 + 20
+
+	// P2WPKHOutputSize is the serialize size of a transaction output with a
+	// P2WPKH output script.  It is calculated as:
+	//
+	//   - 8 bytes output value
+	//   - 1 byte compact int encoding value 22
+	//   - 22 bytes P2WPKH output script
+	P2WPKHOutputSize = 8 + 1 + P2WPKHPkScriptSize
+
+	// RedeemP2WPKHScriptSize is the size of a transaction input script
+	// that spends a pay-to-witness-public-key hash (P2WPKH).  The redeem
+	// script for P2WPKH spends MUST be empty.
+	RedeemP2WPKHScriptSize = 0
+
+	// RedeemP2WPKHInputSize is the worst case size of a transaction
+	// input redeeming a P2WPKH output.  This does not account for the
+	// witness data, which is discounted.  It is calculated as:
+	//
+	//   - 32 bytes previous tx
+	//   - 4 bytes output index
+	//   - 1 byte compact int encoding value 0
+	//   - 0 bytes signature script
+	//   - 4 bytes sequence
+	RedeemP2WPKHInputSize = 32 + 4 + 1 + RedeemP2WPKHScriptSize + 4
+
+	// RedeemNestedP2WPKHScriptSize is the worst case size of a transaction
+	// input script that redeems a pay-to-witness-key hash nested in P2SH
+	// (P2SH-P2WPKH).  It is calculated as:
+	//
+	//   - 1 byte compact int encoding value 22
+	//   - OP_0
+	//   - 1 byte compact int encoding value 20
+	//   - 20 byte key hash
+	RedeemNestedP2WPKHScriptSize = 1 + 1 + 1 + 20
+
+	// RedeemNestedP2WPKHInputSize is the worst case size of a transaction
+	// input redeeming a P2SH-P2WPKH output.  This does not account for the
+	// witness data, which is discounted.  It is calculated as:
+	//
+	//   - 32 bytes previous tx
+	//   - 4 bytes output index
+	//   - 1 byte compact int encoding value 23
+	//   - 23 bytes signature script
+	//   - 4 bytes sequence
+	RedeemNestedP2WPKHInputSize = 32 + 4 + 1 +
+		RedeemNestedP2WPKHScriptSize + 4
+
+	// RedeemP2WPKHInputWitnessWeight is the worst case weight of a witness
+	// for spending P2WPKH outputs.  It is calculated as:
+	//
+	//   - 1 wu compact int encoding value 2 (number of items)
+	//   - 1 wu compact int encoding value 73
+	//   - 72 wu DER signature + 1 wu sighash
+	//   - 1 wu compact int encoding value 33
+	//   - 33 wu serialized compressed pubkey
+	RedeemP2WPKHInputWitnessWeight = 1 + 1 + 73 + 1 + 33
+
+	// MaxRedeemP2SHSigScriptSize is the size of the largest transaction
+	// input script redeeming a P2SH output that is relayed by nodes using
+	// the standard policy.  It covers the signatures and redeem script of
+	// a 15-of-15 multisig script, the largest standard multisig script.
+	MaxRedeemP2SHSigScriptSize = 1650
+
+	// MaxRedeemP2SHInputSize is the worst case size of a transaction input
+	// redeeming a P2SH output with an unknown redeem script.  It is
+	// calculated as:
+	//
+	//   - 32 bytes previous tx
+	//   - 4 bytes output index
+	//   - 3 bytes compact int encoding value 1650
+	//   - 1650 bytes signature script
+	//   - 4 bytes sequence
+	MaxRedeemP2SHInputSize = 32 + 4 + 3 + MaxRedeemP2SHSigScriptSize + 4
+)
+
+// InputSize describes the worst case size of a transaction input.  Size is the
+// serialize size of the input without its witness, and WitnessWeight is the
+// weight of the witness, or zero for inputs redeeming non-witness outputs.
+type InputSize struct {
+	Size          int
+	WitnessWeight int
+}
+
+// Worst case sizes of inputs redeeming common output types.
+var (
+	P2PKHInputSize        = InputSize{RedeemP2PKHInputSize, 0}
+	P2WPKHInputSize       = InputSize{RedeemP2WPKHInputSize, RedeemP2WPKHInputWitnessWeight}
+	NestedP2WPKHInputSize = InputSize{RedeemNestedP2WPKHInputSize, RedeemP2WPKHInputWitnessWeight}
+	MaxP2SHInputSize      = InputSize{MaxRedeemP2SHInputSize, 0}
 )
 
+// SigScriptInputSize returns the serialize size of a transaction input with a
+// signature script of sigScriptSize bytes.
+func SigScriptInputSize(sigScriptSize int) int {
+	// Previous outpoint 36 bytes + serialized var int size for the length
+	// of the script + script + sequence 4 bytes.
+	return 36 + wire.VarIntSerializeSize(uint64(sigScriptSize)) +
+		sigScriptSize + 4
+}
+
 // EstimateSerializeSize returns a worst case serialize size estimate for a
 // signed transaction that spends inputCount number of compressed P2PKH outputs
 // and contains each transaction output from txOuts.  The estimated size is
@@ -72,3 +179,97 @@ func EstimateSerializeSize(inputCount int, txOuts []*wire.TxOut, addChangeOutput
 		h.SumOutputSerializeSizes(txOuts) +
 		changeSize
 }
+
+// EstimateVirtualSize returns a worst case virtual size estimate for a signed
+// transaction that spends the given number of compressed P2PKH, P2WPKH and
+// P2SH-P2WPKH outputs, and contains each transaction output from txOuts.  The
+// estimate is incremented for an additional P2PKH change output if
+// addChangeOutput is true.
+//
+// The virtual size is the size used when calculating fees for transactions
+// containing witness data, where each byte of witness data only contributes a
+// quarter of a byte.  With no witness inputs, the result is identical to
+// EstimateSerializeSize.
+func EstimateVirtualSize(numP2PKHIns, numP2WPKHIns, numNestedP2WPKHIns int,
+	txOuts []*wire.TxOut, addChangeOutput bool) int {
+
+	changeSize := 0
+	outputCount := len(txOuts)
+	if addChangeOutput {
+		changeSize = P2PKHOutputSize
+		outputCount++
+	}
+
+	// Version 4 bytes + LockTime 4 bytes + Serialized var int size for the
+	// number of transaction inputs and outputs + size of redeem scripts +
+	// the size of the serialized outputs and change.
+	baseSize := 8 +
+		wire.VarIntSerializeSize(
+			uint64(numP2PKHIns+numP2WPKHIns+numNestedP2WPKHIns)) +
+		wire.VarIntSerializeSize(uint64(outputCount)) +
+		numP2PKHIns*RedeemP2PKHInputSize +
+		numP2WPKHIns*RedeemP2WPKHInputSize +
+		numNestedP2WPKHIns*RedeemNestedP2WPKHInputSize +
+		h.SumOutputSerializeSizes(txOuts) +
+		changeSize
+
+	// If this transaction has any witness inputs, we must count the
+	// witness data.  This includes the 2 byte segwit marker and flag, and
+	// an empty witness (a single zero byte) for every non-witness input.
+	witnessWeight := 0
+	if numP2WPKHIns+numNestedP2WPKHIns > 0 {
+		witnessWeight = 2 + numP2PKHIns +
+			(numP2WPKHIns+numNestedP2WPKHIns)*
+				RedeemP2WPKHInputWitnessWeight
+	}
+
+	// We add 3 to the witness weight to make sure the result is always
+	// rounded up.
+	return baseSize + (witnessWeight+blockchain.WitnessScaleFactor-1)/
+		blockchain.WitnessScaleFactor
+}
+
+// EstimateInputsVirtualSize returns a worst case virtual size estimate for a
+// signed transaction with inputs of the given sizes, containing each
+// transaction output from txOuts.  The estimate is incremented for an
+// additional P2PKH change output if addChangeOutput is true.
+func EstimateInputsVirtualSize(inputs []InputSize, txOuts []*wire.TxOut,
+	addChangeOutput bool) int {
+
+	changeSize := 0
+	outputCount := len(txOuts)
+	if addChangeOutput {
+		changeSize = P2PKHOutputSize
+		outputCount++
+	}
+
+	inputsSize := 0
+	witnessWeight := 0
+	hasWitness := false
+	for _, in := range inputs {
+		inputsSize += in.Size
+		if in.WitnessWeight != 0 {
+			hasWitness = true
+			witnessWeight += in.WitnessWeight
+		} else {
+			// Empty witness of a non-witness input.
+			witnessWeight++
+		}
+	}
+
+	baseSize := 8 +
+		wire.VarIntSerializeSize(uint64(len(inputs))) +
+		wire.VarIntSerializeSize(uint64(outputCount)) +
+		inputsSize +
+		h.SumOutputSerializeSizes(txOuts) +
+		changeSize
+
+	// The witness data, including the 2 byte segwit marker and flag, is
+	// only serialized when the transaction has any witness inputs.
+	if !hasWitness {
+		return baseSize
+	}
+	witnessWeight += 2
+	return baseSize + (witnessWeight+blockchain.WitnessScaleFactor-1)/
+		blockchain.WitnessScaleFactor
+}
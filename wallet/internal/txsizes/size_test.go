@@ -8,8 +8,9 @@ import (
 )
 
 const (
-	p2pkhScriptSize = P2PKHPkScriptSize
-	p2shScriptSize  = 23
+	p2pkhScriptSize  = P2PKHPkScriptSize
+	p2shScriptSize   = 23
+	p2wpkhScriptSize = P2WPKHPkScriptSize
 )
 
 func makeInts(value int, n int) []int {
@@ -60,3 +61,82 @@ func TestEstimateSerializeSize(t *testing.T) {
 		}
 	}
 }
+
+func TestEstimateVirtualSize(t *testing.T) {
+	tests := []struct {
+		P2PKHInputCount        int
+		P2WPKHInputCount       int
+		NestedP2WPKHInputCount int
+		OutputScriptLengths    []int
+		AddChangeOutput        bool
+		ExpectedSizeEstimate   int
+	}{
+		// Without witness inputs the virtual size must match the
+		// serialize size estimate.
+		0: {1, 0, 0, []int{}, false, 159},
+		1: {1, 0, 0, []int{p2pkhScriptSize}, true, 227},
+		2: {2, 0, 0, []int{p2shScriptSize}, true, 374},
+
+		3: {0, 1, 0, []int{}, false, 79},
+		4: {0, 1, 0, []int{p2pkhScriptSize}, false, 113},
+		5: {0, 1, 0, []int{}, true, 113},
+		6: {0, 1, 0, []int{p2wpkhScriptSize}, false, 110},
+		7: {0, 2, 0, []int{}, false, 147},
+
+		// Mixed inputs include an empty witness for the P2PKH input.
+		8: {1, 1, 0, []int{}, false, 228},
+
+		// Nested inputs carry the witness program in the signature
+		// script.
+		9:  {0, 0, 1, []int{}, false, 102},
+		10: {0, 0, 1, []int{p2shScriptSize}, true, 168},
+		11: {0, 1, 1, []int{}, false, 170},
+		12: {1, 0, 1, []int{}, false, 251},
+	}
+	for i, test := range tests {
+		outputs := make([]*wire.TxOut, 0, len(test.OutputScriptLengths))
+		for _, l := range test.OutputScriptLengths {
+			outputs = append(outputs, &wire.TxOut{PkScript: make([]byte, l)})
+		}
+		actualEstimate := EstimateVirtualSize(test.P2PKHInputCount,
+			test.P2WPKHInputCount, test.NestedP2WPKHInputCount,
+			outputs, test.AddChangeOutput)
+		if actualEstimate != test.ExpectedSizeEstimate {
+			t.Errorf("Test %d: Got %v: Expected %v", i, actualEstimate, test.ExpectedSizeEstimate)
+		}
+	}
+}
+
+func TestEstimateInputsVirtualSize(t *testing.T) {
+	tests := []struct {
+		Inputs               []InputSize
+		OutputScriptLengths  []int
+		AddChangeOutput      bool
+		ExpectedSizeEstimate int
+	}{
+		// The estimates of common inputs must match EstimateVirtualSize.
+		0: {[]InputSize{P2PKHInputSize}, []int{}, false, 159},
+		1: {[]InputSize{P2WPKHInputSize}, []int{p2pkhScriptSize}, false, 113},
+		2: {[]InputSize{P2PKHInputSize, P2WPKHInputSize}, []int{}, false, 228},
+		3: {[]InputSize{NestedP2WPKHInputSize}, []int{p2shScriptSize}, true, 168},
+		4: {[]InputSize{P2PKHInputSize, NestedP2WPKHInputSize}, []int{}, false, 251},
+
+		// A P2SH input with an unknown redeem script is charged for the
+		// largest standard signature script.
+		5: {[]InputSize{MaxP2SHInputSize}, []int{}, false, 10 + MaxRedeemP2SHInputSize},
+		6: {[]InputSize{MaxP2SHInputSize, P2WPKHInputSize}, []int{}, false,
+			10 + MaxRedeemP2SHInputSize + RedeemP2WPKHInputSize +
+				(2+1+RedeemP2WPKHInputWitnessWeight+3)/4},
+	}
+	for i, test := range tests {
+		outputs := make([]*wire.TxOut, 0, len(test.OutputScriptLengths))
+		for _, l := range test.OutputScriptLengths {
+			outputs = append(outputs, &wire.TxOut{PkScript: make([]byte, l)})
+		}
+		actualEstimate := EstimateInputsVirtualSize(test.Inputs, outputs,
+			test.AddChangeOutput)
+		if actualEstimate != test.ExpectedSizeEstimate {
+			t.Errorf("Test %d: Got %v: Expected %v", i, actualEstimate, test.ExpectedSizeEstimate)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"errors"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// ErrOutputNotFound describes an error where an output index exceeds the
+// number of outputs of a transaction.
+var ErrOutputNotFound = errors.New("transaction has no output with this index")
+
+// LabelTransaction sets the label of a wallet transaction, such as an invoice
+// ID, which is saved in the wallet database and included in transaction
+// results and notifications.  An empty label removes the label.  ErrTxNotFound
+// is returned if the transaction is not recorded by the wallet.
+func (w *Wallet) LabelTransaction(txHash *chainhash.Hash, label string) error {
+	details, err := w.TxStore.TxDetails(txHash)
+	if err != nil {
+		return err
+	}
+	if details == nil {
+		return ErrTxNotFound
+	}
+	return w.TxStore.LabelTx(txHash, label)
+}
+
+// TransactionLabel returns the label of a transaction, or an empty string if
+// the transaction is not labeled.
+func (w *Wallet) TransactionLabel(txHash *chainhash.Hash) (string, error) {
+	return w.TxStore.TxLabel(txHash)
+}
+
+// LabelOutput sets the label of an output of a wallet transaction, such as a
+// memo describing the recipient of a payment.  An empty label removes the
+// label.  ErrTxNotFound is returned if the transaction is not recorded by the
+// wallet, and ErrOutputNotFound if the transaction has no output with the
+// outpoint's index.
+func (w *Wallet) LabelOutput(op *wire.OutPoint, label string) error {
+	details, err := w.TxStore.TxDetails(&op.Hash)
+	if err != nil {
+		return err
+	}
+	if details == nil {
+		return ErrTxNotFound
+	}
+	if op.Index >= uint32(len(details.MsgTx.TxOut)) {
+		return ErrOutputNotFound
+	}
+	return w.TxStore.LabelOutput(op, label)
+}
+
+// OutputLabel returns the label of a transaction output, or an empty string if
+// the output is not labeled.
+func (w *Wallet) OutputLabel(op *wire.OutPoint) (string, error) {
+	return w.TxStore.OutputLabel(op)
+}
@@ -6,20 +6,35 @@ package wallet
 
 import (
 	"errors"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcwallet/internal/prompt"
+	"github.com/btcsuite/btcwallet/internal/zero"
 	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wallet/bip39"
 	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/btcsuite/btcwallet/walletdb/encrypted"
 )
 
 const (
 	walletDbName = "wallet.db"
+
+	// walletsDirName is the name of the directory within the loader's
+	// database directory holding a directory for each named wallet.
+	walletsDirName = "wallets"
 )
 
+// DefaultWalletName is the name of the wallet whose database is kept directly
+// in the loader's database directory.  This is the wallet loaded by the
+// methods of Loader which do not take a wallet name.
+const DefaultWalletName = ""
+
 var (
 	// ErrLoaded describes the error condition of attempting to load or
 	// create a wallet when the loader has already done so.
@@ -32,22 +47,37 @@ var (
 	// ErrExists describes the error condition of attempting to create a new
 	// wallet when one exists already.
 	ErrExists = errors.New("wallet already exists")
+
+	// ErrInvalidWalletName describes the error condition of using a wallet
+	// name which is not a valid directory name.
+	ErrInvalidWalletName = errors.New("invalid wallet name")
 )
 
+// loadedWallet is a wallet opened by the loader and its database.
+type loadedWallet struct {
+	wallet *Wallet
+	db     walletdb.DB
+}
+
 // Loader implements the creating of new and opening of existing wallets, while
 // providing a callback system for other subsystems to handle the loading of a
 // wallet.  This is primarely intended for use by the RPC servers, to enable
 // methods and services which require the wallet when the wallet is loaded by
 // another subsystem.
 //
+// A loader may load many wallets at once, each identified by a name.  The
+// default wallet, named by the empty string, is stored in the loader's
+// database directory, while the other wallets are each stored in a
+// subdirectory of the wallets directory named after the wallet.
+//
 // Loader is safe for concurrent access.
 type Loader struct {
-	callbacks   []func(*Wallet)
-	chainParams *chaincfg.Params
-	dbDirPath   string
-	wallet      *Wallet
-	db          walletdb.DB
-	mu          sync.Mutex
+	callbacks         []func(*Wallet)
+	chainParams       *chaincfg.Params
+	dbDirPath         string
+	passphraseOptions *waddrmgr.ScryptOptions
+	wallets           map[string]*loadedWallet
+	mu                sync.Mutex
 }
 
 // NewLoader constructs a Loader.
@@ -55,33 +85,55 @@ func NewLoader(chainParams *chaincfg.Params, dbDirPath string) *Loader {
 	return &Loader{
 		chainParams: chainParams,
 		dbDirPath:   dbDirPath,
+		wallets:     make(map[string]*loadedWallet),
+	}
+}
+
+// walletDir returns the directory of the database of a named wallet.
+func (l *Loader) walletDir(name string) (string, error) {
+	if name == DefaultWalletName {
+		return l.dbDirPath, nil
 	}
+	if name == "." || name == ".." || filepath.Base(name) != name ||
+		strings.ContainsAny(name, "/\\\x00") {
+		return "", ErrInvalidWalletName
+	}
+	return filepath.Join(l.dbDirPath, walletsDirName, name), nil
+}
+
+// SetPassphraseOptions sets the options used to derive the master keys of the
+// wallets the loader creates, and of the passphrases changed in each wallet it
+// loads.  See Wallet.SetPassphraseOptions.  Wallets which are already loaded
+// are not affected.
+func (l *Loader) SetPassphraseOptions(opts *waddrmgr.ScryptOptions) {
+	l.mu.Lock()
+	l.passphraseOptions = opts
+	l.mu.Unlock()
 }
 
-// onLoaded executes each added callback and prevents loader from loading any
-// additional wallets.  Requires mutex to be locked.
-func (l *Loader) onLoaded(w *Wallet, db walletdb.DB) {
+// onLoaded records a loaded wallet and executes each added callback.
+// Requires mutex to be locked.
+func (l *Loader) onLoaded(name string, w *Wallet, db walletdb.DB) {
+	w.SetPassphraseOptions(l.passphraseOptions)
+	l.wallets[name] = &loadedWallet{wallet: w, db: db}
+
 	for _, fn := range l.callbacks {
 		fn(w)
 	}
-
-	l.wallet = w
-	l.db = db
-	l.callbacks = nil // not needed anymore
 }
 
-// RunAfterLoad adds a function to be executed when the loader creates or opens
-// a wallet.  Functions are executed in a single goroutine in the order they are
-// added.
+// RunAfterLoad adds a function to be executed for each wallet the loader
+// creates or opens.  The function is executed immediately for every wallet
+// which is already loaded.  Functions are executed in a single goroutine in the
+// order they are added.
 func (l *Loader) RunAfterLoad(fn func(*Wallet)) {
 	l.mu.Lock()
-	if l.wallet != nil {
-		w := l.wallet
-		l.mu.Unlock()
+	l.callbacks = append(l.callbacks, fn)
+	wallets := l.loadedWallets()
+	l.mu.Unlock()
+
+	for _, w := range wallets {
 		fn(w)
-	} else {
-		l.callbacks = append(l.callbacks, fn)
-		l.mu.Unlock()
 	}
 }
 
@@ -89,14 +141,28 @@ func (l *Loader) RunAfterLoad(fn func(*Wallet)) {
 // passphrases.  The seed is optional.  If non-nil, addresses are derived from
 // this seed.  If nil, a secure random seed is generated.
 func (l *Loader) CreateNewWallet(pubPassphrase, privPassphrase, seed []byte) (*Wallet, error) {
+	return l.CreateNamedWallet(DefaultWalletName, pubPassphrase,
+		privPassphrase, seed)
+}
+
+// CreateNamedWallet creates a new wallet with a name using the provided public
+// and private passphrases.  The seed is optional.  If non-nil, addresses are
+// derived from this seed.  If nil, a secure random seed is generated.
+func (l *Loader) CreateNamedWallet(name string, pubPassphrase, privPassphrase,
+	seed []byte) (*Wallet, error) {
+
 	defer l.mu.Unlock()
 	l.mu.Lock()
 
-	if l.wallet != nil {
+	if _, ok := l.wallets[name]; ok {
 		return nil, ErrLoaded
 	}
 
-	dbPath := filepath.Join(l.dbDirPath, walletDbName)
+	dbDir, err := l.walletDir(name)
+	if err != nil {
+		return nil, err
+	}
+	dbPath := filepath.Join(dbDir, walletDbName)
 	exists, err := fileExists(dbPath)
 	if err != nil {
 		return nil, err
@@ -106,7 +172,7 @@ func (l *Loader) CreateNewWallet(pubPassphrase, privPassphrase, seed []byte) (*W
 	}
 
 	// Create the wallet database backed by bolt db.
-	err = os.MkdirAll(l.dbDirPath, 0700)
+	err = os.MkdirAll(dbDir, 0700)
 	if err != nil {
 		return nil, err
 	}
@@ -116,22 +182,40 @@ func (l *Loader) CreateNewWallet(pubPassphrase, privPassphrase, seed []byte) (*W
 	}
 
 	// Initialize the newly created database for the wallet before opening.
-	err = Create(db, pubPassphrase, privPassphrase, seed, l.chainParams)
+	err = CreateWithOptions(db, pubPassphrase, privPassphrase, seed,
+		l.chainParams, l.passphraseOptions)
 	if err != nil {
+		db.Close()
 		return nil, err
 	}
 
 	// Open the newly-created wallet.
 	w, err := Open(db, pubPassphrase, nil, l.chainParams)
 	if err != nil {
+		db.Close()
 		return nil, err
 	}
 	w.Start()
 
-	l.onLoaded(w, db)
+	l.onLoaded(name, w, db)
 	return w, nil
 }
 
+// CreateNamedWalletFromMnemonic creates a new wallet with a name like
+// CreateNamedWallet, deriving its seed from a BIP0039 mnemonic and an optional
+// mnemonic passphrase.  The mnemonic is validated before the wallet database is
+// created, so a wallet is never written for a mistyped mnemonic.
+func (l *Loader) CreateNamedWalletFromMnemonic(name string, pubPassphrase,
+	privPassphrase []byte, mnemonic string, mnemonicPassphrase []byte) (*Wallet, error) {
+
+	seed, err := bip39.Seed(mnemonic, string(mnemonicPassphrase))
+	if err != nil {
+		return nil, err
+	}
+	defer zero.Bytes(seed)
+	return l.CreateNamedWallet(name, pubPassphrase, privPassphrase, seed)
+}
+
 var errNoConsole = errors.New("db upgrade requires console access for additional input")
 
 func noConsole() ([]byte, error) {
@@ -143,21 +227,52 @@ func noConsole() ([]byte, error) {
 // standard input prompts may be used during wallet upgrades, setting
 // canConsolePrompt will enables these prompts.
 func (l *Loader) OpenExistingWallet(pubPassphrase []byte, canConsolePrompt bool) (*Wallet, error) {
+	return l.OpenNamedWallet(DefaultWalletName, pubPassphrase,
+		canConsolePrompt)
+}
+
+// OpenNamedWallet opens the wallet with a name from its database path and the
+// public passphrase.  If the loader is being called by a context where standard
+// input prompts may be used during wallet upgrades, setting canConsolePrompt
+// will enables these prompts.
+func (l *Loader) OpenNamedWallet(name string, pubPassphrase []byte,
+	canConsolePrompt bool) (*Wallet, error) {
+
 	defer l.mu.Unlock()
 	l.mu.Lock()
 
-	if l.wallet != nil {
+	if _, ok := l.wallets[name]; ok {
 		return nil, ErrLoaded
 	}
 
+	dbDir, err := l.walletDir(name)
+	if err != nil {
+		return nil, err
+	}
+
 	// Ensure that the network directory exists.
 	if err := checkCreateDir(l.dbDirPath); err != nil {
 		return nil, err
 	}
 
-	// Open the database using the boltdb backend.
-	dbPath := filepath.Join(l.dbDirPath, walletDbName)
-	db, err := walletdb.Open("bdb", dbPath)
+	// Named wallets are never created by opening them.
+	dbPath := filepath.Join(dbDir, walletDbName)
+	if name != DefaultWalletName {
+		exists, err := fileExists(dbPath)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, walletdb.ErrDbDoesNotExist
+		}
+	}
+
+	// Open the database using the boltdb backend, decrypting it with the
+	// public passphrase when it was encrypted.
+	db, err := walletdb.Open("encrypted", "bdb", pubPassphrase, dbPath)
+	if err == encrypted.ErrNotEncrypted {
+		db, err = walletdb.Open("bdb", dbPath)
+	}
 	if err != nil {
 		log.Errorf("Failed to open database: %v", err)
 		return nil, err
@@ -176,54 +291,194 @@ func (l *Loader) OpenExistingWallet(pubPassphrase []byte, canConsolePrompt bool)
 		}
 	}
 	w, err := Open(db, pubPassphrase, cbs, l.chainParams)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	w.Start()
+
+	l.onLoaded(name, w, db)
+	return w, nil
+}
+
+// OpenNamedWalletDB opens the wallet with a name from an already open database,
+// such as an in-memory database which has no path the loader could open it
+// from.  The loader takes ownership of the database and closes it when the
+// wallet is unloaded.  Opening the wallet never prompts for input, so it fails
+// if the database requires an upgrade which needs the seed or private
+// passphrase.
+func (l *Loader) OpenNamedWalletDB(name string, db walletdb.DB,
+	pubPassphrase []byte) (*Wallet, error) {
+
+	defer l.mu.Unlock()
+	l.mu.Lock()
+
+	if _, ok := l.wallets[name]; ok {
+		return nil, ErrLoaded
+	}
+
+	cbs := &waddrmgr.OpenCallbacks{
+		ObtainSeed:        noConsole,
+		ObtainPrivatePass: noConsole,
+	}
+	w, err := Open(db, pubPassphrase, cbs, l.chainParams)
 	if err != nil {
 		return nil, err
 	}
 	w.Start()
 
-	l.onLoaded(w, db)
+	l.onLoaded(name, w, db)
 	return w, nil
 }
 
 // WalletExists returns whether a file exists at the loader's database path.
 // This may return an error for unexpected I/O failures.
 func (l *Loader) WalletExists() (bool, error) {
-	dbPath := filepath.Join(l.dbDirPath, walletDbName)
-	return fileExists(dbPath)
+	return l.NamedWalletExists(DefaultWalletName)
 }
 
-// LoadedWallet returns the loaded wallet, if any, and a bool for whether the
-// wallet has been loaded or not.  If true, the wallet pointer should be safe to
-// dereference.
+// NamedWalletExists returns whether a file exists at the database path of the
+// wallet with a name.  This may return an error for unexpected I/O failures.
+func (l *Loader) NamedWalletExists(name string) (bool, error) {
+	dbDir, err := l.walletDir(name)
+	if err != nil {
+		return false, err
+	}
+	return fileExists(filepath.Join(dbDir, walletDbName))
+}
+
+// WalletNames returns the sorted names of the wallets with a database in the
+// loader's database directory, whether they are loaded or not.
+func (l *Loader) WalletNames() ([]string, error) {
+	var names []string
+	exists, err := l.WalletExists()
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		names = append(names, DefaultWalletName)
+	}
+
+	dirs, err := ioutil.ReadDir(filepath.Join(l.dbDirPath, walletsDirName))
+	if os.IsNotExist(err) {
+		return names, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		exists, err := l.NamedWalletExists(dir.Name())
+		if err == ErrInvalidWalletName {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			names = append(names, dir.Name())
+		}
+	}
+	return names, nil
+}
+
+// LoadedWallet returns the loaded default wallet, if any, and a bool for
+// whether the wallet has been loaded or not.  If true, the wallet pointer
+// should be safe to dereference.
 func (l *Loader) LoadedWallet() (*Wallet, bool) {
+	return l.NamedWallet(DefaultWalletName)
+}
+
+// NamedWallet returns the loaded wallet with a name, if any, and a bool for
+// whether the wallet has been loaded or not.  If true, the wallet pointer
+// should be safe to dereference.
+func (l *Loader) NamedWallet(name string) (*Wallet, bool) {
+	l.mu.Lock()
+	lw, ok := l.wallets[name]
+	l.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	return lw.wallet, true
+}
+
+// LoadedWalletNames returns the sorted names of the loaded wallets.
+func (l *Loader) LoadedWalletNames() []string {
+	l.mu.Lock()
+	names := l.loadedWalletNames()
+	l.mu.Unlock()
+	return names
+}
+
+// LoadedWallets returns the loaded wallets, sorted by their names.
+func (l *Loader) LoadedWallets() []*Wallet {
 	l.mu.Lock()
-	w := l.wallet
+	wallets := l.loadedWallets()
 	l.mu.Unlock()
-	return w, w != nil
+	return wallets
 }
 
-// UnloadWallet stops the loaded wallet, if any, and closes the wallet database.
-// This returns ErrNotLoaded if the wallet has not been loaded with
+// loadedWalletNames returns the sorted names of the loaded wallets.  Requires
+// mutex to be locked.
+func (l *Loader) loadedWalletNames() []string {
+	names := make([]string, 0, len(l.wallets))
+	for name := range l.wallets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// loadedWallets returns the loaded wallets, sorted by their names.  Requires
+// mutex to be locked.
+func (l *Loader) loadedWallets() []*Wallet {
+	names := l.loadedWalletNames()
+	wallets := make([]*Wallet, len(names))
+	for i, name := range names {
+		wallets[i] = l.wallets[name].wallet
+	}
+	return wallets
+}
+
+// UnloadWallet stops the loaded default wallet, if any, and closes the wallet
+// database.  This returns ErrNotLoaded if the wallet has not been loaded with
 // CreateNewWallet or LoadExistingWallet.  The Loader may be reused if this
 // function returns without error.
 func (l *Loader) UnloadWallet() error {
+	return l.UnloadNamedWallet(DefaultWalletName)
+}
+
+// UnloadNamedWallet stops the loaded wallet with a name, if any, and closes the
+// wallet database.  This returns ErrNotLoaded if the wallet has not been
+// loaded.
+func (l *Loader) UnloadNamedWallet(name string) error {
 	defer l.mu.Unlock()
 	l.mu.Lock()
 
-	if l.wallet == nil {
+	lw, ok := l.wallets[name]
+	if !ok {
 		return ErrNotLoaded
 	}
 
-	l.wallet.Stop()
-	l.wallet.WaitForShutdown()
-	err := l.db.Close()
-	if err != nil {
-		return err
-	}
+	lw.wallet.Stop()
+	lw.wallet.WaitForShutdown()
+	delete(l.wallets, name)
+	return lw.db.Close()
+}
 
-	l.wallet = nil
-	l.db = nil
-	return nil
+// UnloadAllWallets stops every loaded wallet and closes their databases,
+// returning the first error encountered.
+func (l *Loader) UnloadAllWallets() error {
+	var firstErr error
+	for _, name := range l.LoadedWalletNames() {
+		err := l.UnloadNamedWallet(name)
+		if err != nil && err != ErrNotLoaded && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
 }
 
 func fileExists(filePath string) (bool, error) {
@@ -0,0 +1,189 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+// newTestLoader returns a loader of wallets in a temporary directory.  The
+// teardown unloads all wallets and removes the directory.
+func newTestLoader(t *testing.T) (*Loader, func()) {
+	dir, err := ioutil.TempDir("", "loader")
+	if err != nil {
+		t.Fatal(err)
+	}
+	loader := NewLoader(testParams, dir)
+	loader.SetPassphraseOptions(fastScrypt)
+	teardown := func() {
+		loader.UnloadAllWallets()
+		os.RemoveAll(dir)
+	}
+	return loader, teardown
+}
+
+// sameWallets returns whether two slices hold the same wallets in the same
+// order.
+func sameWallets(a, b []*Wallet) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// TestLoaderWalletNames ensures names which are not a single directory name
+// are rejected.
+func TestLoaderWalletNames(t *testing.T) {
+	t.Parallel()
+
+	loader, teardown := newTestLoader(t)
+	defer teardown()
+
+	for _, name := range []string{".", "..", "a/b", "../a", `a\b`, "a\x00"} {
+		_, err := loader.CreateNamedWallet(name, testPubPass,
+			testPrivPass, testSeed)
+		if err != ErrInvalidWalletName {
+			t.Errorf("creating wallet %q failed with error %v, "+
+				"expected %v", name, err, ErrInvalidWalletName)
+		}
+		_, err = loader.OpenNamedWallet(name, testPubPass, false)
+		if err != ErrInvalidWalletName {
+			t.Errorf("opening wallet %q failed with error %v, "+
+				"expected %v", name, err, ErrInvalidWalletName)
+		}
+		_, err = loader.NamedWalletExists(name)
+		if err != ErrInvalidWalletName {
+			t.Errorf("checking wallet %q failed with error %v, "+
+				"expected %v", name, err, ErrInvalidWalletName)
+		}
+	}
+
+	names, err := loader.WalletNames()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 0 {
+		t.Errorf("wallets %q were created", names)
+	}
+}
+
+// TestLoaderMultipleWallets ensures several wallets are loaded at once, each
+// from its own database, and are unloaded and opened again by name.
+func TestLoaderMultipleWallets(t *testing.T) {
+	t.Parallel()
+
+	loader, teardown := newTestLoader(t)
+	defer teardown()
+
+	var loaded []*Wallet
+	loader.RunAfterLoad(func(w *Wallet) {
+		loaded = append(loaded, w)
+	})
+
+	alice, err := loader.CreateNamedWallet("alice", testPubPass,
+		testPrivPass, testSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	def, err := loader.CreateNewWallet(testPubPass, testPrivPass, testSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := loader.CreateNamedWallet("bob", testPubPass, testPrivPass,
+		testSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameWallets(loaded, []*Wallet{alice, def, bob}) {
+		t.Error("load callback did not run once for each wallet")
+	}
+	_, err = loader.CreateNamedWallet("alice", testPubPass, testPrivPass,
+		testSeed)
+	if err != ErrLoaded {
+		t.Errorf("creating a loaded wallet failed with error %v, "+
+			"expected %v", err, ErrLoaded)
+	}
+
+	wantNames := []string{DefaultWalletName, "alice", "bob"}
+	if names := loader.LoadedWalletNames(); !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("loaded wallets %q, expected %q", names, wantNames)
+	}
+	if !sameWallets(loader.LoadedWallets(), []*Wallet{def, alice, bob}) {
+		t.Error("loaded wallets are not sorted by name")
+	}
+	for name, want := range map[string]*Wallet{
+		DefaultWalletName: def, "alice": alice, "bob": bob,
+	} {
+		w, ok := loader.NamedWallet(name)
+		if !ok || w != want {
+			t.Errorf("wallet %q is not loaded", name)
+		}
+	}
+	if w, ok := loader.LoadedWallet(); !ok || w != def {
+		t.Error("default wallet is not loaded")
+	}
+	if alice.db == bob.db || alice.db == def.db {
+		t.Error("wallets share a database")
+	}
+
+	// An unloaded wallet is no longer returned, but remains in the
+	// database directory and may be opened again.
+	err = loader.UnloadNamedWallet("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := loader.NamedWallet("alice"); ok {
+		t.Error("unloaded wallet is still loaded")
+	}
+	err = loader.UnloadNamedWallet("alice")
+	if err != ErrNotLoaded {
+		t.Errorf("unloading an unloaded wallet failed with error %v, "+
+			"expected %v", err, ErrNotLoaded)
+	}
+	wantNames = []string{DefaultWalletName, "bob"}
+	if names := loader.LoadedWalletNames(); !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("loaded wallets %q, expected %q", names, wantNames)
+	}
+	names, err := loader.WalletNames()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantNames = []string{DefaultWalletName, "alice", "bob"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("wallet names %q, expected %q", names, wantNames)
+	}
+	alice, err = loader.OpenNamedWallet("alice", testPubPass, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w, ok := loader.NamedWallet("alice"); !ok || w != alice {
+		t.Error("reopened wallet is not loaded")
+	}
+
+	// Named wallets are not created by opening them.
+	_, err = loader.OpenNamedWallet("carol", testPubPass, false)
+	if err != walletdb.ErrDbDoesNotExist {
+		t.Errorf("opening a missing wallet failed with error %v, "+
+			"expected %v", err, walletdb.ErrDbDoesNotExist)
+	}
+
+	err = loader.UnloadAllWallets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := loader.LoadedWalletNames(); len(names) != 0 {
+		t.Errorf("wallets %q are still loaded", names)
+	}
+}
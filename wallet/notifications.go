@@ -130,6 +130,7 @@ func makeTxSummary(w *Wallet, details *wtxmgr.TxDetails) TransactionSummary {
 			Index:    uint32(i),
 			Account:  acct,
 			Internal: internal,
+			Label:    details.OutputLabels[uint32(i)],
 		}
 		outputs = append(outputs, output)
 	}
@@ -140,6 +141,7 @@ func makeTxSummary(w *Wallet, details *wtxmgr.TxDetails) TransactionSummary {
 		MyOutputs:   outputs,
 		Fee:         fee,
 		Timestamp:   details.Received.Unix(),
+		Label:       details.Label,
 	}
 }
 
@@ -353,6 +355,7 @@ type TransactionSummary struct {
 	MyOutputs   []TransactionSummaryOutput
 	Fee         btcutil.Amount
 	Timestamp   int64
+	Label       string
 }
 
 // TransactionSummaryInput describes a transaction input that is relevant to the
@@ -367,11 +370,13 @@ type TransactionSummaryInput struct {
 
 // TransactionSummaryOutput describes wallet properties of a transaction output
 // controlled by the wallet.  The Index field marks the transaction output index
-// of the transaction (not included here).
+// of the transaction (not included here).  The Label field is the output's
+// label, if any.
 type TransactionSummaryOutput struct {
 	Index    uint32
 	Account  uint32
 	Internal bool
+	Label    string
 }
 
 // AccountBalance associates a total (zero confirmation) balance with an
@@ -0,0 +1,339 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"errors"
+	"time"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wallet/psbt"
+	"github.com/btcsuite/btcwallet/wallet/txauthor"
+	"github.com/btcsuite/btcwallet/wallet/txrules"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+)
+
+// Inputs of PSBTs funded by the wallet are locked with this lock ID, and are
+// released after psbtLockDuration unless the transaction is published first.
+// This prevents other transactions from spending the inputs while the PSBT is
+// passed between its signers.
+const (
+	psbtLockID       = "psbt"
+	psbtLockDuration = 10 * time.Minute
+)
+
+// ErrSighashMismatch describes an error where a PSBT input records a sighash
+// type other than the one requested for signing.
+var ErrSighashMismatch = errors.New("PSBT input requires a different " +
+	"sighash type")
+
+type (
+	fundPsbtRequest struct {
+		packet   *psbt.Packet
+		account  uint32
+		minconf  int32
+		feeRate  btcutil.Amount
+		selector txauthor.CoinSelector
+		resp     chan fundPsbtResponse
+	}
+	fundPsbtResponse struct {
+		changeIndex int
+		fee         btcutil.Amount
+		err         error
+	}
+)
+
+// FundPsbt adds inputs from an account, and a change output if necessary, to
+// a PSBT so that its inputs pay for its outputs and a fee of feeRate per kB,
+// or the wallet's estimated fee rate when feeRate is zero.  Inputs already
+// included by the PSBT must spend unspent wallet outputs, and additional
+// inputs with at least minconf confirmations are chosen by selector, or by the
+// largest-first strategy if selector is nil.  The UTXOs of every input, and
+// the redeem scripts of inputs spending nested witness outputs, are recorded
+// by the PSBT so it may be signed by the wallet or any other signer.
+//
+// The PSBT is modified in place.  The index of the change output, or -1 if no
+// change is paid, and the fee are returned.  The inputs of the funded PSBT
+// are locked for a short duration so they are not spent by other
+// transactions, except for preset inputs which are already locked under
+// another lock ID and keep that lock.  Funding is serialized with all other
+// transaction creation.
+func (w *Wallet) FundPsbt(packet *psbt.Packet, account uint32, minconf int32,
+	feeRate btcutil.Amount, selector txauthor.CoinSelector) (int, btcutil.Amount, error) {
+
+	req := fundPsbtRequest{
+		packet:   packet,
+		account:  account,
+		minconf:  minconf,
+		feeRate:  feeRate,
+		selector: selector,
+		resp:     make(chan fundPsbtResponse),
+	}
+	w.fundPsbtRequests <- req
+	resp := <-req.resp
+	return resp.changeIndex, resp.fee, resp.err
+}
+
+// fundPsbt implements FundPsbt.  It must only be called by the txCreator
+// goroutine.
+func (w *Wallet) fundPsbt(packet *psbt.Packet, account uint32, minconf int32,
+	feeRate btcutil.Amount, selector txauthor.CoinSelector) (int, btcutil.Amount, error) {
+
+	chainClient, err := w.requireChainClient()
+	if err != nil {
+		return 0, 0, err
+	}
+	bs, err := chainClient.BlockStamp()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Inputs already included by the PSBT must spend wallet outputs, and
+	// are excluded from the outputs eligible to be added.
+	unspent, err := w.TxStore.UnspentOutputs()
+	if err != nil {
+		return 0, 0, err
+	}
+	credits := make(map[wire.OutPoint]*wtxmgr.Credit, len(unspent))
+	for i := range unspent {
+		credits[unspent[i].OutPoint] = &unspent[i]
+	}
+	origTx := packet.UnsignedTx
+	presetValues := make([]btcutil.Amount, len(origTx.TxIn))
+	presetScripts := make([][]byte, len(origTx.TxIn))
+	preset := make(map[wire.OutPoint]struct{}, len(origTx.TxIn))
+	for i, txIn := range origTx.TxIn {
+		credit, ok := credits[txIn.PreviousOutPoint]
+		if !ok {
+			return 0, 0, ErrTxNotOwned
+		}
+		presetValues[i] = credit.Amount
+		presetScripts[i] = credit.PkScript
+		preset[txIn.PreviousOutPoint] = struct{}{}
+	}
+	eligible, err := w.findEligibleOutputs(account, minconf, bs)
+	if err != nil {
+		return 0, 0, err
+	}
+	// Outputs of time-locked scripts are not added, since the PSBT's lock
+	// time and version are kept and the inputs could not be finalized.
+	n := 0
+	for _, credit := range eligible {
+		if _, ok := preset[credit.OutPoint]; ok {
+			continue
+		}
+		if _, ok := w.creditTimeLock(&credit); ok {
+			continue
+		}
+		eligible[n] = credit
+		n++
+	}
+	eligible = eligible[:n]
+
+	inputSource := makeFixedInputSource(origTx.TxIn, presetValues,
+		presetScripts, w.redeemScripts(presetScripts),
+		w.makeInputSource(eligible, selector))
+	changeSource := func() ([]byte, error) {
+		// As in txToOutputs, change for spends from the imported
+		// account is paid to the default account.
+		changeAccount := account
+		if account == waddrmgr.ImportedAddrAccount {
+			changeAccount = 0
+		}
+		changeAddr, err := w.NewChangeAddress(changeAccount)
+		if err != nil {
+			return nil, err
+		}
+		return txscript.PayToAddrScript(changeAddr)
+	}
+	tx, err := txauthor.NewUnsignedTransaction(origTx.TxOut,
+		w.txFeeRate(feeRate), inputSource, changeSource)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// The version and lock time of the PSBT are kept, as are the
+	// sequence numbers of the preset inputs.  Added inputs signal
+	// replaceability.
+	tx.Tx.Version = origTx.Version
+	tx.Tx.LockTime = origTx.LockTime
+	for i := len(origTx.TxIn); i < len(tx.Tx.TxIn); i++ {
+		tx.Tx.TxIn[i].Sequence = txrules.MaxRBFSequence
+	}
+
+	// Output data of the PSBT is moved along with its output when the
+	// change position is randomized.
+	outputs := make([]psbt.POutput, len(tx.Tx.TxOut))
+	copy(outputs, packet.Outputs)
+	if tx.ChangeIndex >= 0 {
+		changeIndex := tx.ChangeIndex
+		tx.RandomizeChangePosition()
+		outputs[changeIndex], outputs[tx.ChangeIndex] =
+			outputs[tx.ChangeIndex], outputs[changeIndex]
+	}
+
+	inputs := make([]psbt.PInput, len(tx.Tx.TxIn))
+	copy(inputs, packet.Inputs)
+	for i, txIn := range tx.Tx.TxIn {
+		err := w.addPsbtInputUtxo(&inputs[i], &txIn.PreviousOutPoint,
+			tx.PrevScripts[i], tx.PrevInputValues[i])
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	expiration := time.Now().Add(psbtLockDuration)
+	// Preset inputs locked under another lock ID, such as by the user,
+	// keep their lock, since replacing it would release them once the
+	// PSBT lock expires.  Locks replaced by the PSBT lock are restored if
+	// locking fails partway, and the outputs which were not locked are
+	// unlocked again, since the packet which would spend them is not
+	// returned.
+	lockedInputs := make([]*wire.TxIn, 0, len(tx.Tx.TxIn))
+	prevLocks := make([]*wtxmgr.LockedOutput, 0, len(tx.Tx.TxIn))
+	for _, txIn := range tx.Tx.TxIn {
+		op := txIn.PreviousOutPoint
+		prevLock, err := w.TxStore.LockedOutput(op)
+		if err == nil && prevLock != nil && prevLock.LockID != psbtLockID {
+			continue
+		}
+		if err == nil {
+			err = w.LockOutpoint(op, psbtLockID, expiration)
+		}
+		if err != nil {
+			w.restoreLocks(lockedInputs, prevLocks)
+			return 0, 0, err
+		}
+		lockedInputs = append(lockedInputs, txIn)
+		prevLocks = append(prevLocks, prevLock)
+	}
+
+	var output btcutil.Amount
+	for _, txOut := range tx.Tx.TxOut {
+		output += btcutil.Amount(txOut.Value)
+	}
+	*packet = psbt.Packet{
+		UnsignedTx: tx.Tx,
+		Inputs:     inputs,
+		Outputs:    outputs,
+		Unknowns:   packet.Unknowns,
+	}
+	return tx.ChangeIndex, tx.TotalInput - output, nil
+}
+
+// restoreLocks restores the locks of the outputs spent by the inputs to the
+// previous locks, unlocking each output whose previous lock is nil.  Errors are
+// logged, since this is only used to undo locks when an operation fails.
+func (w *Wallet) restoreLocks(inputs []*wire.TxIn, prevLocks []*wtxmgr.LockedOutput) {
+	for i, txIn := range inputs {
+		op := txIn.PreviousOutPoint
+		var err error
+		if l := prevLocks[i]; l != nil {
+			err = w.LockOutpoint(op, l.LockID, l.Expiration)
+		} else {
+			err = w.UnlockOutpoint(op)
+		}
+		if err != nil {
+			log.Errorf("Failed to restore lock of output %v: %v",
+				op, err)
+		}
+	}
+}
+
+// addPsbtInputUtxo records the UTXO spent by a PSBT input, if not already
+// recorded, along with the redeem script of a nested witness output.  Witness
+// outputs are recorded as witness UTXOs, and all other outputs by their
+// previous transaction.
+func (w *Wallet) addPsbtInputUtxo(in *psbt.PInput, op *wire.OutPoint,
+	pkScript []byte, value btcutil.Amount) error {
+
+	if in.WitnessUtxo != nil || in.NonWitnessUtxo != nil {
+		return nil
+	}
+
+	if txscript.IsPayToScriptHash(pkScript) && in.RedeemScript == nil {
+		_, addrs, _, err := txscript.ExtractPkScriptAddrs(pkScript,
+			w.chainParams)
+		if err != nil {
+			return err
+		}
+		redeemScript, err := secretSource{w.Manager}.GetScript(addrs[0])
+		if err == nil {
+			in.RedeemScript = redeemScript
+		}
+	}
+
+	script := pkScript
+	if in.RedeemScript != nil {
+		script = in.RedeemScript
+	}
+	if txscript.IsWitnessProgram(script) {
+		in.WitnessUtxo = wire.NewTxOut(int64(value), pkScript)
+		return nil
+	}
+
+	details, err := w.TxStore.TxDetails(&op.Hash)
+	if err != nil {
+		return err
+	}
+	if details == nil {
+		return ErrTxNotFound
+	}
+	in.NonWitnessUtxo = &details.MsgTx
+	return nil
+}
+
+// SignPsbt adds signatures to each input of a PSBT which spends an output
+// controlled by the wallet, including the wallet's keys of multisig outputs.
+// The UTXOs of inputs spending unspent wallet outputs are recorded if missing.
+// Inputs are signed with hashType, and ErrSighashMismatch is returned if an
+// input records a different sighash type.  The indexes of the signed inputs
+// are returned.  The wallet must be unlocked to sign the PSBT.
+func (w *Wallet) SignPsbt(packet *psbt.Packet, hashType txscript.SigHashType) ([]int, error) {
+	heldUnlock, err := w.HoldUnlock()
+	if err != nil {
+		return nil, err
+	}
+	defer heldUnlock.Release()
+
+	for i, txIn := range packet.UnsignedTx.TxIn {
+		in := &packet.Inputs[i]
+		switch in.SighashType {
+		case 0:
+			if hashType != txscript.SigHashAll {
+				in.SighashType = hashType
+			}
+		case hashType:
+		default:
+			return nil, ErrSighashMismatch
+		}
+
+		if in.WitnessUtxo != nil || in.NonWitnessUtxo != nil {
+			continue
+		}
+		details, err := w.TxStore.TxDetails(&txIn.PreviousOutPoint.Hash)
+		if err != nil {
+			return nil, err
+		}
+		if details == nil {
+			continue
+		}
+		index := txIn.PreviousOutPoint.Index
+		if int(index) >= len(details.MsgTx.TxOut) {
+			continue
+		}
+		prevOut := details.MsgTx.TxOut[index]
+		err = w.addPsbtInputUtxo(in, &txIn.PreviousOutPoint,
+			prevOut.PkScript, btcutil.Amount(prevOut.Value))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return psbt.Sign(packet, secretSource{w.Manager})
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package psbt
+
+import (
+	"bytes"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+)
+
+// Finalize creates the final scriptSig and witness of an input from its
+// partial signatures, and removes the data which is no longer needed after
+// finalization.  ErrNotFinalizable is returned if the input is missing
+// signatures or spends an unsupported script.  Finalizing an already
+// finalized input does nothing.
+func Finalize(p *Packet, index int) error {
+	in := &p.Inputs[index]
+	if in.isFinalized() {
+		return nil
+	}
+	prevOut, err := p.PrevOutput(index)
+	if err != nil {
+		return err
+	}
+	if prevOut == nil {
+		return ErrNotFinalizable
+	}
+	script, witness, err := signingScript(in, prevOut.PkScript)
+	if err != nil {
+		return err
+	}
+	items, err := satisfyScript(script, in.PartialSigs)
+	if err != nil {
+		return err
+	}
+
+	builder := txscript.NewScriptBuilder()
+	switch {
+	case witness:
+		if txscript.IsPayToWitnessScriptHash(p2shInner(in, prevOut.PkScript)) {
+			items = append(items, in.WitnessScript)
+		}
+		in.FinalScriptWitness = items
+	default:
+		for _, item := range items {
+			builder.AddData(item)
+		}
+	}
+	if txscript.IsPayToScriptHash(prevOut.PkScript) {
+		builder.AddData(in.RedeemScript)
+	}
+	sigScript, err := builder.Script()
+	if err != nil {
+		return err
+	}
+	if len(sigScript) != 0 {
+		in.FinalScriptSig = sigScript
+	} else if in.FinalScriptWitness == nil {
+		// Scripts satisfied by an empty scriptSig are not supported.
+		return ErrNotFinalizable
+	}
+
+	in.PartialSigs = nil
+	in.SighashType = 0
+	in.RedeemScript = nil
+	in.WitnessScript = nil
+	in.Bip32Derivation = nil
+	return nil
+}
+
+// MaybeFinalizeAll finalizes every input of the PSBT which can be finalized.
+// The first error of an input which could not be finalized is returned after
+// all inputs are attempted.
+func MaybeFinalizeAll(p *Packet) error {
+	var firstErr error
+	for i := range p.Inputs {
+		err := Finalize(p, i)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Extract returns the signed transaction of a PSBT with all inputs finalized.
+func Extract(p *Packet) (*wire.MsgTx, error) {
+	if !p.IsComplete() {
+		return nil, ErrIncomplete
+	}
+	tx := p.UnsignedTx.Copy()
+	for i, txIn := range tx.TxIn {
+		txIn.SignatureScript = p.Inputs[i].FinalScriptSig
+		txIn.Witness = p.Inputs[i].FinalScriptWitness
+	}
+	return tx, nil
+}
+
+// p2shInner returns the redeem script of an input spending a p2sh output, or
+// the previous output script of any other input.
+func p2shInner(in *PInput, pkScript []byte) []byte {
+	if txscript.IsPayToScriptHash(pkScript) {
+		return in.RedeemScript
+	}
+	return pkScript
+}
+
+// satisfyScript returns the stack items, in push order, which satisfy a
+// pay-to-pubkey, pay-to-pubkey-hash, p2wkh or multisig script using the
+// partial signatures.  Multisig signatures are ordered by their public keys'
+// positions in the script and preceded by the dummy item consumed by
+// OP_CHECKMULTISIG.
+func satisfyScript(script []byte, sigs []*PartialSig) ([][]byte, error) {
+	switch txscript.GetScriptClass(script) {
+	case txscript.PubKeyHashTy, txscript.WitnessV0PubKeyHashTy:
+		var pubKeyHash []byte
+		if txscript.IsPayToWitnessPubKeyHash(script) {
+			pubKeyHash = script[2:22]
+		} else {
+			pubKeyHash = script[3:23]
+		}
+		for _, sig := range sigs {
+			if bytes.Equal(btcutil.Hash160(sig.PubKey), pubKeyHash) {
+				return [][]byte{sig.Signature, sig.PubKey}, nil
+			}
+		}
+
+	case txscript.PubKeyTy:
+		pushes, err := txscript.PushedData(script)
+		if err != nil {
+			return nil, err
+		}
+		for _, sig := range sigs {
+			if bytes.Equal(sig.PubKey, pushes[0]) {
+				return [][]byte{sig.Signature}, nil
+			}
+		}
+
+	case txscript.MultiSigTy:
+		_, required, err := txscript.CalcMultiSigStats(script)
+		if err != nil {
+			return nil, err
+		}
+		pubKeys, err := txscript.PushedData(script)
+		if err != nil {
+			return nil, err
+		}
+		items := [][]byte{nil}
+		for _, pubKey := range pubKeys {
+			for _, sig := range sigs {
+				if bytes.Equal(sig.PubKey, pubKey) {
+					items = append(items, sig.Signature)
+					break
+				}
+			}
+			if len(items) == required+1 {
+				return items, nil
+			}
+		}
+	}
+
+	return nil, ErrNotFinalizable
+}
@@ -0,0 +1,389 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package psbt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"sort"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// PartialSig is a signature for an input created by one of the keys able to
+// sign the input.
+type PartialSig struct {
+	PubKey    []byte
+	Signature []byte
+}
+
+// Bip32Derivation records the BIP0032 derivation path of a public key from
+// the master key with the given fingerprint.
+type Bip32Derivation struct {
+	PubKey               []byte
+	MasterKeyFingerprint uint32
+	Bip32Path            []uint32
+}
+
+// PInput is the data of a PSBT input.  Once an input is finalized, only the
+// UTXO and the final script fields are kept.
+type PInput struct {
+	NonWitnessUtxo     *wire.MsgTx
+	WitnessUtxo        *wire.TxOut
+	PartialSigs        []*PartialSig
+	SighashType        txscript.SigHashType
+	RedeemScript       []byte
+	WitnessScript      []byte
+	Bip32Derivation    []*Bip32Derivation
+	FinalScriptSig     []byte
+	FinalScriptWitness wire.TxWitness
+	Unknowns           []*Unknown
+}
+
+// isFinalized returns whether the input has a final scriptSig or witness.
+func (in *PInput) isFinalized() bool {
+	return in.FinalScriptSig != nil || in.FinalScriptWitness != nil
+}
+
+// AddPartialSig records a signature for the input, replacing any signature
+// already recorded for the same public key.
+func (in *PInput) AddPartialSig(sig *PartialSig) {
+	for i, s := range in.PartialSigs {
+		if bytes.Equal(s.PubKey, sig.PubKey) {
+			in.PartialSigs[i] = sig
+			return
+		}
+	}
+	in.PartialSigs = append(in.PartialSigs, sig)
+}
+
+func (in *PInput) deserialize(r io.Reader) error {
+	return readMap(r, func(keyType byte, keyData, value []byte) error {
+		switch keyType {
+		case inputNonWitnessUtxoType:
+			if len(keyData) != 0 {
+				return ErrInvalidKeyData
+			}
+			tx := new(wire.MsgTx)
+			err := tx.Deserialize(bytes.NewReader(value))
+			if err != nil {
+				return ErrInvalidFormat
+			}
+			in.NonWitnessUtxo = tx
+
+		case inputWitnessUtxoType:
+			if len(keyData) != 0 {
+				return ErrInvalidKeyData
+			}
+			txOut, err := readTxOut(value)
+			if err != nil {
+				return err
+			}
+			in.WitnessUtxo = txOut
+
+		case inputPartialSigType:
+			if !validPubKey(keyData) {
+				return ErrInvalidKeyData
+			}
+			in.PartialSigs = append(in.PartialSigs, &PartialSig{
+				PubKey:    keyData,
+				Signature: value,
+			})
+
+		case inputSighashType:
+			if len(keyData) != 0 {
+				return ErrInvalidKeyData
+			}
+			if len(value) != 4 {
+				return ErrInvalidFormat
+			}
+			in.SighashType = txscript.SigHashType(
+				binary.LittleEndian.Uint32(value))
+
+		case inputRedeemScriptType:
+			if len(keyData) != 0 {
+				return ErrInvalidKeyData
+			}
+			in.RedeemScript = value
+
+		case inputWitnessScriptType:
+			if len(keyData) != 0 {
+				return ErrInvalidKeyData
+			}
+			in.WitnessScript = value
+
+		case inputBip32DerivationType:
+			d, err := readBip32Derivation(keyData, value)
+			if err != nil {
+				return err
+			}
+			in.Bip32Derivation = append(in.Bip32Derivation, d)
+
+		case inputFinalScriptSigType:
+			if len(keyData) != 0 {
+				return ErrInvalidKeyData
+			}
+			in.FinalScriptSig = value
+
+		case inputFinalScriptWitnessType:
+			if len(keyData) != 0 {
+				return ErrInvalidKeyData
+			}
+			witness, err := readWitness(value)
+			if err != nil {
+				return err
+			}
+			in.FinalScriptWitness = witness
+
+		default:
+			in.Unknowns = append(in.Unknowns, newUnknown(keyType, keyData, value))
+		}
+		return nil
+	})
+}
+
+func (in *PInput) serialize(w io.Writer) error {
+	if in.NonWitnessUtxo != nil {
+		var buf bytes.Buffer
+		err := in.NonWitnessUtxo.Serialize(&buf)
+		if err != nil {
+			return err
+		}
+		err = writePair(w, inputNonWitnessUtxoType, nil, buf.Bytes())
+		if err != nil {
+			return err
+		}
+	}
+	if in.WitnessUtxo != nil {
+		var buf bytes.Buffer
+		err := wire.WriteTxOut(&buf, 0, 0, in.WitnessUtxo)
+		if err != nil {
+			return err
+		}
+		err = writePair(w, inputWitnessUtxoType, nil, buf.Bytes())
+		if err != nil {
+			return err
+		}
+	}
+
+	if !in.isFinalized() {
+		sigs := make([]*PartialSig, len(in.PartialSigs))
+		copy(sigs, in.PartialSigs)
+		sort.Slice(sigs, func(i, j int) bool {
+			return bytes.Compare(sigs[i].PubKey, sigs[j].PubKey) < 0
+		})
+		for _, sig := range sigs {
+			err := writePair(w, inputPartialSigType, sig.PubKey,
+				sig.Signature)
+			if err != nil {
+				return err
+			}
+		}
+		if in.SighashType != 0 {
+			var v [4]byte
+			binary.LittleEndian.PutUint32(v[:], uint32(in.SighashType))
+			err := writePair(w, inputSighashType, nil, v[:])
+			if err != nil {
+				return err
+			}
+		}
+		if in.RedeemScript != nil {
+			err := writePair(w, inputRedeemScriptType, nil,
+				in.RedeemScript)
+			if err != nil {
+				return err
+			}
+		}
+		if in.WitnessScript != nil {
+			err := writePair(w, inputWitnessScriptType, nil,
+				in.WitnessScript)
+			if err != nil {
+				return err
+			}
+		}
+		err := writeBip32Derivations(w, inputBip32DerivationType,
+			in.Bip32Derivation)
+		if err != nil {
+			return err
+		}
+	}
+
+	if in.FinalScriptSig != nil {
+		err := writePair(w, inputFinalScriptSigType, nil, in.FinalScriptSig)
+		if err != nil {
+			return err
+		}
+	}
+	if in.FinalScriptWitness != nil {
+		var buf bytes.Buffer
+		err := writeWitness(&buf, in.FinalScriptWitness)
+		if err != nil {
+			return err
+		}
+		err = writePair(w, inputFinalScriptWitnessType, nil, buf.Bytes())
+		if err != nil {
+			return err
+		}
+	}
+
+	err := writeUnknowns(w, in.Unknowns)
+	if err != nil {
+		return err
+	}
+	return writeSeparator(w)
+}
+
+// merge adds the data of src which is missing from the input.
+func (in *PInput) merge(src *PInput) {
+	if in.NonWitnessUtxo == nil {
+		in.NonWitnessUtxo = src.NonWitnessUtxo
+	}
+	if in.WitnessUtxo == nil {
+		in.WitnessUtxo = src.WitnessUtxo
+	}
+	for _, sig := range src.PartialSigs {
+		if !hasPartialSig(in.PartialSigs, sig.PubKey) {
+			in.PartialSigs = append(in.PartialSigs, sig)
+		}
+	}
+	if in.SighashType == 0 {
+		in.SighashType = src.SighashType
+	}
+	if in.RedeemScript == nil {
+		in.RedeemScript = src.RedeemScript
+	}
+	if in.WitnessScript == nil {
+		in.WitnessScript = src.WitnessScript
+	}
+	in.Bip32Derivation = mergeBip32Derivations(in.Bip32Derivation,
+		src.Bip32Derivation)
+	if in.FinalScriptSig == nil {
+		in.FinalScriptSig = src.FinalScriptSig
+	}
+	if in.FinalScriptWitness == nil {
+		in.FinalScriptWitness = src.FinalScriptWitness
+	}
+	in.Unknowns = mergeUnknowns(in.Unknowns, src.Unknowns)
+}
+
+func hasPartialSig(sigs []*PartialSig, pubKey []byte) bool {
+	for _, s := range sigs {
+		if bytes.Equal(s.PubKey, pubKey) {
+			return true
+		}
+	}
+	return false
+}
+
+// validPubKey returns whether b is a serialized compressed or uncompressed
+// public key.
+func validPubKey(b []byte) bool {
+	if len(b) != btcec.PubKeyBytesLenCompressed &&
+		len(b) != btcec.PubKeyBytesLenUncompressed {
+		return false
+	}
+	_, err := btcec.ParsePubKey(b, btcec.S256())
+	return err == nil
+}
+
+func readTxOut(b []byte) (*wire.TxOut, error) {
+	if len(b) < 9 {
+		return nil, ErrInvalidFormat
+	}
+	r := bytes.NewReader(b[8:])
+	pkScript, err := wire.ReadVarBytes(r, 0, maxValueLength, "pkScript")
+	if err != nil || r.Len() != 0 {
+		return nil, ErrInvalidFormat
+	}
+	value := int64(binary.LittleEndian.Uint64(b[:8]))
+	return wire.NewTxOut(value, pkScript), nil
+}
+
+func readWitness(b []byte) (wire.TxWitness, error) {
+	r := bytes.NewReader(b)
+	n, err := wire.ReadVarInt(r, 0)
+	if err != nil || n > uint64(len(b)) {
+		return nil, ErrInvalidFormat
+	}
+	witness := make(wire.TxWitness, n)
+	for i := range witness {
+		witness[i], err = wire.ReadVarBytes(r, 0, maxValueLength, "witness")
+		if err != nil {
+			return nil, ErrInvalidFormat
+		}
+	}
+	if r.Len() != 0 {
+		return nil, ErrInvalidFormat
+	}
+	return witness, nil
+}
+
+func writeWitness(w io.Writer, witness wire.TxWitness) error {
+	err := wire.WriteVarInt(w, 0, uint64(len(witness)))
+	if err != nil {
+		return err
+	}
+	for _, item := range witness {
+		err := wire.WriteVarBytes(w, 0, item)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readBip32Derivation(keyData, value []byte) (*Bip32Derivation, error) {
+	if !validPubKey(keyData) {
+		return nil, ErrInvalidKeyData
+	}
+	if len(value) < 4 || len(value)%4 != 0 {
+		return nil, ErrInvalidFormat
+	}
+	d := &Bip32Derivation{
+		PubKey:               keyData,
+		MasterKeyFingerprint: binary.LittleEndian.Uint32(value),
+	}
+	for i := 4; i < len(value); i += 4 {
+		d.Bip32Path = append(d.Bip32Path,
+			binary.LittleEndian.Uint32(value[i:]))
+	}
+	return d, nil
+}
+
+func writeBip32Derivations(w io.Writer, keyType byte, derivations []*Bip32Derivation) error {
+	ds := make([]*Bip32Derivation, len(derivations))
+	copy(ds, derivations)
+	sort.Slice(ds, func(i, j int) bool {
+		return bytes.Compare(ds[i].PubKey, ds[j].PubKey) < 0
+	})
+	for _, d := range ds {
+		value := make([]byte, 4+4*len(d.Bip32Path))
+		binary.LittleEndian.PutUint32(value, d.MasterKeyFingerprint)
+		for i, index := range d.Bip32Path {
+			binary.LittleEndian.PutUint32(value[4+4*i:], index)
+		}
+		err := writePair(w, keyType, d.PubKey, value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func mergeBip32Derivations(dst, src []*Bip32Derivation) []*Bip32Derivation {
+next:
+	for _, d := range src {
+		for _, e := range dst {
+			if bytes.Equal(e.PubKey, d.PubKey) {
+				continue next
+			}
+		}
+		dst = append(dst, d)
+	}
+	return dst
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package psbt
+
+import (
+	"io"
+)
+
+// POutput is the data of a PSBT output, which may be used by signers to
+// verify that an output pays to themselves, such as a change output.
+type POutput struct {
+	RedeemScript    []byte
+	WitnessScript   []byte
+	Bip32Derivation []*Bip32Derivation
+	Unknowns        []*Unknown
+}
+
+func (out *POutput) deserialize(r io.Reader) error {
+	return readMap(r, func(keyType byte, keyData, value []byte) error {
+		switch keyType {
+		case outputRedeemScriptType:
+			if len(keyData) != 0 {
+				return ErrInvalidKeyData
+			}
+			out.RedeemScript = value
+
+		case outputWitnessScriptType:
+			if len(keyData) != 0 {
+				return ErrInvalidKeyData
+			}
+			out.WitnessScript = value
+
+		case outputBip32DerivationType:
+			d, err := readBip32Derivation(keyData, value)
+			if err != nil {
+				return err
+			}
+			out.Bip32Derivation = append(out.Bip32Derivation, d)
+
+		default:
+			out.Unknowns = append(out.Unknowns, newUnknown(keyType, keyData, value))
+		}
+		return nil
+	})
+}
+
+func (out *POutput) serialize(w io.Writer) error {
+	if out.RedeemScript != nil {
+		err := writePair(w, outputRedeemScriptType, nil, out.RedeemScript)
+		if err != nil {
+			return err
+		}
+	}
+	if out.WitnessScript != nil {
+		err := writePair(w, outputWitnessScriptType, nil, out.WitnessScript)
+		if err != nil {
+			return err
+		}
+	}
+	err := writeBip32Derivations(w, outputBip32DerivationType,
+		out.Bip32Derivation)
+	if err != nil {
+		return err
+	}
+	err = writeUnknowns(w, out.Unknowns)
+	if err != nil {
+		return err
+	}
+	return writeSeparator(w)
+}
+
+// merge adds the data of src which is missing from the output.
+func (out *POutput) merge(src *POutput) {
+	if out.RedeemScript == nil {
+		out.RedeemScript = src.RedeemScript
+	}
+	if out.WitnessScript == nil {
+		out.WitnessScript = src.WitnessScript
+	}
+	out.Bip32Derivation = mergeBip32Derivations(out.Bip32Derivation,
+		src.Bip32Derivation)
+	out.Unknowns = mergeUnknowns(out.Unknowns, src.Unknowns)
+}
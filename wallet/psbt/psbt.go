@@ -0,0 +1,388 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package psbt implements partially signed bitcoin transactions as described
+// by BIP0174.  A PSBT carries an unsigned transaction along with the data
+// required by each participant to sign its inputs, so transactions may be
+// created, signed and finalized by separate wallets.
+package psbt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+// magic is the serialized prefix of every PSBT: the bytes "psbt" followed by
+// the separator 0xff.
+var magic = [5]byte{0x70, 0x73, 0x62, 0x74, 0xff}
+
+// Limits on the size of keys and values read from a serialized PSBT.
+const (
+	maxKeyLength   = 10000
+	maxValueLength = 4000000
+)
+
+// Global key types.
+const (
+	globalUnsignedTxType = 0x00
+)
+
+// Input key types.
+const (
+	inputNonWitnessUtxoType     = 0x00
+	inputWitnessUtxoType        = 0x01
+	inputPartialSigType         = 0x02
+	inputSighashType            = 0x03
+	inputRedeemScriptType       = 0x04
+	inputWitnessScriptType      = 0x05
+	inputBip32DerivationType    = 0x06
+	inputFinalScriptSigType     = 0x07
+	inputFinalScriptWitnessType = 0x08
+)
+
+// Output key types.
+const (
+	outputRedeemScriptType    = 0x00
+	outputWitnessScriptType   = 0x01
+	outputBip32DerivationType = 0x02
+)
+
+var (
+	// ErrInvalidMagic describes an error where a serialized PSBT does not
+	// begin with the PSBT magic bytes.
+	ErrInvalidMagic = errors.New("invalid PSBT magic bytes")
+
+	// ErrInvalidFormat describes an error where a serialized PSBT is
+	// malformed.
+	ErrInvalidFormat = errors.New("invalid PSBT serialization format")
+
+	// ErrDuplicateKey describes an error where a key is repeated within a
+	// map of a serialized PSBT.
+	ErrDuplicateKey = errors.New("duplicate key in PSBT map")
+
+	// ErrInvalidKeyData describes an error where the key data of a
+	// serialized PSBT key is invalid for its type.
+	ErrInvalidKeyData = errors.New("invalid PSBT key data")
+
+	// ErrUnsignedTxHasScripts describes an error where the unsigned
+	// transaction of a PSBT has signature scripts or witnesses.
+	ErrUnsignedTxHasScripts = errors.New("unsigned transaction has " +
+		"signature scripts or witnesses")
+
+	// ErrMismatchedTx describes an error where PSBTs for different
+	// unsigned transactions are combined.
+	ErrMismatchedTx = errors.New("PSBTs are for different unsigned " +
+		"transactions")
+
+	// ErrNotFinalizable describes an error where an input can not be
+	// finalized, because it is missing signatures or other data, or
+	// spends an unsupported output script.
+	ErrNotFinalizable = errors.New("PSBT input can not be finalized")
+
+	// ErrIncomplete describes an error where a transaction is extracted
+	// from a PSBT before every input is finalized.
+	ErrIncomplete = errors.New("PSBT is not complete")
+
+	// ErrInvalidUtxo describes an error where the previous output recorded
+	// for an input does not match the outpoint spent by the input.
+	ErrInvalidUtxo = errors.New("PSBT input UTXO does not match outpoint")
+
+	// ErrInvalidScript describes an error where the redeem or witness
+	// script required to spend an input is missing or does not match the
+	// previous output script.
+	ErrInvalidScript = errors.New("PSBT input redeem or witness script " +
+		"is missing or invalid")
+)
+
+// Unknown is a key-value pair of a PSBT map with a key type not known to this
+// package.  Unknowns are kept so they are not lost when the PSBT is passed on.
+type Unknown struct {
+	Key   []byte
+	Value []byte
+}
+
+// Packet is a partially signed bitcoin transaction.  Inputs and Outputs have
+// the same lengths and order as the inputs and outputs of UnsignedTx.
+type Packet struct {
+	UnsignedTx *wire.MsgTx
+	Inputs     []PInput
+	Outputs    []POutput
+	Unknowns   []*Unknown
+}
+
+// New creates a PSBT for an unsigned transaction, with no data recorded for
+// any input or output.
+func New(tx *wire.MsgTx) (*Packet, error) {
+	if !isUnsigned(tx) {
+		return nil, ErrUnsignedTxHasScripts
+	}
+	return &Packet{
+		UnsignedTx: tx,
+		Inputs:     make([]PInput, len(tx.TxIn)),
+		Outputs:    make([]POutput, len(tx.TxOut)),
+	}, nil
+}
+
+// isUnsigned returns whether no input of tx has a signature script or
+// witness.
+func isUnsigned(tx *wire.MsgTx) bool {
+	for _, txIn := range tx.TxIn {
+		if len(txIn.SignatureScript) != 0 || len(txIn.Witness) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// NewFromRawBytes deserializes a PSBT.  If b64 is true, the serialization is
+// read as base64 encoded.
+func NewFromRawBytes(r io.Reader, b64 bool) (*Packet, error) {
+	if b64 {
+		r = base64.NewDecoder(base64.StdEncoding, r)
+	}
+
+	var m [5]byte
+	_, err := io.ReadFull(r, m[:])
+	if err != nil || m != magic {
+		return nil, ErrInvalidMagic
+	}
+
+	p := new(Packet)
+	err = readMap(r, func(keyType byte, keyData, value []byte) error {
+		switch keyType {
+		case globalUnsignedTxType:
+			if len(keyData) != 0 {
+				return ErrInvalidKeyData
+			}
+			tx := new(wire.MsgTx)
+			err := tx.BtcDecode(bytes.NewReader(value), 0, wire.BaseEncoding)
+			if err != nil {
+				return ErrInvalidFormat
+			}
+			if !isUnsigned(tx) {
+				return ErrUnsignedTxHasScripts
+			}
+			p.UnsignedTx = tx
+		default:
+			p.Unknowns = append(p.Unknowns, newUnknown(keyType, keyData, value))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if p.UnsignedTx == nil {
+		return nil, ErrInvalidFormat
+	}
+
+	p.Inputs = make([]PInput, len(p.UnsignedTx.TxIn))
+	for i := range p.Inputs {
+		err := p.Inputs[i].deserialize(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+	p.Outputs = make([]POutput, len(p.UnsignedTx.TxOut))
+	for i := range p.Outputs {
+		err := p.Outputs[i].deserialize(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+// Serialize writes the BIP0174 serialization of the PSBT to w.
+func (p *Packet) Serialize(w io.Writer) error {
+	_, err := w.Write(magic[:])
+	if err != nil {
+		return err
+	}
+
+	var tx bytes.Buffer
+	err = p.UnsignedTx.BtcEncode(&tx, 0, wire.BaseEncoding)
+	if err != nil {
+		return err
+	}
+	err = writePair(w, globalUnsignedTxType, nil, tx.Bytes())
+	if err != nil {
+		return err
+	}
+	err = writeUnknowns(w, p.Unknowns)
+	if err != nil {
+		return err
+	}
+	err = writeSeparator(w)
+	if err != nil {
+		return err
+	}
+
+	for i := range p.Inputs {
+		err := p.Inputs[i].serialize(w)
+		if err != nil {
+			return err
+		}
+	}
+	for i := range p.Outputs {
+		err := p.Outputs[i].serialize(w)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// B64Encode returns the base64 encoding of the PSBT's serialization.
+func (p *Packet) B64Encode() (string, error) {
+	var buf bytes.Buffer
+	err := p.Serialize(&buf)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// IsComplete returns whether every input of the PSBT is finalized, so the
+// signed transaction can be extracted.
+func (p *Packet) IsComplete() bool {
+	for i := range p.Inputs {
+		if !p.Inputs[i].isFinalized() {
+			return false
+		}
+	}
+	return true
+}
+
+// PrevOutput returns the previous output spent by an input, as recorded by the
+// input's witness or non-witness UTXO.  Nil is returned if neither is recorded.
+// ErrInvalidUtxo is returned if the non-witness UTXO is not the transaction
+// referenced by the input.
+func (p *Packet) PrevOutput(index int) (*wire.TxOut, error) {
+	in := &p.Inputs[index]
+	if in.WitnessUtxo != nil {
+		return in.WitnessUtxo, nil
+	}
+	if in.NonWitnessUtxo == nil {
+		return nil, nil
+	}
+	prevOut := &p.UnsignedTx.TxIn[index].PreviousOutPoint
+	if in.NonWitnessUtxo.TxHash() != prevOut.Hash ||
+		prevOut.Index >= uint32(len(in.NonWitnessUtxo.TxOut)) {
+		return nil, ErrInvalidUtxo
+	}
+	return in.NonWitnessUtxo.TxOut[prevOut.Index], nil
+}
+
+// Combine merges the data of PSBTs for the same unsigned transaction, such as
+// the partial signatures created by different signers, into a new PSBT.
+func Combine(packets ...*Packet) (*Packet, error) {
+	if len(packets) == 0 {
+		return nil, errors.New("no PSBTs to combine")
+	}
+	txHash := packets[0].UnsignedTx.TxHash()
+	for _, p := range packets[1:] {
+		if p.UnsignedTx.TxHash() != txHash {
+			return nil, ErrMismatchedTx
+		}
+	}
+
+	combined, err := New(packets[0].UnsignedTx.Copy())
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range packets {
+		combined.Unknowns = mergeUnknowns(combined.Unknowns, p.Unknowns)
+		for i := range p.Inputs {
+			combined.Inputs[i].merge(&p.Inputs[i])
+		}
+		for i := range p.Outputs {
+			combined.Outputs[i].merge(&p.Outputs[i])
+		}
+	}
+	return combined, nil
+}
+
+// readMap reads the key-value pairs of a PSBT map until the separator,
+// calling f for each pair.  Duplicate keys are rejected.
+func readMap(r io.Reader, f func(keyType byte, keyData, value []byte) error) error {
+	seen := make(map[string]struct{})
+	for {
+		key, err := wire.ReadVarBytes(r, 0, maxKeyLength, "key")
+		if err != nil {
+			return ErrInvalidFormat
+		}
+		if len(key) == 0 {
+			return nil
+		}
+		if _, ok := seen[string(key)]; ok {
+			return ErrDuplicateKey
+		}
+		seen[string(key)] = struct{}{}
+
+		value, err := wire.ReadVarBytes(r, 0, maxValueLength, "value")
+		if err != nil {
+			return ErrInvalidFormat
+		}
+		err = f(key[0], key[1:], value)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func writePair(w io.Writer, keyType byte, keyData, value []byte) error {
+	key := make([]byte, 1+len(keyData))
+	key[0] = keyType
+	copy(key[1:], keyData)
+	err := wire.WriteVarBytes(w, 0, key)
+	if err != nil {
+		return err
+	}
+	return wire.WriteVarBytes(w, 0, value)
+}
+
+func writeSeparator(w io.Writer) error {
+	_, err := w.Write([]byte{0x00})
+	return err
+}
+
+func newUnknown(keyType byte, keyData, value []byte) *Unknown {
+	key := make([]byte, 1+len(keyData))
+	key[0] = keyType
+	copy(key[1:], keyData)
+	return &Unknown{Key: key, Value: value}
+}
+
+func writeUnknowns(w io.Writer, unknowns []*Unknown) error {
+	for _, u := range unknowns {
+		err := wire.WriteVarBytes(w, 0, u.Key)
+		if err != nil {
+			return err
+		}
+		err = wire.WriteVarBytes(w, 0, u.Value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeUnknowns appends the unknowns of src with keys not in dst to dst.
+func mergeUnknowns(dst, src []*Unknown) []*Unknown {
+next:
+	for _, u := range src {
+		for _, d := range dst {
+			if bytes.Equal(d.Key, u.Key) {
+				continue next
+			}
+		}
+		dst = append(dst, u)
+	}
+	return dst
+}
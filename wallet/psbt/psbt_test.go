@@ -0,0 +1,285 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package psbt_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	. "github.com/btcsuite/btcwallet/wallet/psbt"
+)
+
+var params = &chaincfg.MainNetParams
+
+// testSecrets is a SecretsSource holding keys and redeem scripts looked up by
+// their encoded addresses.
+type testSecrets struct {
+	keys    map[string]*btcec.PrivateKey
+	scripts map[string][]byte
+}
+
+func newTestSecrets() *testSecrets {
+	return &testSecrets{
+		keys:    make(map[string]*btcec.PrivateKey),
+		scripts: make(map[string][]byte),
+	}
+}
+
+func (s *testSecrets) addKey(t *testing.T, privKey *btcec.PrivateKey) {
+	pubKeyHash := btcutil.Hash160(privKey.PubKey().SerializeCompressed())
+	pkh, err := btcutil.NewAddressPubKeyHash(pubKeyHash, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wpkh, err := btcutil.NewAddressWitnessPubKeyHash(pubKeyHash, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.keys[pkh.EncodeAddress()] = privKey
+	s.keys[wpkh.EncodeAddress()] = privKey
+}
+
+func (s *testSecrets) addScript(t *testing.T, script []byte) {
+	addr, err := btcutil.NewAddressScriptHash(script, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.scripts[addr.EncodeAddress()] = script
+}
+
+func (s *testSecrets) GetKey(addr btcutil.Address) (*btcec.PrivateKey, bool, error) {
+	privKey, ok := s.keys[addr.EncodeAddress()]
+	if !ok {
+		return nil, false, errors.New("unknown key")
+	}
+	return privKey, true, nil
+}
+
+func (s *testSecrets) GetScript(addr btcutil.Address) ([]byte, error) {
+	script, ok := s.scripts[addr.EncodeAddress()]
+	if !ok {
+		return nil, errors.New("unknown script")
+	}
+	return script, nil
+}
+
+func (s *testSecrets) ChainParams() *chaincfg.Params {
+	return params
+}
+
+func payToAddrScript(t *testing.T, addr btcutil.Address) []byte {
+	script, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return script
+}
+
+func newKey(t *testing.T) *btcec.PrivateKey {
+	privKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return privKey
+}
+
+func TestSignFinalizeExtract(t *testing.T) {
+	k1, k2, k3 := newKey(t), newKey(t), newKey(t)
+
+	pubKeyHash := btcutil.Hash160(k1.PubKey().SerializeCompressed())
+	pkhAddr, err := btcutil.NewAddressPubKeyHash(pubKeyHash, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wpkhAddr, err := btcutil.NewAddressWitnessPubKeyHash(pubKeyHash, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	witnessProgram := payToAddrScript(t, wpkhAddr)
+	nestedAddr, err := btcutil.NewAddressScriptHash(witnessProgram, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pubKeys []*btcutil.AddressPubKey
+	for _, k := range []*btcec.PrivateKey{k1, k2, k3} {
+		pk, err := btcutil.NewAddressPubKey(k.PubKey().SerializeCompressed(),
+			params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubKeys = append(pubKeys, pk)
+	}
+	multisigScript, err := txscript.MultiSigScript(pubKeys, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	multisigAddr, err := btcutil.NewAddressScriptHash(multisigScript, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prevTx := wire.NewMsgTx(wire.TxVersion)
+	prevTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, nil, nil))
+	prevTx.AddTxOut(wire.NewTxOut(1e8, payToAddrScript(t, pkhAddr)))
+	prevTx.AddTxOut(wire.NewTxOut(2e8, witnessProgram))
+	prevTx.AddTxOut(wire.NewTxOut(3e8, payToAddrScript(t, nestedAddr)))
+	prevTx.AddTxOut(wire.NewTxOut(4e8, payToAddrScript(t, multisigAddr)))
+	prevHash := prevTx.TxHash()
+
+	tx := wire.NewMsgTx(wire.TxVersion)
+	for i := range prevTx.TxOut {
+		tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&prevHash, uint32(i)), nil, nil))
+	}
+	tx.AddTxOut(wire.NewTxOut(10e8-1e5, payToAddrScript(t, pkhAddr)))
+
+	p, err := New(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Inputs[0].NonWitnessUtxo = prevTx
+	p.Inputs[1].WitnessUtxo = prevTx.TxOut[1]
+	p.Inputs[2].WitnessUtxo = prevTx.TxOut[2]
+	p.Inputs[3].NonWitnessUtxo = prevTx
+	p.Inputs[3].RedeemScript = multisigScript
+
+	// The first signer owns k1 and knows the nested witness program, and
+	// the second signer only owns k2.  They sign copies of the PSBT which
+	// are then combined.
+	secrets1 := newTestSecrets()
+	secrets1.addKey(t, k1)
+	secrets1.addScript(t, witnessProgram)
+	secrets2 := newTestSecrets()
+	secrets2.addKey(t, k2)
+
+	p1 := roundTrip(t, p)
+	signed, err := Sign(p1, secrets1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signed) != 4 {
+		t.Fatalf("first signer signed inputs %v, expected all", signed)
+	}
+	err = Finalize(p1, 3)
+	if err != ErrNotFinalizable {
+		t.Fatalf("finalized multisig input with one signature: %v", err)
+	}
+
+	p2 := roundTrip(t, p)
+	signed, err = Sign(p2, secrets2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signed) != 1 || signed[0] != 3 {
+		t.Fatalf("second signer signed inputs %v, expected [3]", signed)
+	}
+
+	combined, err := Combine(roundTrip(t, p1), roundTrip(t, p2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(combined.Inputs[3].PartialSigs) != 2 {
+		t.Fatalf("combined multisig input has %d signatures",
+			len(combined.Inputs[3].PartialSigs))
+	}
+	if _, err := Extract(combined); err != ErrIncomplete {
+		t.Fatalf("extracted incomplete PSBT: %v", err)
+	}
+	err = MaybeFinalizeAll(combined)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !combined.IsComplete() {
+		t.Fatal("PSBT is not complete after finalizing all inputs")
+	}
+
+	signedTx, err := Extract(roundTrip(t, combined))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashCache := txscript.NewTxSigHashes(signedTx)
+	for i, prevOut := range prevTx.TxOut {
+		vm, err := txscript.NewEngine(prevOut.PkScript, signedTx, i,
+			txscript.StandardVerifyFlags, nil, hashCache, prevOut.Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := vm.Execute(); err != nil {
+			t.Errorf("input %d failed to validate: %v", i, err)
+		}
+	}
+}
+
+// roundTrip serializes and deserializes a PSBT, checking that the base64
+// encodings of both are equal.
+func roundTrip(t *testing.T, p *Packet) *Packet {
+	b64, err := p.B64Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := NewFromRawBytes(strings.NewReader(b64), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b64Decoded, err := decoded.B64Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b64 != b64Decoded {
+		t.Fatalf("PSBT serialization changed after round trip")
+	}
+	return decoded
+}
+
+func TestDeserializeErrors(t *testing.T) {
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: chainhash.Hash{1}}, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(1e8, []byte{txscript.OP_TRUE}))
+	p, err := New(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	err = p.Serialize(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid := buf.Bytes()
+
+	tests := []struct {
+		name string
+		b    []byte
+		err  error
+	}{
+		{"bad magic", append([]byte{0x00}, valid[1:]...), ErrInvalidMagic},
+		{"truncated", valid[:len(valid)-1], ErrInvalidFormat},
+		{
+			// Repeat the unsigned transaction pair of the global map.
+			"duplicate key",
+			append(append(append([]byte(nil), valid[:len(valid)-3]...),
+				valid[5:len(valid)-3]...), valid[len(valid)-3:]...),
+			ErrDuplicateKey,
+		},
+	}
+	for _, test := range tests {
+		_, err := NewFromRawBytes(bytes.NewReader(test.b), false)
+		if err != test.err {
+			t.Errorf("%s: got error %v, expected %v", test.name, err,
+				test.err)
+		}
+	}
+
+	tx.TxIn[0].SignatureScript = []byte{txscript.OP_TRUE}
+	if _, err := New(tx); err != ErrUnsignedTxHasScripts {
+		t.Errorf("created PSBT for signed transaction: %v", err)
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package psbt
+
+import (
+	"bytes"
+	"crypto/sha256"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/wallet/txauthor"
+)
+
+// Sign adds partial signatures to each input of the PSBT which can be signed
+// by keys of the secrets source.  Redeem scripts and witness scripts missing
+// from an input are looked up using the secrets source as well.  Inputs
+// without a recorded UTXO, finalized inputs and inputs spending scripts of
+// unknown keys are skipped.  Inputs are signed with the sighash type recorded
+// by the input, or SigHashAll if none is recorded.  The indexes of the signed
+// inputs are returned.
+//
+// Pay-to-pubkey, pay-to-pubkey-hash and multisig scripts are signed, both as
+// bare scripts and nested in p2sh or p2wsh outputs, as well as p2wkh outputs
+// and p2wkh outputs nested in p2sh.
+func Sign(p *Packet, secrets txauthor.SecretsSource) ([]int, error) {
+	chainParams := secrets.ChainParams()
+	sigHashes := txscript.NewTxSigHashes(p.UnsignedTx)
+
+	var signed []int
+	for i := range p.Inputs {
+		in := &p.Inputs[i]
+		if in.isFinalized() {
+			continue
+		}
+		prevOut, err := p.PrevOutput(i)
+		if err != nil {
+			return nil, err
+		}
+		if prevOut == nil {
+			continue
+		}
+
+		// Look up the scripts the wallet knows for script hash
+		// outputs if they were not provided by the creator.
+		script := prevOut.PkScript
+		isScriptHash := txscript.IsPayToScriptHash(script)
+		if isScriptHash && in.RedeemScript == nil {
+			addr, err := btcutil.NewAddressScriptHashFromHash(
+				script[2:22], chainParams)
+			if err != nil {
+				return nil, err
+			}
+			redeemScript, err := secrets.GetScript(addr)
+			if err != nil {
+				continue
+			}
+			in.RedeemScript = redeemScript
+		}
+		if isScriptHash {
+			script = in.RedeemScript
+		}
+		if txscript.IsPayToWitnessScriptHash(script) && in.WitnessScript == nil {
+			addr, err := btcutil.NewAddressWitnessScriptHash(
+				script[2:], chainParams)
+			if err != nil {
+				return nil, err
+			}
+			witnessScript, err := secrets.GetScript(addr)
+			if err != nil {
+				continue
+			}
+			in.WitnessScript = witnessScript
+		}
+
+		signScript, witness, err := signingScript(in, prevOut.PkScript)
+		if err != nil {
+			return nil, err
+		}
+		_, addrs, _, err := txscript.ExtractPkScriptAddrs(signScript,
+			chainParams)
+		if err != nil {
+			return nil, err
+		}
+
+		hashType := in.SighashType
+		if hashType == 0 {
+			hashType = txscript.SigHashAll
+		}
+
+		signedInput := false
+		for _, addr := range addrs {
+			// Keys for pubkeys in the script are looked up by
+			// their pubkey hash, and must match the serialization
+			// of the script.
+			var scriptPubKey []byte
+			if pkAddr, ok := addr.(*btcutil.AddressPubKey); ok {
+				scriptPubKey = pkAddr.ScriptAddress()
+				addr = pkAddr.AddressPubKeyHash()
+			}
+			privKey, compressed, err := secrets.GetKey(addr)
+			if err != nil || (witness && !compressed) {
+				continue
+			}
+			pubKey := serializePubKey(privKey, compressed)
+			if scriptPubKey != nil && !bytes.Equal(scriptPubKey, pubKey) {
+				continue
+			}
+
+			var sig []byte
+			if witness {
+				sig, err = txscript.RawTxInWitnessSignature(
+					p.UnsignedTx, sigHashes, i, prevOut.Value,
+					signScript, hashType, privKey)
+			} else {
+				sig, err = txscript.RawTxInSignature(p.UnsignedTx,
+					i, signScript, hashType, privKey)
+			}
+			if err != nil {
+				return nil, err
+			}
+			in.AddPartialSig(&PartialSig{PubKey: pubKey, Signature: sig})
+			signedInput = true
+		}
+		if signedInput {
+			signed = append(signed, i)
+		}
+	}
+	return signed, nil
+}
+
+func serializePubKey(privKey *btcec.PrivateKey, compressed bool) []byte {
+	if compressed {
+		return privKey.PubKey().SerializeCompressed()
+	}
+	return privKey.PubKey().SerializeUncompressed()
+}
+
+// signingScript returns the script which is signed to spend an input with the
+// previous output script pkScript, after resolving p2sh and p2wsh outputs
+// using the redeem and witness scripts of the input, and whether the input is
+// spent with a witness.
+func signingScript(in *PInput, pkScript []byte) ([]byte, bool, error) {
+	script := pkScript
+	if txscript.IsPayToScriptHash(script) {
+		if in.RedeemScript == nil || !bytes.Equal(
+			btcutil.Hash160(in.RedeemScript), script[2:22]) {
+			return nil, false, ErrInvalidScript
+		}
+		script = in.RedeemScript
+	}
+
+	switch {
+	case txscript.IsPayToWitnessPubKeyHash(script):
+		return script, true, nil
+
+	case txscript.IsPayToWitnessScriptHash(script):
+		if in.WitnessScript == nil {
+			return nil, false, ErrInvalidScript
+		}
+		scriptHash := sha256.Sum256(in.WitnessScript)
+		if !bytes.Equal(scriptHash[:], script[2:]) {
+			return nil, false, ErrInvalidScript
+		}
+		return in.WitnessScript, true, nil
+	}
+
+	return script, false, nil
+}
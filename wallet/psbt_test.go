@@ -0,0 +1,89 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wallet/psbt"
+)
+
+// TestFundPsbtLocks ensures the inputs added to a funded PSBT, and preset
+// inputs which were not locked, are locked with the PSBT lock, while preset
+// inputs already locked under another lock ID keep their lock.
+func TestFundPsbtLocks(t *testing.T) {
+	t.Parallel()
+
+	w, client, teardown := newTestWallet(t)
+	defer teardown()
+
+	addr, err := w.NewAddress(0, waddrmgr.PubKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	syncTestWallet(t, w, client)
+	funding := client.payTx(t, addr, 1e8, 1e8, 1e8)
+	client.mineBlock(t, funding)
+	waitForSync(t, w, client)
+	fundingHash := funding.TxHash()
+	ops := make([]wire.OutPoint, len(funding.TxOut))
+	for i := range ops {
+		ops[i] = *wire.NewOutPoint(&fundingHash, uint32(i))
+		waitForUnspent(t, w, ops[i])
+	}
+
+	// The first preset input is locked by the user, and the second is not
+	// locked.
+	err = w.LockOutpoint(ops[0], "user", time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(wire.NewTxIn(&ops[0], nil, nil))
+	tx.AddTxIn(wire.NewTxIn(&ops[1], nil, nil))
+	tx.AddTxOut(wire.NewTxOut(2.5e8, []byte{txscript.OP_TRUE}))
+	packet, err := psbt.New(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = w.FundPsbt(packet, 0, 1, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(packet.UnsignedTx.TxIn); n != 3 {
+		t.Fatalf("funded PSBT has %d inputs, expected 3", n)
+	}
+
+	tests := []struct {
+		op     wire.OutPoint
+		lockID string
+		expiry bool
+	}{
+		{ops[0], "user", false},
+		{ops[1], psbtLockID, true},
+		{ops[2], psbtLockID, true},
+	}
+	for _, test := range tests {
+		lock, err := w.TxStore.LockedOutput(test.op)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lock == nil {
+			t.Errorf("output %v is not locked", test.op)
+			continue
+		}
+		if lock.LockID != test.lockID ||
+			lock.Expiration.IsZero() == test.expiry {
+
+			t.Errorf("output %v locked with ID %q until %v, "+
+				"expected ID %q", test.op, lock.LockID,
+				lock.Expiration, test.lockID)
+		}
+	}
+}
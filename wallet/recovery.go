@@ -0,0 +1,478 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/chain"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+)
+
+// recoveryBatchSize is the number of blocks rescanned by each batch of a
+// recovery rescan, which is a single rescanblocks request to a consensus RPC
+// server.
+const recoveryBatchSize = 2000
+
+// SetRecoveryWindow sets the gap limit used to discover the addresses and
+// accounts of a wallet restored from its seed.  While the window is nonzero,
+// each sync with the chain server rescans the blocks since the wallet's synced
+// block, keeping window unused addresses derived after the last address found
+// used on every account branch.  When a rescan finds an address beyond those
+// previously derived, more addresses are derived and the blocks are rescanned
+// for them, until no further addresses are found.  Accounts following the last
+// account are discovered in the same way when the last account is found used,
+// but only while the wallet is unlocked, since deriving account keys requires
+// the private keys.  A zero window, the default, disables discovery.
+//
+// The window is also the gap limit of watch-only accounts, which is 20 while
+// discovery is disabled.
+func (w *Wallet) SetRecoveryWindow(window uint32) {
+	w.recoveryMu.Lock()
+	w.recoveryWindow = window
+	w.recoveryMu.Unlock()
+}
+
+// RecoveryWindow returns the gap limit used to discover addresses and
+// accounts.  See SetRecoveryWindow for details.
+func (w *Wallet) RecoveryWindow() uint32 {
+	w.recoveryMu.Lock()
+	window := w.recoveryWindow
+	w.recoveryMu.Unlock()
+	return window
+}
+
+// branchKey identifies the external or internal branch of an account.
+type branchKey struct {
+	account  uint32
+	internal bool
+}
+
+// recoveryState records the addresses found used during recovery.
+type recoveryState struct {
+	window uint32
+
+	// nextUnused is one more than the index of the last address found
+	// used on each branch.
+	nextUnused map[branchKey]uint32
+
+	// usedAccounts records the accounts with any address found used.
+	usedAccounts map[uint32]struct{}
+
+	// lockedWarned is set once the wallet has been found locked when
+	// attempting to discover the next account, so the warning is only
+	// logged once.
+	lockedWarned bool
+}
+
+func newRecoveryState(window uint32) *recoveryState {
+	return &recoveryState{
+		window:       window,
+		nextUnused:   make(map[branchKey]uint32),
+		usedAccounts: make(map[uint32]struct{}),
+	}
+}
+
+// markUsed records the address at index of a branch as used.
+func (s *recoveryState) markUsed(account uint32, internal bool, index uint32) {
+	key := branchKey{account, internal}
+	if index+1 > s.nextUnused[key] {
+		s.nextUnused[key] = index + 1
+	}
+	s.usedAccounts[account] = struct{}{}
+}
+
+// horizon returns the number of addresses which must be derived on a branch
+// to watch window addresses after the last used address.
+func (s *recoveryState) horizon(account uint32, internal bool) uint32 {
+	return s.nextUnused[branchKey{account, internal}] + s.window
+}
+
+// recoverAddresses discovers the addresses and accounts used in the blocks
+// from start through the chain server's best block, in rounds of rescans of
+// these blocks.  The first round rescans the blocks for every wallet address,
+// after deriving addresses up to the gap limit of each account branch, and
+// each further round rescans them for the addresses derived after finding
+// addresses used by the previous round.  The block the wallet is recovered
+// through is returned.
+func (w *Wallet) recoverAddresses(chainClient chain.Interface,
+	start waddrmgr.BlockStamp, window uint32) (*waddrmgr.BlockStamp, error) {
+
+	hash, height, err := chainClient.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+	end := &waddrmgr.BlockStamp{Hash: *hash, Height: height}
+	if start.Height > end.Height {
+		return end, nil
+	}
+	log.Infof("Recovering addresses with a gap limit of %d from block "+
+		"height %d through %d", window, start.Height, end.Height)
+
+	addrs, unspent, err := w.activeData()
+	if err != nil {
+		return nil, err
+	}
+	outpoints := make([]wire.OutPoint, len(unspent))
+	for i := range unspent {
+		outpoints[i] = unspent[i].OutPoint
+	}
+
+	state := newRecoveryState(window)
+	for round := 1; ; round++ {
+		derived, err := w.extendRecoveryHorizon(state)
+		if err != nil {
+			return nil, err
+		}
+		if round > 1 && len(derived) == 0 {
+			break
+		}
+		scan := derived
+		if round == 1 {
+			scan = append(addrs, derived...)
+		}
+
+		noun := pickNoun(len(scan), "address", "addresses")
+		log.Infof("Recovery round %d: rescanning for %d %s", round,
+			len(scan), noun)
+		err = w.recoveryRescan(chainClient, state, scan, outpoints,
+			start.Height, end.Height)
+		if err != nil {
+			return nil, err
+		}
+
+		// Outputs of the wallet found by a rescan are added to the
+		// rescan's filter as they are found, so only the first round
+		// must watch the unspent outputs.
+		outpoints = nil
+	}
+
+	log.Infof("Finished recovery through block %v (height %d)", end.Hash,
+		end.Height)
+	return end, nil
+}
+
+// extendRecoveryHorizon derives the addresses of every account branch up to
+// the gap limit following the last address found used, and creates the next
+// account when the last account was found used.  The derived addresses are
+// returned.
+func (w *Wallet) extendRecoveryHorizon(state *recoveryState) ([]btcutil.Address, error) {
+	lastAccount, err := w.Manager.LastAccount()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := state.usedAccounts[lastAccount]; ok {
+		err := w.discoverNextAccount(state, lastAccount)
+		if err != nil {
+			return nil, err
+		}
+		lastAccount, err = w.Manager.LastAccount()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var derived []btcutil.Address
+	for account := uint32(0); account <= lastAccount; account++ {
+		props, err := w.Manager.AccountProperties(account)
+		if err != nil {
+			return nil, err
+		}
+		for _, internal := range []bool{false, true} {
+			count := props.ExternalKeyCount
+			if internal {
+				count = props.InternalKeyCount
+			}
+			horizon := state.horizon(account, internal)
+			if count >= horizon {
+				continue
+			}
+			addrs, err := w.deriveAccountAddresses(account,
+				horizon-count, internal)
+			if err != nil {
+				return nil, err
+			}
+			derived = append(derived, addrs...)
+		}
+	}
+	return derived, nil
+}
+
+// discoverNextAccount creates the account following the last account, which
+// was found used, deriving addresses of the same type.  Account keys can only
+// be derived while the wallet is unlocked, and watch-only accounts are not
+// followed by further accounts, so no account is created in these cases.
+func (w *Wallet) discoverNextAccount(state *recoveryState, lastAccount uint32) error {
+	props, err := w.Manager.AccountProperties(lastAccount)
+	if err != nil {
+		return err
+	}
+	if props.WatchOnly {
+		return nil
+	}
+	if w.Manager.IsLocked() {
+		if !state.lockedWarned {
+			log.Warnf("Account %d is used, but following accounts "+
+				"can not be discovered while the wallet is "+
+				"locked", lastAccount)
+			state.lockedWarned = true
+		}
+		return nil
+	}
+
+	name := fmt.Sprintf("account %d", lastAccount+1)
+	account, err := w.NextAccount(name, props.AddressType)
+	if err != nil {
+		return err
+	}
+	log.Infof("Created account %d to discover its addresses", account)
+	return nil
+}
+
+// recoveredBlock holds the relevant transactions found in a block by a
+// recovery rescan.
+type recoveredBlock struct {
+	hash chainhash.Hash
+	time time.Time
+	txs  []*wire.MsgTx
+}
+
+// recoveryRescan rescans the blocks from height from through to for
+// transactions paying to addrs or spending outpoints.  Relevant transactions
+// are added to the wallet, and the addresses they pay are recorded as used.
+//
+// Consensus RPC servers filter the blocks with the rescanblocks extension,
+// while the blocks are fetched and filtered by the wallet for other chain
+// backends.  This includes consensus RPC servers shared by several wallets,
+// whose transaction filter must not be replaced by the filter of one wallet.
+func (w *Wallet) recoveryRescan(chainClient chain.Interface,
+	state *recoveryState, addrs []btcutil.Address, outpoints []wire.OutPoint,
+	from, to int32) error {
+
+	var rescanBlocks func([]chainhash.Hash) ([]recoveredBlock, error)
+	if rpcClient, ok := chainClient.(*chain.RPCClient); ok {
+		err := rpcClient.LoadTxFilter(true, addrs, outpoints)
+		if err != nil {
+			return err
+		}
+		rescanBlocks = func(hashes []chainhash.Hash) ([]recoveredBlock, error) {
+			return rescanBlocksRPC(rpcClient, hashes)
+		}
+	} else {
+		filter, err := newRecoveryFilter(addrs, outpoints)
+		if err != nil {
+			return err
+		}
+		rescanBlocks = func(hashes []chainhash.Hash) ([]recoveredBlock, error) {
+			return filter.rescanBlocks(chainClient, hashes)
+		}
+	}
+
+	for height := from; height <= to; height += recoveryBatchSize {
+		last := height + recoveryBatchSize - 1
+		if last > to {
+			last = to
+		}
+
+		hashes := make([]chainhash.Hash, 0, last-height+1)
+		heights := make(map[chainhash.Hash]int32, last-height+1)
+		for h := height; h <= last; h++ {
+			hash, err := chainClient.GetBlockHash(int64(h))
+			if err != nil {
+				return err
+			}
+			hashes = append(hashes, *hash)
+			heights[*hash] = h
+		}
+		blocks, err := rescanBlocks(hashes)
+		if err != nil {
+			return err
+		}
+
+		for _, b := range blocks {
+			block := &wtxmgr.BlockMeta{
+				Block: wtxmgr.Block{
+					Hash:   b.hash,
+					Height: heights[b.hash],
+				},
+				Time: b.time,
+			}
+			for _, tx := range b.txs {
+				rec, err := wtxmgr.NewTxRecordFromMsgTx(tx, b.time)
+				if err != nil {
+					return err
+				}
+				err = w.addRelevantTx(rec, block)
+				if err != nil {
+					return err
+				}
+				err = w.markRecoveredAddresses(state, &rec.MsgTx)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		log.Infof("Recovery rescanned through block height %d", last)
+	}
+	return nil
+}
+
+// rescanBlocksRPC rescans blocks with the rescanblocks extension of a
+// consensus RPC server, using the transaction filter loaded by the client.
+func rescanBlocksRPC(chainClient *chain.RPCClient,
+	hashes []chainhash.Hash) ([]recoveredBlock, error) {
+
+	blocks, err := chainClient.RescanBlocks(hashes)
+	if err != nil {
+		return nil, err
+	}
+	recovered := make([]recoveredBlock, 0, len(blocks))
+	for _, b := range blocks {
+		hash, err := chainhash.NewHashFromStr(b.Hash)
+		if err != nil {
+			return nil, err
+		}
+		header, err := chainClient.GetBlockHeader(hash)
+		if err != nil {
+			return nil, err
+		}
+		txs := make([]*wire.MsgTx, 0, len(b.Transactions))
+		for _, txHex := range b.Transactions {
+			serializedTx, err := hex.DecodeString(txHex)
+			if err != nil {
+				return nil, err
+			}
+			var tx wire.MsgTx
+			err = tx.Deserialize(bytes.NewReader(serializedTx))
+			if err != nil {
+				return nil, err
+			}
+			txs = append(txs, &tx)
+		}
+		recovered = append(recovered, recoveredBlock{
+			hash: *hash,
+			time: header.Timestamp,
+			txs:  txs,
+		})
+	}
+	return recovered, nil
+}
+
+// recoveryFilter matches the transactions of blocks fetched from chain
+// backends which do not filter blocks for the wallet.  Like the filter of a
+// consensus RPC server, outputs paying to the filtered scripts are added to
+// the filter as they are found, so that later spends of them are matched.
+type recoveryFilter struct {
+	scripts   map[string]struct{}
+	outpoints map[wire.OutPoint]struct{}
+}
+
+func newRecoveryFilter(addrs []btcutil.Address, outpoints []wire.OutPoint) (*recoveryFilter, error) {
+	f := &recoveryFilter{
+		scripts:   make(map[string]struct{}, len(addrs)),
+		outpoints: make(map[wire.OutPoint]struct{}, len(outpoints)),
+	}
+	for _, addr := range addrs {
+		script, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			return nil, err
+		}
+		f.scripts[string(script)] = struct{}{}
+	}
+	for _, op := range outpoints {
+		f.outpoints[op] = struct{}{}
+	}
+	return f, nil
+}
+
+// match returns whether a transaction pays to a filtered script or spends a
+// filtered outpoint, adding the outputs paying to filtered scripts to the
+// filter.
+func (f *recoveryFilter) match(tx *wire.MsgTx) bool {
+	matched := false
+	for _, in := range tx.TxIn {
+		if _, ok := f.outpoints[in.PreviousOutPoint]; ok {
+			matched = true
+			break
+		}
+	}
+	txHash := tx.TxHash()
+	for i, out := range tx.TxOut {
+		if _, ok := f.scripts[string(out.PkScript)]; ok {
+			op := wire.OutPoint{Hash: txHash, Index: uint32(i)}
+			f.outpoints[op] = struct{}{}
+			matched = true
+		}
+	}
+	return matched
+}
+
+// rescanBlocks fetches blocks from the chain backend and returns the blocks
+// with transactions matched by the filter.
+func (f *recoveryFilter) rescanBlocks(chainClient chain.Interface,
+	hashes []chainhash.Hash) ([]recoveredBlock, error) {
+
+	var recovered []recoveredBlock
+	for i := range hashes {
+		block, err := chainClient.GetBlock(&hashes[i])
+		if err != nil {
+			return nil, err
+		}
+		var txs []*wire.MsgTx
+		for _, tx := range block.Transactions {
+			if f.match(tx) {
+				txs = append(txs, tx)
+			}
+		}
+		if len(txs) != 0 {
+			recovered = append(recovered, recoveredBlock{
+				hash: hashes[i],
+				time: block.Header.Timestamp,
+				txs:  txs,
+			})
+		}
+	}
+	return recovered, nil
+}
+
+// markRecoveredAddresses records the chained wallet addresses paid by the
+// outputs of tx as used.
+func (w *Wallet) markRecoveredAddresses(state *recoveryState, tx *wire.MsgTx) error {
+	for _, output := range tx.TxOut {
+		_, addrs, _, err := txscript.ExtractPkScriptAddrs(
+			output.PkScript, w.chainParams)
+		if err != nil {
+			// Non-standard outputs are skipped.
+			continue
+		}
+		for _, addr := range addrs {
+			ma, err := w.Manager.Address(addr)
+			if waddrmgr.IsError(err, waddrmgr.ErrAddressNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if ma.Imported() {
+				continue
+			}
+			_, index, err := w.Manager.AddrBranchIndex(ma.Address())
+			if err != nil {
+				return err
+			}
+			state.markUsed(ma.Account(), ma.Internal(), index)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+)
+
+// recoveryAddresses returns the first n external addresses of an account of a
+// wallet of the test seed, which are derived by a separate wallet so the
+// recovered wallet derives none of them itself.  Accounts are created as
+// needed.
+func recoveryAddresses(t *testing.T, account, n uint32) []btcutil.Address {
+	w, _, teardown := newTestWallet(t)
+	defer teardown()
+
+	err := w.Unlock(testPrivPass, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	props, err := w.Manager.AccountProperties(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for a := uint32(1); a <= account; a++ {
+		_, err := w.NextAccount(fmt.Sprintf("account %d", a),
+			props.AddressType)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	managed, err := w.Manager.NextExternalAddresses(account, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addrs := make([]btcutil.Address, n)
+	for i, ma := range managed {
+		addrs[i] = ma.Address()
+	}
+	return addrs
+}
+
+// checkKeyCounts checks the number of external and internal addresses derived
+// for an account.
+func checkKeyCounts(t *testing.T, w *Wallet, account, external,
+	internal uint32) {
+
+	props, err := w.Manager.AccountProperties(account)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if props.ExternalKeyCount != external ||
+		props.InternalKeyCount != internal {
+
+		t.Errorf("account %d has %d external and %d internal "+
+			"addresses, expected %d and %d", account,
+			props.ExternalKeyCount, props.InternalKeyCount,
+			external, internal)
+	}
+}
+
+// checkUnspentCount checks the number of unspent outputs of the wallet.
+func checkUnspentCount(t *testing.T, w *Wallet, want int) {
+	unspent, err := w.TxStore.UnspentOutputs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unspent) != want {
+		t.Errorf("wallet has %d unspent outputs, expected %d",
+			len(unspent), want)
+	}
+}
+
+// TestRecoverAddresses ensures addresses used beyond the initial recovery
+// window are found by further rounds, as long as each is within the window of
+// the last address found used, and the window is kept derived after the last
+// used address.
+func TestRecoverAddresses(t *testing.T) {
+	t.Parallel()
+
+	addrs := recoveryAddresses(t, 0, 40)
+	w, client, teardown := newTestWallet(t)
+	defer teardown()
+
+	// Each address is beyond the horizon of the addresses before the
+	// previous one, so every round finds a single address.  The address
+	// at index 30 is beyond the window of the last used address, and is
+	// not found.
+	const window = 5
+	for _, index := range []int{3, 7, 12, 30} {
+		client.mineBlock(t, client.payTx(t, addrs[index], 1e6))
+	}
+
+	start := waddrmgr.BlockStamp{Height: 1}
+	end, err := w.recoverAddresses(client, start, window)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if end.Height != 4 {
+		t.Errorf("recovered through height %d, expected 4", end.Height)
+	}
+	checkKeyCounts(t, w, 0, 12+1+window, window)
+	checkUnspentCount(t, w, 3)
+
+	// Recovering again finds nothing further.
+	_, err = w.recoverAddresses(client, start, window)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkKeyCounts(t, w, 0, 12+1+window, window)
+	checkUnspentCount(t, w, 3)
+}
+
+// TestRecoverNextAccount ensures the account following a used account is
+// created and its addresses are discovered while the wallet is unlocked.
+func TestRecoverNextAccount(t *testing.T) {
+	t.Parallel()
+
+	addrs0 := recoveryAddresses(t, 0, 1)
+	addrs1 := recoveryAddresses(t, 1, 2)
+	w, client, teardown := newTestWallet(t)
+	defer teardown()
+	err := w.Unlock(testPrivPass, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client.mineBlock(t, client.payTx(t, addrs0[0], 1e6))
+	client.mineBlock(t, client.payTx(t, addrs1[1], 1e6))
+
+	const window = 3
+	_, err = w.recoverAddresses(client, waddrmgr.BlockStamp{Height: 1},
+		window)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Account 1 was found used, so account 2 was created as well.
+	lastAccount, err := w.Manager.LastAccount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lastAccount != 2 {
+		t.Fatalf("last account is %d, expected 2", lastAccount)
+	}
+	checkKeyCounts(t, w, 0, 1+window, window)
+	checkKeyCounts(t, w, 1, 2+window, window)
+	checkKeyCounts(t, w, 2, window, window)
+	checkUnspentCount(t, w, 2)
+}
+
+// TestRecoverLocked ensures accounts are not discovered while the wallet is
+// locked, while the addresses of existing accounts still are.
+func TestRecoverLocked(t *testing.T) {
+	t.Parallel()
+
+	addrs0 := recoveryAddresses(t, 0, 1)
+	addrs1 := recoveryAddresses(t, 1, 1)
+	w, client, teardown := newTestWallet(t)
+	defer teardown()
+
+	client.mineBlock(t, client.payTx(t, addrs0[0], 1e6))
+	client.mineBlock(t, client.payTx(t, addrs1[0], 1e6))
+
+	const window = 3
+	_, err := w.recoverAddresses(client, waddrmgr.BlockStamp{Height: 1},
+		window)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lastAccount, err := w.Manager.LastAccount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lastAccount != 0 {
+		t.Fatalf("last account is %d, expected 0", lastAccount)
+	}
+	checkKeyCounts(t, w, 0, 1+window, window)
+	checkUnspentCount(t, w, 1)
+}
+
+// TestAccountGapLimit ensures watch-only accounts use the recovery window as
+// their gap limit, or the default gap limit while discovery is disabled.
+func TestAccountGapLimit(t *testing.T) {
+	t.Parallel()
+
+	w, _, teardown := newTestWallet(t)
+	defer teardown()
+
+	if limit := w.accountGapLimit(); limit != defaultAccountGapLimit {
+		t.Errorf("gap limit is %d, expected %d", limit,
+			defaultAccountGapLimit)
+	}
+	w.SetRecoveryWindow(50)
+	if limit := w.accountGapLimit(); limit != 50 {
+		t.Errorf("gap limit is %d, expected 50", limit)
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"github.com/btcsuite/btcwallet/chain"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+)
+
+// blockHashBatchSize is the number of block hashes recorded in each database
+// transaction when filling in the hashes of rescanned blocks.
+const blockHashBatchSize = 2000
+
+// rollback rolls the wallet back to the block described by bs, which must be a
+// block of the wallet's synced chain, removing all later blocks from the
+// address manager's sync state and moving the transactions mined in them back
+// to the unconfirmed pool.
+//
+// The address manager and transaction store are kept in separate database
+// namespaces and cannot be updated in a single transaction.  Instead, the
+// rollback is recorded as pending in the same transaction that rolls back the
+// address manager, and only finished once the transaction store has been
+// rolled back as well.  A rollback interrupted by a crash or shutdown is
+// completed by finishPendingRollback the next time the wallet syncs.
+func (w *Wallet) rollback(bs *waddrmgr.BlockStamp) error {
+	log.Infof("Rolling back to block %v (height %d)", bs.Hash, bs.Height)
+
+	err := w.Manager.BeginRollback(bs)
+	if err != nil {
+		return err
+	}
+	// Rollback unconfirms transactions at and beyond the passed height, so
+	// add one to the new synced-to height to prevent unconfirming txs from
+	// the synced-to block.
+	err = w.TxStore.Rollback(bs.Height + 1)
+	if err != nil {
+		return err
+	}
+	return w.Manager.FinishRollback()
+}
+
+// finishPendingRollback completes a rollback that was interrupted before the
+// transaction store was rolled back.
+func (w *Wallet) finishPendingRollback() error {
+	bs, err := w.Manager.PendingRollback()
+	if err != nil || bs == nil {
+		return err
+	}
+
+	log.Infof("Finishing interrupted rollback to block %v (height %d)",
+		bs.Hash, bs.Height)
+	err = w.TxStore.Rollback(bs.Height + 1)
+	if err != nil {
+		return err
+	}
+	return w.Manager.FinishRollback()
+}
+
+// findForkPoint returns the latest block of the wallet's synced chain which is
+// also in the main chain of the chain server.  The recorded hashes of the
+// synced blocks are compared against the chain server from the synced block
+// down, skipping blocks without a recorded hash, so the fork point is found
+// regardless of the depth of a reorganization.  The genesis block is returned
+// when no other synced block remains in the main chain.
+func (w *Wallet) findForkPoint(chainClient chain.Interface) (*waddrmgr.BlockStamp, error) {
+	_, bestHeight, err := chainClient.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	height := w.Manager.SyncedTo().Height
+	if height > bestHeight {
+		height = bestHeight
+	}
+	for ; height > 0; height-- {
+		hash, err := w.Manager.BlockHash(height)
+		if waddrmgr.IsError(err, waddrmgr.ErrBlockNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		mainHash, err := chainClient.GetBlockHash(int64(height))
+		if err != nil {
+			return nil, err
+		}
+		if *mainHash == *hash {
+			return &waddrmgr.BlockStamp{Hash: *hash, Height: height}, nil
+		}
+		log.Debugf("Synced block %v (height %d) is no longer in the "+
+			"main chain", hash, height)
+	}
+
+	return &waddrmgr.BlockStamp{
+		Hash:   *w.chainParams.GenesisHash,
+		Height: 0,
+	}, nil
+}
+
+// fillBlockHashes records the hashes of the main chain blocks from height
+// start through end, which the wallet has been synced through without being
+// marked synced to each block.
+func (w *Wallet) fillBlockHashes(chainClient chain.Interface, start, end int32) error {
+	stamps := make([]waddrmgr.BlockStamp, 0, blockHashBatchSize)
+	for height := start; height <= end; height++ {
+		hash, err := chainClient.GetBlockHash(int64(height))
+		if err != nil {
+			return err
+		}
+		stamps = append(stamps, waddrmgr.BlockStamp{
+			Hash:   *hash,
+			Height: height,
+		})
+
+		if len(stamps) == blockHashBatchSize || height == end {
+			err := w.Manager.PutBlockHashes(stamps)
+			if err != nil {
+				return err
+			}
+			stamps = stamps[:0]
+		}
+	}
+	return nil
+}
+
+// setSyncedToScanned marks the wallet synced to the block described by bs,
+// which has been reached by scanning the blocks after the current synced
+// block, such as by a rescan or recovery.  The hashes of the skipped blocks
+// are recorded so that a later reorganization can be rolled back to the
+// exact fork point.
+func (w *Wallet) setSyncedToScanned(chainClient chain.Interface, bs *waddrmgr.BlockStamp) error {
+	prev := w.Manager.SyncedTo()
+	err := w.Manager.SetSyncedTo(bs)
+	if err != nil {
+		return err
+	}
+	if prev.Height+1 >= bs.Height {
+		return nil
+	}
+	return w.fillBlockHashes(chainClient, prev.Height+1, bs.Height-1)
+}
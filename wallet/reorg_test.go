@@ -0,0 +1,232 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcwallet/chain"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+)
+
+// recentHashesWindow is the number of recent block hashes kept by the address
+// manager, which limited the depth of rollbacks before the hashes of all synced
+// blocks were recorded.
+const recentHashesWindow = 20
+
+// mainBlockMeta returns the block of the main chain of the mock chain at the
+// height.
+func mainBlockMeta(t *testing.T, client *testClient, height int32) *wtxmgr.BlockMeta {
+	hash, err := client.GetBlockHash(int64(height))
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, err := client.GetBlockHeader(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &wtxmgr.BlockMeta{
+		Block: wtxmgr.Block{Hash: *hash, Height: height},
+		Time:  header.Timestamp,
+	}
+}
+
+// mainBlockStamp returns the block stamp of the main chain block of the mock
+// chain at the height.
+func mainBlockStamp(t *testing.T, client *testClient, height int32) *waddrmgr.BlockStamp {
+	meta := mainBlockMeta(t, client, height)
+	return &waddrmgr.BlockStamp{Hash: meta.Hash, Height: meta.Height}
+}
+
+// restartTestWallet stops the wallet and its client, and opens the wallet
+// again from its database with a new client.  The chain of the new client
+// holds the main chain blocks of the old client through forkHeight, so blocks
+// mined with the new client fork from the old chain after forkHeight.  The
+// returned teardown does not close the database, which is closed by the
+// teardown of the stopped wallet.
+func restartTestWallet(t *testing.T, w *Wallet, client *testClient,
+	forkHeight int32) (*Wallet, *testClient, func()) {
+
+	w.Stop()
+	w.WaitForShutdown()
+
+	newClient := &testClient{
+		MockClient: chain.NewMockClient(testParams),
+		nonce:      client.nextNonce(),
+	}
+	for height := int32(1); height <= forkHeight; height++ {
+		hash, err := client.GetBlockHash(int64(height))
+		if err != nil {
+			t.Fatal(err)
+		}
+		block, err := client.GetBlock(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = newClient.ConnectBlock(block)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	newWallet, err := Open(w.db, testPubPass, nil, testParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newWallet.Start()
+	teardown := func() {
+		newWallet.Stop()
+		newWallet.WaitForShutdown()
+		newClient.Stop()
+		newClient.WaitForShutdown()
+	}
+	return newWallet, newClient, teardown
+}
+
+// TestDeepReorg ensures a wallet syncing after a reorganization of more blocks
+// than the old window of recent block hashes is rolled back to the fork point,
+// rather than only as far as the recent hashes reach.
+func TestDeepReorg(t *testing.T) {
+	t.Parallel()
+
+	w, client, teardown := newTestWallet(t)
+	defer teardown()
+
+	addr, err := w.NewAddress(0, waddrmgr.PubKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	syncTestWallet(t, w, client)
+
+	client.mineBlocks(t, 2)
+	payment := client.payTx(t, addr, 1e6)
+	client.mineBlock(t, payment)
+	client.mineBlocks(t, recentHashesWindow+5)
+	waitForSync(t, w, client)
+	paymentHash := payment.TxHash()
+	waitForTxHeight(t, w, paymentHash, 3)
+
+	// Replace every block after height 2 while the wallet is stopped.
+	w, client, teardown2 := restartTestWallet(t, w, client, 2)
+	defer teardown2()
+	client.mineBlocks(t, recentHashesWindow+10)
+	syncTestWallet(t, w, client)
+
+	waitForTxHeight(t, w, paymentHash, -1)
+	checkUnspentCount(t, w, 1)
+	checkBlockHashes(t, w, client)
+}
+
+// TestFinishPendingRollback ensures a rollback interrupted before the
+// transaction store was rolled back is finished when the wallet next syncs.
+func TestFinishPendingRollback(t *testing.T) {
+	t.Parallel()
+
+	w, client, teardown := newTestWallet(t)
+	defer teardown()
+
+	addr, err := w.NewAddress(0, waddrmgr.PubKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	syncTestWallet(t, w, client)
+
+	client.mineBlocks(t, 1)
+	payment := client.payTx(t, addr, 1e6)
+	client.mineBlock(t, payment)
+	client.mineBlocks(t, 1)
+	waitForSync(t, w, client)
+	paymentHash := payment.TxHash()
+	waitForTxHeight(t, w, paymentHash, 2)
+
+	// Roll back the address manager to block 1, as if the wallet was
+	// stopped before the transaction store was rolled back as well.  The
+	// address manager is then synced to a block of the new main chain, so
+	// only the pending rollback records that the payment's block was
+	// rolled back.
+	err = w.Manager.BeginRollback(mainBlockStamp(t, client, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, client, teardown2 := restartTestWallet(t, w, client, 1)
+	defer teardown2()
+	client.mineBlocks(t, 3)
+	syncTestWallet(t, w, client)
+
+	pending, err := w.Manager.PendingRollback()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending != nil {
+		t.Errorf("rollback to block %v (height %d) is still pending",
+			pending.Hash, pending.Height)
+	}
+	waitForTxHeight(t, w, paymentHash, -1)
+	checkBlockHashes(t, w, client)
+}
+
+// TestReorgHashGaps ensures a wallet synced through blocks without recorded
+// hashes, such as by rescans before the hashes of all synced blocks were
+// recorded, is rolled back to the latest synced block with a recorded hash
+// which remains in the main chain when a block is disconnected, and rescans
+// the main chain blocks after it.
+func TestReorgHashGaps(t *testing.T) {
+	t.Parallel()
+
+	w, client, teardown := newTestWallet(t)
+	defer teardown()
+
+	addr, err := w.NewAddress(0, waddrmgr.PubKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.mineBlocks(t, 3)
+	payment := client.payTx(t, addr, 1e6)
+	client.mineBlock(t, payment)
+	client.mineBlocks(t, 2)
+
+	// Sync the wallet through block 2, and from there to block 6 without
+	// recording the hashes of the blocks in between, recording the payment
+	// mined in block 4.
+	for height := int32(1); height <= 2; height++ {
+		err = w.Manager.SetSyncedTo(mainBlockStamp(t, client, height))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = w.Manager.SetSyncedTo(mainBlockStamp(t, client, 6))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec, err := wtxmgr.NewTxRecordFromMsgTx(payment, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.addRelevantTx(rec, mainBlockMeta(t, client, 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for height := int32(3); height <= 5; height++ {
+		_, err := w.Manager.BlockHash(height)
+		if !waddrmgr.IsError(err, waddrmgr.ErrBlockNotFound) {
+			t.Fatalf("hash of block %d is recorded", height)
+		}
+	}
+	syncTestWallet(t, w, client)
+
+	// Disconnecting block 6 rolls the wallet back to block 2, since the
+	// hash of block 5 is unknown.  The following rescan finds the payment
+	// in block 4 again.
+	err = client.DisconnectBlocks(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForSync(t, w, client)
+	paymentHash := payment.TxHash()
+	waitForTxHeight(t, w, paymentHash, 4)
+	checkBlockHashes(t, w, client)
+}
@@ -96,7 +96,9 @@ func (b *rescanBatch) done(err error) {
 
 // rescanBatchHandler handles incoming rescan request, serializing rescan
 // submissions, and possibly batching many waiting requests together so they
-// can be handled by a single rescan after the current one completes.
+// can be handled by a single rescan after the current one completes.  Sends to
+// the other rescan goroutines are abandoned when the wallet is stopped, since
+// those goroutines may have already exited.
 func (w *Wallet) rescanBatchHandler() {
 	var curBatch, nextBatch *rescanBatch
 	quit := w.quitChan()
@@ -109,7 +111,11 @@ out:
 				// Set current batch as this job and send
 				// request.
 				curBatch = job.batch()
-				w.rescanBatch <- curBatch
+				select {
+				case w.rescanBatch <- curBatch:
+				case <-quit:
+					break out
+				}
 			} else {
 				// Create next batch if it doesn't exist, or
 				// merge the job.
@@ -123,10 +129,15 @@ out:
 		case n := <-w.rescanNotifications:
 			switch n := n.(type) {
 			case *chain.RescanProgress:
-				w.rescanProgress <- &RescanProgressMsg{
+				msg := &RescanProgressMsg{
 					Addresses:    curBatch.addrs,
 					Notification: n,
 				}
+				select {
+				case w.rescanProgress <- msg:
+				case <-quit:
+					break out
+				}
 
 			case *chain.RescanFinished:
 				if curBatch == nil {
@@ -135,15 +146,24 @@ out:
 						"currently running")
 					continue
 				}
-				w.rescanFinished <- &RescanFinishedMsg{
+				msg := &RescanFinishedMsg{
 					Addresses:    curBatch.addrs,
 					Notification: n,
 				}
+				select {
+				case w.rescanFinished <- msg:
+				case <-quit:
+					break out
+				}
 
 				curBatch, nextBatch = nextBatch, nil
 
 				if curBatch != nil {
-					w.rescanBatch <- curBatch
+					select {
+					case w.rescanBatch <- curBatch:
+					case <-quit:
+						break out
+					}
 				}
 
 			default:
@@ -162,6 +182,13 @@ out:
 // rescanProgressHandler handles notifications for partially and fully completed
 // rescans by marking each rescanned address as partially or fully synced.
 func (w *Wallet) rescanProgressHandler() {
+	chainClient, err := w.requireChainClient()
+	if err != nil {
+		log.Errorf("rescanProgressHandler called without an RPC client")
+		w.wg.Done()
+		return
+	}
+
 	quit := w.quitChan()
 out:
 	for {
@@ -178,7 +205,8 @@ out:
 				Hash:   *n.Hash,
 				Height: n.Height,
 			}
-			if err := w.Manager.SetSyncedTo(&bs); err != nil {
+			err := w.setSyncedToScanned(chainClient, &bs)
+			if err != nil {
 				log.Errorf("Failed to update address manager "+
 					"sync state for hash %v (height %d): %v",
 					n.Hash, n.Height, err)
@@ -192,7 +220,8 @@ out:
 				"%s, height %d)", len(addrs), noun, n.Hash,
 				n.Height)
 			bs := waddrmgr.BlockStamp{Height: n.Height, Hash: *n.Hash}
-			if err := w.Manager.SetSyncedTo(&bs); err != nil {
+			err := w.setSyncedToScanned(chainClient, &bs)
+			if err != nil {
 				log.Errorf("Failed to update address manager "+
 					"sync state for hash %v (height %d): %v",
 					n.Hash, n.Height, err)
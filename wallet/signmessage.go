@@ -0,0 +1,161 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wallet/bip322"
+	"github.com/btcsuite/btcwallet/wallet/psbt"
+)
+
+var (
+	// ErrLegacyMessageAddress describes an error where a message is signed
+	// in the legacy format for an address which is not a P2PKH address.
+	ErrLegacyMessageAddress = errors.New("legacy message signatures " +
+		"require a P2PKH address")
+
+	// ErrMessageNotSigned describes an error where no key of the wallet
+	// could sign a BIP0322 message for an address.
+	ErrMessageNotSigned = errors.New("no keys of the wallet can sign " +
+		"messages for the address")
+)
+
+// legacyMessageHash returns the hash of a message signed in the legacy
+// "Bitcoin Signed Message" format.
+func legacyMessageHash(message string) []byte {
+	var buf bytes.Buffer
+	wire.WriteVarString(&buf, 0, "Bitcoin Signed Message:\n")
+	wire.WriteVarString(&buf, 0, message)
+	return chainhash.DoubleHashB(buf.Bytes())
+}
+
+// isLegacyMessageAddress returns whether messages may be signed for addr in
+// the legacy format.
+func isLegacyMessageAddress(addr btcutil.Address) bool {
+	switch addr.(type) {
+	case *btcutil.AddressPubKeyHash, *btcutil.AddressPubKey:
+		return true
+	}
+	return false
+}
+
+// SignMessage signs a message with the private key of a P2PKH address in the
+// legacy "Bitcoin Signed Message" format, returning the compact signature from
+// which the public key can be recovered.  Messages of other addresses must be
+// signed with SignMessageBIP322.  The wallet must be unlocked.
+func (w *Wallet) SignMessage(addr btcutil.Address, message string) ([]byte, error) {
+	if !isLegacyMessageAddress(addr) {
+		return nil, ErrLegacyMessageAddress
+	}
+	ainfo, err := w.Manager.Address(addr)
+	if err != nil {
+		return nil, err
+	}
+	pka, ok := ainfo.(waddrmgr.ManagedPubKeyAddress)
+	if !ok {
+		return nil, ErrLegacyMessageAddress
+	}
+	privKey, err := pka.PrivKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return btcec.SignCompact(btcec.S256(), privKey,
+		legacyMessageHash(message), pka.Compressed())
+}
+
+// SignMessageBIP322 signs a message for any address the wallet can spend from
+// using the generic signed message format of BIP0322, including multisig
+// addresses of which the wallet only holds some of the keys.  When packet is
+// nil a new signature is started.  Otherwise, packet must be the unfinished
+// signature of the message returned by another signer, and the wallet's
+// signatures are added to it.
+//
+// The encoded signature is returned when the message is fully signed.  If more
+// signatures are required, the PSBT of the message is returned instead so it
+// can be passed on to the remaining signers.  The wallet must be unlocked.
+func (w *Wallet) SignMessageBIP322(addr btcutil.Address, message []byte,
+	packet *psbt.Packet) (sig []byte, partial *psbt.Packet, err error) {
+
+	// Fail with the manager's error for addresses the wallet does not
+	// know about.
+	_, err = w.Manager.Address(addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	challenge, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	if packet == nil {
+		packet, err = bip322.NewPacket(message, challenge)
+	} else {
+		err = bip322.CheckPacket(packet, message, challenge)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	signed, err := w.SignPsbt(packet, txscript.SigHashAll)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(signed) == 0 {
+		return nil, nil, ErrMessageNotSigned
+	}
+
+	sig, err = bip322.Finalize(packet)
+	switch err {
+	case nil:
+		return sig, nil, nil
+	case psbt.ErrNotFinalizable:
+		return nil, packet, nil
+	default:
+		return nil, nil, err
+	}
+}
+
+// VerifyMessage returns whether sig is a valid signature of a message by the
+// owner of addr.  Signatures for P2PKH addresses may be in the legacy format,
+// and signatures for all addresses may be in either BIP0322 format.  An error
+// is returned if the signature can not be decoded.
+func VerifyMessage(addr btcutil.Address, message string, sig []byte) (bool, error) {
+	if isLegacyMessageAddress(addr) && len(sig) == 65 {
+		pk, wasCompressed, err := btcec.RecoverCompact(btcec.S256(), sig,
+			legacyMessageHash(message))
+		if err != nil {
+			return false, nil
+		}
+		var serializedPubKey []byte
+		if wasCompressed {
+			serializedPubKey = pk.SerializeCompressed()
+		} else {
+			serializedPubKey = pk.SerializeUncompressed()
+		}
+		// Verify that the signed-by address matches the given address
+		switch checkAddr := addr.(type) {
+		case *btcutil.AddressPubKeyHash:
+			return bytes.Equal(btcutil.Hash160(serializedPubKey),
+				checkAddr.Hash160()[:]), nil
+		case *btcutil.AddressPubKey:
+			return bytes.Equal(serializedPubKey,
+				checkAddr.ScriptAddress()), nil
+		}
+	}
+
+	challenge, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return false, err
+	}
+	return bip322.Verify(sig, []byte(message), challenge)
+}
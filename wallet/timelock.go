@@ -0,0 +1,170 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"sort"
+	"time"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wallet/txauthor"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+)
+
+// medianTimeBlocks is the number of blocks used to calculate the median time
+// past of a block.
+const medianTimeBlocks = 11
+
+// scriptTimeLock returns the locks of the branch of a time-locked P2SH address
+// which the wallet is able to sign.  The boolean is false if the address does
+// not redeem a time-locked script, no branch can be signed, or the script is
+// not available because the wallet is locked or watching-only.  Outputs of
+// such addresses are not treated as locked, and spending them fails when the
+// transaction is signed, as for any other script the wallet can not sign.
+func (w *Wallet) scriptTimeLock(addr btcutil.Address) (txauthor.TimeLock, bool) {
+	ma, err := w.Manager.Address(addr)
+	if err != nil {
+		return txauthor.TimeLock{}, false
+	}
+	msa, ok := ma.(waddrmgr.ManagedScriptAddress)
+	if !ok {
+		return txauthor.TimeLock{}, false
+	}
+	script, err := msa.Script()
+	if err != nil {
+		return txauthor.TimeLock{}, false
+	}
+	ls, ok := txauthor.ParseLockedScript(script)
+	if !ok {
+		return txauthor.TimeLock{}, false
+	}
+
+	canSign := func(addr btcutil.Address) bool {
+		ma, err := w.Manager.Address(addr)
+		if err != nil {
+			return false
+		}
+		_, ok := ma.(waddrmgr.ManagedPubKeyAddress)
+		return ok
+	}
+	b, ok := ls.SignableBranch(canSign, w.chainParams)
+	if !ok {
+		return txauthor.TimeLock{}, false
+	}
+	return ls.Branches[b].Lock, true
+}
+
+// creditTimeLock returns the locks of the branch of a time-locked script which
+// the wallet is able to sign to spend a credit, as described by scriptTimeLock.
+func (w *Wallet) creditTimeLock(credit *wtxmgr.Credit) (txauthor.TimeLock, bool) {
+	if !txscript.IsPayToScriptHash(credit.PkScript) {
+		return txauthor.TimeLock{}, false
+	}
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(credit.PkScript,
+		w.chainParams)
+	if err != nil || len(addrs) != 1 {
+		return txauthor.TimeLock{}, false
+	}
+	return w.scriptTimeLock(addrs[0])
+}
+
+// timeLockMatured returns whether an input spending output with the lock can
+// be included in the block after bs.  Time-based locks are compared with the
+// median time past of the relevant blocks, which is looked up with mtp.
+func timeLockMatured(lock txauthor.TimeLock, output *wtxmgr.Credit,
+	bs *waddrmgr.BlockStamp, mtp func(height int32) (time.Time, error)) (bool, error) {
+
+	if lock.LockTime != 0 {
+		if lock.LockTime < txscript.LockTimeThreshold {
+			// The lock time must be below the height of the next
+			// block.
+			if int64(lock.LockTime) > int64(bs.Height) {
+				return false, nil
+			}
+		} else {
+			// BIP0113 compares timestamp lock times with the median
+			// time past of the previous block.
+			t, err := mtp(bs.Height)
+			if err != nil {
+				return false, err
+			}
+			if int64(lock.LockTime) >= t.Unix() {
+				return false, nil
+			}
+		}
+	}
+
+	if lock.CSV {
+		// Relative locks start when the output is mined.
+		if output.Height == -1 {
+			return false, nil
+		}
+		value := int64(lock.Sequence & wire.SequenceLockTimeMask)
+		if lock.Sequence&wire.SequenceLockTimeIsSeconds == 0 {
+			if int64(bs.Height)+1-int64(output.Height) < value {
+				return false, nil
+			}
+		} else {
+			// BIP0068 measures time-based relative locks from the
+			// median time past of the block before the output's.
+			prevHeight := output.Height - 1
+			if prevHeight < 0 {
+				prevHeight = 0
+			}
+			start, err := mtp(prevHeight)
+			if err != nil {
+				return false, err
+			}
+			end, err := mtp(bs.Height)
+			if err != nil {
+				return false, err
+			}
+			elapsed := end.Unix() - start.Unix()
+			if elapsed < value<<wire.SequenceLockTimeGranularity {
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// medianTimePast returns a function calculating the median time past of main
+// chain blocks from the chain backend.  Results are cached, so the function is
+// meant to be used for the duration of a single operation.
+func (w *Wallet) medianTimePast() func(height int32) (time.Time, error) {
+	cache := make(map[int32]time.Time)
+	return func(height int32) (time.Time, error) {
+		if t, ok := cache[height]; ok {
+			return t, nil
+		}
+		chainClient, err := w.requireChainClient()
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		timestamps := make([]int64, 0, medianTimeBlocks)
+		for h := height; h >= 0 && height-h < medianTimeBlocks; h-- {
+			hash, err := chainClient.GetBlockHash(int64(h))
+			if err != nil {
+				return time.Time{}, err
+			}
+			header, err := chainClient.GetBlockHeader(hash)
+			if err != nil {
+				return time.Time{}, err
+			}
+			timestamps = append(timestamps, header.Timestamp.Unix())
+		}
+		sort.Slice(timestamps, func(i, j int) bool {
+			return timestamps[i] < timestamps[j]
+		})
+		t := time.Unix(timestamps[len(timestamps)/2], 0)
+		cache[height] = t
+		return t, nil
+	}
+}
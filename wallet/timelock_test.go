@@ -0,0 +1,118 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wallet/txauthor"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+)
+
+func TestTimeLockMatured(t *testing.T) {
+	// The median time past of each block is 600 seconds after that of
+	// its parent.  The tip is at height 100, with a median time past of
+	// mtpBase+60000.
+	const mtpBase = 1500000000
+	mtp := func(height int32) (time.Time, error) {
+		return time.Unix(mtpBase+int64(height)*600, 0), nil
+	}
+	tip := &waddrmgr.BlockStamp{Height: 100}
+	seconds := func(units uint32) uint32 {
+		return wire.SequenceLockTimeIsSeconds | units
+	}
+
+	tests := []struct {
+		name    string
+		lock    txauthor.TimeLock
+		height  int32 // Height of the spent output.
+		matured bool
+	}{
+		{"no lock", txauthor.TimeLock{}, 50, true},
+
+		// Height lock times must not exceed the tip height.
+		{"CLTV height reached", txauthor.TimeLock{LockTime: 100}, 50, true},
+		{"CLTV height not reached", txauthor.TimeLock{LockTime: 101}, 50,
+			false},
+
+		// Time lock times must be before the tip's median time past.
+		{"CLTV time reached", txauthor.TimeLock{LockTime: mtpBase + 59999},
+			50, true},
+		{"CLTV time not reached",
+			txauthor.TimeLock{LockTime: mtpBase + 60000}, 50, false},
+
+		// An output mined at height 91 has 10 confirmations in the next
+		// block.
+		{"CSV height reached", txauthor.TimeLock{Sequence: 10, CSV: true},
+			91, true},
+		{"CSV height not reached",
+			txauthor.TimeLock{Sequence: 11, CSV: true}, 91, false},
+		{"CSV height unmined", txauthor.TimeLock{Sequence: 0, CSV: true},
+			-1, false},
+
+		// 6000 seconds passed between the median time past of block 90,
+		// the parent of the output's block, and the tip.  Time-based
+		// relative locks are in units of 512 seconds.
+		{"CSV time reached",
+			txauthor.TimeLock{Sequence: seconds(11), CSV: true}, 91,
+			true},
+		{"CSV time not reached",
+			txauthor.TimeLock{Sequence: seconds(12), CSV: true}, 91,
+			false},
+		{"CSV time unmined",
+			txauthor.TimeLock{Sequence: seconds(0), CSV: true}, -1,
+			false},
+		{"CSV time genesis output",
+			txauthor.TimeLock{Sequence: seconds(117), CSV: true}, 0,
+			true},
+
+		// Both locks of a branch must be satisfied.
+		{"CLTV and CSV reached",
+			txauthor.TimeLock{LockTime: 100, Sequence: 10, CSV: true},
+			91, true},
+		{"CLTV reached and CSV not reached",
+			txauthor.TimeLock{LockTime: 100, Sequence: 11, CSV: true},
+			91, false},
+		{"CLTV not reached and CSV reached",
+			txauthor.TimeLock{LockTime: 101, Sequence: 10, CSV: true},
+			91, false},
+	}
+	for _, test := range tests {
+		output := new(wtxmgr.Credit)
+		output.Height = test.height
+		matured, err := timeLockMatured(test.lock, output, tip, mtp)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if matured != test.matured {
+			t.Errorf("%s: matured %v, expected %v", test.name,
+				matured, test.matured)
+		}
+	}
+
+	// Failures looking up the median time past of time-based locks are
+	// returned.
+	errMTP := errors.New("no median time past")
+	failingMTP := func(int32) (time.Time, error) {
+		return time.Time{}, errMTP
+	}
+	for _, lock := range []txauthor.TimeLock{
+		{LockTime: mtpBase},
+		{Sequence: seconds(1), CSV: true},
+	} {
+		output := new(wtxmgr.Credit)
+		output.Height = 91
+		_, err := timeLockMatured(lock, output, tip, failingMTP)
+		if err != errMTP {
+			t.Errorf("lock %+v: error %v, expected %v", lock, err,
+				errMTP)
+		}
+	}
+}
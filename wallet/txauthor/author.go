@@ -23,7 +23,18 @@ import (
 // can not be satisified, this can be signaled by returning a total amount less
 // than the target or by returning a more detailed error implementing
 // InputSourceError.
-type InputSource func(target btcutil.Amount) (total btcutil.Amount, inputs []*wire.TxIn, scripts [][]byte, err error)
+//
+// The value of each input is returned in inputValues and the previous output
+// script of each input in scripts.  Both slices must have the same length as
+// inputs.  Input values are required to sign inputs spending witness outputs.
+//
+// The redeem scripts of inputs spending P2SH outputs are returned in
+// redeemScripts, which is used to estimate the size of the signed inputs.
+// The slice may be nil, and any element may be nil when the redeem script is
+// not known, in which case the input is assumed to have the largest standard
+// signature script.
+type InputSource func(target btcutil.Amount) (total btcutil.Amount, inputs []*wire.TxIn,
+	inputValues []btcutil.Amount, scripts [][]byte, redeemScripts [][]byte, err error)
 
 // InputSourceError describes the failure to provide enough input value from
 // unspent transaction outputs to meet a target amount.  A typed error is used
@@ -38,6 +49,10 @@ type InputSourceError interface {
 // Default implementation of InputSourceError.
 type insufficientFundsError struct{}
 
+// ErrInsufficientFunds is the InputSourceError returned when the input value
+// of a transaction does not pay for its outputs and fee.
+var ErrInsufficientFunds InputSourceError = insufficientFundsError{}
+
 func (insufficientFundsError) InputSourceError() {}
 func (insufficientFundsError) Error() string {
 	return "insufficient funds available to construct transaction"
@@ -46,15 +61,149 @@ func (insufficientFundsError) Error() string {
 // AuthoredTx holds the state of a newly-created transaction and the change
 // output (if one was added).
 type AuthoredTx struct {
-	Tx          *wire.MsgTx
-	PrevScripts [][]byte
-	TotalInput  btcutil.Amount
-	ChangeIndex int // negative if no change
+	Tx              *wire.MsgTx
+	PrevScripts     [][]byte
+	PrevInputValues []btcutil.Amount
+	TotalInput      btcutil.Amount
+	ChangeIndex     int // negative if no change
 }
 
 // ChangeSource provides P2PKH change output scripts for transaction creation.
 type ChangeSource func() ([]byte, error)
 
+// pushSize returns the size of a canonical data push of n bytes.
+func pushSize(n int) int {
+	switch {
+	case n < txscript.OP_PUSHDATA1:
+		return 1 + n
+	case n <= 0xff:
+		return 2 + n
+	case n <= 0xffff:
+		return 3 + n
+	default:
+		return 5 + n
+	}
+}
+
+// redeemSigScriptSize returns the worst case size of the signatures and keys
+// in a signature script satisfying a P2SH redeem script, not including the
+// push of the redeem script itself.  Only P2PK, P2PKH, multisig and time-locked
+// redeem scripts are recognized.
+func redeemSigScriptSize(redeemScript []byte) (int, bool) {
+	switch txscript.GetScriptClass(redeemScript) {
+	case txscript.PubKeyTy:
+		return 1 + 73, true
+	case txscript.PubKeyHashTy:
+		return txsizes.RedeemP2PKHSigScriptSize, true
+	case txscript.MultiSigTy:
+		_, nRequired, err := txscript.CalcMultiSigStats(redeemScript)
+		if err != nil {
+			return 0, false
+		}
+		// OP_0 for the extra item popped by OP_CHECKMULTISIG, and
+		// a push of each signature.
+		return 1 + nRequired*(1+73), true
+	}
+
+	ls, ok := ParseLockedScript(redeemScript)
+	if !ok {
+		return 0, false
+	}
+	size := 0
+	for i := range ls.Branches {
+		branchSize, ok := redeemSigScriptSize(ls.Branches[i].Script)
+		if !ok {
+			return 0, false
+		}
+		if branchSize > size {
+			size = branchSize
+		}
+	}
+	if len(ls.Branches) > 1 {
+		// The branch is selected with OP_TRUE or OP_FALSE.
+		size++
+	}
+	return size, true
+}
+
+// estimateInputSizes returns the worst case sizes of numInputs inputs
+// redeeming outputs with the previous output scripts prevPkScripts and, for
+// P2SH outputs, the redeem scripts redeemScripts.  Inputs without a known
+// previous output script are assumed to redeem P2PKH outputs.  P2SH outputs
+// are only assumed to nest a P2WPKH output when the redeem script is known to
+// be a P2WPKH program, and the size of the largest standard signature script
+// is used when the redeem script is unknown or not recognized.
+func estimateInputSizes(numInputs int, prevPkScripts,
+	redeemScripts [][]byte) []txsizes.InputSize {
+
+	sizes := make([]txsizes.InputSize, numInputs)
+	for i := range sizes {
+		sizes[i] = txsizes.P2PKHInputSize
+		if i >= len(prevPkScripts) {
+			continue
+		}
+		pkScript := prevPkScripts[i]
+		switch {
+		case txscript.IsPayToWitnessPubKeyHash(pkScript):
+			sizes[i] = txsizes.P2WPKHInputSize
+		case txscript.IsPayToScriptHash(pkScript):
+			var redeemScript []byte
+			if i < len(redeemScripts) {
+				redeemScript = redeemScripts[i]
+			}
+			sizes[i] = estimateP2SHInputSize(redeemScript)
+		}
+	}
+	return sizes
+}
+
+// estimateP2SHInputSize returns the worst case size of an input redeeming a
+// P2SH output with the redeem script redeemScript, which may be nil if the
+// script is unknown.
+func estimateP2SHInputSize(redeemScript []byte) txsizes.InputSize {
+	if redeemScript == nil {
+		return txsizes.MaxP2SHInputSize
+	}
+	if txscript.IsPayToWitnessPubKeyHash(redeemScript) {
+		return txsizes.NestedP2WPKHInputSize
+	}
+	sigScriptSize, ok := redeemSigScriptSize(redeemScript)
+	if !ok {
+		return txsizes.MaxP2SHInputSize
+	}
+	sigScriptSize += pushSize(len(redeemScript))
+	return txsizes.InputSize{
+		Size: txsizes.SigScriptInputSize(sigScriptSize),
+	}
+}
+
+// EstimateVirtualSize returns a worst case virtual size estimate for a signed
+// transaction redeeming outputs with the previous output scripts prevPkScripts
+// and paying to outputs, with an optional P2PKH change output.  redeemScripts
+// holds the redeem scripts of P2SH outputs, and may be nil or contain nil
+// scripts when they are unknown.  The same assumptions about the redeemed
+// outputs are made as by NewUnsignedTransaction.
+func EstimateVirtualSize(prevPkScripts, redeemScripts [][]byte,
+	outputs []*wire.TxOut, addChangeOutput bool) int {
+
+	inputSizes := estimateInputSizes(len(prevPkScripts), prevPkScripts,
+		redeemScripts)
+	return txsizes.EstimateInputsVirtualSize(inputSizes, outputs,
+		addChangeOutput)
+}
+
+// EstimateFee returns the fee, at feeRatePerKb, required by a signed
+// transaction redeeming outputs with the previous output scripts prevPkScripts
+// and paying to outputs, with an optional P2PKH change output.  The fee is
+// calculated from the virtual size returned by EstimateVirtualSize.
+func EstimateFee(prevPkScripts, redeemScripts [][]byte, outputs []*wire.TxOut,
+	addChangeOutput bool, feeRatePerKb btcutil.Amount) btcutil.Amount {
+
+	size := EstimateVirtualSize(prevPkScripts, redeemScripts, outputs,
+		addChangeOutput)
+	return txrules.FeeForSerializeSize(feeRatePerKb, size)
+}
+
 // NewUnsignedTransaction creates an unsigned transaction paying to one or more
 // non-change outputs.  An appropriate transaction fee is included based on the
 // transaction size.
@@ -74,24 +223,36 @@ type ChangeSource func() ([]byte, error)
 // enough input value to pay for every output any any necessary fees, an
 // InputSourceError is returned.
 //
+// Fees are calculated from the virtual size of the transaction, so inputs
+// redeeming P2WPKH and P2SH-P2WPKH outputs are charged for their discounted
+// witness data.  Inputs redeeming P2SH outputs are sized from the redeem
+// scripts returned by the input source.
+//
 // BUGS: Fee estimation may be off when redeeming non-compressed P2PKH outputs.
 func NewUnsignedTransaction(outputs []*wire.TxOut, relayFeePerKb btcutil.Amount,
 	fetchInputs InputSource, fetchChange ChangeSource) (*AuthoredTx, error) {
 
 	targetAmount := h.SumOutputValues(outputs)
-	estimatedSize := txsizes.EstimateSerializeSize(1, outputs, true)
+	estimatedSize := txsizes.EstimateVirtualSize(1, 0, 0, outputs, true)
 	targetFee := txrules.FeeForSerializeSize(relayFeePerKb, estimatedSize)
 
 	for {
-		inputAmount, inputs, scripts, err := fetchInputs(targetAmount + targetFee)
+		inputAmount, inputs, inputValues, scripts, redeemScripts, err :=
+			fetchInputs(targetAmount + targetFee)
 		if err != nil {
 			return nil, err
 		}
 		if inputAmount < targetAmount+targetFee {
-			return nil, insufficientFundsError{}
+			return nil, ErrInsufficientFunds
 		}
 
-		maxSignedSize := txsizes.EstimateSerializeSize(len(inputs), outputs, true)
+		// We estimate the size of each signed input, which we'll use
+		// to estimate the vsize of the transaction.
+		inputSizes := estimateInputSizes(len(inputs), scripts,
+			redeemScripts)
+
+		maxSignedSize := txsizes.EstimateInputsVirtualSize(inputSizes,
+			outputs, true)
 		maxRequiredFee := txrules.FeeForSerializeSize(relayFeePerKb, maxSignedSize)
 		remainingAmount := inputAmount - targetAmount
 		if remainingAmount < maxRequiredFee {
@@ -124,10 +285,11 @@ func NewUnsignedTransaction(outputs []*wire.TxOut, relayFeePerKb btcutil.Amount,
 		}
 
 		return &AuthoredTx{
-			Tx:          unsignedTransaction,
-			PrevScripts: scripts,
-			TotalInput:  inputAmount,
-			ChangeIndex: changeIndex,
+			Tx:              unsignedTransaction,
+			PrevScripts:     scripts,
+			PrevInputValues: inputValues,
+			TotalInput:      inputAmount,
+			ChangeIndex:     changeIndex,
 		}, nil
 	}
 }
@@ -166,36 +328,175 @@ type SecretsSource interface {
 
 // AddAllInputScripts modifies a transaction by adding inputs scripts for each
 // input.  Previous output scripts being redeemed by each input are passed in
-// prevPkScripts and the slice length must match the number of inputs.  Private
-// keys and redeem scripts are looked up using a SecretsSource based on the
-// previous output script.
-func AddAllInputScripts(tx *wire.MsgTx, prevPkScripts [][]byte, secrets SecretsSource) error {
+// prevPkScripts and the slice length must match the number of inputs.  The
+// values of the previous outputs are passed in inputValues, which must also
+// match the number of inputs, and are used to create BIP0143 signatures for
+// inputs redeeming witness outputs.  Private keys and redeem scripts are looked
+// up using a SecretsSource based on the previous output script.  Inputs
+// redeeming time-locked scripts require the transaction to be prepared with
+// SetTimeLocks first.
+func AddAllInputScripts(tx *wire.MsgTx, prevPkScripts [][]byte,
+	inputValues []btcutil.Amount, secrets SecretsSource) error {
+
 	inputs := tx.TxIn
+	hashCache := txscript.NewTxSigHashes(tx)
 	chainParams := secrets.ChainParams()
 
 	if len(inputs) != len(prevPkScripts) {
 		return errors.New("tx.TxIn and prevPkScripts slices must " +
 			"have equal length")
 	}
+	if len(inputs) != len(inputValues) {
+		return errors.New("tx.TxIn and inputValues slices must " +
+			"have equal length")
+	}
 
 	for i := range inputs {
 		pkScript := prevPkScripts[i]
-		sigScript := inputs[i].SignatureScript
-		script, err := txscript.SignTxOutput(chainParams, tx, i,
-			pkScript, txscript.SigHashAll, secrets, secrets,
-			sigScript)
-		if err != nil {
-			return err
+
+		switch {
+		// If this is a p2wkh output, then we'll generate the witness
+		// committing to the input value using the BIP0143 sighash.
+		case txscript.IsPayToWitnessPubKeyHash(pkScript):
+			err := spendWitnessKeyHash(inputs[i], pkScript,
+				int64(inputValues[i]), chainParams, secrets,
+				tx, hashCache, i)
+			if err != nil {
+				return err
+			}
+
+		// If this is a p2sh output nesting a p2wkh output, the
+		// signature script pushes the witness program while the
+		// signature is placed in the witness.
+		case isNestedWitnessKeyHash(pkScript, chainParams, secrets):
+			err := spendNestedWitnessKeyHash(inputs[i], pkScript,
+				int64(inputValues[i]), chainParams, secrets,
+				tx, hashCache, i)
+			if err != nil {
+				return err
+			}
+
+		// If this is a p2sh output with a time-locked redeem script,
+		// the branch that can be signed is spent.  The transaction
+		// locks must have been set by SetTimeLocks.
+		case isTimeLocked(pkScript, secrets):
+			redeemScript, ls, _ := lockedRedeemScript(pkScript, secrets)
+			err := spendTimeLocked(inputs[i], redeemScript, ls,
+				secrets, tx, i)
+			if err != nil {
+				return err
+			}
+
+		default:
+			sigScript := inputs[i].SignatureScript
+			script, err := txscript.SignTxOutput(chainParams, tx, i,
+				pkScript, txscript.SigHashAll, secrets, secrets,
+				sigScript)
+			if err != nil {
+				return err
+			}
+			inputs[i].SignatureScript = script
 		}
-		inputs[i].SignatureScript = script
 	}
 
 	return nil
 }
 
+// spendWitnessKeyHash generates, and sets a valid witness for spending the
+// passed pkScript with the specified input amount.  The input amount *must*
+// correspond to the output value of the previous pkScript, or else verification
+// will fail since the new sighash digest algorithm defined in BIP0143 includes
+// the input value in the sighash.
+func spendWitnessKeyHash(txIn *wire.TxIn, pkScript []byte,
+	inputValue int64, chainParams *chaincfg.Params, secrets SecretsSource,
+	tx *wire.MsgTx, hashCache *txscript.TxSigHashes, idx int) error {
+
+	// First obtain the key pair associated with this p2wkh address.
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(pkScript,
+		chainParams)
+	if err != nil {
+		return err
+	}
+	privKey, compressed, err := secrets.GetKey(addrs[0])
+	if err != nil {
+		return err
+	}
+	if !compressed {
+		return errors.New("witness outputs may only be spent using " +
+			"compressed public keys")
+	}
+
+	// Generate a valid witness stack for the input.  The subscript is
+	// the p2wkh output script itself, which is expanded to the matching
+	// p2pkh script when calculating the sighash.
+	witnessScript, err := txscript.WitnessSignature(tx, hashCache, idx,
+		inputValue, pkScript, txscript.SigHashAll, privKey, true)
+	if err != nil {
+		return err
+	}
+
+	txIn.Witness = witnessScript
+	return nil
+}
+
+// isNestedWitnessKeyHash returns whether pkScript is a p2sh output script with
+// a redeem script, known by the secrets source, that is a p2wkh witness
+// program.
+func isNestedWitnessKeyHash(pkScript []byte, chainParams *chaincfg.Params,
+	secrets SecretsSource) bool {
+
+	if !txscript.IsPayToScriptHash(pkScript) {
+		return false
+	}
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(pkScript,
+		chainParams)
+	if err != nil || len(addrs) != 1 {
+		return false
+	}
+	redeemScript, err := secrets.GetScript(addrs[0])
+	if err != nil {
+		return false
+	}
+	return txscript.IsPayToWitnessPubKeyHash(redeemScript)
+}
+
+// spendNestedWitnessKeyHash generates, and sets a valid witness and signature
+// script for spending the passed p2sh pkScript which nests a p2wkh output with
+// the specified input amount.  As with spendWitnessKeyHash, the input amount
+// *must* correspond to the output value of the previous pkScript.
+func spendNestedWitnessKeyHash(txIn *wire.TxIn, pkScript []byte,
+	inputValue int64, chainParams *chaincfg.Params, secrets SecretsSource,
+	tx *wire.MsgTx, hashCache *txscript.TxSigHashes, idx int) error {
+
+	// Look up the witness program which is the redeem script of the p2sh
+	// output.
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(pkScript,
+		chainParams)
+	if err != nil {
+		return err
+	}
+	witnessProgram, err := secrets.GetScript(addrs[0])
+	if err != nil {
+		return err
+	}
+
+	// The signature script only pushes the witness program, which is
+	// then executed with the witness as for a regular p2wkh output.
+	sigScript, err := txscript.NewScriptBuilder().AddData(witnessProgram).
+		Script()
+	if err != nil {
+		return err
+	}
+	txIn.SignatureScript = sigScript
+
+	return spendWitnessKeyHash(txIn, witnessProgram, inputValue,
+		chainParams, secrets, tx, hashCache, idx)
+}
+
 // AddAllInputScripts modifies an authored transaction by adding inputs scripts
 // for each input of an authored transaction.  Private keys and redeem scripts
 // are looked up using a SecretsSource based on the previous output script.
 func (tx *AuthoredTx) AddAllInputScripts(secrets SecretsSource) error {
-	return AddAllInputScripts(tx.Tx, tx.PrevScripts, secrets)
+	return AddAllInputScripts(tx.Tx, tx.PrevScripts, tx.PrevInputValues,
+		secrets)
 }
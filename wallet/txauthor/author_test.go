@@ -7,6 +7,10 @@ package txauthor_test
 import (
 	"testing"
 
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcutil"
 	. "github.com/btcsuite/btcwallet/wallet/txauthor"
@@ -24,26 +28,87 @@ func p2pkhOutputs(amounts ...btcutil.Amount) []*wire.TxOut {
 	return v
 }
 
-func makeInputSource(unspents []*wire.TxOut) InputSource {
+func p2wpkhOutputs(amounts ...btcutil.Amount) []*wire.TxOut {
+	v := make([]*wire.TxOut, 0, len(amounts))
+	for _, a := range amounts {
+		outScript := make([]byte, txsizes.P2WPKHPkScriptSize)
+		outScript[0] = txscript.OP_0
+		outScript[1] = txscript.OP_DATA_20
+		v = append(v, wire.NewTxOut(int64(a), outScript))
+	}
+	return v
+}
+
+func p2shOutputs(amounts ...btcutil.Amount) []*wire.TxOut {
+	v := make([]*wire.TxOut, 0, len(amounts))
+	for _, a := range amounts {
+		outScript := make([]byte, 23)
+		outScript[0] = txscript.OP_HASH160
+		outScript[1] = txscript.OP_DATA_20
+		outScript[22] = txscript.OP_EQUAL
+		v = append(v, wire.NewTxOut(int64(a), outScript))
+	}
+	return v
+}
+
+// multisigScript returns an m-of-n multisig script of random compressed keys.
+func multisigScript(t *testing.T, m, n int) []byte {
+	keys := make([]*btcutil.AddressPubKey, n)
+	for i := range keys {
+		privKey, err := btcec.NewPrivateKey(btcec.S256())
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys[i], err = btcutil.NewAddressPubKey(
+			privKey.PubKey().SerializeCompressed(),
+			&chaincfg.MainNetParams)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	script, err := txscript.MultiSigScript(keys, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return script
+}
+
+func makeInputSource(unspents []*wire.TxOut, redeemScripts [][]byte) InputSource {
 	// Return outputs in order.
 	currentTotal := btcutil.Amount(0)
 	currentInputs := make([]*wire.TxIn, 0, len(unspents))
-	f := func(target btcutil.Amount) (btcutil.Amount, []*wire.TxIn, [][]byte, error) {
+	currentInputValues := make([]btcutil.Amount, 0, len(unspents))
+	currentScripts := make([][]byte, 0, len(unspents))
+	currentRedeemScripts := make([][]byte, 0, len(unspents))
+	f := func(target btcutil.Amount) (btcutil.Amount, []*wire.TxIn, []btcutil.Amount, [][]byte, [][]byte, error) {
 		for currentTotal < target && len(unspents) != 0 {
 			u := unspents[0]
 			unspents = unspents[1:]
-			nextInput := wire.NewTxIn(&wire.OutPoint{}, nil)
+			var redeemScript []byte
+			if len(redeemScripts) != 0 {
+				redeemScript = redeemScripts[0]
+				redeemScripts = redeemScripts[1:]
+			}
+			nextInput := wire.NewTxIn(&wire.OutPoint{}, nil, nil)
 			currentTotal += btcutil.Amount(u.Value)
 			currentInputs = append(currentInputs, nextInput)
+			currentInputValues = append(currentInputValues, btcutil.Amount(u.Value))
+			currentScripts = append(currentScripts, u.PkScript)
+			currentRedeemScripts = append(currentRedeemScripts, redeemScript)
 		}
-		return currentTotal, currentInputs, make([][]byte, len(currentInputs)), nil
+		return currentTotal, currentInputs, currentInputValues, currentScripts,
+			currentRedeemScripts, nil
 	}
 	return InputSource(f)
 }
 
 func TestNewUnsignedTransaction(t *testing.T) {
+	p2wpkhProgram := p2wpkhOutputs(1)[0].PkScript
+	multisig := multisigScript(t, 2, 3)
+
 	tests := []struct {
 		UnspentOutputs   []*wire.TxOut
+		RedeemScripts    [][]byte
 		Outputs          []*wire.TxOut
 		RelayFee         btcutil.Amount
 		ChangeAmount     btcutil.Amount
@@ -170,6 +235,67 @@ func TestNewUnsignedTransaction(t *testing.T) {
 			ChangeAmount:   0,
 			InputCount:     1,
 		},
+
+		// Test that the fee for P2WPKH inputs is calculated using the
+		// virtual size of the transaction.
+		13: {
+			UnspentOutputs: p2wpkhOutputs(1e8),
+			Outputs:        p2pkhOutputs(1e6),
+			RelayFee:       1e4,
+			ChangeAmount: 1e8 - 1e6 - txrules.FeeForSerializeSize(1e4,
+				txsizes.EstimateVirtualSize(0, 1, 0, p2pkhOutputs(1e6), true)),
+			InputCount: 1,
+		},
+		14: {
+			UnspentOutputs: append(p2pkhOutputs(1e8), p2wpkhOutputs(1e8)...),
+			Outputs:        p2pkhOutputs(1e8),
+			RelayFee:       1e3,
+			ChangeAmount: 1e8 - txrules.FeeForSerializeSize(1e3,
+				txsizes.EstimateVirtualSize(1, 1, 0, p2pkhOutputs(1e8), true)),
+			InputCount: 2,
+		},
+
+		// P2SH inputs are estimated as nested P2WPKH inputs when the
+		// redeem script is a P2WPKH program.
+		15: {
+			UnspentOutputs: p2shOutputs(1e8),
+			RedeemScripts:  [][]byte{p2wpkhProgram},
+			Outputs:        p2pkhOutputs(1e6),
+			RelayFee:       1e4,
+			ChangeAmount: 1e8 - 1e6 - txrules.FeeForSerializeSize(1e4,
+				txsizes.EstimateVirtualSize(0, 0, 1, p2pkhOutputs(1e6), true)),
+			InputCount: 1,
+		},
+
+		// P2SH inputs with an unknown redeem script are charged for
+		// the largest standard signature script.
+		16: {
+			UnspentOutputs: p2shOutputs(1e8),
+			Outputs:        p2pkhOutputs(1e6),
+			RelayFee:       1e4,
+			ChangeAmount: 1e8 - 1e6 - txrules.FeeForSerializeSize(1e4,
+				txsizes.EstimateInputsVirtualSize(
+					[]txsizes.InputSize{txsizes.MaxP2SHInputSize},
+					p2pkhOutputs(1e6), true)),
+			InputCount: 1,
+		},
+
+		// P2SH multisig inputs are sized from the redeem script: OP_0,
+		// two pushed signatures and the pushed 105 byte script.
+		17: {
+			UnspentOutputs: p2shOutputs(1e8),
+			RedeemScripts:  [][]byte{multisig},
+			Outputs:        p2pkhOutputs(1e6),
+			RelayFee:       1e4,
+			ChangeAmount: 1e8 - 1e6 - txrules.FeeForSerializeSize(1e4,
+				txsizes.EstimateInputsVirtualSize(
+					[]txsizes.InputSize{{
+						Size: txsizes.SigScriptInputSize(
+							1 + 2*(1+73) + 2 + 105),
+					}},
+					p2pkhOutputs(1e6), true)),
+			InputCount: 1,
+		},
 	}
 
 	changeSource := func() ([]byte, error) {
@@ -178,7 +304,7 @@ func TestNewUnsignedTransaction(t *testing.T) {
 	}
 
 	for i, test := range tests {
-		inputSource := makeInputSource(test.UnspentOutputs)
+		inputSource := makeInputSource(test.UnspentOutputs, test.RedeemScripts)
 		tx, err := NewUnsignedTransaction(test.Outputs, test.RelayFee, inputSource, changeSource)
 		switch e := err.(type) {
 		case nil:
@@ -217,3 +343,129 @@ func TestNewUnsignedTransaction(t *testing.T) {
 		}
 	}
 }
+
+// testSecrets is a SecretsSource holding a single key and the witness program
+// paying to it, which is used as the redeem script of a nested p2wkh output.
+type testSecrets struct {
+	privKey        *btcec.PrivateKey
+	witnessProgram []byte
+}
+
+func (s *testSecrets) GetKey(btcutil.Address) (*btcec.PrivateKey, bool, error) {
+	return s.privKey, true, nil
+}
+
+func (s *testSecrets) GetScript(btcutil.Address) ([]byte, error) {
+	return s.witnessProgram, nil
+}
+
+func (s *testSecrets) ChainParams() *chaincfg.Params {
+	return &chaincfg.MainNetParams
+}
+
+func TestAddAllInputScriptsWitness(t *testing.T) {
+	privKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKeyHash := btcutil.Hash160(privKey.PubKey().SerializeCompressed())
+	witnessAddr, err := btcutil.NewAddressWitnessPubKeyHash(pubKeyHash,
+		&chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	witnessProgram, err := txscript.PayToAddrScript(witnessAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nestedAddr, err := btcutil.NewAddressScriptHash(witnessProgram,
+		&chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nestedScript, err := txscript.PayToAddrScript(nestedAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prevScripts := [][]byte{witnessProgram, nestedScript}
+	inputValues := []btcutil.Amount{1e8, 2e8}
+	tx := wire.NewMsgTx(wire.TxVersion)
+	for i := range prevScripts {
+		prevOut := wire.NewOutPoint(&chainhash.Hash{}, uint32(i))
+		tx.AddTxIn(wire.NewTxIn(prevOut, nil, nil))
+	}
+	tx.AddTxOut(p2pkhOutputs(3e8 - 1e4)[0])
+
+	secrets := &testSecrets{privKey: privKey, witnessProgram: witnessProgram}
+	err = AddAllInputScripts(tx, prevScripts, inputValues, secrets)
+	if err != nil {
+		t.Fatalf("AddAllInputScripts: unexpected error: %v", err)
+	}
+
+	if len(tx.TxIn[0].SignatureScript) != 0 {
+		t.Errorf("p2wkh input has a non-empty signature script")
+	}
+	if len(tx.TxIn[1].SignatureScript) != txsizes.RedeemNestedP2WPKHScriptSize {
+		t.Errorf("nested p2wkh input has signature script length %d, "+
+			"want %d", len(tx.TxIn[1].SignatureScript),
+			txsizes.RedeemNestedP2WPKHScriptSize)
+	}
+
+	hashCache := txscript.NewTxSigHashes(tx)
+	for i := range tx.TxIn {
+		vm, err := txscript.NewEngine(prevScripts[i], tx, i,
+			txscript.StandardVerifyFlags, nil, hashCache,
+			int64(inputValues[i]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := vm.Execute(); err != nil {
+			t.Errorf("input %d failed to validate: %v", i, err)
+		}
+	}
+}
+
+func TestEstimateFee(t *testing.T) {
+	const feeRate = 1000
+	outputs := p2pkhOutputs(1e6)
+	p2shScript := p2shOutputs(1)[0].PkScript
+	p2wpkhScript := p2wpkhOutputs(1)[0].PkScript
+	tests := []struct {
+		prevScripts   [][]byte
+		redeemScripts [][]byte
+		change        bool
+		size          int
+	}{
+		0: {nil, nil, false, txsizes.EstimateVirtualSize(0, 0, 0, outputs, false)},
+		1: {[][]byte{nil}, nil, true, txsizes.EstimateVirtualSize(1, 0, 0, outputs, true)},
+		2: {
+			[][]byte{p2wpkhScript, p2shScript},
+			[][]byte{nil, p2wpkhScript},
+			false,
+			txsizes.EstimateVirtualSize(0, 1, 1, outputs, false),
+		},
+		3: {
+			[][]byte{p2pkhOutputs(1)[0].PkScript, p2wpkhScript},
+			nil,
+			true,
+			txsizes.EstimateVirtualSize(1, 1, 0, outputs, true),
+		},
+		4: {
+			[][]byte{p2shScript},
+			nil,
+			false,
+			txsizes.EstimateInputsVirtualSize(
+				[]txsizes.InputSize{txsizes.MaxP2SHInputSize},
+				outputs, false),
+		},
+	}
+	for i, test := range tests {
+		fee := EstimateFee(test.prevScripts, test.redeemScripts, outputs,
+			test.change, feeRate)
+		wantFee := txrules.FeeForSerializeSize(feeRate, test.size)
+		if fee != wantFee {
+			t.Errorf("Test %d: Got fee %v, expected %v", i, fee, wantFee)
+		}
+	}
+}
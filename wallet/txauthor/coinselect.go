@@ -0,0 +1,239 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txauthor
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/wallet/internal/txsizes"
+	"github.com/btcsuite/btcwallet/wallet/txrules"
+)
+
+// Coin describes a spendable transaction output which may be selected as a
+// transaction input.
+type Coin struct {
+	OutPoint     wire.OutPoint
+	Value        btcutil.Amount
+	PkScript     []byte
+	RedeemScript []byte // nil if not P2SH or unknown
+	Height       int32  // -1 if the output is unmined
+}
+
+// CoinSelector describes a strategy for selecting which coins are spent by a
+// transaction.
+type CoinSelector interface {
+	// SelectCoins returns the coins to spend to pay at least target.  If
+	// the total value of all coins is less than target, all coins are
+	// returned.  The passed slice must not be modified.
+	SelectCoins(target btcutil.Amount, coins []Coin) ([]Coin, error)
+}
+
+// MakeInputSource creates an InputSource which uses a CoinSelector to select
+// inputs from a set of coins.  Each call selects coins for the target again,
+// so the selected coins are not required to include earlier selections.  The
+// LargestFirst strategy is used if selector is nil.
+func MakeInputSource(coins []Coin, selector CoinSelector) InputSource {
+	if selector == nil {
+		selector = LargestFirst
+	}
+	return func(target btcutil.Amount) (btcutil.Amount, []*wire.TxIn,
+		[]btcutil.Amount, [][]byte, [][]byte, error) {
+
+		selected, err := selector.SelectCoins(target, coins)
+		if err != nil {
+			return 0, nil, nil, nil, nil, err
+		}
+		var total btcutil.Amount
+		inputs := make([]*wire.TxIn, 0, len(selected))
+		inputValues := make([]btcutil.Amount, 0, len(selected))
+		scripts := make([][]byte, 0, len(selected))
+		redeemScripts := make([][]byte, 0, len(selected))
+		for i := range selected {
+			c := &selected[i]
+			total += c.Value
+			inputs = append(inputs, wire.NewTxIn(&c.OutPoint, nil, nil))
+			inputValues = append(inputValues, c.Value)
+			scripts = append(scripts, c.PkScript)
+			redeemScripts = append(redeemScripts, c.RedeemScript)
+		}
+		return total, inputs, inputValues, scripts, redeemScripts, nil
+	}
+}
+
+// selectInOrder returns the shortest prefix of coins paying at least target,
+// or all coins if their total value is less than target.
+func selectInOrder(target btcutil.Amount, coins []Coin) []Coin {
+	var total btcutil.Amount
+	for i := range coins {
+		if total >= target {
+			return coins[:i]
+		}
+		total += coins[i].Value
+	}
+	return coins
+}
+
+// Built-in coin selection strategies.
+var (
+	// LargestFirst selects the coins with the highest values first.  This
+	// results in few inputs, but fragments large outputs into change.
+	LargestFirst CoinSelector = largestFirst{}
+
+	// OldestFirst selects the coins with the most confirmations first.
+	// Unmined coins are selected last.
+	OldestFirst CoinSelector = oldestFirst{}
+
+	// RandomImprove selects coins at random until the target is paid, and
+	// then continues to add random coins while doing so brings the total
+	// closer to twice the target, without exceeding three times the target.
+	// This creates change outputs similar in value to the payment, which
+	// limits the fragmentation of outputs and makes the change less
+	// distinguishable from the payment.
+	RandomImprove CoinSelector = &RandomImproveSelector{}
+)
+
+type largestFirst struct{}
+
+func (largestFirst) SelectCoins(target btcutil.Amount, coins []Coin) ([]Coin, error) {
+	sorted := append([]Coin(nil), coins...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Value > sorted[j].Value
+	})
+	return selectInOrder(target, sorted), nil
+}
+
+type oldestFirst struct{}
+
+func (oldestFirst) SelectCoins(target btcutil.Amount, coins []Coin) ([]Coin, error) {
+	sorted := append([]Coin(nil), coins...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		hi, hj := sorted[i].Height, sorted[j].Height
+		switch {
+		case hi == -1:
+			return false
+		case hj == -1:
+			return true
+		default:
+			return hi < hj
+		}
+	})
+	return selectInOrder(target, sorted), nil
+}
+
+// RandomImproveSelector implements the RandomImprove coin selection strategy.
+// Coins are shuffled with Rand, or a time-seeded source if Rand is nil.
+type RandomImproveSelector struct {
+	Rand *rand.Rand
+}
+
+// SelectCoins implements the CoinSelector interface.
+func (s *RandomImproveSelector) SelectCoins(target btcutil.Amount, coins []Coin) ([]Coin, error) {
+	r := s.Rand
+	if r == nil {
+		r = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	shuffled := append([]Coin(nil), coins...)
+	for i := len(shuffled) - 1; i > 0; i-- {
+		j := r.Intn(i + 1)
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	}
+
+	selected := selectInOrder(target, shuffled)
+	if len(selected) == len(shuffled) {
+		return selected, nil
+	}
+	var total btcutil.Amount
+	for i := range selected {
+		total += selected[i].Value
+	}
+
+	distance := func(amount btcutil.Amount) btcutil.Amount {
+		if amount > 2*target {
+			return amount - 2*target
+		}
+		return 2*target - amount
+	}
+	n := len(selected)
+	for _, c := range shuffled[n:] {
+		improved := total + c.Value
+		if improved > 3*target || distance(improved) >= distance(total) {
+			break
+		}
+		total = improved
+		n++
+	}
+	return shuffled[:n], nil
+}
+
+// maxBranchAndBoundTries limits the number of selections considered by the
+// branch and bound search before the fallback strategy is used.
+const maxBranchAndBoundTries = 100000
+
+// BranchAndBoundSelector searches for a set of coins paying the target so
+// exactly that no change output is required, because any excess value is
+// dust at RelayFeePerKb and is paid as fee.  The Fallback strategy, or
+// LargestFirst if Fallback is nil, is used when no such set is found.
+type BranchAndBoundSelector struct {
+	RelayFeePerKb btcutil.Amount
+	Fallback      CoinSelector
+}
+
+// SelectCoins implements the CoinSelector interface.
+func (s *BranchAndBoundSelector) SelectCoins(target btcutil.Amount, coins []Coin) ([]Coin, error) {
+	sorted := append([]Coin(nil), coins...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Value > sorted[j].Value
+	})
+
+	// remaining[i] is the total value of the coins from index i.
+	remaining := make([]btcutil.Amount, len(sorted)+1)
+	for i := len(sorted) - 1; i >= 0; i-- {
+		remaining[i] = remaining[i+1] + sorted[i].Value
+	}
+
+	// Depth first search, trying to include each coin before excluding
+	// it, so selections with fewer inputs are found first.
+	selected := make([]bool, len(sorted))
+	tries := 0
+	var search func(i int, total btcutil.Amount) bool
+	search = func(i int, total btcutil.Amount) bool {
+		tries++
+		if tries > maxBranchAndBoundTries {
+			return false
+		}
+		if total >= target {
+			return total == target || txrules.IsDustAmount(total-target,
+				txsizes.P2PKHPkScriptSize, s.RelayFeePerKb)
+		}
+		if i == len(sorted) || total+remaining[i] < target {
+			return false
+		}
+		selected[i] = true
+		if search(i+1, total+sorted[i].Value) {
+			return true
+		}
+		selected[i] = false
+		return search(i+1, total)
+	}
+	if search(0, 0) {
+		var coins []Coin
+		for i, ok := range selected {
+			if ok {
+				coins = append(coins, sorted[i])
+			}
+		}
+		return coins, nil
+	}
+
+	fallback := s.Fallback
+	if fallback == nil {
+		fallback = LargestFirst
+	}
+	return fallback.SelectCoins(target, coins)
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txauthor_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	. "github.com/btcsuite/btcwallet/wallet/txauthor"
+	"github.com/btcsuite/btcwallet/wallet/txrules"
+
+	"github.com/btcsuite/btcwallet/wallet/internal/txsizes"
+)
+
+// makeCoins creates coins with the passed values, mined at increasing heights
+// in reverse order, so the last coin is the oldest.
+func makeCoins(values ...btcutil.Amount) []Coin {
+	coins := make([]Coin, len(values))
+	for i, v := range values {
+		coins[i] = Coin{
+			OutPoint: wire.OutPoint{Index: uint32(i)},
+			Value:    v,
+			Height:   int32(len(values) - i),
+		}
+	}
+	return coins
+}
+
+func coinIndexes(coins []Coin) []uint32 {
+	indexes := make([]uint32, len(coins))
+	for i := range coins {
+		indexes[i] = coins[i].OutPoint.Index
+	}
+	return indexes
+}
+
+func sumCoins(coins []Coin) btcutil.Amount {
+	var total btcutil.Amount
+	for i := range coins {
+		total += coins[i].Value
+	}
+	return total
+}
+
+func equalIndexes(a, b []uint32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestCoinSelectors(t *testing.T) {
+	coins := makeCoins(1e6, 5e6, 2e6, 3e6)
+	coins = append(coins, Coin{OutPoint: wire.OutPoint{Index: 4}, Value: 4e6, Height: -1})
+	bnb := &BranchAndBoundSelector{RelayFeePerKb: txrules.DefaultRelayFeePerKb}
+
+	tests := []struct {
+		name     string
+		selector CoinSelector
+		target   btcutil.Amount
+		expected []uint32
+	}{
+		{"largest first", LargestFirst, 6e6, []uint32{1, 4}},
+		{"largest first insufficient", LargestFirst, 20e6, []uint32{1, 4, 3, 2, 0}},
+		{"oldest first", OldestFirst, 4e6, []uint32{3, 2}},
+		{"oldest first unmined last", OldestFirst, 12e6, []uint32{3, 2, 1, 0, 4}},
+		{"branch and bound exact", bnb, 8e6, []uint32{1, 3}},
+		{"branch and bound dust excess", bnb, 6e6 - 100, []uint32{1, 0}},
+		{"branch and bound fallback", bnb, 9.5e6, []uint32{1, 4, 3}},
+		{"no target", LargestFirst, 0, []uint32{}},
+	}
+	for _, test := range tests {
+		selected, err := test.selector.SelectCoins(test.target, coins)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if got := coinIndexes(selected); !equalIndexes(got, test.expected) {
+			t.Errorf("%s: selected coins %v, expected %v", test.name,
+				got, test.expected)
+		}
+	}
+
+	// The passed coins must not be reordered by the selectors.
+	if got := coinIndexes(coins); !equalIndexes(got, []uint32{0, 1, 2, 3, 4}) {
+		t.Errorf("selectors modified the passed coins: %v", got)
+	}
+}
+
+func TestRandomImprove(t *testing.T) {
+	coins := makeCoins(1e6, 1e6, 1e6, 1e6, 1e6, 1e6, 1e6, 1e6, 1e6, 1e6)
+	selector := &RandomImproveSelector{Rand: rand.New(rand.NewSource(1))}
+
+	// With equal coin values, the selection is improved until the total
+	// is as close as possible to twice the target, regardless of the order
+	// the coins are selected in.
+	tests := []struct {
+		target, total btcutil.Amount
+	}{
+		{1, 1e6},
+		{1e6, 2e6},
+		{2e6, 4e6},
+		{3e6 + 1, 6e6},
+	}
+	for _, test := range tests {
+		selected, err := selector.SelectCoins(test.target, coins)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total := sumCoins(selected); total != test.total {
+			t.Errorf("target %v: selected %v, expected %v", test.target,
+				total, test.total)
+		}
+	}
+
+	// Insufficient coins select everything.
+	selected, err := selector.SelectCoins(20e6, coins)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(selected) != len(coins) {
+		t.Errorf("selected %d coins for an unreachable target, expected %d",
+			len(selected), len(coins))
+	}
+}
+
+func TestMakeInputSourceSelector(t *testing.T) {
+	outputs := p2pkhOutputs(7e6)
+	coins := makeCoins(1e6, 5e6, 2e6, 3e6)
+	for i, out := range p2pkhOutputs(1e6, 5e6, 2e6, 3e6) {
+		coins[i].PkScript = out.PkScript
+	}
+
+	changeSource := func() ([]byte, error) {
+		return make([]byte, txsizes.P2PKHPkScriptSize), nil
+	}
+	tx, err := NewUnsignedTransaction(outputs, txrules.DefaultRelayFeePerKb,
+		MakeInputSource(coins, OldestFirst), changeSource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []uint32{3, 2, 1}
+	if len(tx.Tx.TxIn) != len(expected) {
+		t.Fatalf("transaction has %d inputs, expected %d",
+			len(tx.Tx.TxIn), len(expected))
+	}
+	for i, txIn := range tx.Tx.TxIn {
+		if txIn.PreviousOutPoint.Index != expected[i] {
+			t.Errorf("input %d spends coin %d, expected %d", i,
+				txIn.PreviousOutPoint.Index, expected[i])
+		}
+	}
+	if tx.TotalInput != 10e6 {
+		t.Errorf("total input %v, expected %v", tx.TotalInput, btcutil.Amount(10e6))
+	}
+}
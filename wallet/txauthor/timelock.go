@@ -0,0 +1,428 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txauthor
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+)
+
+// TimeLock describes the absolute and relative locks which must be satisfied
+// to spend a branch of a time-locked script.
+type TimeLock struct {
+	// LockTime is the lock time required by OP_CHECKLOCKTIMEVERIFY, or
+	// zero if the branch has no absolute lock.  Values below
+	// txscript.LockTimeThreshold are block heights, and all others are
+	// unix timestamps.
+	LockTime uint32
+
+	// Sequence is the input sequence number required by
+	// OP_CHECKSEQUENCEVERIFY.  It is only meaningful when CSV is set.
+	Sequence uint32
+	CSV      bool
+}
+
+// LockedBranch is a spending path of a time-locked script.  Script is the
+// P2PK, P2PKH or multisig script that must be signed once the branch's locks
+// have been satisfied.
+type LockedBranch struct {
+	Lock   TimeLock
+	Script []byte
+}
+
+// LockedScript is a redeem script with one or two spending paths, at least
+// one of which is time-locked.  Each branch is a standard P2PK, P2PKH or
+// multisig script prefixed by any number of
+//
+//	<locktime> OP_CHECKLOCKTIMEVERIFY OP_DROP
+//	<sequence> OP_CHECKSEQUENCEVERIFY OP_DROP
+//
+// locks.  Scripts with two branches, such as vaults which may be spent by a
+// recovery key at any time or by a hot key after a delay, take the form
+//
+//	OP_IF <branch> OP_ELSE <branch> OP_ENDIF
+//
+// and select the first branch with a true value.
+type LockedScript struct {
+	Branches []LockedBranch
+}
+
+// errUnlockedBranch is returned when no branch of a locked script can be
+// signed with the keys of a secrets source.
+var errUnlockedBranch = errors.New("no branch of the time-locked script " +
+	"can be signed")
+
+// ParseLockedScript parses a redeem script as a time-locked script.  The
+// boolean is false if the script is not of a form described by LockedScript
+// or none of its branches is locked.
+func ParseLockedScript(script []byte) (*LockedScript, bool) {
+	ops, ok := parseScript(script)
+	if !ok || len(ops) == 0 {
+		return nil, false
+	}
+
+	var branches []LockedBranch
+	if ops[0].opcode == txscript.OP_IF {
+		elseIdx, endIdx := -1, -1
+		for i := 1; i < len(ops); i++ {
+			switch ops[i].opcode {
+			case txscript.OP_ELSE:
+				if elseIdx != -1 {
+					return nil, false
+				}
+				elseIdx = i
+			case txscript.OP_ENDIF:
+				if endIdx != -1 {
+					return nil, false
+				}
+				endIdx = i
+			}
+		}
+		if elseIdx == -1 || endIdx != len(ops)-1 || elseIdx > endIdx {
+			return nil, false
+		}
+		first, ok := parseBranch(script, ops[1:elseIdx])
+		if !ok {
+			return nil, false
+		}
+		second, ok := parseBranch(script, ops[elseIdx+1:endIdx])
+		if !ok {
+			return nil, false
+		}
+		branches = []LockedBranch{first, second}
+	} else {
+		branch, ok := parseBranch(script, ops)
+		if !ok {
+			return nil, false
+		}
+		branches = []LockedBranch{branch}
+	}
+
+	for _, b := range branches {
+		if b.Lock.LockTime != 0 || b.Lock.CSV {
+			return &LockedScript{Branches: branches}, true
+		}
+	}
+	return nil, false
+}
+
+// parseBranch parses the lock prefixes and the signed script of a branch.
+func parseBranch(script []byte, ops []parsedOp) (LockedBranch, bool) {
+	var b LockedBranch
+	for len(ops) >= 3 && ops[2].opcode == txscript.OP_DROP {
+		n, ok := ops[0].number()
+		if !ok || n < 0 || n > 0xffffffff {
+			return b, false
+		}
+		switch ops[1].opcode {
+		case txscript.OP_CHECKLOCKTIMEVERIFY:
+			if b.Lock.LockTime != 0 {
+				return b, false
+			}
+			b.Lock.LockTime = uint32(n)
+		case txscript.OP_CHECKSEQUENCEVERIFY:
+			// A sequence with the disable flag set makes
+			// OP_CHECKSEQUENCEVERIFY behave as a NOP.
+			if b.Lock.CSV || uint32(n)&wire.SequenceLockTimeDisabled != 0 {
+				return b, false
+			}
+			b.Lock.Sequence = uint32(n)
+			b.Lock.CSV = true
+		default:
+			return b, false
+		}
+		ops = ops[3:]
+	}
+	if len(ops) == 0 {
+		return b, false
+	}
+	b.Script = script[ops[0].offset : ops[len(ops)-1].offset+ops[len(ops)-1].size]
+	switch txscript.GetScriptClass(b.Script) {
+	case txscript.PubKeyTy, txscript.PubKeyHashTy, txscript.MultiSigTy:
+		return b, true
+	}
+	return b, false
+}
+
+// parsedOp is an opcode of a script along with its pushed data.
+type parsedOp struct {
+	opcode byte
+	data   []byte
+	offset int
+	size   int
+}
+
+// number returns the script number pushed by the opcode.  Only minimally
+// encoded numbers of up to five bytes are accepted, as required by the lock
+// time opcodes.
+func (op *parsedOp) number() (int64, bool) {
+	switch {
+	case op.opcode == txscript.OP_0:
+		return 0, true
+	case op.opcode >= txscript.OP_1 && op.opcode <= txscript.OP_16:
+		return int64(op.opcode-txscript.OP_1) + 1, true
+	case op.opcode < txscript.OP_DATA_1 || op.opcode > txscript.OP_DATA_5:
+		return 0, false
+	}
+	b := op.data
+	if b[len(b)-1]&0x7f == 0 && (len(b) == 1 || b[len(b)-2]&0x80 == 0) {
+		return 0, false
+	}
+	var n int64
+	for i, v := range b {
+		n |= int64(v) << uint(8*i)
+	}
+	if b[len(b)-1]&0x80 != 0 {
+		n &^= int64(0x80) << uint(8*(len(b)-1))
+		n = -n
+	}
+	return n, true
+}
+
+// parseScript splits a script into its opcodes.  The boolean is false if a
+// push runs past the end of the script.
+func parseScript(script []byte) ([]parsedOp, bool) {
+	var ops []parsedOp
+	for i := 0; i < len(script); {
+		op := parsedOp{opcode: script[i], offset: i}
+		var dataLen, prefix int
+		switch {
+		case op.opcode >= txscript.OP_DATA_1 && op.opcode <= txscript.OP_DATA_75:
+			dataLen, prefix = int(op.opcode), 1
+		case op.opcode == txscript.OP_PUSHDATA1:
+			if i+2 > len(script) {
+				return nil, false
+			}
+			dataLen, prefix = int(script[i+1]), 2
+		case op.opcode == txscript.OP_PUSHDATA2:
+			if i+3 > len(script) {
+				return nil, false
+			}
+			dataLen = int(binary.LittleEndian.Uint16(script[i+1:]))
+			prefix = 3
+		case op.opcode == txscript.OP_PUSHDATA4:
+			if i+5 > len(script) {
+				return nil, false
+			}
+			dataLen = int(binary.LittleEndian.Uint32(script[i+1:]))
+			prefix = 5
+		default:
+			prefix = 1
+		}
+		if dataLen < 0 || i+prefix+dataLen > len(script) {
+			return nil, false
+		}
+		op.data = script[i+prefix : i+prefix+dataLen]
+		op.size = prefix + dataLen
+		ops = append(ops, op)
+		i += op.size
+	}
+	return ops, true
+}
+
+// branchKeys returns the addresses of the keys which may sign a branch and
+// the number of signatures required.
+func branchKeys(b *LockedBranch, chainParams *chaincfg.Params) ([]btcutil.Address, int, error) {
+	_, addrs, reqSigs, err := txscript.ExtractPkScriptAddrs(b.Script,
+		chainParams)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i, addr := range addrs {
+		if pk, ok := addr.(*btcutil.AddressPubKey); ok {
+			addrs[i] = pk.AddressPubKeyHash()
+		}
+	}
+	return addrs, reqSigs, nil
+}
+
+// SignableBranch returns the index of the first branch of the script with
+// enough keys for which canSign returns true to be spent.  The boolean is
+// false if no branch can be signed.
+func (s *LockedScript) SignableBranch(canSign func(btcutil.Address) bool,
+	chainParams *chaincfg.Params) (int, bool) {
+
+	for i := range s.Branches {
+		addrs, reqSigs, err := branchKeys(&s.Branches[i], chainParams)
+		if err != nil {
+			continue
+		}
+		n := 0
+		for _, addr := range addrs {
+			if canSign(addr) {
+				n++
+			}
+		}
+		if n >= reqSigs {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// lockedRedeemScript returns the redeem script of a P2SH output script if it
+// is a time-locked script known by the secrets source.
+func lockedRedeemScript(pkScript []byte, secrets SecretsSource) ([]byte, *LockedScript, bool) {
+	if !txscript.IsPayToScriptHash(pkScript) {
+		return nil, nil, false
+	}
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(pkScript,
+		secrets.ChainParams())
+	if err != nil || len(addrs) != 1 {
+		return nil, nil, false
+	}
+	redeemScript, err := secrets.GetScript(addrs[0])
+	if err != nil {
+		return nil, nil, false
+	}
+	ls, ok := ParseLockedScript(redeemScript)
+	return redeemScript, ls, ok
+}
+
+// isTimeLocked returns whether pkScript is a p2sh output script with a
+// time-locked redeem script known by the secrets source.
+func isTimeLocked(pkScript []byte, secrets SecretsSource) bool {
+	_, _, ok := lockedRedeemScript(pkScript, secrets)
+	return ok
+}
+
+// signableBranch returns the branch of a locked script spent with the keys
+// of a secrets source.
+func signableBranch(ls *LockedScript, secrets SecretsSource) (int, error) {
+	canSign := func(addr btcutil.Address) bool {
+		_, _, err := secrets.GetKey(addr)
+		return err == nil
+	}
+	i, ok := ls.SignableBranch(canSign, secrets.ChainParams())
+	if !ok {
+		return 0, errUnlockedBranch
+	}
+	return i, nil
+}
+
+// SetTimeLocks prepares a transaction spending time-locked P2SH outputs for
+// signing.  For every input redeeming a script described by LockedScript, the
+// branch that can be signed by the secrets source is chosen and the
+// transaction lock time, input sequence number and transaction version are set
+// as required by the branch's locks.  Inputs are given non-final sequence
+// numbers when the lock time is set.  This must be called before the input
+// scripts are added.
+//
+// An error is returned if the inputs require both block height and timestamp
+// lock times, which can not be satisfied by a single transaction.
+func SetTimeLocks(tx *wire.MsgTx, prevPkScripts [][]byte, secrets SecretsSource) error {
+	if len(tx.TxIn) != len(prevPkScripts) {
+		return errors.New("tx.TxIn and prevPkScripts slices must " +
+			"have equal length")
+	}
+
+	for i, pkScript := range prevPkScripts {
+		_, ls, ok := lockedRedeemScript(pkScript, secrets)
+		if !ok {
+			continue
+		}
+		b, err := signableBranch(ls, secrets)
+		if err != nil {
+			return err
+		}
+		lock := ls.Branches[b].Lock
+
+		if lock.CSV {
+			if tx.Version < 2 {
+				tx.Version = 2
+			}
+			tx.TxIn[i].Sequence = lock.Sequence
+		}
+		if lock.LockTime != 0 {
+			if tx.LockTime != 0 && (tx.LockTime < txscript.LockTimeThreshold) !=
+				(lock.LockTime < txscript.LockTimeThreshold) {
+				return errors.New("inputs require both block height " +
+					"and timestamp lock times")
+			}
+			if lock.LockTime > tx.LockTime {
+				tx.LockTime = lock.LockTime
+			}
+		}
+	}
+
+	if tx.LockTime != 0 {
+		for _, txIn := range tx.TxIn {
+			if txIn.Sequence == wire.MaxTxInSequenceNum {
+				txIn.Sequence = wire.MaxTxInSequenceNum - 1
+			}
+		}
+	}
+	return nil
+}
+
+// spendTimeLocked generates and sets the signature script for spending a
+// time-locked P2SH output.  The transaction locks must already have been set
+// by SetTimeLocks.
+func spendTimeLocked(txIn *wire.TxIn, redeemScript []byte, ls *LockedScript,
+	secrets SecretsSource, tx *wire.MsgTx, idx int) error {
+
+	b, err := signableBranch(ls, secrets)
+	if err != nil {
+		return err
+	}
+	branch := &ls.Branches[b]
+	addrs, reqSigs, err := branchKeys(branch, secrets.ChainParams())
+	if err != nil {
+		return err
+	}
+
+	builder := txscript.NewScriptBuilder()
+	switch txscript.GetScriptClass(branch.Script) {
+	case txscript.MultiSigTy:
+		// Account for the extra item popped by OP_CHECKMULTISIG.
+		builder.AddOp(txscript.OP_FALSE)
+	}
+	signed := 0
+	for _, addr := range addrs {
+		if signed == reqSigs {
+			break
+		}
+		privKey, compressed, err := secrets.GetKey(addr)
+		if err != nil {
+			continue
+		}
+		sig, err := txscript.RawTxInSignature(tx, idx, redeemScript,
+			txscript.SigHashAll, privKey)
+		if err != nil {
+			return err
+		}
+		builder.AddData(sig)
+		if txscript.GetScriptClass(branch.Script) == txscript.PubKeyHashTy {
+			pubKey := privKey.PubKey().SerializeUncompressed()
+			if compressed {
+				pubKey = privKey.PubKey().SerializeCompressed()
+			}
+			builder.AddData(pubKey)
+		}
+		signed++
+	}
+
+	// Select the branch of a script with two spending paths.
+	if len(ls.Branches) == 2 {
+		if b == 0 {
+			builder.AddOp(txscript.OP_TRUE)
+		} else {
+			builder.AddOp(txscript.OP_FALSE)
+		}
+	}
+	builder.AddData(redeemScript)
+
+	sigScript, err := builder.Script()
+	if err != nil {
+		return err
+	}
+	txIn.SignatureScript = sigScript
+	return nil
+}
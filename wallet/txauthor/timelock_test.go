@@ -0,0 +1,245 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txauthor_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	. "github.com/btcsuite/btcwallet/wallet/txauthor"
+)
+
+// lockSecrets is a SecretsSource holding a set of keys and a single redeem
+// script.
+type lockSecrets struct {
+	keys         map[string]*btcec.PrivateKey
+	redeemScript []byte
+}
+
+func (s *lockSecrets) GetKey(addr btcutil.Address) (*btcec.PrivateKey, bool, error) {
+	privKey, ok := s.keys[addr.EncodeAddress()]
+	if !ok {
+		return nil, false, errors.New("unknown key")
+	}
+	return privKey, true, nil
+}
+
+func (s *lockSecrets) GetScript(btcutil.Address) ([]byte, error) {
+	return s.redeemScript, nil
+}
+
+func (s *lockSecrets) ChainParams() *chaincfg.Params {
+	return &chaincfg.MainNetParams
+}
+
+func newLockKey(t *testing.T) (*btcec.PrivateKey, []byte, string) {
+	privKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey := privKey.PubKey().SerializeCompressed()
+	addr, err := btcutil.NewAddressPubKeyHash(btcutil.Hash160(pubKey),
+		&chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return privKey, pubKey, addr.EncodeAddress()
+}
+
+func TestParseLockedScript(t *testing.T) {
+	_, pubKey, _ := newLockKey(t)
+	mustScript := func(b *txscript.ScriptBuilder) []byte {
+		script, err := b.Script()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return script
+	}
+
+	tests := []struct {
+		name     string
+		script   []byte
+		ok       bool
+		branches []TimeLock
+	}{
+		{
+			name: "cltv p2pk",
+			script: mustScript(txscript.NewScriptBuilder().
+				AddInt64(500000).AddOp(txscript.OP_CHECKLOCKTIMEVERIFY).
+				AddOp(txscript.OP_DROP).AddData(pubKey).
+				AddOp(txscript.OP_CHECKSIG)),
+			ok:       true,
+			branches: []TimeLock{{LockTime: 500000}},
+		},
+		{
+			name: "cltv and csv p2pk",
+			script: mustScript(txscript.NewScriptBuilder().
+				AddInt64(1500000000).AddOp(txscript.OP_CHECKLOCKTIMEVERIFY).
+				AddOp(txscript.OP_DROP).
+				AddInt64(144).AddOp(txscript.OP_CHECKSEQUENCEVERIFY).
+				AddOp(txscript.OP_DROP).AddData(pubKey).
+				AddOp(txscript.OP_CHECKSIG)),
+			ok:       true,
+			branches: []TimeLock{{LockTime: 1500000000, Sequence: 144, CSV: true}},
+		},
+		{
+			name: "vault",
+			script: mustScript(txscript.NewScriptBuilder().
+				AddOp(txscript.OP_IF).AddData(pubKey).
+				AddOp(txscript.OP_CHECKSIG).AddOp(txscript.OP_ELSE).
+				AddInt64(16).AddOp(txscript.OP_CHECKSEQUENCEVERIFY).
+				AddOp(txscript.OP_DROP).AddData(pubKey).
+				AddOp(txscript.OP_CHECKSIG).AddOp(txscript.OP_ENDIF)),
+			ok:       true,
+			branches: []TimeLock{{}, {Sequence: 16, CSV: true}},
+		},
+		{
+			name: "unlocked p2pk",
+			script: mustScript(txscript.NewScriptBuilder().AddData(pubKey).
+				AddOp(txscript.OP_CHECKSIG)),
+		},
+		{
+			name: "disabled csv",
+			script: mustScript(txscript.NewScriptBuilder().
+				AddInt64(1 << 31).AddOp(txscript.OP_CHECKSEQUENCEVERIFY).
+				AddOp(txscript.OP_DROP).AddData(pubKey).
+				AddOp(txscript.OP_CHECKSIG)),
+		},
+		{
+			name: "nonstandard branch",
+			script: mustScript(txscript.NewScriptBuilder().
+				AddInt64(10).AddOp(txscript.OP_CHECKLOCKTIMEVERIFY).
+				AddOp(txscript.OP_DROP).AddOp(txscript.OP_TRUE)),
+		},
+		{
+			name:   "truncated push",
+			script: []byte{txscript.OP_DATA_5, 0x01},
+		},
+	}
+
+	for _, test := range tests {
+		ls, ok := ParseLockedScript(test.script)
+		if ok != test.ok {
+			t.Errorf("%s: parsed %v, want %v", test.name, ok, test.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if len(ls.Branches) != len(test.branches) {
+			t.Errorf("%s: %d branches, want %d", test.name,
+				len(ls.Branches), len(test.branches))
+			continue
+		}
+		for i, b := range ls.Branches {
+			if b.Lock != test.branches[i] {
+				t.Errorf("%s: branch %d lock %+v, want %+v", test.name,
+					i, b.Lock, test.branches[i])
+			}
+		}
+	}
+}
+
+func TestSpendTimeLocked(t *testing.T) {
+	recoveryKey, recoveryPubKey, recoveryAddr := newLockKey(t)
+	hotKey, hotPubKey, hotAddr := newLockKey(t)
+
+	// A vault spendable by the recovery key at any time, or by the hot
+	// key at least 10 blocks after being mined and after block 600000.
+	vault, err := txscript.NewScriptBuilder().
+		AddOp(txscript.OP_IF).AddData(recoveryPubKey).
+		AddOp(txscript.OP_CHECKSIG).AddOp(txscript.OP_ELSE).
+		AddInt64(600000).AddOp(txscript.OP_CHECKLOCKTIMEVERIFY).
+		AddOp(txscript.OP_DROP).
+		AddInt64(10).AddOp(txscript.OP_CHECKSEQUENCEVERIFY).
+		AddOp(txscript.OP_DROP).
+		AddOp(txscript.OP_DUP).AddOp(txscript.OP_HASH160).
+		AddData(btcutil.Hash160(hotPubKey)).AddOp(txscript.OP_EQUALVERIFY).
+		AddOp(txscript.OP_CHECKSIG).AddOp(txscript.OP_ENDIF).Script()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vaultAddr, err := btcutil.NewAddressScriptHash(vault, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkScript, err := txscript.PayToAddrScript(vaultAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		keys     map[string]*btcec.PrivateKey
+		version  int32
+		lockTime uint32
+		sequence uint32
+	}{
+		{
+			name:     "recovery branch",
+			keys:     map[string]*btcec.PrivateKey{recoveryAddr: recoveryKey},
+			version:  wire.TxVersion,
+			lockTime: 0,
+			sequence: wire.MaxTxInSequenceNum,
+		},
+		{
+			name:     "hot branch",
+			keys:     map[string]*btcec.PrivateKey{hotAddr: hotKey},
+			version:  2,
+			lockTime: 600000,
+			sequence: 10,
+		},
+	}
+
+	for _, test := range tests {
+		tx := wire.NewMsgTx(wire.TxVersion)
+		tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{}, 0), nil, nil))
+		tx.AddTxOut(p2pkhOutputs(1e8 - 1e4)[0])
+		prevScripts := [][]byte{pkScript}
+		inputValues := []btcutil.Amount{1e8}
+		secrets := &lockSecrets{keys: test.keys, redeemScript: vault}
+
+		err := SetTimeLocks(tx, prevScripts, secrets)
+		if err != nil {
+			t.Errorf("%s: SetTimeLocks: %v", test.name, err)
+			continue
+		}
+		if tx.Version != test.version || tx.LockTime != test.lockTime ||
+			tx.TxIn[0].Sequence != test.sequence {
+			t.Errorf("%s: version %d lock time %d sequence %d, want "+
+				"%d %d %d", test.name, tx.Version, tx.LockTime,
+				tx.TxIn[0].Sequence, test.version, test.lockTime,
+				test.sequence)
+		}
+
+		err = AddAllInputScripts(tx, prevScripts, inputValues, secrets)
+		if err != nil {
+			t.Errorf("%s: AddAllInputScripts: %v", test.name, err)
+			continue
+		}
+		vm, err := txscript.NewEngine(pkScript, tx, 0,
+			txscript.StandardVerifyFlags, nil, nil, 1e8)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := vm.Execute(); err != nil {
+			t.Errorf("%s: input failed to validate: %v", test.name, err)
+		}
+	}
+
+	// Without any key of the vault, no branch can be signed.
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{}, 0), nil, nil))
+	secrets := &lockSecrets{redeemScript: vault}
+	if err := SetTimeLocks(tx, [][]byte{pkScript}, secrets); err == nil {
+		t.Errorf("SetTimeLocks succeeded without keys")
+	}
+}
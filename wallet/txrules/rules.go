@@ -90,3 +90,27 @@ func FeeForSerializeSize(relayFeePerKb btcutil.Amount, txSerializeSize int) btcu
 
 	return fee
 }
+
+// MaxRBFSequence is the highest input sequence number which signals that a
+// transaction may be replaced by another paying a higher fee, as described by
+// BIP0125.
+const MaxRBFSequence = wire.MaxTxInSequenceNum - 2
+
+// SignalsReplacement returns whether a transaction signals that it may be
+// replaced as described by BIP0125.  Replacement is signaled when any input
+// has a sequence number no higher than MaxRBFSequence.
+func SignalsReplacement(tx *wire.MsgTx) bool {
+	for _, txIn := range tx.TxIn {
+		if txIn.Sequence <= MaxRBFSequence {
+			return true
+		}
+	}
+	return false
+}
+
+// VirtualSize returns the virtual size of a transaction, which is its weight
+// as defined by BIP0141 divided by four and rounded up.
+func VirtualSize(tx *wire.MsgTx) int {
+	weight := tx.SerializeSizeStripped()*3 + tx.SerializeSize()
+	return (weight + 3) / 4
+}
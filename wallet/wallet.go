@@ -24,7 +24,6 @@ import (
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
-	btcrpcclient "github.com/btcsuite/btcd/rpcclient"
 	"github.com/btcsuite/btcutil"
 	"github.com/btcsuite/btcutil/hdkeychain"
 	"github.com/btcsuite/btcwallet/chain"
@@ -32,6 +31,7 @@ import (
 	"github.com/btcsuite/btcwallet/wallet/txauthor"
 	"github.com/btcsuite/btcwallet/wallet/txrules"
 	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/btcsuite/btcwallet/walletdb/encrypted"
 	"github.com/btcsuite/btcwallet/wtxmgr"
 )
 
@@ -64,21 +64,30 @@ var (
 // complete wallet.  It contains the Armory-style key store
 // addresses and keys),
 type Wallet struct {
-	publicPassphrase []byte
+	publicPassphrase   []byte
+	publicPassphraseMu sync.Mutex
 
 	// Data stores
 	db      walletdb.DB
 	Manager *waddrmgr.Manager
 	TxStore *wtxmgr.Store
 
-	chainClient        *chain.RPCClient
+	chainClient        chain.Interface
 	chainClientLock    sync.Mutex
 	chainClientSynced  bool
 	chainClientSyncMtx sync.Mutex
 
-	lockedOutpoints map[wire.OutPoint]struct{}
-	relayFee        btcutil.Amount
-	relayFeeMu      sync.Mutex
+	relayFee     btcutil.Amount
+	relayFeeMu   sync.Mutex
+	feeEstimator FeeEstimator
+	feePolicy    FeePolicy
+	feeMu        sync.Mutex
+
+	recoveryWindow uint32
+	recoveryMu     sync.Mutex
+
+	passphraseOptions   *waddrmgr.ScryptOptions
+	passphraseOptionsMu sync.Mutex
 
 	// Channels for rescan processing.  Requests are added and merged with
 	// any waiting requests, before being sent to another goroutine to
@@ -89,14 +98,18 @@ type Wallet struct {
 	rescanProgress      chan *RescanProgressMsg
 	rescanFinished      chan *RescanFinishedMsg
 
-	// Channel for transaction creation requests.
+	// Channels for transaction creation and replacement requests.
 	createTxRequests chan createTxRequest
+	bumpFeeRequests  chan bumpFeeRequest
+	cpfpRequests     chan cpfpRequest
+	fundPsbtRequests chan fundPsbtRequest
 
 	// Channels for the manager locker.
 	unlockRequests     chan unlockRequest
 	lockRequests       chan struct{}
 	holdUnlockRequests chan chan HeldUnlock
 	lockState          chan bool
+	lockStatus         chan lockStatus
 	changePassphrase   chan changePassphraseRequest
 
 	NtfnServer *NotificationServer
@@ -132,13 +145,14 @@ func (w *Wallet) Start() {
 	go w.walletLocker()
 }
 
-// SynchronizeRPC associates the wallet with the consensus RPC client,
+// SynchronizeRPC associates the wallet with the chain backend, which may be a
+// consensus RPC client or any other chain.Interface implementation,
 // synchronizes the wallet with the latest changes to the blockchain, and
-// continuously updates the wallet through RPC notifications.
+// continuously updates the wallet through the backend's notifications.
 //
 // This method is unstable and will be removed when all syncing logic is moved
 // outside of the wallet package.
-func (w *Wallet) SynchronizeRPC(chainClient *chain.RPCClient) {
+func (w *Wallet) SynchronizeRPC(chainClient chain.Interface) {
 	w.quitMu.Lock()
 	select {
 	case <-w.quit:
@@ -158,6 +172,8 @@ func (w *Wallet) SynchronizeRPC(chainClient *chain.RPCClient) {
 	w.chainClient = chainClient
 	w.chainClientLock.Unlock()
 
+	w.SetFeeEstimator(chainClient)
+
 	// TODO: It would be preferable to either run these goroutines
 	// separately from the wallet (use wallet mutator functions to
 	// make changes from the RPC client) and not have to stop and
@@ -169,11 +185,30 @@ func (w *Wallet) SynchronizeRPC(chainClient *chain.RPCClient) {
 	go w.rescanRPCHandler()
 }
 
+// SynchronizeMux synchronizes the wallet with its own client of a chain backend
+// shared with other wallets through a ClientMux.  The client is started here,
+// and is stopped along with the wallet.
+func (w *Wallet) SynchronizeMux(m *chain.ClientMux) error {
+	c := m.NewClient()
+	err := c.Start()
+	if err != nil {
+		return err
+	}
+	w.SynchronizeRPC(c)
+
+	// The client is ignored when the wallet is shutting down or already
+	// syncing with another client.
+	if w.ChainClient() != chain.Interface(c) {
+		c.Stop()
+	}
+	return nil
+}
+
 // requireChainClient marks that a wallet method can only be completed when the
 // consensus RPC server is set.  This function and all functions that call it
 // are unstable and will need to be moved when the syncing code is moved out of
 // the wallet.
-func (w *Wallet) requireChainClient() (*chain.RPCClient, error) {
+func (w *Wallet) requireChainClient() (chain.Interface, error) {
 	w.chainClientLock.Lock()
 	chainClient := w.chainClient
 	w.chainClientLock.Unlock()
@@ -183,12 +218,13 @@ func (w *Wallet) requireChainClient() (*chain.RPCClient, error) {
 	return chainClient, nil
 }
 
-// ChainClient returns the optional consensus RPC client associated with the
-// wallet.
+// ChainClient returns the optional chain backend associated with the wallet.
+// Callers requiring methods of a specific backend, such as the RPC passthrough
+// of a consensus RPC client, must check the dynamic type of the result.
 //
 // This function is unstable and will be removed once sync logic is moved out of
 // the wallet.
-func (w *Wallet) ChainClient() *chain.RPCClient {
+func (w *Wallet) ChainClient() chain.Interface {
 	w.chainClientLock.Lock()
 	chainClient := w.chainClient
 	w.chainClientLock.Unlock()
@@ -334,6 +370,13 @@ func (w *Wallet) syncWithChain() error {
 		return err
 	}
 
+	// Complete any rollback interrupted by a crash or shutdown before the
+	// transaction store was rolled back.
+	err = w.finishPendingRollback()
+	if err != nil {
+		return err
+	}
+
 	// Request notifications for transactions sending to all wallet
 	// addresses.
 	addrs, unspent, err := w.activeData()
@@ -351,7 +394,11 @@ func (w *Wallet) syncWithChain() error {
 	// addresses ever created, including those that don't need to be watched
 	// anymore.  This code should be updated when this assumption is no
 	// longer true, but worst case would result in an unnecessary rescan.
-	if len(addrs) == 0 && len(unspent) == 0 {
+	//
+	// Wallets recovering addresses must always rescan, since addresses are
+	// derived by the recovery.
+	window := w.RecoveryWindow()
+	if window == 0 && len(addrs) == 0 && len(unspent) == 0 {
 		// TODO: It would be ideal if on initial sync wallet saved the
 		// last several recent blocks rather than just one.  This would
 		// avoid a full rescan for a one block reorg of the current
@@ -366,38 +413,36 @@ func (w *Wallet) syncWithChain() error {
 		})
 	}
 
-	// Compare previously-seen blocks against the chain server.  If any of
-	// these blocks no longer exist, rollback all of the missing blocks
-	// before catching up with the rescan.
-	iter := w.Manager.NewIterateRecentBlocks()
-	rollback := iter == nil
-	syncBlock := waddrmgr.BlockStamp{
-		Hash:   *w.chainParams.GenesisHash,
-		Height: 0,
-	}
-	for cont := iter != nil; cont; cont = iter.Prev() {
-		bs := iter.BlockStamp()
-		log.Debugf("Checking for previous saved block with height %v hash %v",
-			bs.Height, bs.Hash)
-		_, err = chainClient.GetBlock(&bs.Hash)
+	// Compare the synced blocks against the chain server.  If any of
+	// these blocks are no longer in the main chain, rollback all of the
+	// blocks after the fork point before catching up with the rescan.
+	forkPoint, err := w.findForkPoint(chainClient)
+	if err != nil {
+		return err
+	}
+	if *forkPoint != w.Manager.SyncedTo() {
+		err = w.rollback(forkPoint)
 		if err != nil {
-			rollback = true
-			continue
+			return err
 		}
-
-		log.Debug("Found matching block.")
-		syncBlock = bs
-		break
 	}
-	if rollback {
-		err = w.Manager.SetSyncedTo(&syncBlock)
+
+	// Discover the used addresses and accounts of a wallet restored from
+	// its seed before catching up with the rescan.  The recovery rescans
+	// the blocks through the chain server's best block, so the final rescan
+	// only covers blocks attached since, for all addresses including those
+	// derived by the recovery.
+	if window != 0 {
+		end, err := w.recoverAddresses(chainClient, w.Manager.SyncedTo(),
+			window)
+		if err != nil {
+			return err
+		}
+		err = w.setSyncedToScanned(chainClient, end)
 		if err != nil {
 			return err
 		}
-		// Rollback unconfirms transactions at and beyond the passed
-		// height, so add one to the new synced-to height to prevent
-		// unconfirming txs from the synced-to block.
-		err = w.TxStore.Rollback(syncBlock.Height + 1)
+		addrs, unspent, err = w.activeData()
 		if err != nil {
 			return err
 		}
@@ -408,10 +453,12 @@ func (w *Wallet) syncWithChain() error {
 
 type (
 	createTxRequest struct {
-		account uint32
-		outputs []*wire.TxOut
-		minconf int32
-		resp    chan createTxResponse
+		account  uint32
+		outputs  []*wire.TxOut
+		minconf  int32
+		feeRate  btcutil.Amount
+		selector txauthor.CoinSelector
+		resp     chan createTxResponse
 	}
 	createTxResponse struct {
 		tx  *txauthor.AuthoredTx
@@ -435,9 +482,23 @@ out:
 	for {
 		select {
 		case txr := <-w.createTxRequests:
-			tx, err := w.txToOutputs(txr.outputs, txr.account, txr.minconf)
+			tx, err := w.txToOutputs(txr.outputs, txr.account,
+				txr.minconf, txr.feeRate, txr.selector)
 			txr.resp <- createTxResponse{tx, err}
 
+		case req := <-w.bumpFeeRequests:
+			result, err := w.bumpFee(&req.txHash, req.feeRate)
+			req.resp <- bumpFeeResponse{result, err}
+
+		case req := <-w.cpfpRequests:
+			result, err := w.cpfp(&req.outPoint, req.feeRate)
+			req.resp <- cpfpResponse{result, err}
+
+		case req := <-w.fundPsbtRequests:
+			changeIndex, fee, err := w.fundPsbt(req.packet,
+				req.account, req.minconf, req.feeRate, req.selector)
+			req.resp <- fundPsbtResponse{changeIndex, fee, err}
+
 		case <-quit:
 			break out
 		}
@@ -448,17 +509,23 @@ out:
 // CreateSimpleTx creates a new signed transaction spending unspent P2PKH
 // outputs with at laest minconf confirmations spending to any number of
 // address/amount pairs.  Change and an appropriate transaction fee are
-// automatically included, if necessary.  All transaction creation through this
-// function is serialized to prevent the creation of many transactions which
-// spend the same outputs.
+// automatically included, if necessary.  The fee pays feeRate per kB of
+// serialized transaction size, or the wallet's estimated fee rate when feeRate
+// is zero.  Outputs to spend are chosen by selector, or by the largest-first
+// strategy if selector is nil.  All transaction creation through this function
+// is serialized to prevent the creation of many transactions which spend the
+// same outputs.
 func (w *Wallet) CreateSimpleTx(account uint32, outputs []*wire.TxOut,
-	minconf int32) (*txauthor.AuthoredTx, error) {
+	minconf int32, feeRate btcutil.Amount,
+	selector txauthor.CoinSelector) (*txauthor.AuthoredTx, error) {
 
 	req := createTxRequest{
-		account: account,
-		outputs: outputs,
-		minconf: minconf,
-		resp:    make(chan createTxResponse),
+		account:  account,
+		outputs:  outputs,
+		minconf:  minconf,
+		feeRate:  feeRate,
+		selector: selector,
+		resp:     make(chan createTxResponse),
 	}
 	w.createTxRequests <- req
 	resp := <-req.resp
@@ -469,14 +536,23 @@ type (
 	unlockRequest struct {
 		passphrase []byte
 		lockAfter  <-chan time.Time // nil prevents the timeout.
+		lockTime   time.Time        // zero when unknown or no timeout.
 		err        chan error
 	}
 
 	changePassphraseRequest struct {
 		old, new []byte
+		private  bool
 		err      chan error
 	}
 
+	// lockStatus describes whether the wallet is locked, and when an
+	// unlocked wallet will be locked again.
+	lockStatus struct {
+		locked   bool
+		lockTime time.Time // zero when locked, unknown or no timeout.
+	}
+
 	// HeldUnlock is a tool to prevent the wallet from automatically
 	// locking after some timeout before an operation which needed
 	// the unlocked wallet has finished.  Any aquired HeldUnlock
@@ -488,6 +564,7 @@ type (
 // walletLocker manages the locked/unlocked state of a wallet.
 func (w *Wallet) walletLocker() {
 	var timeout <-chan time.Time
+	var lockTime time.Time
 	holdChan := make(HeldUnlock)
 	quit := w.quitChan()
 out:
@@ -500,6 +577,7 @@ out:
 				continue
 			}
 			timeout = req.lockAfter
+			lockTime = req.lockTime
 			if timeout == nil {
 				log.Info("The wallet has been unlocked without a time limit")
 			} else {
@@ -509,8 +587,13 @@ out:
 			continue
 
 		case req := <-w.changePassphrase:
-			err := w.Manager.ChangePassphrase(req.old, req.new, true,
-				&waddrmgr.DefaultScryptOptions)
+			var err error
+			if req.private {
+				err = w.Manager.ChangePassphrase(req.old, req.new,
+					true, w.PassphraseOptions())
+			} else {
+				err = w.changePublicPassphrase(req.old, req.new)
+			}
 			req.err <- err
 			continue
 
@@ -538,6 +621,9 @@ out:
 		case w.lockState <- w.Manager.IsLocked():
 			continue
 
+		case w.lockStatus <- lockStatus{w.Manager.IsLocked(), lockTime}:
+			continue
+
 		case <-quit:
 			break out
 
@@ -548,6 +634,7 @@ out:
 		// Select statement fell through by an explicit lock or the
 		// timer expiring.  Lock the manager here.
 		timeout = nil
+		lockTime = time.Time{}
 		err := w.Manager.Lock()
 		if err != nil && !waddrmgr.IsError(err, waddrmgr.ErrLocked) {
 			log.Errorf("Could not lock wallet: %v", err)
@@ -573,6 +660,25 @@ func (w *Wallet) Unlock(passphrase []byte, lock <-chan time.Time) error {
 	return <-err
 }
 
+// UnlockUntil unlocks the wallet's address manager and relocks it at lockTime.
+// A zero lockTime leaves the wallet unlocked until it is explicitly locked.
+// Unlike Unlock, the time the wallet will be locked is remembered and can be
+// queried with LockState.
+func (w *Wallet) UnlockUntil(passphrase []byte, lockTime time.Time) error {
+	var lock <-chan time.Time
+	if !lockTime.IsZero() {
+		lock = time.After(time.Until(lockTime))
+	}
+	err := make(chan error, 1)
+	w.unlockRequests <- unlockRequest{
+		passphrase: passphrase,
+		lockAfter:  lock,
+		lockTime:   lockTime,
+		err:        err,
+	}
+	return <-err
+}
+
 // Lock locks the wallet's address manager.
 func (w *Wallet) Lock() {
 	w.lockRequests <- struct{}{}
@@ -583,6 +689,19 @@ func (w *Wallet) Locked() bool {
 	return <-w.lockState
 }
 
+// LockState returns whether the wallet is locked, and the time at which the
+// wallet will be locked again after being unlocked by UnlockUntil.  Both are
+// read at once, so the time always belongs to the returned lock state.  The
+// zero time is returned when the wallet is locked or when it was unlocked
+// without a known time limit.
+func (w *Wallet) LockState() (bool, time.Time) {
+	status := <-w.lockStatus
+	if status.locked {
+		return true, time.Time{}
+	}
+	return false, status.lockTime
+}
+
 // HoldUnlock prevents the wallet from being locked.  The HeldUnlock object
 // *must* be released, or the wallet will forever remain unlocked.
 //
@@ -616,6 +735,22 @@ func (c HeldUnlock) Release() {
 // manager locking and unlocking.  The lock state will be the same as it was
 // before the password change.
 func (w *Wallet) ChangePassphrase(old, new []byte) error {
+	err := make(chan error, 1)
+	w.changePassphrase <- changePassphraseRequest{
+		old:     old,
+		new:     new,
+		private: true,
+		err:     err,
+	}
+	return <-err
+}
+
+// ChangePublicPassphrase attempts to change the public passphrase for a wallet
+// from old to new.  When the wallet database is encrypted with the public
+// passphrase, the passphrase of the database is changed as well.  Changing the
+// passphrase is synchronized with all other address manager locking and
+// unlocking.
+func (w *Wallet) ChangePublicPassphrase(old, new []byte) error {
 	err := make(chan error, 1)
 	w.changePassphrase <- changePassphraseRequest{
 		old: old,
@@ -625,6 +760,62 @@ func (w *Wallet) ChangePassphrase(old, new []byte) error {
 	return <-err
 }
 
+// changePublicPassphrase changes the public passphrase of the address manager
+// and of an encrypted wallet database.  The address manager passphrase is
+// changed while the database opens with both passphrases, so the wallet may be
+// opened with either passphrase if the change is interrupted by a crash, and
+// Open ends the interrupted database passphrase change.
+func (w *Wallet) changePublicPassphrase(old, new []byte) error {
+	opts := w.PassphraseOptions()
+	changeManager := func() error {
+		return w.Manager.ChangePassphrase(old, new, false, opts)
+	}
+	var err error
+	if encrypted.IsEncrypted(w.db) {
+		err = encrypted.ChangePassphrase(w.db, old, new, changeManager)
+		if err == encrypted.ErrInvalidPassphrase {
+			// Report the error of the address manager, since the
+			// database shares its public passphrase.
+			err = waddrmgr.ManagerError{
+				ErrorCode:   waddrmgr.ErrWrongPassphrase,
+				Description: "invalid passphrase for public master key",
+			}
+		}
+	} else {
+		err = changeManager()
+	}
+	if err != nil {
+		return err
+	}
+
+	w.publicPassphraseMu.Lock()
+	w.publicPassphrase = append([]byte(nil), new...)
+	w.publicPassphraseMu.Unlock()
+	return nil
+}
+
+// SetPassphraseOptions sets the options used to derive the master keys of
+// changed passphrases.  Setting options selecting Argon2id migrates master keys
+// derived with scrypt the next time their passphrase is changed.  Nil options
+// restore the default scrypt options.
+func (w *Wallet) SetPassphraseOptions(opts *waddrmgr.ScryptOptions) {
+	w.passphraseOptionsMu.Lock()
+	w.passphraseOptions = opts
+	w.passphraseOptionsMu.Unlock()
+}
+
+// PassphraseOptions returns the options used to derive the master keys of
+// changed passphrases.  See SetPassphraseOptions for details.
+func (w *Wallet) PassphraseOptions() *waddrmgr.ScryptOptions {
+	w.passphraseOptionsMu.Lock()
+	opts := w.passphraseOptions
+	w.passphraseOptionsMu.Unlock()
+	if opts == nil {
+		opts = &waddrmgr.DefaultScryptOptions
+	}
+	return opts
+}
+
 // AccountUsed returns whether there are any recorded transactions spending to
 // a given account. It returns true if atleast one address in the account was
 // used and false if no address in the account was used.
@@ -713,13 +904,19 @@ func (w *Wallet) CalculateAccountBalances(account uint32, confirms int32) (Balan
 // CurrentAddress gets the most recently requested Bitcoin payment address
 // from a wallet.  If the address has already been used (there is at least
 // one transaction spending to it in the blockchain or btcd mempool), the next
-// chained address is returned.
-func (w *Wallet) CurrentAddress(account uint32) (btcutil.Address, error) {
+// chained address is returned.  The account must derive addresses of type
+// addrType; see NewAddress.
+func (w *Wallet) CurrentAddress(account uint32, addrType waddrmgr.AddressType) (btcutil.Address, error) {
+	err := w.checkAccountAddressType(account, addrType)
+	if err != nil {
+		return nil, err
+	}
+
 	addr, err := w.Manager.LastExternalAddress(account)
 	if err != nil {
 		// If no address exists yet, create the first external address
 		if waddrmgr.IsError(err, waddrmgr.ErrAddressNotFound) {
-			return w.NewAddress(account)
+			return w.NewAddress(account, addrType)
 		}
 		return nil, err
 	}
@@ -730,7 +927,7 @@ func (w *Wallet) CurrentAddress(account uint32) (btcutil.Address, error) {
 		return nil, err
 	}
 	if used {
-		return w.NewAddress(account)
+		return w.NewAddress(account, addrType)
 	}
 
 	return addr.Address(), nil
@@ -755,9 +952,10 @@ func (w *Wallet) RenameAccount(account uint32, newName string) error {
 }
 
 // NextAccount creates the next account and returns its account number.  The
-// name must be unique to the account.
-func (w *Wallet) NextAccount(name string) (uint32, error) {
-	account, err := w.Manager.NewAccount(name)
+// name must be unique to the account.  All addresses derived for the account
+// will be of type addrType.
+func (w *Wallet) NextAccount(name string, addrType waddrmgr.AddressType) (uint32, error) {
+	account, err := w.Manager.NewAccountWithType(name, addrType)
 	if err != nil {
 		return 0, err
 	}
@@ -897,6 +1095,13 @@ outputs:
 			}
 		}
 
+		// The comment is the label of the output, or the label of the
+		// transaction if the output is not labeled.
+		comment, ok := details.OutputLabels[uint32(i)]
+		if !ok {
+			comment = details.Label
+		}
+
 		amountF64 := btcutil.Amount(output.Value).ToBTC()
 		result := btcjson.ListTransactionsResult{
 			// Fields left zeroed:
@@ -909,6 +1114,7 @@ outputs:
 			//   Amount
 			//   Fee
 			Address:         address,
+			Comment:         comment,
 			Vout:            uint32(i),
 			Confirmations:   confirmations,
 			Generated:       generated,
@@ -1031,11 +1237,13 @@ func (w *Wallet) ListAddressTransactions(pkHashes map[string]struct{}) (
 				if err != nil || len(addrs) != 1 {
 					continue
 				}
-				apkh, ok := addrs[0].(*btcutil.AddressPubKeyHash)
-				if !ok {
+				switch addrs[0].(type) {
+				case *btcutil.AddressPubKeyHash,
+					*btcutil.AddressWitnessPubKeyHash:
+				default:
 					continue
 				}
-				_, ok = pkHashes[string(apkh.ScriptAddress())]
+				_, ok := pkHashes[string(addrs[0].ScriptAddress())]
 				if !ok {
 					continue
 				}
@@ -1127,7 +1335,6 @@ func (w *Wallet) GetTransactions(startBlock, endBlock *BlockIdentifier, cancel <
 	// TODO: Fetching block heights by their hashes is inherently racy
 	// because not all block headers are saved but when they are for SPV the
 	// db can be queried directly without this.
-	var startResp, endResp btcrpcclient.FutureGetBlockVerboseResult
 	if startBlock != nil {
 		if startBlock.hash == nil {
 			start = startBlock.height
@@ -1135,7 +1342,11 @@ func (w *Wallet) GetTransactions(startBlock, endBlock *BlockIdentifier, cancel <
 			if chainClient == nil {
 				return nil, errors.New("no chain server client")
 			}
-			startResp = chainClient.GetBlockVerboseAsync(startBlock.hash)
+			height, err := chainClient.GetBlockHeight(startBlock.hash)
+			if err != nil {
+				return nil, err
+			}
+			start = height
 		}
 	}
 	if endBlock != nil {
@@ -1145,22 +1356,12 @@ func (w *Wallet) GetTransactions(startBlock, endBlock *BlockIdentifier, cancel <
 			if chainClient == nil {
 				return nil, errors.New("no chain server client")
 			}
-			endResp = chainClient.GetBlockVerboseAsync(endBlock.hash)
-		}
-	}
-	if startResp != nil {
-		resp, err := startResp.Receive()
-		if err != nil {
-			return nil, err
-		}
-		start = int32(resp.Height)
-	}
-	if endResp != nil {
-		resp, err := endResp.Receive()
-		if err != nil {
-			return nil, err
+			height, err := chainClient.GetBlockHeight(endBlock.hash)
+			if err != nil {
+				return nil, err
+			}
+			end = height
 		}
-		end = int32(resp.Height)
 	}
 
 	var res GetTransactionsResult
@@ -1326,6 +1527,11 @@ func (w *Wallet) ListUnspent(minconf, maxconf int32,
 		return nil, err
 	}
 
+	locked, err := w.lockedOutpointSet()
+	if err != nil {
+		return nil, err
+	}
+
 	results := make([]*btcjson.ListUnspentResult, 0, len(unspent))
 	for i := range unspent {
 		output := &unspent[i]
@@ -1346,7 +1552,7 @@ func (w *Wallet) ListUnspent(minconf, maxconf int32,
 		}
 
 		// Exclude locked outputs from the result set.
-		if w.LockedOutpoint(output.OutPoint) {
+		if _, ok := locked[output.OutPoint]; ok {
 			continue
 		}
 
@@ -1566,8 +1772,17 @@ func (w *Wallet) ExportWatchingWallet() (string, error) {
 	defer os.Remove(woDbPath)
 
 	// Open the new database, get the address manager namespace, and open
-	// it.
-	woDb, err := walletdb.Open("bdb", woDbPath)
+	// it.  The copy of an encrypted database is encrypted with the same
+	// public passphrase.
+	w.publicPassphraseMu.Lock()
+	pubPass := w.publicPassphrase
+	w.publicPassphraseMu.Unlock()
+	var woDb walletdb.DB
+	if encrypted.IsEncrypted(w.db) {
+		woDb, err = walletdb.Open("encrypted", "bdb", pubPass, woDbPath)
+	} else {
+		woDb, err = walletdb.Open("bdb", woDbPath)
+	}
 	if err != nil {
 		_ = os.Remove(woDbPath)
 		return "", err
@@ -1578,7 +1793,7 @@ func (w *Wallet) ExportWatchingWallet() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	woMgr, err := waddrmgr.Open(namespace, w.publicPassphrase,
+	woMgr, err := waddrmgr.Open(namespace, pubPass,
 		w.chainParams, nil)
 	if err != nil {
 		return "", err
@@ -1610,44 +1825,54 @@ func exportBase64DB(db walletdb.DB) (string, error) {
 }
 
 // LockedOutpoint returns whether an outpoint has been marked as locked and
-// should not be used as an input for created transactions.
+// should not be used as an input for created transactions.  Outpoints are
+// reported as locked if their lock can not be read from the database.
 func (w *Wallet) LockedOutpoint(op wire.OutPoint) bool {
-	_, locked := w.lockedOutpoints[op]
-	return locked
+	lock, err := w.TxStore.LockedOutput(op)
+	if err != nil {
+		log.Errorf("Cannot read lock of outpoint %v: %v", op, err)
+		return true
+	}
+	return lock != nil
 }
 
 // LockOutpoint marks an outpoint as locked, that is, it should not be used as
-// an input for newly created transactions.
-func (w *Wallet) LockOutpoint(op wire.OutPoint) {
-	w.lockedOutpoints[op] = struct{}{}
+// an input for newly created transactions.  The lock is saved in the database
+// with a free-form lock ID, and expires at the expiration time unless it is
+// the zero time.  Locking an already locked outpoint replaces its lock.
+func (w *Wallet) LockOutpoint(op wire.OutPoint, lockID string, expiration time.Time) error {
+	return w.TxStore.LockOutput(op, lockID, expiration)
 }
 
 // UnlockOutpoint marks an outpoint as unlocked, that is, it may be used as an
 // input for newly created transactions.
-func (w *Wallet) UnlockOutpoint(op wire.OutPoint) {
-	delete(w.lockedOutpoints, op)
+func (w *Wallet) UnlockOutpoint(op wire.OutPoint) error {
+	return w.TxStore.UnlockOutput(op)
 }
 
 // ResetLockedOutpoints resets the set of locked outpoints so all may be used
 // as inputs for new transactions.
-func (w *Wallet) ResetLockedOutpoints() {
-	w.lockedOutpoints = map[wire.OutPoint]struct{}{}
+func (w *Wallet) ResetLockedOutpoints() error {
+	return w.TxStore.ResetLockedOutputs()
 }
 
-// LockedOutpoints returns a slice of currently locked outpoints.  This is
-// intended to be used by marshaling the result as a JSON array for
-// listlockunspent RPC results.
-func (w *Wallet) LockedOutpoints() []btcjson.TransactionInput {
-	locked := make([]btcjson.TransactionInput, len(w.lockedOutpoints))
-	i := 0
-	for op := range w.lockedOutpoints {
-		locked[i] = btcjson.TransactionInput{
-			Txid: op.Hash.String(),
-			Vout: op.Index,
-		}
-		i++
+// LockedOutpoints returns the locks of all currently locked outpoints.
+// Expired locks are not returned.
+func (w *Wallet) LockedOutpoints() ([]wtxmgr.LockedOutput, error) {
+	return w.TxStore.LockedOutputs()
+}
+
+// lockedOutpointSet returns the set of currently locked outpoints.
+func (w *Wallet) lockedOutpointSet() (map[wire.OutPoint]struct{}, error) {
+	locks, err := w.TxStore.LockedOutputs()
+	if err != nil {
+		return nil, err
 	}
-	return locked
+	locked := make(map[wire.OutPoint]struct{}, len(locks))
+	for i := range locks {
+		locked[locks[i].OutPoint] = struct{}{}
+	}
+	return locked, nil
 }
 
 // ResendUnminedTxs iterates through all transactions that spend from wallet
@@ -1694,8 +1919,41 @@ func (w *Wallet) SortedActivePaymentAddresses() ([]string, error) {
 	return addrStrs, nil
 }
 
-// NewAddress returns the next external chained address for a wallet.
-func (w *Wallet) NewAddress(account uint32) (btcutil.Address, error) {
+// checkAccountAddressType returns an error if the account does not derive
+// addresses of type addrType.  Accounts derive addresses of a single type,
+// which is chosen when the account is created.
+func (w *Wallet) checkAccountAddressType(account uint32, addrType waddrmgr.AddressType) error {
+	props, err := w.Manager.AccountProperties(account)
+	if err != nil {
+		return err
+	}
+	if props.AddressType != addrType {
+		return waddrmgr.ManagerError{
+			ErrorCode: waddrmgr.ErrUnsupportedAddressType,
+			Description: fmt.Sprintf("account %d derives %v addresses, "+
+				"not %v addresses", account, props.AddressType,
+				addrType),
+		}
+	}
+	return nil
+}
+
+// NewAddress returns the next external chained address for a wallet.  The
+// account must derive addresses of type addrType.
+//
+// The address type does not select the kind of address returned.  Accounts
+// derive addresses of a single type, chosen when the account is created, and
+// ErrUnsupportedAddressType is returned when addrType is not the account's
+// type.  Addresses of another type are received by creating an account of that
+// type with NewAccountWithType.  Wallets created before BIP0049 support do not
+// hold the BIP0049 cointype keys, which can only be derived from the seed, and
+// can not create accounts of nested witness addresses.
+func (w *Wallet) NewAddress(account uint32, addrType waddrmgr.AddressType) (btcutil.Address, error) {
+	err := w.checkAccountAddressType(account, addrType)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get next address from wallet.
 	addrs, err := w.Manager.NextExternalAddresses(account, 1)
 	if err != nil {
@@ -1854,9 +2112,13 @@ func (w *Wallet) TotalReceivedForAddr(addr btcutil.Address, minConf int32) (btcu
 }
 
 // SendOutputs creates and sends payment transactions. It returns the
-// transaction hash upon success.
+// transaction hash upon success.  The transaction pays feeRate per kB of
+// serialized size, or the wallet's estimated fee rate when feeRate is zero.
+// Outputs to spend are chosen by selector, or by the largest-first strategy if
+// selector is nil.
 func (w *Wallet) SendOutputs(outputs []*wire.TxOut, account uint32,
-	minconf int32) (*chainhash.Hash, error) {
+	minconf int32, feeRate btcutil.Amount,
+	selector txauthor.CoinSelector) (*chainhash.Hash, error) {
 
 	chainClient, err := w.requireChainClient()
 	if err != nil {
@@ -1873,7 +2135,8 @@ func (w *Wallet) SendOutputs(outputs []*wire.TxOut, account uint32,
 
 	// Create transaction, replying with an error if the creation
 	// was not successful.
-	createdTx, err := w.CreateSimpleTx(account, outputs, minconf)
+	createdTx, err := w.CreateSimpleTx(account, outputs, minconf, feeRate,
+		selector)
 	if err != nil {
 		return nil, err
 	}
@@ -1926,21 +2189,31 @@ func (w *Wallet) SignTransaction(tx *wire.MsgTx, hashType txscript.SigHashType,
 	p2shRedeemScriptsByAddress map[string][]byte) ([]SignatureError, error) {
 
 	var signErrors []SignatureError
+	hashCache := txscript.NewTxSigHashes(tx)
 	for i, txIn := range tx.TxIn {
+		// The previous output amount is only known when the output
+		// is recorded by the wallet, and is only required to sign and
+		// verify witness inputs.
+		var prevOutAmount int64
+		prevHash := &txIn.PreviousOutPoint.Hash
+		prevIndex := txIn.PreviousOutPoint.Index
 		prevOutScript, ok := additionalPrevScripts[txIn.PreviousOutPoint]
-		if !ok {
-			prevHash := &txIn.PreviousOutPoint.Hash
-			prevIndex := txIn.PreviousOutPoint.Index
-			txDetails, err := w.TxStore.TxDetails(prevHash)
-			if err != nil {
-				return nil, fmt.Errorf("Cannot query previous transaction "+
-					"details for %v: %v", txIn.PreviousOutPoint, err)
-			}
-			if txDetails == nil {
-				return nil, fmt.Errorf("%v not found",
-					txIn.PreviousOutPoint)
+		txDetails, err := w.TxStore.TxDetails(prevHash)
+		if err != nil {
+			return nil, fmt.Errorf("Cannot query previous transaction "+
+				"details for %v: %v", txIn.PreviousOutPoint, err)
+		}
+		if txDetails != nil && int(prevIndex) < len(txDetails.MsgTx.TxOut) {
+			prevOut := txDetails.MsgTx.TxOut[prevIndex]
+			prevOutAmount = prevOut.Value
+			if !ok {
+				prevOutScript = prevOut.PkScript
+				ok = true
 			}
-			prevOutScript = txDetails.MsgTx.TxOut[prevIndex].PkScript
+		}
+		if !ok {
+			return nil, fmt.Errorf("%v not found",
+				txIn.PreviousOutPoint)
 		}
 
 		// Set up our callbacks that we pass to txscript so it can
@@ -1991,6 +2264,10 @@ func (w *Wallet) SignTransaction(tx *wire.MsgTx, hashType txscript.SigHashType,
 			if err != nil {
 				return nil, err
 			}
+			if address.AddrType() == waddrmgr.NestedWitnessPubKey {
+				return nestedWitnessProgram(address,
+					w.ChainParams())
+			}
 			sa, ok := address.(waddrmgr.ManagedScriptAddress)
 			if !ok {
 				return nil, errors.New("address is not a script" +
@@ -2003,9 +2280,56 @@ func (w *Wallet) SignTransaction(tx *wire.MsgTx, hashType txscript.SigHashType,
 		// SigHashSingle inputs can only be signed if there's a
 		// corresponding output. However this could be already signed,
 		// so we always verify the output.
-		if (hashType&txscript.SigHashSingle) !=
-			txscript.SigHashSingle || i < len(tx.TxOut) {
+		signable := (hashType&txscript.SigHashSingle) !=
+			txscript.SigHashSingle || i < len(tx.TxOut)
+
+		// Inputs redeeming p2sh outputs which nest a witness program
+		// are signed as witness inputs for the witness program, which
+		// is pushed by the signature script.
+		witnessProgram := prevOutScript
+		var sigScript []byte
+		if txscript.IsPayToScriptHash(prevOutScript) {
+			_, addrs, _, err := txscript.ExtractPkScriptAddrs(
+				prevOutScript, w.ChainParams())
+			if err == nil && len(addrs) == 1 {
+				script, err := getScript.GetScript(addrs[0])
+				if err == nil && txscript.IsPayToWitnessPubKeyHash(script) {
+					witnessProgram = script
+					sigScript, err = txscript.NewScriptBuilder().
+						AddData(script).Script()
+					if err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
 
+		switch {
+		case signable && txscript.IsPayToWitnessPubKeyHash(witnessProgram):
+			if txDetails == nil {
+				signErrors = append(signErrors, SignatureError{
+					InputIndex: uint32(i),
+					Error: errors.New("previous output " +
+						"amount required to sign witness input"),
+				})
+				continue
+			}
+			witness, err := signWitnessPubKeyHash(w.ChainParams(),
+				tx, hashCache, i, prevOutAmount, witnessProgram,
+				hashType, getKey)
+			// Failure to sign isn't an error, it just means that
+			// the tx isn't complete.
+			if err != nil {
+				signErrors = append(signErrors, SignatureError{
+					InputIndex: uint32(i),
+					Error:      err,
+				})
+				continue
+			}
+			txIn.SignatureScript = sigScript
+			txIn.Witness = witness
+
+		case signable:
 			script, err := txscript.SignTxOutput(w.ChainParams(),
 				tx, i, prevOutScript, hashType, getKey,
 				getScript, txIn.SignatureScript)
@@ -2024,7 +2348,7 @@ func (w *Wallet) SignTransaction(tx *wire.MsgTx, hashType txscript.SigHashType,
 		// Either it was already signed or we just signed it.
 		// Find out if it is completely satisfied or still needs more.
 		vm, err := txscript.NewEngine(prevOutScript, tx, i,
-			txscript.StandardVerifyFlags, nil,nil,0)
+			txscript.StandardVerifyFlags, nil, hashCache, prevOutAmount)
 		if err == nil {
 			err = vm.Execute()
 		}
@@ -2039,6 +2363,33 @@ func (w *Wallet) SignTransaction(tx *wire.MsgTx, hashType txscript.SigHashType,
 	return signErrors, nil
 }
 
+// signWitnessPubKeyHash creates the witness for a pay-to-witness-pubkey-hash
+// input using the BIP0143 signature digest.  The private key for the witness
+// program's address is fetched with getKey.
+func signWitnessPubKeyHash(chainParams *chaincfg.Params, tx *wire.MsgTx,
+	hashCache *txscript.TxSigHashes, idx int, amount int64, pkScript []byte,
+	hashType txscript.SigHashType, getKey txscript.KeyDB) (wire.TxWitness, error) {
+
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(pkScript, chainParams)
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) != 1 {
+		return nil, errors.New("witness script does not pay to a " +
+			"single address")
+	}
+	privKey, compressed, err := getKey.GetKey(addrs[0])
+	if err != nil {
+		return nil, err
+	}
+	if !compressed {
+		return nil, errors.New("witness inputs require compressed keys")
+	}
+
+	return txscript.WitnessSignature(tx, hashCache, idx, amount, pkScript,
+		hashType, privKey, true)
+}
+
 // PublishTransaction sends the transaction to the consensus RPC server so it
 // can be propigated to other nodes and eventually mined.
 //
@@ -2064,6 +2415,15 @@ func (w *Wallet) ChainParams() *chaincfg.Params {
 // seed is non-nil, it is used.  Otherwise, a secure random seed of the
 // recommended length is generated.
 func Create(db walletdb.DB, pubPass, privPass, seed []byte, params *chaincfg.Params) error {
+	return CreateWithOptions(db, pubPass, privPass, seed, params, nil)
+}
+
+// CreateWithOptions creates a new wallet like Create, deriving the master keys
+// from the passphrases with the passed options.  Nil options select the default
+// scrypt options.
+func CreateWithOptions(db walletdb.DB, pubPass, privPass, seed []byte,
+	params *chaincfg.Params, config *waddrmgr.ScryptOptions) error {
+
 	// If a seed was provided, ensure that it is of valid length. Otherwise,
 	// we generate a random seed for the wallet with the recommended seed
 	// length.
@@ -2086,7 +2446,7 @@ func Create(db walletdb.DB, pubPass, privPass, seed []byte, params *chaincfg.Par
 		return err
 	}
 	err = waddrmgr.Create(addrMgrNamespace, seed, pubPass, privPass,
-		params, nil)
+		params, config)
 	if err != nil {
 		return err
 	}
@@ -2113,6 +2473,15 @@ func Open(db walletdb.DB, pubPass []byte, cbs *waddrmgr.OpenCallbacks, params *c
 	if err != nil {
 		return nil, err
 	}
+	// The address manager accepted the public passphrase, so it is the
+	// passphrase of an encrypted database whose passphrase change was
+	// interrupted.
+	if encrypted.IsEncrypted(db) {
+		err = encrypted.CommitPassphrase(db)
+		if err != nil {
+			return nil, err
+		}
+	}
 	noTxMgr, err := walletdb.NamespaceIsEmpty(txMgrNS)
 	if err != nil {
 		return nil, err
@@ -2139,18 +2508,22 @@ func Open(db walletdb.DB, pubPass []byte, cbs *waddrmgr.OpenCallbacks, params *c
 		db:                  db,
 		Manager:             addrMgr,
 		TxStore:             txMgr,
-		lockedOutpoints:     map[wire.OutPoint]struct{}{},
 		relayFee:            txrules.DefaultRelayFeePerKb,
+		feePolicy:           DefaultFeePolicy(),
 		rescanAddJob:        make(chan *RescanJob),
 		rescanBatch:         make(chan *rescanBatch),
 		rescanNotifications: make(chan interface{}),
 		rescanProgress:      make(chan *RescanProgressMsg),
 		rescanFinished:      make(chan *RescanFinishedMsg),
 		createTxRequests:    make(chan createTxRequest),
+		bumpFeeRequests:     make(chan bumpFeeRequest),
+		cpfpRequests:        make(chan cpfpRequest),
+		fundPsbtRequests:    make(chan fundPsbtRequest),
 		unlockRequests:      make(chan unlockRequest),
 		lockRequests:        make(chan struct{}),
 		holdUnlockRequests:  make(chan chan HeldUnlock),
 		lockState:           make(chan bool),
+		lockStatus:          make(chan lockStatus),
 		changePassphrase:    make(chan changePassphraseRequest),
 		chainParams:         params,
 		quit:                make(chan struct{}),
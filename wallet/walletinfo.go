@@ -0,0 +1,269 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+)
+
+// CalculateWalletBalances sums the unspent outputs of every account of the
+// wallet, splitting the total into spendable and immature coinbase balances
+// the same way as CalculateAccountBalances.
+func (w *Wallet) CalculateWalletBalances(confirms int32) (Balances, error) {
+	var bals Balances
+
+	syncBlock := w.Manager.SyncedTo()
+
+	unspent, err := w.TxStore.UnspentOutputs()
+	if err != nil {
+		return bals, err
+	}
+	for i := range unspent {
+		output := &unspent[i]
+
+		bals.Total += output.Amount
+		if output.FromCoinBase && !confirmed(int32(w.chainParams.CoinbaseMaturity),
+			output.Height, syncBlock.Height) {
+			bals.ImmatureReward += output.Amount
+		} else if confirmed(confirms, output.Height, syncBlock.Height) {
+			bals.Spendable += output.Amount
+		}
+	}
+	return bals, nil
+}
+
+// TxCount returns the number of mined and unmined transactions recorded by the
+// wallet.
+func (w *Wallet) TxCount() (int, error) {
+	var n int
+	err := w.TxStore.RangeTransactions(0, -1, func(details []wtxmgr.TxDetails) (bool, error) {
+		n += len(details)
+		return false, nil
+	})
+	return n, err
+}
+
+// AccountKeyPool describes the addresses an account has derived ahead of their
+// use.
+type AccountKeyPool struct {
+	AccountNumber uint32
+	AccountName   string
+	External      uint32
+	Internal      uint32
+}
+
+// KeyPoolSizes returns, for every account deriving addresses, the number of
+// external and internal addresses which have been derived but not yet used in
+// any transaction.  The imported account has no key pool and is not included.
+func (w *Wallet) KeyPoolSizes() ([]AccountKeyPool, error) {
+	var accounts []uint32
+	err := w.Manager.ForEachAccount(func(account uint32) error {
+		if account != waddrmgr.ImportedAddrAccount {
+			accounts = append(accounts, account)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pools := make([]AccountKeyPool, 0, len(accounts))
+	for _, account := range accounts {
+		name, err := w.Manager.AccountName(account)
+		if err != nil {
+			return nil, err
+		}
+		pool := AccountKeyPool{AccountNumber: account, AccountName: name}
+		err = w.Manager.ForEachAccountAddress(account,
+			func(maddr waddrmgr.ManagedAddress) error {
+				if maddr.Imported() {
+					return nil
+				}
+				used, err := maddr.Used()
+				if err != nil || used {
+					return err
+				}
+				if maddr.Internal() {
+					pool.Internal++
+				} else {
+					pool.External++
+				}
+				return nil
+			})
+		if err != nil {
+			return nil, err
+		}
+		pools = append(pools, pool)
+	}
+	return pools, nil
+}
+
+// GroupedAddress is a wallet address belonging to an address grouping, along
+// with the total of its unspent outputs and the name of its account.
+type GroupedAddress struct {
+	Address btcutil.Address
+	Balance btcutil.Amount
+	Account string
+}
+
+// groupedTx records the wallet outputs a transaction spends and creates, as
+// needed to group the wallet's addresses.
+type groupedTx struct {
+	debits []wire.OutPoint
+	change []int // Address indexes of the credited change outputs.
+}
+
+// AddressGroupings clusters the wallet's addresses by common input ownership.
+// Addresses which are spent together as inputs of the same transaction are
+// assumed to be controlled by the same party and are placed in the same
+// grouping, as are the change outputs of those transactions.  Every address
+// which has received an output appears in exactly one grouping.
+//
+// Groupings are ordered by the first transaction paying to any of their
+// addresses, and addresses within a grouping by the first transaction paying
+// to them.
+func (w *Wallet) AddressGroupings() ([][]GroupedAddress, error) {
+	var (
+		addrs    []btcutil.Address
+		addrIdx  = make(map[string]int)
+		outAddrs = make(map[wire.OutPoint]int)
+		txs      []groupedTx
+	)
+	indexOf := func(pkScript []byte) (int, bool) {
+		_, as, _, err := txscript.ExtractPkScriptAddrs(pkScript,
+			w.chainParams)
+		if err != nil || len(as) != 1 {
+			return 0, false
+		}
+		encoded := as[0].EncodeAddress()
+		i, ok := addrIdx[encoded]
+		if !ok {
+			i = len(addrs)
+			addrs = append(addrs, as[0])
+			addrIdx[encoded] = i
+		}
+		return i, true
+	}
+
+	// The spent output of a debit is always a credit of some earlier
+	// transaction, so record every credited address first and resolve
+	// debits once all transactions have been seen.
+	err := w.TxStore.RangeTransactions(0, -1, func(details []wtxmgr.TxDetails) (bool, error) {
+		for i := range details {
+			d := &details[i]
+			var tx groupedTx
+			for _, debit := range d.Debits {
+				tx.debits = append(tx.debits,
+					d.MsgTx.TxIn[debit.Index].PreviousOutPoint)
+			}
+			for _, credit := range d.Credits {
+				pkScript := d.MsgTx.TxOut[credit.Index].PkScript
+				a, ok := indexOf(pkScript)
+				if !ok {
+					continue
+				}
+				op := wire.OutPoint{Hash: d.Hash, Index: credit.Index}
+				outAddrs[op] = a
+				if credit.Change {
+					tx.change = append(tx.change, a)
+				}
+			}
+			txs = append(txs, tx)
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Union all addresses spent together along with the change outputs
+	// of the spending transaction.
+	parents := make([]int, len(addrs))
+	for i := range parents {
+		parents[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parents[i] != i {
+			parents[i] = find(parents[i])
+		}
+		return parents[i]
+	}
+	union := func(a, b int) {
+		ra, rb := find(a), find(b)
+		switch {
+		case ra < rb:
+			parents[rb] = ra
+		case rb < ra:
+			parents[ra] = rb
+		}
+	}
+	for i := range txs {
+		tx := &txs[i]
+		first := -1
+		for _, op := range tx.debits {
+			a, ok := outAddrs[op]
+			if !ok {
+				continue
+			}
+			if first == -1 {
+				first = a
+			}
+			union(first, a)
+		}
+		if first == -1 {
+			continue
+		}
+		for _, a := range tx.change {
+			union(first, a)
+		}
+	}
+
+	balances := make([]btcutil.Amount, len(addrs))
+	unspent, err := w.TxStore.UnspentOutputs()
+	if err != nil {
+		return nil, err
+	}
+	for i := range unspent {
+		if a, ok := outAddrs[unspent[i].OutPoint]; ok {
+			balances[a] += unspent[i].Amount
+		}
+	}
+
+	// Because union keeps the smallest index as the root, groupings and
+	// their members are ordered by first appearance when built in index
+	// order.
+	var groupings [][]GroupedAddress
+	groupIdx := make(map[int]int)
+	for i, addr := range addrs {
+		var account string
+		acct, err := w.Manager.AddrAccount(addr)
+		if err == nil {
+			account, err = w.Manager.AccountName(acct)
+		}
+		if err != nil && !waddrmgr.IsError(err, waddrmgr.ErrAddressNotFound) {
+			return nil, err
+		}
+		ga := GroupedAddress{
+			Address: addr,
+			Balance: balances[i],
+			Account: account,
+		}
+
+		root := find(i)
+		g, ok := groupIdx[root]
+		if !ok {
+			g = len(groupings)
+			groupIdx[root] = g
+			groupings = append(groupings, nil)
+		}
+		groupings[g] = append(groupings[g], ga)
+	}
+	return groupings, nil
+}
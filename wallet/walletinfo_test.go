@@ -0,0 +1,270 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+)
+
+// newTestAddresses returns n new external addresses of the default account.
+func newTestAddresses(t *testing.T, w *Wallet, n int) []btcutil.Address {
+	addrs := make([]btcutil.Address, n)
+	for i := range addrs {
+		addr, err := w.NewAddress(0, waddrmgr.PubKeyHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addrs[i] = addr
+	}
+	return addrs
+}
+
+// TestAddressGroupings ensures addresses spent together by a transaction are
+// grouped along with the change of the transaction, while addresses which are
+// never spent together remain in groupings of their own.
+func TestAddressGroupings(t *testing.T) {
+	t.Parallel()
+
+	w, client, teardown := newTestWallet(t)
+	defer teardown()
+
+	addrs := newTestAddresses(t, w, 4)
+	change, err := w.NewChangeAddress(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	syncTestWallet(t, w, client)
+
+	// The first three addresses are paid in separate blocks, and the
+	// fourth address is never paid.
+	var payments []*wire.MsgTx
+	for i, amount := range []btcutil.Amount{1e8, 2e8, 4e8} {
+		payment := client.payTx(t, addrs[i], amount)
+		client.mineBlock(t, payment)
+		payments = append(payments, payment)
+	}
+	waitForSync(t, w, client)
+	checkUnspentCount(t, w, 3)
+
+	// The outputs of the first two addresses are spent together, paying
+	// change to the change address.
+	changeScript, err := txscript.PayToAddrScript(change)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spend := wire.NewMsgTx(wire.TxVersion)
+	for _, payment := range payments[:2] {
+		hash := payment.TxHash()
+		spend.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&hash, 0), nil, nil))
+	}
+	spend.AddTxOut(wire.NewTxOut(1e8, []byte{txscript.OP_TRUE}))
+	spend.AddTxOut(wire.NewTxOut(1.9e8, changeScript))
+	client.AddUnminedTx(spend)
+	spendHash := spend.TxHash()
+	waitForUnspent(t, w, *wire.NewOutPoint(&spendHash, 1))
+
+	groupings, err := w.AddressGroupings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]GroupedAddress{
+		{
+			{Address: addrs[0], Balance: 0, Account: "default"},
+			{Address: addrs[1], Balance: 0, Account: "default"},
+			{Address: change, Balance: 1.9e8, Account: "default"},
+		},
+		{
+			{Address: addrs[2], Balance: 4e8, Account: "default"},
+		},
+	}
+	if len(groupings) != len(want) {
+		t.Fatalf("found %d groupings, expected %d: %v", len(groupings),
+			len(want), groupings)
+	}
+	for i := range want {
+		if len(groupings[i]) != len(want[i]) {
+			t.Errorf("grouping %d: found %v, expected %v", i,
+				groupings[i], want[i])
+			continue
+		}
+		for j, ga := range groupings[i] {
+			w := want[i][j]
+			if ga.Address.EncodeAddress() != w.Address.EncodeAddress() ||
+				ga.Balance != w.Balance || ga.Account != w.Account {
+
+				t.Errorf("grouping %d address %d: found %v %v "+
+					"%q, expected %v %v %q", i, j,
+					ga.Address, ga.Balance, ga.Account,
+					w.Address, w.Balance, w.Account)
+			}
+		}
+	}
+}
+
+// TestKeyPoolSizes ensures the key pool of every account counts the derived
+// addresses which have not been used, without the imported account.
+func TestKeyPoolSizes(t *testing.T) {
+	t.Parallel()
+
+	w, client, teardown := newTestWallet(t)
+	defer teardown()
+	err := w.Unlock(testPrivPass, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addrs := newTestAddresses(t, w, 3)
+	for i := 0; i < 2; i++ {
+		_, err := w.NewChangeAddress(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	account, err := w.NextAccount("savings", waddrmgr.PubKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = w.NewAddress(account, waddrmgr.PubKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wif, _ := dumpTestKey(t, 1)
+	_, err = w.Manager.ImportPrivateKey(wif, &waddrmgr.BlockStamp{
+		Hash: *testParams.GenesisHash,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Receiving an output uses the first address of the default account.
+	syncTestWallet(t, w, client)
+	payment := client.payTx(t, addrs[0], 1e8)
+	client.mineBlock(t, payment)
+	waitForSync(t, w, client)
+	paymentHash := payment.TxHash()
+	waitForUnspent(t, w, *wire.NewOutPoint(&paymentHash, 0))
+
+	pools, err := w.KeyPoolSizes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []AccountKeyPool{
+		{AccountNumber: 0, AccountName: "default", External: 2, Internal: 2},
+		{AccountNumber: account, AccountName: "savings", External: 1},
+	}
+	if !reflect.DeepEqual(pools, want) {
+		t.Errorf("key pools %+v, expected %+v", pools, want)
+	}
+}
+
+// TestCalculateWalletBalances ensures the wallet balance splits the unspent
+// outputs of every account into spendable, immature coinbase and unconfirmed
+// balances.
+func TestCalculateWalletBalances(t *testing.T) {
+	t.Parallel()
+
+	w, client, teardown := newTestWallet(t)
+	defer teardown()
+	err := w.Unlock(testPrivPass, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addrs := newTestAddresses(t, w, 3)
+	account, err := w.NextAccount("savings", waddrmgr.PubKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	savingsAddr, err := w.NewAddress(account, waddrmgr.PubKeyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	syncTestWallet(t, w, client)
+
+	// Blocks 1 and 2 pay the default and savings accounts, block 3 holds
+	// a coinbase paying the wallet, and a payment remains unmined.
+	client.mineBlock(t, client.payTx(t, addrs[0], 1e8))
+	client.mineBlock(t, client.payTx(t, savingsAddr, 2e8))
+	pkScript, err := txscript.PayToAddrScript(addrs[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	coinbase := wire.NewMsgTx(wire.TxVersion)
+	coinbase.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: *wire.NewOutPoint(&chainhash.Hash{},
+			wire.MaxPrevOutIndex),
+		SignatureScript: []byte{0x51, 0x51},
+		Sequence:        wire.MaxTxInSequenceNum,
+	})
+	coinbase.AddTxOut(wire.NewTxOut(50e8, pkScript))
+	client.mineBlock(t, coinbase)
+	unmined := client.payTx(t, addrs[2], 4e8)
+	client.AddUnminedTx(unmined)
+	waitForSync(t, w, client)
+	unminedHash := unmined.TxHash()
+	waitForUnspent(t, w, *wire.NewOutPoint(&unminedHash, 0))
+	checkUnspentCount(t, w, 4)
+
+	tests := []struct {
+		confirms int32
+		want     Balances
+	}{
+		{1, Balances{Total: 57e8, Spendable: 3e8, ImmatureReward: 50e8}},
+		{2, Balances{Total: 57e8, Spendable: 3e8, ImmatureReward: 50e8}},
+		{3, Balances{Total: 57e8, Spendable: 1e8, ImmatureReward: 50e8}},
+		{4, Balances{Total: 57e8, ImmatureReward: 50e8}},
+	}
+	for _, test := range tests {
+		bals, err := w.CalculateWalletBalances(test.confirms)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bals != test.want {
+			t.Errorf("%d confirmations: balances %+v, expected %+v",
+				test.confirms, bals, test.want)
+		}
+	}
+}
+
+// TestLockState ensures the lock state and lock time of the wallet are
+// reported together.
+func TestLockState(t *testing.T) {
+	t.Parallel()
+
+	w, _, teardown := newTestWallet(t)
+	defer teardown()
+
+	checkLockState := func(wantLocked bool, wantTime time.Time) {
+		t.Helper()
+		locked, lockTime := w.LockState()
+		if locked != wantLocked || !lockTime.Equal(wantTime) {
+			t.Errorf("lock state %v until %v, expected %v until %v",
+				locked, lockTime, wantLocked, wantTime)
+		}
+	}
+
+	checkLockState(true, time.Time{})
+	err := w.Unlock(testPrivPass, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkLockState(false, time.Time{})
+	lockTime := time.Now().Add(time.Hour)
+	err = w.UnlockUntil(testPrivPass, lockTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkLockState(false, lockTime)
+	w.Lock()
+	checkLockState(true, time.Time{})
+}
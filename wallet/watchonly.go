@@ -0,0 +1,203 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wallet
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wtxmgr"
+)
+
+// defaultAccountGapLimit is the gap limit of watch-only accounts when no
+// recovery window is set.
+const defaultAccountGapLimit = 20
+
+// accountGapLimit returns the number of unused addresses kept derived after the
+// last used address of each branch of a watch-only account.  Addresses of
+// these accounts are handed out by the external wallet holding the private
+// keys, so the wallet must watch addresses beyond the last one it has seen
+// used to discover further transactions.  The gap limit is the recovery
+// window, the gap limit used to discover the addresses of restored wallets, or
+// defaultAccountGapLimit when discovery is disabled.
+func (w *Wallet) accountGapLimit() uint32 {
+	window := w.RecoveryWindow()
+	if window == 0 {
+		return defaultAccountGapLimit
+	}
+	return window
+}
+
+// ImportAccount imports a watch-only account from an account-level extended
+// public key, such as the key of an account kept in cold storage.  The
+// account derives addresses of type addrType, and the gap limit of addresses
+// of both branches are derived and watched right away.  When an address of
+// the account is found used, more addresses are derived so that the gap
+// limit of unused addresses is kept.
+//
+// Balances of the account are tracked like any other account, and unsigned
+// transactions spending its outputs may be created with FundPsbt, to be signed
+// by the holder of the private keys.  If rescan is true, the blockchain is
+// rescanned for the account's addresses from the block bs, or the genesis
+// block if bs is nil.
+func (w *Wallet) ImportAccount(name string, acctKey *hdkeychain.ExtendedKey,
+	addrType waddrmgr.AddressType, bs *waddrmgr.BlockStamp, rescan bool) (uint32, error) {
+
+	account, err := w.Manager.ImportAccount(name, acctKey, addrType)
+	if err != nil {
+		return 0, err
+	}
+
+	var addrs []btcutil.Address
+	for _, internal := range []bool{false, true} {
+		branchAddrs, err := w.deriveAccountAddresses(account,
+			w.accountGapLimit(), internal)
+		if err != nil {
+			return 0, err
+		}
+		addrs = append(addrs, branchAddrs...)
+	}
+
+	if rescan {
+		if bs == nil {
+			bs = &waddrmgr.BlockStamp{
+				Hash:   *w.chainParams.GenesisHash,
+				Height: 0,
+			}
+		}
+		job := &RescanJob{
+			Addrs:      addrs,
+			BlockStamp: *bs,
+		}
+
+		// The rescan is not waited on, and its result is logged by
+		// the rescan handlers.
+		_ = w.SubmitRescan(job)
+	}
+
+	log.Infof("Imported watch-only account %q (account %d)", name, account)
+
+	props, err := w.Manager.AccountProperties(account)
+	if err != nil {
+		log.Errorf("Cannot fetch new account properties for notification "+
+			"after account import: %v", err)
+	} else {
+		w.NtfnServer.notifyAccountProperties(props)
+	}
+
+	return account, nil
+}
+
+// deriveAccountAddresses derives the next n addresses of a branch of an
+// account and requests notifications for transactions paying to them.
+func (w *Wallet) deriveAccountAddresses(account, n uint32,
+	internal bool) ([]btcutil.Address, error) {
+
+	var managed []waddrmgr.ManagedAddress
+	var err error
+	if internal {
+		managed, err = w.Manager.NextInternalAddresses(account, n)
+	} else {
+		managed, err = w.Manager.NextExternalAddresses(account, n)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := make([]btcutil.Address, len(managed))
+	for i, ma := range managed {
+		addrs[i] = ma.Address()
+	}
+	w.chainClientLock.Lock()
+	chainClient := w.chainClient
+	w.chainClientLock.Unlock()
+	if chainClient != nil {
+		err := chainClient.NotifyReceived(addrs)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return addrs, nil
+}
+
+// extendWatchOnlyAccount keeps the gap limit of unused addresses derived after
+// ma on its branch when ma, which was just found used, belongs to a
+// watch-only account.  When the address was used in a block, the block and
+// all later blocks are rescanned for the newly derived addresses, since other
+// transactions of the same block may already pay to them.
+func (w *Wallet) extendWatchOnlyAccount(ma waddrmgr.ManagedAddress,
+	block *wtxmgr.BlockMeta) error {
+
+	props, err := w.Manager.AccountProperties(ma.Account())
+	if err != nil {
+		return err
+	}
+	if !props.WatchOnly {
+		return nil
+	}
+	_, index, err := w.Manager.AddrBranchIndex(ma.Address())
+	if err != nil {
+		return err
+	}
+
+	derived := props.ExternalKeyCount
+	if ma.Internal() {
+		derived = props.InternalKeyCount
+	}
+	want := index + 1 + w.accountGapLimit()
+	if want <= derived {
+		return nil
+	}
+	addrs, err := w.deriveAccountAddresses(ma.Account(), want-derived,
+		ma.Internal())
+	if err != nil {
+		return err
+	}
+	log.Debugf("Derived %d %s for watch-only account %d", len(addrs),
+		pickNoun(len(addrs), "address", "addresses"), ma.Account())
+
+	props, err = w.Manager.AccountProperties(ma.Account())
+	if err != nil {
+		log.Errorf("Cannot fetch account properties for notification "+
+			"after extending watch-only account: %v", err)
+	} else {
+		w.NtfnServer.notifyAccountProperties(props)
+	}
+
+	if block != nil {
+		job := &RescanJob{
+			Addrs: addrs,
+			BlockStamp: waddrmgr.BlockStamp{
+				Hash:   block.Hash,
+				Height: block.Height,
+			},
+		}
+
+		// Chain notifications are being handled by the caller, so
+		// the rescan is submitted without blocking on the rescan
+		// handlers.
+		go w.SubmitRescan(job)
+	}
+	return nil
+}
+
+// checkAccountSigns returns an error if the account is a watch-only account,
+// for which the wallet is unable to sign transactions.
+func (w *Wallet) checkAccountSigns(account uint32) error {
+	props, err := w.Manager.AccountProperties(account)
+	if err != nil {
+		return err
+	}
+	if props.WatchOnly {
+		return waddrmgr.ManagerError{
+			ErrorCode: waddrmgr.ErrWatchingOnly,
+			Description: fmt.Sprintf("account %d is watch-only "+
+				"and can not sign transactions", account),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package encrypted
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+
+	"github.com/btcsuite/btcwallet/internal/zero"
+	"github.com/btcsuite/btcwallet/snacl"
+	"github.com/btcsuite/golangcrypto/nacl/secretbox"
+)
+
+// dbKeys are the keys which encrypt the keys and values of the database.  They
+// are generated when the database is created and are stored encrypted with the
+// key derived from the passphrase, so changing the passphrase does not
+// reencrypt the data.
+type dbKeys struct {
+	// encKey encrypts keys and values with XSalsa20 and Poly1305.
+	encKey snacl.CryptoKey
+
+	// macKey derives the nonces of encrypted keys.
+	macKey [sha256.Size]byte
+}
+
+// dbKeysSize is the size of serialized dbKeys.
+const dbKeysSize = snacl.KeySize + sha256.Size
+
+// generateDBKeys returns new random database keys.
+func generateDBKeys() (*dbKeys, error) {
+	encKey, err := snacl.GenerateCryptoKey()
+	if err != nil {
+		return nil, err
+	}
+	defer encKey.Zero()
+	macKey, err := snacl.GenerateCryptoKey()
+	if err != nil {
+		return nil, err
+	}
+	defer macKey.Zero()
+
+	k := new(dbKeys)
+	copy(k.encKey[:], encKey[:])
+	copy(k.macKey[:], macKey[:])
+	return k, nil
+}
+
+// serialize returns the keys serialized as the encryption key followed by the
+// MAC key.
+func (k *dbKeys) serialize() []byte {
+	b := make([]byte, 0, dbKeysSize)
+	b = append(b, k.encKey[:]...)
+	return append(b, k.macKey[:]...)
+}
+
+// deserializeDBKeys returns the keys serialized by serialize.
+func deserializeDBKeys(b []byte) (*dbKeys, error) {
+	if len(b) != dbKeysSize {
+		return nil, ErrCorrupt
+	}
+	k := new(dbKeys)
+	copy(k.encKey[:], b[:snacl.KeySize])
+	copy(k.macKey[:], b[snacl.KeySize:])
+	return k, nil
+}
+
+// zero clears the keys.
+func (k *dbKeys) zero() {
+	k.encKey.Zero()
+	zero.Bytea32(&k.macKey)
+}
+
+// keyNonce returns the nonce of an encrypted key, which is derived from the
+// plaintext key with HMAC-SHA256.
+func (k *dbKeys) keyNonce(key []byte) [snacl.NonceSize]byte {
+	mac := hmac.New(sha256.New, k.macKey[:])
+	mac.Write(key)
+	var nonce [snacl.NonceSize]byte
+	copy(nonce[:], mac.Sum(nil))
+	return nonce
+}
+
+// encryptKey encrypts a key of a bucket.  Keys are encrypted
+// deterministically, using a nonce derived from the plaintext key, so that a
+// key may be found by encrypting it again.  Encrypting the same key therefore
+// reveals that the keys are equal, but nothing else about them.
+func (k *dbKeys) encryptKey(key []byte) []byte {
+	nonce := k.keyNonce(key)
+	out := make([]byte, snacl.NonceSize, snacl.NonceSize+len(key)+
+		secretbox.Overhead)
+	copy(out, nonce[:])
+	return secretbox.Seal(out, key, &nonce, (*[snacl.KeySize]byte)(&k.encKey))
+}
+
+// decryptKey decrypts a key encrypted with encryptKey.
+func (k *dbKeys) decryptKey(in []byte) ([]byte, error) {
+	key, err := k.encKey.Decrypt(in)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the nonce was derived from the key, so that an encrypted value
+	// is never mistaken for an encrypted key.
+	nonce := k.keyNonce(key)
+	if !hmac.Equal(nonce[:], in[:snacl.NonceSize]) {
+		return nil, snacl.ErrDecryptFailed
+	}
+	if key == nil {
+		key = []byte{}
+	}
+	return key, nil
+}
+
+// encryptValue encrypts a value with a random nonce.
+func (k *dbKeys) encryptValue(value []byte) ([]byte, error) {
+	return k.encKey.Encrypt(value)
+}
+
+// decryptValue decrypts a value encrypted with encryptValue.
+func (k *dbKeys) decryptValue(in []byte) ([]byte, error) {
+	value, err := k.encKey.Decrypt(in)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		value = []byte{}
+	}
+	return value, nil
+}
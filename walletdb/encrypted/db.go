@@ -0,0 +1,859 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package encrypted
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/btcsuite/btcwallet/snacl"
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+var (
+	// ErrNotEncrypted is returned when opening a database which was not
+	// created by this driver.
+	ErrNotEncrypted = errors.New("database is not encrypted")
+
+	// ErrInvalidPassphrase is returned when opening a database, or
+	// changing its passphrase, with the wrong passphrase.
+	ErrInvalidPassphrase = errors.New("invalid database passphrase")
+
+	// ErrCorrupt is returned when the encryption parameters of a database
+	// can not be read.
+	ErrCorrupt = errors.New("corrupt database encryption parameters")
+)
+
+var (
+	// metaNamespaceKey is the key of the namespace of the underlying
+	// database which holds the encryption parameters.  It is the only
+	// namespace stored in plaintext.  The keys of encrypted namespaces
+	// are longer, so they never collide with it.
+	metaNamespaceKey = []byte("walletdbencryption")
+
+	// paramsKey is the key of the marshalled parameters of the key
+	// derived from the passphrase.
+	paramsKey = []byte("params")
+
+	// keysKey is the key of the database keys, encrypted with the key
+	// derived from the passphrase.
+	keysKey = []byte("keys")
+
+	// pendingParamsKey and pendingKeysKey are the keys of the parameters
+	// and the encrypted database keys of the new passphrase while the
+	// passphrase is changed.  The database opens with either passphrase
+	// until the change is committed.
+	pendingParamsKey = []byte("pendingparams")
+	pendingKeysKey   = []byte("pendingkeys")
+)
+
+// newSecretKey derives a key from the passphrase with new parameters.
+func newSecretKey(passphrase *[]byte) (*snacl.SecretKey, error) {
+	return snacl.NewSecretKey(passphrase, snacl.DefaultN, snacl.DefaultR,
+		snacl.DefaultP)
+}
+
+// bucket is an internal type used to represent a collection of key/value pairs
+// and implements the walletdb.Bucket interface.  It encrypts the keys and
+// values written to, and decrypts those read from, a bucket of the underlying
+// database.
+type bucket struct {
+	inner walletdb.Bucket
+	keys  *dbKeys
+}
+
+// Enforce bucket implements the walletdb.Bucket interface.
+var _ walletdb.Bucket = (*bucket)(nil)
+
+// Bucket retrieves a nested bucket with the given key.  Returns nil if
+// the bucket does not exist.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) Bucket(key []byte) walletdb.Bucket {
+	inner := b.inner.Bucket(b.keys.encryptKey(key))
+	if inner == nil {
+		return nil
+	}
+	return &bucket{inner: inner, keys: b.keys}
+}
+
+// CreateBucket creates and returns a new nested bucket with the given key.
+// Returns ErrBucketExists if the bucket already exists, ErrBucketNameRequired
+// if the key is empty, or ErrIncompatibleValue if the key value is otherwise
+// invalid.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) CreateBucket(key []byte) (walletdb.Bucket, error) {
+	// Empty keys are passed unencrypted so the underlying database
+	// returns its error for them.
+	if len(key) != 0 {
+		key = b.keys.encryptKey(key)
+	}
+	inner, err := b.inner.CreateBucket(key)
+	if err != nil {
+		return nil, err
+	}
+	return &bucket{inner: inner, keys: b.keys}, nil
+}
+
+// CreateBucketIfNotExists creates and returns a new nested bucket with the
+// given key if it does not already exist.  Returns ErrBucketNameRequired if the
+// key is empty or ErrIncompatibleValue if the key value is otherwise invalid.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) CreateBucketIfNotExists(key []byte) (walletdb.Bucket, error) {
+	if len(key) != 0 {
+		key = b.keys.encryptKey(key)
+	}
+	inner, err := b.inner.CreateBucketIfNotExists(key)
+	if err != nil {
+		return nil, err
+	}
+	return &bucket{inner: inner, keys: b.keys}, nil
+}
+
+// DeleteBucket removes a nested bucket with the given key.  Returns
+// ErrTxNotWritable if attempted against a read-only transaction and
+// ErrBucketNotFound if the specified bucket does not exist.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) DeleteBucket(key []byte) error {
+	if len(key) != 0 {
+		key = b.keys.encryptKey(key)
+	}
+	return b.inner.DeleteBucket(key)
+}
+
+// ForEach invokes the passed function with every key/value pair in the bucket.
+// This includes nested buckets, in which case the value is nil, but it does not
+// include the key/value pairs within those nested buckets.
+//
+// Since keys are encrypted, the pairs are decrypted and sorted before the
+// function is first invoked.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) ForEach(fn func(k, v []byte) error) error {
+	c := &cursor{bucket: b}
+	if err := c.load(); err != nil {
+		return err
+	}
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		if err := fn(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Writable returns whether or not the bucket is writable.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) Writable() bool {
+	return b.inner.Writable()
+}
+
+// Put saves the specified key/value pair to the bucket.  Keys that do not
+// already exist are added and keys that already exist are overwritten.  Returns
+// ErrTxNotWritable if attempted against a read-only transaction.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) Put(key, value []byte) error {
+	// Empty keys and writes to read-only transactions are passed to the
+	// underlying database unencrypted so it returns its error for them.
+	if len(key) == 0 || !b.inner.Writable() {
+		return b.inner.Put(key, value)
+	}
+	encValue, err := b.keys.encryptValue(value)
+	if err != nil {
+		return err
+	}
+	return b.inner.Put(b.keys.encryptKey(key), encValue)
+}
+
+// Get returns the value for the given key.  Returns nil if the key does
+// not exist in this bucket (or nested buckets).  Nil is also returned when the
+// value can not be decrypted, which only happens when the underlying database
+// was modified without the database keys.
+//
+// NOTE: The value returned by this function is only valid during a
+// transaction and must not be modified.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) Get(key []byte) []byte {
+	encValue := b.inner.Get(b.keys.encryptKey(key))
+	if encValue == nil {
+		return nil
+	}
+	value, err := b.keys.decryptValue(encValue)
+	if err != nil {
+		return nil
+	}
+	return value
+}
+
+// Delete removes the specified key from the bucket.  Deleting a key that does
+// not exist does not return an error.  Returns ErrTxNotWritable if attempted
+// against a read-only transaction.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) Delete(key []byte) error {
+	if len(key) != 0 {
+		key = b.keys.encryptKey(key)
+	}
+	return b.inner.Delete(key)
+}
+
+// Cursor returns a new cursor, allowing for iteration over the bucket's
+// key/value pairs and nested buckets in forward or backward order.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) Cursor() walletdb.Cursor {
+	return &cursor{bucket: b}
+}
+
+// cursorKey is a decrypted key of a cursor's bucket and its encryption.
+type cursorKey struct {
+	key    []byte
+	encKey []byte
+}
+
+// cursor represents a cursor over key/value pairs and nested buckets of a
+// bucket.
+//
+// The encrypted keys of the underlying bucket are not in the order of their
+// plaintext, so the cursor decrypts and sorts all keys of the bucket when it is
+// first positioned.  Keys added to the bucket after that are not visited by the
+// cursor, and keys deleted after that are skipped.
+type cursor struct {
+	bucket *bucket
+	keys   []cursorKey // Sorted by key; nil until loaded.
+	loaded bool
+	index  int // Index of the current key; -1 when not positioned.
+
+	// deleted is set when the current key was deleted, leaving the
+	// cursor between the keys at index and index+1.
+	deleted bool
+}
+
+// Enforce cursor implements the walletdb.Cursor interface.
+var _ walletdb.Cursor = (*cursor)(nil)
+
+// load decrypts and sorts the keys of the bucket.
+func (c *cursor) load() error {
+	if c.loaded {
+		return nil
+	}
+	inner := c.bucket.inner.Cursor()
+	for k, _ := inner.First(); k != nil; k, _ = inner.Next() {
+		key, err := c.bucket.keys.decryptKey(k)
+		if err != nil {
+			return err
+		}
+		encKey := make([]byte, len(k))
+		copy(encKey, k)
+		c.keys = append(c.keys, cursorKey{key: key, encKey: encKey})
+	}
+	sort.Slice(c.keys, func(i, j int) bool {
+		return bytes.Compare(c.keys[i].key, c.keys[j].key) < 0
+	})
+	c.loaded = true
+	c.index = -1
+	return nil
+}
+
+// at positions the cursor at the first key starting at an index, moving in the
+// direction of step, which still exists in the bucket, and returns the pair.
+// Nil is returned when there is no such key.
+func (c *cursor) at(i, step int) (key, value []byte) {
+	if err := c.load(); err != nil {
+		return nil, nil
+	}
+	for ; i >= 0 && i < len(c.keys); i += step {
+		k := c.keys[i]
+		encValue := c.bucket.inner.Get(k.encKey)
+		if encValue != nil {
+			value, err := c.bucket.keys.decryptValue(encValue)
+			if err != nil {
+				continue
+			}
+			c.index, c.deleted = i, false
+			return k.key, value
+		}
+		if c.bucket.inner.Bucket(k.encKey) != nil {
+			c.index, c.deleted = i, false
+			return k.key, nil
+		}
+	}
+	return nil, nil
+}
+
+// Bucket returns the bucket the cursor was created for.
+//
+// This function is part of the walletdb.Cursor interface implementation.
+func (c *cursor) Bucket() walletdb.Bucket {
+	return c.bucket
+}
+
+// Delete removes the current key/value pair the cursor is at without
+// invalidating the cursor. Returns ErrTxNotWritable if attempted on a read-only
+// transaction, or ErrIncompatibleValue if attempted when the cursor points to a
+// nested bucket.
+//
+// This function is part of the walletdb.Cursor interface implementation.
+func (c *cursor) Delete() error {
+	if !c.bucket.Writable() {
+		return walletdb.ErrTxNotWritable
+	}
+	if !c.loaded || c.index < 0 || c.deleted {
+		return nil
+	}
+	err := c.bucket.inner.Delete(c.keys[c.index].encKey)
+	if err != nil {
+		return err
+	}
+
+	// Remove the key, leaving the cursor between the previous and next
+	// keys.
+	c.keys = append(c.keys[:c.index], c.keys[c.index+1:]...)
+	c.index--
+	c.deleted = true
+	return nil
+}
+
+// First positions the cursor at the first key/value pair and returns the pair.
+//
+// This function is part of the walletdb.Cursor interface implementation.
+func (c *cursor) First() (key, value []byte) {
+	return c.at(0, 1)
+}
+
+// Last positions the cursor at the last key/value pair and returns the pair.
+//
+// This function is part of the walletdb.Cursor interface implementation.
+func (c *cursor) Last() (key, value []byte) {
+	if err := c.load(); err != nil {
+		return nil, nil
+	}
+	return c.at(len(c.keys)-1, -1)
+}
+
+// Next moves the cursor one key/value pair forward and returns the new pair.
+//
+// This function is part of the walletdb.Cursor interface implementation.
+func (c *cursor) Next() (key, value []byte) {
+	if !c.loaded {
+		return c.First()
+	}
+	return c.at(c.index+1, 1)
+}
+
+// Prev moves the cursor one key/value pair backward and returns the new pair.
+//
+// This function is part of the walletdb.Cursor interface implementation.
+func (c *cursor) Prev() (key, value []byte) {
+	if !c.loaded || (c.index < 0 && !c.deleted) {
+		return c.Last()
+	}
+	if c.deleted {
+		return c.at(c.index, -1)
+	}
+	return c.at(c.index-1, -1)
+}
+
+// Seek positions the cursor at the passed seek key. If the key does not exist,
+// the cursor is moved to the next key after seek. Returns the new pair.
+//
+// This function is part of the walletdb.Cursor interface implementation.
+func (c *cursor) Seek(seek []byte) (key, value []byte) {
+	if err := c.load(); err != nil {
+		return nil, nil
+	}
+	i := sort.Search(len(c.keys), func(i int) bool {
+		return bytes.Compare(c.keys[i].key, seek) >= 0
+	})
+	return c.at(i, 1)
+}
+
+// transaction represents a database transaction.  It can either by read-only or
+// read-write and implements the walletdb.Tx interface.  The transaction
+// provides a root bucket against which all read and writes occur.
+type transaction struct {
+	inner walletdb.Tx
+	keys  *dbKeys
+}
+
+// Enforce transaction implements the walletdb.Tx interface.
+var _ walletdb.Tx = (*transaction)(nil)
+
+// RootBucket returns the top-most bucket for the namespace the transaction was
+// created from.
+//
+// This function is part of the walletdb.Tx interface implementation.
+func (tx *transaction) RootBucket() walletdb.Bucket {
+	return &bucket{inner: tx.inner.RootBucket(), keys: tx.keys}
+}
+
+// Commit commits all changes that have been made through the root bucket and
+// all of its sub-buckets to persistent storage.
+//
+// This function is part of the walletdb.Tx interface implementation.
+func (tx *transaction) Commit() error {
+	return tx.inner.Commit()
+}
+
+// Rollback undoes all changes that have been made to the root bucket and all of
+// its sub-buckets.
+//
+// This function is part of the walletdb.Tx interface implementation.
+func (tx *transaction) Rollback() error {
+	return tx.inner.Rollback()
+}
+
+// namespace represents a database namespace that is inteded to support the
+// concept of a single entity that controls the opening, creating, and closing
+// of a database while providing other entities their own namespace to work in.
+// It implements the walletdb.Namespace interface.
+type namespace struct {
+	inner walletdb.Namespace
+	keys  *dbKeys
+}
+
+// Enforce namespace implements the walletdb.Namespace interface.
+var _ walletdb.Namespace = (*namespace)(nil)
+
+// Begin starts a transaction which is either read-only or read-write depending
+// on the specified flag.  The transaction has the concurrency of the underlying
+// database.
+//
+// NOTE: The transaction must be closed by calling Rollback or Commit on it when
+// it is no longer needed.
+//
+// This function is part of the walletdb.Namespace interface implementation.
+func (ns *namespace) Begin(writable bool) (walletdb.Tx, error) {
+	inner, err := ns.inner.Begin(writable)
+	if err != nil {
+		return nil, err
+	}
+	return &transaction{inner: inner, keys: ns.keys}, nil
+}
+
+// View invokes the passed function in the context of a managed read-only
+// transaction.  Any errors returned from the user-supplied function are
+// returned from this function.
+//
+// This function is part of the walletdb.Namespace interface implementation.
+func (ns *namespace) View(fn func(walletdb.Tx) error) error {
+	return ns.inner.View(func(inner walletdb.Tx) error {
+		return fn(&transaction{inner: inner, keys: ns.keys})
+	})
+}
+
+// Update invokes the passed function in the context of a managed read-write
+// transaction.  Any errors returned from the user-supplied function will cause
+// the transaction to be rolled back and are returned from this function.
+// Otherwise, the transaction is commited when the user-supplied function
+// returns a nil error.
+//
+// This function is part of the walletdb.Namespace interface implementation.
+func (ns *namespace) Update(fn func(walletdb.Tx) error) error {
+	return ns.inner.Update(func(inner walletdb.Tx) error {
+		return fn(&transaction{inner: inner, keys: ns.keys})
+	})
+}
+
+// db represents a collection of namespaces which are encrypted and persisted
+// by an underlying database, and implements the walletdb.Db interface.  All
+// database access is performed through transactions which are obtained through
+// the specific Namespace.
+type db struct {
+	inner walletdb.DB
+	keys  *dbKeys
+
+	// mu protects the encryption parameters while the passphrase is
+	// changed.
+	mu sync.Mutex
+
+	// pending is whether the database was opened with the new passphrase
+	// of an interrupted passphrase change.  It is protected by mu.
+	pending bool
+}
+
+// Enforce db implements the walletdb.Db interface.
+var _ walletdb.DB = (*db)(nil)
+
+// Namespace returns a Namespace interface for the provided key.  See the
+// Namespace interface documentation for more details.  Attempting to access a
+// Namespace on a database that is not open yet or has been closed will result
+// in ErrDbNotOpen.  Namespaces are created in the database on first access.
+//
+// This function is part of the walletdb.Db interface implementation.
+func (db *db) Namespace(key []byte) (walletdb.Namespace, error) {
+	if len(key) != 0 {
+		key = db.keys.encryptKey(key)
+	}
+	inner, err := db.inner.Namespace(key)
+	if err != nil {
+		return nil, err
+	}
+	return &namespace{inner: inner, keys: db.keys}, nil
+}
+
+// DeleteNamespace deletes the namespace for the passed key.  ErrBucketNotFound
+// will be returned if the namespace does not exist.
+//
+// This function is part of the walletdb.Db interface implementation.
+func (db *db) DeleteNamespace(key []byte) error {
+	if len(key) != 0 {
+		key = db.keys.encryptKey(key)
+	}
+	return db.inner.DeleteNamespace(key)
+}
+
+// Copy writes a copy of the underlying database to the provided writer.  The
+// copy holds the encrypted data and the encryption parameters, so it may only
+// be opened with the passphrase, by this driver over the driver of the
+// underlying database.
+//
+// This function is part of the walletdb.Db interface implementation.
+func (db *db) Copy(w io.Writer) error {
+	return db.inner.Copy(w)
+}
+
+// Close cleanly shuts down the underlying database and clears the database
+// keys.
+//
+// This function is part of the walletdb.Db interface implementation.
+func (db *db) Close() error {
+	err := db.inner.Close()
+	db.keys.zero()
+	return err
+}
+
+// writeKeys writes the database keys encrypted with a key derived from the
+// passphrase to the metadata namespace.  The parameters of the derived key and
+// the encrypted database keys are put under the passed keys.
+func writeKeys(ns walletdb.Namespace, keys *dbKeys, passphrase []byte,
+	paramsKey, keysKey []byte) error {
+
+	sk, err := newSecretKey(&passphrase)
+	if err != nil {
+		return err
+	}
+	defer sk.Zero()
+
+	serialized := keys.serialize()
+	encKeys, err := sk.Encrypt(serialized)
+	for i := range serialized {
+		serialized[i] = 0
+	}
+	if err != nil {
+		return err
+	}
+
+	return ns.Update(func(tx walletdb.Tx) error {
+		root := tx.RootBucket()
+		if err := root.Put(paramsKey, sk.Marshal()); err != nil {
+			return err
+		}
+		return root.Put(keysKey, encKeys)
+	})
+}
+
+// readKeys reads the database keys from the metadata namespace and decrypts
+// them with the key derived from the passphrase.  The parameters of the derived
+// key and the encrypted database keys are read from the passed keys.  ErrNotEncrypted is returned when no keys are stored.
+func readKeys(ns walletdb.Namespace, passphrase []byte,
+	paramsKey, keysKey []byte) (*dbKeys, error) {
+
+	var params, encKeys []byte
+	err := ns.View(func(tx walletdb.Tx) error {
+		root := tx.RootBucket()
+		params = append([]byte(nil), root.Get(paramsKey)...)
+		encKeys = append([]byte(nil), root.Get(keysKey)...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if params == nil && encKeys == nil {
+		return nil, ErrNotEncrypted
+	}
+
+	var sk snacl.SecretKey
+	if err := sk.Unmarshal(params); err != nil {
+		return nil, ErrCorrupt
+	}
+	defer sk.Zero()
+	switch err := sk.DeriveKey(&passphrase); err {
+	case nil:
+	case snacl.ErrInvalidPassword:
+		return nil, ErrInvalidPassphrase
+	default:
+		return nil, err
+	}
+
+	serialized, err := sk.Decrypt(encKeys)
+	if err != nil {
+		return nil, ErrCorrupt
+	}
+	defer func() {
+		for i := range serialized {
+			serialized[i] = 0
+		}
+	}()
+	return deserializeDBKeys(serialized)
+}
+
+// readAnyKeys reads the database keys with the passphrase, or with the new
+// passphrase of an interrupted passphrase change.  It returns whether the keys
+// were decrypted with the new passphrase.
+func readAnyKeys(ns walletdb.Namespace, passphrase []byte) (*dbKeys, bool, error) {
+	keys, err := readKeys(ns, passphrase, paramsKey, keysKey)
+	if err != ErrInvalidPassphrase {
+		return keys, false, err
+	}
+	keys, err = readKeys(ns, passphrase, pendingParamsKey, pendingKeysKey)
+	switch err {
+	case nil:
+		return keys, true, nil
+	case ErrNotEncrypted:
+		return nil, false, ErrInvalidPassphrase
+	default:
+		return nil, false, err
+	}
+}
+
+// commitPending replaces the keys of the old passphrase with those of the new
+// passphrase when promote is true, and removes the keys of the new passphrase
+// in either case, ending a passphrase change.
+func commitPending(ns walletdb.Namespace, promote bool) error {
+	return ns.Update(func(tx walletdb.Tx) error {
+		root := tx.RootBucket()
+		if promote {
+			params := root.Get(pendingParamsKey)
+			encKeys := root.Get(pendingKeysKey)
+			if params == nil || encKeys == nil {
+				return ErrCorrupt
+			}
+			err := root.Put(paramsKey, params)
+			if err != nil {
+				return err
+			}
+			err = root.Put(keysKey, encKeys)
+			if err != nil {
+				return err
+			}
+		}
+		if root.Get(pendingParamsKey) != nil {
+			err := root.Delete(pendingParamsKey)
+			if err != nil {
+				return err
+			}
+		}
+		if root.Get(pendingKeysKey) != nil {
+			return root.Delete(pendingKeysKey)
+		}
+		return nil
+	})
+}
+
+// createDB creates an underlying database and stores new database keys in it,
+// encrypted with a key derived from the passphrase.
+func createDB(innerType string, passphrase []byte, innerArgs ...interface{}) (walletdb.DB, error) {
+	inner, err := walletdb.Create(innerType, innerArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := generateDBKeys()
+	if err != nil {
+		inner.Close()
+		return nil, err
+	}
+	ns, err := inner.Namespace(metaNamespaceKey)
+	if err == nil {
+		err = writeKeys(ns, keys, passphrase, paramsKey, keysKey)
+	}
+	if err != nil {
+		keys.zero()
+		inner.Close()
+		return nil, err
+	}
+
+	return &db{inner: inner, keys: keys}, nil
+}
+
+// openDB opens an underlying database and decrypts its database keys with a
+// key derived from the passphrase.  The database is also opened with the new
+// passphrase of an interrupted passphrase change, until CommitPassphrase ends
+// the change.
+func openDB(innerType string, passphrase []byte, innerArgs ...interface{}) (walletdb.DB, error) {
+	inner, err := walletdb.Open(innerType, innerArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	ns, err := inner.Namespace(metaNamespaceKey)
+	if err != nil {
+		inner.Close()
+		return nil, err
+	}
+	keys, pending, err := readAnyKeys(ns, passphrase)
+	if err != nil {
+		// Accessing the metadata namespace created it when the
+		// database is not encrypted, so remove it again.
+		if err == ErrNotEncrypted {
+			inner.DeleteNamespace(metaNamespaceKey)
+		}
+		inner.Close()
+		return nil, err
+	}
+
+	return &db{inner: inner, keys: keys, pending: pending}, nil
+}
+
+// IsEncrypted returns whether a database was opened or created by this driver.
+func IsEncrypted(walletDB walletdb.DB) bool {
+	_, ok := walletDB.(*db)
+	return ok
+}
+
+// ChangePassphrase changes the passphrase of a database opened or created by
+// this driver.  The data is not reencrypted, since it is encrypted with keys
+// which are themselves encrypted with a key derived from the passphrase.
+// ErrNotEncrypted is returned for databases of other drivers, and
+// ErrInvalidPassphrase when the old passphrase is wrong.
+//
+// The database keys are first recorded for the new passphrase, and update, if
+// not nil, is then called to change data sharing the passphrase, such as the
+// public passphrase of a wallet.  If update fails, the change is abandoned and
+// its error returned.  The database opens with either passphrase until the
+// change is committed, so a change interrupted by a crash is ended by
+// CommitPassphrase after reopening the database.
+func ChangePassphrase(walletDB walletdb.DB, oldPassphrase, newPassphrase []byte,
+	update func() error) error {
+
+	db, ok := walletDB.(*db)
+	if !ok {
+		return ErrNotEncrypted
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	ns, err := db.inner.Namespace(metaNamespaceKey)
+	if err != nil {
+		return err
+	}
+	keys, pending, err := readAnyKeys(ns, oldPassphrase)
+	if err != nil {
+		return err
+	}
+	keys.zero()
+
+	// The old passphrase must be the only one opening the database before
+	// the keys of the new passphrase are recorded.
+	err = commitPending(ns, pending)
+	if err != nil {
+		return err
+	}
+	db.pending = false
+
+	err = writeKeys(ns, db.keys, newPassphrase, pendingParamsKey,
+		pendingKeysKey)
+	if err != nil {
+		return err
+	}
+	if update != nil {
+		err = update()
+		if err != nil {
+			cerr := commitPending(ns, false)
+			if cerr != nil {
+				return cerr
+			}
+			return err
+		}
+	}
+	return commitPending(ns, true)
+}
+
+// CommitPassphrase ends a passphrase change of a database opened or created by
+// this driver which was interrupted before it was committed.  It must only be
+// called after the passphrase the database was opened with is known to be the
+// passphrase of the data sharing it, such as after a wallet is opened with it.
+// If the database was opened with the new passphrase of the interrupted
+// change, the change is completed, and otherwise it is abandoned.
+// ErrNotEncrypted is returned for databases of other drivers.
+func CommitPassphrase(walletDB walletdb.DB) error {
+	db, ok := walletDB.(*db)
+	if !ok {
+		return ErrNotEncrypted
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	ns, err := db.inner.Namespace(metaNamespaceKey)
+	if err != nil {
+		return err
+	}
+	err = commitPending(ns, db.pending)
+	if err != nil {
+		return err
+	}
+	db.pending = false
+	return nil
+}
+
+// copyBucket copies the key/value pairs and nested buckets of a bucket into
+// another bucket.
+func copyBucket(dst, src walletdb.Bucket) error {
+	return src.ForEach(func(k, v []byte) error {
+		if v != nil {
+			return dst.Put(k, v)
+		}
+		srcChild := src.Bucket(k)
+		if srcChild == nil {
+			// The pair is a nil value rather than a bucket.
+			return dst.Put(k, v)
+		}
+		dstChild, err := dst.CreateBucketIfNotExists(k)
+		if err != nil {
+			return err
+		}
+		return copyBucket(dstChild, srcChild)
+	})
+}
+
+// Migrate copies the namespaces with the passed keys from a database to
+// another, which is used to encrypt an existing database by copying it to a
+// database created by this driver.  Each namespace is copied in a single
+// transaction.  Namespaces which do not exist in the source database are
+// created empty in the destination database.
+func Migrate(dst, src walletdb.DB, namespaceKeys ...[]byte) error {
+	for _, key := range namespaceKeys {
+		srcNS, err := src.Namespace(key)
+		if err != nil {
+			return err
+		}
+		dstNS, err := dst.Namespace(key)
+		if err != nil {
+			return err
+		}
+		err = srcNS.View(func(srcTx walletdb.Tx) error {
+			return dstNS.Update(func(dstTx walletdb.Tx) error {
+				return copyBucket(dstTx.RootBucket(),
+					srcTx.RootBucket())
+			})
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package encrypted implements an instance of walletdb that encrypts the keys and
+values of another walletdb driver, so that no wallet data other than the size
+and number of entries can be read from the database file without the
+passphrase.
+
+Usage
+
+This package is only a driver to the walletdb package and provides the database
+type of "encrypted".  The Open and Create functions take the type of the
+underlying database, the passphrase as a []byte, and the parameters of the
+underlying database:
+
+	db, err := walletdb.Open("encrypted", "bdb", passphrase, "path/to/database.db")
+	if err != nil {
+		// Handle error
+	}
+
+	db, err := walletdb.Create("encrypted", "bdb", passphrase, "path/to/database.db")
+	if err != nil {
+		// Handle error
+	}
+
+Opening a database which was not created by this driver returns
+ErrNotEncrypted, and opening it with the wrong passphrase returns
+ErrInvalidPassphrase.  The Migrate function copies the namespaces of an
+existing database into an encrypted one, and ChangePassphrase changes the
+passphrase of an open encrypted database.  Data sharing the passphrase may be
+changed by a function passed to ChangePassphrase, while the database opens with
+both passphrases.  If the change is interrupted, CommitPassphrase ends it once
+the database is reopened with the passphrase of that data.
+
+Encryption
+
+Keys and values are encrypted with XSalsa20 and Poly1305 using random database
+keys, which are stored in a plaintext namespace of the underlying database
+encrypted with a key derived from the passphrase with scrypt.  Values are
+encrypted with random nonces.  Keys, including namespace and bucket names, are
+encrypted with nonces derived from the keys with HMAC-SHA256, so that a key can
+be found by encrypting it again.  Since the encrypted keys are not ordered by
+their plaintext, cursors decrypt and sort the keys of a bucket when they are
+first positioned.
+
+The Copy method copies the underlying database, so backups remain encrypted and
+are opened with the same passphrase.
+*/
+package encrypted
@@ -0,0 +1,79 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package encrypted
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+const (
+	dbType = "encrypted"
+)
+
+// parseArgs parses the arguments from the walletdb Open/Create methods.
+func parseArgs(funcName string, args ...interface{}) (string, []byte, []interface{}, error) {
+	if len(args) < 2 {
+		return "", nil, nil, fmt.Errorf("invalid arguments to %s.%s -- "+
+			"expected underlying database type, passphrase and "+
+			"underlying database arguments", dbType, funcName)
+	}
+
+	innerType, ok := args[0].(string)
+	if !ok {
+		return "", nil, nil, fmt.Errorf("first argument to %s.%s is "+
+			"invalid -- expected underlying database type string",
+			dbType, funcName)
+	}
+	if innerType == dbType {
+		return "", nil, nil, fmt.Errorf("first argument to %s.%s is "+
+			"invalid -- underlying database may not be %s", dbType,
+			funcName, dbType)
+	}
+
+	passphrase, ok := args[1].([]byte)
+	if !ok {
+		return "", nil, nil, fmt.Errorf("second argument to %s.%s is "+
+			"invalid -- expected passphrase []byte", dbType, funcName)
+	}
+
+	return innerType, passphrase, args[2:], nil
+}
+
+// openDBDriver is the callback provided during driver registration that opens
+// an existing database for use.
+func openDBDriver(args ...interface{}) (walletdb.DB, error) {
+	innerType, passphrase, innerArgs, err := parseArgs("Open", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return openDB(innerType, passphrase, innerArgs...)
+}
+
+// createDBDriver is the callback provided during driver registration that
+// creates, initializes, and opens a database for use.
+func createDBDriver(args ...interface{}) (walletdb.DB, error) {
+	innerType, passphrase, innerArgs, err := parseArgs("Create", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return createDB(innerType, passphrase, innerArgs...)
+}
+
+func init() {
+	// Register the driver.
+	driver := walletdb.Driver{
+		DbType: dbType,
+		Create: createDBDriver,
+		Open:   openDBDriver,
+	}
+	if err := walletdb.RegisterDriver(driver); err != nil {
+		panic(fmt.Sprintf("Failed to regiser database driver '%s': %v",
+			dbType, err))
+	}
+}
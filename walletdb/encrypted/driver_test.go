@@ -0,0 +1,454 @@
+// Copyright (c) 2014-2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package encrypted_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+	_ "github.com/btcsuite/btcwallet/walletdb/bdb"
+	"github.com/btcsuite/btcwallet/walletdb/encrypted"
+)
+
+// dbType is the database type name for this driver.
+const dbType = "encrypted"
+
+// passphrase is the passphrase of the test databases.
+var passphrase = []byte("passphrase")
+
+// TestCreateOpenFail ensures that errors related to creating and opening a
+// database are handled properly.
+func TestCreateOpenFail(t *testing.T) {
+	// Ensure that attempting to open a database that doesn't exist returns
+	// the error of the underlying driver.
+	wantErr := walletdb.ErrDbDoesNotExist
+	if _, err := walletdb.Open(dbType, "bdb", passphrase, "noexist.db"); err != wantErr {
+		t.Errorf("Open: did not receive expected error - got %v, "+
+			"want %v", err, wantErr)
+		return
+	}
+
+	// Ensure that attempting to open a database with the wrong number of
+	// parameters returns the expected error.
+	wantErr = fmt.Errorf("invalid arguments to %s.Open -- expected "+
+		"underlying database type, passphrase and underlying database "+
+		"arguments", dbType)
+	if _, err := walletdb.Open(dbType, "bdb"); err.Error() != wantErr.Error() {
+		t.Errorf("Open: did not receive expected error - got %v, "+
+			"want %v", err, wantErr)
+		return
+	}
+
+	// Ensure that attempting to create a database with an invalid type for
+	// the first parameter returns the expected error.
+	wantErr = fmt.Errorf("first argument to %s.Create is invalid -- "+
+		"expected underlying database type string", dbType)
+	if _, err := walletdb.Create(dbType, 1, passphrase); err.Error() != wantErr.Error() {
+		t.Errorf("Create: did not receive expected error - got %v, "+
+			"want %v", err, wantErr)
+		return
+	}
+
+	// Ensure that attempting to create a database with an invalid type for
+	// the second parameter returns the expected error.
+	wantErr = fmt.Errorf("second argument to %s.Create is invalid -- "+
+		"expected passphrase []byte", dbType)
+	if _, err := walletdb.Create(dbType, "bdb", "passphrase"); err.Error() != wantErr.Error() {
+		t.Errorf("Create: did not receive expected error - got %v, "+
+			"want %v", err, wantErr)
+		return
+	}
+
+	// Ensure that opening an unencrypted database, or an encrypted
+	// database with the wrong passphrase, returns the expected error.
+	dbPath := "createfail.db"
+	db, err := walletdb.Create("bdb", dbPath)
+	if err != nil {
+		t.Errorf("Create: unexpected error: %v", err)
+		return
+	}
+	db.Close()
+	wantErr = encrypted.ErrNotEncrypted
+	if _, err := walletdb.Open(dbType, "bdb", passphrase, dbPath); err != wantErr {
+		t.Errorf("Open: did not receive expected error - got %v, "+
+			"want %v", err, wantErr)
+	}
+	os.Remove(dbPath)
+
+	db, err = walletdb.Create(dbType, "bdb", passphrase, dbPath)
+	if err != nil {
+		t.Errorf("Create: unexpected error: %v", err)
+		return
+	}
+	defer os.Remove(dbPath)
+	db.Close()
+	wantErr = encrypted.ErrInvalidPassphrase
+	if _, err := walletdb.Open(dbType, "bdb", []byte("wrong"), dbPath); err != wantErr {
+		t.Errorf("Open: did not receive expected error - got %v, "+
+			"want %v", err, wantErr)
+		return
+	}
+
+	// Ensure operations against a closed database return the expected
+	// error.
+	wantErr = walletdb.ErrDbNotOpen
+	if _, err := db.Namespace([]byte("ns1")); err != wantErr {
+		t.Errorf("Namespace: did not receive expected error - got %v, "+
+			"want %v", err, wantErr)
+		return
+	}
+}
+
+// TestInterface performs all interfaces tests for this database driver.
+func TestInterface(t *testing.T) {
+	// Create a new database to run tests against.
+	dbPath := "interfacetest.db"
+	db, err := walletdb.Create(dbType, "bdb", passphrase, dbPath)
+	if err != nil {
+		t.Errorf("Failed to create test database (%s) %v", dbType, err)
+		return
+	}
+	defer os.Remove(dbPath)
+	defer db.Close()
+
+	// Run all of the interface tests against the database.
+	testInterface(t, db)
+}
+
+// TestCursor ensures cursors iterate the pairs and nested buckets of a bucket
+// in the order of their decrypted keys.
+func TestCursor(t *testing.T) {
+	dbPath := "cursortest.db"
+	db, err := walletdb.Create(dbType, "bdb", passphrase, dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create test database (%s) %v", dbType, err)
+	}
+	defer os.Remove(dbPath)
+	defer db.Close()
+
+	ns, err := db.Namespace([]byte("ns1"))
+	if err != nil {
+		t.Fatalf("Namespace: unexpected error: %v", err)
+	}
+	err = ns.Update(func(tx walletdb.Tx) error {
+		root := tx.RootBucket()
+		for _, k := range []string{"c", "a", "e"} {
+			if err := root.Put([]byte(k), []byte("v"+k)); err != nil {
+				return err
+			}
+		}
+		nested, err := root.CreateBucket([]byte("b"))
+		if err != nil {
+			return err
+		}
+		if err := nested.Put([]byte("nested"), []byte("x")); err != nil {
+			return err
+		}
+		if err := root.Put([]byte("empty"), nil); err != nil {
+			return err
+		}
+
+		var keys []string
+		c := root.Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			if string(k) == "b" && v != nil {
+				return fmt.Errorf("nested bucket has value %q", v)
+			}
+			keys = append(keys, string(k))
+		}
+		want := []string{"a", "b", "c", "e", "empty"}
+		if !reflect.DeepEqual(keys, want) {
+			return fmt.Errorf("cursor keys %q, want %q", keys, want)
+		}
+
+		if k, v := c.Seek([]byte("d")); string(k) != "e" || string(v) != "ve" {
+			return fmt.Errorf("Seek: got %q %q, want e ve", k, v)
+		}
+		if k, _ := c.Prev(); string(k) != "c" {
+			return fmt.Errorf("Prev: got %q, want c", k)
+		}
+		if err := c.Delete(); err != nil {
+			return fmt.Errorf("Delete: unexpected error: %v", err)
+		}
+		if k, _ := c.Next(); string(k) != "e" {
+			return fmt.Errorf("Next after Delete: got %q, want e", k)
+		}
+		if k, v := c.Last(); string(k) != "empty" || v == nil || len(v) != 0 {
+			return fmt.Errorf("Last: got %q %q, want empty value", k, v)
+		}
+		c.Seek([]byte("b"))
+		if err := c.Delete(); err != walletdb.ErrIncompatibleValue {
+			return fmt.Errorf("Delete bucket: unexpected error: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestEncryption ensures no keys or values are written to the underlying
+// database in plaintext, that copies are encrypted, and that the passphrase
+// may be changed.
+func TestEncryption(t *testing.T) {
+	dbPath := "encryptiontest.db"
+	db, err := walletdb.Create(dbType, "bdb", passphrase, dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create test database (%s) %v", dbType, err)
+	}
+	defer os.Remove(dbPath)
+
+	secrets := [][]byte{
+		[]byte("secretnamespace"),
+		[]byte("secretbucket"),
+		[]byte("secretkey"),
+		[]byte("secretvalue"),
+	}
+	ns, err := db.Namespace(secrets[0])
+	if err != nil {
+		t.Fatalf("Namespace: unexpected error: %v", err)
+	}
+	err = ns.Update(func(tx walletdb.Tx) error {
+		nested, err := tx.RootBucket().CreateBucket(secrets[1])
+		if err != nil {
+			return err
+		}
+		return nested.Put(secrets[2], secrets[3])
+	})
+	if err != nil {
+		t.Fatalf("Update: unexpected error: %v", err)
+	}
+
+	var backup bytes.Buffer
+	if err := db.Copy(&backup); err != nil {
+		t.Fatalf("Copy: unexpected error: %v", err)
+	}
+	newPassphrase := []byte("new passphrase")
+	err = encrypted.ChangePassphrase(db, []byte("wrong"), newPassphrase, nil)
+	if err != encrypted.ErrInvalidPassphrase {
+		t.Errorf("ChangePassphrase: did not receive expected error - "+
+			"got %v, want %v", err, encrypted.ErrInvalidPassphrase)
+	}
+	err = encrypted.ChangePassphrase(db, passphrase, newPassphrase, nil)
+	if err != nil {
+		t.Fatalf("ChangePassphrase: unexpected error: %v", err)
+	}
+	db.Close()
+
+	contents, err := ioutil.ReadFile(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range secrets {
+		if bytes.Contains(contents, s) {
+			t.Errorf("database contains plaintext %q", s)
+		}
+		if bytes.Contains(backup.Bytes(), s) {
+			t.Errorf("copy contains plaintext %q", s)
+		}
+	}
+
+	// Ensure the data is read with the new passphrase, and the copy with
+	// the old one.
+	backupPath := "encryptiontest-copy.db"
+	if err := ioutil.WriteFile(backupPath, backup.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(backupPath)
+	for _, test := range []struct {
+		path       string
+		passphrase []byte
+	}{
+		{dbPath, newPassphrase},
+		{backupPath, passphrase},
+	} {
+		db, err := walletdb.Open(dbType, "bdb", test.passphrase, test.path)
+		if err != nil {
+			t.Fatalf("Open %s: unexpected error: %v", test.path, err)
+		}
+		ns, err := db.Namespace(secrets[0])
+		if err != nil {
+			t.Fatalf("Namespace: unexpected error: %v", err)
+		}
+		err = ns.View(func(tx walletdb.Tx) error {
+			nested := tx.RootBucket().Bucket(secrets[1])
+			if nested == nil {
+				return fmt.Errorf("bucket does not exist")
+			}
+			if v := nested.Get(secrets[2]); !bytes.Equal(v, secrets[3]) {
+				return fmt.Errorf("value %q, want %q", v, secrets[3])
+			}
+			return nil
+		})
+		db.Close()
+		if err != nil {
+			t.Errorf("%s: %v", test.path, err)
+		}
+	}
+}
+
+// TestMigrate ensures the namespaces of an unencrypted database are copied to
+// an encrypted database.
+func TestMigrate(t *testing.T) {
+	srcPath := "migratetest-src.db"
+	src, err := walletdb.Create("bdb", srcPath)
+	if err != nil {
+		t.Fatalf("Create: unexpected error: %v", err)
+	}
+	defer os.Remove(srcPath)
+	defer src.Close()
+	dstPath := "migratetest-dst.db"
+	dst, err := walletdb.Create(dbType, "bdb", passphrase, dstPath)
+	if err != nil {
+		t.Fatalf("Create: unexpected error: %v", err)
+	}
+	defer os.Remove(dstPath)
+	defer dst.Close()
+
+	ns, err := src.Namespace([]byte("ns1"))
+	if err != nil {
+		t.Fatalf("Namespace: unexpected error: %v", err)
+	}
+	err = ns.Update(func(tx walletdb.Tx) error {
+		root := tx.RootBucket()
+		if err := root.Put([]byte("key"), []byte("value")); err != nil {
+			return err
+		}
+		nested, err := root.CreateBucket([]byte("bucket"))
+		if err != nil {
+			return err
+		}
+		if _, err := nested.CreateBucket([]byte("empty")); err != nil {
+			return err
+		}
+		return nested.Put([]byte("nested"), []byte("nested value"))
+	})
+	if err != nil {
+		t.Fatalf("Update: unexpected error: %v", err)
+	}
+
+	err = encrypted.Migrate(dst, src, []byte("ns1"))
+	if err != nil {
+		t.Fatalf("Migrate: unexpected error: %v", err)
+	}
+
+	ns, err = dst.Namespace([]byte("ns1"))
+	if err != nil {
+		t.Fatalf("Namespace: unexpected error: %v", err)
+	}
+	err = ns.View(func(tx walletdb.Tx) error {
+		root := tx.RootBucket()
+		if v := root.Get([]byte("key")); string(v) != "value" {
+			return fmt.Errorf("value %q, want value", v)
+		}
+		nested := root.Bucket([]byte("bucket"))
+		if nested == nil {
+			return fmt.Errorf("bucket does not exist")
+		}
+		if nested.Bucket([]byte("empty")) == nil {
+			return fmt.Errorf("empty bucket does not exist")
+		}
+		if v := nested.Get([]byte("nested")); string(v) != "nested value" {
+			return fmt.Errorf("nested value %q, want nested value", v)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// checkOpen ensures the database at a path is opened with a passphrase only
+// when wantOpen is true.
+func checkOpen(t *testing.T, path string, pass []byte, wantOpen bool) {
+	db, err := walletdb.Open(dbType, "bdb", pass, path)
+	switch {
+	case wantOpen && err != nil:
+		t.Fatalf("Open %s with %q: unexpected error: %v", path, pass, err)
+	case !wantOpen && err != encrypted.ErrInvalidPassphrase:
+		t.Fatalf("Open %s with %q: did not receive expected error - "+
+			"got %v, want %v", path, pass, err,
+			encrypted.ErrInvalidPassphrase)
+	}
+	if err == nil {
+		db.Close()
+	}
+}
+
+// TestInterruptedChangePassphrase ensures a failed passphrase change keeps the
+// old passphrase, and a change interrupted before it is committed opens with
+// both passphrases until CommitPassphrase ends it.
+func TestInterruptedChangePassphrase(t *testing.T) {
+	dbPath := "changepassphrasetest.db"
+	db, err := walletdb.Create(dbType, "bdb", passphrase, dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create test database (%s) %v", dbType, err)
+	}
+	defer os.Remove(dbPath)
+
+	newPassphrase := []byte("new passphrase")
+	updateErr := fmt.Errorf("update failed")
+	err = encrypted.ChangePassphrase(db, passphrase, newPassphrase,
+		func() error { return updateErr })
+	if err != updateErr {
+		t.Errorf("ChangePassphrase: did not receive expected error - "+
+			"got %v, want %v", err, updateErr)
+	}
+	db.Close()
+	checkOpen(t, dbPath, passphrase, true)
+	checkOpen(t, dbPath, newPassphrase, false)
+
+	// Copy the database while the change is made, as if the change was
+	// interrupted by a crash.
+	db, err = walletdb.Open(dbType, "bdb", passphrase, dbPath)
+	if err != nil {
+		t.Fatalf("Open: unexpected error: %v", err)
+	}
+	var interrupted bytes.Buffer
+	err = encrypted.ChangePassphrase(db, passphrase, newPassphrase,
+		func() error { return db.Copy(&interrupted) })
+	if err != nil {
+		t.Fatalf("ChangePassphrase: unexpected error: %v", err)
+	}
+	db.Close()
+	checkOpen(t, dbPath, passphrase, false)
+	checkOpen(t, dbPath, newPassphrase, true)
+
+	// The interrupted change is completed when the database is committed
+	// after opening it with the new passphrase, and abandoned when it is
+	// committed after opening it with the old one.
+	for _, test := range []struct {
+		path              string
+		commit, abandoned []byte
+	}{
+		{"changepassphrasetest-new.db", newPassphrase, passphrase},
+		{"changepassphrasetest-old.db", passphrase, newPassphrase},
+	} {
+		err := ioutil.WriteFile(test.path, interrupted.Bytes(), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.Remove(test.path)
+		checkOpen(t, test.path, passphrase, true)
+		checkOpen(t, test.path, newPassphrase, true)
+
+		db, err := walletdb.Open(dbType, "bdb", test.commit, test.path)
+		if err != nil {
+			t.Fatalf("Open: unexpected error: %v", err)
+		}
+		err = encrypted.CommitPassphrase(db)
+		db.Close()
+		if err != nil {
+			t.Fatalf("CommitPassphrase: unexpected error: %v", err)
+		}
+		checkOpen(t, test.path, test.commit, true)
+		checkOpen(t, test.path, test.abandoned, false)
+	}
+}
@@ -0,0 +1,839 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package memdb
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/boltdb/bolt"
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+const (
+	// maxKeySize and maxValueSize are the largest keys and values which
+	// may be stored.  They match the limits of bolt so that data can be
+	// copied to a bolt database.
+	maxKeySize   = 32768
+	maxValueSize = (1 << 31) - 2
+)
+
+// node holds the key/value pairs and nested buckets of a bucket.  Nodes which
+// have been committed are never modified, so they may be shared between
+// transactions.
+type node struct {
+	keys    []string // Sorted keys of both pairs and nested buckets.
+	values  map[string][]byte
+	buckets map[string]*node
+}
+
+// newNode returns an empty node.
+func newNode() *node {
+	return &node{
+		values:  make(map[string][]byte),
+		buckets: make(map[string]*node),
+	}
+}
+
+// clone returns a copy of the node which may be modified without modifying n.
+// The values and nested buckets are shared, since they are copied themselves
+// before being modified.
+func (n *node) clone() *node {
+	c := &node{
+		keys:    make([]string, len(n.keys)),
+		values:  make(map[string][]byte, len(n.values)),
+		buckets: make(map[string]*node, len(n.buckets)),
+	}
+	copy(c.keys, n.keys)
+	for k, v := range n.values {
+		c.values[k] = v
+	}
+	for k, b := range n.buckets {
+		c.buckets[k] = b
+	}
+	return c
+}
+
+// insertKey adds a key to the sorted keys of the node.
+func (n *node) insertKey(key string) {
+	i := sort.SearchStrings(n.keys, key)
+	n.keys = append(n.keys, "")
+	copy(n.keys[i+1:], n.keys[i:])
+	n.keys[i] = key
+}
+
+// removeKey removes a key from the sorted keys of the node.
+func (n *node) removeKey(key string) {
+	i := sort.SearchStrings(n.keys, key)
+	if i < len(n.keys) && n.keys[i] == key {
+		n.keys = append(n.keys[:i], n.keys[i+1:]...)
+	}
+}
+
+// bucket is an internal type used to represent a collection of key/value pairs
+// and implements the walletdb.Bucket interface.
+//
+// A bucket is identified by its parent and name rather than by its node, since
+// the node of a bucket is replaced by a copy when the bucket is first modified
+// by a transaction.
+type bucket struct {
+	tx     *transaction
+	parent *bucket // Nil for the root of the database.
+	name   string
+}
+
+// Enforce bucket implements the walletdb.Bucket interface.
+var _ walletdb.Bucket = (*bucket)(nil)
+
+// node returns the current node of the bucket, or nil when the bucket has been
+// deleted.
+func (b *bucket) node() *node {
+	if b.parent == nil {
+		return b.tx.root
+	}
+	p := b.parent.node()
+	if p == nil {
+		return nil
+	}
+	return p.buckets[b.name]
+}
+
+// mutableNode returns the node of the bucket owned by the transaction, copying
+// the node and the nodes of its parents when they are not yet owned.  Nil is
+// returned when the bucket has been deleted.
+func (b *bucket) mutableNode() *node {
+	tx := b.tx
+	if b.parent == nil {
+		if _, ok := tx.owned[tx.root]; !ok {
+			tx.root = tx.root.clone()
+			tx.owned[tx.root] = struct{}{}
+		}
+		return tx.root
+	}
+	p := b.parent.mutableNode()
+	if p == nil {
+		return nil
+	}
+	n := p.buckets[b.name]
+	if n == nil {
+		return nil
+	}
+	if _, ok := tx.owned[n]; !ok {
+		n = n.clone()
+		p.buckets[b.name] = n
+		tx.owned[n] = struct{}{}
+	}
+	return n
+}
+
+// checkWritable returns the error of writing to the bucket when its
+// transaction is closed or read-only.
+func (b *bucket) checkWritable() error {
+	if b.tx.closed {
+		return walletdb.ErrTxClosed
+	}
+	if !b.tx.writable {
+		return walletdb.ErrTxNotWritable
+	}
+	return nil
+}
+
+// Bucket retrieves a nested bucket with the given key.  Returns nil if
+// the bucket does not exist.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) Bucket(key []byte) walletdb.Bucket {
+	if b.tx.closed {
+		return nil
+	}
+	n := b.node()
+	if n == nil || n.buckets[string(key)] == nil {
+		return nil
+	}
+	return &bucket{tx: b.tx, parent: b, name: string(key)}
+}
+
+// CreateBucket creates and returns a new nested bucket with the given key.
+// Returns ErrBucketExists if the bucket already exists, ErrBucketNameRequired
+// if the key is empty, or ErrIncompatibleValue if the key is already used by a
+// key/value pair.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) CreateBucket(key []byte) (walletdb.Bucket, error) {
+	if err := b.checkWritable(); err != nil {
+		return nil, err
+	}
+	if len(key) == 0 {
+		return nil, walletdb.ErrBucketNameRequired
+	}
+	if len(key) > maxKeySize {
+		return nil, walletdb.ErrKeyTooLarge
+	}
+
+	n := b.node()
+	if n == nil {
+		return nil, walletdb.ErrBucketNotFound
+	}
+	k := string(key)
+	if n.buckets[k] != nil {
+		return nil, walletdb.ErrBucketExists
+	}
+	if _, ok := n.values[k]; ok {
+		return nil, walletdb.ErrIncompatibleValue
+	}
+
+	n = b.mutableNode()
+	child := newNode()
+	b.tx.owned[child] = struct{}{}
+	n.buckets[k] = child
+	n.insertKey(k)
+	return &bucket{tx: b.tx, parent: b, name: k}, nil
+}
+
+// CreateBucketIfNotExists creates and returns a new nested bucket with the
+// given key if it does not already exist.  Returns ErrBucketNameRequired if the
+// key is empty or ErrIncompatibleValue if the key is already used by a
+// key/value pair.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) CreateBucketIfNotExists(key []byte) (walletdb.Bucket, error) {
+	child, err := b.CreateBucket(key)
+	if err == walletdb.ErrBucketExists {
+		return b.Bucket(key), nil
+	}
+	return child, err
+}
+
+// DeleteBucket removes a nested bucket with the given key.  Returns
+// ErrTxNotWritable if attempted against a read-only transaction,
+// ErrBucketNotFound if the specified bucket does not exist, and
+// ErrIncompatibleValue if the key is empty or a key/value pair.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) DeleteBucket(key []byte) error {
+	if err := b.checkWritable(); err != nil {
+		return err
+	}
+	if len(key) == 0 {
+		return walletdb.ErrIncompatibleValue
+	}
+
+	n := b.node()
+	if n == nil {
+		return walletdb.ErrBucketNotFound
+	}
+	k := string(key)
+	if n.buckets[k] == nil {
+		if _, ok := n.values[k]; ok {
+			return walletdb.ErrIncompatibleValue
+		}
+		return walletdb.ErrBucketNotFound
+	}
+
+	n = b.mutableNode()
+	delete(n.buckets, k)
+	n.removeKey(k)
+	return nil
+}
+
+// ForEach invokes the passed function with every key/value pair in the bucket.
+// This includes nested buckets, in which case the value is nil, but it does not
+// include the key/value pairs within those nested buckets.
+//
+// NOTE: The values returned by this function are only valid during a
+// transaction and must not be modified.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) ForEach(fn func(k, v []byte) error) error {
+	if b.tx.closed {
+		return walletdb.ErrTxClosed
+	}
+	c := b.Cursor()
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		if err := fn(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Writable returns whether or not the bucket is writable.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) Writable() bool {
+	return b.tx.writable
+}
+
+// Put saves the specified key/value pair to the bucket.  Keys that do not
+// already exist are added and keys that already exist are overwritten.  Returns
+// ErrTxNotWritable if attempted against a read-only transaction.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) Put(key, value []byte) error {
+	if err := b.checkWritable(); err != nil {
+		return err
+	}
+	switch {
+	case len(key) == 0:
+		return walletdb.ErrKeyRequired
+	case len(key) > maxKeySize:
+		return walletdb.ErrKeyTooLarge
+	case len(value) > maxValueSize:
+		return walletdb.ErrValueTooLarge
+	}
+
+	n := b.node()
+	if n == nil {
+		return walletdb.ErrBucketNotFound
+	}
+	k := string(key)
+	if n.buckets[k] != nil {
+		return walletdb.ErrIncompatibleValue
+	}
+
+	// The value is copied since the caller may modify it after the call.
+	n = b.mutableNode()
+	if _, ok := n.values[k]; !ok {
+		n.insertKey(k)
+	}
+	n.values[k] = append([]byte{}, value...)
+	return nil
+}
+
+// Get returns the value for the given key.  Returns nil if the key does
+// not exist in this bucket (or nested buckets).
+//
+// NOTE: The value returned by this function is only valid during a
+// transaction and must not be modified.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) Get(key []byte) []byte {
+	if b.tx.closed {
+		return nil
+	}
+	n := b.node()
+	if n == nil {
+		return nil
+	}
+	return n.values[string(key)]
+}
+
+// Delete removes the specified key from the bucket.  Deleting a key that does
+// not exist does not return an error.  Returns ErrTxNotWritable if attempted
+// against a read-only transaction, or ErrIncompatibleValue if the key is a
+// nested bucket.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) Delete(key []byte) error {
+	if err := b.checkWritable(); err != nil {
+		return err
+	}
+
+	n := b.node()
+	if n == nil {
+		return nil
+	}
+	k := string(key)
+	if n.buckets[k] != nil {
+		return walletdb.ErrIncompatibleValue
+	}
+	if _, ok := n.values[k]; !ok {
+		return nil
+	}
+
+	n = b.mutableNode()
+	delete(n.values, k)
+	n.removeKey(k)
+	return nil
+}
+
+// Cursor returns a new cursor, allowing for iteration over the bucket's
+// key/value pairs and nested buckets in forward or backward order.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) Cursor() walletdb.Cursor {
+	return &cursor{bucket: b}
+}
+
+// cursor represents a cursor over key/value pairs and nested buckets of a
+// bucket.  The cursor is positioned by key rather than by index, so each move
+// searches the current keys of the bucket and the cursor remains valid when
+// the bucket is modified.
+type cursor struct {
+	bucket     *bucket
+	key        string
+	positioned bool
+}
+
+// Enforce cursor implements the walletdb.Cursor interface.
+var _ walletdb.Cursor = (*cursor)(nil)
+
+// at positions the cursor at the key with an index in the keys of the node and
+// returns the pair.  Nil is returned, and the cursor is not moved, when the
+// index is out of range.
+func (c *cursor) at(n *node, i int) (key, value []byte) {
+	if i < 0 || i >= len(n.keys) {
+		return nil, nil
+	}
+	c.key = n.keys[i]
+	c.positioned = true
+	if n.buckets[c.key] != nil {
+		return []byte(c.key), nil
+	}
+	return []byte(c.key), n.values[c.key]
+}
+
+// currentNode returns the node of the cursor's bucket, or nil when the
+// transaction is closed or the bucket was deleted.
+func (c *cursor) currentNode() *node {
+	if c.bucket.tx.closed {
+		return nil
+	}
+	return c.bucket.node()
+}
+
+// Bucket returns the bucket the cursor was created for.
+//
+// This function is part of the walletdb.Cursor interface implementation.
+func (c *cursor) Bucket() walletdb.Bucket {
+	return c.bucket
+}
+
+// Delete removes the current key/value pair the cursor is at without
+// invalidating the cursor. Returns ErrTxNotWritable if attempted on a read-only
+// transaction, or ErrIncompatibleValue if attempted when the cursor points to a
+// nested bucket.
+//
+// This function is part of the walletdb.Cursor interface implementation.
+func (c *cursor) Delete() error {
+	if err := c.bucket.checkWritable(); err != nil {
+		return err
+	}
+	if !c.positioned {
+		return nil
+	}
+	return c.bucket.Delete([]byte(c.key))
+}
+
+// First positions the cursor at the first key/value pair and returns the pair.
+//
+// This function is part of the walletdb.Cursor interface implementation.
+func (c *cursor) First() (key, value []byte) {
+	n := c.currentNode()
+	if n == nil {
+		return nil, nil
+	}
+	return c.at(n, 0)
+}
+
+// Last positions the cursor at the last key/value pair and returns the pair.
+//
+// This function is part of the walletdb.Cursor interface implementation.
+func (c *cursor) Last() (key, value []byte) {
+	n := c.currentNode()
+	if n == nil {
+		return nil, nil
+	}
+	return c.at(n, len(n.keys)-1)
+}
+
+// Next moves the cursor one key/value pair forward and returns the new pair.
+//
+// This function is part of the walletdb.Cursor interface implementation.
+func (c *cursor) Next() (key, value []byte) {
+	if !c.positioned {
+		return c.First()
+	}
+	n := c.currentNode()
+	if n == nil {
+		return nil, nil
+	}
+	i := sort.SearchStrings(n.keys, c.key)
+	if i < len(n.keys) && n.keys[i] == c.key {
+		i++
+	}
+	return c.at(n, i)
+}
+
+// Prev moves the cursor one key/value pair backward and returns the new pair.
+//
+// This function is part of the walletdb.Cursor interface implementation.
+func (c *cursor) Prev() (key, value []byte) {
+	if !c.positioned {
+		return c.Last()
+	}
+	n := c.currentNode()
+	if n == nil {
+		return nil, nil
+	}
+	return c.at(n, sort.SearchStrings(n.keys, c.key)-1)
+}
+
+// Seek positions the cursor at the passed seek key. If the key does not exist,
+// the cursor is moved to the next key after seek. Returns the new pair.
+//
+// This function is part of the walletdb.Cursor interface implementation.
+func (c *cursor) Seek(seek []byte) (key, value []byte) {
+	n := c.currentNode()
+	if n == nil {
+		return nil, nil
+	}
+	return c.at(n, sort.SearchStrings(n.keys, string(seek)))
+}
+
+// transaction represents a database transaction.  It can either by read-only or
+// read-write and implements the walletdb.Tx interface.  The transaction
+// provides a root bucket against which all read and writes occur.
+type transaction struct {
+	db         *db
+	root       *node // Root node of the database viewed by the transaction.
+	owned      map[*node]struct{}
+	writable   bool
+	managed    bool
+	closed     bool
+	rootBucket *bucket
+}
+
+// Enforce transaction implements the walletdb.Tx interface.
+var _ walletdb.Tx = (*transaction)(nil)
+
+// close releases the write lock of the transaction.
+func (tx *transaction) close() {
+	tx.closed = true
+	tx.root = nil
+	tx.owned = nil
+	if tx.writable {
+		tx.db.writeMu.Unlock()
+	}
+}
+
+// RootBucket returns the top-most bucket for the namespace the transaction was
+// created from.
+//
+// This function is part of the walletdb.Tx interface implementation.
+func (tx *transaction) RootBucket() walletdb.Bucket {
+	return tx.rootBucket
+}
+
+// Commit commits all changes that have been made through the root bucket and
+// all of its sub-buckets.
+//
+// This function is part of the walletdb.Tx interface implementation.
+func (tx *transaction) Commit() error {
+	if tx.managed {
+		panic("managed transaction commit not allowed")
+	}
+	return tx.commit()
+}
+
+// commit makes the nodes of the transaction the committed nodes of the
+// database.
+func (tx *transaction) commit() error {
+	if tx.closed {
+		return walletdb.ErrTxClosed
+	}
+	if !tx.writable {
+		return walletdb.ErrTxNotWritable
+	}
+	defer tx.close()
+
+	tx.db.mu.Lock()
+	defer tx.db.mu.Unlock()
+	if tx.db.closed {
+		return walletdb.ErrDbNotOpen
+	}
+	tx.db.root = tx.root
+	return nil
+}
+
+// Rollback undoes all changes that have been made to the root bucket and all of
+// its sub-buckets.
+//
+// This function is part of the walletdb.Tx interface implementation.
+func (tx *transaction) Rollback() error {
+	if tx.managed {
+		panic("managed transaction rollback not allowed")
+	}
+	return tx.rollback()
+}
+
+// rollback discards the nodes of the transaction.
+func (tx *transaction) rollback() error {
+	if tx.closed {
+		return walletdb.ErrTxClosed
+	}
+	tx.close()
+	return nil
+}
+
+// namespace represents a database namespace that is inteded to support the
+// concept of a single entity that controls the opening, creating, and closing
+// of a database while providing other entities their own namespace to work in.
+// It implements the walletdb.Namespace interface.
+type namespace struct {
+	db  *db
+	key []byte
+}
+
+// Enforce namespace implements the walletdb.Namespace interface.
+var _ walletdb.Namespace = (*namespace)(nil)
+
+// Begin starts a transaction which is either read-only or read-write depending
+// on the specified flag.  Multiple read-only transactions can be started
+// simultaneously while only a single read-write transaction can be started at a
+// time.  The call will block when starting a read-write transaction when one is
+// already open.
+//
+// NOTE: The transaction must be closed by calling Rollback or Commit on it when
+// it is no longer needed.  Failure to do so will keep the buckets it views in
+// memory, and for read-write transactions, hold the write lock.
+//
+// This function is part of the walletdb.Namespace interface implementation.
+func (ns *namespace) Begin(writable bool) (walletdb.Tx, error) {
+	return ns.begin(writable)
+}
+
+// begin starts a transaction with the root bucket of the namespace.
+func (ns *namespace) begin(writable bool) (*transaction, error) {
+	tx, err := ns.db.begin(writable)
+	if err != nil {
+		return nil, err
+	}
+	if tx.rootBucket.Bucket(ns.key) == nil {
+		tx.rollback()
+		return nil, walletdb.ErrBucketNotFound
+	}
+	tx.rootBucket = &bucket{tx: tx, parent: tx.rootBucket, name: string(ns.key)}
+	return tx, nil
+}
+
+// View invokes the passed function in the context of a managed read-only
+// transaction.  Any errors returned from the user-supplied function are
+// returned from this function.
+//
+// Calling Rollback on the transaction passed to the user-supplied function will
+// result in a panic.
+//
+// This function is part of the walletdb.Namespace interface implementation.
+func (ns *namespace) View(fn func(walletdb.Tx) error) error {
+	tx, err := ns.begin(false)
+	if err != nil {
+		return err
+	}
+	defer tx.rollback()
+
+	tx.managed = true
+	err = fn(tx)
+	tx.managed = false
+	return err
+}
+
+// Update invokes the passed function in the context of a managed read-write
+// transaction.  Any errors returned from the user-supplied function will cause
+// the transaction to be rolled back and are returned from this function.
+// Otherwise, the transaction is commited when the user-supplied function
+// returns a nil error.
+//
+// Calling Rollback on the transaction passed to the user-supplied function will
+// result in a panic.
+//
+// This function is part of the walletdb.Namespace interface implementation.
+func (ns *namespace) Update(fn func(walletdb.Tx) error) error {
+	tx, err := ns.begin(true)
+	if err != nil {
+		return err
+	}
+
+	tx.managed = true
+	err = fn(tx)
+	tx.managed = false
+	if err != nil {
+		tx.rollback()
+		return err
+	}
+	return tx.commit()
+}
+
+// db represents a collection of namespaces which are kept in memory and
+// implements the walletdb.Db interface.  All database access is performed
+// through transactions which are obtained through the specific Namespace.
+type db struct {
+	// writeMu is held by the open read-write transaction.
+	writeMu sync.Mutex
+
+	mu     sync.RWMutex // Protects root and closed.
+	root   *node
+	closed bool
+}
+
+// Enforce db implements the walletdb.Db interface.
+var _ walletdb.DB = (*db)(nil)
+
+// newDB returns a new empty database.
+func newDB() *db {
+	return &db{root: newNode()}
+}
+
+// begin starts a transaction with the root bucket of the database, which holds
+// the namespaces.
+func (db *db) begin(writable bool) (*transaction, error) {
+	// The write lock is taken before viewing the committed nodes so that
+	// the transaction views the changes of the previous read-write
+	// transaction.
+	if writable {
+		db.writeMu.Lock()
+	}
+	db.mu.RLock()
+	root, closed := db.root, db.closed
+	db.mu.RUnlock()
+	if closed {
+		if writable {
+			db.writeMu.Unlock()
+		}
+		return nil, walletdb.ErrDbNotOpen
+	}
+
+	tx := &transaction{db: db, root: root, writable: writable}
+	if writable {
+		tx.owned = make(map[*node]struct{})
+	}
+	tx.rootBucket = &bucket{tx: tx}
+	return tx, nil
+}
+
+// Namespace returns a Namespace interface for the provided key.  See the
+// Namespace interface documentation for more details.  Attempting to access a
+// Namespace on a database that is not open yet or has been closed will result
+// in ErrDbNotOpen.  Namespaces are created in the database on first access.
+//
+// This function is part of the walletdb.Db interface implementation.
+func (db *db) Namespace(key []byte) (walletdb.Namespace, error) {
+	// Check if the namespace needs to be created using a read-only
+	// transaction.  This is done because read-only transactions don't
+	// block like write transactions.
+	tx, err := db.begin(false)
+	if err != nil {
+		return nil, err
+	}
+	exists := tx.rootBucket.Bucket(key) != nil
+	tx.rollback()
+
+	// Create the namespace if needed by using a read-write transaction.
+	if !exists {
+		tx, err := db.begin(true)
+		if err != nil {
+			return nil, err
+		}
+		_, err = tx.rootBucket.CreateBucketIfNotExists(key)
+		if err != nil {
+			tx.rollback()
+			return nil, err
+		}
+		if err := tx.commit(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &namespace{db: db, key: key}, nil
+}
+
+// DeleteNamespace deletes the namespace for the passed key.  ErrBucketNotFound
+// will be returned if the namespace does not exist.
+//
+// This function is part of the walletdb.Db interface implementation.
+func (db *db) DeleteNamespace(key []byte) error {
+	tx, err := db.begin(true)
+	if err != nil {
+		return err
+	}
+	if err := tx.rootBucket.DeleteBucket(key); err != nil {
+		tx.rollback()
+		return err
+	}
+	return tx.commit()
+}
+
+// copyNode writes the pairs and nested buckets of a node to a bolt bucket.
+func copyNode(dst *bolt.Bucket, n *node) error {
+	for _, k := range n.keys {
+		if child := n.buckets[k]; child != nil {
+			b, err := dst.CreateBucket([]byte(k))
+			if err != nil {
+				return err
+			}
+			if err := copyNode(b, child); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := dst.Put([]byte(k), n.values[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Copy writes a copy of the database to the provided writer.  The copy is a
+// bolt database holding the committed data, which may be opened with the bdb
+// driver.
+//
+// This function is part of the walletdb.Db interface implementation.
+func (db *db) Copy(w io.Writer) error {
+	db.mu.RLock()
+	root, closed := db.root, db.closed
+	db.mu.RUnlock()
+	if closed {
+		return walletdb.ErrDbNotOpen
+	}
+
+	dir, err := ioutil.TempDir("", "walletdb-memdb")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	copyPath := filepath.Join(dir, "copy.db")
+	boltDB, err := bolt.Open(copyPath, 0600, nil)
+	if err != nil {
+		return err
+	}
+	err = boltDB.Update(func(tx *bolt.Tx) error {
+		for _, k := range root.keys {
+			b, err := tx.CreateBucket([]byte(k))
+			if err != nil {
+				return err
+			}
+			if err := copyNode(b, root.buckets[k]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		boltDB.Close()
+		return err
+	}
+	return boltDB.View(func(tx *bolt.Tx) error {
+		defer boltDB.Close()
+		_, err := tx.WriteTo(w)
+		return err
+	})
+}
+
+// Close discards all data of the database.
+//
+// This function is part of the walletdb.Db interface implementation.
+func (db *db) Close() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.closed = true
+	db.root = nil
+	return nil
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package memdb implements an instance of walletdb that keeps all data in memory.
+It is intended for tests and for throwaway wallets, such as simulation wallets,
+which do not need to outlive the process.
+
+Usage
+
+This package is only a driver to the walletdb package and provides the database
+type of "memdb".  The Create function takes no parameters and returns a new
+empty database:
+
+	db, err := walletdb.Create("memdb")
+	if err != nil {
+		// Handle error
+	}
+
+All data is lost when the database is closed, so there are no databases for
+the Open function to open, and it always returns walletdb.ErrDbDoesNotExist.
+The Copy method of a database writes a bolt database which may be opened with
+the bdb driver, allowing an in-memory database to be persisted.
+
+Transactions
+
+Buckets are copied on write.  Read-only transactions view the buckets of the
+last committed transaction, which are never modified, while the read-write
+transaction modifies copies of the buckets it writes to.  Committing the
+read-write transaction replaces the committed buckets with its copies, and
+rolling it back discards them.
+*/
+package memdb
@@ -0,0 +1,290 @@
+// Copyright (c) 2014-2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package memdb_test
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+	_ "github.com/btcsuite/btcwallet/walletdb/bdb"
+	_ "github.com/btcsuite/btcwallet/walletdb/memdb"
+)
+
+// dbType is the database type name for this driver.
+const dbType = "memdb"
+
+// TestCreateOpenFail ensures that errors related to creating and opening a
+// database are handled properly.
+func TestCreateOpenFail(t *testing.T) {
+	// Ensure that attempting to open a database returns the expected error
+	// since in-memory databases do not outlive the process.
+	wantErr := walletdb.ErrDbDoesNotExist
+	if _, err := walletdb.Open(dbType); err != wantErr {
+		t.Errorf("Open: did not receive expected error - got %v, "+
+			"want %v", err, wantErr)
+		return
+	}
+
+	// Ensure that attempting to open a database with parameters returns
+	// the expected error.
+	wantErr = fmt.Errorf("invalid arguments to %s.Open -- expected no "+
+		"arguments", dbType)
+	if _, err := walletdb.Open(dbType, 1); err.Error() != wantErr.Error() {
+		t.Errorf("Open: did not receive expected error - got %v, "+
+			"want %v", err, wantErr)
+		return
+	}
+
+	// Ensure that attempting to create a database with parameters returns
+	// the expected error.
+	wantErr = fmt.Errorf("invalid arguments to %s.Create -- expected no "+
+		"arguments", dbType)
+	if _, err := walletdb.Create(dbType, "path"); err.Error() != wantErr.Error() {
+		t.Errorf("Create: did not receive expected error - got %v, "+
+			"want %v", err, wantErr)
+		return
+	}
+
+	// Ensure operations against a closed database return the expected
+	// error.
+	db, err := walletdb.Create(dbType)
+	if err != nil {
+		t.Errorf("Create: unexpected error: %v", err)
+		return
+	}
+	db.Close()
+
+	wantErr = walletdb.ErrDbNotOpen
+	if _, err := db.Namespace([]byte("ns1")); err != wantErr {
+		t.Errorf("Namespace: did not receive expected error - got %v, "+
+			"want %v", err, wantErr)
+		return
+	}
+}
+
+// TestInterface performs all interfaces tests for this database driver.
+func TestInterface(t *testing.T) {
+	// Create a new database to run tests against.
+	db, err := walletdb.Create(dbType)
+	if err != nil {
+		t.Errorf("Failed to create test database (%s) %v", dbType, err)
+		return
+	}
+	defer db.Close()
+
+	// Run all of the interface tests against the database.
+	testInterface(t, db)
+}
+
+// TestCursor ensures cursors iterate the pairs and nested buckets of a bucket
+// in key order and remain valid when the bucket is modified.
+func TestCursor(t *testing.T) {
+	db, err := walletdb.Create(dbType)
+	if err != nil {
+		t.Fatalf("Failed to create test database (%s) %v", dbType, err)
+	}
+	defer db.Close()
+
+	ns, err := db.Namespace([]byte("ns1"))
+	if err != nil {
+		t.Fatalf("Namespace: unexpected error: %v", err)
+	}
+	err = ns.Update(func(tx walletdb.Tx) error {
+		root := tx.RootBucket()
+		for _, k := range []string{"c", "a", "e"} {
+			if err := root.Put([]byte(k), []byte("v"+k)); err != nil {
+				return err
+			}
+		}
+		nested, err := root.CreateBucket([]byte("b"))
+		if err != nil {
+			return err
+		}
+		if err := nested.Put([]byte("nested"), []byte("x")); err != nil {
+			return err
+		}
+		if err := root.Put([]byte("empty"), nil); err != nil {
+			return err
+		}
+
+		var keys []string
+		c := root.Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			if string(k) == "b" && v != nil {
+				return fmt.Errorf("nested bucket has value %q", v)
+			}
+			keys = append(keys, string(k))
+		}
+		want := []string{"a", "b", "c", "e", "empty"}
+		if !reflect.DeepEqual(keys, want) {
+			return fmt.Errorf("cursor keys %q, want %q", keys, want)
+		}
+
+		if k, v := c.Seek([]byte("d")); string(k) != "e" || string(v) != "ve" {
+			return fmt.Errorf("Seek: got %q %q, want e ve", k, v)
+		}
+		if k, _ := c.Prev(); string(k) != "c" {
+			return fmt.Errorf("Prev: got %q, want c", k)
+		}
+		if err := c.Delete(); err != nil {
+			return fmt.Errorf("Delete: unexpected error: %v", err)
+		}
+		if k, _ := c.Next(); string(k) != "e" {
+			return fmt.Errorf("Next after Delete: got %q, want e", k)
+		}
+		if k, v := c.Last(); string(k) != "empty" || v == nil || len(v) != 0 {
+			return fmt.Errorf("Last: got %q %q, want empty value", k, v)
+		}
+		c.Seek([]byte("b"))
+		if err := c.Delete(); err != walletdb.ErrIncompatibleValue {
+			return fmt.Errorf("Delete bucket: unexpected error: %v", err)
+		}
+		if err := root.Put([]byte("b"), nil); err != walletdb.ErrIncompatibleValue {
+			return fmt.Errorf("Put over bucket: unexpected error: %v", err)
+		}
+		if _, err := root.CreateBucket([]byte("a")); err != walletdb.ErrIncompatibleValue {
+			return fmt.Errorf("CreateBucket over value: unexpected "+
+				"error: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestIsolation ensures read-only transactions view the data committed when
+// they began, and that rolled back changes to nested buckets are discarded.
+func TestIsolation(t *testing.T) {
+	db, err := walletdb.Create(dbType)
+	if err != nil {
+		t.Fatalf("Failed to create test database (%s) %v", dbType, err)
+	}
+	defer db.Close()
+
+	ns, err := db.Namespace([]byte("ns1"))
+	if err != nil {
+		t.Fatalf("Namespace: unexpected error: %v", err)
+	}
+	err = ns.Update(func(tx walletdb.Tx) error {
+		nested, err := tx.RootBucket().CreateBucket([]byte("bucket"))
+		if err != nil {
+			return err
+		}
+		return nested.Put([]byte("key"), []byte("old"))
+	})
+	if err != nil {
+		t.Fatalf("Update: unexpected error: %v", err)
+	}
+
+	readTx, err := ns.Begin(false)
+	if err != nil {
+		t.Fatalf("Begin: unexpected error: %v", err)
+	}
+	defer readTx.Rollback()
+
+	// Modify the nested bucket and roll back the changes.
+	writeTx, err := ns.Begin(true)
+	if err != nil {
+		t.Fatalf("Begin: unexpected error: %v", err)
+	}
+	nested := writeTx.RootBucket().Bucket([]byte("bucket"))
+	if err := nested.Put([]byte("key"), []byte("rolled back")); err != nil {
+		t.Fatalf("Put: unexpected error: %v", err)
+	}
+	if err := writeTx.Rollback(); err != nil {
+		t.Fatalf("Rollback: unexpected error: %v", err)
+	}
+
+	// Modify the nested bucket and commit the changes.
+	err = ns.Update(func(tx walletdb.Tx) error {
+		nested := tx.RootBucket().Bucket([]byte("bucket"))
+		if v := nested.Get([]byte("key")); string(v) != "old" {
+			return fmt.Errorf("rolled back value %q, want old", v)
+		}
+		return nested.Put([]byte("key"), []byte("new"))
+	})
+	if err != nil {
+		t.Fatalf("Update: unexpected error: %v", err)
+	}
+
+	nested = readTx.RootBucket().Bucket([]byte("bucket"))
+	if v := nested.Get([]byte("key")); string(v) != "old" {
+		t.Errorf("read-only transaction value %q, want old", v)
+	}
+	err = ns.View(func(tx walletdb.Tx) error {
+		nested := tx.RootBucket().Bucket([]byte("bucket"))
+		if v := nested.Get([]byte("key")); string(v) != "new" {
+			return fmt.Errorf("committed value %q, want new", v)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestCopy ensures the copy of a database is a bolt database holding the same
+// data which may be opened with the bdb driver.
+func TestCopy(t *testing.T) {
+	db, err := walletdb.Create(dbType)
+	if err != nil {
+		t.Fatalf("Failed to create test database (%s) %v", dbType, err)
+	}
+	defer db.Close()
+
+	ns, err := db.Namespace([]byte("ns1"))
+	if err != nil {
+		t.Fatalf("Namespace: unexpected error: %v", err)
+	}
+	err = ns.Update(func(tx walletdb.Tx) error {
+		nested, err := tx.RootBucket().CreateBucket([]byte("bucket"))
+		if err != nil {
+			return err
+		}
+		return nested.Put([]byte("key"), []byte("value"))
+	})
+	if err != nil {
+		t.Fatalf("Update: unexpected error: %v", err)
+	}
+
+	copyPath := "copytest.db"
+	f, err := os.Create(copyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(copyPath)
+	err = db.Copy(f)
+	f.Close()
+	if err != nil {
+		t.Fatalf("Copy: unexpected error: %v", err)
+	}
+
+	copyDB, err := walletdb.Open("bdb", copyPath)
+	if err != nil {
+		t.Fatalf("Open: unexpected error: %v", err)
+	}
+	defer copyDB.Close()
+	ns, err = copyDB.Namespace([]byte("ns1"))
+	if err != nil {
+		t.Fatalf("Namespace: unexpected error: %v", err)
+	}
+	err = ns.View(func(tx walletdb.Tx) error {
+		nested := tx.RootBucket().Bucket([]byte("bucket"))
+		if nested == nil {
+			return fmt.Errorf("copied bucket does not exist")
+		}
+		if v := nested.Get([]byte("key")); string(v) != "value" {
+			return fmt.Errorf("copied value %q, want value", v)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,873 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+	_ "github.com/mattn/go-sqlite3" // Registers the sqlite3 sql driver.
+)
+
+const (
+	// maxKeySize and maxValueSize are the largest keys and values which
+	// may be stored.  They match the limits of bolt so that data can be
+	// moved between the drivers.
+	maxKeySize   = 32768
+	maxValueSize = (1 << 31) - 2
+
+	// busyTimeout is the number of milliseconds to wait for the locks of
+	// the database file when it is used by another process.
+	busyTimeout = 10000
+
+	// rootBucketID is the ID of the bucket holding the namespaces.
+	rootBucketID = 0
+)
+
+// schema creates the table holding all key/value pairs and nested buckets of
+// the database.  See the package documentation for a description.
+const schema = `
+CREATE TABLE IF NOT EXISTS kv (
+	key    BLOB PRIMARY KEY,
+	value  BLOB,
+	bucket INTEGER
+) WITHOUT ROWID;
+CREATE INDEX IF NOT EXISTS kv_bucket ON kv (bucket);
+`
+
+// bucketPrefix returns the prefix of the keys of the bucket with an ID.
+func bucketPrefix(id int64) []byte {
+	prefix := make([]byte, 8)
+	binary.BigEndian.PutUint64(prefix, uint64(id))
+	return prefix
+}
+
+// bucket is an internal type used to represent a collection of key/value pairs
+// and implements the walletdb.Bucket interface.
+type bucket struct {
+	tx *transaction
+	id int64
+}
+
+// Enforce bucket implements the walletdb.Bucket interface.
+var _ walletdb.Bucket = (*bucket)(nil)
+
+// dbKey returns the key of the table row holding a key of the bucket.
+func (b *bucket) dbKey(key []byte) []byte {
+	return append(bucketPrefix(b.id), key...)
+}
+
+// bounds returns the range of table keys holding the keys of the bucket.  The
+// lower bound is inclusive and the upper bound is exclusive.
+func (b *bucket) bounds() (lo, hi []byte) {
+	return bucketPrefix(b.id), bucketPrefix(b.id + 1)
+}
+
+// lookup returns the row of a key of the bucket.  The value is nil and the
+// child is valid when the key is a nested bucket.
+func (b *bucket) lookup(key []byte) (value []byte, child sql.NullInt64, found bool, err error) {
+	row := b.tx.queryRow("SELECT value, bucket FROM kv WHERE key = ?",
+		b.dbKey(key))
+	err = row.Scan(&value, &child)
+	switch err {
+	case nil:
+		return value, child, true, nil
+	case sql.ErrNoRows:
+		return nil, child, false, nil
+	default:
+		return nil, child, false, err
+	}
+}
+
+// checkWritable returns the error of writing to the bucket when its
+// transaction is closed or read-only.
+func (b *bucket) checkWritable() error {
+	if b.tx.closed {
+		return walletdb.ErrTxClosed
+	}
+	if !b.tx.writable {
+		return walletdb.ErrTxNotWritable
+	}
+	return nil
+}
+
+// Bucket retrieves a nested bucket with the given key.  Returns nil if
+// the bucket does not exist.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) Bucket(key []byte) walletdb.Bucket {
+	if b.tx.closed {
+		return nil
+	}
+	_, child, found, err := b.lookup(key)
+	if err != nil {
+		b.tx.setErr(err)
+		return nil
+	}
+	if !found || !child.Valid {
+		return nil
+	}
+	return &bucket{tx: b.tx, id: child.Int64}
+}
+
+// CreateBucket creates and returns a new nested bucket with the given key.
+// Returns ErrBucketExists if the bucket already exists, ErrBucketNameRequired
+// if the key is empty, or ErrIncompatibleValue if the key is already used by a
+// key/value pair.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) CreateBucket(key []byte) (walletdb.Bucket, error) {
+	if err := b.checkWritable(); err != nil {
+		return nil, err
+	}
+	if len(key) == 0 {
+		return nil, walletdb.ErrBucketNameRequired
+	}
+	if len(key) > maxKeySize {
+		return nil, walletdb.ErrKeyTooLarge
+	}
+
+	_, child, found, err := b.lookup(key)
+	if err != nil {
+		return nil, err
+	}
+	if found && child.Valid {
+		return nil, walletdb.ErrBucketExists
+	}
+	if found {
+		return nil, walletdb.ErrIncompatibleValue
+	}
+
+	var id int64
+	row := b.tx.queryRow("SELECT COALESCE(MAX(bucket), 0) + 1 FROM kv")
+	if err := row.Scan(&id); err != nil {
+		return nil, err
+	}
+	err = b.tx.exec("INSERT INTO kv (key, value, bucket) VALUES (?, NULL, ?)",
+		b.dbKey(key), id)
+	if err != nil {
+		return nil, err
+	}
+	return &bucket{tx: b.tx, id: id}, nil
+}
+
+// CreateBucketIfNotExists creates and returns a new nested bucket with the
+// given key if it does not already exist.  Returns ErrBucketNameRequired if the
+// key is empty or ErrIncompatibleValue if the key is already used by a
+// key/value pair.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) CreateBucketIfNotExists(key []byte) (walletdb.Bucket, error) {
+	child, err := b.CreateBucket(key)
+	if err == walletdb.ErrBucketExists {
+		return b.Bucket(key), nil
+	}
+	return child, err
+}
+
+// DeleteBucket removes a nested bucket with the given key.  Returns
+// ErrTxNotWritable if attempted against a read-only transaction,
+// ErrBucketNotFound if the specified bucket does not exist, and
+// ErrIncompatibleValue if the key is empty or a key/value pair.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) DeleteBucket(key []byte) error {
+	if err := b.checkWritable(); err != nil {
+		return err
+	}
+	if len(key) == 0 {
+		return walletdb.ErrIncompatibleValue
+	}
+
+	_, child, found, err := b.lookup(key)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return walletdb.ErrBucketNotFound
+	}
+	if !child.Valid {
+		return walletdb.ErrIncompatibleValue
+	}
+
+	err = b.tx.deleteBucketContents(child.Int64)
+	if err != nil {
+		return err
+	}
+	return b.tx.exec("DELETE FROM kv WHERE key = ?", b.dbKey(key))
+}
+
+// ForEach invokes the passed function with every key/value pair in the bucket.
+// This includes nested buckets, in which case the value is nil, but it does not
+// include the key/value pairs within those nested buckets.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) ForEach(fn func(k, v []byte) error) error {
+	if b.tx.closed {
+		return walletdb.ErrTxClosed
+	}
+	c := b.Cursor()
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		if err := fn(k, v); err != nil {
+			return err
+		}
+	}
+	return b.tx.err
+}
+
+// Writable returns whether or not the bucket is writable.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) Writable() bool {
+	return b.tx.writable
+}
+
+// Put saves the specified key/value pair to the bucket.  Keys that do not
+// already exist are added and keys that already exist are overwritten.  Returns
+// ErrTxNotWritable if attempted against a read-only transaction.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) Put(key, value []byte) error {
+	if err := b.checkWritable(); err != nil {
+		return err
+	}
+	switch {
+	case len(key) == 0:
+		return walletdb.ErrKeyRequired
+	case len(key) > maxKeySize:
+		return walletdb.ErrKeyTooLarge
+	case len(value) > maxValueSize:
+		return walletdb.ErrValueTooLarge
+	}
+
+	_, child, _, err := b.lookup(key)
+	if err != nil {
+		return err
+	}
+	if child.Valid {
+		return walletdb.ErrIncompatibleValue
+	}
+
+	// A nil value would be stored as NULL, which marks nested buckets.
+	if value == nil {
+		value = []byte{}
+	}
+	return b.tx.exec("INSERT OR REPLACE INTO kv (key, value, bucket) "+
+		"VALUES (?, ?, NULL)", b.dbKey(key), value)
+}
+
+// Get returns the value for the given key.  Returns nil if the key does
+// not exist in this bucket (or nested buckets).
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) Get(key []byte) []byte {
+	if b.tx.closed {
+		return nil
+	}
+	value, child, found, err := b.lookup(key)
+	if err != nil {
+		b.tx.setErr(err)
+		return nil
+	}
+	if !found || child.Valid {
+		return nil
+	}
+	if value == nil {
+		value = []byte{}
+	}
+	return value
+}
+
+// Delete removes the specified key from the bucket.  Deleting a key that does
+// not exist does not return an error.  Returns ErrTxNotWritable if attempted
+// against a read-only transaction, or ErrIncompatibleValue if the key is a
+// nested bucket.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) Delete(key []byte) error {
+	if err := b.checkWritable(); err != nil {
+		return err
+	}
+
+	_, child, _, err := b.lookup(key)
+	if err != nil {
+		return err
+	}
+	if child.Valid {
+		return walletdb.ErrIncompatibleValue
+	}
+	return b.tx.exec("DELETE FROM kv WHERE key = ?", b.dbKey(key))
+}
+
+// Cursor returns a new cursor, allowing for iteration over the bucket's
+// key/value pairs and nested buckets in forward or backward order.
+//
+// This function is part of the walletdb.Bucket interface implementation.
+func (b *bucket) Cursor() walletdb.Cursor {
+	return &cursor{bucket: b}
+}
+
+// cursor represents a cursor over key/value pairs and nested buckets of a
+// bucket.  Each move of the cursor queries the table for the next key in the
+// direction of the move, so the cursor remains valid when the bucket is
+// modified.
+type cursor struct {
+	bucket *bucket
+
+	// key is the table key of the current pair, or nil when the cursor
+	// has not been positioned.
+	key []byte
+}
+
+// Enforce cursor implements the walletdb.Cursor interface.
+var _ walletdb.Cursor = (*cursor)(nil)
+
+// move positions the cursor at the first row returned by a query selecting
+// the key, value and bucket columns, and returns the pair of the row.  Nil is
+// returned, and the cursor is not moved, when the query returns no rows.
+func (c *cursor) move(query string, args ...interface{}) (key, value []byte) {
+	tx := c.bucket.tx
+	if tx.closed {
+		return nil, nil
+	}
+
+	var dbKey []byte
+	var child sql.NullInt64
+	err := tx.queryRow(query, args...).Scan(&dbKey, &value, &child)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			tx.setErr(err)
+		}
+		return nil, nil
+	}
+
+	c.key = dbKey
+	key = dbKey[len(bucketPrefix(0)):]
+	if child.Valid {
+		return key, nil
+	}
+	if value == nil {
+		value = []byte{}
+	}
+	return key, value
+}
+
+// Bucket returns the bucket the cursor was created for.
+//
+// This function is part of the walletdb.Cursor interface implementation.
+func (c *cursor) Bucket() walletdb.Bucket {
+	return c.bucket
+}
+
+// Delete removes the current key/value pair the cursor is at without
+// invalidating the cursor. Returns ErrTxNotWritable if attempted on a read-only
+// transaction, or ErrIncompatibleValue if attempted when the cursor points to a
+// nested bucket.
+//
+// This function is part of the walletdb.Cursor interface implementation.
+func (c *cursor) Delete() error {
+	if err := c.bucket.checkWritable(); err != nil {
+		return err
+	}
+	if c.key == nil {
+		return nil
+	}
+	return c.bucket.Delete(c.key[len(bucketPrefix(0)):])
+}
+
+// First positions the cursor at the first key/value pair and returns the pair.
+//
+// This function is part of the walletdb.Cursor interface implementation.
+func (c *cursor) First() (key, value []byte) {
+	lo, hi := c.bucket.bounds()
+	return c.move("SELECT key, value, bucket FROM kv "+
+		"WHERE key >= ? AND key < ? ORDER BY key LIMIT 1", lo, hi)
+}
+
+// Last positions the cursor at the last key/value pair and returns the pair.
+//
+// This function is part of the walletdb.Cursor interface implementation.
+func (c *cursor) Last() (key, value []byte) {
+	lo, hi := c.bucket.bounds()
+	return c.move("SELECT key, value, bucket FROM kv "+
+		"WHERE key >= ? AND key < ? ORDER BY key DESC LIMIT 1", lo, hi)
+}
+
+// Next moves the cursor one key/value pair forward and returns the new pair.
+//
+// This function is part of the walletdb.Cursor interface implementation.
+func (c *cursor) Next() (key, value []byte) {
+	if c.key == nil {
+		return c.First()
+	}
+	_, hi := c.bucket.bounds()
+	return c.move("SELECT key, value, bucket FROM kv "+
+		"WHERE key > ? AND key < ? ORDER BY key LIMIT 1", c.key, hi)
+}
+
+// Prev moves the cursor one key/value pair backward and returns the new pair.
+//
+// This function is part of the walletdb.Cursor interface implementation.
+func (c *cursor) Prev() (key, value []byte) {
+	if c.key == nil {
+		return c.Last()
+	}
+	lo, _ := c.bucket.bounds()
+	return c.move("SELECT key, value, bucket FROM kv "+
+		"WHERE key >= ? AND key < ? ORDER BY key DESC LIMIT 1", lo, c.key)
+}
+
+// Seek positions the cursor at the passed seek key. If the key does not exist,
+// the cursor is moved to the next key after seek. Returns the new pair.
+//
+// This function is part of the walletdb.Cursor interface implementation.
+func (c *cursor) Seek(seek []byte) (key, value []byte) {
+	_, hi := c.bucket.bounds()
+	return c.move("SELECT key, value, bucket FROM kv "+
+		"WHERE key >= ? AND key < ? ORDER BY key LIMIT 1",
+		c.bucket.dbKey(seek), hi)
+}
+
+// transaction represents a database transaction.  It can either by read-only or
+// read-write and implements the walletdb.Tx interface.  The transaction
+// provides a root bucket against which all read and writes occur.
+//
+// Each transaction is run on its own connection to the database.  Read-write
+// transactions take the write lock of the database when they begin, and are
+// serialized within the process by the write mutex of the db.
+type transaction struct {
+	db         *db
+	conn       *sql.Conn
+	writable   bool
+	managed    bool
+	closed     bool
+	rootBucket *bucket
+
+	// err is the first error of a query run by a method unable to return
+	// it, such as Bucket.Get.  Committing a transaction with an error
+	// rolls it back and returns the error.
+	err error
+}
+
+// Enforce transaction implements the walletdb.Tx interface.
+var _ walletdb.Tx = (*transaction)(nil)
+
+// queryRow runs a query returning at most one row on the connection of the
+// transaction.
+func (tx *transaction) queryRow(query string, args ...interface{}) *sql.Row {
+	return tx.conn.QueryRowContext(context.Background(), query, args...)
+}
+
+// exec runs a statement on the connection of the transaction.
+func (tx *transaction) exec(query string, args ...interface{}) error {
+	_, err := tx.conn.ExecContext(context.Background(), query, args...)
+	return err
+}
+
+// setErr records the first error of a query which could not be returned.
+func (tx *transaction) setErr(err error) {
+	if tx.err == nil {
+		tx.err = err
+	}
+}
+
+// deleteBucketContents removes all key/value pairs and nested buckets of the
+// bucket with an ID.
+func (tx *transaction) deleteBucketContents(id int64) error {
+	lo, hi := bucketPrefix(id), bucketPrefix(id+1)
+	rows, err := tx.conn.QueryContext(context.Background(),
+		"SELECT bucket FROM kv WHERE key >= ? AND key < ? AND "+
+			"bucket IS NOT NULL", lo, hi)
+	if err != nil {
+		return err
+	}
+	var children []int64
+	for rows.Next() {
+		var child int64
+		if err := rows.Scan(&child); err != nil {
+			rows.Close()
+			return err
+		}
+		children = append(children, child)
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, child := range children {
+		if err := tx.deleteBucketContents(child); err != nil {
+			return err
+		}
+	}
+	return tx.exec("DELETE FROM kv WHERE key >= ? AND key < ?", lo, hi)
+}
+
+// close releases the connection and write lock of the transaction.
+func (tx *transaction) close() {
+	tx.closed = true
+	tx.conn.Close()
+	if tx.writable {
+		tx.db.writeMu.Unlock()
+	}
+}
+
+// RootBucket returns the top-most bucket for the namespace the transaction was
+// created from.
+//
+// This function is part of the walletdb.Tx interface implementation.
+func (tx *transaction) RootBucket() walletdb.Bucket {
+	return tx.rootBucket
+}
+
+// Commit commits all changes that have been made through the root bucket and
+// all of its sub-buckets to persistent storage.
+//
+// This function is part of the walletdb.Tx interface implementation.
+func (tx *transaction) Commit() error {
+	if tx.managed {
+		panic("managed transaction commit not allowed")
+	}
+	return tx.commit()
+}
+
+// commit commits the transaction.  A transaction holding an error from an
+// earlier query is rolled back instead, and the error is returned.
+func (tx *transaction) commit() error {
+	if tx.closed {
+		return walletdb.ErrTxClosed
+	}
+	if !tx.writable {
+		return walletdb.ErrTxNotWritable
+	}
+	defer tx.close()
+
+	if tx.err != nil {
+		tx.exec("ROLLBACK")
+		return tx.err
+	}
+	if err := tx.exec("COMMIT"); err != nil {
+		tx.exec("ROLLBACK")
+		return err
+	}
+	return nil
+}
+
+// Rollback undoes all changes that have been made to the root bucket and all of
+// its sub-buckets.
+//
+// This function is part of the walletdb.Tx interface implementation.
+func (tx *transaction) Rollback() error {
+	if tx.managed {
+		panic("managed transaction rollback not allowed")
+	}
+	return tx.rollback()
+}
+
+// rollback rolls back the transaction.
+func (tx *transaction) rollback() error {
+	if tx.closed {
+		return walletdb.ErrTxClosed
+	}
+	defer tx.close()
+
+	return tx.exec("ROLLBACK")
+}
+
+// namespace represents a database namespace that is inteded to support the
+// concept of a single entity that controls the opening, creating, and closing
+// of a database while providing other entities their own namespace to work in.
+// It implements the walletdb.Namespace interface.
+type namespace struct {
+	db  *db
+	key []byte
+}
+
+// Enforce namespace implements the walletdb.Namespace interface.
+var _ walletdb.Namespace = (*namespace)(nil)
+
+// Begin starts a transaction which is either read-only or read-write depending
+// on the specified flag.  Multiple read-only transactions can be started
+// simultaneously while only a single read-write transaction can be started at a
+// time.  The call will block when starting a read-write transaction when one is
+// already open.
+//
+// NOTE: The transaction must be closed by calling Rollback or Commit on it when
+// it is no longer needed.  Failure to do so will hold a connection to the
+// database, and for read-write transactions, the write lock.
+//
+// This function is part of the walletdb.Namespace interface implementation.
+func (ns *namespace) Begin(writable bool) (walletdb.Tx, error) {
+	return ns.begin(writable)
+}
+
+// begin starts a transaction with the root bucket of the namespace.
+func (ns *namespace) begin(writable bool) (*transaction, error) {
+	tx, err := ns.db.begin(writable)
+	if err != nil {
+		return nil, err
+	}
+
+	// Looking up the namespace also starts the read snapshot of the
+	// transaction.
+	_, child, found, err := tx.rootBucket.lookup(ns.key)
+	if err != nil {
+		tx.rollback()
+		return nil, err
+	}
+	if !found || !child.Valid {
+		tx.rollback()
+		return nil, walletdb.ErrBucketNotFound
+	}
+	tx.rootBucket = &bucket{tx: tx, id: child.Int64}
+	return tx, nil
+}
+
+// View invokes the passed function in the context of a managed read-only
+// transaction.  Any errors returned from the user-supplied function are
+// returned from this function.
+//
+// Calling Rollback on the transaction passed to the user-supplied function will
+// result in a panic.
+//
+// This function is part of the walletdb.Namespace interface implementation.
+func (ns *namespace) View(fn func(walletdb.Tx) error) error {
+	tx, err := ns.begin(false)
+	if err != nil {
+		return err
+	}
+	defer tx.rollback()
+
+	tx.managed = true
+	err = fn(tx)
+	tx.managed = false
+	return err
+}
+
+// Update invokes the passed function in the context of a managed read-write
+// transaction.  Any errors returned from the user-supplied function will cause
+// the transaction to be rolled back and are returned from this function.
+// Otherwise, the transaction is commited when the user-supplied function
+// returns a nil error.
+//
+// Calling Rollback on the transaction passed to the user-supplied function will
+// result in a panic.
+//
+// This function is part of the walletdb.Namespace interface implementation.
+func (ns *namespace) Update(fn func(walletdb.Tx) error) error {
+	tx, err := ns.begin(true)
+	if err != nil {
+		return err
+	}
+
+	tx.managed = true
+	err = fn(tx)
+	tx.managed = false
+	if err != nil {
+		tx.rollback()
+		return err
+	}
+	return tx.commit()
+}
+
+// db represents a collection of namespaces which are persisted and implements
+// the walletdb.Db interface.  All database access is performed through
+// transactions which are obtained through the specific Namespace.
+type db struct {
+	sqlDB *sql.DB
+
+	// writeMu is held by the open read-write transaction.
+	writeMu sync.Mutex
+
+	mu     sync.RWMutex // Protects closed.
+	closed bool
+}
+
+// Enforce db implements the walletdb.Db interface.
+var _ walletdb.DB = (*db)(nil)
+
+// begin starts a transaction with the root bucket of the database, which holds
+// the namespaces.
+func (db *db) begin(writable bool) (*transaction, error) {
+	db.mu.RLock()
+	closed := db.closed
+	db.mu.RUnlock()
+	if closed {
+		return nil, walletdb.ErrDbNotOpen
+	}
+
+	if writable {
+		db.writeMu.Lock()
+	}
+	conn, err := db.sqlDB.Conn(context.Background())
+	if err == nil {
+		begin := "BEGIN"
+		if writable {
+			begin = "BEGIN IMMEDIATE"
+		}
+		_, err = conn.ExecContext(context.Background(), begin)
+		if err != nil {
+			conn.Close()
+		}
+	}
+	if err != nil {
+		if writable {
+			db.writeMu.Unlock()
+		}
+		return nil, err
+	}
+
+	tx := &transaction{db: db, conn: conn, writable: writable}
+	tx.rootBucket = &bucket{tx: tx, id: rootBucketID}
+	return tx, nil
+}
+
+// Namespace returns a Namespace interface for the provided key.  See the
+// Namespace interface documentation for more details.  Attempting to access a
+// Namespace on a database that is not open yet or has been closed will result
+// in ErrDbNotOpen.  Namespaces are created in the database on first access.
+//
+// This function is part of the walletdb.Db interface implementation.
+func (db *db) Namespace(key []byte) (walletdb.Namespace, error) {
+	// Check if the namespace needs to be created using a read-only
+	// transaction.  This is done because read-only transactions don't
+	// block like write transactions.
+	tx, err := db.begin(false)
+	if err != nil {
+		return nil, err
+	}
+	exists := tx.rootBucket.Bucket(key) != nil
+	err = tx.err
+	tx.rollback()
+	if err != nil {
+		return nil, err
+	}
+
+	// Create the namespace if needed by using a read-write transaction.
+	if !exists {
+		tx, err := db.begin(true)
+		if err != nil {
+			return nil, err
+		}
+		_, err = tx.rootBucket.CreateBucketIfNotExists(key)
+		if err != nil {
+			tx.rollback()
+			return nil, err
+		}
+		if err := tx.commit(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &namespace{db: db, key: key}, nil
+}
+
+// DeleteNamespace deletes the namespace for the passed key.  ErrBucketNotFound
+// will be returned if the namespace does not exist.
+//
+// This function is part of the walletdb.Db interface implementation.
+func (db *db) DeleteNamespace(key []byte) error {
+	tx, err := db.begin(true)
+	if err != nil {
+		return err
+	}
+	if err := tx.rootBucket.DeleteBucket(key); err != nil {
+		tx.rollback()
+		return err
+	}
+	return tx.commit()
+}
+
+// Copy writes a copy of the database to the provided writer.  The copy is
+// itself a database which may be opened by this driver.  It is written from a
+// single read transaction, so it is consistent.
+//
+// This function is part of the walletdb.Db interface implementation.
+func (db *db) Copy(w io.Writer) error {
+	db.mu.RLock()
+	closed := db.closed
+	db.mu.RUnlock()
+	if closed {
+		return walletdb.ErrDbNotOpen
+	}
+
+	dir, err := ioutil.TempDir("", "walletdb-sqlite")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	copyPath := filepath.Join(dir, "copy.sqlite")
+	_, err = db.sqlDB.Exec("VACUUM INTO ?", copyPath)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(copyPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// Close cleanly shuts down the database and syncs all data.
+//
+// This function is part of the walletdb.Db interface implementation.
+func (db *db) Close() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.closed {
+		return nil
+	}
+	db.closed = true
+	return db.sqlDB.Close()
+}
+
+// filesExists reports whether the named file or directory exists.
+func fileExists(name string) bool {
+	if _, err := os.Stat(name); err != nil {
+		if os.IsNotExist(err) {
+			return false
+		}
+	}
+	return true
+}
+
+// openDB opens the database at the provided path.  walletdb.ErrDbDoesNotExist
+// is returned if the database doesn't exist and the create flag is not set.
+func openDB(dbPath string, create bool) (walletdb.DB, error) {
+	if !create && !fileExists(dbPath) {
+		return nil, walletdb.ErrDbDoesNotExist
+	}
+
+	// The options are applied to every connection opened to the database.
+	// Write-ahead logging allows read transactions to run concurrently
+	// with the write transaction, and full synchronization makes commits
+	// durable across power loss.
+	dsn := fmt.Sprintf("%s?_busy_timeout=%d&_journal_mode=WAL&"+
+		"_synchronous=FULL", dbPath, busyTimeout)
+	sqlDB, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	_, err = sqlDB.Exec(schema)
+	if err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+	return &db{sqlDB: sqlDB}, nil
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package sqlite implements an instance of walletdb that uses an embedded SQLite
+database file for the backing datastore.
+
+Usage
+
+This package is only a driver to the walletdb package and provides the database
+type of "sqlite".  The only parameter the Open and Create functions take is the
+database path as a string:
+
+	db, err := walletdb.Open("sqlite", "path/to/database.sqlite")
+	if err != nil {
+		// Handle error
+	}
+
+	db, err := walletdb.Create("sqlite", "path/to/database.sqlite")
+	if err != nil {
+		// Handle error
+	}
+
+Storage Format
+
+All key/value pairs and nested buckets are stored in a single table:
+
+	CREATE TABLE kv (key BLOB PRIMARY KEY, value BLOB, bucket INTEGER)
+
+Every bucket is identified by an integer ID, and the keys of a bucket are
+stored prefixed with the 8-byte big-endian ID of the bucket, so the pairs of a
+bucket are a contiguous range of the table in key order.  The root of the
+database has ID 0 and holds the namespaces.  A nested bucket is stored as a key
+in its parent with a NULL value and the ID of the nested bucket in the bucket
+column.  The data may be inspected with the sqlite3 tool, for example:
+
+	SELECT hex(key), hex(value), bucket FROM kv;
+
+The database is opened in write-ahead logging mode with full synchronization,
+so committed transactions survive crashes and power loss, and read-only
+transactions are not blocked by the single read-write transaction.
+*/
+package sqlite
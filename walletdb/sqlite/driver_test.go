@@ -0,0 +1,331 @@
+// Copyright (c) 2014-2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package sqlite_test
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+	_ "github.com/btcsuite/btcwallet/walletdb/sqlite"
+)
+
+// dbType is the database type name for this driver.
+const dbType = "sqlite"
+
+// TestCreateOpenFail ensures that errors related to creating and opening a
+// database are handled properly.
+func TestCreateOpenFail(t *testing.T) {
+	// Ensure that attempting to open a database that doesn't exist returns
+	// the expected error.
+	wantErr := walletdb.ErrDbDoesNotExist
+	if _, err := walletdb.Open(dbType, "noexist.sqlite"); err != wantErr {
+		t.Errorf("Open: did not receive expected error - got %v, "+
+			"want %v", err, wantErr)
+		return
+	}
+
+	// Ensure that attempting to open a database with the wrong number of
+	// parameters returns the expected error.
+	wantErr = fmt.Errorf("invalid arguments to %s.Open -- expected "+
+		"database path", dbType)
+	if _, err := walletdb.Open(dbType, 1, 2, 3); err.Error() != wantErr.Error() {
+		t.Errorf("Open: did not receive expected error - got %v, "+
+			"want %v", err, wantErr)
+		return
+	}
+
+	// Ensure that attempting to open a database with an invalid type for
+	// the first parameter returns the expected error.
+	wantErr = fmt.Errorf("first argument to %s.Open is invalid -- "+
+		"expected database path string", dbType)
+	if _, err := walletdb.Open(dbType, 1); err.Error() != wantErr.Error() {
+		t.Errorf("Open: did not receive expected error - got %v, "+
+			"want %v", err, wantErr)
+		return
+	}
+
+	// Ensure that attempting to create a database with the wrong number of
+	// parameters returns the expected error.
+	wantErr = fmt.Errorf("invalid arguments to %s.Create -- expected "+
+		"database path", dbType)
+	if _, err := walletdb.Create(dbType, 1, 2, 3); err.Error() != wantErr.Error() {
+		t.Errorf("Create: did not receive expected error - got %v, "+
+			"want %v", err, wantErr)
+		return
+	}
+
+	// Ensure that attempting to open a database with an invalid type for
+	// the first parameter returns the expected error.
+	wantErr = fmt.Errorf("first argument to %s.Create is invalid -- "+
+		"expected database path string", dbType)
+	if _, err := walletdb.Create(dbType, 1); err.Error() != wantErr.Error() {
+		t.Errorf("Create: did not receive expected error - got %v, "+
+			"want %v", err, wantErr)
+		return
+	}
+
+	// Ensure operations against a closed database return the expected
+	// error.
+	dbPath := "createfail.sqlite"
+	db, err := walletdb.Create(dbType, dbPath)
+	if err != nil {
+		t.Errorf("Create: unexpected error: %v", err)
+		return
+	}
+	defer os.Remove(dbPath)
+	db.Close()
+
+	wantErr = walletdb.ErrDbNotOpen
+	if _, err := db.Namespace([]byte("ns1")); err != wantErr {
+		t.Errorf("Namespace: did not receive expected error - got %v, "+
+			"want %v", err, wantErr)
+		return
+	}
+}
+
+// TestPersistence ensures that values stored are still valid after closing and
+// reopening the database.
+func TestPersistence(t *testing.T) {
+	// Create a new database to run tests against.
+	dbPath := "persistencetest.sqlite"
+	db, err := walletdb.Create(dbType, dbPath)
+	if err != nil {
+		t.Errorf("Failed to create test database (%s) %v", dbType, err)
+		return
+	}
+	defer os.Remove(dbPath)
+	defer db.Close()
+
+	// Create a namespace and put some values into it so they can be tested
+	// for existence on re-open.
+	storeValues := map[string]string{
+		"ns1key1": "foo1",
+		"ns1key2": "foo2",
+		"ns1key3": "foo3",
+	}
+	ns1Key := []byte("ns1")
+	ns1, err := db.Namespace(ns1Key)
+	if err != nil {
+		t.Errorf("Namespace: unexpected error: %v", err)
+		return
+	}
+	err = ns1.Update(func(tx walletdb.Tx) error {
+		rootBucket := tx.RootBucket()
+		if rootBucket == nil {
+			return fmt.Errorf("RootBucket: unexpected nil root bucket")
+		}
+
+		for k, v := range storeValues {
+			if err := rootBucket.Put([]byte(k), []byte(v)); err != nil {
+				return fmt.Errorf("Put: unexpected error: %v", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Errorf("ns1 Update: unexpected error: %v", err)
+		return
+	}
+
+	// Close and reopen the database to ensure the values persist.
+	db.Close()
+	db, err = walletdb.Open(dbType, dbPath)
+	if err != nil {
+		t.Errorf("Failed to open test database (%s) %v", dbType, err)
+		return
+	}
+	defer db.Close()
+
+	// Ensure the values previously stored in the 3rd namespace still exist
+	// and are correct.
+	ns1, err = db.Namespace(ns1Key)
+	if err != nil {
+		t.Errorf("Namespace: unexpected error: %v", err)
+		return
+	}
+	err = ns1.View(func(tx walletdb.Tx) error {
+		rootBucket := tx.RootBucket()
+		if rootBucket == nil {
+			return fmt.Errorf("RootBucket: unexpected nil root bucket")
+		}
+
+		for k, v := range storeValues {
+			gotVal := rootBucket.Get([]byte(k))
+			if !reflect.DeepEqual(gotVal, []byte(v)) {
+				return fmt.Errorf("Get: key '%s' does not "+
+					"match expected value - got %s, want %s",
+					k, gotVal, v)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Errorf("ns1 View: unexpected error: %v", err)
+		return
+	}
+}
+
+// TestInterface performs all interfaces tests for this database driver.
+func TestInterface(t *testing.T) {
+	// Create a new database to run tests against.
+	dbPath := "interfacetest.sqlite"
+	db, err := walletdb.Create(dbType, dbPath)
+	if err != nil {
+		t.Errorf("Failed to create test database (%s) %v", dbType, err)
+		return
+	}
+	defer os.Remove(dbPath)
+	defer db.Close()
+
+	// Run all of the interface tests against the database.
+	testInterface(t, db)
+}
+
+// TestCursor ensures cursors iterate the pairs and nested buckets of a bucket
+// in key order without the pairs of the nested buckets, which are stored in
+// the same table.
+func TestCursor(t *testing.T) {
+	dbPath := "cursortest.sqlite"
+	db, err := walletdb.Create(dbType, dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create test database (%s) %v", dbType, err)
+	}
+	defer os.Remove(dbPath)
+	defer db.Close()
+
+	ns, err := db.Namespace([]byte("ns1"))
+	if err != nil {
+		t.Fatalf("Namespace: unexpected error: %v", err)
+	}
+	err = ns.Update(func(tx walletdb.Tx) error {
+		root := tx.RootBucket()
+		for _, k := range []string{"c", "a", "e"} {
+			if err := root.Put([]byte(k), []byte("v"+k)); err != nil {
+				return err
+			}
+		}
+		nested, err := root.CreateBucket([]byte("b"))
+		if err != nil {
+			return err
+		}
+		if err := nested.Put([]byte("nested"), []byte("x")); err != nil {
+			return err
+		}
+		if err := root.Put([]byte("empty"), nil); err != nil {
+			return err
+		}
+
+		var keys []string
+		c := root.Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			if string(k) == "b" && v != nil {
+				return fmt.Errorf("nested bucket has value %q", v)
+			}
+			keys = append(keys, string(k))
+		}
+		want := []string{"a", "b", "c", "e", "empty"}
+		if !reflect.DeepEqual(keys, want) {
+			return fmt.Errorf("cursor keys %q, want %q", keys, want)
+		}
+
+		if k, v := c.Seek([]byte("d")); string(k) != "e" || string(v) != "ve" {
+			return fmt.Errorf("Seek: got %q %q, want e ve", k, v)
+		}
+		if k, _ := c.Prev(); string(k) != "c" {
+			return fmt.Errorf("Prev: got %q, want c", k)
+		}
+		if err := c.Delete(); err != nil {
+			return fmt.Errorf("Delete: unexpected error: %v", err)
+		}
+		if k, _ := c.Next(); string(k) != "e" {
+			return fmt.Errorf("Next after Delete: got %q, want e", k)
+		}
+		if k, v := c.Last(); string(k) != "empty" || v == nil || len(v) != 0 {
+			return fmt.Errorf("Last: got %q %q, want empty value", k, v)
+		}
+		c.Seek([]byte("b"))
+		if err := c.Delete(); err != walletdb.ErrIncompatibleValue {
+			return fmt.Errorf("Delete bucket: unexpected error: %v", err)
+		}
+		if err := root.Put([]byte("b"), nil); err != walletdb.ErrIncompatibleValue {
+			return fmt.Errorf("Put over bucket: unexpected error: %v", err)
+		}
+		if _, err := root.CreateBucket([]byte("a")); err != walletdb.ErrIncompatibleValue {
+			return fmt.Errorf("CreateBucket over value: unexpected "+
+				"error: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestCopy ensures the copy of a database is a database holding the same
+// data.
+func TestCopy(t *testing.T) {
+	dbPath := "copytest.sqlite"
+	db, err := walletdb.Create(dbType, dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create test database (%s) %v", dbType, err)
+	}
+	defer os.Remove(dbPath)
+	defer db.Close()
+
+	ns, err := db.Namespace([]byte("ns1"))
+	if err != nil {
+		t.Fatalf("Namespace: unexpected error: %v", err)
+	}
+	err = ns.Update(func(tx walletdb.Tx) error {
+		nested, err := tx.RootBucket().CreateBucket([]byte("bucket"))
+		if err != nil {
+			return err
+		}
+		return nested.Put([]byte("key"), []byte("value"))
+	})
+	if err != nil {
+		t.Fatalf("Update: unexpected error: %v", err)
+	}
+
+	copyPath := "copytest-copy.sqlite"
+	f, err := os.Create(copyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(copyPath)
+	err = db.Copy(f)
+	f.Close()
+	if err != nil {
+		t.Fatalf("Copy: unexpected error: %v", err)
+	}
+
+	copyDB, err := walletdb.Open(dbType, copyPath)
+	if err != nil {
+		t.Fatalf("Open: unexpected error: %v", err)
+	}
+	defer copyDB.Close()
+	ns, err = copyDB.Namespace([]byte("ns1"))
+	if err != nil {
+		t.Fatalf("Namespace: unexpected error: %v", err)
+	}
+	err = ns.View(func(tx walletdb.Tx) error {
+		nested := tx.RootBucket().Bucket([]byte("bucket"))
+		if nested == nil {
+			return fmt.Errorf("copied bucket does not exist")
+		}
+		if v := nested.Get([]byte("key")); string(v) != "value" {
+			return fmt.Errorf("copied value %q, want value", v)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/btcsuite/btcwallet/wallet"
 	"github.com/btcsuite/btcwallet/walletdb"
 	_ "github.com/btcsuite/btcwallet/walletdb/bdb"
+	_ "github.com/btcsuite/btcwallet/walletdb/memdb"
 )
 
 // networkDir returns the directory name of a network directory to hold wallet
@@ -39,6 +40,18 @@ func networkDir(dataDir string, chainParams *chaincfg.Params) string {
 	return filepath.Join(dataDir, netname)
 }
 
+// passphraseOptions returns the options used to derive the master keys of
+// wallets from their passphrases.  Nil options select the default scrypt
+// options.
+func passphraseOptions(cfg *config) *waddrmgr.ScryptOptions {
+	if !cfg.Argon2id {
+		return nil
+	}
+	return &waddrmgr.ScryptOptions{
+		Argon2id: &waddrmgr.DefaultArgon2idOptions,
+	}
+}
+
 // convertLegacyKeystore converts all of the addresses in the passed legacy
 // key store to the new waddrmgr.Manager format.  Both the legacy keystore and
 // the new manager must be unlocked.
@@ -101,6 +114,7 @@ func convertLegacyKeystore(legacyKeyStore *keystore.Store, manager *waddrmgr.Man
 func createWallet(cfg *config) error {
 	dbDir := networkDir(cfg.AppDataDir.Value, activeNet.Params)
 	loader := wallet.NewLoader(activeNet.Params, dbDir)
+	loader.SetPassphraseOptions(passphraseOptions(cfg))
 
 	// When there is a legacy keystore, open it now to ensure any errors
 	// don't end up exiting the process after the user has spent time
@@ -201,12 +215,6 @@ func createWallet(cfg *config) error {
 // createSimulationWallet is intended to be called from the rpcclient
 // and used to create a wallet for actors involved in simulations.
 func createSimulationWallet(cfg *config) error {
-	// Simulation wallet password is 'password'.
-	privPass := []byte("password")
-
-	// Public passphrase is the default.
-	pubPass := []byte(wallet.InsecurePubPassphrase)
-
 	netDir := networkDir(cfg.AppDataDir.Value, activeNet.Params)
 
 	// Create the wallet.
@@ -221,7 +229,7 @@ func createSimulationWallet(cfg *config) error {
 	defer db.Close()
 
 	// Create the wallet.
-	err = wallet.Create(db, pubPass, privPass, nil, activeNet.Params)
+	err = initSimulationWallet(db)
 	if err != nil {
 		return err
 	}
@@ -230,6 +238,39 @@ func createSimulationWallet(cfg *config) error {
 	return nil
 }
 
+// initSimulationWallet creates a simulation wallet in an empty database.
+func initSimulationWallet(db walletdb.DB) error {
+	// Simulation wallet password is 'password'.
+	privPass := []byte("password")
+
+	// Public passphrase is the default.
+	pubPass := []byte(wallet.InsecurePubPassphrase)
+
+	return wallet.Create(db, pubPass, privPass, nil, activeNet.Params)
+}
+
+// loadMemDBSimulationWallet creates a simulation wallet in an in-memory
+// database and loads it as the default wallet of the loader.  The wallet is
+// lost when it is unloaded.
+func loadMemDBSimulationWallet(loader *wallet.Loader) (*wallet.Wallet, error) {
+	db, err := walletdb.Create("memdb")
+	if err != nil {
+		return nil, err
+	}
+	err = initSimulationWallet(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	w, err := loader.OpenNamedWalletDB(wallet.DefaultWalletName, db,
+		[]byte(wallet.InsecurePubPassphrase))
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
 // checkCreateDir checks that the path exists and is a directory.
 // If path does not exist, it is created.
 func checkCreateDir(path string) error {
@@ -55,7 +55,15 @@ var byteOrder = binary.BigEndian
 // change.
 const (
 	// LatestVersion is the most recent store version.
-	LatestVersion = 1
+	LatestVersion = 3
+
+	// lockedOutputsVersion is the store version which added the locked
+	// outputs bucket.
+	lockedOutputsVersion = 2
+
+	// labelsVersion is the store version which added the transaction and
+	// output labels buckets.
+	labelsVersion = 3
 )
 
 // This package makes assumptions that the width of a chainhash.Hash is always
@@ -74,6 +82,9 @@ var (
 	bucketUnmined        = []byte("m")
 	bucketUnminedCredits = []byte("mc")
 	bucketUnminedInputs  = []byte("mi")
+	bucketLockedOutputs  = []byte("lo")
+	bucketTxLabels       = []byte("tl")
+	bucketOutputLabels   = []byte("ol")
 )
 
 // Root (namespace) bucket keys
@@ -1183,6 +1194,114 @@ func deleteRawUnminedInput(ns walletdb.Bucket, k []byte) error {
 	return nil
 }
 
+// Outputs locked against being spent by created transactions are saved in the
+// locked outputs bucket.  The key is the canonical outpoint serialization of
+// the locked output.
+//
+// The value is serialized as such:
+//
+//   [0:8]   Expiration time (8 bytes), or zero if the lock never expires
+//   [8:]    Lock ID (variable length)
+
+func valueLockedOutput(id string, expiration time.Time) []byte {
+	v := make([]byte, 8+len(id))
+	if !expiration.IsZero() {
+		byteOrder.PutUint64(v, uint64(expiration.Unix()))
+	}
+	copy(v[8:], id)
+	return v
+}
+
+func readLockedOutput(k, v []byte, l *LockedOutput) error {
+	err := readCanonicalOutPoint(k, &l.OutPoint)
+	if err != nil {
+		return err
+	}
+	if len(v) < 8 {
+		str := fmt.Sprintf("%s: short read (expected at least 8 bytes, "+
+			"read %d)", bucketLockedOutputs, len(v))
+		return storeError(ErrData, str, nil)
+	}
+	l.Expiration = time.Time{}
+	if exp := byteOrder.Uint64(v); exp != 0 {
+		l.Expiration = time.Unix(int64(exp), 0)
+	}
+	l.LockID = string(v[8:])
+	return nil
+}
+
+func putRawLockedOutput(ns walletdb.Bucket, k, v []byte) error {
+	err := ns.Bucket(bucketLockedOutputs).Put(k, v)
+	if err != nil {
+		str := "failed to put locked output"
+		return storeError(ErrDatabase, str, err)
+	}
+	return nil
+}
+
+func existsRawLockedOutput(ns walletdb.Bucket, k []byte) (v []byte) {
+	return ns.Bucket(bucketLockedOutputs).Get(k)
+}
+
+func deleteRawLockedOutput(ns walletdb.Bucket, k []byte) error {
+	err := ns.Bucket(bucketLockedOutputs).Delete(k)
+	if err != nil {
+		str := "failed to delete locked output"
+		return storeError(ErrDatabase, str, err)
+	}
+	return nil
+}
+
+// Labels of transactions are saved in the transaction labels bucket, keyed by
+// the transaction hash, and labels of transaction outputs are saved in the
+// output labels bucket, keyed by the canonical outpoint serialization.  The
+// value of both is the label.  Labels are not removed along with the records
+// of the labeled transactions and outputs.
+
+func putRawTxLabel(ns walletdb.Bucket, k, v []byte) error {
+	err := ns.Bucket(bucketTxLabels).Put(k, v)
+	if err != nil {
+		str := "failed to put transaction label"
+		return storeError(ErrDatabase, str, err)
+	}
+	return nil
+}
+
+func existsRawTxLabel(ns walletdb.Bucket, k []byte) (v []byte) {
+	return ns.Bucket(bucketTxLabels).Get(k)
+}
+
+func deleteRawTxLabel(ns walletdb.Bucket, k []byte) error {
+	err := ns.Bucket(bucketTxLabels).Delete(k)
+	if err != nil {
+		str := "failed to delete transaction label"
+		return storeError(ErrDatabase, str, err)
+	}
+	return nil
+}
+
+func putRawOutputLabel(ns walletdb.Bucket, k, v []byte) error {
+	err := ns.Bucket(bucketOutputLabels).Put(k, v)
+	if err != nil {
+		str := "failed to put output label"
+		return storeError(ErrDatabase, str, err)
+	}
+	return nil
+}
+
+func existsRawOutputLabel(ns walletdb.Bucket, k []byte) (v []byte) {
+	return ns.Bucket(bucketOutputLabels).Get(k)
+}
+
+func deleteRawOutputLabel(ns walletdb.Bucket, k []byte) error {
+	err := ns.Bucket(bucketOutputLabels).Delete(k)
+	if err != nil {
+		str := "failed to delete output label"
+		return storeError(ErrDatabase, str, err)
+	}
+	return nil
+}
+
 // openStore opens an existing transaction store from the passed namespace.  If
 // necessary, an already existing store is upgraded to newer db format.
 func openStore(namespace walletdb.Namespace) error {
@@ -1223,19 +1342,70 @@ func openStore(namespace walletdb.Namespace) error {
 	// Upgrade the tx store as needed, one version at a time, until
 	// LatestVersion is reached.  Versions are not skipped when performing
 	// database upgrades, and each upgrade is done in its own transaction.
-	//
-	// No upgrades yet.
-	//if version < LatestVersion {
-	//	err := scopedUpdate(namespace, func(ns walletdb.Bucket) error {
-	//	})
-	//	if err != nil {
-	//		// Handle err
-	//	}
-	//}
+	if version < lockedOutputsVersion {
+		err := scopedUpdate(namespace, upgradeLockedOutputs)
+		if err != nil {
+			const desc = "failed to upgrade store to add locked outputs"
+			if serr, ok := err.(Error); ok {
+				serr.Desc = desc + ": " + serr.Desc
+				return serr
+			}
+			return storeError(ErrDatabase, desc, err)
+		}
+	}
+	if version < labelsVersion {
+		err := scopedUpdate(namespace, upgradeLabels)
+		if err != nil {
+			const desc = "failed to upgrade store to add labels"
+			if serr, ok := err.(Error); ok {
+				serr.Desc = desc + ": " + serr.Desc
+				return serr
+			}
+			return storeError(ErrDatabase, desc, err)
+		}
+	}
 
 	return nil
 }
 
+// upgradeLabels upgrades a version 2 store by creating the transaction and
+// output labels buckets.
+func upgradeLabels(ns walletdb.Bucket) error {
+	_, err := ns.CreateBucket(bucketTxLabels)
+	if err != nil {
+		str := "failed to create transaction labels bucket"
+		return storeError(ErrDatabase, str, err)
+	}
+	_, err = ns.CreateBucket(bucketOutputLabels)
+	if err != nil {
+		str := "failed to create output labels bucket"
+		return storeError(ErrDatabase, str, err)
+	}
+	return putVersion(ns, labelsVersion)
+}
+
+// upgradeLockedOutputs upgrades a version 1 store by creating the locked
+// outputs bucket.
+func upgradeLockedOutputs(ns walletdb.Bucket) error {
+	_, err := ns.CreateBucket(bucketLockedOutputs)
+	if err != nil {
+		str := "failed to create locked outputs bucket"
+		return storeError(ErrDatabase, str, err)
+	}
+	return putVersion(ns, lockedOutputsVersion)
+}
+
+func putVersion(ns walletdb.Bucket, version uint32) error {
+	v := make([]byte, 4)
+	byteOrder.PutUint32(v, version)
+	err := ns.Put(rootVersion, v)
+	if err != nil {
+		str := "failed to store database version"
+		return storeError(ErrDatabase, str, err)
+	}
+	return nil
+}
+
 // createStore creates the tx store (with the latest db version) in the passed
 // namespace.  If a store already exists, ErrAlreadyExists is returned.
 func createStore(namespace walletdb.Namespace) error {
@@ -1322,6 +1492,24 @@ func createStore(namespace walletdb.Namespace) error {
 			return storeError(ErrDatabase, str, err)
 		}
 
+		_, err = ns.CreateBucket(bucketLockedOutputs)
+		if err != nil {
+			str := "failed to create locked outputs bucket"
+			return storeError(ErrDatabase, str, err)
+		}
+
+		_, err = ns.CreateBucket(bucketTxLabels)
+		if err != nil {
+			str := "failed to create transaction labels bucket"
+			return storeError(ErrDatabase, str, err)
+		}
+
+		_, err = ns.CreateBucket(bucketOutputLabels)
+		if err != nil {
+			str := "failed to create output labels bucket"
+			return storeError(ErrDatabase, str, err)
+		}
+
 		return nil
 	})
 	if err != nil {
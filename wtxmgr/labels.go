@@ -0,0 +1,57 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wtxmgr
+
+import (
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+// LabelTx sets the label of a transaction.  An empty label removes the label.
+// The transaction is not required to be recorded by the store.
+func (s *Store) LabelTx(txHash *chainhash.Hash, label string) error {
+	return scopedUpdate(s.namespace, func(ns walletdb.Bucket) error {
+		if label == "" {
+			return deleteRawTxLabel(ns, txHash[:])
+		}
+		return putRawTxLabel(ns, txHash[:], []byte(label))
+	})
+}
+
+// TxLabel returns the label of a transaction, or an empty string if the
+// transaction is not labeled.
+func (s *Store) TxLabel(txHash *chainhash.Hash) (string, error) {
+	var label string
+	err := scopedView(s.namespace, func(ns walletdb.Bucket) error {
+		label = string(existsRawTxLabel(ns, txHash[:]))
+		return nil
+	})
+	return label, err
+}
+
+// LabelOutput sets the label of a transaction output.  An empty label removes
+// the label.  The output is not required to be recorded by the store.
+func (s *Store) LabelOutput(op *wire.OutPoint, label string) error {
+	return scopedUpdate(s.namespace, func(ns walletdb.Bucket) error {
+		k := canonicalOutPoint(&op.Hash, op.Index)
+		if label == "" {
+			return deleteRawOutputLabel(ns, k)
+		}
+		return putRawOutputLabel(ns, k, []byte(label))
+	})
+}
+
+// OutputLabel returns the label of a transaction output, or an empty string if
+// the output is not labeled.
+func (s *Store) OutputLabel(op *wire.OutPoint) (string, error) {
+	var label string
+	err := scopedView(s.namespace, func(ns walletdb.Bucket) error {
+		k := canonicalOutPoint(&op.Hash, op.Index)
+		label = string(existsRawOutputLabel(ns, k))
+		return nil
+	})
+	return label, err
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wtxmgr_test
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	. "github.com/btcsuite/btcwallet/wtxmgr"
+)
+
+func TestLabels(t *testing.T) {
+	t.Parallel()
+
+	s, teardown, err := testStore()
+	defer teardown()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cb := newCoinBase(50e8)
+	cbRec, err := NewTxRecordFromMsgTx(cb, timeNow())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b100 := makeBlockMeta(100)
+	err = s.InsertTx(cbRec, &b100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.AddCredit(cbRec, &b100, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spendRec, err := NewTxRecordFromMsgTx(spendOutput(&cbRec.Hash, 0, 40e8, 9e8), timeNow())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.InsertTx(spendRec, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = s.LabelTx(&cbRec.Hash, "mined")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.LabelTx(&spendRec.Hash, "invoice 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paymentOp := wire.OutPoint{Hash: spendRec.Hash, Index: 0}
+	err = s.LabelOutput(&paymentOp, "payee")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	label, err := s.TxLabel(&spendRec.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if label != "invoice 1" {
+		t.Fatalf("got transaction label %q, expected %q", label, "invoice 1")
+	}
+	label, err = s.OutputLabel(&paymentOp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if label != "payee" {
+		t.Fatalf("got output label %q, expected %q", label, "payee")
+	}
+
+	// Labels must be included in the details of both mined and unmined
+	// transactions.
+	details, err := s.TxDetails(&spendRec.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if details.Label != "invoice 1" || len(details.OutputLabels) != 1 ||
+		details.OutputLabels[0] != "payee" {
+		t.Fatalf("unexpected labels %q %v", details.Label, details.OutputLabels)
+	}
+	labels := make(map[string]bool)
+	err = s.RangeTransactions(0, -1, func(details []TxDetails) (bool, error) {
+		for i := range details {
+			labels[details[i].Label] = true
+		}
+		return false, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(labels) != 2 || !labels["mined"] || !labels["invoice 1"] {
+		t.Fatalf("unexpected labels %v in range", labels)
+	}
+
+	// An empty label removes the label.
+	err = s.LabelTx(&spendRec.Hash, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.LabelOutput(&paymentOp, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	details, err = s.TxDetails(&spendRec.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if details.Label != "" || details.OutputLabels != nil {
+		t.Fatalf("unexpected labels %q %v after removal", details.Label,
+			details.OutputLabels)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wtxmgr
+
+import (
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+// LockedOutput describes an output which is locked against being spent by
+// transactions created by the wallet.
+type LockedOutput struct {
+	OutPoint wire.OutPoint
+
+	// LockID is a free-form identifier or reason recorded for the lock.
+	LockID string
+
+	// Expiration is the time at which the lock is released.  The lock
+	// never expires if this is the zero time.
+	Expiration time.Time
+}
+
+// expired returns whether the lock has expired at time now.
+func (l *LockedOutput) expired(now time.Time) bool {
+	return !l.Expiration.IsZero() && !now.Before(l.Expiration)
+}
+
+// LockOutput locks an output so it is not used by created transactions until
+// it is unlocked or, if expiration is not the zero time, until the expiration
+// time is reached.  Locking an already locked output replaces the lock ID and
+// expiration of the previous lock.  The output is not required to be a credit
+// of the store.  Expired locks of other outputs are removed from the store.
+func (s *Store) LockOutput(op wire.OutPoint, lockID string, expiration time.Time) error {
+	return scopedUpdate(s.namespace, func(ns walletdb.Bucket) error {
+		err := deleteExpiredLockedOutputs(ns, time.Now())
+		if err != nil {
+			return err
+		}
+		k := canonicalOutPoint(&op.Hash, op.Index)
+		v := valueLockedOutput(lockID, expiration)
+		return putRawLockedOutput(ns, k, v)
+	})
+}
+
+// UnlockOutput removes the lock of an output, if any.  Expired locks of other
+// outputs are removed from the store as well.
+func (s *Store) UnlockOutput(op wire.OutPoint) error {
+	return scopedUpdate(s.namespace, func(ns walletdb.Bucket) error {
+		err := deleteExpiredLockedOutputs(ns, time.Now())
+		if err != nil {
+			return err
+		}
+		k := canonicalOutPoint(&op.Hash, op.Index)
+		return deleteRawLockedOutput(ns, k)
+	})
+}
+
+// ResetLockedOutputs removes the locks of all outputs.
+func (s *Store) ResetLockedOutputs() error {
+	return scopedUpdate(s.namespace, func(ns walletdb.Bucket) error {
+		err := ns.DeleteBucket(bucketLockedOutputs)
+		if err != nil {
+			str := "failed to delete locked outputs bucket"
+			return storeError(ErrDatabase, str, err)
+		}
+		_, err = ns.CreateBucket(bucketLockedOutputs)
+		if err != nil {
+			str := "failed to create locked outputs bucket"
+			return storeError(ErrDatabase, str, err)
+		}
+		return nil
+	})
+}
+
+// LockedOutput returns the lock of an output, or nil if the output is not
+// locked or its lock has expired.
+func (s *Store) LockedOutput(op wire.OutPoint) (*LockedOutput, error) {
+	var lock *LockedOutput
+	err := scopedView(s.namespace, func(ns walletdb.Bucket) error {
+		k := canonicalOutPoint(&op.Hash, op.Index)
+		v := existsRawLockedOutput(ns, k)
+		if v == nil {
+			return nil
+		}
+		l := new(LockedOutput)
+		err := readLockedOutput(k, v, l)
+		if err != nil {
+			return err
+		}
+		if !l.expired(time.Now()) {
+			lock = l
+		}
+		return nil
+	})
+	return lock, err
+}
+
+// LockedOutputs returns the locks of all locked outputs.  Expired locks are not
+// returned.  They remain in the store until the next lock or unlock removes
+// them.
+func (s *Store) LockedOutputs() ([]LockedOutput, error) {
+	var locks []LockedOutput
+	err := scopedView(s.namespace, func(ns walletdb.Bucket) error {
+		var err error
+		locks, err = s.lockedOutputs(ns, time.Now())
+		return err
+	})
+	return locks, err
+}
+
+func (s *Store) lockedOutputs(ns walletdb.Bucket, now time.Time) ([]LockedOutput, error) {
+	var locks []LockedOutput
+	err := ns.Bucket(bucketLockedOutputs).ForEach(func(k, v []byte) error {
+		var l LockedOutput
+		err := readLockedOutput(k, v, &l)
+		if err != nil {
+			return err
+		}
+		if !l.expired(now) {
+			locks = append(locks, l)
+		}
+		return nil
+	})
+	if err != nil {
+		if _, ok := err.(Error); ok {
+			return nil, err
+		}
+		str := "failed iterating locked outputs bucket"
+		return nil, storeError(ErrDatabase, str, err)
+	}
+	return locks, nil
+}
+
+// deleteExpiredLockedOutputs removes all locks which have expired at time now.
+func deleteExpiredLockedOutputs(ns walletdb.Bucket, now time.Time) error {
+	var expired [][]byte
+	err := ns.Bucket(bucketLockedOutputs).ForEach(func(k, v []byte) error {
+		var l LockedOutput
+		err := readLockedOutput(k, v, &l)
+		if err != nil {
+			return err
+		}
+		if l.expired(now) {
+			expired = append(expired, append([]byte(nil), k...))
+		}
+		return nil
+	})
+	if err != nil {
+		if _, ok := err.(Error); ok {
+			return err
+		}
+		str := "failed iterating locked outputs bucket"
+		return storeError(ErrDatabase, str, err)
+	}
+
+	// Keys can not be deleted while iterating over the bucket, so expired
+	// locks are removed afterwards.
+	for _, k := range expired {
+		err := deleteRawLockedOutput(ns, k)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+// Copyright (c) 2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wtxmgr_test
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/walletdb"
+	. "github.com/btcsuite/btcwallet/wtxmgr"
+)
+
+// testStoreNamespace creates a new store like testStore, and also returns the
+// namespace of the store so it may be reopened.
+func testStoreNamespace() (walletdb.Namespace, func(), error) {
+	tmpDir, err := ioutil.TempDir("", "wtxmgr_test")
+	if err != nil {
+		return nil, func() {}, err
+	}
+	teardown := func() {
+		os.RemoveAll(tmpDir)
+	}
+	db, err := walletdb.Create("bdb", filepath.Join(tmpDir, "db"))
+	if err != nil {
+		return nil, teardown, err
+	}
+	teardown = func() {
+		db.Close()
+		os.RemoveAll(tmpDir)
+	}
+	ns, err := db.Namespace([]byte("txstore"))
+	if err != nil {
+		return nil, teardown, err
+	}
+	return ns, teardown, Create(ns)
+}
+
+func TestLockedOutputs(t *testing.T) {
+	t.Parallel()
+
+	ns, teardown, err := testStoreNamespace()
+	defer teardown()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := Open(ns, &chaincfg.TestNet3Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	op1 := wire.OutPoint{Hash: chainhash.Hash{1}, Index: 0}
+	op2 := wire.OutPoint{Hash: chainhash.Hash{2}, Index: 1}
+	op3 := wire.OutPoint{Hash: chainhash.Hash{3}, Index: 2}
+	expiration := time.Unix(time.Now().Unix()+3600, 0)
+
+	err = s.LockOutput(op1, "order-1", time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.LockOutput(op2, "order-2", expiration)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.LockOutput(op3, "expired", time.Now().Add(-time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lock, err := s.LockedOutput(op2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lock == nil || lock.LockID != "order-2" || !lock.Expiration.Equal(expiration) {
+		t.Fatalf("unexpected lock for %v: %+v", op2, lock)
+	}
+	lock, err = s.LockedOutput(op3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lock != nil {
+		t.Fatalf("expired lock for %v was returned", op3)
+	}
+
+	// Locks must persist when the store is reopened.
+	s, err = Open(ns, &chaincfg.TestNet3Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	locks, err := s.LockedOutputs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[wire.OutPoint]LockedOutput{
+		op1: {OutPoint: op1, LockID: "order-1"},
+		op2: {OutPoint: op2, LockID: "order-2", Expiration: expiration},
+	}
+	if len(locks) != len(want) {
+		t.Fatalf("got %d locks, expected %d", len(locks), len(want))
+	}
+	for _, l := range locks {
+		w, ok := want[l.OutPoint]
+		if !ok || l.LockID != w.LockID || !l.Expiration.Equal(w.Expiration) {
+			t.Errorf("unexpected lock %+v", l)
+		}
+	}
+
+	// Reading the locks must not modify the store, so the expired lock is
+	// only removed by the next write.
+	expiredLockExists := func() bool {
+		var exists bool
+		err := ns.View(func(tx walletdb.Tx) error {
+			k := make([]byte, 36)
+			copy(k, op3.Hash[:])
+			binary.BigEndian.PutUint32(k[32:], op3.Index)
+			exists = tx.RootBucket().Bucket([]byte("lo")).Get(k) != nil
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return exists
+	}
+	if !expiredLockExists() {
+		t.Fatalf("expired lock for %v was removed by a read", op3)
+	}
+
+	err = s.UnlockOutput(op1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock, err = s.LockedOutput(op1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lock != nil {
+		t.Fatalf("unlocked output %v is still locked", op1)
+	}
+	if expiredLockExists() {
+		t.Fatalf("expired lock for %v was not removed by a write", op3)
+	}
+
+	err = s.ResetLockedOutputs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	locks, err = s.LockedOutputs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(locks) != 0 {
+		t.Fatalf("got %d locks after reset", len(locks))
+	}
+}
+
+// TestUpgradeStore ensures a version 1 store is upgraded to include the
+// locked outputs and labels buckets.
+func TestUpgradeStore(t *testing.T) {
+	t.Parallel()
+
+	ns, teardown, err := testStoreNamespace()
+	defer teardown()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Revert the store to version 1.
+	err = ns.Update(func(tx walletdb.Tx) error {
+		root := tx.RootBucket()
+		for _, b := range []string{"lo", "tl", "ol"} {
+			err := root.DeleteBucket([]byte(b))
+			if err != nil {
+				return err
+			}
+		}
+		v := make([]byte, 4)
+		binary.BigEndian.PutUint32(v, 1)
+		return root.Put([]byte("vers"), v)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Open(ns, &chaincfg.TestNet3Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ns.View(func(tx walletdb.Tx) error {
+		root := tx.RootBucket()
+		for _, b := range []string{"lo", "tl", "ol"} {
+			if root.Bucket([]byte(b)) == nil {
+				t.Errorf("bucket %q was not created", b)
+			}
+		}
+		v := root.Get([]byte("vers"))
+		if len(v) != 4 || binary.BigEndian.Uint32(v) != LatestVersion {
+			t.Errorf("store was not upgraded to version %d", LatestVersion)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	op := wire.OutPoint{Hash: chainhash.Hash{1}}
+	err = s.LockOutput(op, "", time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock, err := s.LockedOutput(op)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lock == nil {
+		t.Fatalf("output %v was not locked", op)
+	}
+}
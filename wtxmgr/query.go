@@ -38,6 +38,28 @@ type TxDetails struct {
 	Block   BlockMeta
 	Credits []CreditRecord
 	Debits  []DebitRecord
+
+	// Label is the label of the transaction, and OutputLabels maps the
+	// indexes of labeled outputs to their labels.  OutputLabels is nil if
+	// no outputs are labeled.
+	Label        string
+	OutputLabels map[uint32]string
+}
+
+// readLabels sets the transaction and output labels of details.
+func readLabels(ns walletdb.Bucket, details *TxDetails) {
+	details.Label = string(existsRawTxLabel(ns, details.Hash[:]))
+	for i := range details.MsgTx.TxOut {
+		k := canonicalOutPoint(&details.Hash, uint32(i))
+		v := existsRawOutputLabel(ns, k)
+		if v == nil {
+			continue
+		}
+		if details.OutputLabels == nil {
+			details.OutputLabels = make(map[uint32]string)
+		}
+		details.OutputLabels[uint32(i)] = string(v)
+	}
 }
 
 // minedTxDetails fetches the TxDetails for the mined transaction with hash
@@ -89,7 +111,12 @@ func (s *Store) minedTxDetails(ns walletdb.Bucket, txHash *chainhash.Hash, recKe
 
 		details.Debits = append(details.Debits, debIter.elem)
 	}
-	return &details, debIter.err
+	if debIter.err != nil {
+		return nil, debIter.err
+	}
+
+	readLabels(ns, &details)
+	return &details, nil
 }
 
 // unminedTxDetails fetches the TxDetails for the unmined transaction with the
@@ -157,6 +184,7 @@ func (s *Store) unminedTxDetails(ns walletdb.Bucket, txHash *chainhash.Hash, v [
 		})
 	}
 
+	readLabels(ns, &details)
 	return &details, nil
 }
 
@@ -351,6 +379,7 @@ func (s *Store) rangeBlockTransactions(ns walletdb.Bucket, begin, end int32, f f
 				return false, debIter.err
 			}
 
+			readLabels(ns, &detail)
 			details = append(details, detail)
 		}
 
@@ -94,7 +94,7 @@ func NewTxRecord(serializedTx []byte, received time.Time) (*TxRecord, error) {
 		str := "failed to deserialize transaction"
 		return nil, storeError(ErrInput, str, err)
 	}
-	copy(rec.Hash[:], chainhash.DoubleHashB(serializedTx))
+	rec.Hash = rec.MsgTx.TxHash()
 	return rec, nil
 }
 
@@ -112,7 +112,7 @@ func NewTxRecordFromMsgTx(msgTx *wire.MsgTx, received time.Time) (*TxRecord, err
 		Received:     received,
 		SerializedTx: buf.Bytes(),
 	}
-	copy(rec.Hash[:], chainhash.DoubleHashB(rec.SerializedTx))
+	rec.Hash = rec.MsgTx.TxHash()
 	return rec, nil
 }
 
@@ -110,7 +110,7 @@ func TestInsertsCreditsDebitsRollbacks(t *testing.T) {
 	// Create a "signed" (with invalid sigs) tx that spends output 0 of
 	// the double spend.
 	spendingTx := wire.NewMsgTx(wire.TxVersion)
-	spendingTxIn := wire.NewTxIn(wire.NewOutPoint(TstDoubleSpendTx.Hash(), 0), []byte{0, 1, 2, 3, 4})
+	spendingTxIn := wire.NewTxIn(wire.NewOutPoint(TstDoubleSpendTx.Hash(), 0), []byte{0, 1, 2, 3, 4}, nil)
 	spendingTx.AddTxIn(spendingTxIn)
 	spendingTxOut1 := wire.NewTxOut(1e7, []byte{5, 6, 7, 8, 9})
 	spendingTxOut2 := wire.NewTxOut(9e7, []byte{10, 11, 12, 13, 14})
@@ -1255,3 +1255,330 @@ func TestInsertUnserializedTx(t *testing.T) {
 		t.Fatal("Serialized txs for coinbase spender do not match")
 	}
 }
+
+// TestInsertWitnessTx ensures transactions carrying witness data are recorded
+// by their transaction hash rather than their witness hash, and that witness
+// program outputs are tracked as credits.
+func TestInsertWitnessTx(t *testing.T) {
+	t.Parallel()
+
+	s, teardown, err := testStore()
+	defer teardown()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Pay to a P2WPKH output with a spend that includes a witness.
+	p2wpkhScript := append([]byte{0x00, 0x14}, bytes.Repeat([]byte{0x01}, 20)...)
+	tx := spendOutput(&chainhash.Hash{}, 0, 1e8)
+	tx.TxIn[0].Witness = wire.TxWitness{{0x30, 0x44}, {0x02, 0x03}}
+	tx.TxOut[0].PkScript = p2wpkhScript
+	rec, err := NewTxRecordFromMsgTx(tx, timeNow())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Hash != tx.TxHash() {
+		t.Fatalf("Record hash %v does not match transaction hash %v",
+			rec.Hash, tx.TxHash())
+	}
+	rec2, err := NewTxRecord(rec.SerializedTx, rec.Received)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec2.Hash != rec.Hash {
+		t.Fatalf("Deserialized record hash %v does not match %v",
+			rec2.Hash, rec.Hash)
+	}
+
+	err = s.InsertTx(rec, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.AddCredit(rec, nil, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unspents, err := s.UnspentOutputs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unspents) != 1 {
+		t.Fatalf("Expected 1 unspent output, got %d", len(unspents))
+	}
+	c := unspents[0]
+	if c.Hash != rec.Hash || c.Index != 0 {
+		t.Fatalf("Unexpected unspent outpoint %v", c.OutPoint)
+	}
+	if !bytes.Equal(c.PkScript, p2wpkhScript) {
+		t.Fatalf("Unexpected unspent output script %x", c.PkScript)
+	}
+	if c.Amount != 1e8 {
+		t.Fatalf("Unexpected unspent output amount %v", c.Amount)
+	}
+
+	// The witness must survive the round trip through the store.
+	details, err := s.UniqueTxDetails(&rec.Hash, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(details.MsgTx.TxIn[0].Witness) != 2 {
+		t.Fatal("Transaction witness was not stored")
+	}
+}
+
+// TestRemoveUnminedTx ensures removing an unmined transaction also removes the
+// unmined transactions spending it, and marks the outputs it spent unspent.
+func TestRemoveUnminedTx(t *testing.T) {
+	t.Parallel()
+
+	s, teardown, err := testStore()
+	defer teardown()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cb := newCoinBase(50e8)
+	cbRec, err := NewTxRecordFromMsgTx(cb, timeNow())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b100 := makeBlockMeta(100)
+	err = s.InsertTx(cbRec, &b100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.AddCredit(cbRec, &b100, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Spend the coinbase output with an unmined transaction, and spend its
+	// change with another.
+	spendRec, err := NewTxRecordFromMsgTx(spendOutput(&cbRec.Hash, 0, 40e8, 9e8), timeNow())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.InsertTx(spendRec, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.AddCredit(spendRec, nil, 1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	childRec, err := NewTxRecordFromMsgTx(spendOutput(&spendRec.Hash, 1, 8e8), timeNow())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.InsertTx(childRec, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.AddCredit(childRec, nil, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = s.RemoveUnminedTx(spendRec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hashes, err := s.UnminedTxHashes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hashes) != 0 {
+		t.Fatalf("Expected no unmined transactions, found %d", len(hashes))
+	}
+	unspent, err := s.UnspentOutputs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unspent) != 1 || unspent[0].OutPoint != *wire.NewOutPoint(&cbRec.Hash, 0) {
+		t.Fatalf("Expected the coinbase output to be unspent, found %v",
+			unspent)
+	}
+
+	// Removing a transaction which is no longer unmined must fail.
+	err = s.RemoveUnminedTx(spendRec)
+	if e, ok := err.(Error); !ok || e.Code != ErrInput {
+		t.Fatalf("Expected ErrInput removing a missing transaction, got %v", err)
+	}
+}
+
+// TestInsertUnminedTx ensures an unmined transaction is recorded with its
+// credits, and is not recorded when a credit is invalid.
+func TestInsertUnminedTx(t *testing.T) {
+	t.Parallel()
+
+	s, teardown, err := testStore()
+	defer teardown()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cb := newCoinBase(50e8)
+	cbRec, err := NewTxRecordFromMsgTx(cb, timeNow())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b100 := makeBlockMeta(100)
+	err = s.InsertTx(cbRec, &b100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.AddCredit(cbRec, &b100, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec, err := NewTxRecordFromMsgTx(spendOutput(&cbRec.Hash, 0, 40e8, 9e8), timeNow())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A transaction crediting an output it does not have must fail
+	// without modifying the store.
+	err = s.InsertUnminedTx(rec, map[uint32]bool{2: true})
+	if e, ok := err.(Error); !ok || e.Code != ErrInput {
+		t.Fatalf("Expected ErrInput crediting a missing output, got %v", err)
+	}
+	hashes, err := s.UnminedTxHashes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hashes) != 0 {
+		t.Fatalf("Expected no unmined transactions, found %v", hashes)
+	}
+
+	err = s.InsertUnminedTx(rec, map[uint32]bool{1: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashes, err = s.UnminedTxHashes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hashes) != 1 || *hashes[0] != rec.Hash {
+		t.Fatalf("Expected only the transaction to be unmined, found %v",
+			hashes)
+	}
+	unspent, err := s.UnspentOutputs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unspent) != 1 || unspent[0].OutPoint != *wire.NewOutPoint(&rec.Hash, 1) ||
+		unspent[0].Amount != 9e8 {
+		t.Fatalf("Expected the change output to be unspent, found %v",
+			unspent)
+	}
+	details, err := s.TxDetails(&rec.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(details.Credits) != 1 || !details.Credits[0].Change {
+		t.Fatalf("Expected the change credit, found %v", details.Credits)
+	}
+}
+
+// TestReplaceUnminedTx ensures replacing an unmined transaction removes the
+// original, records the replacement with its credits and labels, and leaves the
+// store unchanged when the replacement is invalid.
+func TestReplaceUnminedTx(t *testing.T) {
+	t.Parallel()
+
+	s, teardown, err := testStore()
+	defer teardown()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cb := newCoinBase(50e8)
+	cbRec, err := NewTxRecordFromMsgTx(cb, timeNow())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b100 := makeBlockMeta(100)
+	err = s.InsertTx(cbRec, &b100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.AddCredit(cbRec, &b100, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	origRec, err := NewTxRecordFromMsgTx(spendOutput(&cbRec.Hash, 0, 40e8, 9e8), timeNow())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.InsertTx(origRec, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.AddCredit(origRec, nil, 1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replRec, err := NewTxRecordFromMsgTx(spendOutput(&cbRec.Hash, 0, 40e8, 8e8), timeNow())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A replacement crediting an output it does not have must fail
+	// without modifying the store.
+	err = s.ReplaceUnminedTx(origRec, replRec, map[uint32]bool{2: true}, "", nil)
+	if e, ok := err.(Error); !ok || e.Code != ErrInput {
+		t.Fatalf("Expected ErrInput crediting a missing output, got %v", err)
+	}
+	hashes, err := s.UnminedTxHashes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hashes) != 1 || *hashes[0] != origRec.Hash {
+		t.Fatalf("Expected only the original transaction to be unmined, "+
+			"found %v", hashes)
+	}
+
+	err = s.ReplaceUnminedTx(origRec, replRec, map[uint32]bool{1: true},
+		"invoice 1", map[uint32]string{0: "payee"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashes, err = s.UnminedTxHashes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hashes) != 1 || *hashes[0] != replRec.Hash {
+		t.Fatalf("Expected only the replacement to be unmined, found %v",
+			hashes)
+	}
+	unspent, err := s.UnspentOutputs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unspent) != 1 || unspent[0].OutPoint != *wire.NewOutPoint(&replRec.Hash, 1) ||
+		unspent[0].Amount != 8e8 {
+		t.Fatalf("Expected the replacement change output to be unspent, "+
+			"found %v", unspent)
+	}
+	details, err := s.TxDetails(&replRec.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(details.Credits) != 1 || !details.Credits[0].Change {
+		t.Fatalf("Expected the replacement change credit, found %v",
+			details.Credits)
+	}
+	if details.Label != "invoice 1" || details.OutputLabels[0] != "payee" {
+		t.Fatalf("Replacement labels were not recorded: %q %v",
+			details.Label, details.OutputLabels)
+	}
+
+	// The original is no longer unmined and can not be replaced again.
+	err = s.ReplaceUnminedTx(origRec, replRec, nil, "", nil)
+	if e, ok := err.(Error); !ok || e.Code != ErrInput {
+		t.Fatalf("Expected ErrInput replacing a missing transaction, got %v", err)
+	}
+}
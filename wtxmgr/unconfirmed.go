@@ -5,6 +5,8 @@
 package wtxmgr
 
 import (
+	"fmt"
+
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcwallet/walletdb"
@@ -123,6 +125,129 @@ func (s *Store) removeConflict(ns walletdb.Bucket, rec *TxRecord) error {
 	return deleteRawUnmined(ns, rec.Hash[:])
 }
 
+// RemoveUnminedTx removes an unmined transaction record, and all unmined
+// transactions spending its outputs, from the store.  Previous outputs spent
+// by the removed transactions are marked unspent.  This is used to remove
+// transactions which have been replaced by another spending the same outputs.
+func (s *Store) RemoveUnminedTx(rec *TxRecord) error {
+	return scopedUpdate(s.namespace, func(ns walletdb.Bucket) error {
+		if existsRawUnmined(ns, rec.Hash[:]) == nil {
+			str := fmt.Sprintf("transaction %v is not unmined", rec.Hash)
+			return storeError(ErrInput, str, nil)
+		}
+		log.Infof("Removing unconfirmed transaction %v", rec.Hash)
+		return s.removeConflict(ns, rec)
+	})
+}
+
+// InsertUnminedTx records the unmined transaction rec along with its credits.
+// credits maps the indexes of the outputs of rec which are spendable by the
+// wallet to whether they are change outputs.  The transaction and its credits
+// are recorded in a single database transaction, so the store never records
+// the transaction without its credits.
+func (s *Store) InsertUnminedTx(rec *TxRecord, credits map[uint32]bool) error {
+	for index := range credits {
+		if int(index) >= len(rec.MsgTx.TxOut) {
+			str := "transaction output does not exist"
+			return storeError(ErrInput, str, nil)
+		}
+	}
+
+	var newCredits []uint32
+	err := scopedUpdate(s.namespace, func(ns walletdb.Bucket) error {
+		err := s.insertMemPoolTx(ns, rec)
+		if err != nil {
+			return err
+		}
+		for index, change := range credits {
+			isNew, err := s.addCredit(ns, rec, nil, index, change)
+			if err != nil {
+				return err
+			}
+			if isNew {
+				newCredits = append(newCredits, index)
+			}
+		}
+		return nil
+	})
+	if err == nil && s.NotifyUnspent != nil {
+		for _, index := range newCredits {
+			s.NotifyUnspent(&rec.Hash, index)
+		}
+	}
+	return err
+}
+
+// ReplaceUnminedTx replaces the record of the unmined transaction orig, and all
+// unmined transactions spending its outputs, with the record of the unmined
+// transaction rec.  credits maps the indexes of the outputs of rec which are
+// spendable by the wallet to whether they are change outputs, and label and
+// outputLabels are the labels of rec and its outputs.  Empty labels are not
+// recorded.  All changes are made in a single database transaction, so the
+// store records either the original transaction or the complete replacement.
+func (s *Store) ReplaceUnminedTx(orig, rec *TxRecord, credits map[uint32]bool,
+	label string, outputLabels map[uint32]string) error {
+
+	for index := range credits {
+		if int(index) >= len(rec.MsgTx.TxOut) {
+			str := "transaction output does not exist"
+			return storeError(ErrInput, str, nil)
+		}
+	}
+
+	var newCredits []uint32
+	err := scopedUpdate(s.namespace, func(ns walletdb.Bucket) error {
+		if existsRawUnmined(ns, orig.Hash[:]) == nil {
+			str := fmt.Sprintf("transaction %v is not unmined", orig.Hash)
+			return storeError(ErrInput, str, nil)
+		}
+		log.Infof("Replacing unconfirmed transaction %v with %v",
+			orig.Hash, rec.Hash)
+		err := s.removeConflict(ns, orig)
+		if err != nil {
+			return err
+		}
+		err = s.insertMemPoolTx(ns, rec)
+		if err != nil {
+			return err
+		}
+
+		for index, change := range credits {
+			isNew, err := s.addCredit(ns, rec, nil, index, change)
+			if err != nil {
+				return err
+			}
+			if isNew {
+				newCredits = append(newCredits, index)
+			}
+		}
+
+		if label != "" {
+			err = putRawTxLabel(ns, rec.Hash[:], []byte(label))
+			if err != nil {
+				return err
+			}
+		}
+		for index, label := range outputLabels {
+			if label == "" {
+				continue
+			}
+			k := canonicalOutPoint(&rec.Hash, index)
+			err = putRawOutputLabel(ns, k, []byte(label))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == nil && s.NotifyUnspent != nil {
+		for _, index := range newCredits {
+			s.NotifyUnspent(&rec.Hash, index)
+		}
+	}
+	return err
+}
+
 // UnminedTxs returns the underlying transactions for all unmined transactions
 // which are not known to have been mined in a block.  Transactions are
 // guaranteed to be sorted by their dependency order.